@@ -43,6 +43,8 @@ import (
 const (
 	EccSecp256K1      = "ECC_SECP256K1"
 	EccSecp256K1Value = 1
+	Ed25519           = "ED25519"
+	Ed25519Value      = 2
 
 	DefaultKeyDir = "keydir"
 )
@@ -56,6 +58,9 @@ var (
 
 	// ErrInvalidSignerAddress sign addr not from
 	ErrInvalidSignerAddress = errors.New("transaction sign not use from address")
+
+	// ErrInvalidPayerAddress sign addr not payer
+	ErrInvalidPayerAddress = errors.New("transaction payer sign not use payer address")
 )
 
 // Neblet interface breaks cycle import dependency and hides unused services.
@@ -111,8 +116,11 @@ func NewManager(neblet Neblet) (*Manager, error) {
 		}
 
 		if len(conf.SignatureCiphers) > 0 {
-			if conf.SignatureCiphers[0] == EccSecp256K1 {
+			switch conf.SignatureCiphers[0] {
+			case EccSecp256K1:
 				m.signatureAlg = keystore.Algorithm(EccSecp256K1Value)
+			case Ed25519:
+				m.signatureAlg = keystore.Algorithm(Ed25519Value)
 			}
 		}
 	}
@@ -417,3 +425,35 @@ func (m *Manager) SignTransactionWithPassphrase(addr *core.Address, tx *core.Tra
 	signature.InitSign(key.(keystore.PrivateKey))
 	return tx.Sign(signature)
 }
+
+// SignTransactionAsPayerWithPassphrase co-sign transaction with the payer passphrase
+func (m *Manager) SignTransactionAsPayerWithPassphrase(addr *core.Address, tx *core.Transaction, passphrase []byte) error {
+	// check sign addr is tx's payer addr
+	if tx.Payer() == nil || !tx.Payer().Equals(addr) {
+		return ErrInvalidPayerAddress
+	}
+	res, err := m.ks.ContainsAlias(addr.String())
+	if err != nil || res == false {
+		err = m.loadFile(addr, passphrase)
+		if err != nil {
+			return err
+		}
+	}
+
+	key, err := m.ks.GetKey(addr.String(), passphrase)
+	if err != nil {
+		logging.VLog().WithFields(logrus.Fields{
+			"err": err,
+			"tx":  tx,
+		}).Error("Failed to unlock private key to sign transaction as payer")
+		return ErrAccountIsLocked
+	}
+	defer key.Clear()
+
+	signature, err := crypto.NewSignature(m.signatureAlg)
+	if err != nil {
+		return err
+	}
+	signature.InitSign(key.(keystore.PrivateKey))
+	return tx.SignAsPayer(signature)
+}
@@ -26,6 +26,7 @@ import (
 	"encoding/json"
 
 	"github.com/alexlisong/go-nebulas/core"
+	"github.com/alexlisong/go-nebulas/storage"
 	"github.com/urfave/cli"
 )
 
@@ -67,6 +68,49 @@ Dump the genesis config info.`,
 		Description: `
 Use "./neb dump 10" to dump 10 blocks before tail block.`,
 	}
+
+	migrateCommand = cli.Command{
+		Name:     "migrate",
+		Usage:    "Migrate the storage to the schema of this node version",
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+The migrate command applies the pending storage migrations, they are also applied automatically at startup.`,
+		Subcommands: []cli.Command{
+			{
+				Name:   "run",
+				Usage:  "apply the pending storage migrations",
+				Action: MergeFlags(migrateRun),
+			},
+			{
+				Name:   "dryrun",
+				Usage:  "report what the pending storage migrations would change",
+				Action: MergeFlags(migrateDryRun),
+			},
+		},
+	}
+
+	verifyIndexCommand = cli.Command{
+		Name:     "verifyindex",
+		Usage:    "Verify the indexes of the canonical blocks",
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+The verifyindex command checks the canonical height index, txs and receipts of blocks in [from, to].
+Use "./neb verifyindex check 1 100", 0 means the genesis or tail block.`,
+		Subcommands: []cli.Command{
+			{
+				Name:      "check",
+				Usage:     "report the discrepancies of indexes",
+				ArgsUsage: "<fromHeight> <toHeight>",
+				Action:    MergeFlags(verifyIndexCheck),
+			},
+			{
+				Name:      "repair",
+				Usage:     "report the discrepancies and rebuild the height index",
+				ArgsUsage: "<fromHeight> <toHeight>",
+				Action:    MergeFlags(verifyIndexRepair),
+			},
+		},
+	}
 )
 
 func initGenesis(ctx *cli.Context) error {
@@ -124,3 +168,89 @@ func dumpblock(ctx *cli.Context) error {
 	fmt.Printf("blockchain dump: %s\n", neb.BlockChain().Dump(count))
 	return nil
 }
+
+func migrateRun(ctx *cli.Context) error {
+	return migrate(ctx, false)
+}
+
+func migrateDryRun(ctx *cli.Context) error {
+	return migrate(ctx, true)
+}
+
+func migrate(ctx *cli.Context, dryRun bool) error {
+	neb, err := makeNeb(ctx)
+	if err != nil {
+		return err
+	}
+
+	stor, err := storage.NewDiskStorage(neb.Config().Chain.Datadir)
+	if err != nil {
+		return err
+	}
+	defer stor.Close()
+
+	migrator, err := core.NewStorageMigrator(stor)
+	if err != nil {
+		return err
+	}
+	version, err := migrator.SchemaVersion()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("schema version: %d, latest: %d\n", version, migrator.LatestVersion())
+
+	var reports []*storage.MigrationReport
+	if dryRun {
+		reports, err = migrator.DryRun()
+	} else {
+		reports, err = migrator.Run()
+	}
+	if err != nil {
+		return err
+	}
+	for _, report := range reports {
+		fmt.Printf("migration %d (%s): %d puts, %d dels\n", report.ID, report.Name, report.Puts, report.Dels)
+	}
+	return nil
+}
+
+func verifyIndexCheck(ctx *cli.Context) error {
+	return verifyIndex(ctx, false)
+}
+
+func verifyIndexRepair(ctx *cli.Context) error {
+	return verifyIndex(ctx, true)
+}
+
+func verifyIndex(ctx *cli.Context, repair bool) error {
+	var from, to uint64
+	var err error
+	if ctx.NArg() > 0 {
+		if from, err = strconv.ParseUint(ctx.Args().Get(0), 10, 64); err != nil {
+			return err
+		}
+	}
+	if ctx.NArg() > 1 {
+		if to, err = strconv.ParseUint(ctx.Args().Get(1), 10, 64); err != nil {
+			return err
+		}
+	}
+
+	neb, err := makeNeb(ctx)
+	if err != nil {
+		return err
+	}
+
+	neb.Setup()
+
+	report, err := core.VerifyIndexConsistency(neb.BlockChain(), from, to, repair)
+	if err != nil {
+		return err
+	}
+	for _, d := range report.Discrepancies {
+		fmt.Println(d)
+	}
+	fmt.Printf("verified blocks [%d, %d]: %d blocks, %d txs, %d discrepancies, %d repaired\n",
+		report.FromHeight, report.ToHeight, report.Blocks, report.Txs, len(report.Discrepancies), report.Repaired())
+	return nil
+}
@@ -111,6 +111,12 @@ var (
 		Usage: "chain transaction pool's max gasLimit.",
 	}
 
+	// ChainSyncModeFlag chain sync mode of fresh node
+	ChainSyncModeFlag = cli.StringFlag{
+		Name:  "chain.syncmode",
+		Usage: "chain sync mode of fresh node, full or snapshot.",
+	}
+
 	// ChainFlags chain config list
 	ChainFlags = []cli.Flag{
 		ChainIDFlag,
@@ -122,6 +128,7 @@ var (
 		ChainPassphraseFlag,
 		ChainGasPriceFlag,
 		ChainGasLimitFlag,
+		ChainSyncModeFlag,
 	}
 
 	// RPCListenFlag rpc listen
@@ -285,6 +292,9 @@ func chainConfig(ctx *cli.Context, cfg *nebletpb.ChainConfig) {
 	if ctx.GlobalIsSet(ChainCipherFlag.Name) {
 		cfg.SignatureCiphers = ctx.GlobalStringSlice(ChainCipherFlag.Name)
 	}
+	if ctx.GlobalIsSet(ChainSyncModeFlag.Name) {
+		cfg.SyncMode = ctx.GlobalString(ChainSyncModeFlag.Name)
+	}
 }
 
 func rpcConfig(ctx *cli.Context, cfg *nebletpb.RPCConfig) {
@@ -69,6 +69,8 @@ func main() {
 		licenseCommand,
 		configCommand,
 		blockDumpCommand,
+		migrateCommand,
+		verifyIndexCommand,
 	}
 	sort.Sort(cli.CommandsByName(app.Commands))
 
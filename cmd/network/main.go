@@ -117,8 +117,7 @@ func run(mode, configPath string, packageSize, concurrentMessageCount, totalMess
 	}
 
 	// register dispatcher.
-	netService.Register(net.NewSubscriber(netService, messageCh, false, PingMessage, net.MessageWeightZero))
-	netService.Register(net.NewSubscriber(netService, messageCh, false, PongMessage, net.MessageWeightZero))
+	netService.Register(net.NewSubscriber(netService, []string{PingMessage, PongMessage}, net.WithMessageChan(messageCh)))
 
 	// start server.
 	netService.Start()
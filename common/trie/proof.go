@@ -21,6 +21,10 @@ package trie
 import (
 	"bytes"
 	"errors"
+
+	"github.com/alexlisong/go-nebulas/common/trie/pb"
+	"github.com/alexlisong/go-nebulas/crypto/hash"
+	"github.com/gogo/protobuf/proto"
 )
 
 // MerkleProof is a path from root to the proved node
@@ -119,3 +123,97 @@ func (t *Trie) Verify(rootHash []byte, key []byte, proof MerkleProof) error {
 	}
 	return nil
 }
+
+// ProvePath return the value of key and the encoded nodes on the path from root to it, which can be verified
+// by VerifyPath without the trie. If key is not in trie, value is nil and the path proves its absence.
+func (t *Trie) ProvePath(key []byte) ([]byte, [][]byte, error) {
+	curRoute := keyToRoute(key)
+	curHash := t.rootHash
+	var path [][]byte
+	for len(curHash) > 0 {
+		n, err := t.fetchNode(curHash)
+		if err != nil {
+			return nil, nil, err
+		}
+		path = append(path, n.Bytes)
+		flag, err := n.Type()
+		if err != nil {
+			return nil, nil, err
+		}
+		switch flag {
+		case branch:
+			if len(curRoute) == 0 {
+				return nil, path, nil
+			}
+			curHash = n.Val[curRoute[0]]
+			curRoute = curRoute[1:]
+		case ext:
+			if !bytes.HasPrefix(curRoute, n.Val[1]) {
+				return nil, path, nil
+			}
+			curHash = n.Val[2]
+			curRoute = curRoute[len(n.Val[1]):]
+		case leaf:
+			if !bytes.Equal(curRoute, n.Val[1]) {
+				return nil, path, nil
+			}
+			return n.Val[2], path, nil
+		default:
+			return nil, nil, errors.New("unknown node type")
+		}
+	}
+	return nil, path, nil
+}
+
+// VerifyPath return if the path proves key has value in the trie with rootHash, an empty value proves key is absent.
+func VerifyPath(rootHash []byte, key []byte, value []byte, path [][]byte) bool {
+	if len(rootHash) == 0 {
+		return len(path) == 0 && len(value) == 0
+	}
+
+	curRoute := keyToRoute(key)
+	wantHash := rootHash
+	for i, nodeBytes := range path {
+		last := i == len(path)-1
+		if !bytes.Equal(wantHash, hash.Sha3256(nodeBytes)) {
+			return false
+		}
+		pb := new(triepb.Node)
+		if err := proto.Unmarshal(nodeBytes, pb); err != nil {
+			return false
+		}
+		n := new(node)
+		if err := n.FromProto(pb); err != nil {
+			return false
+		}
+		flag, err := n.Type()
+		if err != nil {
+			return false
+		}
+		switch flag {
+		case branch:
+			if len(curRoute) == 0 {
+				return last && len(value) == 0
+			}
+			wantHash = n.Val[curRoute[0]]
+			curRoute = curRoute[1:]
+			if len(wantHash) == 0 {
+				return last && len(value) == 0
+			}
+		case ext:
+			if !bytes.HasPrefix(curRoute, n.Val[1]) {
+				return last && len(value) == 0
+			}
+			wantHash = n.Val[2]
+			curRoute = curRoute[len(n.Val[1]):]
+		case leaf:
+			if !bytes.Equal(curRoute, n.Val[1]) {
+				return last && len(value) == 0
+			}
+			return last && len(value) > 0 && bytes.Equal(n.Val[2], value)
+		default:
+			return false
+		}
+	}
+	return false
+}
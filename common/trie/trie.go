@@ -624,6 +624,25 @@ func (t *Trie) CopyTo(storage storage.Storage, needChangelog bool) (*Trie, error
 	return &Trie{rootHash: t.rootHash, storage: storage, needChangelog: needChangelog}, nil
 }
 
+// Snapshot is a state of trie which can be reverted to.
+type Snapshot struct {
+	rootHash     []byte
+	changelogLen int
+}
+
+// Snapshot return the current state of trie.
+func (t *Trie) Snapshot() *Snapshot {
+	return &Snapshot{rootHash: t.rootHash, changelogLen: len(t.changelog)}
+}
+
+// RevertToSnapshot revert the trie to snapshot, dropping the changes after it.
+func (t *Trie) RevertToSnapshot(snapshot *Snapshot) {
+	t.rootHash = snapshot.rootHash
+	if snapshot.changelogLen < len(t.changelog) {
+		t.changelog = t.changelog[:snapshot.changelogLen]
+	}
+}
+
 // Replay return roothash not save key to storage
 func (t *Trie) Replay(ft *Trie) ([]byte, error) {
 
@@ -656,6 +675,81 @@ func (t *Trie) Replay(ft *Trie) ([]byte, error) {
 	return rootHash, nil
 }
 
+// WalkNodes visit the nodes of trie in depth-first order, the children are skipped if visit returns false.
+// val is the value of leaf node, nil for others.
+func (t *Trie) WalkNodes(visit func(hash []byte, val []byte) (bool, error)) error {
+	if len(t.rootHash) == 0 {
+		return nil
+	}
+	return t.walkNodes(t.rootHash, visit)
+}
+
+func (t *Trie) walkNodes(hash []byte, visit func(hash []byte, val []byte) (bool, error)) error {
+	n, err := t.fetchNode(hash)
+	if err != nil {
+		return err
+	}
+	flag, err := n.Type()
+	if err != nil {
+		return err
+	}
+
+	var val []byte
+	if flag == leaf {
+		val = n.Val[2]
+	}
+	if goOn, err := visit(hash, val); err != nil || !goOn {
+		return err
+	}
+
+	switch flag {
+	case branch:
+		for _, child := range n.Val {
+			if len(child) == 0 {
+				continue
+			}
+			if err := t.walkNodes(child, visit); err != nil {
+				return err
+			}
+		}
+	case ext:
+		return t.walkNodes(n.Val[2], visit)
+	}
+	return nil
+}
+
+// DecodeNode return the hashes of children and the leaf value of the node encoded in bytes.
+func DecodeNode(bytes []byte) ([][]byte, []byte, error) {
+	pb := new(triepb.Node)
+	if err := proto.Unmarshal(bytes, pb); err != nil {
+		return nil, nil, err
+	}
+	n := new(node)
+	if err := n.FromProto(pb); err != nil {
+		return nil, nil, err
+	}
+	flag, err := n.Type()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch flag {
+	case branch:
+		children := [][]byte{}
+		for _, child := range n.Val {
+			if len(child) > 0 {
+				children = append(children, child)
+			}
+		}
+		return children, nil, nil
+	case ext:
+		return [][]byte{n.Val[2]}, nil, nil
+	case leaf:
+		return nil, n.Val[2], nil
+	}
+	return nil, nil, errors.New("unknown node type")
+}
+
 // prefixLen returns the length of the common prefix between a and b.
 func prefixLen(a, b []byte) int {
 	var i, length = 0, len(a)
@@ -574,6 +574,31 @@ func TestTrie_Replay(t *testing.T) {
 	assert.Equal(t, rootHash, tr.RootHash())
 }
 
+func TestTrie_RevertToSnapshot(t *testing.T) {
+	s, _ := storage.NewMemoryStorage()
+	tr, _ := NewTrie(nil, s, true)
+	_, err := tr.Put([]byte("key1"), []byte("value1"))
+	assert.Nil(t, err)
+	root := tr.RootHash()
+
+	snapshot := tr.Snapshot()
+	_, err = tr.Put([]byte("key1"), []byte("value2"))
+	assert.Nil(t, err)
+	_, err = tr.Put([]byte("key2"), []byte("value2"))
+	assert.Nil(t, err)
+	tr.RevertToSnapshot(snapshot)
+	assert.Equal(t, root, tr.RootHash())
+	val, err := tr.Get([]byte("key1"))
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("value1"), val)
+
+	// the reverted changes are not replayed.
+	parent, _ := NewTrie(nil, s, false)
+	rootHash, err := parent.Replay(tr)
+	assert.Nil(t, err)
+	assert.Equal(t, root, rootHash)
+}
+
 func TestTrie_Iterator(t *testing.T) {
 	storage, _ := storage.NewMemoryStorage()
 	tr, _ := NewTrie(nil, storage, false)
@@ -683,3 +708,130 @@ func TestTrie_Iterator(t *testing.T) {
 	it, err = tr.Iterator(HashDomainsPrefix("b"))
 	assert.NotNil(t, err)
 }
+
+func TestTrie_WalkNodes(t *testing.T) {
+	storage, _ := storage.NewMemoryStorage()
+	tr, _ := NewTrie(nil, storage, false)
+	assert.Nil(t, tr.WalkNodes(func(hash []byte, val []byte) (bool, error) {
+		t.Fatal("visit empty trie")
+		return false, nil
+	}))
+
+	values := map[string]bool{}
+	for i := 0; i < 64; i++ {
+		value := "value" + strconv.Itoa(i)
+		tr.Put(hash.Sha3256([]byte("key"+strconv.Itoa(i))), []byte(value))
+		values[value] = true
+	}
+
+	visited := map[string]bool{}
+	leaves := map[string]bool{}
+	assert.Nil(t, tr.WalkNodes(func(h []byte, val []byte) (bool, error) {
+		_, err := storage.Get(h)
+		assert.Nil(t, err)
+		visited[byteutils.Hex(h)] = true
+		if val != nil {
+			leaves[string(val)] = true
+		}
+		return true, nil
+	}))
+	assert.Equal(t, values, leaves)
+	assert.True(t, visited[byteutils.Hex(tr.RootHash())])
+
+	// skip the children of root.
+	count := 0
+	assert.Nil(t, tr.WalkNodes(func(h []byte, val []byte) (bool, error) {
+		count++
+		return false, nil
+	}))
+	assert.Equal(t, 1, count)
+}
+
+func TestDecodeNode(t *testing.T) {
+	src, _ := storage.NewMemoryStorage()
+	tr, _ := NewTrie(nil, src, false)
+	for i := 0; i < 64; i++ {
+		tr.Put(hash.Sha3256([]byte("key"+strconv.Itoa(i))), []byte("value"+strconv.Itoa(i)))
+	}
+
+	// copy the trie node by node from root.
+	dst, _ := storage.NewMemoryStorage()
+	leaves := 0
+	queue := [][]byte{tr.RootHash()}
+	for len(queue) > 0 {
+		h := queue[0]
+		queue = queue[1:]
+		bytes, err := src.Get(h)
+		assert.Nil(t, err)
+		assert.Equal(t, h, hash.Sha3256(bytes))
+		assert.Nil(t, dst.Put(h, bytes))
+
+		children, val, err := DecodeNode(bytes)
+		assert.Nil(t, err)
+		if val != nil {
+			leaves++
+		}
+		queue = append(queue, children...)
+	}
+	assert.Equal(t, 64, leaves)
+
+	copied, err := NewTrie(tr.RootHash(), dst, false)
+	assert.Nil(t, err)
+	for i := 0; i < 64; i++ {
+		val, err := copied.Get(hash.Sha3256([]byte("key" + strconv.Itoa(i))))
+		assert.Nil(t, err)
+		assert.Equal(t, []byte("value"+strconv.Itoa(i)), val)
+	}
+
+	_, _, err = DecodeNode([]byte("invalid"))
+	assert.NotNil(t, err)
+}
+
+func TestTrie_ProvePath(t *testing.T) {
+	s, _ := storage.NewMemoryStorage()
+	tr, _ := NewTrie(nil, s, false)
+
+	// the empty trie proves any key is absent.
+	value, path, err := tr.ProvePath([]byte("key"))
+	assert.Nil(t, err)
+	assert.Nil(t, value)
+	assert.True(t, VerifyPath(tr.RootHash(), []byte("key"), nil, path))
+	assert.False(t, VerifyPath(tr.RootHash(), []byte("key"), []byte("value"), path))
+
+	keys := [][]byte{}
+	for i := 0; i < 100; i++ {
+		key := hash.Sha3256([]byte{byte(i)})
+		keys = append(keys, key)
+		_, err := tr.Put(key, []byte{byte(i)})
+		assert.Nil(t, err)
+	}
+	root := tr.RootHash()
+
+	for i, key := range keys {
+		value, path, err := tr.ProvePath(key)
+		assert.Nil(t, err)
+		assert.Equal(t, []byte{byte(i)}, value)
+		assert.True(t, VerifyPath(root, key, value, path))
+		assert.False(t, VerifyPath(root, key, []byte{byte(i + 1)}, path))
+		assert.False(t, VerifyPath(root, key, nil, path))
+		assert.False(t, VerifyPath(root, keys[(i+1)%len(keys)], value, path))
+		assert.False(t, VerifyPath(root, key, value, path[:len(path)-1]))
+	}
+
+	// exclusion proofs.
+	for i := 100; i < 200; i++ {
+		key := hash.Sha3256([]byte{byte(i)})
+		value, path, err := tr.ProvePath(key)
+		assert.Nil(t, err)
+		assert.Nil(t, value)
+		assert.True(t, VerifyPath(root, key, nil, path))
+		assert.False(t, VerifyPath(root, key, []byte{byte(i)}, path))
+	}
+
+	// the proof is bound to the root.
+	value, path, err = tr.ProvePath(keys[0])
+	assert.Nil(t, err)
+	_, err = tr.Put(keys[0], []byte("changed"))
+	assert.Nil(t, err)
+	assert.False(t, VerifyPath(tr.RootHash(), keys[0], value, path))
+}
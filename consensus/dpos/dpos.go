@@ -229,7 +229,7 @@ func (dpos *Dpos) UpdateLIB() {
 		}
 		miners[byteutils.Hex(cur.ConsensusRoot().Proposer)] = true
 		if len(miners) >= ConsensusSize {
-			if err := dpos.chain.StoreLIBHashToStorage(cur); err != nil {
+			if err := dpos.chain.SetLIB(cur); err != nil {
 				logging.VLog().WithFields(logrus.Fields{
 					"tail": tail,
 					"lib":  cur,
@@ -243,13 +243,6 @@ func (dpos *Dpos) UpdateLIB() {
 				"miners.limit":     ConsensusSize,
 				"miners.supported": len(miners),
 			}).Info("Succeed to update latest irreversible block.")
-			dpos.chain.SetLIB(cur)
-
-			e := &state.Event{
-				Topic: core.TopicLibBlock,
-				Data:  dpos.chain.LIB().String(),
-			}
-			dpos.chain.EventEmitter().Trigger(e)
 			return
 		}
 
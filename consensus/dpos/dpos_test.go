@@ -254,6 +254,12 @@ func (n mockNetService) ClosePeer(peerID string, reason error) {}
 
 func (n mockNetService) BroadcastNetworkID([]byte) {}
 
+func (n mockNetService) PeerCapabilities(peerID string) (net.Capability, bool) {
+	return net.LocalCapabilities, true
+}
+
+func (n mockNetService) ReportPeer(peerID string, verdict net.Verdict) {}
+
 func mockBlockFromNetwork(block *core.Block) (*core.Block, error) {
 	pbBlock, err := block.ToProto()
 	if err != nil {
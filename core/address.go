@@ -66,6 +66,8 @@ const (
 	AddressBase58Length = 35
 	// PublicKeyDataLength length of public key
 	PublicKeyDataLength = 65
+	// Ed25519PublicKeyDataLength length of ed25519 public key
+	Ed25519PublicKeyDataLength = 32
 )
 
 // Address design of nebulas address
@@ -182,7 +184,7 @@ func newAddress(t AddressType, args ...[]byte) (*Address, error) {
 
 // NewAddressFromPublicKey return new address from publickey bytes
 func NewAddressFromPublicKey(s []byte) (*Address, error) {
-	if len(s) != PublicKeyDataLength {
+	if len(s) != PublicKeyDataLength && len(s) != Ed25519PublicKeyDataLength {
 		return nil, ErrInvalidArgument
 	}
 	return newAddress(AccountAddress, s)
@@ -196,6 +198,14 @@ func NewContractAddressFromData(from ContractTxFrom, nonce ContractTxNonce) (*Ad
 	return newAddress(ContractAddress, from, nonce)
 }
 
+// NewContractAddressWithSalt return new contract address from chainID, tx.from, tx.nonce, salt and source.
+func NewContractAddressWithSalt(chainID uint32, from ContractTxFrom, nonce ContractTxNonce, salt, source []byte) (*Address, error) {
+	if len(from) == 0 || len(nonce) == 0 || len(salt) == 0 {
+		return nil, ErrInvalidArgument
+	}
+	return newAddress(ContractAddress, byteutils.FromUint32(chainID), from, nonce, salt, hash.Sha3256(source))
+}
+
 // AddressParse parse address string.
 func AddressParse(s string) (*Address, error) {
 	if len(s) != AddressBase58Length || s[0] != NebulasFaith {
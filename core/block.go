@@ -20,6 +20,7 @@ package core
 
 import (
 	"fmt"
+	"math"
 	"reflect"
 	"sync"
 	"time"
@@ -54,6 +55,24 @@ var (
 	// rule: 3% per year, 3,000,000. 1 block per 15 seconds
 	// value: 10^8 * 3% / (365*24*3600/15) * 10^18 ≈ 1.42694 * 10^18
 	BlockReward, _ = util.NewUint128FromString("1426940000000000000")
+
+	// BlockTimestampForkHeight from this height, a block's timestamp must be after its parent's
+	// and aligned to the block slots.
+	BlockTimestampForkHeight uint64 = math.MaxUint64
+
+	// BlockGasLimit default max sum of gas used by txs in a block of chain config: 100 * 10 ** 9
+	BlockGasLimit, _ = util.NewUint128FromString("100000000000")
+
+	// BlockGasLimitForkHeight from this height, the gas used by txs in a block is limited by the chain config,
+	// and the limit and gas used are recorded in block header.
+	// The gas used is summed from receipts, so the limit is not activated before ReceiptsForkHeight.
+	BlockGasLimitForkHeight uint64 = math.MaxUint64
+
+	// BlockBloomForkHeight from this height, a bloom of the txs' addresses and event topics is recorded in block header.
+	BlockBloomForkHeight uint64 = math.MaxUint64
+
+	// ReceiptsForkHeight from this height, the receipts of txs are recorded and their root is in block header.
+	ReceiptsForkHeight uint64 = math.MaxUint64
 )
 
 // BlockHeader of a block
@@ -65,8 +84,17 @@ type BlockHeader struct {
 	stateRoot     byteutils.Hash
 	txsRoot       byteutils.Hash
 	eventsRoot    byteutils.Hash
+	receiptsRoot  byteutils.Hash
+	delegateRoot  byteutils.Hash
 	consensusRoot *consensuspb.ConsensusRoot
 
+	// nil before BlockGasLimitForkHeight.
+	gasLimit *util.Uint128
+	gasUsed  *util.Uint128
+
+	// nil before BlockBloomForkHeight.
+	bloom Bloom
+
 	coinbase  *Address
 	timestamp int64
 	chainID   uint32
@@ -78,18 +106,33 @@ type BlockHeader struct {
 
 // ToProto converts domain BlockHeader to proto BlockHeader
 func (b *BlockHeader) ToProto() (proto.Message, error) {
+	var gasLimit, gasUsed []byte
+	if b.gasLimit != nil && b.gasUsed != nil {
+		var err error
+		if gasLimit, err = b.gasLimit.ToFixedSizeByteSlice(); err != nil {
+			return nil, err
+		}
+		if gasUsed, err = b.gasUsed.ToFixedSizeByteSlice(); err != nil {
+			return nil, err
+		}
+	}
 	return &corepb.BlockHeader{
 		Hash:          b.hash,
 		ParentHash:    b.parentHash,
 		StateRoot:     b.stateRoot,
 		TxsRoot:       b.txsRoot,
 		EventsRoot:    b.eventsRoot,
+		ReceiptsRoot:  b.receiptsRoot,
+		DelegateRoot:  b.delegateRoot,
 		ConsensusRoot: b.consensusRoot,
 		Coinbase:      b.coinbase.address,
 		Timestamp:     b.timestamp,
 		ChainId:       b.chainID,
 		Alg:           uint32(b.alg),
 		Sign:          b.sign,
+		GasLimit:      gasLimit,
+		GasUsed:       gasUsed,
+		Bloom:         b.bloom,
 	}, nil
 }
 
@@ -102,6 +145,8 @@ func (b *BlockHeader) FromProto(msg proto.Message) error {
 			b.stateRoot = msg.StateRoot
 			b.txsRoot = msg.TxsRoot
 			b.eventsRoot = msg.EventsRoot
+			b.receiptsRoot = msg.ReceiptsRoot
+			b.delegateRoot = msg.DelegateRoot
 			if msg.ConsensusRoot == nil {
 				return ErrInvalidProtoToBlockHeader
 			}
@@ -121,6 +166,24 @@ func (b *BlockHeader) FromProto(msg proto.Message) error {
 
 			b.alg = alg
 			b.sign = msg.Sign
+
+			b.gasLimit, b.gasUsed = nil, nil
+			if len(msg.GasLimit) > 0 || len(msg.GasUsed) > 0 {
+				if b.gasLimit, err = util.NewUint128FromFixedSizeByteSlice(msg.GasLimit); err != nil {
+					return ErrInvalidProtoToBlockHeader
+				}
+				if b.gasUsed, err = util.NewUint128FromFixedSizeByteSlice(msg.GasUsed); err != nil {
+					return ErrInvalidProtoToBlockHeader
+				}
+			}
+
+			b.bloom = nil
+			if len(msg.Bloom) > 0 {
+				if len(msg.Bloom) != BloomByteLength {
+					return ErrInvalidProtoToBlockHeader
+				}
+				b.bloom = msg.Bloom
+			}
 			return nil
 		}
 		return ErrInvalidProtoToBlockHeader
@@ -138,11 +201,16 @@ type Block struct {
 	height uint64
 
 	worldState state.WorldState
+	// the account state is pruned, the other states are kept.
+	statePruned bool
 
 	txPool       *TransactionPool
 	eventEmitter *EventEmitter
 	nvm          NVM
 	storage      storage.Storage
+
+	// the gas traces of executed txs, only recorded in a replayed block.
+	gasTraces map[byteutils.HexHash]*GasTrace
 }
 
 // ToProto converts domain Block into proto Block
@@ -183,6 +251,23 @@ func (block *Block) ToProto() (proto.Message, error) {
 
 // FromProto converts proto Block to domain Block
 func (block *Block) FromProto(msg proto.Message) error {
+	return block.fromProto(msg, func(tx *Transaction, pbTx *corepb.Transaction) error {
+		return tx.FromProto(pbTx)
+	})
+}
+
+// FromProtoWithChainID converts proto Block to domain Block, rejecting a block
+// or any of its txs of another chain before they are decoded.
+func (block *Block) FromProtoWithChainID(msg proto.Message, chainID uint32) error {
+	if msg, ok := msg.(*corepb.Block); ok && msg != nil && msg.Header != nil && msg.Header.ChainId != chainID {
+		return ErrInvalidChainID
+	}
+	return block.fromProto(msg, func(tx *Transaction, pbTx *corepb.Transaction) error {
+		return tx.FromProtoWithChainID(pbTx, chainID)
+	})
+}
+
+func (block *Block) fromProto(msg proto.Message, txFromProto func(*Transaction, *corepb.Transaction) error) error {
 	if msg, ok := msg.(*corepb.Block); ok {
 		if msg != nil {
 			block.header = new(BlockHeader)
@@ -193,7 +278,7 @@ func (block *Block) FromProto(msg proto.Message) error {
 			for idx, v := range msg.Transactions {
 				if v != nil {
 					tx := new(Transaction)
-					if err := tx.FromProto(v); err != nil {
+					if err := txFromProto(tx, v); err != nil {
 						return err
 					}
 					block.transactions[idx] = tx
@@ -219,7 +304,11 @@ func NewBlock(chainID uint32, coinbase *Address, parent *Block) (*Block, error)
 	if err != nil {
 		return nil, err
 	}
+	return newBlockWithWorldState(chainID, coinbase, parent, worldState)
+}
 
+// newBlockWithWorldState create a block on parent, executing on the given clone of parent's world state.
+func newBlockWithWorldState(chainID uint32, coinbase *Address, parent *Block, worldState state.WorldState) (*Block, error) {
 	block := &Block{
 		header: &BlockHeader{
 			chainID:       chainID,
@@ -335,6 +424,16 @@ func (block *Block) EventsRoot() byteutils.Hash {
 	return block.header.eventsRoot
 }
 
+// ReceiptsRoot return receipts root hash.
+func (block *Block) ReceiptsRoot() byteutils.Hash {
+	return block.header.receiptsRoot
+}
+
+// DelegateRoot return delegate root hash.
+func (block *Block) DelegateRoot() byteutils.Hash {
+	return block.header.delegateRoot
+}
+
 // ConsensusRoot return consensus root
 func (block *Block) ConsensusRoot() *consensuspb.ConsensusRoot {
 	return block.header.consensusRoot
@@ -360,6 +459,15 @@ func (block *Block) LinkParentBlock(chain *BlockChain, parentBlock *Block) error
 	if !block.ParentHash().Equals(parentBlock.Hash()) {
 		return ErrLinkToWrongParentBlock
 	}
+	if parentBlock.statePruned {
+		return ErrStatePruned
+	}
+	if parentBlock.height+1 >= BlockTimestampForkHeight && block.Timestamp() <= parentBlock.Timestamp() {
+		return ErrBlockTimestampBehindParent
+	}
+	if err := block.checkTxDataTypes(parentBlock.height + 1); err != nil {
+		return err
+	}
 
 	var err error
 	if block.worldState, err = parentBlock.WorldState().Clone(); err != nil {
@@ -382,6 +490,20 @@ func (block *Block) LinkParentBlock(chain *BlockChain, parentBlock *Block) error
 	return nil
 }
 
+// checkTxDataTypes check the data type of txs in block at height from TxPayloadRegistryForkHeight,
+// the height is the linked one, not the unhashed height in block proto.
+func (block *Block) checkTxDataTypes(height uint64) error {
+	if height < TxPayloadRegistryForkHeight {
+		return nil
+	}
+	for _, tx := range block.transactions {
+		if err := CheckTxDataType(tx.Type()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Begin a batch task
 func (block *Block) Begin() error {
 	return block.WorldState().Begin()
@@ -433,6 +555,7 @@ func (block *Block) CollectTransactions(deadlineInMs int64) {
 	deadlineTimer := time.NewTimer(time.Duration(elapseInMs) * time.Millisecond)
 
 	pool := block.txPool
+	quota := pool.newPackingQuota(block)
 
 	packed := int64(0)
 	unpacked := int64(0)
@@ -468,6 +591,10 @@ func (block *Block) CollectTransactions(deadlineInMs int64) {
 				<-mergeCh // unlock
 				return
 			}
+			if quota.full() {
+				<-mergeCh // unlock
+				continue
+			}
 			try++
 			tx := pool.PopWithBlacklist(fromBlacklist, toBlacklist)
 			if tx == nil {
@@ -479,6 +606,21 @@ func (block *Block) CollectTransactions(deadlineInMs int64) {
 				"tx.hash": tx.hash,
 			}).Debug("Pop tx.")
 
+			if !quota.reserve(tx) {
+				// the block can't hold the tx, skip its sender to keep the nonce order.
+				fromBlacklist.Store(tx.from.address.Hex(), true)
+				<-mergeCh // unlock
+				if err := pool.Push(tx); err != nil {
+					logging.VLog().WithFields(logrus.Fields{
+						"block": block,
+						"tx":    tx,
+						"err":   err,
+					}).Debug("Failed to giveback the tx.")
+					pool.dropPacking(tx)
+				}
+				continue
+			}
+
 			fetch++
 			fromBlacklist.Store(tx.from.address.Hex(), true)
 			fromBlacklist.Store(tx.to.address.Hex(), true)
@@ -507,6 +649,7 @@ func (block *Block) CollectTransactions(deadlineInMs int64) {
 							"tx":    tx,
 							"err":   err,
 						}).Debug("Failed to giveback the tx.")
+						pool.dropPacking(tx)
 					}
 					return
 				}
@@ -522,6 +665,7 @@ func (block *Block) CollectTransactions(deadlineInMs int64) {
 						"err":   err,
 					}).Debug("Failed to prepare tx.")
 					failed++
+					quota.release(tx)
 
 					if err := pool.Push(tx); err != nil {
 						logging.VLog().WithFields(logrus.Fields{
@@ -529,6 +673,7 @@ func (block *Block) CollectTransactions(deadlineInMs int64) {
 							"tx":    tx,
 							"err":   err,
 						}).Debug("Failed to giveback the tx.")
+						pool.dropPacking(tx)
 					}
 
 					fromBlacklist.Delete(tx.from.address.Hex())
@@ -552,7 +697,12 @@ func (block *Block) CollectTransactions(deadlineInMs int64) {
 
 				// step2. execute tx.
 				executeAt := time.Now().UnixNano()
-				giveback, err := block.ExecuteTransaction(tx, txWorldState)
+				_, err = block.ExecuteTransaction(tx, txWorldState)
+				giveback := TxErrorGiveback(err)
+				var receipt *TransactionReceipt
+				if err == nil {
+					receipt, _ = GetTransactionReceipt(tx.hash, txWorldState)
+				}
 				executedAt := time.Now().UnixNano()
 				execute += executedAt - executeAt
 				if err != nil {
@@ -564,6 +714,10 @@ func (block *Block) CollectTransactions(deadlineInMs int64) {
 					unpacked++
 					failed++
 
+					mergeCh <- true // lock
+					quota.release(tx)
+					<-mergeCh // unlock
+
 					/* 					if err := txWorldState.Close(); err != nil {
 						logging.VLog().WithFields(logrus.Fields{
 							"block": block,
@@ -572,29 +726,26 @@ func (block *Block) CollectTransactions(deadlineInMs int64) {
 						}).Debug("Failed to close tx.")
 					} */
 
-					if giveback {
-						if err := pool.Push(tx); err != nil {
-							logging.VLog().WithFields(logrus.Fields{
-								"block": block,
-								"tx":    tx,
-								"err":   err,
-							}).Debug("Failed to giveback the tx.")
-						}
+					if !giveback {
+						pool.dropPacking(tx)
+					} else if err := pool.Push(tx); err != nil {
+						logging.VLog().WithFields(logrus.Fields{
+							"block": block,
+							"tx":    tx,
+							"err":   err,
+						}).Debug("Failed to giveback the tx.")
+						pool.dropPacking(tx)
 					}
-					if err == ErrLargeTransactionNonce {
-						// as for the transactions from a same account
-						// we will pop them out of transaction pool order by nonce ascend
-						// thus, when we find a transaction with a very large nonce
-						// we won't try to pack other transactions from the same account in the block
-						// the account will be in our from blacklist util the block is sealed
+					// as for the transactions from a same account
+					// we will pop them out of transaction pool order by nonce ascend
+					// thus, when a transaction fails, the following ones of the same account can't be valid
+					// we won't try to pack other transactions from the same account in the block
+					// the account will be in our from blacklist util the block is sealed
+					if !tx.to.Equals(tx.from) {
 						fromBlacklist.Delete(tx.to.address.Hex())
-						toBlacklist.Delete(tx.to.address.Hex())
-					} else {
-						fromBlacklist.Delete(tx.from.address.Hex())
-						fromBlacklist.Delete(tx.to.address.Hex())
-						toBlacklist.Delete(tx.from.address.Hex())
-						toBlacklist.Delete(tx.to.address.Hex())
 					}
+					toBlacklist.Delete(tx.from.address.Hex())
+					toBlacklist.Delete(tx.to.address.Hex())
 					return
 				}
 
@@ -609,6 +760,7 @@ func (block *Block) CollectTransactions(deadlineInMs int64) {
 							"tx":    tx,
 							"err":   err,
 						}).Debug("Failed to giveback the tx.")
+						pool.dropPacking(tx)
 					}
 					return
 				}
@@ -625,6 +777,7 @@ func (block *Block) CollectTransactions(deadlineInMs int64) {
 					}).Debug("CheckAndUpdate invalid tx.")
 					unpacked++
 					conflict++
+					quota.release(tx)
 
 					if err := pool.Push(tx); err != nil {
 						logging.VLog().WithFields(logrus.Fields{
@@ -632,6 +785,7 @@ func (block *Block) CollectTransactions(deadlineInMs int64) {
 							"tx":    tx,
 							"err":   err,
 						}).Debug("Failed to giveback the tx.")
+						pool.dropPacking(tx)
 					}
 
 					fromBlacklist.Delete(tx.from.address.Hex())
@@ -646,6 +800,9 @@ func (block *Block) CollectTransactions(deadlineInMs int64) {
 					"tx": tx,
 				}).Debug("packed tx.")
 				packed++
+				if receipt != nil {
+					quota.settle(tx, receipt.GasUsed())
+				}
 
 				transactions = append(transactions, tx)
 				txid := tx.Hash().String()
@@ -713,6 +870,11 @@ func (block *Block) Sealed() bool {
 	return block.sealed
 }
 
+// StatePruned return if the account state of block is pruned.
+func (block *Block) StatePruned() bool {
+	return block.statePruned
+}
+
 // Seal seal block, calculate stateRoot and block hash.
 func (block *Block) Seal() error {
 	if block.sealed {
@@ -732,7 +894,17 @@ func (block *Block) Seal() error {
 	block.header.stateRoot = block.WorldState().AccountsRoot()
 	block.header.txsRoot = block.WorldState().TxsRoot()
 	block.header.eventsRoot = block.WorldState().EventsRoot()
+	if block.height >= ReceiptsForkHeight {
+		block.header.receiptsRoot = block.WorldState().ReceiptsRoot()
+	}
+	block.header.delegateRoot = block.WorldState().DelegateRoot()
 	block.header.consensusRoot = block.WorldState().ConsensusRoot()
+	if err := block.sealGas(); err != nil {
+		return err
+	}
+	if err := block.sealBloom(); err != nil {
+		return err
+	}
 
 	hash, err := block.calHash()
 	if err != nil {
@@ -764,6 +936,10 @@ func (block *Block) String() string {
 func (block *Block) VerifyExecution() error {
 	startAt := time.Now().Unix()
 
+	if err := block.checkGasHeader(); err != nil {
+		return err
+	}
+
 	if err := block.Begin(); err != nil {
 		return err
 	}
@@ -777,6 +953,16 @@ func (block *Block) VerifyExecution() error {
 
 	executedAt := time.Now().Unix()
 
+	if err := block.verifyGas(); err != nil {
+		block.RollBack()
+		return err
+	}
+
+	if err := block.verifyBloom(); err != nil {
+		block.RollBack()
+		return err
+	}
+
 	if err := block.verifyState(); err != nil {
 		block.RollBack()
 		return err
@@ -885,6 +1071,24 @@ func (block *Block) verifyState() error {
 		return ErrInvalidBlockEventsRoot
 	}
 
+	// verify receipts root.
+	if block.height >= ReceiptsForkHeight && !byteutils.Equal(block.WorldState().ReceiptsRoot(), block.ReceiptsRoot()) {
+		logging.VLog().WithFields(logrus.Fields{
+			"expect": block.ReceiptsRoot(),
+			"actual": block.WorldState().ReceiptsRoot(),
+		}).Debug("Failed to verify receipts.")
+		return ErrInvalidBlockReceiptsRoot
+	}
+
+	// verify delegate root.
+	if !byteutils.Equal(block.WorldState().DelegateRoot(), block.DelegateRoot()) {
+		logging.VLog().WithFields(logrus.Fields{
+			"expect": block.DelegateRoot(),
+			"actual": block.WorldState().DelegateRoot(),
+		}).Debug("Failed to verify delegate.")
+		return ErrInvalidBlockDelegateRoot
+	}
+
 	// verify transaction root.
 	if !reflect.DeepEqual(block.WorldState().ConsensusRoot(), block.ConsensusRoot()) {
 		logging.VLog().WithFields(logrus.Fields{
@@ -904,10 +1108,55 @@ type verifyCtx struct {
 // Execute block and return result.
 func (block *Block) execute() error {
 
+	if err := block.checkDuplicatedTransactions(); err != nil {
+		return err
+	}
+
 	if err := block.rewardCoinbaseForMint(); err != nil {
 		return err
 	}
 
+	if ExecutionWorkers > 1 {
+		if err := newParallelExecutor(block, ExecutionWorkers).run(); err != nil {
+			logging.VLog().WithFields(logrus.Fields{
+				"block":   block,
+				"workers": ExecutionWorkers,
+				"err":     err,
+			}).Debug("Failed to verify txs in block.")
+			return err
+		}
+	} else if err := block.executeByDependency(); err != nil {
+		return err
+	}
+
+	if err := block.rewardCoinbaseForGas(); err != nil {
+		return err
+	}
+	if err := block.WorldState().Flush(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// checkDuplicatedTransactions reject the block containing a tx twice.
+func (block *Block) checkDuplicatedTransactions() error {
+	hashes := make(map[byteutils.HexHash]bool, len(block.transactions))
+	for _, tx := range block.transactions {
+		if hashes[tx.hash.Hex()] {
+			logging.VLog().WithFields(logrus.Fields{
+				"block": block,
+				"tx":    tx,
+			}).Debug("Found duplicated tx in block.")
+			return ErrDuplicatedTransaction
+		}
+		hashes[tx.hash.Hex()] = true
+	}
+	return nil
+}
+
+// executeByDependency execute txs by the block's dependency dag.
+func (block *Block) executeByDependency() error {
 	context := &verifyCtx{
 		mergeCh: make(chan bool, 1),
 		block:   block,
@@ -957,15 +1206,6 @@ func (block *Block) execute() error {
 		}).Debug("Failed to verify txs in block.")
 		return err
 	}
-
-	if err := block.rewardCoinbaseForGas(); err != nil {
-		return err
-	}
-	if err := block.WorldState().Flush(); err != nil {
-		return err
-	}
-
-
 	return nil
 }
 
@@ -980,6 +1220,9 @@ func (block *Block) Dynasty() ([]byteutils.Hash, error) {
 
 // GetAccount return the account with the given address on this block.
 func (block *Block) GetAccount(address byteutils.Hash) (state.Account, error) {
+	if block.statePruned {
+		return nil, ErrStatePruned
+	}
 	worldState, err := block.WorldState().Clone()
 	if err != nil {
 		return nil, err
@@ -1002,24 +1245,30 @@ func (block *Block) FetchExecutionResultEvent(txHash byteutils.Hash) (*state.Eve
 	if err != nil {
 		return nil, err
 	}
-	events, err := worldState.FetchEvents(txHash)
+	return FetchExecutionResultEvent(txHash, worldState)
+}
+
+// FetchEventsWithExecutionResult fetch events by txHash, and the execution result event at last.
+func (block *Block) FetchEventsWithExecutionResult(txHash byteutils.Hash) ([]*state.Event, error) {
+	worldState, err := block.WorldState().Clone()
 	if err != nil {
 		return nil, err
 	}
+	return fetchEventsWithExecutionResult(txHash, worldState)
+}
 
-	if events != nil && len(events) > 0 {
-		idx := len(events) - 1
-		event := events[idx]
-		if event.Topic != TopicTransactionExecutionResult {
-			logging.VLog().WithFields(logrus.Fields{
-				"tx":     txHash,
-				"events": events,
-			}).Debug("Failed to locate the result event")
-			return nil, ErrInvalidTransactionResultEvent
-		}
-		return event, nil
+func fetchEventsWithExecutionResult(txHash byteutils.Hash, ws WorldState) ([]*state.Event, error) {
+	events, err := ws.FetchEvents(txHash)
+	if err != nil {
+		return nil, err
+	}
+	event, err := ws.FetchExecutionResult(txHash)
+	if err == nil {
+		events = append(events, event)
+	} else if !isTrieKeyNotFound(err) {
+		return nil, err
 	}
-	return nil, ErrNotFoundTransactionResultEvent
+	return events, nil
 }
 
 func (block *Block) rewardCoinbaseForMint() error {
@@ -1037,6 +1286,18 @@ func (block *Block) rewardCoinbaseForGas() error {
 
 	gasConsumed := worldState.GetGas()
 	for from, gas := range gasConsumed {
+		// gas fee has been charged from sender in execution after gas refund fork.
+		if block.Height() >= GasRefundForkHeight {
+			coinbaseAcc, err := worldState.GetOrCreateUserAccount(coinbaseAddr)
+			if err != nil {
+				return err
+			}
+			if err := coinbaseAcc.AddBalance(gas); err != nil {
+				return err
+			}
+			continue
+		}
+
 		fromAddr, err := AddressParse(from)
 		if err != nil {
 			return err
@@ -1074,7 +1335,7 @@ func transfer(from, to byteutils.Hash, value *util.Uint128, ws WorldState) (bool
 // system error: giveback == true
 // logic error: giveback == false, expect Bigger Nonce
 func (block *Block) ExecuteTransaction(tx *Transaction, ws WorldState) (bool, error) {
-	if giveback, err := CheckTransaction(tx, ws); err != nil {
+	if giveback, err := CheckTransaction(tx, block, ws); err != nil {
 		logging.VLog().WithFields(logrus.Fields{
 			"tx":  tx,
 			"err": err,
@@ -1120,6 +1381,18 @@ func (block *Block) GetTransaction(hash byteutils.Hash) (*Transaction, error) {
 	return GetTransaction(hash, worldState)
 }
 
+// GetTransactionReceipt from receipts Trie, ErrReceiptsNotAvailable before ReceiptsForkHeight.
+func (block *Block) GetTransactionReceipt(hash byteutils.Hash) (*TransactionReceipt, error) {
+	if block.height < ReceiptsForkHeight {
+		return nil, ErrReceiptsNotAvailable
+	}
+	worldState, err := block.worldState.Clone()
+	if err != nil {
+		return nil, err
+	}
+	return GetTransactionReceipt(hash, worldState)
+}
+
 // CalHash calculate the hash of block.
 func (block *Block) calHash() (byteutils.Hash, error) {
 	hasher := sha3.New256()
@@ -1142,11 +1415,25 @@ func (block *Block) calHash() (byteutils.Hash, error) {
 	hasher.Write(block.StateRoot())
 	hasher.Write(block.TxsRoot())
 	hasher.Write(block.EventsRoot())
+	if block.height >= ReceiptsForkHeight {
+		hasher.Write(block.ReceiptsRoot())
+	}
+	hasher.Write(block.DelegateRoot())
 	hasher.Write(consensusRoot)
 	hasher.Write(dependency)
 	hasher.Write(block.header.coinbase.address)
 	hasher.Write(byteutils.FromInt64(block.header.timestamp))
 	hasher.Write(byteutils.FromUint32(block.header.chainID))
+	if block.height >= BlockGasLimitForkHeight {
+		gas, err := block.hashGas()
+		if err != nil {
+			return nil, err
+		}
+		hasher.Write(gas)
+	}
+	if block.height >= BlockBloomForkHeight {
+		hasher.Write(block.header.bloom)
+	}
 
 	for _, tx := range block.transactions {
 		hasher.Write(tx.Hash())
@@ -1170,7 +1457,7 @@ func LoadBlockFromStorage(hash byteutils.Hash, chain *BlockChain) (*Block, error
 		return nil, ErrNilArgument
 	}
 
-	value, err := chain.storage.Get(hash)
+	value, err := chain.storage.Get(blockStorageKey(hash))
 	if err != nil {
 		return nil, err
 	}
@@ -1186,15 +1473,26 @@ func LoadBlockFromStorage(hash byteutils.Hash, chain *BlockChain) (*Block, error
 	if err != nil {
 		return nil, err
 	}
-	if err := block.WorldState().LoadAccountsRoot(block.StateRoot()); err != nil {
+	if block.statePruned, err = chain.isStatePruned(hash); err != nil {
 		return nil, err
 	}
+	if !block.statePruned {
+		if err := block.WorldState().LoadAccountsRoot(block.StateRoot()); err != nil {
+			return nil, err
+		}
+	}
 	if err := block.WorldState().LoadTxsRoot(block.TxsRoot()); err != nil {
 		return nil, err
 	}
 	if err := block.WorldState().LoadEventsRoot(block.EventsRoot()); err != nil {
 		return nil, err
 	}
+	if err := block.WorldState().LoadReceiptsRoot(block.ReceiptsRoot()); err != nil {
+		return nil, err
+	}
+	if err := block.WorldState().LoadDelegateRoot(block.DelegateRoot()); err != nil {
+		return nil, err
+	}
 	if err := block.WorldState().LoadConsensusRoot(block.ConsensusRoot()); err != nil {
 		return nil, err
 	}
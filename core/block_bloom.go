@@ -0,0 +1,212 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"bytes"
+
+	"github.com/alexlisong/go-nebulas/util/byteutils"
+	"github.com/alexlisong/go-nebulas/util/logging"
+	"github.com/sirupsen/logrus"
+)
+
+// FilteredBlock is a block matched by FilterBlocks with its matched txs.
+type FilteredBlock struct {
+	Block *Block
+	Txs   Transactions
+}
+
+// Bloom return the bloom of the block's tx addresses and event topics, nil before BlockBloomForkHeight.
+func (block *Block) Bloom() Bloom {
+	return block.header.bloom
+}
+
+// calBloom return the bloom of the from, to addresses and event topics of txs executed in world state.
+func (block *Block) calBloom() (Bloom, error) {
+	bloom := NewBloom()
+	for _, tx := range block.transactions {
+		bloom.Add(tx.from.address)
+		bloom.Add(tx.to.address)
+		events, err := block.WorldState().FetchEvents(tx.hash)
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range events {
+			bloom.Add([]byte(event.Topic))
+		}
+	}
+	return bloom, nil
+}
+
+// sealBloom record the bloom in header after txs are executed.
+func (block *Block) sealBloom() error {
+	if block.height < BlockBloomForkHeight {
+		return nil
+	}
+	bloom, err := block.calBloom()
+	if err != nil {
+		return err
+	}
+	block.header.bloom = bloom
+	return nil
+}
+
+// verifyBloom verify the bloom in header with executed txs.
+func (block *Block) verifyBloom() error {
+	if block.height < BlockBloomForkHeight {
+		if block.header.bloom != nil {
+			return ErrInvalidBlockBloom
+		}
+		return nil
+	}
+	bloom, err := block.calBloom()
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(bloom, block.header.bloom) {
+		logging.VLog().WithFields(logrus.Fields{
+			"block": block,
+		}).Debug("Failed to verify block bloom.")
+		return ErrInvalidBlockBloom
+	}
+	return nil
+}
+
+// mayMatch return false if the header's bloom tells its block has no tx of the addresses, or no event of the topics.
+func (b *BlockHeader) mayMatch(addresses []*Address, topics []string) bool {
+	// blocks before BlockBloomForkHeight have no bloom.
+	if b.bloom == nil {
+		return true
+	}
+	if len(addresses) > 0 {
+		found := false
+		for _, addr := range addresses {
+			if b.bloom.Test(addr.address) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(topics) > 0 {
+		found := false
+		for _, topic := range topics {
+			if b.bloom.Test([]byte(topic)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// FilterBlocks return the blocks in [fromHeight, toHeight] on canonical chain, which have txs from or to
+// the addresses and emitting events of the topics, empty addresses or topics match any.
+// Blocks are skipped by the blooms in headers before their txs and events are matched.
+func (bc *BlockChain) FilterBlocks(fromHeight, toHeight uint64, addresses []*Address, topics []string) ([]*FilteredBlock, error) {
+	return bc.filterBlocks(fromHeight, toHeight, addresses, topics, bc.GetBlock)
+}
+
+func (bc *BlockChain) filterBlocks(fromHeight, toHeight uint64, addresses []*Address, topics []string, load func(byteutils.Hash) *Block) ([]*FilteredBlock, error) {
+	if tail := bc.TailBlock().height; toHeight > tail {
+		toHeight = tail
+	}
+	if fromHeight == 0 || fromHeight > toHeight {
+		return nil, ErrInvalidFilterRange
+	}
+
+	addrs := make(map[byteutils.HexHash]bool)
+	for _, addr := range addresses {
+		addrs[addr.address.Hex()] = true
+	}
+	tps := make(map[string]bool)
+	for _, topic := range topics {
+		tps[topic] = true
+	}
+
+	filtered := []*FilteredBlock{}
+	for height := fromHeight; height <= toHeight; height++ {
+		hash, err := bc.storage.Get(heightStorageKey(height))
+		if err != nil {
+			return nil, err
+		}
+		header, err := bc.GetBlockHeader(hash)
+		if err != nil {
+			return nil, err
+		}
+		if !header.mayMatch(addresses, topics) {
+			continue
+		}
+
+		block := load(hash)
+		if block == nil {
+			return nil, ErrCannotFindBlockAtGivenHeight
+		}
+		txs, err := block.filterTransactions(addrs, tps)
+		if err != nil {
+			return nil, err
+		}
+		// false positives of bloom are dropped here.
+		if len(txs) > 0 {
+			filtered = append(filtered, &FilteredBlock{Block: block, Txs: txs})
+		}
+	}
+	return filtered, nil
+}
+
+// filterTransactions return the txs from or to the addresses and emitting events of the topics.
+func (block *Block) filterTransactions(addresses map[byteutils.HexHash]bool, topics map[string]bool) (Transactions, error) {
+	var worldState WorldState
+	if len(topics) > 0 {
+		var err error
+		if worldState, err = block.WorldState().Clone(); err != nil {
+			return nil, err
+		}
+	}
+
+	txs := Transactions{}
+	for _, tx := range block.transactions {
+		if len(addresses) > 0 && !addresses[tx.from.address.Hex()] && !addresses[tx.to.address.Hex()] {
+			continue
+		}
+		if len(topics) > 0 {
+			events, err := worldState.FetchEvents(tx.hash)
+			if err != nil {
+				return nil, err
+			}
+			found := false
+			for _, event := range events {
+				if topics[event.Topic] {
+					found = true
+					break
+				}
+			}
+			if !found {
+				continue
+			}
+		}
+		txs = append(txs, tx)
+	}
+	return txs, nil
+}
@@ -0,0 +1,168 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alexlisong/go-nebulas/consensus/pb"
+	"github.com/alexlisong/go-nebulas/crypto/keystore"
+	"github.com/alexlisong/go-nebulas/util"
+	"github.com/alexlisong/go-nebulas/util/byteutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBloom(t *testing.T) {
+	bloom := NewBloom()
+	assert.Equal(t, BloomByteLength, len(bloom))
+	addr := mockAddress()
+	assert.False(t, bloom.Test(addr.address))
+	bloom.Add(addr.address)
+	assert.True(t, bloom.Test(addr.address))
+	assert.False(t, bloom.Test(mockAddress().address))
+	assert.False(t, Bloom(nil).Test(addr.address))
+}
+
+func TestBlock_VerifyBloom(t *testing.T) {
+	defer func(height uint64) { BlockBloomForkHeight = height }(BlockBloomForkHeight)
+	BlockBloomForkHeight = 2
+
+	bc := testNeb(t).chain
+	block, err := bc.NewBlock(bc.tailBlock.header.coinbase)
+	assert.Nil(t, err)
+	block.CollectTransactions(time.Now().Unix()*1000 + 1000)
+	assert.Nil(t, block.Seal())
+	assert.Equal(t, NewBloom(), block.Bloom())
+
+	received := func() *Block {
+		pbBlock, err := block.ToProto()
+		assert.Nil(t, err)
+		copied := new(Block)
+		assert.Nil(t, copied.FromProto(pbBlock))
+		assert.Nil(t, copied.LinkParentBlock(bc, bc.tailBlock))
+		return copied
+	}
+	copied := received()
+	assert.Equal(t, block.Bloom(), copied.Bloom())
+	assert.Nil(t, copied.VerifyIntegrity(bc.ChainID(), bc.ConsensusHandler()))
+	assert.Nil(t, copied.VerifyExecution())
+
+	copied = received()
+	copied.header.bloom[0] = 1
+	assert.Equal(t, ErrInvalidBlockHash, copied.VerifyIntegrity(bc.ChainID(), bc.ConsensusHandler()))
+	assert.Equal(t, ErrInvalidBlockBloom, copied.VerifyExecution())
+}
+
+func TestBlockChain_FilterBlocks(t *testing.T) {
+	defer func(height uint64) { BlockBloomForkHeight = height }(BlockBloomForkHeight)
+	BlockBloomForkHeight = 2
+
+	bc := testNeb(t).chain
+	coinbase := mockAddress()
+	target := mockAddress()
+	senders, recipients := []*Address{}, []*Address{}
+	signatures := make(map[*Address]keystore.Signature)
+	for i := 0; i < 4; i++ {
+		sender := mockAddress()
+		senders = append(senders, sender)
+		signatures[sender] = mockSignature(t, sender)
+	}
+	for i := 0; i < 16; i++ {
+		recipients = append(recipients, mockAddress())
+	}
+
+	// 1000 blocks with a tx each, only every 250th is sent to target.
+	for i := 1; i <= 1000; i++ {
+		block, err := bc.NewBlock(coinbase)
+		assert.Nil(t, err)
+		block.header.timestamp = bc.TailBlock().Timestamp() + BlockIntervalInSecond
+		from, to := senders[i%len(senders)], recipients[i%len(recipients)]
+		if i%250 == 0 {
+			to = target
+		}
+		tx, err := NewTransaction(bc.ChainID(), from, to, util.NewUint128(), uint64(i), TxPayloadBinaryType, nil, TransactionGasPrice, TransactionMaxGas)
+		assert.Nil(t, err)
+		assert.Nil(t, tx.Sign(signatures[from]))
+		block.transactions = append(block.transactions, tx)
+		assert.Nil(t, block.Seal())
+		block.header.consensusRoot = &consensuspb.ConsensusRoot{
+			Timestamp: block.header.timestamp,
+			Proposer:  coinbase.Bytes(),
+		}
+		block.header.hash, err = block.calHash()
+		assert.Nil(t, err)
+		assert.Nil(t, bc.StoreBlockToStorage(block))
+		assert.Nil(t, bc.storage.Put(heightStorageKey(block.height), block.Hash()))
+		bc.tailBlock = block
+	}
+	fromHeight, toHeight := uint64(2), bc.TailBlock().Height()
+
+	// a bloom matching anything is a false positive.
+	falsePositive := bc.GetBlockOnCanonicalChainByHeight(600)
+	for i := range falsePositive.header.bloom {
+		falsePositive.header.bloom[i] = 0xff
+	}
+	assert.Nil(t, bc.StoreBlockToStorage(falsePositive))
+
+	// the naive scan loads every block.
+	naiveLoads, expected := 0, []byteutils.Hash{}
+	for height := fromHeight; height <= toHeight; height++ {
+		block := bc.GetBlockOnCanonicalChainByHeight(height)
+		naiveLoads++
+		for _, tx := range block.transactions {
+			if tx.to.Equals(target) {
+				expected = append(expected, block.Hash())
+			}
+		}
+	}
+	assert.Equal(t, 4, len(expected))
+
+	loads := 0
+	load := func(hash byteutils.Hash) *Block {
+		loads++
+		return bc.GetBlock(hash)
+	}
+	filtered, err := bc.filterBlocks(fromHeight, toHeight, []*Address{target}, nil, load)
+	assert.Nil(t, err)
+	actual := []byteutils.Hash{}
+	for _, v := range filtered {
+		assert.Equal(t, 1, len(v.Txs))
+		assert.True(t, v.Txs[0].to.Equals(target))
+		actual = append(actual, v.Block.Hash())
+	}
+	assert.Equal(t, expected, actual)
+	assert.True(t, loads > len(expected))
+	assert.True(t, loads*10 < naiveLoads)
+
+	// no block emits the topic.
+	loads = 0
+	filtered, err = bc.filterBlocks(fromHeight, toHeight, nil, []string{TopicBatchTransfer}, load)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(filtered))
+	assert.Equal(t, 1, loads)
+
+	// blocks of a sender.
+	filtered, err = bc.FilterBlocks(fromHeight, 101, senders[:1], nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 25, len(filtered))
+
+	_, err = bc.FilterBlocks(toHeight, fromHeight, nil, nil)
+	assert.Equal(t, ErrInvalidFilterRange, err)
+}
@@ -0,0 +1,272 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"sync"
+
+	"github.com/alexlisong/go-nebulas/core/state"
+	"github.com/alexlisong/go-nebulas/util/byteutils"
+	"github.com/alexlisong/go-nebulas/util/logging"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// ExecutionWorkers num of txs executed concurrently in block verification,
+	// 1 means executing txs serially by the block's dependency dag.
+	ExecutionWorkers = 1
+
+	// delegateConflictKey is touched by all vote txs, which share the candidates in delegate trie.
+	delegateConflictKey = byteutils.HexHash("delegate")
+)
+
+// conflictAddresses return the accounts a tx is known to touch before execution.
+func conflictAddresses(tx *Transaction) []byteutils.HexHash {
+	addrs := []byteutils.HexHash{tx.from.address.Hex(), tx.to.address.Hex()}
+	if tx.payer != nil {
+		addrs = append(addrs, tx.payer.address.Hex())
+	}
+	if tx.Type() == TxPayloadDeployType {
+		if contract, err := tx.GenerateContractAddress(); err == nil {
+			addrs = append(addrs, contract.address.Hex())
+		}
+		// the salted address is used after the fork.
+		if contract, err := tx.GenerateContractAddressWithSalt(); err == nil {
+			addrs = append(addrs, contract.address.Hex())
+		}
+	}
+	if tx.Type() == TxPayloadVoteType {
+		addrs = append(addrs, delegateConflictKey)
+	}
+	if tx.Type() == TxPayloadBatchType {
+		if payload, err := LoadBatchPayload(tx.data.Payload); err == nil {
+			for _, to := range payload.Recipients() {
+				addrs = append(addrs, to.address.Hex())
+			}
+		}
+	}
+	return addrs
+}
+
+// conflictGroups groups txs' indexes by the accounts they touch,
+// txs in a group keep the block order, groups are ordered by their first tx.
+func conflictGroups(txs []*Transaction) [][]int {
+	parent := make([]int, len(txs))
+	for i := range parent {
+		parent[i] = i
+	}
+	find := func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+
+	owners := make(map[byteutils.HexHash]int)
+	for i, tx := range txs {
+		for _, addr := range conflictAddresses(tx) {
+			j, ok := owners[addr]
+			if !ok {
+				owners[addr] = i
+				continue
+			}
+			ri, rj := find(i), find(j)
+			if ri < rj {
+				parent[rj] = ri
+			} else if rj < ri {
+				parent[ri] = rj
+			}
+		}
+	}
+
+	groups := [][]int{}
+	index := make(map[int]int)
+	for i := range txs {
+		root := find(i)
+		g, ok := index[root]
+		if !ok {
+			g = len(groups)
+			index[root] = g
+			groups = append(groups, []int{})
+		}
+		groups[g] = append(groups[g], i)
+	}
+	return groups
+}
+
+// parallelExecutor executes the conflict groups of a block concurrently on prepared tx world states,
+// and merges the results in tx order. If a tx touches accounts of other groups, the merge conflicts
+// and the rest txs are executed serially, so the result is always the same as serial execution.
+type parallelExecutor struct {
+	block *Block
+	txs   []*Transaction
+
+	// mu guards Prepare & CheckAndUpdate on block's world state.
+	mu sync.Mutex
+	// tokens limits the txs executed concurrently.
+	tokens chan bool
+
+	states   []state.TxWorldState
+	executed []chan error
+	merged   []chan bool
+	abortCh  chan bool
+	wg       sync.WaitGroup
+}
+
+func newParallelExecutor(block *Block, workers int) *parallelExecutor {
+	size := len(block.transactions)
+	e := &parallelExecutor{
+		block:    block,
+		txs:      block.transactions,
+		tokens:   make(chan bool, workers),
+		states:   make([]state.TxWorldState, size),
+		executed: make([]chan error, size),
+		merged:   make([]chan bool, size),
+		abortCh:  make(chan bool),
+	}
+	for i := 0; i < size; i++ {
+		e.executed[i] = make(chan error, 1)
+		e.merged[i] = make(chan bool)
+	}
+	return e
+}
+
+func (e *parallelExecutor) run() error {
+	groups := conflictGroups(e.txs)
+	if len(groups) <= 1 {
+		return e.runSerially(0)
+	}
+
+	for _, group := range groups {
+		e.wg.Add(1)
+		go e.executeGroup(group)
+	}
+
+	for i, tx := range e.txs {
+		if err := <-e.executed[i]; err != nil {
+			e.abort(i)
+			return err
+		}
+
+		e.mu.Lock()
+		_, err := e.states[i].CheckAndUpdate()
+		e.mu.Unlock()
+		if err != nil {
+			logging.VLog().WithFields(logrus.Fields{
+				"block":  e.block,
+				"tx":     tx,
+				"index":  i,
+				"groups": len(groups),
+				"err":    err,
+			}).Debug("Found conflict in parallel execution, fallback to serial execution.")
+			e.abort(i)
+			return e.runSerially(i)
+		}
+		close(e.merged[i])
+	}
+	e.wg.Wait()
+	return nil
+}
+
+// executeGroup executes txs in group one by one, each tx is prepared after its previous tx merged.
+func (e *parallelExecutor) executeGroup(group []int) {
+	defer e.wg.Done()
+
+	for k, i := range group {
+		if k > 0 {
+			select {
+			case <-e.merged[group[k-1]]:
+			case <-e.abortCh:
+				return
+			}
+		}
+
+		tx := e.txs[i]
+		e.mu.Lock()
+		txWorldState, err := e.block.WorldState().Prepare(tx.Hash().String())
+		e.mu.Unlock()
+		if err != nil {
+			e.executed[i] <- err
+			return
+		}
+		e.states[i] = txWorldState
+
+		e.tokens <- true
+		_, err = e.block.ExecuteTransaction(tx, txWorldState)
+		<-e.tokens
+
+		e.executed[i] <- err
+		if err != nil {
+			return
+		}
+	}
+}
+
+// abort stops the groups and closes the tx world states not merged from index from.
+func (e *parallelExecutor) abort(from int) {
+	close(e.abortCh)
+	e.wg.Wait()
+
+	for i := from; i < len(e.states); i++ {
+		if e.states[i] == nil {
+			continue
+		}
+		if err := e.states[i].Close(); err != nil {
+			logging.VLog().WithFields(logrus.Fields{
+				"block": e.block,
+				"tx":    e.txs[i],
+				"err":   err,
+			}).Debug("Failed to close tx.")
+		}
+		e.states[i] = nil
+	}
+}
+
+func (e *parallelExecutor) runSerially(from int) error {
+	for i := from; i < len(e.txs); i++ {
+		if err := e.runTx(e.txs[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runTx executes and merges tx, its tx world state is closed on every exit path.
+func (e *parallelExecutor) runTx(tx *Transaction) error {
+	txWorldState, err := e.block.WorldState().Prepare(tx.Hash().String())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := txWorldState.Close(); err != nil {
+			logging.VLog().WithFields(logrus.Fields{
+				"block": e.block,
+				"tx":    tx,
+				"err":   err,
+			}).Debug("Failed to close tx.")
+		}
+	}()
+
+	if _, err := e.block.ExecuteTransaction(tx, txWorldState); err != nil {
+		return err
+	}
+	_, err = txWorldState.CheckAndUpdate()
+	return err
+}
@@ -0,0 +1,150 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/alexlisong/go-nebulas/common/dag"
+	"github.com/alexlisong/go-nebulas/crypto"
+	"github.com/alexlisong/go-nebulas/crypto/keystore"
+	"github.com/alexlisong/go-nebulas/util"
+	"github.com/alexlisong/go-nebulas/util/byteutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConflictGroups(t *testing.T) {
+	a, b, c, d := mockAddress(), mockAddress(), mockAddress(), mockAddress()
+	newTx := func(from, to *Address) *Transaction {
+		tx := mockNormalTransaction(0, 1)
+		tx.from, tx.to = from, to
+		return tx
+	}
+	deploy := mockDeployTransaction(0, 1)
+	deploy.from, deploy.to = d, d
+	call := mockCallTransaction(0, 2, "totalSupply", "")
+	call.from = mockAddress()
+	call.to, _ = deploy.GenerateContractAddress()
+
+	txs := []*Transaction{newTx(a, b), newTx(c, c), deploy, newTx(b, mockAddress()), call, newTx(c, a)}
+	assert.Equal(t, [][]int{{0, 1, 3, 5}, {2, 4}}, conflictGroups(txs))
+
+	txs = []*Transaction{newTx(a, b), newTx(c, d), newTx(mockAddress(), mockAddress())}
+	assert.Equal(t, [][]int{{0}, {1}, {2}}, conflictGroups(txs))
+}
+
+type executionRoots struct {
+	accounts byteutils.Hash
+	txs      byteutils.Hash
+	events   byteutils.Hash
+	receipts byteutils.Hash
+}
+
+func TestBlock_ParallelExecution(t *testing.T) {
+	defer func(workers int) { ExecutionWorkers = workers }(ExecutionWorkers)
+
+	neb := testNeb(t)
+	bc := neb.chain
+
+	accounts := []*Address{}
+	bc.tailBlock.Begin()
+	balance, _ := util.NewUint128FromString("1000000000000000000")
+	for i := 0; i < 8; i++ {
+		addr := mockAddress()
+		acc, err := bc.tailBlock.worldState.GetOrCreateUserAccount(addr.Bytes())
+		assert.Nil(t, err)
+		assert.Nil(t, acc.AddBalance(balance))
+		accounts = append(accounts, addr)
+	}
+	bc.tailBlock.Commit()
+	bc.tailBlock.header.stateRoot = bc.tailBlock.worldState.AccountsRoot()
+	assert.Nil(t, bc.StoreBlockToStorage(bc.tailBlock))
+
+	gasLimit, _ := util.NewUint128FromInt(2000000)
+	execute := func(txs []*Transaction, workers int) *executionRoots {
+		ExecutionWorkers = workers
+		block, err := NewBlock(bc.ChainID(), accounts[0], bc.tailBlock)
+		assert.Nil(t, err)
+		block.transactions = txs
+		// serially by dag.
+		block.dependency = dag.NewDag()
+		for i, tx := range txs {
+			assert.Nil(t, block.dependency.AddNode(tx.Hash().String()))
+			if i > 0 {
+				assert.Nil(t, block.dependency.AddEdge(txs[i-1].Hash().String(), tx.Hash().String()))
+			}
+		}
+		assert.Nil(t, block.execute())
+		roots := &executionRoots{
+			accounts: block.WorldState().AccountsRoot(),
+			txs:      block.WorldState().TxsRoot(),
+			events:   block.WorldState().EventsRoot(),
+			receipts: block.WorldState().ReceiptsRoot(),
+		}
+		block.RollBack()
+		return roots
+	}
+
+	for round := 0; round < 20; round++ {
+		r := rand.New(rand.NewSource(int64(round)))
+		nonces := make(map[string]uint64)
+		contracts := []*Address{}
+		txs := []*Transaction{}
+		for i := 0; i < 10+r.Intn(30); i++ {
+			from := accounts[r.Intn(len(accounts))]
+			nonces[from.String()]++
+			nonce := nonces[from.String()]
+
+			var tx *Transaction
+			switch k := r.Intn(10); {
+			case k == 0:
+				tx = mockDeployTransaction(bc.ChainID(), nonce)
+				tx.to = from
+				tx.from = from
+				contract, _ := tx.GenerateContractAddress()
+				contracts = append(contracts, contract)
+			case k == 1 && len(contracts) > 0:
+				tx = mockCallTransaction(bc.ChainID(), nonce, "totalSupply", "")
+				tx.from = from
+				tx.to = contracts[r.Intn(len(contracts))]
+			default:
+				to := mockAddress()
+				if r.Intn(2) == 0 {
+					to = accounts[r.Intn(len(accounts))]
+				}
+				tx = mockNormalTransaction(bc.ChainID(), nonce)
+				tx.from = from
+				tx.to = to
+				tx.value = util.NewUint128FromUint(uint64(r.Intn(1000)))
+			}
+			tx.gasLimit = gasLimit
+
+			key, _ := keystore.DefaultKS.GetUnlocked(from.String())
+			signature, _ := crypto.NewSignature(keystore.SECP256K1)
+			signature.InitSign(key.(keystore.PrivateKey))
+			assert.Nil(t, tx.Sign(signature))
+			txs = append(txs, tx)
+		}
+
+		serial := execute(txs, 1)
+		parallel := execute(txs, 2+r.Intn(7))
+		assert.Equal(t, serial, parallel, "round %d, groups %d", round, len(conflictGroups(txs)))
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"github.com/alexlisong/go-nebulas/util"
+	"github.com/alexlisong/go-nebulas/util/logging"
+	"github.com/sirupsen/logrus"
+)
+
+// GasLimit return the max gas of the block's txs, nil before BlockGasLimitForkHeight.
+func (block *Block) GasLimit() *util.Uint128 {
+	return block.header.gasLimit
+}
+
+// GasUsed return the gas used by the block's txs, nil before BlockGasLimitForkHeight.
+func (block *Block) GasUsed() *util.Uint128 {
+	return block.header.gasUsed
+}
+
+// gasLimitOfChain return the block gas limit of the block's chain, nil if it's not activated at the block.
+func (block *Block) gasLimitOfChain() *util.Uint128 {
+	if block.height < BlockGasLimitForkHeight || block.height < ReceiptsForkHeight {
+		return nil
+	}
+	return GetChainConfig(block.header.chainID).BlockGasLimit
+}
+
+// sumGasUsed return the sum of gas used by txs from their receipts in world state.
+func (block *Block) sumGasUsed() (*util.Uint128, error) {
+	gasUsed := util.NewUint128()
+	for _, tx := range block.transactions {
+		receipt, err := GetTransactionReceipt(tx.hash, block.WorldState())
+		if err != nil {
+			return nil, err
+		}
+		if gasUsed, err = gasUsed.Add(receipt.GasUsed()); err != nil {
+			return nil, err
+		}
+	}
+	return gasUsed, nil
+}
+
+// hashGas return the bytes of gas limit & gas used hashed into the block.
+func (block *Block) hashGas() ([]byte, error) {
+	if block.header.gasLimit == nil || block.header.gasUsed == nil {
+		return nil, ErrInvalidBlockGasLimit
+	}
+	gasLimit, err := block.header.gasLimit.ToFixedSizeByteSlice()
+	if err != nil {
+		return nil, err
+	}
+	gasUsed, err := block.header.gasUsed.ToFixedSizeByteSlice()
+	if err != nil {
+		return nil, err
+	}
+	return append(gasLimit, gasUsed...), nil
+}
+
+// sealGas record the gas limit & gas used in header after txs are executed.
+func (block *Block) sealGas() error {
+	gasLimit := block.gasLimitOfChain()
+	if gasLimit == nil {
+		return nil
+	}
+	gasUsed, err := block.sumGasUsed()
+	if err != nil {
+		return err
+	}
+	if gasUsed.Cmp(gasLimit) > 0 {
+		return ErrBlockGasLimitExceeded
+	}
+	block.header.gasLimit = gasLimit
+	block.header.gasUsed = gasUsed
+	return nil
+}
+
+// checkGasHeader reject the block with an unexpected gas limit before executing its txs.
+func (block *Block) checkGasHeader() error {
+	gasLimit := block.gasLimitOfChain()
+	if gasLimit == nil {
+		return nil
+	}
+	if block.header.gasLimit == nil || block.header.gasLimit.Cmp(gasLimit) != 0 {
+		logging.VLog().WithFields(logrus.Fields{
+			"block":  block,
+			"expect": gasLimit,
+			"actual": block.header.gasLimit,
+		}).Debug("Failed to check block gas limit.")
+		return ErrInvalidBlockGasLimit
+	}
+	if block.header.gasUsed == nil || block.header.gasUsed.Cmp(gasLimit) > 0 {
+		return ErrBlockGasLimitExceeded
+	}
+	return nil
+}
+
+// verifyGas verify the gas used by executed txs.
+func (block *Block) verifyGas() error {
+	gasLimit := block.gasLimitOfChain()
+	if gasLimit == nil {
+		return nil
+	}
+	gasUsed, err := block.sumGasUsed()
+	if err != nil {
+		return err
+	}
+	if gasUsed.Cmp(gasLimit) > 0 {
+		logging.VLog().WithFields(logrus.Fields{
+			"block":    block,
+			"gasLimit": gasLimit,
+			"gasUsed":  gasUsed,
+		}).Debug("Block gas limit exceeded.")
+		return ErrBlockGasLimitExceeded
+	}
+	if gasUsed.Cmp(block.header.gasUsed) != 0 {
+		logging.VLog().WithFields(logrus.Fields{
+			"block":  block,
+			"expect": block.header.gasUsed,
+			"actual": gasUsed,
+		}).Debug("Failed to verify block gas used.")
+		return ErrInvalidBlockGasUsed
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alexlisong/go-nebulas/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlock_GasLimit(t *testing.T) {
+	defer func(height uint64) { BlockGasLimitForkHeight = height }(BlockGasLimitForkHeight)
+	BlockGasLimitForkHeight = 2
+	defer func(height uint64) { ReceiptsForkHeight = height }(ReceiptsForkHeight)
+	ReceiptsForkHeight = 0
+
+	neb := testNeb(t)
+	bc := neb.chain
+
+	config := GetChainConfig(bc.chainID)
+	defer RegisterChainConfig(config)
+	setGasLimit := func(limit int64) {
+		limitedConfig := *config
+		limitedConfig.BlockGasLimit, _ = util.NewUint128FromInt(limit)
+		RegisterChainConfig(&limitedConfig)
+	}
+
+	from := mockAddress()
+	balance, _ := util.NewUint128FromString("1000000000000000000")
+	bc.tailBlock.Begin()
+	acc, err := bc.tailBlock.worldState.GetOrCreateUserAccount(from.address)
+	assert.Nil(t, err)
+	assert.Nil(t, acc.AddBalance(balance))
+	bc.tailBlock.Commit()
+	bc.tailBlock.header.stateRoot = bc.tailBlock.worldState.AccountsRoot()
+	assert.Nil(t, bc.StoreBlockToStorage(bc.tailBlock))
+
+	// each tx uses 20000 gas.
+	gasLimit, _ := util.NewUint128FromInt(30000)
+	for nonce := uint64(1); nonce <= 3; nonce++ {
+		tx, _ := NewTransaction(bc.ChainID(), from, mockAddress(), util.NewUint128(), nonce, TxPayloadBinaryType, nil, TransactionGasPrice, gasLimit)
+		assert.Nil(t, tx.Sign(mockSignature(t, from)))
+		assert.Nil(t, bc.txPool.Push(tx))
+	}
+
+	mint := func() *Block {
+		block, err := bc.NewBlock(bc.tailBlock.header.coinbase)
+		assert.Nil(t, err)
+		block.CollectTransactions(time.Now().Unix()*1000 + 1000)
+		assert.Nil(t, block.Seal())
+		for _, tx := range block.transactions {
+			assert.Nil(t, bc.txPool.Push(tx))
+		}
+		return block
+	}
+	received := func(block *Block) *Block {
+		pbBlock, err := block.ToProto()
+		assert.Nil(t, err)
+		copied := new(Block)
+		assert.Nil(t, copied.FromProto(pbBlock))
+		assert.Nil(t, copied.LinkParentBlock(bc, bc.tailBlock))
+		return copied
+	}
+
+	// packing stops before the next tx's gasLimit exceeds the rest of block gas limit.
+	setGasLimit(50000)
+	block := mint()
+	assert.Equal(t, 2, len(block.transactions))
+	assert.Equal(t, "50000", block.GasLimit().String())
+	assert.Equal(t, "40000", block.GasUsed().String())
+
+	copied := received(block)
+	assert.Equal(t, block.GasLimit(), copied.GasLimit())
+	assert.Equal(t, block.GasUsed(), copied.GasUsed())
+	assert.Nil(t, copied.VerifyIntegrity(bc.ChainID(), bc.ConsensusHandler()))
+	assert.Nil(t, copied.VerifyExecution())
+
+	// gas limit & gas used are hashed.
+	copied = received(block)
+	copied.header.gasUsed = util.NewUint128FromUint(20000)
+	assert.Equal(t, ErrInvalidBlockHash, copied.VerifyIntegrity(bc.ChainID(), bc.ConsensusHandler()))
+	assert.Equal(t, ErrInvalidBlockGasUsed, copied.VerifyExecution())
+
+	// a block minted with a higher limit is rejected.
+	setGasLimit(100000)
+	block = mint()
+	assert.Equal(t, 3, len(block.transactions))
+	assert.Equal(t, "60000", block.GasUsed().String())
+
+	setGasLimit(50000)
+	assert.Equal(t, ErrInvalidBlockGasLimit, received(block).VerifyExecution())
+
+	copied = received(block)
+	copied.header.gasLimit = util.NewUint128FromUint(50000)
+	assert.Equal(t, ErrBlockGasLimitExceeded, copied.VerifyExecution())
+
+	// the executed total is checked, not only the header.
+	copied.header.gasUsed = util.NewUint128FromUint(40000)
+	assert.Equal(t, ErrBlockGasLimitExceeded, copied.VerifyExecution())
+}
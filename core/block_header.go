@@ -0,0 +1,129 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"github.com/alexlisong/go-nebulas/core/pb"
+	"github.com/alexlisong/go-nebulas/util"
+	"github.com/alexlisong/go-nebulas/util/byteutils"
+	"github.com/alexlisong/go-nebulas/util/logging"
+	"github.com/gogo/protobuf/proto"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// BlockIntervalInSecond the interval of block timestamps checked in header verification.
+	BlockIntervalInSecond = 15
+)
+
+// Hash return the hash of header.
+func (b *BlockHeader) Hash() byteutils.Hash {
+	return b.hash
+}
+
+// ParentHash return the hash of parent block.
+func (b *BlockHeader) ParentHash() byteutils.Hash {
+	return b.parentHash
+}
+
+// Timestamp return the timestamp of header.
+func (b *BlockHeader) Timestamp() int64 {
+	return b.timestamp
+}
+
+// GasLimit return the max gas of the block's txs, nil before BlockGasLimitForkHeight.
+func (b *BlockHeader) GasLimit() *util.Uint128 {
+	return b.gasLimit
+}
+
+// GasUsed return the gas used by the block's txs, nil before BlockGasLimitForkHeight.
+func (b *BlockHeader) GasUsed() *util.Uint128 {
+	return b.gasUsed
+}
+
+// Bloom return the bloom of the block's tx addresses and event topics, nil before BlockBloomForkHeight.
+func (b *BlockHeader) Bloom() Bloom {
+	return b.bloom
+}
+
+// GetBlockHeader return the header of block with hash from storage, the transactions are not decoded.
+func (bc *BlockChain) GetBlockHeader(hash byteutils.Hash) (*BlockHeader, error) {
+	value, err := bc.storage.Get(blockStorageKey(hash))
+	if err != nil {
+		return nil, err
+	}
+	pbBlock := new(corepb.LightBlock)
+	if err := proto.Unmarshal(value, pbBlock); err != nil {
+		return nil, err
+	}
+	header := new(BlockHeader)
+	if err := header.FromProto(pbBlock.Header); err != nil {
+		return nil, err
+	}
+	return header, nil
+}
+
+// GetBlockHeaderOnCanonicalChainByHeight return the header of block with height on canonical chain.
+func (bc *BlockChain) GetBlockHeaderOnCanonicalChainByHeight(height uint64) (*BlockHeader, error) {
+	if height > bc.TailBlock().height {
+		return nil, ErrCannotFindBlockAtGivenHeight
+	}
+	hash, err := bc.storage.Get(heightStorageKey(height))
+	if err != nil {
+		return nil, err
+	}
+	return bc.GetBlockHeader(hash)
+}
+
+// VerifyHeaderChain verify the headers ordered by height ascending without the transactions,
+// each header links to the previous one, is minted at a later slot and signed by its proposer.
+func VerifyHeaderChain(headers []*BlockHeader) error {
+	for i, header := range headers {
+		if i > 0 && !header.parentHash.Equals(headers[i-1].hash) {
+			logging.VLog().WithFields(logrus.Fields{
+				"header": header.hash,
+				"parent": headers[i-1].hash,
+			}).Debug("Failed to link the header to its parent.")
+			return ErrLinkToWrongParentBlock
+		}
+
+		if header.timestamp <= 0 || header.timestamp%BlockIntervalInSecond != 0 ||
+			header.timestamp != header.consensusRoot.Timestamp ||
+			(i > 0 && header.timestamp <= headers[i-1].timestamp) {
+			logging.VLog().WithFields(logrus.Fields{
+				"header":    header.hash,
+				"timestamp": header.timestamp,
+			}).Debug("Failed to check the header's timestamp.")
+			return ErrInvalidHeaderTimestamp
+		}
+
+		signer, err := RecoverSignerFromSignature(header.alg, header.hash, header.sign)
+		if err != nil {
+			return err
+		}
+		if !byteutils.Equal(signer.Bytes(), header.consensusRoot.Proposer) {
+			logging.VLog().WithFields(logrus.Fields{
+				"header": header.hash,
+				"signer": signer,
+			}).Debug("Failed to check the header's signer.")
+			return ErrInvalidHeaderSigner
+		}
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"testing"
+
+	"github.com/alexlisong/go-nebulas/consensus/pb"
+	"github.com/alexlisong/go-nebulas/crypto"
+	"github.com/alexlisong/go-nebulas/crypto/keystore"
+	"github.com/alexlisong/go-nebulas/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func mockSignature(t testing.TB, addr *Address) keystore.Signature {
+	key, err := keystore.DefaultKS.GetUnlocked(addr.String())
+	assert.Nil(t, err)
+	signature, err := crypto.NewSignature(keystore.SECP256K1)
+	assert.Nil(t, err)
+	assert.Nil(t, signature.InitSign(key.(keystore.PrivateKey)))
+	return signature
+}
+
+// mockHeaderChain extend the canonical chain with n blocks signed by their proposers, each packs txs transactions.
+func mockHeaderChain(t testing.TB, bc *BlockChain, n, txs int) []*Block {
+	from, to := mockAddress(), mockAddress()
+	fromSignature := mockSignature(t, from)
+	coinbase := mockAddress()
+	signature := mockSignature(t, coinbase)
+
+	blocks := []*Block{}
+	nonce := uint64(0)
+	for i := 0; i < n; i++ {
+		block, err := bc.NewBlock(coinbase)
+		assert.Nil(t, err)
+		block.header.timestamp = bc.TailBlock().Timestamp() + BlockIntervalInSecond
+		for j := 0; j < txs; j++ {
+			nonce++
+			tx, err := NewTransaction(bc.ChainID(), from, to, util.NewUint128(), nonce, TxPayloadBinaryType, nil, TransactionGasPrice, TransactionMaxGas)
+			assert.Nil(t, err)
+			assert.Nil(t, tx.Sign(fromSignature))
+			block.transactions = append(block.transactions, tx)
+		}
+		assert.Nil(t, block.Seal())
+		block.header.consensusRoot = &consensuspb.ConsensusRoot{
+			Timestamp: block.header.timestamp,
+			Proposer:  coinbase.Bytes(),
+		}
+		block.header.hash, err = block.calHash()
+		assert.Nil(t, err)
+		assert.Nil(t, block.Sign(signature))
+		assert.Nil(t, bc.StoreBlockToStorage(block))
+		assert.Nil(t, bc.storage.Put(heightStorageKey(block.height), block.Hash()))
+		bc.tailBlock = block
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+func TestBlockChain_GetBlockHeader(t *testing.T) {
+	neb := testNeb(t)
+	bc := neb.chain
+	blocks := mockHeaderChain(t, bc, 3, 2)
+
+	for _, block := range blocks {
+		header, err := bc.GetBlockHeader(block.Hash())
+		assert.Nil(t, err)
+		assert.Equal(t, block.Hash(), header.Hash())
+		assert.Equal(t, block.StateRoot(), header.stateRoot)
+		assert.Equal(t, block.Signature(), header.sign)
+
+		header, err = bc.GetBlockHeaderOnCanonicalChainByHeight(block.Height())
+		assert.Nil(t, err)
+		assert.Equal(t, block.Hash(), header.Hash())
+		assert.Equal(t, block.ParentHash(), header.ParentHash())
+		assert.Equal(t, block.Timestamp(), header.Timestamp())
+	}
+
+	_, err := bc.GetBlockHeaderOnCanonicalChainByHeight(blocks[2].Height() + 1)
+	assert.Equal(t, ErrCannotFindBlockAtGivenHeight, err)
+}
+
+func TestVerifyHeaderChain(t *testing.T) {
+	neb := testNeb(t)
+	bc := neb.chain
+	blocks := mockHeaderChain(t, bc, 5, 1)
+
+	headers := []*BlockHeader{}
+	for _, block := range blocks {
+		header, err := bc.GetBlockHeader(block.Hash())
+		assert.Nil(t, err)
+		headers = append(headers, header)
+	}
+	assert.Nil(t, VerifyHeaderChain(headers))
+	assert.Nil(t, VerifyHeaderChain(nil))
+
+	// the headers must be linked by parent hash.
+	assert.Equal(t, ErrLinkToWrongParentBlock, VerifyHeaderChain([]*BlockHeader{headers[0], headers[2]}))
+	assert.Equal(t, ErrLinkToWrongParentBlock, VerifyHeaderChain([]*BlockHeader{headers[1], headers[0]}))
+
+	// the timestamp must be at a block slot.
+	copied := *headers[1]
+	copied.timestamp++
+	assert.Equal(t, ErrInvalidHeaderTimestamp, VerifyHeaderChain([]*BlockHeader{headers[0], &copied, headers[2]}))
+	copied = *headers[1]
+	copied.timestamp = headers[0].timestamp
+	assert.Equal(t, ErrInvalidHeaderTimestamp, VerifyHeaderChain([]*BlockHeader{headers[0], &copied}))
+
+	// the header must be signed by its proposer.
+	copied = *headers[1]
+	sign, err := mockSignature(t, mockAddress()).Sign(copied.hash)
+	assert.Nil(t, err)
+	copied.sign = sign
+	assert.Equal(t, ErrInvalidHeaderSigner, VerifyHeaderChain([]*BlockHeader{headers[0], &copied, headers[2]}))
+}
+
+func verifyHeaders(b *testing.B, bc *BlockChain, blocks []*Block) {
+	for i := 0; i < b.N; i++ {
+		headers := []*BlockHeader{}
+		for _, block := range blocks {
+			header, err := bc.GetBlockHeader(block.Hash())
+			if err != nil {
+				b.Fatal(err)
+			}
+			headers = append(headers, header)
+		}
+		if err := VerifyHeaderChain(headers); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func verifyBlocks(b *testing.B, bc *BlockChain, blocks []*Block) {
+	for i := 0; i < b.N; i++ {
+		for _, v := range blocks {
+			block, err := LoadBlockFromStorage(v.Hash(), bc)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if err := block.VerifyIntegrity(bc.ChainID(), bc.ConsensusHandler()); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkVerifyHeaderChain(b *testing.B) {
+	bc := testNeb(b).chain
+	blocks := mockHeaderChain(b, bc, 100, 20)
+	b.ResetTimer()
+	verifyHeaders(b, bc, blocks)
+}
+
+func BenchmarkVerifyBlocks(b *testing.B) {
+	bc := testNeb(b).chain
+	blocks := mockHeaderChain(b, bc, 100, 20)
+	b.ResetTimer()
+	verifyBlocks(b, bc, blocks)
+}
+
+func TestVerifyHeaderChain_Performance(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skip benchmark in short mode")
+	}
+
+	bc := testNeb(t).chain
+	blocks := mockHeaderChain(t, bc, 100, 20)
+	headerResult := testing.Benchmark(func(b *testing.B) { verifyHeaders(b, bc, blocks) })
+	blockResult := testing.Benchmark(func(b *testing.B) { verifyBlocks(b, bc, blocks) })
+	t.Logf("headers: %v, blocks: %v", headerResult, blockResult)
+	assert.True(t, blockResult.NsPerOp() >= 10*headerResult.NsPerOp())
+}
@@ -0,0 +1,167 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/alexlisong/go-nebulas/util/byteutils"
+)
+
+// BlockIterator iterates the blocks on canonical chain by height ascending,
+// the blocks are loaded from storage one by one.
+type BlockIterator struct {
+	bc    *BlockChain
+	next  uint64
+	to    uint64
+	block *Block
+	err   error
+}
+
+// BlockSummary is the summary of a block in DumpJSON.
+type BlockSummary struct {
+	Hash       string `json:"hash"`
+	Height     uint64 `json:"height"`
+	ParentHash string `json:"parent_hash"`
+	Miner      string `json:"miner"`
+	TxCount    int    `json:"tx_count"`
+	Timestamp  int64  `json:"timestamp"`
+}
+
+// Iterator return the iterator of canonical blocks in [fromHeight, toHeight],
+// fromHeight below genesis starts from genesis, toHeight above tail stops at tail.
+func (bc *BlockChain) Iterator(fromHeight, toHeight uint64) *BlockIterator {
+	if genesisHeight := bc.genesisBlock.Height(); fromHeight < genesisHeight {
+		fromHeight = genesisHeight
+	}
+	if tailHeight := bc.TailBlock().Height(); toHeight > tailHeight {
+		toHeight = tailHeight
+	}
+	return &BlockIterator{
+		bc:   bc,
+		next: fromHeight,
+		to:   toHeight,
+	}
+}
+
+// Next moves to the next block, it returns false at the end or on error.
+// If the canonical chain is changed under the iterated blocks, it stops with ErrCanonicalChainChanged.
+func (it *BlockIterator) Next() bool {
+	if it.err != nil || it.next > it.to {
+		return false
+	}
+
+	block := it.bc.GetBlockOnCanonicalChainByHeight(it.next)
+	if block == nil || (it.block != nil && !block.ParentHash().Equals(it.block.Hash())) {
+		it.err = ErrCanonicalChainChanged
+		return false
+	}
+	it.block = block
+	it.next++
+	return true
+}
+
+// Block return the current block.
+func (it *BlockIterator) Block() *Block {
+	return it.block
+}
+
+// Err return the error stopped the iteration.
+func (it *BlockIterator) Err() error {
+	return it.err
+}
+
+// FetchDescendantInCanonicalChainCh streams at most n descendants of block on canonical chain by height ascending,
+// the blocks are loaded lazily as the receiver consumes them. Both channels are closed when the stream ends,
+// the descendants of tail is empty, and the stream stops at tail if n reaches beyond it.
+// An error is sent if block is not on canonical chain, the chain is changed during streaming or ctx is done.
+func (bc *BlockChain) FetchDescendantInCanonicalChainCh(ctx context.Context, n int, block *Block) (<-chan *Block, <-chan error) {
+	blockCh := make(chan *Block)
+	errCh := make(chan error, 1)
+
+	go func() {
+		defer close(errCh)
+		defer close(blockCh)
+
+		canonical := bc.GetBlockOnCanonicalChainByHash(block.Hash())
+		if canonical == nil {
+			errCh <- ErrNotBlockInCanonicalChain
+			return
+		}
+		if n <= 0 {
+			return
+		}
+
+		it := bc.Iterator(canonical.Height()+1, canonical.Height()+uint64(n))
+		it.block = canonical
+		for it.Next() {
+			select {
+			case blockCh <- it.Block():
+			case <-ctx.Done():
+				errCh <- ctx.Err()
+				return
+			}
+		}
+		if err := it.Err(); err != nil {
+			errCh <- err
+		}
+	}()
+	return blockCh, errCh
+}
+
+// Summary return the summary of block.
+func (block *Block) Summary() *BlockSummary {
+	return &BlockSummary{
+		Hash:       block.Hash().String(),
+		Height:     block.Height(),
+		ParentHash: block.ParentHash().String(),
+		Miner:      byteutils.Hash(block.header.consensusRoot.Proposer).Base58(),
+		TxCount:    len(block.transactions),
+		Timestamp:  block.Timestamp(),
+	}
+}
+
+// DumpJSON dump the summaries of at most limit blocks from tail, skipping offset blocks, tail first.
+func (bc *BlockChain) DumpJSON(offset, limit uint64) (string, error) {
+	summaries := []*BlockSummary{}
+
+	tailHeight := bc.TailBlock().Height()
+	if limit > 0 && offset < tailHeight {
+		toHeight := tailHeight - offset
+		fromHeight := uint64(0)
+		if toHeight >= limit {
+			fromHeight = toHeight - limit + 1
+		}
+
+		it := bc.Iterator(fromHeight, toHeight)
+		for it.Next() {
+			summaries = append([]*BlockSummary{it.Block().Summary()}, summaries...)
+		}
+		if err := it.Err(); err != nil {
+			return "", err
+		}
+	}
+
+	bytes, err := json.Marshal(summaries)
+	if err != nil {
+		return "", err
+	}
+	return string(bytes), nil
+}
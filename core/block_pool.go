@@ -20,6 +20,9 @@ package core
 
 import (
 	"sync"
+	"sync/atomic"
+	"time"
+
 	"github.com/gogo/protobuf/proto"
 	"github.com/hashicorp/golang-lru"
 	"github.com/alexlisong/go-nebulas/core/pb"
@@ -35,6 +38,30 @@ const (
 	NoSender = ""
 )
 
+// Default block pool config
+const (
+	DefaultBlockPoolSize      = 4096
+	DefaultBlockPoolOrphanTTL = 300 * time.Second
+
+	// DefaultBlockTimestampMaxDrift is the default max seconds a received block's timestamp can be ahead of the node's clock.
+	DefaultBlockTimestampMaxDrift = 15
+
+	blockPoolExpireInterval = 10 * time.Second
+)
+
+// BlockPoolStats is the snapshot of block pool.
+type BlockPoolStats struct {
+	Size     int
+	Capacity int
+	// Evicted counts the blocks evicted to make room, including the ones evicted on arrival.
+	Evicted    uint64
+	Expired    uint64
+	Rejected   uint64
+	Duplicated uint64
+	// Dropped counts the block messages dropped by network dispatcher.
+	Dropped uint64
+}
+
 // BlockPool a pool of all received blocks from network.
 // Blocks will be sent to Consensus when it passes signature verification.
 type BlockPool struct {
@@ -46,8 +73,24 @@ type BlockPool struct {
 	bc    *BlockChain
 	cache *lru.Cache
 
-	ns net.Service
-	mu sync.RWMutex
+	orphanTTL         time.Duration
+	timestampMaxDrift int64
+	evicted           uint64
+	expired           uint64
+	rejected          uint64
+	duplicated        uint64
+
+	pipeline          *blockPipeline
+	pipelineQueueSize int
+	decodeWorkers     int
+	headerWorkers     int
+	signatureWorkers  int
+	// onCommit is called after a block from network passed the commit stage.
+	onCommit func(*Block)
+
+	ns         net.Service
+	subscriber *net.Subscriber
+	mu         sync.RWMutex
 }
 
 type linkedBlock struct {
@@ -58,18 +101,33 @@ type linkedBlock struct {
 
 	parentBlock *linkedBlock
 	childBlocks map[byteutils.HexHash]*linkedBlock
+
+	receivedAt time.Time
 }
 
-// NewBlockPool return new #BlockPool instance.
+// NewBlockPool return new #BlockPool instance, size is the max count of blocks waiting for parents, 0 for default.
 func NewBlockPool(size int) (*BlockPool, error) {
+	if size <= 0 {
+		size = DefaultBlockPoolSize
+	}
 	bp := &BlockPool{
 		size: size,
-		receiveBlockMessageCh:         make(chan net.Message, size),
-		receiveDownloadBlockMessageCh: make(chan net.Message, size),
+		receiveBlockMessageCh:         make(chan net.Message, DefaultPipelineQueueSize),
+		receiveDownloadBlockMessageCh: make(chan net.Message, DefaultPipelineQueueSize),
 		quitCh: make(chan int, 1),
+
+		orphanTTL:         DefaultBlockPoolOrphanTTL,
+		timestampMaxDrift: DefaultBlockTimestampMaxDrift,
+
+		pipelineQueueSize: DefaultPipelineQueueSize,
+		decodeWorkers:     DefaultPipelineDecodeWorkers,
+		headerWorkers:     DefaultPipelineHeaderWorkers,
+		signatureWorkers:  DefaultPipelineSignatureWorkers,
 	}
+	bp.pipeline = newBlockPipeline(bp)
 	var err error
-	bp.cache, err = lru.NewWithEvict(size, func(key interface{}, value interface{}) {
+	// the linkable blocks stay in cache for a moment even if the pool is full of orphans.
+	bp.cache, err = lru.NewWithEvict(size+DefaultPipelineQueueSize, func(key interface{}, value interface{}) {
 		lb := value.(*linkedBlock)
 		if lb != nil {
 			lb.Dispose()
@@ -84,18 +142,100 @@ func NewBlockPool(size int) (*BlockPool, error) {
 
 // RegisterInNetwork register message subscriber in network.
 func (pool *BlockPool) RegisterInNetwork(ns net.Service) {
-	ns.Register(net.NewSubscriber(pool, pool.receiveBlockMessageCh, true, MessageTypeNewBlock, net.MessageWeightNewBlock))
-	ns.Register(net.NewSubscriber(pool, pool.receiveBlockMessageCh, false, MessageTypeBlockDownloadResponse, net.MessageWeightZero))
-	ns.Register(net.NewSubscriber(pool, pool.receiveDownloadBlockMessageCh, false, MessageTypeParentBlockDownloadRequest, net.MessageWeightZero))
+	// the stalest blocks are dropped when the pipeline is busy, the newer ones are more likely to extend the chain.
+	pool.subscriber = net.NewSubscriber(pool, []string{MessageTypeNewBlock, MessageTypeBlockDownloadResponse},
+		net.WithMessageChan(pool.receiveBlockMessageCh),
+		net.WithDoFilter(MessageTypeNewBlock),
+		net.WithMessageWeight(MessageTypeNewBlock, net.MessageWeightNewBlock),
+		net.WithPriority(net.MessagePriorityHigh),
+		net.WithDispatchPolicy(net.DispatchDropOldest),
+		net.WithName("BlockPool.blocks"),
+	)
+	ns.Register(pool.subscriber)
+	ns.Register(net.NewSubscriber(pool, []string{MessageTypeParentBlockDownloadRequest},
+		net.WithMessageChan(pool.receiveDownloadBlockMessageCh),
+		net.WithName("BlockPool.downloads"),
+	))
 	pool.ns = ns
 }
 
+// SetPipelineConfig config the queue size and worker counts of pipeline stages, 0 for default.
+// Execution and commit are always single-threaded. It should be called before Start.
+func (pool *BlockPool) SetPipelineConfig(queueSize, decodeWorkers, headerWorkers, signatureWorkers uint32) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	if queueSize > 0 {
+		pool.pipelineQueueSize = int(queueSize)
+	}
+	if decodeWorkers > 0 {
+		pool.decodeWorkers = int(decodeWorkers)
+	}
+	if headerWorkers > 0 {
+		pool.headerWorkers = int(headerWorkers)
+	}
+	if signatureWorkers > 0 {
+		pool.signatureWorkers = int(signatureWorkers)
+	}
+	pool.pipeline = newBlockPipeline(pool)
+}
+
+// SetOrphanTTL config the seconds a block waits for its parent before expired, 0 for default.
+func (pool *BlockPool) SetOrphanTTL(ttl int64) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	if ttl <= 0 {
+		pool.orphanTTL = DefaultBlockPoolOrphanTTL
+		return
+	}
+	pool.orphanTTL = time.Duration(ttl) * time.Second
+}
+
+// SetTimestampMaxDrift config the max seconds a received block's timestamp can be ahead of the node's clock, 0 for default.
+func (pool *BlockPool) SetTimestampMaxDrift(drift int64) {
+	if drift <= 0 {
+		drift = DefaultBlockTimestampMaxDrift
+	}
+	atomic.StoreInt64(&pool.timestampMaxDrift, drift)
+}
+
+// Stats return the occupancy and counters of block pool.
+func (pool *BlockPool) Stats() *BlockPoolStats {
+	return &BlockPoolStats{
+		Size:       pool.cache.Len(),
+		Capacity:   pool.size,
+		Evicted:    atomic.LoadUint64(&pool.evicted),
+		Expired:    atomic.LoadUint64(&pool.expired),
+		Rejected:   atomic.LoadUint64(&pool.rejected),
+		Duplicated: atomic.LoadUint64(&pool.duplicated),
+		Dropped:    pool.droppedMessages(),
+	}
+}
+
+func (pool *BlockPool) droppedMessages() uint64 {
+	if pool.subscriber == nil {
+		return 0
+	}
+	return pool.subscriber.DroppedMessages()
+}
+
+// PipelineStats return the queue depths, counters and latency histograms of pipeline stages.
+func (pool *BlockPool) PipelineStats() []*PipelineStageStats {
+	return pool.pipeline.stats()
+}
+
 // Start start loop.
 func (pool *BlockPool) Start() {
 	logging.CLog().WithFields(logrus.Fields{
-		"size": pool.size,
+		"size":              pool.size,
+		"pipelineQueueSize": pool.pipelineQueueSize,
+		"decodeWorkers":     pool.decodeWorkers,
+		"headerWorkers":     pool.headerWorkers,
+		"signatureWorkers":  pool.signatureWorkers,
 	}).Info("Starting BlockPool...")
 
+	pool.pipeline.start()
 	go pool.loop()
 }
 
@@ -105,54 +245,13 @@ func (pool *BlockPool) Stop() {
 		"size": pool.size,
 	}).Info("Stopping BlockPool...")
 
+	pool.pipeline.stop()
 	pool.quitCh <- 0
 }
 
+// handleReceivedBlock runs all the pipeline stages on msg synchronously.
 func (pool *BlockPool) handleReceivedBlock(msg net.Message) {
-	if msg.MessageType() != MessageTypeNewBlock && msg.MessageType() != MessageTypeBlockDownloadResponse {
-		logging.VLog().WithFields(logrus.Fields{
-			"msgType": msg.MessageType(),
-			"msg":     msg,
-			"err":     "neither new block nor download block response msg",
-		}).Debug("Received unregistered message.")
-		return
-	}
-
-	block := new(Block)
-	pbblock := new(corepb.Block)
-	if err := proto.Unmarshal(msg.Data(), pbblock); err != nil {
-		logging.VLog().WithFields(logrus.Fields{
-			"msgType": msg.MessageType(),
-			"msg":     msg,
-			"err":     err,
-		}).Debug("Failed to unmarshal data.")
-		return
-	}
-	if err := block.FromProto(pbblock); err != nil {
-		logging.VLog().WithFields(logrus.Fields{
-			"msgType": msg.MessageType(),
-			"msg":     msg,
-			"err":     err,
-		}).Debug("Failed to recover a block from proto data.")
-		return
-	}
-
-	if msg.MessageType() == MessageTypeNewBlock &&
-		pool.bc.ConsensusHandler().CheckTimeout(block) {
-		return
-	}
-
-	if msg.MessageType() == MessageTypeNewBlock &&
-		pool.bc.ConsensusHandler().CheckDoubleMint(block) {
-		return
-	}
-
-	logging.VLog().WithFields(logrus.Fields{
-		"block": block,
-		"type":  msg.MessageType(),
-	}).Debug("Received a new block.")
-
-	pool.PushAndRelay(msg.MessageFrom(), block)
+	pool.pipeline.process(&blockTask{msg: msg})
 }
 
 func (pool *BlockPool) handleParentDownloadRequest(msg net.Message) {
@@ -233,14 +332,19 @@ func (pool *BlockPool) handleParentDownloadRequest(msg net.Message) {
 
 func (pool *BlockPool) loop() {
 	logging.CLog().Info("Started BlockPool.")
+	expireTicker := time.NewTicker(blockPoolExpireInterval)
+	defer expireTicker.Stop()
+
 	for {
 		select {
+		case <-expireTicker.C:
+			pool.mu.Lock()
+			pool.expireOrphans()
+			pool.mu.Unlock()
 
 		case <-pool.quitCh:
 			logging.CLog().Info("Stopped BlockPool.")
 			return
-		case msg := <-pool.receiveBlockMessageCh:
-			go pool.handleReceivedBlock(msg)
 		case msg := <-pool.receiveDownloadBlockMessageCh:
 			go pool.handleParentDownloadRequest(msg)
 		}
@@ -341,29 +445,91 @@ func (pool *BlockPool) downloadParent(sender string, block *Block) error {
 }
 
 func (pool *BlockPool) push(sender string, block *Block) error {
-	// verify non-dup block
+	if pool.isDuplicated(block) {
+		return ErrDuplicatedBlock
+	}
+	if err := pool.verifyTimestamp(block); err != nil {
+		return err
+	}
+	if err := pool.verifyIntegrity(block); err != nil {
+		return err
+	}
+	if err := pool.execute(sender, block); err != nil {
+		return err
+	}
+
+	// notify consensus to handle new block.
+	return pool.bc.ConsensusHandler().ForkChoice()
+}
+
+// isDuplicated verify non-dup block
+func (pool *BlockPool) isDuplicated(block *Block) bool {
 	if pool.cache.Contains(block.Hash().Hex()) ||
 		pool.bc.GetBlock(block.Hash()) != nil {
+		atomic.AddUint64(&pool.duplicated, 1)
 		logging.VLog().WithFields(logrus.Fields{
 			"block": block,
 		}).Debug("Found duplicated block.")
-		return ErrDuplicatedBlock
+		return true
 	}
+	return false
+}
+
+// verifyTimestamp verify the block is not from future and minted at a block slot.
+func (pool *BlockPool) verifyTimestamp(block *Block) error {
+	if block.Timestamp() > time.Now().Unix()+atomic.LoadInt64(&pool.timestampMaxDrift) {
+		logging.VLog().WithFields(logrus.Fields{
+			"block": block,
+			"drift": atomic.LoadInt64(&pool.timestampMaxDrift),
+		}).Debug("Block is too far in the future.")
+		return ErrBlockTooFarInFuture
+	}
+	if block.Height() >= BlockTimestampForkHeight && block.Timestamp()%BlockIntervalInSecond != 0 {
+		logging.VLog().WithFields(logrus.Fields{
+			"block":    block,
+			"interval": BlockIntervalInSecond,
+		}).Debug("Block is not minted at a block slot.")
+		return ErrInvalidBlockSlot
+	}
+	return nil
+}
 
-	// verify block integrity
-	if err := block.VerifyIntegrity(pool.bc.chainID, pool.bc.ConsensusHandler()); err != nil {
+// verifyIntegrity verify block integrity
+func (pool *BlockPool) verifyIntegrity(block *Block) error {
+	startAt := time.Now()
+	err := block.VerifyIntegrity(pool.bc.chainID, pool.bc.ConsensusHandler())
+	pool.bc.verifyLatency.observe(time.Since(startAt))
+	if err != nil {
 		logging.VLog().WithFields(logrus.Fields{
 			"block": block,
 			"err":   err,
 		}).Debug("Failed to check block integrity.")
 		return err
 	}
+	return nil
+}
+
+// reportPeer report the verdict of the block message to its sender.
+func (pool *BlockPool) reportPeer(msg net.Message, verdict net.Verdict) {
+	if pool.ns == nil || msg.MessageFrom() == NoSender {
+		return
+	}
+	pool.ns.ReportPeer(msg.MessageFrom(), verdict)
+}
 
+// execute link the block with blocks in pool and chain, then verify and store all linked blocks.
+func (pool *BlockPool) execute(sender string, block *Block) error {
 	bc := pool.bc
 	cache := pool.cache
 
 	var plb *linkedBlock
 	lb := newLinkedBlock(block, pool.bc)
+	if bc.GetBlock(lb.parentHash) == nil {
+		// the block will wait for its parent in pool.
+		if err := pool.admit(lb); err != nil {
+			return err
+		}
+	}
 	cache.Add(lb.hash.Hex(), lb)
 
 	// find child block in pool.
@@ -412,7 +578,7 @@ func (pool *BlockPool) push(sender string, block *Block) error {
 		if gap > ChunkSize {
 			if bc.StartActiveSync() {
 				logging.CLog().WithFields(logrus.Fields{
-					"tail":    bc.tailBlock,
+					"tail":    bc.TailBlock(),
 					"block":   block,
 					"offline": gap,
 					"limit":   ChunkSize,
@@ -448,9 +614,79 @@ func (pool *BlockPool) push(sender string, block *Block) error {
 	for _, v := range allBlocks {
 		cache.Remove(v.Hash().Hex())
 	}
+	return nil
+}
 
-	// notify consensus to handle new block.
-	return pool.bc.ConsensusHandler().ForkChoice()
+// admit make room for the orphan block lb, the expired blocks are dropped first,
+// then the lowest block is evicted, the oldest one if there are several.
+// A block can only evict blocks lower than itself.
+func (pool *BlockPool) admit(lb *linkedBlock) error {
+	pool.expireOrphans()
+	if pool.cache.Len() < pool.size {
+		return nil
+	}
+
+	var victim *linkedBlock
+	for _, k := range pool.cache.Keys() {
+		v, ok := pool.cache.Peek(k)
+		if !ok {
+			continue
+		}
+		c := v.(*linkedBlock)
+		if victim == nil || c.block.Height() < victim.block.Height() ||
+			(c.block.Height() == victim.block.Height() && c.receivedAt.Before(victim.receivedAt)) {
+			victim = c
+		}
+	}
+	if victim == nil {
+		return nil
+	}
+
+	if lb.block.Height() < victim.block.Height() {
+		atomic.AddUint64(&pool.evicted, 1)
+		logging.VLog().WithFields(logrus.Fields{
+			"block":  lb.block,
+			"lowest": victim.block,
+		}).Debug("Block is evicted on arrival.")
+		return ErrBlockEvictedOnArrival
+	}
+	if lb.block.Height() == victim.block.Height() {
+		atomic.AddUint64(&pool.rejected, 1)
+		logging.VLog().WithFields(logrus.Fields{
+			"block":  lb.block,
+			"lowest": victim.block,
+		}).Debug("Block pool is full.")
+		return ErrBlockPoolFull
+	}
+
+	logging.VLog().WithFields(logrus.Fields{
+		"block":  victim.block,
+		"arrive": lb.block,
+	}).Debug("Evict the lowest block in pool.")
+	pool.cache.Remove(victim.hash.Hex())
+	atomic.AddUint64(&pool.evicted, 1)
+	return nil
+}
+
+// expireOrphans drop the blocks waiting for parents longer than ttl.
+func (pool *BlockPool) expireOrphans() {
+	now := time.Now()
+	for _, k := range pool.cache.Keys() {
+		v, ok := pool.cache.Peek(k)
+		if !ok {
+			continue
+		}
+		lb := v.(*linkedBlock)
+		if now.Sub(lb.receivedAt) <= pool.orphanTTL {
+			continue
+		}
+		logging.VLog().WithFields(logrus.Fields{
+			"block": lb.block,
+			"ttl":   pool.orphanTTL,
+		}).Debug("Block waited for parent too long, expired.")
+		pool.cache.Remove(k)
+		atomic.AddUint64(&pool.expired, 1)
+	}
 }
 
 func (pool *BlockPool) setBlockChain(bc *BlockChain) {
@@ -465,6 +701,7 @@ func newLinkedBlock(block *Block, chain *BlockChain) *linkedBlock {
 		parentHash:  block.ParentHash(),
 		parentBlock: nil,
 		childBlocks: make(map[byteutils.HexHash]*linkedBlock),
+		receivedAt:  time.Now(),
 	}
 }
 
@@ -483,7 +720,10 @@ func (lb *linkedBlock) travelToLinkAndReturnAllValidBlocks(parentBlock *Block) (
 		return nil, nil, err
 	}
 
-	if err := lb.block.VerifyExecution(); err != nil {
+	startAt := time.Now()
+	err := lb.block.VerifyExecution()
+	lb.chain.executeLatency.observe(time.Since(startAt))
+	if err != nil {
 		logging.VLog().WithFields(logrus.Fields{
 			"block": lb.block,
 			"err":   err,
@@ -0,0 +1,436 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/alexlisong/go-nebulas/core/pb"
+	"github.com/alexlisong/go-nebulas/net"
+	"github.com/alexlisong/go-nebulas/util/logging"
+	"github.com/gogo/protobuf/proto"
+	"github.com/sirupsen/logrus"
+)
+
+// Block pipeline stages
+const (
+	PipelineStageDecode    = "decode"
+	PipelineStageHeader    = "header"
+	PipelineStageSignature = "signature"
+	PipelineStageExecution = "execution"
+	PipelineStageCommit    = "commit"
+)
+
+// Default block pipeline config
+const (
+	DefaultPipelineQueueSize        = 128
+	DefaultPipelineDecodeWorkers    = 4
+	DefaultPipelineHeaderWorkers    = 2
+	DefaultPipelineSignatureWorkers = 4
+)
+
+var (
+	// PipelineLatencyBuckets upper bounds of the stage latency histogram, the last bucket is unbounded.
+	PipelineLatencyBuckets = []time.Duration{
+		time.Millisecond,
+		5 * time.Millisecond,
+		10 * time.Millisecond,
+		50 * time.Millisecond,
+		100 * time.Millisecond,
+		500 * time.Millisecond,
+		time.Second,
+		5 * time.Second,
+	}
+)
+
+// LatencyBucket is a bucket of the stage latency histogram.
+// UpperBound 0 means unbounded.
+type LatencyBucket struct {
+	UpperBound time.Duration
+	Count      uint64
+}
+
+// PipelineStageStats is the snapshot of a block pipeline stage.
+type PipelineStageStats struct {
+	Name       string
+	Workers    int
+	QueueDepth int
+	QueueSize  int
+	Processed  uint64
+	Failed     uint64
+	// Blocked counts the times producer waited on the full queue.
+	Blocked uint64
+	Latency []LatencyBucket
+}
+
+type latencyHistogram struct {
+	counts []uint64
+}
+
+func newLatencyHistogram() *latencyHistogram {
+	return &latencyHistogram{
+		counts: make([]uint64, len(PipelineLatencyBuckets)+1),
+	}
+}
+
+func (h *latencyHistogram) observe(d time.Duration) {
+	idx := len(PipelineLatencyBuckets)
+	for i, bound := range PipelineLatencyBuckets {
+		if d <= bound {
+			idx = i
+			break
+		}
+	}
+	atomic.AddUint64(&h.counts[idx], 1)
+}
+
+func (h *latencyHistogram) snapshot() []LatencyBucket {
+	buckets := make([]LatencyBucket, len(h.counts))
+	for i := range h.counts {
+		if i < len(PipelineLatencyBuckets) {
+			buckets[i].UpperBound = PipelineLatencyBuckets[i]
+		}
+		buckets[i].Count = atomic.LoadUint64(&h.counts[i])
+	}
+	return buckets
+}
+
+type blockTask struct {
+	msg   net.Message
+	block *Block
+	err   error
+	done  chan struct{}
+}
+
+// pipelineStage runs handle on tasks by workers concurrently,
+// and forwards the finished tasks to next stage in arrival order.
+type pipelineStage struct {
+	name    string
+	workers int
+	handle  func(*blockTask) error
+	next    *pipelineStage
+
+	queue   chan *blockTask
+	work    chan *blockTask
+	ordered chan *blockTask
+	quitCh  chan int
+
+	processed uint64
+	failed    uint64
+	blocked   uint64
+	latency   *latencyHistogram
+}
+
+func newPipelineStage(name string, workers, queueSize int, handle func(*blockTask) error) *pipelineStage {
+	return &pipelineStage{
+		name:    name,
+		workers: workers,
+		handle:  handle,
+		queue:   make(chan *blockTask, queueSize),
+		work:    make(chan *blockTask),
+		ordered: make(chan *blockTask, workers),
+		quitCh:  make(chan int),
+		latency: newLatencyHistogram(),
+	}
+}
+
+// enqueue blocks when queue is full, which is the backpressure to the producer.
+func (s *pipelineStage) enqueue(task *blockTask) bool {
+	select {
+	case s.queue <- task:
+		return true
+	default:
+	}
+
+	atomic.AddUint64(&s.blocked, 1)
+	select {
+	case s.queue <- task:
+		return true
+	case <-s.quitCh:
+		return false
+	}
+}
+
+func (s *pipelineStage) start() {
+	for i := 0; i < s.workers; i++ {
+		go s.runWorker()
+	}
+	go s.schedule()
+	go s.forward()
+}
+
+func (s *pipelineStage) stop() {
+	close(s.quitCh)
+}
+
+func (s *pipelineStage) schedule() {
+	for {
+		select {
+		case <-s.quitCh:
+			return
+		case task := <-s.queue:
+			task.done = make(chan struct{})
+			// ordered is bounded by workers, so are the tasks in flight.
+			select {
+			case s.ordered <- task:
+			case <-s.quitCh:
+				return
+			}
+			select {
+			case s.work <- task:
+			case <-s.quitCh:
+				return
+			}
+		}
+	}
+}
+
+func (s *pipelineStage) runWorker() {
+	for {
+		select {
+		case <-s.quitCh:
+			return
+		case task := <-s.work:
+			start := time.Now()
+			task.err = s.handle(task)
+			s.latency.observe(time.Since(start))
+			if task.err != nil {
+				atomic.AddUint64(&s.failed, 1)
+			}
+			atomic.AddUint64(&s.processed, 1)
+			close(task.done)
+		}
+	}
+}
+
+func (s *pipelineStage) forward() {
+	for {
+		select {
+		case <-s.quitCh:
+			return
+		case task := <-s.ordered:
+			select {
+			case <-task.done:
+			case <-s.quitCh:
+				return
+			}
+			if task.err != nil || s.next == nil {
+				continue
+			}
+			if !s.next.enqueue(task) {
+				return
+			}
+		}
+	}
+}
+
+func (s *pipelineStage) stats() *PipelineStageStats {
+	return &PipelineStageStats{
+		Name:       s.name,
+		Workers:    s.workers,
+		QueueDepth: len(s.queue),
+		QueueSize:  cap(s.queue),
+		Processed:  atomic.LoadUint64(&s.processed),
+		Failed:     atomic.LoadUint64(&s.failed),
+		Blocked:    atomic.LoadUint64(&s.blocked),
+		Latency:    s.latency.snapshot(),
+	}
+}
+
+// blockPipeline verifies the blocks from network in stages:
+// decode -> header -> signature -> execution -> commit.
+// execution & commit are single-threaded to keep the chain deterministic.
+type blockPipeline struct {
+	pool   *BlockPool
+	stages []*pipelineStage
+	quitCh chan int
+}
+
+func newBlockPipeline(pool *BlockPool) *blockPipeline {
+	p := &blockPipeline{
+		pool:   pool,
+		quitCh: make(chan int),
+	}
+	p.stages = []*pipelineStage{
+		newPipelineStage(PipelineStageDecode, pool.decodeWorkers, pool.pipelineQueueSize, p.decode),
+		newPipelineStage(PipelineStageHeader, pool.headerWorkers, pool.pipelineQueueSize, p.checkHeader),
+		newPipelineStage(PipelineStageSignature, pool.signatureWorkers, pool.pipelineQueueSize, p.verifySignature),
+		newPipelineStage(PipelineStageExecution, 1, pool.pipelineQueueSize, p.execute),
+		newPipelineStage(PipelineStageCommit, 1, pool.pipelineQueueSize, p.commit),
+	}
+	for i := 0; i < len(p.stages)-1; i++ {
+		p.stages[i].next = p.stages[i+1]
+	}
+	return p
+}
+
+func (p *blockPipeline) start() {
+	for _, s := range p.stages {
+		s.start()
+	}
+	go p.loop()
+}
+
+func (p *blockPipeline) stop() {
+	close(p.quitCh)
+	for _, s := range p.stages {
+		s.stop()
+	}
+}
+
+// loop feeds received block messages into decode stage,
+// it stops draining the messages when decode queue is full.
+func (p *blockPipeline) loop() {
+	for {
+		select {
+		case <-p.quitCh:
+			return
+		case msg := <-p.pool.receiveBlockMessageCh:
+			if !p.stages[0].enqueue(&blockTask{msg: msg}) {
+				return
+			}
+		}
+	}
+}
+
+// process runs the stages on task one by one in caller's goroutine.
+func (p *blockPipeline) process(task *blockTask) error {
+	for _, s := range p.stages {
+		if err := s.handle(task); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *blockPipeline) stats() []*PipelineStageStats {
+	stats := make([]*PipelineStageStats, len(p.stages))
+	for i, s := range p.stages {
+		stats[i] = s.stats()
+	}
+	return stats
+}
+
+func (p *blockPipeline) decode(task *blockTask) error {
+	msg := task.msg
+	if msg.MessageType() != MessageTypeNewBlock && msg.MessageType() != MessageTypeBlockDownloadResponse {
+		logging.VLog().WithFields(logrus.Fields{
+			"msgType": msg.MessageType(),
+			"msg":     msg,
+			"err":     "neither new block nor download block response msg",
+		}).Debug("Received unregistered message.")
+		return ErrInvalidArgument
+	}
+
+	block := new(Block)
+	pbblock := new(corepb.Block)
+	if err := proto.Unmarshal(msg.Data(), pbblock); err != nil {
+		logging.VLog().WithFields(logrus.Fields{
+			"msgType": msg.MessageType(),
+			"msg":     msg,
+			"err":     err,
+		}).Debug("Failed to unmarshal data.")
+		p.pool.reportPeer(msg, net.VerdictInvalid)
+		return err
+	}
+	if err := block.FromProtoWithChainID(pbblock, p.pool.bc.ChainID()); err != nil {
+		logging.VLog().WithFields(logrus.Fields{
+			"msgType": msg.MessageType(),
+			"msg":     msg,
+			"err":     err,
+		}).Debug("Failed to recover a block from proto data.")
+		p.pool.reportPeer(msg, net.VerdictInvalid)
+		return err
+	}
+	task.block = block
+	return nil
+}
+
+func (p *blockPipeline) checkHeader(task *blockTask) error {
+	block := task.block
+	// the relayer of a stale or double minted block may be honest.
+	if task.msg.MessageType() == MessageTypeNewBlock &&
+		p.pool.bc.ConsensusHandler().CheckTimeout(block) {
+		p.pool.reportPeer(task.msg, net.VerdictUseless)
+		return ErrBlockReceivedTimeout
+	}
+
+	if task.msg.MessageType() == MessageTypeNewBlock &&
+		p.pool.bc.ConsensusHandler().CheckDoubleMint(block) {
+		p.pool.reportPeer(task.msg, net.VerdictUseless)
+		return ErrDoubleBlockMinted
+	}
+
+	if p.pool.isDuplicated(block) {
+		p.pool.reportPeer(task.msg, net.VerdictUseless)
+		return ErrDuplicatedBlock
+	}
+
+	if err := p.pool.verifyTimestamp(block); err != nil {
+		p.pool.reportPeer(task.msg, net.VerdictInvalid)
+		return err
+	}
+
+	logging.VLog().WithFields(logrus.Fields{
+		"block": block,
+		"type":  task.msg.MessageType(),
+	}).Debug("Received a new block.")
+	return nil
+}
+
+func (p *blockPipeline) verifySignature(task *blockTask) error {
+	if err := p.pool.verifyIntegrity(task.block); err != nil {
+		p.pool.reportPeer(task.msg, net.VerdictInvalid)
+		return err
+	}
+	return nil
+}
+
+func (p *blockPipeline) execute(task *blockTask) error {
+	pool := p.pool
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	// the block may be pushed by others after header checks.
+	if pool.isDuplicated(task.block) {
+		pool.reportPeer(task.msg, net.VerdictUseless)
+		return ErrDuplicatedBlock
+	}
+	if err := pool.execute(task.msg.MessageFrom(), task.block); err != nil {
+		return err
+	}
+	pool.reportPeer(task.msg, net.VerdictValid)
+	return nil
+}
+
+func (p *blockPipeline) commit(task *blockTask) error {
+	// notify consensus to handle new block.
+	if err := p.pool.bc.ConsensusHandler().ForkChoice(); err != nil {
+		logging.VLog().WithFields(logrus.Fields{
+			"block": task.block,
+			"err":   err,
+		}).Debug("Failed to choose fork.")
+		return err
+	}
+	if p.pool.onCommit != nil {
+		p.pool.onCommit(task.block)
+	}
+	return nil
+}
@@ -291,3 +291,184 @@ func TestHandleDownloadedBlock(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, received, data)
 }
+
+func TestBlockPool_Pipeline(t *testing.T) {
+	// generate a chain of blocks
+	src := testNeb(t).chain
+	blocks := []*Block{}
+	for i := 0; i < 200; i++ {
+		addr, err := AddressParse(MockDynasty[i%len(MockDynasty)])
+		assert.Nil(t, err)
+		block, err := NewBlock(src.ChainID(), addr, src.tailBlock)
+		assert.Nil(t, err)
+		block.header.timestamp = src.tailBlock.header.timestamp + BlockInterval
+		assert.Nil(t, block.Seal())
+		signBlock(block)
+		assert.Nil(t, src.bkPool.Push(block))
+		blocks = append(blocks, block)
+	}
+
+	bc := testNeb(t).chain
+	pool := bc.bkPool
+	pool.SetPipelineConfig(4, 2, 2, 2)
+	committed := make(chan *Block, len(blocks))
+	pool.onCommit = func(block *Block) {
+		committed <- block
+	}
+	pool.Start()
+	defer pool.Stop()
+
+	// feed a burst of blocks like the dispatcher.
+	for _, block := range blocks {
+		pbBlock, err := block.ToProto()
+		assert.Nil(t, err)
+		data, err := proto.Marshal(pbBlock)
+		assert.Nil(t, err)
+		pool.receiveBlockMessageCh <- net.NewBaseMessage(MessageTypeBlockDownloadResponse, "from", data)
+	}
+
+	for _, block := range blocks {
+		select {
+		case c := <-committed:
+			assert.Equal(t, block.Hash(), c.Hash())
+		case <-time.After(time.Minute):
+			t.Fatal("timeout to commit blocks")
+		}
+	}
+	assert.Equal(t, blocks[len(blocks)-1].Hash(), bc.TailBlock().Hash())
+
+	// counters are updated after the last commit returns.
+	stats := pool.PipelineStats()
+	for i := 0; i < 100 && stats[4].Processed < uint64(len(blocks)); i++ {
+		time.Sleep(10 * time.Millisecond)
+		stats = pool.PipelineStats()
+	}
+	assert.Equal(t, PipelineStageDecode, stats[0].Name)
+	assert.Equal(t, 4, stats[0].QueueSize)
+	assert.True(t, stats[0].Blocked > 0)
+	for _, s := range stats {
+		assert.Equal(t, uint64(len(blocks)), s.Processed)
+		assert.Equal(t, uint64(0), s.Failed)
+		assert.Equal(t, 0, s.QueueDepth)
+
+		count := uint64(0)
+		for _, bucket := range s.Latency {
+			count += bucket.Count
+		}
+		assert.Equal(t, s.Processed, count)
+	}
+	assert.Equal(t, 1, stats[3].Workers)
+	assert.Equal(t, 1, stats[4].Workers)
+}
+
+func TestBlockPool_Capacity(t *testing.T) {
+	// generate a chain of blocks on another chain, they are orphans in pool.
+	src := testNeb(t).chain
+	blocks := []*Block{}
+	for i := 0; i < 4; i++ {
+		addr, err := AddressParse(MockDynasty[i%len(MockDynasty)])
+		assert.Nil(t, err)
+		block, err := NewBlock(src.ChainID(), addr, src.tailBlock)
+		assert.Nil(t, err)
+		block.header.timestamp = src.tailBlock.header.timestamp + BlockInterval
+		assert.Nil(t, block.Seal())
+		signBlock(block)
+		assert.Nil(t, src.bkPool.Push(block))
+		blocks = append(blocks, block)
+	}
+	// another block at the height of blocks[2].
+	addr, err := AddressParse(MockDynasty[5])
+	assert.Nil(t, err)
+	fork, err := NewBlock(src.ChainID(), addr, blocks[1])
+	assert.Nil(t, err)
+	fork.header.timestamp = blocks[2].header.timestamp
+	assert.Nil(t, fork.Seal())
+	signBlock(fork)
+
+	bc := testNeb(t).chain
+	pool := bc.bkPool
+	pool.size = 2
+
+	assert.Equal(t, ErrMissingParentBlock, pool.Push(blocks[1]))
+	assert.Equal(t, ErrMissingParentBlock, pool.Push(blocks[2]))
+	assert.Equal(t, 2, pool.cache.Len())
+
+	// duplicated pushes are suppressed.
+	assert.Nil(t, pool.Push(blocks[2]))
+	assert.Equal(t, 2, pool.cache.Len())
+
+	// the lowest block is evicted for a higher one.
+	assert.Equal(t, ErrMissingParentBlock, pool.Push(blocks[3]))
+	assert.False(t, pool.cache.Contains(blocks[1].Hash().Hex()))
+	assert.True(t, pool.cache.Contains(blocks[3].Hash().Hex()))
+
+	// a lower block can't evict higher ones.
+	assert.Equal(t, ErrBlockEvictedOnArrival, pool.Push(blocks[1]))
+	// a block can't evict the ones at the same height.
+	assert.Equal(t, ErrBlockPoolFull, pool.Push(fork))
+	assert.Equal(t, 2, pool.cache.Len())
+
+	// the blocks waiting for parents too long are expired.
+	pool.SetOrphanTTL(1)
+	time.Sleep(time.Millisecond * 1100)
+	assert.Equal(t, ErrMissingParentBlock, pool.Push(blocks[1]))
+	assert.Equal(t, 1, pool.cache.Len())
+
+	stats := pool.Stats()
+	assert.Equal(t, 1, stats.Size)
+	assert.Equal(t, 2, stats.Capacity)
+	assert.Equal(t, uint64(2), stats.Evicted)
+	assert.Equal(t, uint64(2), stats.Expired)
+	assert.Equal(t, uint64(1), stats.Rejected)
+	assert.Equal(t, uint64(1), stats.Duplicated)
+
+	// the linkable blocks don't need room in pool.
+	assert.Nil(t, pool.Push(blocks[0]))
+	assert.Nil(t, pool.Push(blocks[2]))
+	assert.Nil(t, pool.Push(blocks[3]))
+	assert.Equal(t, 0, pool.cache.Len())
+}
+
+func TestBlockPool_Timestamp(t *testing.T) {
+	defer func(height uint64) { BlockTimestampForkHeight = height }(BlockTimestampForkHeight)
+	BlockTimestampForkHeight = 3
+
+	bc := testNeb(t).chain
+	coinbase := mockAddress()
+	signature := mockSignature(t, coinbase)
+	newBlock := func(parent *Block, timestamp int64) *Block {
+		block, err := bc.NewBlockFromParent(coinbase, parent)
+		assert.Nil(t, err)
+		block.header.timestamp = timestamp
+		assert.Nil(t, block.Seal())
+		assert.Nil(t, block.Sign(signature))
+		return block
+	}
+
+	// the blocks before fork height are not checked against parent and slots.
+	a1 := newBlock(bc.genesisBlock, bc.genesisBlock.Timestamp())
+	assert.Nil(t, bc.BlockPool().Push(a1))
+	b1 := newBlock(bc.genesisBlock, BlockIntervalInSecond+1)
+	assert.Nil(t, bc.BlockPool().Push(b1))
+
+	b2 := newBlock(b1, BlockIntervalInSecond*2+1)
+	assert.Equal(t, ErrInvalidBlockSlot, bc.BlockPool().Push(b2))
+	b2 = newBlock(b1, BlockIntervalInSecond*2)
+	assert.Nil(t, bc.BlockPool().Push(b2))
+
+	b3 := newBlock(b2, b2.Timestamp())
+	assert.Equal(t, ErrBlockTimestampBehindParent, bc.BlockPool().Push(b3))
+	b3 = newBlock(b2, b1.Timestamp()-1)
+	assert.Equal(t, ErrBlockTimestampBehindParent, bc.BlockPool().Push(b3))
+
+	// the future drift is configurable.
+	future := (time.Now().Unix()/BlockIntervalInSecond + 10) * BlockIntervalInSecond
+	b3 = newBlock(b2, future)
+	assert.Equal(t, ErrBlockTooFarInFuture, bc.BlockPool().Push(b3))
+	bc.BlockPool().SetTimestampMaxDrift(3600)
+	assert.Nil(t, bc.BlockPool().Push(b3))
+	assert.Equal(t, b3.Hash(), bc.TailBlock().Hash())
+
+	bc.BlockPool().SetTimestampMaxDrift(0)
+	assert.Equal(t, int64(DefaultBlockTimestampMaxDrift), bc.BlockPool().timestampMaxDrift)
+}
@@ -214,6 +214,9 @@ func (m mockManager) SignHash(addr *Address, hash byteutils.Hash, alg keystore.A
 func (m mockManager) SignBlock(addr *Address, block *Block) error                        { return nil }
 func (m mockManager) SignTransaction(*Address, *Transaction) error                       { return nil }
 func (m mockManager) SignTransactionWithPassphrase(*Address, *Transaction, []byte) error { return nil }
+func (m mockManager) SignTransactionAsPayerWithPassphrase(*Address, *Transaction, []byte) error {
+	return nil
+}
 
 func (m mockManager) Update(*Address, []byte, []byte) error   { return nil }
 func (m mockManager) Load([]byte, []byte) (*Address, error)   { return nil, nil }
@@ -254,6 +257,12 @@ func (n mockNetService) ClosePeer(peerID string, reason error) {}
 
 func (n mockNetService) BroadcastNetworkID([]byte) {}
 
+func (n mockNetService) PeerCapabilities(peerID string) (net.Capability, bool) {
+	return net.LocalCapabilities, true
+}
+
+func (n mockNetService) ReportPeer(peerID string, verdict net.Verdict) {}
+
 type mockNeb struct {
 	config    *nebletpb.Config
 	chain     *BlockChain
@@ -327,6 +336,9 @@ func (nvm *mockEngine) Dispose() {
 func (nvm *mockEngine) SetExecutionLimits(uint64, uint64) error {
 	return nil
 }
+func (nvm *mockEngine) SetRuntimeVersion(string) error {
+	return nil
+}
 func (nvm *mockEngine) DeployAndInit(source, sourceType, args string) (string, error) {
 	return "", nil
 }
@@ -337,7 +349,7 @@ func (nvm *mockEngine) ExecutionInstructions() uint64 {
 	return uint64(100)
 }
 
-func testNeb(t *testing.T) *mockNeb {
+func testNeb(t testing.TB) *mockNeb {
 	storage, err := storage.NewMemoryStorage()
 	assert.Nil(t, err)
 	eventEmitter := NewEventEmitter(1024)
@@ -444,8 +456,12 @@ func TestBlock(t *testing.T) {
 						1,
 						gasPrice,
 						gasLimit,
+						0,
 						keystore.SECP256K1,
 						nil,
+						nil,
+						nil,
+						0,
 					},
 					&Transaction{
 						[]byte("123455"),
@@ -458,8 +474,12 @@ func TestBlock(t *testing.T) {
 						2,
 						gasPrice,
 						gasLimit,
+						0,
 						keystore.SECP256K1,
 						nil,
+						nil,
+						nil,
+						0,
 					},
 				},
 				dag.NewDag(),
@@ -653,7 +673,66 @@ func TestBlockVerifyDupTx(t *testing.T) {
 	_, err = block.ExecuteTransaction(tx1, block.worldState)
 	assert.Nil(t, err)
 	_, err = block.ExecuteTransaction(tx1, block.worldState)
-	assert.Equal(t, err, ErrSmallTransactionNonce)
+	assert.Equal(t, ErrSmallTransactionNonce, ErrorCause(err))
+}
+
+func TestBlockDuplicatedTx(t *testing.T) {
+	neb := testNeb(t)
+	bc := neb.chain
+	ks := keystore.DefaultKS
+	from := mockAddress()
+	key, err := ks.GetUnlocked(from.String())
+	assert.Nil(t, err)
+	signature, err := crypto.NewSignature(keystore.SECP256K1)
+	assert.Nil(t, err)
+	signature.InitSign(key.(keystore.PrivateKey))
+	gasLimit, _ := util.NewUint128FromInt(200000)
+	tx1, _ := NewTransaction(bc.ChainID(), from, from, util.NewUint128(), 1, TxPayloadBinaryType, []byte("nas"), TransactionGasPrice, gasLimit)
+	tx1.Sign(signature)
+
+	// same tx twice in one block
+	block, err := bc.NewBlock(from)
+	assert.Nil(t, err)
+	block.transactions = append(block.transactions, tx1, tx1)
+	assert.Equal(t, ErrDuplicatedTransaction, block.execute())
+
+	// tx replayed on a state already recording it, e.g. after a reorg
+	block, err = bc.NewBlock(from)
+	assert.Nil(t, err)
+	_, err = block.ExecuteTransaction(tx1, block.worldState)
+	assert.Nil(t, err)
+	giveback, err := AcceptTransaction(tx1, block.worldState)
+	assert.False(t, giveback)
+	assert.Equal(t, ErrDuplicatedTransaction, ErrorCause(err))
+}
+
+func TestBlockBelowMinGasPriceTx(t *testing.T) {
+	neb := testNeb(t)
+	bc := neb.chain
+	ks := keystore.DefaultKS
+	from := mockAddress()
+	key, err := ks.GetUnlocked(from.String())
+	assert.Nil(t, err)
+	signature, err := crypto.NewSignature(keystore.SECP256K1)
+	assert.Nil(t, err)
+	signature.InitSign(key.(keystore.PrivateKey))
+	gasPrice, _ := util.NewUint128FromInt(1)
+	gasLimit, _ := util.NewUint128FromInt(200000)
+	tx1, _ := NewTransaction(bc.ChainID(), from, from, util.NewUint128(), 1, TxPayloadBinaryType, []byte("nas"), gasPrice, gasLimit)
+	tx1.Sign(signature)
+
+	// the min gasPrice is a local relay policy of the pool
+	assert.Equal(t, ErrGasPriceBelowMinimum, bc.txPool.Push(tx1))
+
+	// but the tx is still valid inside a block
+	block, err := bc.NewBlock(from)
+	assert.Nil(t, err)
+	_, err = block.ExecuteTransaction(tx1, block.worldState)
+	assert.Nil(t, err)
+	block.transactions = append(block.transactions, tx1)
+	block.Seal()
+	block.Sign(signature)
+	assert.Nil(t, block.VerifyIntegrity(bc.ChainID(), bc.ConsensusHandler()))
 }
 
 func TestBlockVerifyInvalidTx(t *testing.T) {
@@ -676,7 +755,7 @@ func TestBlockVerifyInvalidTx(t *testing.T) {
 	_, err = block.ExecuteTransaction(tx1, block.worldState)
 	assert.Nil(t, err)
 	_, err = block.ExecuteTransaction(tx2, block.worldState)
-	assert.Equal(t, err, ErrLargeTransactionNonce)
+	assert.Equal(t, ErrLargeTransactionNonce, ErrorCause(err))
 }
 
 func TestBlockVerifyState(t *testing.T) {
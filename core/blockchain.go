@@ -19,7 +19,11 @@
 package core
 
 import (
+	"encoding/json"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/alexlisong/go-nebulas/core/state"
@@ -46,9 +50,11 @@ type BlockChain struct {
 	chainID uint32
 
 	genesis *corepb.Genesis
+	config  *ChainConfig
 
 	genesisBlock *Block
 	tailBlock    *Block
+	mu           sync.RWMutex
 
 	bkPool *BlockPool
 	txPool *TransactionPool
@@ -73,6 +79,48 @@ type BlockChain struct {
 	superNode bool
 
 	unsupportedKeyword string
+
+	// last nonces seen by GetNonces, used by nonce invariant checks
+	observedNonces           *lru.Cache
+	observedNoncesMu         sync.Mutex
+	nonceInvariantViolations uint64
+
+	// count of blocks behind LIB whose states are retained, 0 if pruning is disabled.
+	stateRetention uint64
+	pruning        int32
+	pruneMu        sync.Mutex
+
+	// count of latest blocks to estimate gas price from.
+	gasPriceWindow uint32
+
+	// chain health metrics, see ChainStatus.
+	verifyLatency  *latencyHistogram
+	executeLatency *latencyHistogram
+	reorgs         uint64
+	lastReorgDepth uint64
+	maxReorgDepth  uint64
+	appliedBlocks  uint64
+	appliedTxs     uint64
+
+	// subscriptions of canonical chain events, see SubscribeEvents.
+	subscriptions   map[*ChainSubscription]bool
+	subscriptionsMu sync.Mutex
+}
+
+// ChainReorgEvent is the data of chain reorg event,
+// Reverted is ordered from old tail, Applied is ordered by height ascending.
+type ChainReorgEvent struct {
+	OldTail  string   `json:"old_tail"`
+	NewTail  string   `json:"new_tail"`
+	Ancestor string   `json:"ancestor"`
+	Reverted []string `json:"reverted"`
+	Applied  []string `json:"applied"`
+}
+
+// LIBEvent is the data of latest irreversible block event.
+type LIBEvent struct {
+	Hash   string `json:"hash"`
+	Height uint64 `json:"height"`
 }
 
 const (
@@ -90,6 +138,32 @@ const (
 
 	// LIB (latest irreversible block) in storage
 	LIB = "blockchain_lib"
+
+	// BlockKeyPrefix prefix of block keys in storage
+	BlockKeyPrefix = "blk_"
+
+	// HeightKeyPrefix prefix of canonical height index keys in storage
+	HeightKeyPrefix = "hgt_"
+
+	// TxIndexKeyPrefix prefix of canonical tx hash to block hash index keys in storage
+	TxIndexKeyPrefix = "txi_"
+
+	// EventTopicIndexKeyPrefix prefix of canonical events index keys by topic and height in storage
+	EventTopicIndexKeyPrefix = "evt_"
+
+	// EventContractIndexKeyPrefix prefix of canonical events index keys by contract and height in storage
+	EventContractIndexKeyPrefix = "evc_"
+
+	// DefaultGasPriceWindow count of latest canonical blocks to estimate gas price from
+	DefaultGasPriceWindow = 64
+
+	// DefaultGasPricePercentile percentile of the gas prices in window returned by GasPrice
+	DefaultGasPricePercentile = 50
+)
+
+var (
+	// NonceInvariantCheck enables the nonce invariant checks in GetNonces, debug only.
+	NonceInvariantCheck = false
 )
 
 // NewBlockChain create new #BlockChain instance.
@@ -118,10 +192,26 @@ func NewBlockChain(neb Neblet) (*BlockChain, error) {
 		}
 	}
 
-	blockPool, err := NewBlockPool(128)
+	var blockGasLimit *util.Uint128
+	if len(neb.Config().Chain.BlockGasLimit) > 0 {
+		blockGasLimit, err = util.NewUint128FromString(neb.Config().Chain.BlockGasLimit)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	blockPool, err := NewBlockPool(int(neb.Config().Chain.BlockPoolSize))
 	if err != nil {
 		return nil, err
 	}
+	blockPool.SetOrphanTTL(neb.Config().Chain.BlockPoolOrphanTtl)
+	blockPool.SetTimestampMaxDrift(neb.Config().Chain.BlockMaxTimestampDrift)
+	blockPool.SetPipelineConfig(
+		neb.Config().Chain.BlockPipelineQueueSize,
+		neb.Config().Chain.BlockPipelineDecodeWorkers,
+		neb.Config().Chain.BlockPipelineHeaderWorkers,
+		neb.Config().Chain.BlockPipelineSignatureWorkers,
+	)
 	blockPool.RegisterInNetwork(neb.NetService())
 
 	txPool, err := NewTransactionPool(327680)
@@ -132,6 +222,11 @@ func NewBlockChain(neb Neblet) (*BlockChain, error) {
 	if err := txPool.SetGasConfig(gasPrice, gasLimit); err != nil {
 		return nil, err
 	}
+	txPool.SetReplacePriceBump(neb.Config().Chain.TxReplacePriceBump)
+	txPool.SetTimestampMaxDrift(neb.Config().Chain.TxMaxTimestampDrift)
+	txPool.SetPackingLimit(blockGasLimit, neb.Config().Chain.BlockMaxTxs)
+	txPool.SetRebroadcast(neb.Config().Chain.TxRebroadcastBlocks, neb.Config().Chain.TxRebroadcastMaxRetries)
+	txPool.SetReserveQueued(neb.Config().Chain.TxReserveQueuedBalance)
 	txPool.RegisterInNetwork(neb.NetService())
 
 	var bc = &BlockChain{
@@ -145,7 +240,15 @@ func NewBlockChain(neb Neblet) (*BlockChain, error) {
 		quitCh:             make(chan int, 1),
 		superNode:          neb.Config().Chain.SuperNode,
 		unsupportedKeyword: neb.Config().Chain.UnsupportedKeyword,
+		verifyLatency:      newLatencyHistogram(),
+		executeLatency:     newLatencyHistogram(),
+		subscriptions:      make(map[*ChainSubscription]bool),
+	}
+
+	if neb.Config().Chain.StatePruning {
+		bc.EnableStatePruning(neb.Config().Chain.StateRetention)
 	}
+	bc.SetGasPriceWindow(neb.Config().Chain.GasPriceWindow)
 
 	bc.cachedBlocks, err = lru.New(128)
 	if err != nil {
@@ -157,9 +260,20 @@ func NewBlockChain(neb Neblet) (*BlockChain, error) {
 		return nil, err
 	}
 
+	bc.observedNonces, err = lru.New(40960)
+	if err != nil {
+		return nil, err
+	}
+
 	bc.bkPool.setBlockChain(bc)
 	bc.txPool.setBlockChain(bc)
 
+	if neb.Config().Chain.TxJournal {
+		if err := bc.txPool.EnableJournal(neb.Config().Chain.TxJournalSize, neb.Config().Chain.TxJournalRate); err != nil {
+			return nil, err
+		}
+	}
+
 	return bc, nil
 }
 
@@ -167,11 +281,21 @@ func NewBlockChain(neb Neblet) (*BlockChain, error) {
 func (bc *BlockChain) Setup(neb Neblet) error {
 	bc.consensusHandler = neb.Consensus()
 
+	if err := MigrateStorage(bc.storage); err != nil {
+		return err
+	}
+
 	if err := bc.CheckGenesisConfig(neb); err != nil {
 		return err
 	}
 
 	var err error
+	bc.config, err = NewChainConfig(neb.Genesis())
+	if err != nil {
+		return err
+	}
+	RegisterChainConfig(bc.config)
+
 	bc.genesisBlock, err = bc.LoadGenesisFromStorage()
 	if err != nil {
 		return err
@@ -219,6 +343,7 @@ func (bc *BlockChain) loop() {
 			return
 		case <-timerChan:
 			bc.ConsensusHandler().UpdateLIB()
+			bc.CleanDetachedTails()
 		}
 	}
 }
@@ -251,6 +376,7 @@ func (bc *BlockChain) CheckGenesisConfig(neb Neblet) error {
 		"meta.chainid":           neb.Genesis().Meta.ChainId,
 		"consensus.dpos.dynasty": neb.Genesis().Consensus.Dpos.Dynasty,
 		"token.distribution":     neb.Genesis().TokenDistribution,
+		"chain.config":           neb.Genesis().ChainConfig,
 	}).Info("Genesis Configuration.")
 	return nil
 }
@@ -260,6 +386,11 @@ func (bc *BlockChain) ChainID() uint32 {
 	return bc.chainID
 }
 
+// ChainConfig return the chain config defined in genesis.
+func (bc *BlockChain) ChainConfig() *ChainConfig {
+	return bc.config
+}
+
 // Storage return the storage.
 func (bc *BlockChain) Storage() storage.Storage {
 	return bc.storage
@@ -272,17 +403,94 @@ func (bc *BlockChain) GenesisBlock() *Block {
 
 // TailBlock return the tail block.
 func (bc *BlockChain) TailBlock() *Block {
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
 	return bc.tailBlock
 }
 
+// TailSnapshot is a consistent view of the tail block and a clone of its world state,
+// it's not changed by the following SetTailBlock. It's not thread safe.
+type TailSnapshot struct {
+	block      *Block
+	worldState state.WorldState
+}
+
+// Block return the tail block of snapshot.
+func (s *TailSnapshot) Block() *Block {
+	return s.block
+}
+
+// WorldState return the cloned world state of the snapshot's tail block.
+func (s *TailSnapshot) WorldState() state.WorldState {
+	return s.worldState
+}
+
+// TailSnapshot return a snapshot of the tail block and its world state.
+func (bc *BlockChain) TailSnapshot() (*TailSnapshot, error) {
+	tail := bc.TailBlock()
+	if tail == nil {
+		return nil, ErrNilArgument
+	}
+	worldState, err := tail.WorldState().Clone()
+	if err != nil {
+		return nil, err
+	}
+	return &TailSnapshot{
+		block:      tail,
+		worldState: worldState,
+	}, nil
+}
+
+// newBlock create a block on the snapshot's tail, executing on the snapshot's world state.
+func (s *TailSnapshot) newBlock(chainID uint32, coinbase *Address) (*Block, error) {
+	return newBlockWithWorldState(chainID, coinbase, s.block, s.worldState)
+}
+
 // LIB return the latest irrversible block
 func (bc *BlockChain) LIB() *Block {
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
 	return bc.lib
 }
 
-// SetLIB update the latest irrversible block
-func (bc *BlockChain) SetLIB(lib *Block) {
+// LatestIrreversibleBlock return the latest irrversible block, the blocks below it are finalized.
+func (bc *BlockChain) LatestIrreversibleBlock() *Block {
+	return bc.LIB()
+}
+
+// SetLIB update the latest irrversible block, store it and notify it by TopicLibBlock event.
+func (bc *BlockChain) SetLIB(lib *Block) error {
+	if lib == nil {
+		return ErrNilArgument
+	}
+	if err := bc.StoreLIBHashToStorage(lib); err != nil {
+		return err
+	}
+	bc.mu.Lock()
 	bc.lib = lib
+	bc.mu.Unlock()
+
+	bc.triggerStatePruning()
+	bc.triggerLIBEvent(lib)
+	return nil
+}
+
+func (bc *BlockChain) triggerLIBEvent(lib *Block) {
+	data, err := json.Marshal(&LIBEvent{
+		Hash:   lib.Hash().String(),
+		Height: lib.Height(),
+	})
+	if err != nil {
+		logging.VLog().WithFields(logrus.Fields{
+			"lib": lib,
+			"err": err,
+		}).Debug("Failed to marshal lib event.")
+		return
+	}
+	bc.eventEmitter.Trigger(&state.Event{
+		Topic: TopicLibBlock,
+		Data:  string(data),
+	})
 }
 
 // EventEmitter return the eventEmitter.
@@ -299,30 +507,68 @@ func (bc *BlockChain) triggerRevertBlockEvent(blocks []string) {
 	}
 }
 
-func (bc *BlockChain) revertBlocks(from *Block, to *Block) error {
+// revertBlocks returns the reverted blocks in (from, to], to first.
+// The txs in reverted blocks are given back by tx pool on the chain reorg event.
+func (bc *BlockChain) revertBlocks(from *Block, to *Block) ([]*Block, error) {
 	reverted := to
 	var revertTimes int64
 	blocks := []string{}
+	revertedBlocks := []*Block{}
 	for revertTimes = 0; !reverted.Hash().Equals(from.Hash()); {
-		if reverted.Hash().Equals(bc.lib.Hash()) {
-			return ErrCannotRevertLIB
+		if reverted.Hash().Equals(bc.LIB().Hash()) {
+			return nil, ErrCannotRevertLIB
 		}
 
-		reverted.ReturnTransactions()
+		if err := bc.deleteTxIndex(reverted); err != nil {
+			return nil, err
+		}
+		if err := bc.deleteEventIndex(reverted); err != nil {
+			return nil, err
+		}
 		logging.VLog().WithFields(logrus.Fields{
 			"block": reverted,
 		}).Warn("A block is reverted.")
 		revertTimes++
 		blocks = append(blocks, reverted.String())
+		revertedBlocks = append(revertedBlocks, reverted)
 
 		reverted = bc.GetBlock(reverted.header.parentHash)
 		if reverted == nil {
-			return ErrMissingParentBlock
+			return nil, ErrMissingParentBlock
 		}
 	}
 	go bc.triggerRevertBlockEvent(blocks)
 	// record count of reverted blocks
-	return nil
+	return revertedBlocks, nil
+}
+
+func (bc *BlockChain) triggerChainReorgEvent(oldTail, newTail, ancestor *Block, reverted, applied []*Block) {
+	reorg := &ChainReorgEvent{
+		OldTail:  oldTail.Hash().String(),
+		NewTail:  newTail.Hash().String(),
+		Ancestor: ancestor.Hash().String(),
+		Reverted: make([]string, len(reverted)),
+		Applied:  make([]string, len(applied)),
+	}
+	for i, block := range reverted {
+		reorg.Reverted[i] = block.Hash().String()
+	}
+	// applied blocks are collected from new tail, reorder them by height ascending.
+	for i, block := range applied {
+		reorg.Applied[len(applied)-1-i] = block.Hash().String()
+	}
+	data, err := json.Marshal(reorg)
+	if err != nil {
+		logging.VLog().WithFields(logrus.Fields{
+			"reorg": reorg,
+			"err":   err,
+		}).Debug("Failed to marshal chain reorg event.")
+		return
+	}
+	bc.eventEmitter.Trigger(&state.Event{
+		Topic: TopicChainReorg,
+		Data:  string(data),
+	})
 }
 
 func (bc *BlockChain) dropTxsInBlockFromTxPool(block *Block) {
@@ -331,6 +577,13 @@ func (bc *BlockChain) dropTxsInBlockFromTxPool(block *Block) {
 	}
 }
 
+func (bc *BlockChain) dropTxsInBlocksFromTxPool(from *Block, to *Block) {
+	for to != nil && !to.Hash().Equals(from.Hash()) {
+		bc.dropTxsInBlockFromTxPool(to)
+		to = bc.GetBlock(to.header.parentHash)
+	}
+}
+
 func (bc *BlockChain) triggerNewTailEvent(blocks []*Block) {
 	for i := len(blocks) - 1; i >= 0; i-- {
 		block := blocks[i]
@@ -340,7 +593,7 @@ func (bc *BlockChain) triggerNewTailEvent(blocks []*Block) {
 		})
 
 		for _, v := range block.transactions {
-			events, err := block.FetchEvents(v.hash)
+			events, err := block.FetchEventsWithExecutionResult(v.hash)
 			if err == nil {
 				for _, e := range events {
 					bc.eventEmitter.Trigger(e)
@@ -350,22 +603,28 @@ func (bc *BlockChain) triggerNewTailEvent(blocks []*Block) {
 	}
 }
 
-func (bc *BlockChain) buildIndexByBlockHeight(from *Block, to *Block) error {
+// buildIndexByBlockHeight returns the indexed blocks in (from, to], to first.
+func (bc *BlockChain) buildIndexByBlockHeight(from *Block, to *Block) ([]*Block, error) {
 	blocks := []*Block{}
 	for !to.Hash().Equals(from.Hash()) {
-		err := bc.storage.Put(byteutils.FromUint64(to.height), to.Hash())
+		err := bc.storage.Put(heightStorageKey(to.height), to.Hash())
 		if err != nil {
-			return err
+			return nil, err
+		}
+		if err := bc.putTxIndex(to); err != nil {
+			return nil, err
+		}
+		if err := bc.putEventIndex(to); err != nil {
+			return nil, err
 		}
 		blocks = append(blocks, to)
-		go bc.dropTxsInBlockFromTxPool(to)
 		to = bc.GetBlock(to.header.parentHash)
 		if to == nil {
-			return ErrMissingParentBlock
+			return nil, ErrMissingParentBlock
 		}
 	}
 	go bc.triggerNewTailEvent(blocks)
-	return nil
+	return blocks, nil
 }
 
 // SetTailBlock set tail block.
@@ -373,7 +632,7 @@ func (bc *BlockChain) SetTailBlock(newTail *Block) error {
 	if newTail == nil {
 		return ErrNilArgument
 	}
-	oldTail := bc.tailBlock
+	oldTail := bc.TailBlock()
 	ancestor, err := bc.FindCommonAncestorWithTail(newTail)
 	if err != nil {
 		logging.VLog().WithFields(logrus.Fields{
@@ -383,7 +642,8 @@ func (bc *BlockChain) SetTailBlock(newTail *Block) error {
 		return err
 	}
 
-	if err := bc.revertBlocks(ancestor, oldTail); err != nil {
+	reverted, err := bc.revertBlocks(ancestor, oldTail)
+	if err != nil {
 		logging.VLog().WithFields(logrus.Fields{
 			"from":  ancestor,
 			"to":    oldTail,
@@ -393,7 +653,8 @@ func (bc *BlockChain) SetTailBlock(newTail *Block) error {
 	}
 
 	// build index by block height
-	if err := bc.buildIndexByBlockHeight(ancestor, newTail); err != nil {
+	applied, err := bc.buildIndexByBlockHeight(ancestor, newTail)
+	if err != nil {
 		logging.VLog().WithFields(logrus.Fields{
 			"from":  ancestor,
 			"to":    newTail,
@@ -406,7 +667,30 @@ func (bc *BlockChain) SetTailBlock(newTail *Block) error {
 	if err := bc.StoreTailHashToStorage(newTail); err != nil { // Refine: rename, delete ToStorage
 		return err
 	}
+	// the subscribers see the new tail and its events atomically.
+	bc.subscriptionsMu.Lock()
+	bc.mu.Lock()
 	bc.tailBlock = newTail
+	bc.mu.Unlock()
+	bc.publishChainEvents(reverted, applied)
+	bc.subscriptionsMu.Unlock()
+
+	// drop the height index of the losing fork above the new tail.
+	for height := newTail.height + 1; height <= oldTail.height; height++ {
+		if err := bc.storage.Del(heightStorageKey(height)); err != nil {
+			return err
+		}
+	}
+
+	bc.recordTailUpdate(reverted, applied)
+
+	// drop txs on chain from tx pool after the new tail is visible,
+	// otherwise the txs could be missing in both pool and chain.
+	go bc.dropTxsInBlocksFromTxPool(ancestor, newTail)
+
+	if len(reverted) > 0 {
+		go bc.triggerChainReorgEvent(oldTail, newTail, ancestor, reverted, applied)
+	}
 
 	logging.CLog().WithFields(logrus.Fields{
 		"tail": newTail,
@@ -418,11 +702,11 @@ func (bc *BlockChain) SetTailBlock(newTail *Block) error {
 // GetBlockOnCanonicalChainByHeight return block in given height
 func (bc *BlockChain) GetBlockOnCanonicalChainByHeight(height uint64) *Block {
 
-	if height > bc.tailBlock.height {
+	if height > bc.TailBlock().height {
 		return nil
 	}
 
-	blockHash, err := bc.storage.Get(byteutils.FromUint64(height))
+	blockHash, err := bc.storage.Get(heightStorageKey(height))
 	if err != nil {
 		return nil
 	}
@@ -435,30 +719,33 @@ func (bc *BlockChain) GetBlockOnCanonicalChainByHash(blockHash byteutils.Hash) *
 	if blockByHash == nil {
 		logging.VLog().WithFields(logrus.Fields{
 			"hash": blockHash.Hex(),
-			"tail": bc.tailBlock,
+			"tail": bc.TailBlock(),
 			"err":  "cannot find block with the given hash in local storage",
 		}).Debug("Failed to check a block on canonical chain.")
 		return nil
 	}
-	blockByHeight := bc.GetBlockOnCanonicalChainByHeight(blockByHash.height)
-	if blockByHeight == nil {
+	if blockByHash.height > bc.TailBlock().height {
+		return nil
+	}
+	hashByHeight, err := bc.storage.Get(heightStorageKey(blockByHash.height))
+	if err != nil {
 		logging.VLog().WithFields(logrus.Fields{
-			"height": blockByHeight.Height(),
-			"tail":   bc.tailBlock,
+			"height": blockByHash.height,
+			"tail":   bc.TailBlock(),
 			"err":    "cannot find block with the given height in local storage",
 		}).Debug("Failed to check a block on canonical chain.")
 		return nil
 	}
-	if !blockByHeight.Hash().Equals(blockByHash.Hash()) {
+	if !blockByHash.Hash().Equals(hashByHeight) {
 		logging.VLog().WithFields(logrus.Fields{
-			"blockByHash":   blockByHash,
-			"blockByHeight": blockByHeight,
-			"tail":          bc.tailBlock,
-			"err":           "block with the given hash isn't on canonical chain",
+			"blockByHash":  blockByHash,
+			"hashByHeight": byteutils.Hash(hashByHeight),
+			"tail":         bc.TailBlock(),
+			"err":          "block with the given hash isn't on canonical chain",
 		}).Debug("Failed to check a block on canonical chain.")
 		return nil
 	}
-	return blockByHeight
+	return blockByHash
 }
 
 // FindCommonAncestorWithTail return the block's common ancestor with current tail
@@ -474,30 +761,32 @@ func (bc *BlockChain) FindCommonAncestorWithTail(block *Block) (*Block, error) {
 		return nil, ErrMissingParentBlock
 	}
 
-	tail := bc.TailBlock()
-	if tail.Height() > target.Height() {
-		tail = bc.GetBlockOnCanonicalChainByHeight(target.Height())
-		if tail == nil {
-			return nil, ErrMissingParentBlock
-		}
-	}
-
-	for tail.Height() < target.Height() {
+	tailHeight := bc.TailBlock().Height()
+	for target.Height() > tailHeight {
 		target = bc.GetBlock(target.header.parentHash)
 		if target == nil {
 			return nil, ErrMissingParentBlock
 		}
 	}
 
-	for !tail.Hash().Equals(target.Hash()) {
-		tail = bc.GetBlock(tail.header.parentHash)
+	// the first ancestor of target on canonical chain is the common ancestor,
+	// it's checked by the height index, the canonical chain is never walked.
+	for {
+		hash, err := bc.storage.Get(heightStorageKey(target.height))
+		if err != nil {
+			if err == storage.ErrKeyNotFound {
+				return nil, ErrMissingParentBlock
+			}
+			return nil, err
+		}
+		if target.Hash().Equals(hash) {
+			return target, nil
+		}
 		target = bc.GetBlock(target.header.parentHash)
-		if tail == nil || target == nil {
+		if target == nil {
 			return nil, ErrMissingParentBlock
 		}
 	}
-
-	return target, nil
 }
 
 // BlockPool return block pool.
@@ -543,7 +832,7 @@ func (bc *BlockChain) NewBlock(coinbase *Address) (*Block, error) {
 	if coinbase == nil {
 		return nil, ErrInvalidArgument
 	}
-	return bc.NewBlockFromParent(coinbase, bc.tailBlock)
+	return bc.NewBlockFromParent(coinbase, bc.TailBlock())
 }
 
 // NewBlockFromParent create new block from parent block and return it.
@@ -610,53 +899,225 @@ func (bc *BlockChain) GetBlock(hash byteutils.Hash) *Block {
 
 // GetTransaction return transaction of given hash from local storage.
 func (bc *BlockChain) GetTransaction(hash byteutils.Hash) (*Transaction, error) {
-	worldState, err := bc.TailBlock().WorldState().Clone()
+	snapshot, err := bc.TailSnapshot()
 	if err != nil {
 		return nil, err
 	}
-	tx, err := GetTransaction(hash, worldState)
+	tx, err := GetTransaction(hash, snapshot.WorldState())
 	if err != nil {
 		return nil, err
 	}
 	return tx, nil
 }
 
-// GasPrice returns the lowest transaction gas price.
-func (bc *BlockChain) GasPrice() *util.Uint128 {
-	gasPrice := TransactionMaxGasPrice
-	tailBlock := bc.TailBlock()
-	// search latest block who has transactions, try 128 times at most
-	for i := 0; i < 128; i++ {
-		// if the block is genesis, stop find the parent block
-		if CheckGenesisBlock(tailBlock) {
-			break
+// GetTransactionByHash return the tx on canonical chain and the block including it.
+func (bc *BlockChain) GetTransactionByHash(hash byteutils.Hash) (*Transaction, *Block, error) {
+	blockHash, err := bc.storage.Get(txIndexStorageKey(hash))
+	if err != nil {
+		return nil, nil, err
+	}
+	block := bc.GetBlock(blockHash)
+	if block == nil {
+		return nil, nil, ErrInvalidTxIndex
+	}
+	for _, tx := range block.transactions {
+		if tx.hash.Equals(hash) {
+			return tx, block, nil
 		}
+	}
+	return nil, nil, ErrInvalidTxIndex
+}
 
-		if len(tailBlock.transactions) > 0 {
-			break
+// putTxIndex index the txs in block, which is linked into canonical chain.
+func (bc *BlockChain) putTxIndex(block *Block) error {
+	for _, tx := range block.transactions {
+		if err := bc.storage.Put(txIndexStorageKey(tx.hash), block.Hash()); err != nil {
+			return err
 		}
-		tailBlock = bc.GetBlock(tailBlock.ParentHash())
 	}
+	return nil
+}
+
+// deleteTxIndex remove the index of txs in block, which is reverted from canonical chain.
+func (bc *BlockChain) deleteTxIndex(block *Block) error {
+	for _, tx := range block.transactions {
+		if err := bc.storage.Del(txIndexStorageKey(tx.hash)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetTransactionReceipt return the receipt of the tx on canonical chain.
+func (bc *BlockChain) GetTransactionReceipt(hash byteutils.Hash) (*TransactionReceipt, error) {
+	return bc.TailBlock().GetTransactionReceipt(hash)
+}
+
+type observedNonce struct {
+	height     uint64
+	chainNonce uint64
+	poolNonce  uint64
+	pending    bool
+}
+
+// GetNonces return the nonces of given accounts, all resolved against a single snapshot of tail.
+// If includePending is true, the continuous pending txs in tx pool are overlaid on the chain nonces.
+func (bc *BlockChain) GetNonces(addresses []string, includePending bool) ([]uint64, error) {
+	addrs := make([]*Address, len(addresses))
+	for idx, v := range addresses {
+		addr, err := AddressParse(v)
+		if err != nil {
+			return nil, err
+		}
+		addrs[idx] = addr
+	}
+
+	// txs can't be dropped from pool when we are reading both views.
+	if includePending {
+		bc.txPool.mu.RLock()
+		defer bc.txPool.mu.RUnlock()
+	}
+
+	tail := bc.TailBlock()
+	worldState, err := tail.WorldState().Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	nonces := make([]uint64, len(addrs))
+	for idx, addr := range addrs {
+		_, chainNonce, err := getUserAccountState(worldState, addr.Bytes())
+		if err != nil {
+			return nil, err
+		}
+		nonce := chainNonce
+		if includePending {
+			nonce = bc.txPool.getPendingNonce(addr, chainNonce)
+		}
+		if NonceInvariantCheck {
+			bc.checkNonceInvariant(tail, addr, chainNonce, nonce, includePending)
+		}
+		nonces[idx] = nonce
+	}
+	return nonces, nil
+}
 
-	if len(tailBlock.transactions) > 0 {
-		for _, tx := range tailBlock.transactions {
-			if tx.gasPrice.Cmp(gasPrice) < 0 {
-				gasPrice = tx.gasPrice
+// checkNonceInvariant asserts that the pool-visible nonce is never less than the chain nonce,
+// and neither of them decreases across consecutive snapshots.
+func (bc *BlockChain) checkNonceInvariant(tail *Block, addr *Address, chainNonce, poolNonce uint64, pending bool) {
+	if poolNonce < chainNonce {
+		bc.reportNonceInvariantViolation(tail, addr, chainNonce, poolNonce, nil, "pool nonce is less than chain nonce")
+	}
+
+	bc.observedNoncesMu.Lock()
+	defer bc.observedNoncesMu.Unlock()
+
+	current := &observedNonce{
+		height:     tail.Height(),
+		chainNonce: chainNonce,
+		poolNonce:  poolNonce,
+		pending:    pending,
+	}
+	key := addr.String()
+	if v, ok := bc.observedNonces.Get(key); ok {
+		last := v.(*observedNonce)
+		// snapshots taken concurrently may be checked out of order, only compare with older ones.
+		if last.height > current.height {
+			return
+		}
+		if current.chainNonce < last.chainNonce {
+			bc.reportNonceInvariantViolation(tail, addr, chainNonce, poolNonce, last, "chain nonce decreased")
+		}
+		if current.pending && last.pending && current.poolNonce < last.poolNonce {
+			bc.reportNonceInvariantViolation(tail, addr, chainNonce, poolNonce, last, "pool nonce decreased")
+		}
+	}
+	bc.observedNonces.Add(key, current)
+}
+
+func (bc *BlockChain) reportNonceInvariantViolation(tail *Block, addr *Address, chainNonce, poolNonce uint64, last *observedNonce, reason string) {
+	atomic.AddUint64(&bc.nonceInvariantViolations, 1)
+
+	fields := logrus.Fields{
+		"address":    addr.String(),
+		"chainNonce": chainNonce,
+		"poolNonce":  poolNonce,
+		"tail":       tail,
+		"reason":     reason,
+	}
+	if last != nil {
+		fields["last.height"] = last.height
+		fields["last.chainNonce"] = last.chainNonce
+		fields["last.poolNonce"] = last.poolNonce
+	}
+	logging.VLog().WithFields(fields).Error("Nonce invariant violated.")
+}
+
+// NonceInvariantViolations return the count of nonce invariant violations found by GetNonces.
+func (bc *BlockChain) NonceInvariantViolations() uint64 {
+	return atomic.LoadUint64(&bc.nonceInvariantViolations)
+}
+
+// SetGasPriceWindow config the count of latest canonical blocks to estimate gas price from, 0 for default.
+func (bc *BlockChain) SetGasPriceWindow(window uint32) {
+	if window == 0 {
+		window = DefaultGasPriceWindow
+	}
+	atomic.StoreUint32(&bc.gasPriceWindow, window)
+}
+
+// GasPrice returns the median of the lowest gas prices of the latest blocks.
+func (bc *BlockChain) GasPrice() *util.Uint128 {
+	return bc.GasPriceWithPercentile(DefaultGasPricePercentile)
+}
+
+// GasPriceWithPercentile returns the percentile p in [0, 100] of the lowest gas prices of the latest blocks,
+// a higher one gets the tx packed faster. If no transactions in the window, use the default gasPrice.
+func (bc *BlockChain) GasPriceWithPercentile(p int) *util.Uint128 {
+	if p < 0 {
+		p = 0
+	}
+	if p > 100 {
+		p = 100
+	}
+
+	// a single cheap transaction only drags the lowest price of its own block.
+	prices := []*util.Uint128{}
+	window := atomic.LoadUint32(&bc.gasPriceWindow)
+	block := bc.TailBlock()
+	for i := uint32(0); i < window && block != nil; i++ {
+		// if the block is genesis, stop find the parent block
+		if CheckGenesisBlock(block) {
+			break
+		}
+
+		var lowest *util.Uint128
+		for _, tx := range block.transactions {
+			if lowest == nil || tx.gasPrice.Cmp(lowest) < 0 {
+				lowest = tx.gasPrice
 			}
 		}
-	} else {
-		// if no transactions have been submitted, use the default gasPrice
-		gasPrice = TransactionGasPrice
+		if lowest != nil {
+			prices = append(prices, lowest)
+		}
+		block = bc.GetBlock(block.ParentHash())
 	}
 
-	return gasPrice
+	if len(prices) == 0 {
+		return TransactionGasPrice
+	}
+	sort.Slice(prices, func(i, j int) bool {
+		return prices[i].Cmp(prices[j]) < 0
+	})
+	return prices[(len(prices)-1)*p/100]
 }
 
 // SimulateResult the result of simulating transaction execution
 type SimulateResult struct {
-	GasUsed *util.Uint128
-	Msg     string
-	Err     error
+	GasUsed  *util.Uint128
+	Msg      string
+	Err      error
+	GasTrace *GasTrace
 }
 
 // SimulateTransactionExecution execute transaction in sandbox and rollback all changes, used to EstimateGas and Call api.
@@ -665,8 +1126,12 @@ func (bc *BlockChain) SimulateTransactionExecution(tx *Transaction) (*SimulateRe
 		return nil, ErrInvalidArgument
 	}
 
-	// create block.
-	block, err := bc.NewBlock(GenesisCoinbase)
+	// create block on a snapshot of tail, which isn't changed by a concurrent SetTailBlock.
+	snapshot, err := bc.TailSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	block, err := snapshot.newBlock(bc.chainID, GenesisCoinbase)
 	if err != nil {
 		return nil, err
 	}
@@ -676,10 +1141,157 @@ func (bc *BlockChain) SimulateTransactionExecution(tx *Transaction) (*SimulateRe
 	return tx.simulateExecution(block)
 }
 
+// EstimateGas return the minimum gas limit with which tx executes successfully, and the simulated result under it.
+func (bc *BlockChain) EstimateGas(tx *Transaction) (*util.Uint128, *SimulateResult, error) {
+	if tx == nil {
+		return nil, nil, ErrInvalidArgument
+	}
+
+	// create block on a snapshot of tail, which isn't changed by a concurrent SetTailBlock.
+	snapshot, err := bc.TailSnapshot()
+	if err != nil {
+		return nil, nil, err
+	}
+	block, err := snapshot.newBlock(bc.chainID, GenesisCoinbase)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer block.RollBack()
+
+	return tx.estimateGas(block)
+}
+
+// SafeSendOptions is the checks on the simulation before sending a tx.
+type SafeSendOptions struct {
+	// AllowedErrors are the execution errors tolerated by the simulation.
+	AllowedErrors []string
+	// MaxFee is the max estimated fee, no cap if nil.
+	MaxFee *util.Uint128
+	// FillGasLimit replaces tx's gas limit with the estimated one.
+	FillGasLimit bool
+}
+
+// SafeSendSummary is the simulation summary of a tx sent safely.
+type SafeSendSummary struct {
+	Tx           *Transaction
+	GasLimit     *util.Uint128
+	GasUsed      *util.Uint128
+	BaseGas      *util.Uint128
+	PayloadGas   *util.Uint128
+	ExecutionGas *util.Uint128
+	Fee          *util.Uint128
+	Result       string
+	Err          error
+}
+
+// SendTransactionSafe simulates tx against tail block, and only signs & submits it if the simulation succeeds,
+// return ErrSimulationFailed or ErrEstimatedFeeExceedsMax with the summary if the tx is aborted.
+func (bc *BlockChain) SendTransactionSafe(tx *Transaction, opts *SafeSendOptions, sign func(*Transaction) error) (*SafeSendSummary, error) {
+	if tx == nil || opts == nil || sign == nil {
+		return nil, ErrInvalidArgument
+	}
+
+	gasLimit, result, err := bc.EstimateGas(tx)
+	if err != nil {
+		return nil, err
+	}
+	summary, err := newSafeSendSummary(tx, gasLimit, result)
+	if err != nil {
+		return nil, err
+	}
+
+	// the tx would run out of its own gas limit on chain.
+	if summary.Err == nil && !opts.FillGasLimit && tx.gasLimit.Cmp(gasLimit) < 0 {
+		summary.Err = ErrOutOfGasLimit
+	}
+	if summary.Err != nil {
+		allowed := false
+		for _, e := range opts.AllowedErrors {
+			if e == summary.Err.Error() {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return summary, ErrSimulationFailed
+		}
+	}
+	if opts.MaxFee != nil && summary.Fee.Cmp(opts.MaxFee) > 0 {
+		return summary, ErrEstimatedFeeExceedsMax
+	}
+
+	if opts.FillGasLimit {
+		payer := tx.payer
+		tx, err = NewTransactionWithExpiration(tx.chainID, tx.from, tx.to, tx.value, tx.nonce, tx.Type(), tx.Data(), tx.gasPrice, gasLimit, tx.expiredAt)
+		if err != nil {
+			return summary, err
+		}
+		tx.SetPayer(payer)
+		summary.Tx = tx
+	}
+
+	if err := sign(tx); err != nil {
+		return summary, err
+	}
+	if err := bc.txPool.PushAndBroadcast(tx); err != nil {
+		return summary, err
+	}
+
+	logging.VLog().WithFields(logrus.Fields{
+		"tx":      tx,
+		"gasUsed": summary.GasUsed,
+		"fee":     summary.Fee,
+	}).Debug("Sent transaction after simulation.")
+	return summary, nil
+}
+
+// newSafeSendSummary splits the simulated gas into base, payload base and execution.
+func newSafeSendSummary(tx *Transaction, gasLimit *util.Uint128, result *SimulateResult) (*SafeSendSummary, error) {
+	summary := &SafeSendSummary{
+		Tx:           tx,
+		GasLimit:     gasLimit,
+		GasUsed:      result.GasUsed,
+		BaseGas:      util.NewUint128(),
+		PayloadGas:   util.NewUint128(),
+		ExecutionGas: util.NewUint128(),
+		Result:       result.Msg,
+		Err:          result.Err,
+	}
+
+	fee, err := tx.gasPrice.Mul(result.GasUsed)
+	if err != nil {
+		return nil, err
+	}
+	summary.Fee = fee
+
+	if result.GasTrace != nil {
+		summary.BaseGas = result.GasTrace.BaseGas
+		summary.PayloadGas = result.GasTrace.PayloadBaseGas
+		summary.ExecutionGas = result.GasTrace.ExecutionGas
+		return summary, nil
+	}
+
+	baseGas, err := tx.GasCountOfTxBase()
+	if err != nil || baseGas.Cmp(result.GasUsed) > 0 {
+		return summary, nil
+	}
+	summary.BaseGas = baseGas
+	left, _ := result.GasUsed.Sub(baseGas)
+
+	payload, err := tx.LoadPayload()
+	if err != nil || payload.BaseGasCount().Cmp(left) > 0 {
+		summary.ExecutionGas = left
+		return summary, nil
+	}
+	summary.PayloadGas = payload.BaseGasCount()
+	summary.ExecutionGas, _ = left.Sub(summary.PayloadGas)
+	return summary, nil
+}
+
 // Dump dump full chain.
 func (bc *BlockChain) Dump(count int) string {
 	rl := []string{}
-	block := bc.tailBlock
+	block := bc.TailBlock()
 	rl = append(rl, block.String())
 	for i := 1; i < count; i++ {
 		if !CheckGenesisBlock(block) {
@@ -702,13 +1314,25 @@ func (bc *BlockChain) StoreBlockToStorage(block *Block) error {
 	if err != nil {
 		return err
 	}
-	err = bc.storage.Put(block.Hash(), value)
+	err = bc.storage.Put(blockStorageKey(block.Hash()), value)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
+func blockStorageKey(hash byteutils.Hash) []byte {
+	return append([]byte(BlockKeyPrefix), hash...)
+}
+
+func heightStorageKey(height uint64) []byte {
+	return append([]byte(HeightKeyPrefix), byteutils.FromUint64(height)...)
+}
+
+func txIndexStorageKey(hash byteutils.Hash) []byte {
+	return append([]byte(TxIndexKeyPrefix), hash...)
+}
+
 // StoreTailHashToStorage store tail block hash
 func (bc *BlockChain) StoreTailHashToStorage(block *Block) error { // ToRefine, update func to StoreTailHashToStorage
 	return bc.storage.Put([]byte(Tail), block.Hash())
@@ -755,10 +1379,13 @@ func (bc *BlockChain) LoadGenesisFromStorage() (*Block, error) { // ToRefine, re
 		if err := bc.StoreBlockToStorage(genesis); err != nil {
 			return nil, err
 		}
-		heightKey := byteutils.FromUint64(genesis.height)
+		heightKey := heightStorageKey(genesis.height)
 		if err := bc.storage.Put(heightKey, genesis.Hash()); err != nil {
 			return nil, err
 		}
+		if err := bc.putTxIndex(genesis); err != nil {
+			return nil, err
+		}
 	}
 	return genesis, nil
 }
@@ -0,0 +1,167 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"time"
+
+	"github.com/alexlisong/go-nebulas/core/pb"
+	"github.com/alexlisong/go-nebulas/util/logging"
+	"github.com/gogo/protobuf/proto"
+	"github.com/sirupsen/logrus"
+)
+
+// Chain file is the magic and chain id, followed by blocks, each block is
+// its length in 4 bytes big endian and the marshaled corepb.Block.
+const (
+	ChainFileMagic = "NEBCHAIN"
+
+	// MaxChainFileBlockSize the max size of a block in chain file.
+	MaxChainFileBlockSize = 128 * 1024 * 1024
+)
+
+// Export write the canonical blocks in [fromHeight, toHeight] to w in chain file format.
+func (bc *BlockChain) Export(w io.Writer, fromHeight, toHeight uint64) error {
+	bw := bufio.NewWriter(w)
+	header := make([]byte, len(ChainFileMagic)+4)
+	copy(header, ChainFileMagic)
+	binary.BigEndian.PutUint32(header[len(ChainFileMagic):], bc.chainID)
+	if _, err := bw.Write(header); err != nil {
+		return err
+	}
+
+	it := bc.Iterator(fromHeight, toHeight)
+	for it.Next() {
+		pbBlock, err := it.Block().ToProto()
+		if err != nil {
+			return err
+		}
+		data, err := proto.Marshal(pbBlock)
+		if err != nil {
+			return err
+		}
+		size := make([]byte, 4)
+		binary.BigEndian.PutUint32(size, uint32(len(data)))
+		if _, err := bw.Write(size); err != nil {
+			return err
+		}
+		if _, err := bw.Write(data); err != nil {
+			return err
+		}
+	}
+	if err := it.Err(); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// Import read blocks in chain file format from r, verify and link them on chain one by one,
+// each imported block becomes the new tail. The blocks already on chain are skipped,
+// so a failed import can be resumed with the same file. It returns the count of imported blocks.
+func (bc *BlockChain) Import(r io.Reader) (int, error) {
+	br := bufio.NewReader(r)
+	header := make([]byte, len(ChainFileMagic)+4)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return 0, ErrInvalidChainFile
+	}
+	if string(header[:len(ChainFileMagic)]) != ChainFileMagic {
+		return 0, ErrInvalidChainFile
+	}
+	if binary.BigEndian.Uint32(header[len(ChainFileMagic):]) != bc.chainID {
+		return 0, ErrMismatchedChainFile
+	}
+
+	imported := 0
+	size := make([]byte, 4)
+	for {
+		if _, err := io.ReadFull(br, size); err != nil {
+			if err == io.EOF {
+				return imported, nil
+			}
+			return imported, ErrInvalidChainFile
+		}
+		length := binary.BigEndian.Uint32(size)
+		if length > MaxChainFileBlockSize {
+			return imported, ErrInvalidChainFile
+		}
+		data := make([]byte, length)
+		if _, err := io.ReadFull(br, data); err != nil {
+			return imported, ErrInvalidChainFile
+		}
+
+		pbBlock := new(corepb.Block)
+		if err := proto.Unmarshal(data, pbBlock); err != nil {
+			return imported, err
+		}
+		block := new(Block)
+		if err := block.FromProtoWithChainID(pbBlock, bc.chainID); err != nil {
+			return imported, err
+		}
+
+		ok, err := bc.importBlock(block)
+		if err != nil {
+			logging.VLog().WithFields(logrus.Fields{
+				"block":    block,
+				"imported": imported,
+				"err":      err,
+			}).Debug("Failed to import block.")
+			return imported, err
+		}
+		if ok {
+			imported++
+		}
+	}
+}
+
+// importBlock return false if the block is already on chain.
+func (bc *BlockChain) importBlock(block *Block) (bool, error) {
+	// serialize with the blocks from network.
+	bc.bkPool.mu.Lock()
+	defer bc.bkPool.mu.Unlock()
+
+	if bc.GetBlock(block.Hash()) != nil {
+		return false, nil
+	}
+	parent := bc.GetBlock(block.ParentHash())
+	if parent == nil {
+		return false, ErrMissingParentBlock
+	}
+	startAt := time.Now()
+	err := block.VerifyIntegrity(bc.chainID, bc.ConsensusHandler())
+	bc.verifyLatency.observe(time.Since(startAt))
+	if err != nil {
+		return false, err
+	}
+	if err := block.LinkParentBlock(bc, parent); err != nil {
+		return false, err
+	}
+	startAt = time.Now()
+	err = block.VerifyExecution()
+	bc.executeLatency.observe(time.Since(startAt))
+	if err != nil {
+		return false, err
+	}
+	if err := bc.putVerifiedNewBlocks(parent, []*Block{block}, []*Block{block}); err != nil {
+		return false, err
+	}
+	return true, bc.SetTailBlock(block)
+}
@@ -0,0 +1,110 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/alexlisong/go-nebulas/crypto"
+	"github.com/alexlisong/go-nebulas/crypto/keystore"
+	"github.com/alexlisong/go-nebulas/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockChain_ExportImport(t *testing.T) {
+	src := testNeb(t).chain
+
+	from := mockAddress()
+	to := mockAddress()
+	key, err := keystore.DefaultKS.GetUnlocked(from.String())
+	assert.Nil(t, err)
+	signature, err := crypto.NewSignature(keystore.SECP256K1)
+	assert.Nil(t, err)
+	signature.InitSign(key.(keystore.PrivateKey))
+
+	// a 50-block chain, from is rewarded in the first block and transfers in the others.
+	count := 50
+	gasLimit, _ := util.NewUint128FromInt(200000)
+	value, _ := util.NewUint128FromInt(1)
+	for i := 1; i <= count; i++ {
+		block, err := src.NewBlock(from)
+		assert.Nil(t, err)
+		block.SetTimestamp(BlockInterval * int64(i))
+		if i > 1 {
+			tx, err := NewTransaction(src.ChainID(), from, to, value, uint64(i-1), TxPayloadBinaryType, []byte("nas"), TransactionGasPrice, gasLimit)
+			assert.Nil(t, err)
+			assert.Nil(t, tx.Sign(signature))
+			txWorldState, err := block.WorldState().Prepare(tx.Hash().String())
+			assert.Nil(t, err)
+			_, err = block.ExecuteTransaction(tx, txWorldState)
+			assert.Nil(t, err)
+			_, err = txWorldState.CheckAndUpdate()
+			assert.Nil(t, err)
+			assert.Nil(t, txWorldState.Close())
+			block.transactions = append(block.transactions, tx)
+			block.dependency.AddNode(tx.Hash().String())
+		}
+		assert.Nil(t, block.Seal())
+		assert.Nil(t, block.Sign(signature))
+		assert.Nil(t, src.BlockPool().Push(block))
+	}
+	assert.Equal(t, src.genesisBlock.Height()+uint64(count), src.TailBlock().Height())
+
+	buf := new(bytes.Buffer)
+	assert.Nil(t, src.Export(buf, 0, src.TailBlock().Height()))
+	file := buf.Bytes()
+
+	// refuse the file of another chain.
+	dst := testNeb(t).chain
+	other := append([]byte{}, file...)
+	binary.BigEndian.PutUint32(other[len(ChainFileMagic):], src.ChainID()+1)
+	_, err = dst.Import(bytes.NewReader(other))
+	assert.Equal(t, ErrMismatchedChainFile, err)
+	_, err = dst.Import(bytes.NewReader([]byte("invalid")))
+	assert.Equal(t, ErrInvalidChainFile, err)
+
+	// a truncated file imports the complete blocks before the broken one.
+	imported, err := dst.Import(bytes.NewReader(file[:len(file)/2]))
+	assert.Equal(t, ErrInvalidChainFile, err)
+	assert.True(t, imported > 0 && imported < count)
+	assert.Equal(t, src.GetBlockOnCanonicalChainByHeight(dst.TailBlock().Height()).Hash(), dst.TailBlock().Hash())
+
+	// resume with the whole file.
+	resumed, err := dst.Import(bytes.NewReader(file))
+	assert.Nil(t, err)
+	assert.Equal(t, count, imported+resumed)
+	assert.Equal(t, src.TailBlock().Hash(), dst.TailBlock().Hash())
+
+	for height := src.genesisBlock.Height(); height <= src.TailBlock().Height(); height++ {
+		assert.Equal(t, src.GetBlockOnCanonicalChainByHeight(height).Hash(), dst.GetBlockOnCanonicalChainByHeight(height).Hash())
+	}
+	srcAcc, err := src.TailBlock().GetAccount(to.Bytes())
+	assert.Nil(t, err)
+	dstAcc, err := dst.TailBlock().GetAccount(to.Bytes())
+	assert.Nil(t, err)
+	assert.Equal(t, 0, srcAcc.Balance().Cmp(dstAcc.Balance()))
+	assert.Equal(t, uint64(count-1), dstAcc.Balance().Uint64())
+
+	// nothing to import again.
+	imported, err = dst.Import(bytes.NewReader(file))
+	assert.Nil(t, err)
+	assert.Equal(t, 0, imported)
+}
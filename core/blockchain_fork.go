@@ -0,0 +1,111 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"sort"
+
+	"github.com/alexlisong/go-nebulas/util/byteutils"
+	"github.com/alexlisong/go-nebulas/util/logging"
+	"github.com/sirupsen/logrus"
+)
+
+// ForkStat is the summary of a live fork in ForkStats.
+type ForkStat struct {
+	Tip              string `json:"tip"`
+	Height           uint64 `json:"height"`
+	Divergence       string `json:"divergence"`
+	DivergenceHeight uint64 `json:"divergence_height"`
+}
+
+// ForkStats return the stats of the detached tails off canonical chain, highest first.
+func (bc *BlockChain) ForkStats() []*ForkStat {
+	stats := []*ForkStat{}
+	for _, tail := range bc.DetachedTailBlocks() {
+		ancestor, err := bc.FindCommonAncestorWithTail(tail)
+		if err != nil || ancestor.Hash().Equals(tail.Hash()) {
+			continue
+		}
+		stats = append(stats, &ForkStat{
+			Tip:              tail.Hash().String(),
+			Height:           tail.Height(),
+			Divergence:       ancestor.Hash().String(),
+			DivergenceHeight: ancestor.Height(),
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Height > stats[j].Height
+	})
+	return stats
+}
+
+// forkBlocks return the blocks from tail back to its first ancestor on canonical chain, excluded.
+func (bc *BlockChain) forkBlocks(tail *Block) []*Block {
+	blocks := []*Block{}
+	for block := tail; block != nil && bc.GetBlockOnCanonicalChainByHash(block.Hash()) == nil; {
+		blocks = append(blocks, block)
+		block = bc.GetBlock(block.ParentHash())
+	}
+	return blocks
+}
+
+// CleanDetachedTails drop the detached tails below LIB, which can never be on canonical chain,
+// and the cached blocks only on their forks. It return the count of dropped tails.
+func (bc *BlockChain) CleanDetachedTails() int {
+	// hold block pool to keep new blocks from linking to the dropped tails.
+	bc.bkPool.mu.Lock()
+	defer bc.bkPool.mu.Unlock()
+
+	lib := bc.LIB()
+	tail := bc.TailBlock()
+
+	stale := []*Block{}
+	live := make(map[byteutils.HexHash]bool)
+	for _, block := range bc.DetachedTailBlocks() {
+		if block.Height() < lib.Height() && !block.Hash().Equals(tail.Hash()) {
+			stale = append(stale, block)
+			continue
+		}
+		for _, v := range bc.forkBlocks(block) {
+			live[v.Hash().Hex()] = true
+		}
+	}
+
+	released := 0
+	for _, block := range stale {
+		bc.detachedTailBlocks.Remove(block.Hash().Hex())
+		// the blocks on canonical chain and shared with live forks are kept.
+		for _, v := range bc.forkBlocks(block) {
+			if live[v.Hash().Hex()] {
+				break
+			}
+			bc.cachedBlocks.Remove(v.Hash().Hex())
+			released++
+		}
+	}
+
+	if len(stale) > 0 {
+		logging.VLog().WithFields(logrus.Fields{
+			"lib":      lib,
+			"tails":    len(stale),
+			"released": released,
+		}).Info("Succeed to clean detached tails.")
+	}
+	return len(stale)
+}
@@ -0,0 +1,83 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"github.com/alexlisong/go-nebulas/common/trie"
+	"github.com/alexlisong/go-nebulas/core/pb"
+	"github.com/alexlisong/go-nebulas/core/state"
+	"github.com/alexlisong/go-nebulas/util/byteutils"
+)
+
+// VerifyProof return if proof is a merkle path proving key has value in the trie with root,
+// an empty value proves key is absent.
+func VerifyProof(root byteutils.Hash, key, value []byte, proof [][]byte) bool {
+	return trie.VerifyPath(root, key, value, proof)
+}
+
+// VerifyStateProof return if proof is valid against the trusted root.
+func VerifyStateProof(root byteutils.Hash, proof *corepb.StateProof) bool {
+	return VerifyProof(root, proof.Key, proof.Value, proof.Nodes)
+}
+
+// AccountProof return the proof of addr's account against the state root of canonical block at height,
+// the value is the account bytes, empty if the account doesn't exist.
+func (bc *BlockChain) AccountProof(addr *Address, height uint64) (*corepb.StateProof, error) {
+	return bc.stateProof(height, addr.address, func(block *Block) byteutils.Hash {
+		return block.StateRoot()
+	}, func(ws state.WorldState) ([]byte, [][]byte, error) {
+		return ws.GenerateAccountProof(addr.address)
+	})
+}
+
+// TransactionProof return the proof of tx against the txs root of canonical block at height,
+// the value is the tx bytes, empty if the tx isn't on chain at height.
+func (bc *BlockChain) TransactionProof(hash byteutils.Hash, height uint64) (*corepb.StateProof, error) {
+	return bc.stateProof(height, hash, func(block *Block) byteutils.Hash {
+		return block.TxsRoot()
+	}, func(ws state.WorldState) ([]byte, [][]byte, error) {
+		return ws.GenerateTxProof(hash)
+	})
+}
+
+func (bc *BlockChain) stateProof(height uint64, key byteutils.Hash, root func(*Block) byteutils.Hash, prove func(state.WorldState) ([]byte, [][]byte, error)) (*corepb.StateProof, error) {
+	block := bc.GetBlockOnCanonicalChainByHeight(height)
+	if block == nil {
+		return nil, ErrBlockNotFound
+	}
+	if block.statePruned {
+		return nil, ErrStatePruned
+	}
+	worldState, err := block.WorldState().Clone()
+	if err != nil {
+		return nil, err
+	}
+	value, nodes, err := prove(worldState)
+	if err != nil {
+		return nil, err
+	}
+	return &corepb.StateProof{
+		BlockHash: block.Hash(),
+		Height:    block.Height(),
+		Root:      root(block),
+		Key:       key,
+		Value:     value,
+		Nodes:     nodes,
+	}, nil
+}
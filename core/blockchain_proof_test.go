@@ -0,0 +1,121 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"testing"
+
+	"github.com/alexlisong/go-nebulas/core/pb"
+	"github.com/alexlisong/go-nebulas/util"
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockChain_StateProof(t *testing.T) {
+	neb := testNeb(t)
+	bc := neb.chain
+
+	from, to := mockAddress(), mockAddress()
+	balance, _ := util.NewUint128FromString("1000000000000000000")
+	bc.tailBlock.Begin()
+	acc, err := bc.tailBlock.worldState.GetOrCreateUserAccount(from.address)
+	assert.Nil(t, err)
+	assert.Nil(t, acc.AddBalance(balance))
+	bc.tailBlock.Commit()
+	bc.tailBlock.header.stateRoot = bc.tailBlock.worldState.AccountsRoot()
+	assert.Nil(t, bc.StoreBlockToStorage(bc.tailBlock))
+	parent := bc.TailBlock()
+
+	mint := func(coinbase *Address, txs ...*Transaction) *Block {
+		block, err := bc.NewBlockFromParent(coinbase, parent)
+		assert.Nil(t, err)
+		block.header.timestamp = parent.Timestamp() + BlockIntervalInSecond
+		for _, tx := range txs {
+			txWorldState, err := block.WorldState().Prepare(tx.Hash().String())
+			assert.Nil(t, err)
+			_, err = block.ExecuteTransaction(tx, txWorldState)
+			assert.Nil(t, err)
+			_, err = txWorldState.CheckAndUpdate()
+			assert.Nil(t, err)
+			txWorldState.Close()
+			assert.Nil(t, block.dependency.AddNode(tx.Hash().String()))
+			block.transactions = append(block.transactions, tx)
+		}
+		assert.Nil(t, block.Seal())
+		signBlock(block)
+		assert.Nil(t, bc.BlockPool().Push(block))
+		return bc.GetBlock(block.Hash())
+	}
+
+	tx, err := NewTransaction(bc.ChainID(), from, to, util.NewUint128FromUint(1), 1, TxPayloadBinaryType, nil, TransactionGasPrice, TransactionMaxGas)
+	assert.Nil(t, err)
+	assert.Nil(t, tx.Sign(mockSignature(t, from)))
+	forkA := mint(mockAddress(), tx)
+	forkB := mint(mockAddress())
+	assert.Nil(t, bc.SetTailBlock(forkA))
+	height := forkA.Height()
+
+	// the account and tx on chain.
+	accProof, err := bc.AccountProof(to, height)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte(forkA.StateRoot()), accProof.Root)
+	assert.True(t, VerifyStateProof(forkA.StateRoot(), accProof))
+	pbAcc := new(corepb.Account)
+	assert.Nil(t, proto.Unmarshal(accProof.Value, pbAcc))
+	assert.Equal(t, []byte(to.address), pbAcc.Address)
+
+	txProof, err := bc.TransactionProof(tx.Hash(), height)
+	assert.Nil(t, err)
+	assert.True(t, VerifyStateProof(forkA.TxsRoot(), txProof))
+	txBytes, err := tx.ToBytes()
+	assert.Nil(t, err)
+	assert.Equal(t, txBytes, txProof.Value)
+
+	// the proof travels as protobuf.
+	data, err := proto.Marshal(accProof)
+	assert.Nil(t, err)
+	received := new(corepb.StateProof)
+	assert.Nil(t, proto.Unmarshal(data, received))
+	assert.True(t, VerifyStateProof(forkA.StateRoot(), received))
+
+	// a forged value fails.
+	assert.False(t, VerifyProof(forkA.StateRoot(), accProof.Key, txBytes, accProof.Nodes))
+
+	// exclusion proof.
+	absentProof, err := bc.AccountProof(mockAddress(), height)
+	assert.Nil(t, err)
+	assert.Empty(t, absentProof.Value)
+	assert.True(t, VerifyStateProof(forkA.StateRoot(), absentProof))
+	assert.False(t, VerifyProof(forkA.StateRoot(), absentProof.Key, accProof.Value, absentProof.Nodes))
+
+	// the proofs against the reorged-away root fail on the new canonical block.
+	assert.Nil(t, bc.SetTailBlock(forkB))
+	assert.Equal(t, forkB.Hash(), bc.GetBlockOnCanonicalChainByHeight(height).Hash())
+	assert.False(t, VerifyStateProof(forkB.StateRoot(), accProof))
+	assert.False(t, VerifyStateProof(forkB.TxsRoot(), txProof))
+
+	accProof, err = bc.AccountProof(to, height)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte(forkB.Hash()), accProof.BlockHash)
+	assert.Empty(t, accProof.Value)
+	assert.True(t, VerifyStateProof(forkB.StateRoot(), accProof))
+
+	_, err = bc.AccountProof(to, height+1)
+	assert.Equal(t, ErrBlockNotFound, err)
+}
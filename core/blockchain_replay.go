@@ -0,0 +1,208 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"github.com/alexlisong/go-nebulas/core/state"
+	"github.com/alexlisong/go-nebulas/util"
+	"github.com/alexlisong/go-nebulas/util/byteutils"
+	"github.com/alexlisong/go-nebulas/util/logging"
+	"github.com/sirupsen/logrus"
+)
+
+// ReplayOptions configure ReplayBlock.
+type ReplayOptions struct {
+	// StopAt is the index of the last tx to replay, negative for all txs.
+	StopAt int
+	// DumpStateRoots records the accounts root after each replayed tx.
+	DumpStateRoots bool
+}
+
+// ReplayAccountDiff is the change of an account touched by a replayed tx.
+type ReplayAccountDiff struct {
+	Address       *Address
+	BalanceBefore *util.Uint128
+	BalanceAfter  *util.Uint128
+	NonceBefore   uint64
+	NonceAfter    uint64
+}
+
+// ReplayTxResult is the result of a replayed tx.
+type ReplayTxResult struct {
+	Index int
+	Tx    *Transaction
+	// Err rejects the tx, and the block including it. A failed execution is in Receipt instead.
+	Err error
+	// Receipt is nil before ReceiptsForkHeight, the result is in Events.
+	Receipt  *TransactionReceipt
+	GasTrace *GasTrace
+	Events   []*state.Event
+	Accounts []*ReplayAccountDiff
+	// StateRoot is the accounts root after the tx if DumpStateRoots is set.
+	StateRoot byteutils.Hash
+}
+
+// ReplayResult is the result of a replayed block.
+type ReplayResult struct {
+	Block *Block
+	Txs   []*ReplayTxResult
+	// StateRoot is the accounts root after the replayed txs,
+	// including the gas reward to coinbase if all txs are replayed.
+	StateRoot byteutils.Hash
+	// Err is the error verifying the block after all txs are replayed.
+	Err error
+}
+
+// ReplayBlock re-execute the txs of a stored block one by one on a fork of its parent's state, and return
+// their results for debugging. The fork is rolled back, nothing is written in storage.
+func (bc *BlockChain) ReplayBlock(hash byteutils.Hash, opts *ReplayOptions) (*ReplayResult, error) {
+	if opts == nil {
+		opts = &ReplayOptions{StopAt: -1}
+	}
+	block := bc.GetBlock(hash)
+	if block == nil {
+		return nil, ErrBlockNotFound
+	}
+	parent := bc.GetBlock(block.ParentHash())
+	if parent == nil {
+		return nil, ErrMissingParentBlock
+	}
+
+	// replay a copy of the block, the stored one keeps its state.
+	pbBlock, err := block.ToProto()
+	if err != nil {
+		return nil, err
+	}
+	replayed := new(Block)
+	if err := replayed.FromProto(pbBlock); err != nil {
+		return nil, err
+	}
+	if err := replayed.LinkParentBlock(bc, parent); err != nil {
+		return nil, err
+	}
+	replayed.gasTraces = make(map[byteutils.HexHash]*GasTrace)
+
+	if err := replayed.Begin(); err != nil {
+		return nil, err
+	}
+	defer replayed.RollBack()
+
+	if err := replayed.rewardCoinbaseForMint(); err != nil {
+		return nil, err
+	}
+
+	result := &ReplayResult{Block: block}
+	for idx, tx := range replayed.transactions {
+		if opts.StopAt >= 0 && idx > opts.StopAt {
+			break
+		}
+		txResult, err := replayed.replayTransaction(idx, tx, opts.DumpStateRoots)
+		if err != nil {
+			return nil, err
+		}
+		result.Txs = append(result.Txs, txResult)
+		if txResult.Err != nil {
+			break
+		}
+	}
+
+	if len(result.Txs) == len(replayed.transactions) && (len(result.Txs) == 0 || result.Txs[len(result.Txs)-1].Err == nil) {
+		if err := replayed.rewardCoinbaseForGas(); err != nil {
+			return nil, err
+		}
+		if err := replayed.WorldState().Flush(); err != nil {
+			return nil, err
+		}
+		if result.Err = replayed.verifyGas(); result.Err == nil {
+			if result.Err = replayed.verifyBloom(); result.Err == nil {
+				result.Err = replayed.verifyState()
+			}
+		}
+	} else if err := replayed.WorldState().Flush(); err != nil {
+		return nil, err
+	}
+	result.StateRoot = replayed.WorldState().AccountsRoot()
+
+	logging.VLog().WithFields(logrus.Fields{
+		"block":    block,
+		"replayed": len(result.Txs),
+		"err":      result.Err,
+	}).Debug("Replayed block.")
+	return result, nil
+}
+
+// replayTransaction execute tx on the block's world state and collect its result,
+// the returned error is unexpected.
+func (block *Block) replayTransaction(idx int, tx *Transaction, dumpStateRoot bool) (*ReplayTxResult, error) {
+	result := &ReplayTxResult{Index: idx, Tx: tx}
+
+	addrs := []*Address{tx.from, tx.to}
+	if tx.payer != nil {
+		addrs = append(addrs, tx.payer)
+	}
+	for _, addr := range addrs {
+		balance, nonce, err := getUserAccountState(block.WorldState(), addr.address)
+		if err != nil {
+			return nil, err
+		}
+		result.Accounts = append(result.Accounts, &ReplayAccountDiff{
+			Address:       addr,
+			BalanceBefore: balance,
+			NonceBefore:   nonce,
+		})
+	}
+
+	txWorldState, err := block.WorldState().Prepare(tx.Hash().String())
+	if err != nil {
+		return nil, err
+	}
+	defer txWorldState.Close()
+
+	if _, err := block.ExecuteTransaction(tx, txWorldState); err != nil {
+		result.Err = err
+		return result, nil
+	}
+	if _, err := txWorldState.CheckAndUpdate(); err != nil {
+		result.Err = err
+		return result, nil
+	}
+
+	for _, diff := range result.Accounts {
+		if diff.BalanceAfter, diff.NonceAfter, err = getUserAccountState(block.WorldState(), diff.Address.address); err != nil {
+			return nil, err
+		}
+	}
+	if block.height >= ReceiptsForkHeight {
+		if result.Receipt, err = GetTransactionReceipt(tx.hash, block.WorldState()); err != nil {
+			return nil, err
+		}
+	}
+	if result.Events, err = block.WorldState().FetchEvents(tx.hash); err != nil {
+		return nil, err
+	}
+	result.GasTrace = block.gasTraces[tx.hash.Hex()]
+
+	if dumpStateRoot {
+		if err := block.WorldState().Flush(); err != nil {
+			return nil, err
+		}
+		result.StateRoot = block.WorldState().AccountsRoot()
+	}
+	return result, nil
+}
@@ -0,0 +1,148 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"testing"
+
+	"github.com/alexlisong/go-nebulas/core/state"
+	"github.com/alexlisong/go-nebulas/util"
+	"github.com/stretchr/testify/assert"
+)
+
+// replayNvm fails the call of function "fail", and records an event in other calls.
+type replayNvm struct{}
+
+type replayEngine struct {
+	mockEngine
+	tx *Transaction
+	ws WorldState
+}
+
+func (nvm *replayNvm) CreateEngine(block *Block, tx *Transaction, contract state.Account, ws WorldState) (SmartContractEngine, error) {
+	return &replayEngine{tx: tx, ws: ws}, nil
+}
+
+func (engine *replayEngine) Call(source, sourceType, function, args string) (string, error) {
+	if function == "fail" {
+		return "", ErrExecutionFailed
+	}
+	engine.ws.RecordEvent(engine.tx.hash, &state.Event{Topic: "chain.contract.replay", Data: function})
+	return "", nil
+}
+
+func TestBlockChain_ReplayBlock(t *testing.T) {
+	defer func(height uint64) { ReceiptsForkHeight = height }(ReceiptsForkHeight)
+	ReceiptsForkHeight = 0
+
+	neb := testNeb(t)
+	bc := neb.chain
+	bc.nvm = &replayNvm{}
+	bc.tailBlock.nvm = bc.nvm
+
+	from, coinbase := mockAddress(), mockAddress()
+	signature := mockSignature(t, from)
+	balance, _ := util.NewUint128FromString("1000000000000000000")
+	bc.tailBlock.Begin()
+	acc, err := bc.tailBlock.worldState.GetOrCreateUserAccount(from.address)
+	assert.Nil(t, err)
+	assert.Nil(t, acc.AddBalance(balance))
+	bc.tailBlock.Commit()
+	bc.tailBlock.header.stateRoot = bc.tailBlock.worldState.AccountsRoot()
+	assert.Nil(t, bc.StoreBlockToStorage(bc.tailBlock))
+
+	mint := func(parent *Block, txs ...*Transaction) *Block {
+		block, err := bc.NewBlockFromParent(coinbase, parent)
+		assert.Nil(t, err)
+		block.header.timestamp = parent.Timestamp() + BlockIntervalInSecond
+		for _, tx := range txs {
+			txWorldState, err := block.WorldState().Prepare(tx.Hash().String())
+			assert.Nil(t, err)
+			_, err = block.ExecuteTransaction(tx, txWorldState)
+			assert.Nil(t, err)
+			_, err = txWorldState.CheckAndUpdate()
+			assert.Nil(t, err)
+			txWorldState.Close()
+			assert.Nil(t, block.dependency.AddNode(tx.Hash().String()))
+			block.transactions = append(block.transactions, tx)
+		}
+		assert.Nil(t, block.Seal())
+		signBlock(block)
+		assert.Nil(t, bc.BlockPool().Push(block))
+		return bc.GetBlock(block.Hash())
+	}
+
+	deployTx := mockDeployTransaction(bc.ChainID(), 1)
+	deployTx.from, deployTx.to = from, from
+	assert.Nil(t, deployTx.Sign(signature))
+	contract, err := deployTx.GenerateContractAddress()
+	assert.Nil(t, err)
+	deployed := mint(bc.TailBlock(), deployTx)
+
+	okTx := mockCallTransaction(bc.ChainID(), 2, "ok", "")
+	okTx.from, okTx.to = from, contract
+	assert.Nil(t, okTx.Sign(signature))
+	failTx := mockCallTransaction(bc.ChainID(), 3, "fail", "")
+	failTx.from, failTx.to = from, contract
+	assert.Nil(t, failTx.Sign(signature))
+	block := mint(deployed, okTx, failTx)
+
+	result, err := bc.ReplayBlock(block.Hash(), nil)
+	assert.Nil(t, err)
+	assert.Nil(t, result.Err)
+	assert.Equal(t, block.StateRoot(), result.StateRoot)
+	assert.Equal(t, 2, len(result.Txs))
+
+	ok := result.Txs[0]
+	assert.Nil(t, ok.Err)
+	assert.Equal(t, uint32(TxExecutionSuccess), ok.Receipt.Status())
+	assert.NotNil(t, ok.GasTrace)
+	assert.Equal(t, "ok", ok.Events[0].Data)
+	assert.Equal(t, from, ok.Accounts[0].Address)
+	assert.Equal(t, uint64(1), ok.Accounts[0].NonceBefore)
+	assert.Equal(t, uint64(2), ok.Accounts[0].NonceAfter)
+	assert.True(t, ok.Accounts[0].BalanceAfter.Cmp(ok.Accounts[0].BalanceBefore) < 0)
+	assert.Nil(t, ok.StateRoot)
+
+	fail := result.Txs[1]
+	assert.Nil(t, fail.Err)
+	assert.Equal(t, uint32(TxExecutionFailed), fail.Receipt.Status())
+	assert.NotNil(t, fail.GasTrace)
+	for _, event := range fail.Events {
+		assert.NotEqual(t, "chain.contract.replay", event.Topic)
+	}
+	assert.Equal(t, uint64(3), fail.Accounts[0].NonceAfter)
+
+	// stop at the first tx with its state root.
+	partial, err := bc.ReplayBlock(block.Hash(), &ReplayOptions{StopAt: 0, DumpStateRoots: true})
+	assert.Nil(t, err)
+	assert.Nil(t, partial.Err)
+	assert.Equal(t, 1, len(partial.Txs))
+	assert.NotNil(t, partial.Txs[0].StateRoot)
+	assert.Equal(t, partial.Txs[0].StateRoot, partial.StateRoot)
+	assert.NotEqual(t, block.StateRoot(), partial.StateRoot)
+
+	// the stored block is untouched.
+	stored, err := LoadBlockFromStorage(block.Hash(), bc)
+	assert.Nil(t, err)
+	assert.Equal(t, block.StateRoot(), stored.WorldState().AccountsRoot())
+
+	_, err = bc.ReplayBlock(mockAddress().address, nil)
+	assert.Equal(t, ErrBlockNotFound, err)
+}
@@ -0,0 +1,94 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"github.com/alexlisong/go-nebulas/util/byteutils"
+	"github.com/alexlisong/go-nebulas/util/logging"
+	"github.com/sirupsen/logrus"
+)
+
+// ApplySnapshot set the block as tail and LIB without executing the blocks before it.
+// All trie nodes of the states at block must be in storage already, the blocks
+// below it are never on local chain, so are the tx indices of their txs.
+func (bc *BlockChain) ApplySnapshot(block *Block) error {
+	if block == nil {
+		return ErrNilArgument
+	}
+	if block.ChainID() != bc.chainID {
+		return ErrInvalidChainID
+	}
+	wantedHash, err := block.calHash()
+	if err != nil {
+		return err
+	}
+	if !wantedHash.Equals(block.Hash()) {
+		return ErrInvalidBlockHash
+	}
+
+	// serialize with the blocks from network.
+	bc.bkPool.mu.Lock()
+	defer bc.bkPool.mu.Unlock()
+
+	tail := bc.TailBlock()
+	if block.Height() <= tail.Height() {
+		return ErrSnapshotBehindTail
+	}
+
+	if err := bc.StoreBlockToStorage(block); err != nil {
+		return err
+	}
+	// load the states from storage to make sure the roots exist.
+	snapshot, err := LoadBlockFromStorage(block.Hash(), bc)
+	if err != nil {
+		if err := bc.storage.Del(blockStorageKey(block.Hash())); err != nil {
+			return err
+		}
+		return err
+	}
+	if err := bc.storage.Put(heightStorageKey(snapshot.Height()), snapshot.Hash()); err != nil {
+		return err
+	}
+	if err := bc.putTxIndex(snapshot); err != nil {
+		return err
+	}
+	// nothing to prune below the snapshot.
+	if err := bc.storage.Put([]byte(PrunedHeight), byteutils.FromUint64(snapshot.Height())); err != nil {
+		return err
+	}
+	if err := bc.StoreTailHashToStorage(snapshot); err != nil {
+		return err
+	}
+
+	bc.cachedBlocks.Add(snapshot.Hash().Hex(), snapshot)
+	bc.detachedTailBlocks.Purge()
+	bc.detachedTailBlocks.Add(snapshot.Hash().Hex(), snapshot)
+	bc.mu.Lock()
+	bc.tailBlock = snapshot
+	bc.mu.Unlock()
+	if err := bc.SetLIB(snapshot); err != nil {
+		return err
+	}
+
+	logging.CLog().WithFields(logrus.Fields{
+		"tail":     tail,
+		"snapshot": snapshot,
+	}).Info("Succeed to apply state snapshot.")
+	return nil
+}
@@ -0,0 +1,93 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"sync/atomic"
+)
+
+// ChainStatus is the snapshot of chain health.
+type ChainStatus struct {
+	TailHeight uint64
+	LIBHeight  uint64
+	// DetachedTails counts the tails of forks, including the canonical one.
+	DetachedTails int
+	BlockPool     *BlockPoolStats
+
+	// Reorgs counts the tail updates reverting canonical blocks,
+	// the reorg depth is the count of reverted blocks.
+	Reorgs         uint64
+	LastReorgDepth uint64
+	MaxReorgDepth  uint64
+
+	// AppliedBlocks and AppliedTxs count the blocks and their txs applied on canonical chain.
+	AppliedBlocks uint64
+	AppliedTxs    uint64
+	TailTxs       int
+
+	// VerifyLatency is the time of verifying the integrity of a block,
+	// ExecuteLatency is the time of executing its txs and verifying the states.
+	VerifyLatency  []LatencyBucket
+	ExecuteLatency []LatencyBucket
+}
+
+// ChainStatus return the heights, forks, reorgs and block processing latencies of chain.
+func (bc *BlockChain) ChainStatus() *ChainStatus {
+	tail, lib := bc.TailBlock(), bc.LIB()
+	status := &ChainStatus{
+		TailHeight:     tail.Height(),
+		DetachedTails:  bc.detachedTailBlocks.Len(),
+		Reorgs:         atomic.LoadUint64(&bc.reorgs),
+		LastReorgDepth: atomic.LoadUint64(&bc.lastReorgDepth),
+		MaxReorgDepth:  atomic.LoadUint64(&bc.maxReorgDepth),
+		AppliedBlocks:  atomic.LoadUint64(&bc.appliedBlocks),
+		AppliedTxs:     atomic.LoadUint64(&bc.appliedTxs),
+		TailTxs:        len(tail.transactions),
+		VerifyLatency:  bc.verifyLatency.snapshot(),
+		ExecuteLatency: bc.executeLatency.snapshot(),
+	}
+	if lib != nil {
+		status.LIBHeight = lib.Height()
+	}
+	if bc.bkPool != nil {
+		status.BlockPool = bc.bkPool.Stats()
+	}
+	return status
+}
+
+// recordTailUpdate update the reorg and applied counters after tail is updated.
+func (bc *BlockChain) recordTailUpdate(reverted, applied []*Block) {
+	if depth := uint64(len(reverted)); depth > 0 {
+		atomic.AddUint64(&bc.reorgs, 1)
+		atomic.StoreUint64(&bc.lastReorgDepth, depth)
+		for {
+			max := atomic.LoadUint64(&bc.maxReorgDepth)
+			if depth <= max || atomic.CompareAndSwapUint64(&bc.maxReorgDepth, max, depth) {
+				break
+			}
+		}
+	}
+
+	txs := 0
+	for _, block := range applied {
+		txs += len(block.transactions)
+	}
+	atomic.AddUint64(&bc.appliedBlocks, uint64(len(applied)))
+	atomic.AddUint64(&bc.appliedTxs, uint64(txs))
+}
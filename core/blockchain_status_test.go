@@ -0,0 +1,92 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockChain_ChainStatus(t *testing.T) {
+	neb := testNeb(t)
+	bc := neb.chain
+	genesis := bc.genesisBlock
+
+	coinbase := mockAddress()
+	newBlock := func(parent *Block, timestamp int64, txs ...*Transaction) *Block {
+		block, err := bc.NewBlockFromParent(coinbase, parent)
+		assert.Nil(t, err)
+		block.transactions = append(block.transactions, txs...)
+		block.header.timestamp = timestamp
+		assert.Nil(t, block.Seal())
+		assert.Nil(t, bc.StoreBlockToStorage(block))
+		return block
+	}
+
+	status := bc.ChainStatus()
+	assert.Equal(t, genesis.Height(), status.TailHeight)
+	assert.Equal(t, uint64(0), status.Reorgs)
+	assert.NotNil(t, status.BlockPool)
+	assert.Equal(t, len(PipelineLatencyBuckets)+1, len(status.VerifyLatency))
+
+	/*
+		genesis -- a1 -- a2 -- a3
+		       \_ b1 -- b2
+	*/
+	a1 := newBlock(genesis, BlockInterval, mockNormalTransaction(bc.chainID, 1), mockNormalTransaction(bc.chainID, 2))
+	assert.Nil(t, bc.SetTailBlock(a1))
+	status = bc.ChainStatus()
+	assert.Equal(t, uint64(0), status.Reorgs)
+	assert.Equal(t, uint64(1), status.AppliedBlocks)
+	assert.Equal(t, uint64(2), status.AppliedTxs)
+	assert.Equal(t, 2, status.TailTxs)
+
+	// reorg to the longer branch reverts a1.
+	b1 := newBlock(genesis, BlockInterval*2, mockNormalTransaction(bc.chainID, 1))
+	b2 := newBlock(b1, BlockInterval*3)
+	assert.Nil(t, bc.SetTailBlock(b2))
+	status = bc.ChainStatus()
+	assert.Equal(t, b2.Height(), status.TailHeight)
+	assert.Equal(t, uint64(1), status.Reorgs)
+	assert.Equal(t, uint64(1), status.LastReorgDepth)
+	assert.Equal(t, uint64(1), status.MaxReorgDepth)
+	assert.Equal(t, uint64(3), status.AppliedBlocks)
+	assert.Equal(t, uint64(3), status.AppliedTxs)
+	assert.Equal(t, 0, status.TailTxs)
+
+	// back to the a branch reverts b2 and b1.
+	a2 := newBlock(a1, BlockInterval*4)
+	a3 := newBlock(a2, BlockInterval*5)
+	assert.Nil(t, bc.SetTailBlock(a3))
+	status = bc.ChainStatus()
+	assert.Equal(t, a3.Height(), status.TailHeight)
+	assert.Equal(t, uint64(2), status.Reorgs)
+	assert.Equal(t, uint64(2), status.LastReorgDepth)
+	assert.Equal(t, uint64(2), status.MaxReorgDepth)
+	assert.Equal(t, uint64(6), status.AppliedBlocks)
+
+	// extending the tail isn't a reorg.
+	a4 := newBlock(a3, BlockInterval*6)
+	assert.Nil(t, bc.SetTailBlock(a4))
+	status = bc.ChainStatus()
+	assert.Equal(t, uint64(2), status.Reorgs)
+	assert.Equal(t, uint64(2), status.LastReorgDepth)
+	assert.Equal(t, uint64(7), status.AppliedBlocks)
+}
@@ -19,12 +19,17 @@
 package core
 
 import (
+	"context"
+	"encoding/json"
 	"testing"
 
+	"github.com/alexlisong/go-nebulas/core/state"
 	"github.com/alexlisong/go-nebulas/crypto"
 	"github.com/alexlisong/go-nebulas/crypto/keystore"
 	"github.com/alexlisong/go-nebulas/crypto/keystore/secp256k1"
+	"github.com/alexlisong/go-nebulas/storage"
 	"github.com/alexlisong/go-nebulas/util"
+	"github.com/alexlisong/go-nebulas/util/byteutils"
 
 	"sync"
 	"time"
@@ -83,7 +88,8 @@ func TestBlockChain_FindCommonAncestorWithTail(t *testing.T) {
 
 	tailBlock, _ := bc.cachedBlocks.Get(block12.Hash().Hex())
 	bc.tailBlock = tailBlock.(*Block)
-	assert.Nil(t, bc.buildIndexByBlockHeight(bc.genesisBlock, bc.tailBlock))
+	_, err = bc.buildIndexByBlockHeight(bc.genesisBlock, bc.tailBlock)
+	assert.Nil(t, err)
 	block221, _ := bc.NewBlock(coinbase221)
 	block221.header.timestamp = BlockInterval * 5
 	block222, _ := bc.NewBlock(coinbase222)
@@ -104,7 +110,10 @@ func TestBlockChain_FindCommonAncestorWithTail(t *testing.T) {
 
 	tailBlock, _ = bc.cachedBlocks.Get(block222.Hash().Hex())
 	bc.tailBlock = tailBlock.(*Block)
-	assert.Nil(t, bc.buildIndexByBlockHeight(bc.genesisBlock, bc.tailBlock))
+	_, err = bc.buildIndexByBlockHeight(bc.genesisBlock, bc.tailBlock)
+	assert.Nil(t, err)
+	// the cleanup keeps the forks above LIB.
+	assert.Equal(t, 0, bc.CleanDetachedTails())
 	common2, err := bc.FindCommonAncestorWithTail(block221)
 	assert.Nil(t, err)
 	assert.Equal(t, common2.String(), block12.String())
@@ -125,6 +134,7 @@ func TestBlockChain_FindCommonAncestorWithTail(t *testing.T) {
 	assert.Equal(t, result, "["+block222.String()+","+block12.String()+","+block0.String()+","+bc.genesisBlock.String()+"]")
 
 	assert.Nil(t, bc.BlockPool().Push(block1111))
+	assert.Equal(t, 0, bc.CleanDetachedTails())
 	tails := bc.DetachedTailBlocks()
 	for _, v := range tails {
 		if v.Hash().Equals(block221.Hash()) ||
@@ -143,6 +153,113 @@ func TestBlockChain_FindCommonAncestorWithTail(t *testing.T) {
 	assert.Nil(t, bc.BlockPool().Push(block11111))
 }
 
+func TestBlockChain_CanonicalHeightIndex(t *testing.T) {
+	neb := testNeb(t)
+	bc := neb.chain
+	genesis := bc.genesisBlock
+
+	coinbase := mockAddress()
+	timestamp := int64(0)
+	newBlock := func(parent *Block) *Block {
+		block, err := bc.NewBlockFromParent(coinbase, parent)
+		assert.Nil(t, err)
+		timestamp += BlockInterval
+		block.header.timestamp = timestamp
+		assert.Nil(t, block.Seal())
+		assert.Nil(t, bc.StoreBlockToStorage(block))
+		return block
+	}
+
+	/*
+		genesis -- 0 -- 11 -- 111 -- 1111
+					 \_ 12 -- 221
+					       \_ 222
+	*/
+	block0 := newBlock(genesis)
+	block11 := newBlock(block0)
+	block12 := newBlock(block0)
+	block111 := newBlock(block11)
+	block1111 := newBlock(block111)
+	block221 := newBlock(block12)
+	block222 := newBlock(block12)
+	all := []*Block{genesis, block0, block11, block12, block111, block1111, block221, block222}
+
+	// the index always matches the chain reachable from tail.
+	assertIndex := func(tail *Block) {
+		assert.Nil(t, bc.SetTailBlock(tail))
+		canonical := make(map[byteutils.HexHash]bool)
+		for block := tail; block != nil; block = bc.GetBlock(block.ParentHash()) {
+			canonical[block.Hash().Hex()] = true
+			byHeight := bc.GetBlockOnCanonicalChainByHeight(block.Height())
+			assert.NotNil(t, byHeight)
+			assert.Equal(t, block.Hash(), byHeight.Hash())
+			if CheckGenesisBlock(block) {
+				break
+			}
+		}
+		for _, block := range all {
+			byHash := bc.GetBlockOnCanonicalChainByHash(block.Hash())
+			assert.Equal(t, canonical[block.Hash().Hex()], byHash != nil, block.String())
+		}
+		for height := tail.Height() + 1; height <= block1111.Height(); height++ {
+			assert.Nil(t, bc.GetBlockOnCanonicalChainByHeight(height))
+			_, err := bc.storage.Get(heightStorageKey(height))
+			assert.Equal(t, storage.ErrKeyNotFound, err)
+		}
+	}
+	assertIndex(block11)
+	assertIndex(block222)
+	assertIndex(block1111)
+	assertIndex(block221)
+	assertIndex(block111)
+	assertIndex(block0)
+}
+
+func BenchmarkBlockChain_FindCommonAncestorWithTail(b *testing.B) {
+	const depth = 10000
+
+	neb := testNeb(b)
+	bc := neb.chain
+	coinbase := mockAddress()
+
+	// genesis -- canonical 10k blocks
+	//        \_ fork 10k blocks
+	timestamp := int64(0)
+	extend := func(parent *Block, canonical bool) *Block {
+		for i := 0; i < depth; i++ {
+			block, err := bc.NewBlockFromParent(coinbase, parent)
+			if err != nil {
+				b.Fatal(err)
+			}
+			timestamp += BlockInterval
+			block.header.timestamp = timestamp
+			if err := block.Seal(); err != nil {
+				b.Fatal(err)
+			}
+			if err := bc.StoreBlockToStorage(block); err != nil {
+				b.Fatal(err)
+			}
+			if canonical {
+				if err := bc.storage.Put(heightStorageKey(block.height), block.Hash()); err != nil {
+					b.Fatal(err)
+				}
+			}
+			parent = block
+		}
+		return parent
+	}
+	bc.tailBlock = extend(bc.genesisBlock, true)
+	fork := extend(bc.genesisBlock, false)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ancestor, err := bc.FindCommonAncestorWithTail(fork)
+		if err != nil || !CheckGenesisBlock(ancestor) {
+			b.Fatal(ancestor, err)
+		}
+	}
+}
+
 func TestBlockChain_SimulateTransactionExecution(t *testing.T) {
 	priv := secp256k1.GeneratePrivateKey()
 	pubdata, _ := priv.PublicKey().Encoded()
@@ -233,6 +350,427 @@ func TestSetTailBlockEvent(t *testing.T) {
 	time.Sleep(time.Millisecond * 500)
 }
 
+func TestSetTailBlockChainReorgEvent(t *testing.T) {
+	neb := testNeb(t)
+	bc := neb.chain
+	bc.eventEmitter.Start()
+	defer bc.eventEmitter.Stop()
+	reorgCh := register(bc.eventEmitter, TopicChainReorg)
+
+	coinbase := mockAddress()
+	newBlock := func(parent *Block, timestamp int64, txs ...*Transaction) *Block {
+		block, err := bc.NewBlockFromParent(coinbase, parent)
+		assert.Nil(t, err)
+		block.transactions = append(block.transactions, txs...)
+		block.header.timestamp = timestamp
+		assert.Nil(t, block.Seal())
+		assert.Nil(t, bc.StoreBlockToStorage(block))
+		return block
+	}
+	signedTx := func(nonce uint64) *Transaction {
+		tx := mockNormalTransaction(bc.chainID, nonce)
+		key, _ := keystore.DefaultKS.GetUnlocked(tx.from.String())
+		signature, _ := crypto.NewSignature(keystore.SECP256K1)
+		signature.InitSign(key.(keystore.PrivateKey))
+		assert.Nil(t, tx.Sign(signature))
+		return tx
+	}
+
+	/*
+		genesis -- 0 -- 11
+		             \_ 12 -- 221
+		                  \_ 222
+	*/
+	reverted := signedTx(1)
+	shared := signedTx(1)
+	block0 := newBlock(bc.genesisBlock, BlockInterval)
+	block11 := newBlock(block0, BlockInterval*2, reverted, shared)
+	block12 := newBlock(block0, BlockInterval*3, shared)
+	newBlock(block12, BlockInterval*5)
+	block222 := newBlock(block12, BlockInterval*6)
+
+	assert.Nil(t, bc.SetTailBlock(block11))
+	select {
+	case <-reorgCh.eventCh:
+		t.Fatal("unexpected reorg event without reverted blocks")
+	case <-time.After(time.Millisecond * 200):
+	}
+
+	assert.Nil(t, bc.SetTailBlock(block222))
+	var e *state.Event
+	select {
+	case e = <-reorgCh.eventCh:
+	case <-time.After(time.Second):
+		t.Fatal("missing reorg event")
+	}
+	reorg := &ChainReorgEvent{}
+	assert.Nil(t, json.Unmarshal([]byte(e.Data), reorg))
+	assert.Equal(t, block11.Hash().String(), reorg.OldTail)
+	assert.Equal(t, block222.Hash().String(), reorg.NewTail)
+	assert.Equal(t, block0.Hash().String(), reorg.Ancestor)
+	assert.Equal(t, []string{block11.Hash().String()}, reorg.Reverted)
+	assert.Equal(t, []string{block12.Hash().String(), block222.Hash().String()}, reorg.Applied)
+
+	// only the tx missing in the new fork is given back to tx pool.
+	bc.txPool.handleChainReorg(e)
+	assert.NotNil(t, bc.txPool.GetTransaction(reverted.Hash()))
+	assert.Nil(t, bc.txPool.GetTransaction(shared.Hash()))
+}
+
+func TestBlockChain_GetTransactionByHash(t *testing.T) {
+	neb := testNeb(t)
+	bc := neb.chain
+	genesis := bc.genesisBlock
+
+	coinbase := mockAddress()
+	newBlock := func(parent *Block, timestamp int64, txs ...*Transaction) *Block {
+		block, err := bc.NewBlockFromParent(coinbase, parent)
+		assert.Nil(t, err)
+		block.transactions = append(block.transactions, txs...)
+		block.header.timestamp = timestamp
+		assert.Nil(t, block.Seal())
+		assert.Nil(t, bc.StoreBlockToStorage(block))
+		return block
+	}
+	assertIndexed := func(tx *Transaction, expected *Block) {
+		found, block, err := bc.GetTransactionByHash(tx.Hash())
+		if expected == nil {
+			assert.Equal(t, storage.ErrKeyNotFound, err)
+			return
+		}
+		assert.Nil(t, err)
+		assert.Equal(t, tx.Hash(), found.Hash())
+		assert.Equal(t, expected.Hash(), block.Hash())
+	}
+
+	/*
+		genesis -- a1 -- a2 -- a3
+		       \_ b1 -- b2
+	*/
+	tx1 := mockNormalTransaction(bc.chainID, 1)
+	tx2 := mockNormalTransaction(bc.chainID, 2)
+	tx3 := mockNormalTransaction(bc.chainID, 3)
+
+	a1 := newBlock(genesis, BlockInterval, tx1, tx2)
+	assert.Nil(t, bc.SetTailBlock(a1))
+	assertIndexed(tx1, a1)
+	assertIndexed(tx2, a1)
+	assertIndexed(tx3, nil)
+
+	// reorg to the longer branch, the index follows it.
+	b1 := newBlock(genesis, BlockInterval*2, tx1, tx3)
+	b2 := newBlock(b1, BlockInterval*3)
+	assert.Nil(t, bc.SetTailBlock(b2))
+	assertIndexed(tx1, b1)
+	assertIndexed(tx2, nil)
+	assertIndexed(tx3, b1)
+
+	a2 := newBlock(a1, BlockInterval*4)
+	a3 := newBlock(a2, BlockInterval*5)
+	assert.Nil(t, bc.SetTailBlock(a3))
+	assertIndexed(tx1, a1)
+	assertIndexed(tx2, a1)
+	assertIndexed(tx3, nil)
+}
+
+func TestBlockChain_Iterator(t *testing.T) {
+	neb := testNeb(t)
+	bc := neb.chain
+	genesis := bc.genesisBlock
+
+	coinbase := mockAddress()
+	newBlock := func(parent *Block, timestamp int64) *Block {
+		block, err := bc.NewBlockFromParent(coinbase, parent)
+		assert.Nil(t, err)
+		block.header.timestamp = timestamp
+		assert.Nil(t, block.Seal())
+		assert.Nil(t, bc.StoreBlockToStorage(block))
+		return block
+	}
+	iterate := func(it *BlockIterator) []byteutils.Hash {
+		hashes := []byteutils.Hash{}
+		for it.Next() {
+			hashes = append(hashes, it.Block().Hash())
+		}
+		return hashes
+	}
+
+	/*
+		genesis -- a1 -- a2
+		       \_ b1 -- b2 -- b3
+	*/
+	a1 := newBlock(genesis, BlockInterval)
+	a2 := newBlock(a1, BlockInterval*2)
+	assert.Nil(t, bc.SetTailBlock(a2))
+
+	// the range is bounded by genesis & tail.
+	it := bc.Iterator(0, 100)
+	assert.Equal(t, []byteutils.Hash{genesis.Hash(), a1.Hash(), a2.Hash()}, iterate(it))
+	assert.Nil(t, it.Err())
+	assert.Equal(t, []byteutils.Hash{a1.Hash()}, iterate(bc.Iterator(a1.Height(), a1.Height())))
+	assert.Equal(t, []byteutils.Hash{}, iterate(bc.Iterator(a2.Height(), a1.Height())))
+
+	dump, err := bc.DumpJSON(0, 2)
+	assert.Nil(t, err)
+	summaries := []*BlockSummary{}
+	assert.Nil(t, json.Unmarshal([]byte(dump), &summaries))
+	assert.Equal(t, 2, len(summaries))
+	assert.Equal(t, a2.Summary(), summaries[0])
+	assert.Equal(t, a1.Summary(), summaries[1])
+	assert.Equal(t, a1.Hash().String(), summaries[0].ParentHash)
+
+	dump, err = bc.DumpJSON(1, 10)
+	assert.Nil(t, err)
+	summaries = []*BlockSummary{}
+	assert.Nil(t, json.Unmarshal([]byte(dump), &summaries))
+	assert.Equal(t, []*BlockSummary{a1.Summary(), genesis.Summary()}, summaries)
+
+	dump, err = bc.DumpJSON(3, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, "[]", dump)
+
+	// the iterator stops if the iterated blocks are reverted.
+	it = bc.Iterator(0, a2.Height())
+	assert.True(t, it.Next())
+	assert.True(t, it.Next())
+	assert.Equal(t, a1.Hash(), it.Block().Hash())
+
+	b1 := newBlock(genesis, BlockInterval*3)
+	b2 := newBlock(b1, BlockInterval*4)
+	b3 := newBlock(b2, BlockInterval*5)
+	assert.Nil(t, bc.SetTailBlock(b3))
+	assert.False(t, it.Next())
+	assert.Equal(t, ErrCanonicalChainChanged, it.Err())
+
+	it = bc.Iterator(0, 100)
+	assert.Equal(t, []byteutils.Hash{genesis.Hash(), b1.Hash(), b2.Hash(), b3.Hash()}, iterate(it))
+	assert.Nil(t, it.Err())
+}
+
+func TestBlockChain_FetchDescendantInCanonicalChainCh(t *testing.T) {
+	neb := testNeb(t)
+	bc := neb.chain
+	genesis := bc.genesisBlock
+
+	coinbase := mockAddress()
+	newBlock := func(parent *Block, timestamp int64) *Block {
+		block, err := bc.NewBlockFromParent(coinbase, parent)
+		assert.Nil(t, err)
+		block.header.timestamp = timestamp
+		assert.Nil(t, block.Seal())
+		assert.Nil(t, bc.StoreBlockToStorage(block))
+		return block
+	}
+	fetch := func(ctx context.Context, n int, block *Block) ([]byteutils.Hash, error) {
+		hashes := []byteutils.Hash{}
+		blockCh, errCh := bc.FetchDescendantInCanonicalChainCh(ctx, n, block)
+		for block := range blockCh {
+			hashes = append(hashes, block.Hash())
+		}
+		return hashes, <-errCh
+	}
+
+	/*
+		genesis -- a1 -- a2 -- a3
+		       \_ b1
+	*/
+	a1 := newBlock(genesis, BlockInterval)
+	a2 := newBlock(a1, BlockInterval*2)
+	a3 := newBlock(a2, BlockInterval*3)
+	b1 := newBlock(genesis, BlockInterval*4)
+	assert.Nil(t, bc.SetTailBlock(a3))
+
+	hashes, err := fetch(context.Background(), 2, genesis)
+	assert.Nil(t, err)
+	assert.Equal(t, []byteutils.Hash{a1.Hash(), a2.Hash()}, hashes)
+
+	// the stream stops at tail.
+	hashes, err = fetch(context.Background(), 10, a1)
+	assert.Nil(t, err)
+	assert.Equal(t, []byteutils.Hash{a2.Hash(), a3.Hash()}, hashes)
+
+	hashes, err = fetch(context.Background(), 10, a3)
+	assert.Nil(t, err)
+	assert.Equal(t, []byteutils.Hash{}, hashes)
+
+	_, err = fetch(context.Background(), 10, b1)
+	assert.Equal(t, ErrNotBlockInCanonicalChain, err)
+
+	// the stream stops once ctx is cancelled.
+	ctx, cancel := context.WithCancel(context.Background())
+	blockCh, errCh := bc.FetchDescendantInCanonicalChainCh(ctx, 10, genesis)
+	assert.Equal(t, a1.Hash(), (<-blockCh).Hash())
+	cancel()
+	assert.Equal(t, context.Canceled, <-errCh)
+	_, ok := <-blockCh
+	assert.False(t, ok)
+}
+
+func TestBlockChain_CleanDetachedTails(t *testing.T) {
+	neb := testNeb(t)
+	bc := neb.chain
+	genesis := bc.genesisBlock
+
+	coinbase := mockAddress()
+	newBlock := func(parent *Block, timestamp int64) *Block {
+		block, err := bc.NewBlockFromParent(coinbase, parent)
+		assert.Nil(t, err)
+		block.header.timestamp = timestamp
+		assert.Nil(t, block.Seal())
+		assert.Nil(t, bc.putVerifiedNewBlocks(parent, []*Block{block}, []*Block{block}))
+		return block
+	}
+	cached := func(block *Block) bool {
+		return bc.cachedBlocks.Contains(block.Hash().Hex())
+	}
+
+	/*
+		genesis -- a1 -- a2 -- a3 -- a4 tail
+		      |     \_ d2 -- d3 -- d4 -- d5
+		      |\_ b1 -- b2
+		      |     |     \_ c3
+		      |      \_ b3
+		       \_ e1
+	*/
+	a1 := newBlock(genesis, BlockInterval)
+	a2 := newBlock(a1, BlockInterval*2)
+	a3 := newBlock(a2, BlockInterval*3)
+	a4 := newBlock(a3, BlockInterval*4)
+	assert.Nil(t, bc.SetTailBlock(a4))
+	d2 := newBlock(a1, BlockInterval*5)
+	d3 := newBlock(d2, BlockInterval*6)
+	d4 := newBlock(d3, BlockInterval*7)
+	d5 := newBlock(d4, BlockInterval*8)
+	b1 := newBlock(genesis, BlockInterval*9)
+	b2 := newBlock(b1, BlockInterval*10)
+	c3 := newBlock(b2, BlockInterval*11)
+	b3 := newBlock(b1, BlockInterval*12)
+	e1 := newBlock(genesis, BlockInterval*13)
+	assert.Equal(t, 5, len(bc.DetachedTailBlocks()))
+
+	stats := bc.ForkStats()
+	assert.Equal(t, 4, len(stats))
+	assert.Equal(t, &ForkStat{
+		Tip:              d5.Hash().String(),
+		Height:           d5.Height(),
+		Divergence:       a1.Hash().String(),
+		DivergenceHeight: a1.Height(),
+	}, stats[0])
+	assert.Equal(t, c3.Hash().String(), stats[1].Tip)
+	assert.Equal(t, genesis.Hash().String(), stats[1].Divergence)
+
+	// nothing is below LIB.
+	assert.Equal(t, 0, bc.CleanDetachedTails())
+
+	bc.lib = a3
+	assert.Equal(t, 2, bc.CleanDetachedTails())
+	tails := []byteutils.Hash{}
+	for _, tail := range bc.DetachedTailBlocks() {
+		tails = append(tails, tail.Hash())
+	}
+	assert.ElementsMatch(t, []byteutils.Hash{a4.Hash(), d5.Hash(), c3.Hash()}, tails)
+
+	// only the blocks exclusive to the dropped forks are released.
+	assert.False(t, cached(e1))
+	assert.False(t, cached(b3))
+	for _, block := range []*Block{a1, a2, a3, a4, d2, d3, d4, d5, b1, b2, c3} {
+		assert.True(t, cached(block))
+	}
+	assert.Equal(t, 2, len(bc.ForkStats()))
+
+	// the canonical chain is kept when all forks fall behind LIB.
+	a5 := newBlock(a4, BlockInterval*14)
+	a6 := newBlock(a5, BlockInterval*15)
+	a7 := newBlock(a6, BlockInterval*16)
+	assert.Nil(t, bc.SetTailBlock(a7))
+	bc.lib = a6
+	assert.Equal(t, 2, bc.CleanDetachedTails())
+	assert.Equal(t, 1, len(bc.DetachedTailBlocks()))
+	assert.Equal(t, a7.Hash(), bc.DetachedTailBlocks()[0].Hash())
+	assert.Equal(t, 0, len(bc.ForkStats()))
+	for _, block := range []*Block{a1, a2, a3, a4, a5, a6, a7} {
+		assert.True(t, cached(block))
+	}
+	for _, block := range []*Block{d2, d5, b1, c3} {
+		assert.False(t, cached(block))
+	}
+	ancestor, err := bc.FindCommonAncestorWithTail(d5)
+	assert.Nil(t, err)
+	assert.Equal(t, a1.Hash(), ancestor.Hash())
+}
+
+func TestBlockChain_LatestIrreversibleBlock(t *testing.T) {
+	neb := testNeb(t)
+	bc := neb.chain
+	bc.eventEmitter.Start()
+	defer bc.eventEmitter.Stop()
+	libCh := register(bc.eventEmitter, TopicLibBlock)
+	assert.Equal(t, bc.genesisBlock.Hash(), bc.LatestIrreversibleBlock().Hash())
+
+	coinbase := mockAddress()
+	key, err := keystore.DefaultKS.GetUnlocked(coinbase.String())
+	assert.Nil(t, err)
+	signature, err := crypto.NewSignature(keystore.SECP256K1)
+	assert.Nil(t, err)
+	signature.InitSign(key.(keystore.PrivateKey))
+	for i := 0; i < 5; i++ {
+		block, err := bc.NewBlock(coinbase)
+		assert.Nil(t, err)
+		block.SetTimestamp(bc.TailBlock().Timestamp() + BlockInterval)
+		assert.Nil(t, block.Seal())
+		assert.Nil(t, block.Sign(signature))
+		assert.Nil(t, bc.BlockPool().Push(block))
+	}
+
+	lib := bc.GetBlockOnCanonicalChainByHeight(4)
+	assert.Nil(t, bc.SetLIB(lib))
+	assert.Equal(t, lib.Hash(), bc.LatestIrreversibleBlock().Hash())
+	var e *state.Event
+	select {
+	case e = <-libCh.eventCh:
+	case <-time.After(time.Second):
+		t.Fatal("missing lib event")
+	}
+	libEvent := &LIBEvent{}
+	assert.Nil(t, json.Unmarshal([]byte(e.Data), libEvent))
+	assert.Equal(t, lib.Hash().String(), libEvent.Hash)
+	assert.Equal(t, lib.Height(), libEvent.Height)
+
+	// reload the chain from storage, the lib is not reset to genesis.
+	reloaded := &mockNeb{
+		genesis:   neb.genesis,
+		config:    neb.config,
+		storage:   neb.storage,
+		emitter:   NewEventEmitter(1024),
+		consensus: new(mockConsensus),
+		am:        neb.am,
+		ns:        neb.ns,
+		nvm:       neb.nvm,
+	}
+	reloaded.chain, err = NewBlockChain(reloaded)
+	assert.Nil(t, err)
+	assert.Nil(t, reloaded.consensus.Setup(reloaded))
+	assert.Nil(t, reloaded.chain.Setup(reloaded))
+	assert.Equal(t, bc.TailBlock().Hash(), reloaded.chain.TailBlock().Hash())
+	assert.Equal(t, lib.Hash(), reloaded.chain.LatestIrreversibleBlock().Hash())
+}
+
+func mockGasPriceBlock(t *testing.T, bc *BlockChain, signature keystore.Signature, from *Address, prices ...*util.Uint128) *Block {
+	block, err := bc.NewBlock(from)
+	assert.Nil(t, err)
+	gasLimit, _ := util.NewUint128FromInt(200000)
+	for i, price := range prices {
+		tx, _ := NewTransaction(bc.ChainID(), from, from, util.NewUint128(), uint64(i+1), TxPayloadBinaryType, []byte("nas"), price, gasLimit)
+		tx.Sign(signature)
+		block.transactions = append(block.transactions, tx)
+	}
+	block.Seal()
+	block.Sign(signature)
+	bc.cachedBlocks.Add(block.Hash().Hex(), block)
+	assert.Nil(t, bc.SetTailBlock(block))
+	return block
+}
+
 func TestGetPrice(t *testing.T) {
 	neb := testNeb(t)
 	bc := neb.chain
@@ -245,20 +783,395 @@ func TestGetPrice(t *testing.T) {
 	signature, err := crypto.NewSignature(keystore.SECP256K1)
 	assert.Nil(t, err)
 	signature.InitSign(key.(keystore.PrivateKey))
-	block, err := bc.NewBlock(from)
-	assert.Nil(t, err)
 	GasPriceDetla, _ := util.NewUint128FromInt(1)
 	lowerGasPrice, err := TransactionGasPrice.Sub(GasPriceDetla)
 	assert.Nil(t, err)
-	gasLimit, _ := util.NewUint128FromInt(200000)
-	tx1, _ := NewTransaction(bc.ChainID(), from, from, util.NewUint128(), 1, TxPayloadBinaryType, []byte("nas"), lowerGasPrice, gasLimit)
-	tx1.Sign(signature)
-	tx2, _ := NewTransaction(bc.ChainID(), from, from, util.NewUint128(), 2, TxPayloadBinaryType, []byte("nas"), TransactionGasPrice, gasLimit)
-	tx2.Sign(signature)
-	block.transactions = append(block.transactions, tx1)
-	block.transactions = append(block.transactions, tx2)
-	block.Seal()
-	block.Sign(signature)
-	bc.SetTailBlock(block)
+	mockGasPriceBlock(t, bc, signature, from, lowerGasPrice, TransactionGasPrice)
 	assert.Equal(t, bc.GasPrice(), lowerGasPrice)
+
+	// a cheap transaction in one block doesn't drag down the median of the window.
+	mockGasPriceBlock(t, bc, signature, from, TransactionGasPrice)
+	mockGasPriceBlock(t, bc, signature, from, TransactionGasPrice)
+	assert.Equal(t, bc.GasPrice(), TransactionGasPrice)
+}
+
+func TestGasPriceWithPercentile(t *testing.T) {
+	neb := testNeb(t)
+	bc := neb.chain
+
+	ks := keystore.DefaultKS
+	from := mockAddress()
+	key, err := ks.GetUnlocked(from.String())
+	assert.Nil(t, err)
+	signature, err := crypto.NewSignature(keystore.SECP256K1)
+	assert.Nil(t, err)
+	signature.InitSign(key.(keystore.PrivateKey))
+
+	price := func(delta int64) *util.Uint128 {
+		d, _ := util.NewUint128FromInt(delta)
+		p, _ := TransactionGasPrice.Add(d)
+		return p
+	}
+
+	// the lowest prices of blocks are 1, 3, 5, 2, 4 above default, blocks without txs are skipped.
+	mockGasPriceBlock(t, bc, signature, from, price(1), price(10))
+	mockGasPriceBlock(t, bc, signature, from, price(3))
+	mockGasPriceBlock(t, bc, signature, from)
+	mockGasPriceBlock(t, bc, signature, from, price(9), price(5))
+	mockGasPriceBlock(t, bc, signature, from, price(2))
+	mockGasPriceBlock(t, bc, signature, from, price(4), price(8))
+
+	assert.Equal(t, 0, bc.GasPrice().Cmp(price(3)))
+	assert.Equal(t, 0, bc.GasPriceWithPercentile(0).Cmp(price(1)))
+	assert.Equal(t, 0, bc.GasPriceWithPercentile(-1).Cmp(price(1)))
+	assert.Equal(t, 0, bc.GasPriceWithPercentile(75).Cmp(price(4)))
+	assert.Equal(t, 0, bc.GasPriceWithPercentile(100).Cmp(price(5)))
+	assert.Equal(t, 0, bc.GasPriceWithPercentile(200).Cmp(price(5)))
+
+	// only the latest blocks in window are counted.
+	bc.SetGasPriceWindow(3)
+	assert.Equal(t, 0, bc.GasPriceWithPercentile(0).Cmp(price(2)))
+	assert.Equal(t, 0, bc.GasPriceWithPercentile(100).Cmp(price(5)))
+	bc.SetGasPriceWindow(1)
+	assert.Equal(t, 0, bc.GasPrice().Cmp(price(4)))
+
+	// an empty window uses the default.
+	mockGasPriceBlock(t, bc, signature, from)
+	assert.Equal(t, 0, bc.GasPrice().Cmp(TransactionGasPrice))
+}
+
+func TestBlockChain_GetNonces(t *testing.T) {
+	NonceInvariantCheck = true
+	defer func() { NonceInvariantCheck = false }()
+
+	neb := testNeb(t)
+	bc := neb.chain
+
+	ks := keystore.DefaultKS
+	from := mockAddress()
+	to := mockAddress()
+	key, err := ks.GetUnlocked(from.String())
+	assert.Nil(t, err)
+	signature, err := crypto.NewSignature(keystore.SECP256K1)
+	assert.Nil(t, err)
+	signature.InitSign(key.(keystore.PrivateKey))
+
+	// mint a block to give from some balance.
+	block, err := bc.NewBlock(from)
+	assert.Nil(t, err)
+	block.SetTimestamp(BlockInterval)
+	assert.Nil(t, block.Seal())
+	assert.Nil(t, block.Sign(signature))
+	assert.Nil(t, bc.BlockPool().Push(block))
+
+	addresses := []string{from.String(), to.String()}
+	nonces, err := bc.GetNonces(addresses, true)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint64{0, 0}, nonces)
+
+	_, err = bc.GetNonces([]string{"invalid"}, false)
+	assert.NotNil(t, err)
+
+	count := 8
+	gasLimit, _ := util.NewUint128FromInt(200000)
+	txs := make([]*Transaction, 2*count+1)
+	for i := 1; i <= 2*count; i++ {
+		tx, err := NewTransaction(bc.ChainID(), from, to, util.NewUint128(), uint64(i), TxPayloadBinaryType, []byte("nas"), TransactionGasPrice, gasLimit)
+		assert.Nil(t, err)
+		assert.Nil(t, tx.Sign(signature))
+		txs[i] = tx
+	}
+	for i := 1; i <= count; i++ {
+		assert.Nil(t, bc.txPool.Push(txs[i]))
+	}
+
+	quitCh := make(chan bool)
+	readers := new(sync.WaitGroup)
+	for i := 0; i < 4; i++ {
+		readers.Add(1)
+		go func(includePending bool) {
+			defer readers.Done()
+			for {
+				select {
+				case <-quitCh:
+					return
+				default:
+					_, err := bc.GetNonces(addresses, includePending)
+					assert.Nil(t, err)
+				}
+			}
+		}(i%2 == 0)
+	}
+
+	// pool updates.
+	writers := new(sync.WaitGroup)
+	writers.Add(1)
+	go func() {
+		defer writers.Done()
+		for i := count + 1; i <= 2*count; i++ {
+			assert.Nil(t, bc.txPool.Push(txs[i]))
+			time.Sleep(time.Millisecond * 5)
+		}
+	}()
+
+	// block linking.
+	for i := 1; i <= count; i++ {
+		block, err := bc.NewBlock(from)
+		assert.Nil(t, err)
+		block.SetTimestamp(BlockInterval * int64(i+1))
+
+		tx := txs[i]
+		txWorldState, err := block.WorldState().Prepare(tx.Hash().String())
+		assert.Nil(t, err)
+		_, err = block.ExecuteTransaction(tx, txWorldState)
+		assert.Nil(t, err)
+		_, err = txWorldState.CheckAndUpdate()
+		assert.Nil(t, err)
+		assert.Nil(t, txWorldState.Close())
+		block.transactions = append(block.transactions, tx)
+		block.dependency.AddNode(tx.Hash().String())
+
+		assert.Nil(t, block.Seal())
+		assert.Nil(t, block.Sign(signature))
+		assert.Nil(t, bc.BlockPool().Push(block))
+	}
+	writers.Wait()
+	close(quitCh)
+	readers.Wait()
+
+	nonces, err = bc.GetNonces(addresses, false)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint64{uint64(count), 0}, nonces)
+	nonces, err = bc.GetNonces(addresses, true)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint64{uint64(2 * count), 0}, nonces)
+
+	assert.Equal(t, uint64(0), bc.NonceInvariantViolations())
+}
+
+// mockBatchingEngine processes items of 10 instructions until the limit is
+// nearly reached, and fails if the limit can't afford the first 5 items.
+type mockBatchingNvm struct{}
+type mockBatchingEngine struct {
+	limit uint64
+	used  uint64
+}
+
+func (nvm *mockBatchingNvm) CreateEngine(block *Block, tx *Transaction, contract state.Account, state WorldState) (SmartContractEngine, error) {
+	return &mockBatchingEngine{}, nil
+}
+
+func (e *mockBatchingEngine) Dispose() {}
+func (e *mockBatchingEngine) SetExecutionLimits(limit uint64, memory uint64) error {
+	e.limit = limit
+	return nil
+}
+func (e *mockBatchingEngine) SetRuntimeVersion(string) error {
+	return nil
+}
+func (e *mockBatchingEngine) DeployAndInit(source, sourceType, args string) (string, error) {
+	return e.run()
+}
+func (e *mockBatchingEngine) Call(source, sourceType, function, args string) (string, error) {
+	return e.run()
+}
+func (e *mockBatchingEngine) ExecutionInstructions() uint64 {
+	return e.used
+}
+func (e *mockBatchingEngine) run() (string, error) {
+	if e.limit < 50 {
+		e.used = e.limit
+		return "", ErrExecutionFailed
+	}
+	for i := 0; i < 100 && e.used+10 <= e.limit; i++ {
+		e.used += 10
+	}
+	return "", nil
+}
+
+func TestBlockChain_EstimateGas(t *testing.T) {
+	neb := testNeb(t)
+	bc := neb.chain
+
+	from := mockAddress()
+	ks := keystore.DefaultKS
+	key, _ := ks.GetUnlocked(from.String())
+	signature, _ := crypto.NewSignature(keystore.SECP256K1)
+	signature.InitSign(key.(keystore.PrivateKey))
+	balance, _ := util.NewUint128FromString("1000000000000000000")
+
+	block, err := bc.NewBlock(bc.tailBlock.header.coinbase)
+	assert.Nil(t, err)
+	fromAcc, err := block.worldState.GetOrCreateUserAccount(from.address)
+	assert.Nil(t, err)
+	assert.Nil(t, fromAcc.AddBalance(balance))
+	block.Commit()
+
+	block, err = bc.NewBlockFromParent(bc.tailBlock.header.coinbase, block)
+	assert.Nil(t, err)
+	block.nvm = &mockBatchingNvm{}
+
+	deployTx := mockDeployTransaction(bc.chainID, 1)
+	deployTx.from = from
+	deployTx.to = from
+	deployTx.value = util.NewUint128()
+	assert.Nil(t, deployTx.Sign(signature))
+
+	// with max gas, the contract batches all the items.
+	naive, err := deployTx.simulateExecutionInTxWorldState(block, TransactionMaxGas, 0)
+	assert.Nil(t, err)
+	assert.Nil(t, naive.Err)
+	expected, err := naive.GasUsed.Sub(util.NewUint128FromUint(950))
+	assert.Nil(t, err)
+
+	for i := 0; i < 2; i++ {
+		gasLimit, result, err := deployTx.estimateGas(block)
+		assert.Nil(t, err)
+		assert.Nil(t, result.Err)
+		assert.Equal(t, expected, gasLimit)
+		assert.Equal(t, expected, result.GasUsed)
+	}
+
+	// not enough gas below the estimated limit.
+	below, err := expected.Sub(util.NewUint128FromUint(1))
+	assert.Nil(t, err)
+	result, err := deployTx.simulateExecutionInTxWorldState(block, below, 0)
+	assert.Nil(t, err)
+	assert.NotNil(t, result.Err)
+
+	// the world state is untouched.
+	fromAcc, err = block.worldState.GetOrCreateUserAccount(from.address)
+	assert.Nil(t, err)
+	assert.Equal(t, balance, fromAcc.Balance())
+	contractAddr, err := deployTx.GenerateContractAddress()
+	assert.Nil(t, err)
+	_, err = block.worldState.GetContractAccount(contractAddr.Bytes())
+	assert.NotNil(t, err)
+
+	// binary tx is simulated once with max gas.
+	transferTx := mockNormalTransaction(bc.chainID, 1)
+	transferTx.from = from
+	assert.Nil(t, transferTx.Sign(signature))
+	gasLimit, result, err := transferTx.estimateGas(block)
+	assert.Nil(t, err)
+	assert.Nil(t, result.Err)
+	assert.Equal(t, MinGasCountPerTransaction, gasLimit)
+}
+
+func TestBlockChain_EstimateGasWithConcurrentTail(t *testing.T) {
+	neb := testNeb(t)
+	bc := neb.chain
+
+	from := mockAddress()
+	balance, _ := util.NewUint128FromString("1000000000000000000")
+	bc.tailBlock.Begin()
+	fromAcc, err := bc.tailBlock.worldState.GetOrCreateUserAccount(from.address)
+	assert.Nil(t, err)
+	assert.Nil(t, fromAcc.AddBalance(balance))
+	bc.tailBlock.Commit()
+	bc.tailBlock.header.stateRoot = bc.tailBlock.worldState.AccountsRoot()
+	assert.Nil(t, bc.StoreBlockToStorage(bc.tailBlock))
+
+	parent := bc.TailBlock()
+	blocks := []*Block{}
+	for i := 0; i < 16; i++ {
+		block, err := bc.NewBlockFromParent(parent.header.coinbase, parent)
+		assert.Nil(t, err)
+		block.header.timestamp = parent.Timestamp() + BlockIntervalInSecond
+		assert.Nil(t, block.Seal())
+		assert.Nil(t, bc.StoreBlockToStorage(block))
+		bc.cachedBlocks.Add(block.Hash().Hex(), block)
+		blocks = append(blocks, block)
+		parent = block
+	}
+
+	tx := mockNormalTransaction(bc.chainID, 1)
+	tx.from = from
+	assert.Nil(t, tx.Sign(mockSignature(t, from)))
+
+	// the tail is advanced while the gas is estimated, run with -race.
+	wg := new(sync.WaitGroup)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for _, block := range blocks {
+			assert.Nil(t, bc.SetTailBlock(block))
+		}
+	}()
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 16; j++ {
+				gasLimit, result, err := bc.EstimateGas(tx)
+				assert.Nil(t, err)
+				assert.Nil(t, result.Err)
+				assert.Equal(t, MinGasCountPerTransaction, gasLimit)
+
+				snapshot, err := bc.TailSnapshot()
+				assert.Nil(t, err)
+				acc, err := snapshot.WorldState().GetOrCreateUserAccount(from.address)
+				assert.Nil(t, err)
+				assert.Equal(t, balance, acc.Balance())
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, blocks[len(blocks)-1], bc.TailBlock())
+}
+
+func TestBlockChain_SendTransactionSafe(t *testing.T) {
+	neb := testNeb(t)
+	bc := neb.chain
+
+	from := mockAddress()
+	ks := keystore.DefaultKS
+	key, _ := ks.GetUnlocked(from.String())
+	signature, _ := crypto.NewSignature(keystore.SECP256K1)
+	signature.InitSign(key.(keystore.PrivateKey))
+	sign := func(tx *Transaction) error {
+		return tx.Sign(signature)
+	}
+
+	balance, _ := util.NewUint128FromString("1000000000000000000")
+	bc.tailBlock.Begin()
+	fromAcc, err := bc.tailBlock.worldState.GetOrCreateUserAccount(from.address)
+	assert.Nil(t, err)
+	assert.Nil(t, fromAcc.AddBalance(balance))
+	bc.tailBlock.Commit()
+	bc.tailBlock.header.stateRoot = bc.tailBlock.worldState.AccountsRoot()
+	assert.Nil(t, bc.StoreBlockToStorage(bc.tailBlock))
+
+	// failing contract call never reaches the pool.
+	callTx := mockCallTransaction(bc.chainID, 1, "totalSupply", "")
+	callTx.from = from
+	summary, err := bc.SendTransactionSafe(callTx, &SafeSendOptions{FillGasLimit: true}, sign)
+	assert.Equal(t, ErrSimulationFailed, err)
+	assert.NotNil(t, summary.Err)
+	assert.True(t, bc.txPool.Empty())
+
+	// fee cap aborts submission.
+	deployTx := mockDeployTransaction(bc.chainID, 1)
+	deployTx.from, deployTx.to = from, from
+	summary, err = bc.SendTransactionSafe(deployTx, &SafeSendOptions{FillGasLimit: true, MaxFee: util.NewUint128FromUint(1)}, sign)
+	assert.Equal(t, ErrEstimatedFeeExceedsMax, err)
+	assert.Nil(t, summary.Err)
+	assert.True(t, bc.txPool.Empty())
+
+	// succeeding deploy is sent with the estimated gas limit, and mined.
+	summary, err = bc.SendTransactionSafe(deployTx, &SafeSendOptions{FillGasLimit: true, MaxFee: summary.Fee}, sign)
+	assert.Nil(t, err)
+	assert.Equal(t, summary.GasLimit, summary.Tx.GasLimit())
+	gasUsed, err := summary.BaseGas.Add(summary.PayloadGas)
+	assert.Nil(t, err)
+	gasUsed, err = gasUsed.Add(summary.ExecutionGas)
+	assert.Nil(t, err)
+	assert.Equal(t, summary.GasUsed, gasUsed)
+	assert.NotNil(t, bc.txPool.GetTransaction(summary.Tx.Hash()))
+
+	block, err := bc.NewBlock(bc.tailBlock.header.coinbase)
+	assert.Nil(t, err)
+	block.CollectTransactions(time.Now().Unix()*1000 + 1000)
+	assert.Equal(t, 1, len(block.transactions))
+	assert.Equal(t, summary.Tx.Hash(), block.transactions[0].Hash())
 }
@@ -0,0 +1,69 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"github.com/alexlisong/go-nebulas/crypto/hash"
+)
+
+const (
+	// BloomByteLength the length of bloom in bytes, 2048 bits.
+	BloomByteLength = 256
+
+	// bloomHashCount the count of bits set for each added data.
+	bloomHashCount = 3
+)
+
+// Bloom is a fixed size bloom filter, it's deterministic to be hashed in block header.
+type Bloom []byte
+
+// NewBloom return an empty bloom.
+func NewBloom() Bloom {
+	return make(Bloom, BloomByteLength)
+}
+
+// bloomBits return the bits of data, each is taken from 11 bits of data's hash.
+func bloomBits(data []byte) [bloomHashCount]uint {
+	var bits [bloomHashCount]uint
+	sum := hash.Sha3256(data)
+	for i := 0; i < bloomHashCount; i++ {
+		bits[i] = (uint(sum[2*i])<<8 | uint(sum[2*i+1])) % (BloomByteLength * 8)
+	}
+	return bits
+}
+
+// Add add data into bloom.
+func (b Bloom) Add(data []byte) {
+	for _, bit := range bloomBits(data) {
+		b[bit/8] |= 1 << (bit % 8)
+	}
+}
+
+// Test return false if data is surely not in bloom, true if it may be.
+func (b Bloom) Test(data []byte) bool {
+	if len(b) != BloomByteLength {
+		return false
+	}
+	for _, bit := range bloomBits(data) {
+		if b[bit/8]&(1<<(bit%8)) == 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,182 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"sync"
+
+	"github.com/alexlisong/go-nebulas/core/pb"
+	"github.com/alexlisong/go-nebulas/util"
+)
+
+// ChainConfig is the consensus config of a chain defined in genesis,
+// the package level vars are the defaults.
+type ChainConfig struct {
+	ChainID                   uint32
+	TransactionMaxGasPrice    *util.Uint128
+	MinGasCountPerTransaction *util.Uint128
+	GasCountPerByte           *util.Uint128
+	MaxDataPayLoadLength      int
+	FreezeAdmins              []*Address
+	BlockGasLimit             *util.Uint128
+}
+
+var (
+	chainConfigsLock = sync.RWMutex{}
+	chainConfigs     = make(map[uint32]*ChainConfig)
+)
+
+// DefaultChainConfig return the config of chainID with the default values.
+func DefaultChainConfig(chainID uint32) *ChainConfig {
+	return &ChainConfig{
+		ChainID:                   chainID,
+		TransactionMaxGasPrice:    TransactionMaxGasPrice,
+		MinGasCountPerTransaction: MinGasCountPerTransaction,
+		GasCountPerByte:           GasCountPerByte,
+		MaxDataPayLoadLength:      MaxDataPayLoadLength,
+		BlockGasLimit:             BlockGasLimit,
+	}
+}
+
+// NewChainConfig create the chain config from genesis, the omitted values are the defaults.
+func NewChainConfig(genesis *corepb.Genesis) (*ChainConfig, error) {
+	if genesis == nil || genesis.Meta == nil {
+		return nil, ErrNilArgument
+	}
+	config := DefaultChainConfig(genesis.Meta.ChainId)
+	conf := genesis.ChainConfig
+	if conf == nil {
+		return config, nil
+	}
+
+	var err error
+	if len(conf.TransactionMaxGasPrice) > 0 {
+		if config.TransactionMaxGasPrice, err = parseChainConfigValue(conf.TransactionMaxGasPrice); err != nil {
+			return nil, err
+		}
+	}
+	if len(conf.MinGasCountPerTransaction) > 0 {
+		if config.MinGasCountPerTransaction, err = parseChainConfigValue(conf.MinGasCountPerTransaction); err != nil {
+			return nil, err
+		}
+	}
+	if len(conf.GasCountPerByte) > 0 {
+		if config.GasCountPerByte, err = util.NewUint128FromString(conf.GasCountPerByte); err != nil {
+			return nil, ErrInvalidChainConfig
+		}
+	}
+	if conf.MaxDataPayloadLength > 0 {
+		config.MaxDataPayLoadLength = int(conf.MaxDataPayloadLength)
+	}
+	for _, v := range conf.FreezeAdmins {
+		admin, err := AddressParse(v)
+		if err != nil {
+			return nil, ErrInvalidChainConfig
+		}
+		config.FreezeAdmins = append(config.FreezeAdmins, admin)
+	}
+	if len(conf.BlockGasLimit) > 0 {
+		if config.BlockGasLimit, err = parseChainConfigValue(conf.BlockGasLimit); err != nil {
+			return nil, err
+		}
+	}
+	return config, nil
+}
+
+// parseChainConfigValue parses a positive uint128 value.
+func parseChainConfigValue(value string) (*util.Uint128, error) {
+	v, err := util.NewUint128FromString(value)
+	if err != nil || v.Cmp(util.NewUint128()) <= 0 {
+		return nil, ErrInvalidChainConfig
+	}
+	return v, nil
+}
+
+// IsDefault return if all values of config are the defaults.
+func (config *ChainConfig) IsDefault() bool {
+	return config.Equals(DefaultChainConfig(config.ChainID))
+}
+
+// Equals return if the values of config & other are the same.
+func (config *ChainConfig) Equals(other *ChainConfig) bool {
+	return config.ChainID == other.ChainID &&
+		config.TransactionMaxGasPrice.Cmp(other.TransactionMaxGasPrice) == 0 &&
+		config.MinGasCountPerTransaction.Cmp(other.MinGasCountPerTransaction) == 0 &&
+		config.GasCountPerByte.Cmp(other.GasCountPerByte) == 0 &&
+		config.MaxDataPayLoadLength == other.MaxDataPayLoadLength &&
+		equalAddresses(config.FreezeAdmins, other.FreezeAdmins) &&
+		config.BlockGasLimit.Cmp(other.BlockGasLimit) == 0
+}
+
+func equalAddresses(a, b []*Address) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equals(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsFreezeAdmin return if addr is allowed to freeze & unfreeze accounts.
+func (config *ChainConfig) IsFreezeAdmin(addr *Address) bool {
+	for _, admin := range config.FreezeAdmins {
+		if admin.Equals(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ToProto converts config to the genesis chain config with all values.
+func (config *ChainConfig) ToProto() *corepb.GenesisChainConfig {
+	pbConfig := &corepb.GenesisChainConfig{
+		TransactionMaxGasPrice:    config.TransactionMaxGasPrice.String(),
+		MinGasCountPerTransaction: config.MinGasCountPerTransaction.String(),
+		GasCountPerByte:           config.GasCountPerByte.String(),
+		MaxDataPayloadLength:      uint32(config.MaxDataPayLoadLength),
+	}
+	for _, admin := range config.FreezeAdmins {
+		pbConfig.FreezeAdmins = append(pbConfig.FreezeAdmins, admin.String())
+	}
+	// omitted if default, keep the genesis hash of the chains configured before it.
+	if config.BlockGasLimit.Cmp(BlockGasLimit) != 0 {
+		pbConfig.BlockGasLimit = config.BlockGasLimit.String()
+	}
+	return pbConfig
+}
+
+// RegisterChainConfig register the config of its chain, which is used by the txs of the chain.
+func RegisterChainConfig(config *ChainConfig) {
+	chainConfigsLock.Lock()
+	defer chainConfigsLock.Unlock()
+	chainConfigs[config.ChainID] = config
+}
+
+// GetChainConfig return the registered config of chainID, or the default one.
+func GetChainConfig(chainID uint32) *ChainConfig {
+	chainConfigsLock.RLock()
+	defer chainConfigsLock.RUnlock()
+	if config, ok := chainConfigs[chainID]; ok {
+		return config
+	}
+	return DefaultChainConfig(chainID)
+}
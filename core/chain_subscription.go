@@ -0,0 +1,305 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/alexlisong/go-nebulas/core/state"
+	"github.com/alexlisong/go-nebulas/util/byteutils"
+	"github.com/alexlisong/go-nebulas/util/logging"
+	"github.com/sirupsen/logrus"
+)
+
+// ChainSubscriptionBufferSize is the max count of events buffered for a subscriber,
+// the subscription fails with ErrSubscriptionOverflow beyond it.
+var ChainSubscriptionBufferSize = 1024
+
+// ChainEvent is an event of a canonical block, Removed is set when the block is reverted by a reorg.
+type ChainEvent struct {
+	Event   *state.Event
+	Height  uint64
+	Block   byteutils.Hash
+	Tx      byteutils.Hash
+	Removed bool
+}
+
+// ChainSubscription delivers the events of canonical chain in order. The events of reverted blocks
+// are delivered again with Removed set, before the events of the blocks replacing them.
+type ChainSubscription struct {
+	bc     *BlockChain
+	topics []string
+
+	// the blocks lower than it are not delivered.
+	startHeight uint64
+
+	eventCh  chan *ChainEvent
+	notifyCh chan bool
+	quitCh   chan bool
+	quitOnce sync.Once
+
+	mu    sync.Mutex
+	queue []*ChainEvent
+	err   error
+}
+
+// SubscribeEvents subscribe the events of canonical chain whose topic is one of topics or starts with it
+// and a dot, all events if topics is empty. The events since fromHeight are replayed first, none if it's 0.
+func (bc *BlockChain) SubscribeEvents(topics []string, fromHeight uint64) (*ChainSubscription, error) {
+	bc.subscriptionsMu.Lock()
+	defer bc.subscriptionsMu.Unlock()
+
+	tail := bc.TailBlock()
+	if fromHeight > tail.height+1 {
+		return nil, ErrInvalidFilterRange
+	}
+
+	sub := &ChainSubscription{
+		bc:          bc,
+		topics:      topics,
+		startHeight: fromHeight,
+		eventCh:     make(chan *ChainEvent),
+		notifyCh:    make(chan bool, 1),
+		quitCh:      make(chan bool),
+	}
+	if fromHeight == 0 {
+		sub.startHeight = tail.height + 1
+	}
+	bc.subscriptions[sub] = true
+	go sub.loop(tail, fromHeight)
+	return sub, nil
+}
+
+// Events return the chan of events, which is closed when the subscription ends.
+func (sub *ChainSubscription) Events() <-chan *ChainEvent {
+	return sub.eventCh
+}
+
+// Err return the error ending the subscription, nil if it's alive or unsubscribed.
+func (sub *ChainSubscription) Err() error {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	return sub.err
+}
+
+// Unsubscribe stop the subscription.
+func (sub *ChainSubscription) Unsubscribe() {
+	sub.bc.subscriptionsMu.Lock()
+	delete(sub.bc.subscriptions, sub)
+	sub.bc.subscriptionsMu.Unlock()
+
+	sub.quitOnce.Do(func() {
+		close(sub.quitCh)
+	})
+}
+
+func (sub *ChainSubscription) match(topic string) bool {
+	if len(sub.topics) == 0 {
+		return true
+	}
+	for _, t := range sub.topics {
+		if topic == t || strings.HasPrefix(topic, t+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// push queue the events without blocking, the subscription fails if the queue overflows.
+func (sub *ChainSubscription) push(events []*ChainEvent) {
+	sub.mu.Lock()
+	if sub.err != nil {
+		sub.mu.Unlock()
+		return
+	}
+	if len(sub.queue)+len(events) > ChainSubscriptionBufferSize {
+		sub.mu.Unlock()
+		logging.VLog().WithFields(logrus.Fields{
+			"topics": sub.topics,
+			"queued": len(sub.queue),
+		}).Warn("Chain event subscriber falls behind.")
+		sub.fail(ErrSubscriptionOverflow)
+		return
+	}
+	sub.queue = append(sub.queue, events...)
+	sub.mu.Unlock()
+	sub.notify()
+}
+
+// fail end the subscription with err after the queued events are delivered.
+func (sub *ChainSubscription) fail(err error) {
+	sub.mu.Lock()
+	if sub.err == nil {
+		sub.err = err
+	}
+	sub.mu.Unlock()
+	sub.notify()
+}
+
+func (sub *ChainSubscription) notify() {
+	select {
+	case sub.notifyCh <- true:
+	default:
+	}
+}
+
+func (sub *ChainSubscription) send(events []*ChainEvent) bool {
+	for _, e := range events {
+		select {
+		case sub.eventCh <- e:
+		case <-sub.quitCh:
+			return false
+		}
+	}
+	return true
+}
+
+func (sub *ChainSubscription) loop(tail *Block, fromHeight uint64) {
+	defer close(sub.eventCh)
+	defer sub.Unsubscribe()
+
+	if fromHeight > 0 {
+		if err := sub.replay(tail, fromHeight); err != nil {
+			sub.fail(err)
+			return
+		}
+	}
+
+	for {
+		sub.mu.Lock()
+		events, err := sub.queue, sub.err
+		sub.queue = nil
+		sub.mu.Unlock()
+
+		if !sub.send(events) || err != nil {
+			return
+		}
+
+		select {
+		case <-sub.notifyCh:
+		case <-sub.quitCh:
+			return
+		}
+	}
+}
+
+// replay deliver the events of blocks in [fromHeight, tail], the chain of tail is kept even if it's reorged
+// during replay, the reverted blocks are notified after it.
+func (sub *ChainSubscription) replay(tail *Block, fromHeight uint64) error {
+	hashes := []byteutils.Hash{}
+	for block := tail; block.height >= fromHeight; {
+		hashes = append(hashes, block.Hash())
+		if block.height == fromHeight {
+			break
+		}
+		if block = sub.bc.GetBlock(block.ParentHash()); block == nil {
+			return ErrMissingParentBlock
+		}
+	}
+
+	for i := len(hashes) - 1; i >= 0; i-- {
+		block := sub.bc.GetBlock(hashes[i])
+		if block == nil {
+			return ErrBlockNotFound
+		}
+		events, err := sub.blockEvents(block, false)
+		if err != nil {
+			return err
+		}
+		if !sub.send(events) {
+			return nil
+		}
+	}
+	return nil
+}
+
+// blockEvents return the matched events of block, in reversed order if they are removed.
+func (sub *ChainSubscription) blockEvents(block *Block, removed bool) ([]*ChainEvent, error) {
+	if block.statePruned {
+		return nil, ErrStatePruned
+	}
+	matched := []*ChainEvent{}
+	for _, tx := range block.transactions {
+		events, err := fetchEventsWithExecutionResult(tx.hash, block.WorldState())
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range events {
+			if !sub.match(event.Topic) {
+				continue
+			}
+			matched = append(matched, &ChainEvent{
+				Event:   event,
+				Height:  block.height,
+				Block:   block.Hash(),
+				Tx:      tx.hash,
+				Removed: removed,
+			})
+		}
+	}
+	if removed {
+		for i, j := 0, len(matched)-1; i < j; i, j = i+1, j-1 {
+			matched[i], matched[j] = matched[j], matched[i]
+		}
+	}
+	return matched, nil
+}
+
+// chainEvents return the events of reverted blocks to remove and applied blocks to add,
+// reverted is ordered from old tail, applied is ordered from new tail.
+func (sub *ChainSubscription) chainEvents(reverted, applied []*Block) ([]*ChainEvent, error) {
+	events := []*ChainEvent{}
+	for _, block := range reverted {
+		if block.height < sub.startHeight {
+			continue
+		}
+		removed, err := sub.blockEvents(block, true)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, removed...)
+	}
+	for i := len(applied) - 1; i >= 0; i-- {
+		if applied[i].height < sub.startHeight {
+			continue
+		}
+		added, err := sub.blockEvents(applied[i], false)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, added...)
+	}
+	return events, nil
+}
+
+// publishChainEvents push the events of a tail update to subscribers.
+func (bc *BlockChain) publishChainEvents(reverted, applied []*Block) {
+	for sub := range bc.subscriptions {
+		if sub.Err() != nil {
+			continue
+		}
+		events, err := sub.chainEvents(reverted, applied)
+		if err != nil {
+			sub.fail(err)
+			continue
+		}
+		sub.push(events)
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alexlisong/go-nebulas/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockChain_SubscribeEvents(t *testing.T) {
+	neb := testNeb(t)
+	bc := neb.chain
+	bc.nvm = &replayNvm{}
+	bc.tailBlock.nvm = bc.nvm
+
+	from := mockAddress()
+	signature := mockSignature(t, from)
+	balance, _ := util.NewUint128FromString("1000000000000000000")
+	bc.tailBlock.Begin()
+	acc, err := bc.tailBlock.worldState.GetOrCreateUserAccount(from.address)
+	assert.Nil(t, err)
+	assert.Nil(t, acc.AddBalance(balance))
+	bc.tailBlock.Commit()
+	bc.tailBlock.header.stateRoot = bc.tailBlock.worldState.AccountsRoot()
+	assert.Nil(t, bc.StoreBlockToStorage(bc.tailBlock))
+
+	mint := func(parent *Block, txs ...*Transaction) *Block {
+		block, err := bc.NewBlockFromParent(mockAddress(), parent)
+		assert.Nil(t, err)
+		block.header.timestamp = parent.Timestamp() + BlockIntervalInSecond
+		for _, tx := range txs {
+			txWorldState, err := block.WorldState().Prepare(tx.Hash().String())
+			assert.Nil(t, err)
+			_, err = block.ExecuteTransaction(tx, txWorldState)
+			assert.Nil(t, err)
+			_, err = txWorldState.CheckAndUpdate()
+			assert.Nil(t, err)
+			txWorldState.Close()
+			assert.Nil(t, block.dependency.AddNode(tx.Hash().String()))
+			block.transactions = append(block.transactions, tx)
+		}
+		assert.Nil(t, block.Seal())
+		signBlock(block)
+		assert.Nil(t, bc.BlockPool().Push(block))
+		return bc.GetBlock(block.Hash())
+	}
+
+	deployTx := mockDeployTransaction(bc.ChainID(), 1)
+	deployTx.from, deployTx.to = from, from
+	assert.Nil(t, deployTx.Sign(signature))
+	contract, err := deployTx.GenerateContractAddress()
+	assert.Nil(t, err)
+	call := func(nonce uint64, function string) *Transaction {
+		tx := mockCallTransaction(bc.ChainID(), nonce, function, "")
+		tx.from, tx.to = from, contract
+		assert.Nil(t, tx.Sign(signature))
+		return tx
+	}
+
+	deployed := mint(bc.TailBlock(), deployTx)
+	forkA := mint(deployed, call(2, "a"))
+	forkB1 := mint(deployed, call(2, "b"))
+	forkB2 := mint(forkB1, call(3, "c"))
+	assert.Nil(t, bc.SetTailBlock(forkA))
+
+	next := func(sub *ChainSubscription) *ChainEvent {
+		select {
+		case e := <-sub.Events():
+			return e
+		case <-time.After(time.Second):
+			t.Fatal("timeout to receive the chain event")
+		}
+		return nil
+	}
+
+	_, err = bc.SubscribeEvents(nil, forkA.Height()+2)
+	assert.Equal(t, ErrInvalidFilterRange, err)
+
+	// the events on chain are replayed.
+	sub, err := bc.SubscribeEvents([]string{"chain.contract"}, 1)
+	assert.Nil(t, err)
+	e := next(sub)
+	assert.Equal(t, "a", e.Event.Data)
+	assert.Equal(t, forkA.Height(), e.Height)
+	assert.Equal(t, forkA.Hash(), e.Block)
+	assert.Equal(t, forkA.transactions[0].Hash(), e.Tx)
+	assert.False(t, e.Removed)
+
+	// the reverted events are removed before the replacements arrive.
+	assert.Nil(t, bc.SetTailBlock(forkB2))
+	e = next(sub)
+	assert.Equal(t, "a", e.Event.Data)
+	assert.True(t, e.Removed)
+	e = next(sub)
+	assert.Equal(t, "b", e.Event.Data)
+	assert.Equal(t, forkB1.Hash(), e.Block)
+	assert.False(t, e.Removed)
+	e = next(sub)
+	assert.Equal(t, "c", e.Event.Data)
+	assert.Equal(t, forkB2.Hash(), e.Block)
+	assert.Nil(t, sub.Err())
+
+	sub.Unsubscribe()
+	_, ok := <-sub.Events()
+	assert.False(t, ok)
+	assert.Nil(t, sub.Err())
+
+	// a subscriber falling behind fails instead of blocking the chain.
+	size := ChainSubscriptionBufferSize
+	ChainSubscriptionBufferSize = 1
+	defer func() { ChainSubscriptionBufferSize = size }()
+	slow, err := bc.SubscribeEvents([]string{"chain"}, 0)
+	assert.Nil(t, err)
+	tail := mint(forkB2, call(4, "d"))
+	assert.Equal(t, tail.Hash(), bc.TailBlock().Hash())
+	_, ok = <-slow.Events()
+	assert.False(t, ok)
+	assert.Equal(t, ErrSubscriptionOverflow, slow.Err())
+}
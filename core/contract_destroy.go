@@ -0,0 +1,101 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"encoding/json"
+	"math"
+
+	"github.com/alexlisong/go-nebulas/core/state"
+	"github.com/alexlisong/go-nebulas/storage"
+)
+
+var (
+	// ContractDestroyForkHeight contracts can destroy themselves from this height, disabled by default.
+	ContractDestroyForkHeight uint64 = math.MaxUint64
+
+	// DestroyedContractKey the key of destroyed flag in contract variables.
+	DestroyedContractKey = []byte("__destroyed__")
+)
+
+// ContractDestroyEvent is the data of contract destroy events.
+type ContractDestroyEvent struct {
+	Contract    string `json:"contract"`
+	Beneficiary string `json:"beneficiary"`
+	Value       string `json:"value"`
+}
+
+// DestroyContract marks contract destroyed and sweeps its balance to beneficiary,
+// it's called by the executing contract itself during tx in block. The changes are
+// in ws, so they are reverted with the rest of a failed execution.
+func DestroyContract(tx *Transaction, block *Block, contract state.Account, beneficiary *Address, ws WorldState) error {
+	if tx == nil || block == nil || contract == nil || beneficiary == nil {
+		return ErrNilArgument
+	}
+	if block.Height() < ContractDestroyForkHeight {
+		return ErrContractDestroyDisabled
+	}
+	addr, err := AddressParseFromBytes(contract.Address())
+	if err != nil {
+		return err
+	}
+	if addr.Equals(beneficiary) {
+		return ErrInvalidDestroyBeneficiary
+	}
+	destroyed, err := isContractDestroyed(contract)
+	if err != nil {
+		return err
+	}
+	if destroyed {
+		return ErrContractDestroyed
+	}
+
+	value := contract.Balance()
+	if _, err := transfer(contract.Address(), beneficiary.address, value, ws); err != nil {
+		return err
+	}
+	if err := contract.Put(DestroyedContractKey, []byte{1}); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(&ContractDestroyEvent{
+		Contract:    addr.String(),
+		Beneficiary: beneficiary.String(),
+		Value:       value.String(),
+	})
+	if err != nil {
+		return err
+	}
+	ws.RecordEvent(tx.hash, &state.Event{
+		Topic: TopicContractDestroy,
+		Data:  string(data),
+	})
+	return nil
+}
+
+// isContractDestroyed return if contract has destroyed itself.
+func isContractDestroyed(contract state.Account) (bool, error) {
+	if _, err := contract.Get(DestroyedContractKey); err != nil {
+		if err == storage.ErrKeyNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,152 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"testing"
+
+	"github.com/alexlisong/go-nebulas/core/state"
+	"github.com/alexlisong/go-nebulas/util"
+	"github.com/stretchr/testify/assert"
+)
+
+// destroyNvm destroys the contract to the address in args in "destroy", and fails after it in "fail".
+type destroyNvm struct{}
+
+type destroyEngine struct {
+	mockEngine
+	block    *Block
+	tx       *Transaction
+	contract state.Account
+	ws       WorldState
+}
+
+func (nvm *destroyNvm) CreateEngine(block *Block, tx *Transaction, contract state.Account, ws WorldState) (SmartContractEngine, error) {
+	return &destroyEngine{block: block, tx: tx, contract: contract, ws: ws}, nil
+}
+
+func (engine *destroyEngine) Call(source, sourceType, function, args string) (string, error) {
+	if function != "destroy" && function != "fail" {
+		return "", nil
+	}
+	beneficiary, err := AddressParse(args)
+	if err != nil {
+		return "", err
+	}
+	if err := DestroyContract(engine.tx, engine.block, engine.contract, beneficiary, engine.ws); err != nil {
+		return "", err
+	}
+	if function == "fail" {
+		return "", ErrExecutionFailed
+	}
+	return "", nil
+}
+
+func TestDestroyContract(t *testing.T) {
+	defer func(height uint64) { ContractDestroyForkHeight = height }(ContractDestroyForkHeight)
+	ContractDestroyForkHeight = 0
+	defer func(height uint64) { ReceiptsForkHeight = height }(ReceiptsForkHeight)
+	ReceiptsForkHeight = 0
+
+	neb := testNeb(t)
+	bc := neb.chain
+	bc.nvm = &destroyNvm{}
+	bc.tailBlock.nvm = bc.nvm
+
+	from := mockAddress()
+	signature := mockSignature(t, from)
+	balance, _ := util.NewUint128FromString("1000000000000000000")
+	bc.tailBlock.Begin()
+	acc, err := bc.tailBlock.worldState.GetOrCreateUserAccount(from.address)
+	assert.Nil(t, err)
+	assert.Nil(t, acc.AddBalance(balance))
+	bc.tailBlock.Commit()
+	bc.tailBlock.header.stateRoot = bc.tailBlock.worldState.AccountsRoot()
+	assert.Nil(t, bc.StoreBlockToStorage(bc.tailBlock))
+
+	mint := func(parent *Block, txs ...*Transaction) *Block {
+		block, err := bc.NewBlockFromParent(mockAddress(), parent)
+		assert.Nil(t, err)
+		block.header.timestamp = parent.Timestamp() + BlockIntervalInSecond
+		for _, tx := range txs {
+			txWorldState, err := block.WorldState().Prepare(tx.Hash().String())
+			assert.Nil(t, err)
+			_, err = block.ExecuteTransaction(tx, txWorldState)
+			assert.Nil(t, err)
+			_, err = txWorldState.CheckAndUpdate()
+			assert.Nil(t, err)
+			txWorldState.Close()
+			assert.Nil(t, block.dependency.AddNode(tx.Hash().String()))
+			block.transactions = append(block.transactions, tx)
+		}
+		assert.Nil(t, block.Seal())
+		signBlock(block)
+		assert.Nil(t, bc.BlockPool().Push(block))
+		return bc.GetBlock(block.Hash())
+	}
+
+	deployTx := mockDeployTransaction(bc.ChainID(), 1)
+	deployTx.from, deployTx.to = from, from
+	assert.Nil(t, deployTx.Sign(signature))
+	contract, err := deployTx.GenerateContractAddress()
+	assert.Nil(t, err)
+	beneficiary := mockAddress()
+	value, _ := util.NewUint128FromInt(100)
+	call := func(nonce uint64, function string, args string) *Transaction {
+		tx := mockCallTransaction(bc.ChainID(), nonce, function, args)
+		tx.from, tx.to, tx.value = from, contract, value
+		assert.Nil(t, tx.Sign(signature))
+		return tx
+	}
+
+	deployed := mint(bc.TailBlock(), deployTx)
+	funded := mint(deployed, call(2, "fund", ""), call(3, "fail", beneficiary.String()), call(4, "destroy", contract.String()))
+
+	// the failed destroy and the one to the contract itself are reverted.
+	_, err = funded.CheckContract(contract)
+	assert.Nil(t, err)
+	acc, err = funded.GetAccount(contract.address)
+	assert.Nil(t, err)
+	assert.Equal(t, value, acc.Balance())
+
+	destroyTx := call(5, "destroy", beneficiary.String())
+	destroyed := mint(funded, destroyTx)
+
+	_, err = destroyed.CheckContract(contract)
+	assert.Equal(t, ErrContractDestroyed, err)
+	acc, err = destroyed.GetAccount(contract.address)
+	assert.Nil(t, err)
+	assert.Equal(t, util.NewUint128(), acc.Balance())
+	acc, err = destroyed.GetAccount(beneficiary.address)
+	assert.Nil(t, err)
+	total, _ := value.Add(value)
+	assert.Equal(t, total, acc.Balance())
+
+	events, err := destroyed.FetchEvents(destroyTx.Hash())
+	assert.Nil(t, err)
+	assert.Equal(t, TopicContractDestroy, events[0].Topic)
+
+	// the later calls fail, the history is still readable.
+	failed := mint(destroyed, call(6, "fund", ""))
+	receipt, err := failed.GetTransactionReceipt(failed.transactions[0].Hash())
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(TxExecutionFailed), receipt.Status())
+	_, err = funded.CheckContract(contract)
+	assert.Nil(t, err)
+}
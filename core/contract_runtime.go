@@ -0,0 +1,85 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"math"
+
+	"github.com/alexlisong/go-nebulas/core/state"
+	"github.com/alexlisong/go-nebulas/storage"
+)
+
+var (
+	// ContractRuntimeForkHeight from this height, the runtime lib version of a contract is pinned
+	// at deploy and used by all its executions, disabled by default.
+	ContractRuntimeForkHeight uint64 = math.MaxUint64
+
+	// DefaultContractRuntimeVersion the runtime version of the contracts deployed without one.
+	DefaultContractRuntimeVersion = "1.0.0"
+
+	// ContractRuntimeVersions the runtime versions shipped with the nvm.
+	ContractRuntimeVersions = map[string]bool{
+		"1.0.0": true,
+	}
+
+	// ContractRuntimeKey the key of runtime version in contract variables.
+	ContractRuntimeKey = []byte("__runtime__")
+)
+
+// pinContractRuntime record the runtime version of deploy payload on contract,
+// return the version, empty before ContractRuntimeForkHeight.
+func pinContractRuntime(contract state.Account, payload *DeployPayload, block *Block) (string, error) {
+	if block.Height() < ContractRuntimeForkHeight {
+		return "", nil
+	}
+	version := payload.RuntimeVersion
+	if len(version) == 0 {
+		version = DefaultContractRuntimeVersion
+	}
+	if !ContractRuntimeVersions[version] {
+		return "", ErrUnsupportedRuntimeVersion
+	}
+	if err := contract.Put(ContractRuntimeKey, []byte(version)); err != nil {
+		return "", err
+	}
+	return version, nil
+}
+
+// contractRuntime return the runtime version pinned on contract, empty for the contracts deployed before the fork.
+func contractRuntime(contract state.Account, block *Block) (string, error) {
+	if block.Height() < ContractRuntimeForkHeight {
+		return "", nil
+	}
+	version, err := contract.Get(ContractRuntimeKey)
+	if err == storage.ErrKeyNotFound {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(version), nil
+}
+
+// setEngineRuntime load the runtime lib of version in engine, the engine's default is kept if version is empty.
+func setEngineRuntime(engine SmartContractEngine, version string) error {
+	if len(version) == 0 {
+		return nil
+	}
+	return engine.SetRuntimeVersion(version)
+}
@@ -0,0 +1,131 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/alexlisong/go-nebulas/core/state"
+	"github.com/alexlisong/go-nebulas/util"
+	"github.com/stretchr/testify/assert"
+)
+
+// runtimeNvm records the runtime versions loaded in its engines.
+type runtimeNvm struct {
+	versions []string
+}
+
+type runtimeEngine struct {
+	mockEngine
+	nvm *runtimeNvm
+}
+
+func (nvm *runtimeNvm) CreateEngine(block *Block, tx *Transaction, contract state.Account, ws WorldState) (SmartContractEngine, error) {
+	return &runtimeEngine{nvm: nvm}, nil
+}
+
+func (engine *runtimeEngine) SetRuntimeVersion(version string) error {
+	engine.nvm.versions = append(engine.nvm.versions, version)
+	return nil
+}
+
+func TestContractRuntimeVersion(t *testing.T) {
+	defer func(height uint64, version string) {
+		ContractRuntimeForkHeight, DefaultContractRuntimeVersion = height, version
+		delete(ContractRuntimeVersions, "2.0.0")
+	}(ContractRuntimeForkHeight, DefaultContractRuntimeVersion)
+	ContractRuntimeForkHeight = 0
+
+	neb := testNeb(t)
+	bc := neb.chain
+	nvm := &runtimeNvm{}
+	bc.nvm = nvm
+	bc.tailBlock.nvm = bc.nvm
+
+	from := mockAddress()
+	signature := mockSignature(t, from)
+	balance, _ := util.NewUint128FromString("1000000000000000000")
+	bc.tailBlock.Begin()
+	acc, err := bc.tailBlock.worldState.GetOrCreateUserAccount(from.address)
+	assert.Nil(t, err)
+	assert.Nil(t, acc.AddBalance(balance))
+	bc.tailBlock.Commit()
+	bc.tailBlock.header.stateRoot = bc.tailBlock.worldState.AccountsRoot()
+	assert.Nil(t, bc.StoreBlockToStorage(bc.tailBlock))
+
+	mint := func(parent *Block, txs ...*Transaction) *Block {
+		block, err := bc.NewBlockFromParent(mockAddress(), parent)
+		assert.Nil(t, err)
+		block.header.timestamp = parent.Timestamp() + BlockIntervalInSecond
+		for _, tx := range txs {
+			txWorldState, err := block.WorldState().Prepare(tx.Hash().String())
+			assert.Nil(t, err)
+			_, err = block.ExecuteTransaction(tx, txWorldState)
+			assert.Nil(t, err)
+			_, err = txWorldState.CheckAndUpdate()
+			assert.Nil(t, err)
+			txWorldState.Close()
+			assert.Nil(t, block.dependency.AddNode(tx.Hash().String()))
+			block.transactions = append(block.transactions, tx)
+		}
+		assert.Nil(t, block.Seal())
+		signBlock(block)
+		assert.Nil(t, bc.BlockPool().Push(block))
+		return bc.GetBlock(block.Hash())
+	}
+
+	deploy := func(nonce uint64, version string) *Transaction {
+		payload, err := NewDeployPayload("module.exports = {};", SourceTypeJavaScript, "")
+		assert.Nil(t, err)
+		payload.RuntimeVersion = version
+		data, err := payload.ToBytes()
+		assert.Nil(t, err)
+		loaded, err := LoadDeployPayload(data)
+		assert.Nil(t, err)
+		assert.Equal(t, version, loaded.RuntimeVersion)
+
+		tx := mockTransaction(bc.ChainID(), nonce, TxPayloadDeployType, data)
+		tx.from, tx.to = from, from
+		assert.Nil(t, tx.Sign(signature))
+		return tx
+	}
+
+	deployTx := deploy(1, "")
+	contract, err := deployTx.GenerateContractAddress()
+	assert.Nil(t, err)
+	deployed := mint(bc.TailBlock(), deployTx)
+
+	// the node's default changes, the deployed contract keeps its version.
+	ContractRuntimeVersions["2.0.0"] = true
+	DefaultContractRuntimeVersion = "2.0.0"
+
+	callTx := mockCallTransaction(bc.ChainID(), 2, "f", "")
+	callTx.from, callTx.to = from, contract
+	assert.Nil(t, callTx.Sign(signature))
+	unknownTx := deploy(4, "9.9.9")
+	block := mint(deployed, callTx, deploy(3, ""), unknownTx)
+	assert.Equal(t, []string{"1.0.0", "1.0.0", "2.0.0"}, nvm.versions)
+
+	event, err := block.FetchExecutionResultEvent(unknownTx.Hash())
+	assert.Nil(t, err)
+	txEvent := &TransactionEvent{}
+	assert.Nil(t, json.Unmarshal([]byte(event.Data), txEvent))
+	assert.Equal(t, ErrUnsupportedRuntimeVersion.Error(), txEvent.Error)
+}
@@ -0,0 +1,126 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"github.com/alexlisong/go-nebulas/core/state"
+	"github.com/alexlisong/go-nebulas/storage"
+)
+
+// ContractSource is the source of a deployed contract, loaded from its deploy tx
+// or the upgrade tx of its code version.
+type ContractSource struct {
+	Source     string `json:"source"`
+	SourceType string `json:"source_type"`
+	Deployer   string `json:"deployer"`
+	DeployTx   string `json:"deploy_tx"`
+
+	// the salt deriving the contract address, empty for the legacy address.
+	Salt string `json:"salt,omitempty"`
+
+	// the code version and the tx upgrading to it, 0 and empty for the deployed code.
+	Version   uint64 `json:"version"`
+	UpgradeTx string `json:"upgrade_tx,omitempty"`
+}
+
+// GetContractSource return the current source of contract addr in ws.
+func GetContractSource(addr *Address, ws WorldState) (*ContractSource, error) {
+	contract, err := CheckContract(addr, ws)
+	if err != nil {
+		return nil, err
+	}
+	version, err := contractVersion(contract)
+	if err != nil {
+		return nil, err
+	}
+	return contractSource(contract, version, ws)
+}
+
+// GetContractSourceAtVersion return the source of contract addr at code version in ws,
+// the replaced versions are kept for auditing.
+func GetContractSourceAtVersion(addr *Address, version uint64, ws WorldState) (*ContractSource, error) {
+	contract, err := CheckContract(addr, ws)
+	if err != nil {
+		return nil, err
+	}
+	return contractSource(contract, version, ws)
+}
+
+// contractSource following the birth place of contract to the deploy tx,
+// and the code key of version to the upgrade tx.
+func contractSource(contract state.Account, version uint64, ws WorldState) (*ContractSource, error) {
+	birthTx, err := GetTransaction(contract.BirthPlace(), ws)
+	if isTrieKeyNotFound(err) {
+		return nil, ErrContractBirthTxNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if birthTx.Type() != TxPayloadDeployType {
+		return nil, ErrInvalidContractBirthTx
+	}
+	deploy, err := LoadDeployPayload(birthTx.data.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	source := &ContractSource{
+		Source:     deploy.Source,
+		SourceType: deploy.SourceType,
+		Deployer:   birthTx.from.String(),
+		DeployTx:   birthTx.hash.String(),
+		Salt:       deploy.Salt,
+	}
+	if version == 0 {
+		return source, nil
+	}
+
+	upgradeTxHash, err := contract.Get(contractCodeKey(version))
+	if err == storage.ErrKeyNotFound {
+		return nil, ErrContractVersionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	upgradeTx, err := GetTransaction(upgradeTxHash, ws)
+	if err != nil {
+		return nil, err
+	}
+	upgrade, err := LoadUpgradePayload(upgradeTx.data.Payload)
+	if err != nil {
+		return nil, err
+	}
+	source.Source = upgrade.Source
+	source.SourceType = upgrade.SourceType
+	source.Version = version
+	source.UpgradeTx = upgradeTx.hash.String()
+	return source, nil
+}
+
+// GetContractSource return the source of contract addr in block.
+func (block *Block) GetContractSource(addr *Address) (*ContractSource, error) {
+	if block.statePruned {
+		return nil, ErrStatePruned
+	}
+	worldState, err := block.WorldState().Clone()
+	if err != nil {
+		return nil, err
+	}
+	return GetContractSource(addr, worldState)
+}
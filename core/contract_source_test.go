@@ -0,0 +1,107 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"testing"
+
+	"github.com/alexlisong/go-nebulas/core/state"
+	"github.com/alexlisong/go-nebulas/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlock_GetContractSource(t *testing.T) {
+	defer func(height uint64) { DeploySaltForkHeight = height }(DeploySaltForkHeight)
+	DeploySaltForkHeight = 0
+
+	neb := testNeb(t)
+	bc := neb.chain
+	bc.nvm = &storageNvm{}
+	bc.tailBlock.nvm = bc.nvm
+
+	from := mockAddress()
+	signature := mockSignature(t, from)
+	balance, _ := util.NewUint128FromString("1000000000000000000")
+	bc.tailBlock.Begin()
+	acc, err := bc.tailBlock.worldState.GetOrCreateUserAccount(from.address)
+	assert.Nil(t, err)
+	assert.Nil(t, acc.AddBalance(balance))
+	bc.tailBlock.Commit()
+	bc.tailBlock.header.stateRoot = bc.tailBlock.worldState.AccountsRoot()
+	assert.Nil(t, bc.StoreBlockToStorage(bc.tailBlock))
+
+	deployTx := mockDeployTransaction(bc.ChainID(), 1)
+	deployTx.from, deployTx.to = from, from
+	assert.Nil(t, deployTx.Sign(signature))
+	contract, err := deployTx.GenerateContractAddress()
+	assert.Nil(t, err)
+
+	saltPayload, err := NewDeployPayloadWithSalt("module.exports = {};", "js", "", "salt")
+	assert.Nil(t, err)
+	saltTx := mockDeployTransaction(bc.ChainID(), 2)
+	saltTx.from, saltTx.to = from, from
+	saltTx.data.Payload, err = saltPayload.ToBytes()
+	assert.Nil(t, err)
+	assert.Nil(t, saltTx.Sign(signature))
+	saltContract, err := saltTx.GenerateContractAddressWithSalt()
+	assert.Nil(t, err)
+
+	block, err := bc.NewBlockFromParent(mockAddress(), bc.TailBlock())
+	assert.Nil(t, err)
+	block.header.timestamp = bc.TailBlock().Timestamp() + BlockIntervalInSecond
+	for _, tx := range []*Transaction{deployTx, saltTx} {
+		txWorldState, err := block.WorldState().Prepare(tx.Hash().String())
+		assert.Nil(t, err)
+		_, err = block.ExecuteTransaction(tx, txWorldState)
+		assert.Nil(t, err)
+		_, err = txWorldState.CheckAndUpdate()
+		assert.Nil(t, err)
+		txWorldState.Close()
+		assert.Nil(t, block.dependency.AddNode(tx.Hash().String()))
+		block.transactions = append(block.transactions, tx)
+	}
+	assert.Nil(t, block.Seal())
+	signBlock(block)
+	assert.Nil(t, bc.BlockPool().Push(block))
+	block = bc.GetBlock(block.Hash())
+
+	payload, err := LoadDeployPayload(deployTx.data.Payload)
+	assert.Nil(t, err)
+	source, err := block.GetContractSource(contract)
+	assert.Nil(t, err)
+	assert.Equal(t, &ContractSource{
+		Source:     payload.Source,
+		SourceType: payload.SourceType,
+		Deployer:   from.String(),
+		DeployTx:   deployTx.Hash().String(),
+	}, source)
+
+	source, err = block.GetContractSource(saltContract)
+	assert.Nil(t, err)
+	assert.Equal(t, "module.exports = {};", source.Source)
+	assert.Equal(t, saltTx.Hash().String(), source.DeployTx)
+	assert.Equal(t, "salt", source.Salt)
+
+	_, err = block.GetContractSource(from)
+	assert.Equal(t, state.ErrContractCheckFailed, err)
+
+	// the contract isn't deployed in the parent block.
+	_, err = bc.GetBlock(block.ParentHash()).GetContractSource(contract)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,159 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"bytes"
+
+	"github.com/alexlisong/go-nebulas/core/state"
+)
+
+// the types of StorageChange.
+const (
+	StorageKeyAdded   = "added"
+	StorageKeyChanged = "changed"
+	StorageKeyRemoved = "removed"
+)
+
+// StorageChange is a key changed in contract storage between two blocks,
+// Old is nil if the key is added, New is nil if it's removed.
+type StorageChange struct {
+	Type string
+	Key  []byte
+	Old  []byte
+	New  []byte
+}
+
+// StorageDiffIterator streams the changes of contract storage in key order.
+type StorageDiffIterator struct {
+	from, to     state.Iterator
+	fromOK, toOK bool
+	change       *StorageChange
+}
+
+// ContractStorageIterator return the iterator of addr's storage in block, in key order.
+func ContractStorageIterator(block *Block, addr *Address) (state.Iterator, error) {
+	if addr == nil {
+		return nil, ErrNilArgument
+	}
+	worldState, err := contractStorageState(block)
+	if err != nil {
+		return nil, err
+	}
+	return worldState.ContractStorageIterator(addr.address)
+}
+
+// DiffContractStorage return the iterator of keys added, changed or removed in addr's storage from blockA to blockB.
+// The contract is taken as empty in a block where it isn't deployed.
+func DiffContractStorage(blockA, blockB *Block, addr *Address) (*StorageDiffIterator, error) {
+	if addr == nil {
+		return nil, ErrNilArgument
+	}
+	from, err := diffStorageIterator(blockA, addr)
+	if err != nil {
+		return nil, err
+	}
+	to, err := diffStorageIterator(blockB, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	it := &StorageDiffIterator{from: from, to: to}
+	if from != nil {
+		if it.fromOK, err = from.Next(); err != nil {
+			return nil, err
+		}
+	}
+	if to != nil {
+		if it.toOK, err = to.Next(); err != nil {
+			return nil, err
+		}
+	}
+	return it, nil
+}
+
+func contractStorageState(block *Block) (state.WorldState, error) {
+	if block == nil {
+		return nil, ErrNilArgument
+	}
+	if block.statePruned {
+		return nil, ErrStatePruned
+	}
+	return block.WorldState().Clone()
+}
+
+// diffStorageIterator return nil if the contract isn't deployed in block.
+func diffStorageIterator(block *Block, addr *Address) (state.Iterator, error) {
+	worldState, err := contractStorageState(block)
+	if err != nil {
+		return nil, err
+	}
+	iter, err := worldState.ContractStorageIterator(addr.address)
+	if err == state.ErrAccountNotFound {
+		return nil, nil
+	}
+	return iter, err
+}
+
+// Next move to the next change, return false if there is no more.
+func (it *StorageDiffIterator) Next() (bool, error) {
+	var err error
+	for it.fromOK || it.toOK {
+		cmp := 0
+		switch {
+		case !it.fromOK:
+			cmp = 1
+		case !it.toOK:
+			cmp = -1
+		default:
+			cmp = bytes.Compare(it.from.Key(), it.to.Key())
+		}
+
+		switch {
+		case cmp < 0:
+			it.change = &StorageChange{Type: StorageKeyRemoved, Key: it.from.Key(), Old: it.from.Value()}
+			it.fromOK, err = it.from.Next()
+		case cmp > 0:
+			it.change = &StorageChange{Type: StorageKeyAdded, Key: it.to.Key(), New: it.to.Value()}
+			it.toOK, err = it.to.Next()
+		default:
+			if !bytes.Equal(it.from.Value(), it.to.Value()) {
+				it.change = &StorageChange{Type: StorageKeyChanged, Key: it.to.Key(), Old: it.from.Value(), New: it.to.Value()}
+			} else {
+				it.change = nil
+			}
+			if it.fromOK, err = it.from.Next(); err == nil {
+				it.toOK, err = it.to.Next()
+			}
+		}
+		if err != nil {
+			return false, err
+		}
+		if it.change != nil {
+			return true, nil
+		}
+	}
+	it.change = nil
+	return false, nil
+}
+
+// Change return the current change.
+func (it *StorageDiffIterator) Change() *StorageChange {
+	return it.change
+}
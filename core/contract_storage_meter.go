@@ -0,0 +1,108 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"math"
+
+	"github.com/alexlisong/go-nebulas/core/state"
+	"github.com/alexlisong/go-nebulas/util"
+)
+
+var (
+	// StorageMeterForkHeight from this height, the bytes a contract execution puts into storage are charged,
+	// and limited by MaxStorageBytesPerExecution, disabled by default.
+	StorageMeterForkHeight uint64 = math.MaxUint64
+
+	// StorageGasPerByte gas charged per byte of key and value put into contract storage.
+	StorageGasPerByte uint64 = 10
+
+	// StorageRefundPercent percent of the gas of the bytes deleted from contract storage refunded,
+	// the refund is at most the gas charged in the same execution.
+	StorageRefundPercent uint64 = 50
+
+	// MaxStorageBytesPerExecution max bytes of key and value put into contract storage in one execution.
+	MaxStorageBytesPerExecution uint64 = 128 * 1024
+)
+
+// storageMeter counts the bytes put into and deleted from the storage of contract in one execution.
+type storageMeter struct {
+	state.Account
+
+	put      uint64
+	deleted  uint64
+	exceeded bool
+}
+
+// newStorageMeter wraps contract, nil before StorageMeterForkHeight.
+func newStorageMeter(contract state.Account, block *Block) *storageMeter {
+	if block.Height() < StorageMeterForkHeight {
+		return nil
+	}
+	return &storageMeter{Account: contract}
+}
+
+// Put into contract's storage, fails once the bytes put exceed MaxStorageBytesPerExecution.
+func (m *storageMeter) Put(key []byte, value []byte) error {
+	size := uint64(len(key) + len(value))
+	if m.exceeded || m.put+size > MaxStorageBytesPerExecution {
+		m.exceeded = true
+		return ErrStorageLimitExceeded
+	}
+	if err := m.Account.Put(key, value); err != nil {
+		return err
+	}
+	m.put += size
+	return nil
+}
+
+// Del from contract's storage, the bytes of an existing entry are refunded.
+func (m *storageMeter) Del(key []byte) error {
+	value, err := m.Account.Get(key)
+	if err != nil {
+		return err
+	}
+	if err := m.Account.Del(key); err != nil {
+		return err
+	}
+	m.deleted += uint64(len(key) + len(value))
+	return nil
+}
+
+// settle adds the storage gas to the gas of execution, the error of execution
+// is ErrStorageLimitExceeded if the limit is exceeded, whatever the engine returns.
+func (m *storageMeter) settle(gas *util.Uint128, exeErr error) (*util.Uint128, error) {
+	if m == nil {
+		return gas, exeErr
+	}
+	if m.exceeded {
+		exeErr = ErrStorageLimitExceeded
+	}
+
+	charged := m.put * StorageGasPerByte
+	refund := m.deleted * StorageGasPerByte * StorageRefundPercent / 100
+	if refund > charged {
+		refund = charged
+	}
+	total, err := gas.Add(util.NewUint128FromUint(charged - refund))
+	if err != nil {
+		return gas, ErrGasCntOverflow
+	}
+	return total, exeErr
+}
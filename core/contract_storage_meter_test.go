@@ -0,0 +1,157 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/alexlisong/go-nebulas/core/state"
+	"github.com/alexlisong/go-nebulas/util"
+	"github.com/stretchr/testify/assert"
+)
+
+// meterNvm puts 1KB entries into contract storage in "put", deletes them in "del",
+// and does both in "mix", args is the count of entries.
+type meterNvm struct{}
+
+type meterEngine struct {
+	mockEngine
+	contract state.Account
+}
+
+func (nvm *meterNvm) CreateEngine(block *Block, tx *Transaction, contract state.Account, ws WorldState) (SmartContractEngine, error) {
+	return &meterEngine{contract: contract}, nil
+}
+
+func (engine *meterEngine) Call(source, sourceType, function, args string) (string, error) {
+	count, err := strconv.Atoi(args)
+	if err != nil {
+		return "", err
+	}
+	value := []byte(strings.Repeat("v", 1020))
+	for i := 0; i < count; i++ {
+		// the contract catches the errors and goes on.
+		if function == "put" || function == "mix" {
+			engine.contract.Put([]byte(fmt.Sprintf("k%03d", i)), value)
+		}
+		if function == "del" || function == "mix" {
+			engine.contract.Del([]byte(fmt.Sprintf("k%03d", i)))
+		}
+	}
+	return "", nil
+}
+
+func TestContractStorageMeter(t *testing.T) {
+	defer func(height uint64) { StorageMeterForkHeight = height }(StorageMeterForkHeight)
+	StorageMeterForkHeight = 0
+
+	neb := testNeb(t)
+	bc := neb.chain
+	bc.nvm = &meterNvm{}
+	bc.tailBlock.nvm = bc.nvm
+
+	from := mockAddress()
+	signature := mockSignature(t, from)
+	balance, _ := util.NewUint128FromString("1000000000000000000")
+	bc.tailBlock.Begin()
+	acc, err := bc.tailBlock.worldState.GetOrCreateUserAccount(from.address)
+	assert.Nil(t, err)
+	assert.Nil(t, acc.AddBalance(balance))
+	bc.tailBlock.Commit()
+	bc.tailBlock.header.stateRoot = bc.tailBlock.worldState.AccountsRoot()
+	assert.Nil(t, bc.StoreBlockToStorage(bc.tailBlock))
+
+	mint := func(parent *Block, txs ...*Transaction) *Block {
+		block, err := bc.NewBlockFromParent(mockAddress(), parent)
+		assert.Nil(t, err)
+		block.header.timestamp = parent.Timestamp() + BlockIntervalInSecond
+		for _, tx := range txs {
+			txWorldState, err := block.WorldState().Prepare(tx.Hash().String())
+			assert.Nil(t, err)
+			_, err = block.ExecuteTransaction(tx, txWorldState)
+			assert.Nil(t, err)
+			_, err = txWorldState.CheckAndUpdate()
+			assert.Nil(t, err)
+			txWorldState.Close()
+			assert.Nil(t, block.dependency.AddNode(tx.Hash().String()))
+			block.transactions = append(block.transactions, tx)
+		}
+		assert.Nil(t, block.Seal())
+		signBlock(block)
+		assert.Nil(t, bc.BlockPool().Push(block))
+		return bc.GetBlock(block.Hash())
+	}
+
+	deployTx := mockDeployTransaction(bc.ChainID(), 1)
+	deployTx.from, deployTx.to = from, from
+	assert.Nil(t, deployTx.Sign(signature))
+	contract, err := deployTx.GenerateContractAddress()
+	assert.Nil(t, err)
+	call := func(nonce uint64, function string, count int) *Transaction {
+		// the payloads have the same length, so the same base gas.
+		tx := mockCallTransaction(bc.ChainID(), nonce, function, fmt.Sprintf("%04d", count))
+		tx.from, tx.to = from, contract
+		assert.Nil(t, tx.Sign(signature))
+		return tx
+	}
+	resultOf := func(block *Block, tx *Transaction) *TransactionEvent {
+		event, err := block.FetchExecutionResultEvent(tx.Hash())
+		assert.Nil(t, err)
+		txEvent := &TransactionEvent{}
+		assert.Nil(t, json.Unmarshal([]byte(event.Data), txEvent))
+		return txEvent
+	}
+	gasOf := func(block *Block, tx *Transaction) uint64 {
+		gas, err := util.NewUint128FromString(resultOf(block, tx).GasUsed)
+		assert.Nil(t, err)
+		return gas.Uint64()
+	}
+
+	// 1MB in one call fails whatever the contract does with the error.
+	noopTx, putTx, hugeTx := call(2, "nop", 0), call(3, "put", 10), call(4, "put", 1024)
+	block := mint(mint(bc.TailBlock(), deployTx), noopTx, putTx, hugeTx)
+	noopGas := gasOf(block, noopTx)
+	assert.Equal(t, noopGas+10*1024*StorageGasPerByte, gasOf(block, putTx))
+	huge := resultOf(block, hugeTx)
+	assert.Equal(t, int8(TxExecutionFailed), huge.Status)
+	assert.Equal(t, ErrStorageLimitExceeded.Error(), huge.Error)
+
+	iter, err := ContractStorageIterator(block, contract)
+	assert.Nil(t, err)
+	count := 0
+	for {
+		exist, err := iter.Next()
+		assert.Nil(t, err)
+		if !exist {
+			break
+		}
+		count++
+	}
+	assert.Equal(t, 10, count)
+
+	// the refund of deletes is at most the put gas in the same execution.
+	delTx, mixTx := call(5, "del", 10), call(6, "mix", 10)
+	block = mint(block, delTx, mixTx)
+	assert.Equal(t, noopGas, gasOf(block, delTx))
+	assert.Equal(t, noopGas+10*1024*StorageGasPerByte*(100-StorageRefundPercent)/100, gasOf(block, mixTx))
+}
@@ -0,0 +1,187 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/alexlisong/go-nebulas/core/state"
+	"github.com/alexlisong/go-nebulas/util"
+	"github.com/stretchr/testify/assert"
+)
+
+// storageNvm writes 300 keys to contract storage in "fill", then changes, deletes and adds some in "update".
+type storageNvm struct{}
+
+type storageEngine struct {
+	mockEngine
+	contract state.Account
+}
+
+func (nvm *storageNvm) CreateEngine(block *Block, tx *Transaction, contract state.Account, ws WorldState) (SmartContractEngine, error) {
+	return &storageEngine{contract: contract}, nil
+}
+
+func storageKey(i int) []byte {
+	return []byte(fmt.Sprintf("k%03d", i))
+}
+
+func (engine *storageEngine) Call(source, sourceType, function, args string) (string, error) {
+	var err error
+	for i := 0; i < 350 && err == nil; i++ {
+		switch {
+		case function == "fill" && i < 300:
+			err = engine.contract.Put(storageKey(i), []byte("v1"))
+		case function == "update" && i < 100:
+			err = engine.contract.Put(storageKey(i), []byte("v2"))
+		case function == "update" && i < 150:
+			err = engine.contract.Del(storageKey(i))
+		case function == "update" && i >= 300:
+			err = engine.contract.Put(storageKey(i), []byte("v1"))
+		}
+	}
+	return "", err
+}
+
+func TestContractStorage(t *testing.T) {
+	neb := testNeb(t)
+	bc := neb.chain
+	bc.nvm = &storageNvm{}
+	bc.tailBlock.nvm = bc.nvm
+
+	from := mockAddress()
+	signature := mockSignature(t, from)
+	balance, _ := util.NewUint128FromString("1000000000000000000")
+	bc.tailBlock.Begin()
+	acc, err := bc.tailBlock.worldState.GetOrCreateUserAccount(from.address)
+	assert.Nil(t, err)
+	assert.Nil(t, acc.AddBalance(balance))
+	bc.tailBlock.Commit()
+	bc.tailBlock.header.stateRoot = bc.tailBlock.worldState.AccountsRoot()
+	assert.Nil(t, bc.StoreBlockToStorage(bc.tailBlock))
+
+	mint := func(parent *Block, txs ...*Transaction) *Block {
+		block, err := bc.NewBlockFromParent(mockAddress(), parent)
+		assert.Nil(t, err)
+		block.header.timestamp = parent.Timestamp() + BlockIntervalInSecond
+		for _, tx := range txs {
+			txWorldState, err := block.WorldState().Prepare(tx.Hash().String())
+			assert.Nil(t, err)
+			_, err = block.ExecuteTransaction(tx, txWorldState)
+			assert.Nil(t, err)
+			_, err = txWorldState.CheckAndUpdate()
+			assert.Nil(t, err)
+			txWorldState.Close()
+			assert.Nil(t, block.dependency.AddNode(tx.Hash().String()))
+			block.transactions = append(block.transactions, tx)
+		}
+		assert.Nil(t, block.Seal())
+		signBlock(block)
+		assert.Nil(t, bc.BlockPool().Push(block))
+		return bc.GetBlock(block.Hash())
+	}
+
+	deployTx := mockDeployTransaction(bc.ChainID(), 1)
+	deployTx.from, deployTx.to = from, from
+	assert.Nil(t, deployTx.Sign(signature))
+	contract, err := deployTx.GenerateContractAddress()
+	assert.Nil(t, err)
+	call := func(nonce uint64, function string) *Transaction {
+		tx := mockCallTransaction(bc.ChainID(), nonce, function, "")
+		tx.from, tx.to = from, contract
+		assert.Nil(t, tx.Sign(signature))
+		return tx
+	}
+
+	genesis := bc.TailBlock()
+	deployed := mint(genesis, deployTx)
+	filled := mint(deployed, call(2, "fill"))
+	updated := mint(filled, call(3, "update"))
+
+	// the storage is iterated in key order.
+	iter, err := ContractStorageIterator(updated, contract)
+	assert.Nil(t, err)
+	keys := [][]byte{}
+	for {
+		exist, err := iter.Next()
+		assert.Nil(t, err)
+		if !exist {
+			break
+		}
+		if len(keys) > 0 {
+			assert.True(t, bytes.Compare(keys[len(keys)-1], iter.Key()) < 0)
+		}
+		keys = append(keys, iter.Key())
+	}
+	assert.Equal(t, 300, len(keys))
+
+	iter, err = ContractStorageIterator(deployed, contract)
+	assert.Nil(t, err)
+	exist, err := iter.Next()
+	assert.Nil(t, err)
+	assert.False(t, exist)
+
+	_, err = ContractStorageIterator(updated, from)
+	assert.Equal(t, state.ErrContractCheckFailed, err)
+
+	diff := func(a, b *Block) map[string][]*StorageChange {
+		it, err := DiffContractStorage(a, b, contract)
+		assert.Nil(t, err)
+		changes := make(map[string][]*StorageChange)
+		var last []byte
+		for {
+			exist, err := it.Next()
+			assert.Nil(t, err)
+			if !exist {
+				break
+			}
+			change := it.Change()
+			assert.True(t, last == nil || bytes.Compare(last, change.Key) < 0)
+			last = change.Key
+			changes[change.Type] = append(changes[change.Type], change)
+		}
+		return changes
+	}
+
+	// the contract isn't deployed in genesis.
+	changes := diff(genesis, filled)
+	assert.Equal(t, 300, len(changes[StorageKeyAdded]))
+	assert.Equal(t, 0, len(changes[StorageKeyChanged])+len(changes[StorageKeyRemoved]))
+
+	changes = diff(filled, updated)
+	assert.Equal(t, 50, len(changes[StorageKeyAdded]))
+	assert.Equal(t, 100, len(changes[StorageKeyChanged]))
+	assert.Equal(t, 50, len(changes[StorageKeyRemoved]))
+	assert.Equal(t, storageKey(300), changes[StorageKeyAdded][0].Key)
+	assert.Nil(t, changes[StorageKeyAdded][0].Old)
+	assert.Equal(t, []byte("v1"), changes[StorageKeyChanged][0].Old)
+	assert.Equal(t, []byte("v2"), changes[StorageKeyChanged][0].New)
+	assert.Equal(t, storageKey(100), changes[StorageKeyRemoved][0].Key)
+	assert.Nil(t, changes[StorageKeyRemoved][0].New)
+
+	// the reversed diff.
+	changes = diff(updated, filled)
+	assert.Equal(t, 50, len(changes[StorageKeyAdded]))
+	assert.Equal(t, 50, len(changes[StorageKeyRemoved]))
+	assert.Equal(t, storageKey(300), changes[StorageKeyRemoved][0].Key)
+
+	assert.Empty(t, diff(updated, updated))
+}
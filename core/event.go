@@ -42,8 +42,41 @@ const (
 	// TopicRevertBlock the topic of revert block
 	TopicRevertBlock = "chain.revertBlock"
 
-	// TopicDropTransaction drop tx (1): smaller nonce (2) expire txLifeTime
+	// TopicDropTransaction drop tx (1): smaller nonce (2) expire txLifeTime (3) replaced by higher gasPrice
 	TopicDropTransaction = "chain.dropTransaction"
+
+	// TopicRecoveryRegister the topic of registering a recovery heir.
+	TopicRecoveryRegister = "chain.recoveryRegister"
+
+	// TopicRecoveryClaim the topic of a recovery claim from heir.
+	TopicRecoveryClaim = "chain.recoveryClaim"
+
+	// TopicRecoveryCancel the topic of a recovery claim cancelled by owner's activity.
+	TopicRecoveryCancel = "chain.recoveryCancel"
+
+	// TopicRecoverySweep the topic of heir sweeping owner's balance.
+	TopicRecoverySweep = "chain.recoverySweep"
+
+	// TopicBatchTransfer the topic of a transfer in batch tx.
+	TopicBatchTransfer = "chain.batchTransfer"
+
+	// TopicContractDeploy the topic of a contract deployed by tx.
+	TopicContractDeploy = "chain.contractDeploy"
+
+	// TopicContractDestroy the topic of a contract destroyed by itself.
+	TopicContractDestroy = "chain.contractDestroy"
+
+	// TopicContractUpgrade the topic of a contract code upgraded by its deployer.
+	TopicContractUpgrade = "chain.contractUpgrade"
+
+	// TopicAccountFreeze the topic of an account frozen by admin.
+	TopicAccountFreeze = "chain.accountFreeze"
+
+	// TopicAccountUnfreeze the topic of an account unfrozen by admin.
+	TopicAccountUnfreeze = "chain.accountUnfreeze"
+
+	// TopicChainReorg the topic of canonical chain switched to another fork.
+	TopicChainReorg = "chain.reorg"
 )
 
 // EventSubscriber subscriber object
@@ -0,0 +1,205 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"encoding/json"
+
+	"github.com/alexlisong/go-nebulas/storage"
+	"github.com/alexlisong/go-nebulas/util/byteutils"
+)
+
+// IndexedEvent is an event on canonical chain with its location.
+type IndexedEvent struct {
+	Height   uint64 `json:"height"`
+	Block    string `json:"block"`
+	Tx       string `json:"tx"`
+	Contract string `json:"contract,omitempty"`
+	Topic    string `json:"topic"`
+	Data     string `json:"data"`
+}
+
+// EventPage is a page of indexed events. The events of a height are never split into pages,
+// Next is the height to fetch the next page from, 0 if all events in range are fetched.
+type EventPage struct {
+	Events []*IndexedEvent
+	Next   uint64
+}
+
+// eventTopicIndexKey is the key of events at height, whose topic is or starts with topic and a dot.
+func eventTopicIndexKey(topic string, height uint64) []byte {
+	key := append([]byte(EventTopicIndexKeyPrefix), byteutils.FromUint64(height)...)
+	return append(key, topic...)
+}
+
+// eventContractIndexKey is the key of events emitted by contract at height.
+func eventContractIndexKey(contract byteutils.Hash, height uint64) []byte {
+	key := append([]byte(EventContractIndexKeyPrefix), byteutils.FromUint64(height)...)
+	return append(key, contract...)
+}
+
+// topicPrefixes return the dot separated prefixes of topic, including itself.
+func topicPrefixes(topic string) []string {
+	prefixes := []string{}
+	for i := 0; i < len(topic); i++ {
+		if topic[i] == '.' {
+			prefixes = append(prefixes, topic[:i])
+		}
+	}
+	return append(prefixes, topic)
+}
+
+// eventContract return the contract emitting the events of tx, nil if tx doesn't execute any contract.
+func eventContract(tx *Transaction, height uint64) (*Address, error) {
+	if tx.Type() == TxPayloadDeployType {
+		return tx.ContractAddressAtHeight(height)
+	}
+	if tx.to.Type() == ContractAddress {
+		return tx.to, nil
+	}
+	return nil, nil
+}
+
+// collectEventIndex return the indexed events of block by their index keys.
+func (bc *BlockChain) collectEventIndex(block *Block) (map[string][]*IndexedEvent, error) {
+	index := make(map[string][]*IndexedEvent)
+	if block.statePruned {
+		return index, nil
+	}
+	for _, tx := range block.transactions {
+		events, err := fetchEventsWithExecutionResult(tx.hash, block.WorldState())
+		if err != nil {
+			return nil, err
+		}
+		if len(events) == 0 {
+			continue
+		}
+		contract, err := eventContract(tx, block.height)
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range events {
+			indexed := &IndexedEvent{
+				Height: block.height,
+				Block:  block.Hash().String(),
+				Tx:     tx.hash.String(),
+				Topic:  event.Topic,
+				Data:   event.Data,
+			}
+			for _, prefix := range topicPrefixes(event.Topic) {
+				key := string(eventTopicIndexKey(prefix, block.height))
+				index[key] = append(index[key], indexed)
+			}
+			if contract != nil {
+				indexed.Contract = contract.String()
+				key := string(eventContractIndexKey(contract.address, block.height))
+				index[key] = append(index[key], indexed)
+			}
+		}
+	}
+	return index, nil
+}
+
+// putEventIndex index the events in block, which is linked into canonical chain.
+func (bc *BlockChain) putEventIndex(block *Block) error {
+	index, err := bc.collectEventIndex(block)
+	if err != nil {
+		return err
+	}
+	for key, events := range index {
+		bytes, err := json.Marshal(events)
+		if err != nil {
+			return err
+		}
+		if err := bc.storage.Put([]byte(key), bytes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteEventIndex remove the index of events in block, which is reverted from canonical chain.
+func (bc *BlockChain) deleteEventIndex(block *Block) error {
+	index, err := bc.collectEventIndex(block)
+	if err != nil {
+		return err
+	}
+	for key := range index {
+		if err := bc.storage.Del([]byte(key)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FetchEventsByTopic return a page of the events in [fromHeight, toHeight] on canonical chain, whose topic is
+// the given one or starts with it and a dot, e.g. "chain" matches "chain.transactionResult".
+// A page has at least limit events unless the range is exhausted.
+func (bc *BlockChain) FetchEventsByTopic(topic string, fromHeight, toHeight uint64, limit int) (*EventPage, error) {
+	if len(topic) == 0 {
+		return nil, ErrInvalidArgument
+	}
+	return bc.fetchIndexedEvents(fromHeight, toHeight, limit, func(height uint64) []byte {
+		return eventTopicIndexKey(topic, height)
+	})
+}
+
+// FetchEventsByContract return a page of the events in [fromHeight, toHeight] on canonical chain emitted by
+// the contract, including the execution results of txs deploying or calling it.
+func (bc *BlockChain) FetchEventsByContract(addr *Address, fromHeight, toHeight uint64, limit int) (*EventPage, error) {
+	if addr == nil {
+		return nil, ErrNilArgument
+	}
+	return bc.fetchIndexedEvents(fromHeight, toHeight, limit, func(height uint64) []byte {
+		return eventContractIndexKey(addr.address, height)
+	})
+}
+
+func (bc *BlockChain) fetchIndexedEvents(fromHeight, toHeight uint64, limit int, key func(uint64) []byte) (*EventPage, error) {
+	if tail := bc.TailBlock().height; toHeight > tail {
+		toHeight = tail
+	}
+	if fromHeight == 0 || fromHeight > toHeight {
+		return nil, ErrInvalidFilterRange
+	}
+	if limit <= 0 {
+		return nil, ErrInvalidArgument
+	}
+
+	page := &EventPage{Events: []*IndexedEvent{}}
+	for height := fromHeight; height <= toHeight; height++ {
+		if len(page.Events) >= limit {
+			page.Next = height
+			break
+		}
+		bytes, err := bc.storage.Get(key(height))
+		if err == storage.ErrKeyNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		events := []*IndexedEvent{}
+		if err := json.Unmarshal(bytes, &events); err != nil {
+			return nil, err
+		}
+		page.Events = append(page.Events, events...)
+	}
+	return page, nil
+}
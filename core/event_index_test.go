@@ -0,0 +1,128 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"testing"
+
+	"github.com/alexlisong/go-nebulas/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockChain_FetchIndexedEvents(t *testing.T) {
+	neb := testNeb(t)
+	bc := neb.chain
+	bc.nvm = &replayNvm{}
+	bc.tailBlock.nvm = bc.nvm
+
+	from := mockAddress()
+	signature := mockSignature(t, from)
+	balance, _ := util.NewUint128FromString("1000000000000000000")
+	bc.tailBlock.Begin()
+	acc, err := bc.tailBlock.worldState.GetOrCreateUserAccount(from.address)
+	assert.Nil(t, err)
+	assert.Nil(t, acc.AddBalance(balance))
+	bc.tailBlock.Commit()
+	bc.tailBlock.header.stateRoot = bc.tailBlock.worldState.AccountsRoot()
+	assert.Nil(t, bc.StoreBlockToStorage(bc.tailBlock))
+
+	mint := func(parent *Block, txs ...*Transaction) *Block {
+		block, err := bc.NewBlockFromParent(mockAddress(), parent)
+		assert.Nil(t, err)
+		block.header.timestamp = parent.Timestamp() + BlockIntervalInSecond
+		for _, tx := range txs {
+			txWorldState, err := block.WorldState().Prepare(tx.Hash().String())
+			assert.Nil(t, err)
+			_, err = block.ExecuteTransaction(tx, txWorldState)
+			assert.Nil(t, err)
+			_, err = txWorldState.CheckAndUpdate()
+			assert.Nil(t, err)
+			txWorldState.Close()
+			assert.Nil(t, block.dependency.AddNode(tx.Hash().String()))
+			block.transactions = append(block.transactions, tx)
+		}
+		assert.Nil(t, block.Seal())
+		signBlock(block)
+		assert.Nil(t, bc.BlockPool().Push(block))
+		return bc.GetBlock(block.Hash())
+	}
+
+	deployTx := mockDeployTransaction(bc.ChainID(), 1)
+	deployTx.from, deployTx.to = from, from
+	assert.Nil(t, deployTx.Sign(signature))
+	contract, err := deployTx.GenerateContractAddress()
+	assert.Nil(t, err)
+	deployed := mint(bc.TailBlock(), deployTx)
+
+	call := func(function string) *Transaction {
+		tx := mockCallTransaction(bc.ChainID(), 2, function, "")
+		tx.from, tx.to = from, contract
+		assert.Nil(t, tx.Sign(signature))
+		return tx
+	}
+	forkA := mint(deployed, call("a"))
+	forkB := mint(deployed, call("b"))
+	height := forkA.Height()
+
+	canonical := func(topic string) []string {
+		page, err := bc.FetchEventsByTopic(topic, 1, height, 10)
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(0), page.Next)
+		data := []string{}
+		for _, event := range page.Events {
+			data = append(data, event.Data)
+		}
+		return data
+	}
+
+	assert.Nil(t, bc.SetTailBlock(forkA))
+	assert.Equal(t, []string{"a"}, canonical("chain.contract.replay"))
+	assert.Equal(t, []string{"a"}, canonical("chain.contract"))
+	assert.Empty(t, canonical("chain.contr"))
+	assert.Equal(t, 4, len(canonical("chain")))
+
+	// the reverted events are dropped after the reorg.
+	assert.Nil(t, bc.SetTailBlock(forkB))
+	assert.Equal(t, []string{"b"}, canonical("chain.contract"))
+
+	// the events of a height are kept in one page.
+	page, err := bc.FetchEventsByContract(contract, 1, height+10, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(page.Events))
+	assert.Equal(t, deployed.Height(), page.Events[0].Height)
+	assert.Equal(t, TopicContractDeploy, page.Events[0].Topic)
+	assert.Equal(t, TopicTransactionExecutionResult, page.Events[1].Topic)
+	assert.Equal(t, height, page.Next)
+
+	page, err = bc.FetchEventsByContract(contract, page.Next, height+10, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(page.Events))
+	assert.Equal(t, uint64(0), page.Next)
+	for _, event := range page.Events {
+		assert.Equal(t, forkB.Hash().String(), event.Block)
+		assert.Equal(t, contract.String(), event.Contract)
+	}
+
+	_, err = bc.FetchEventsByTopic("", 1, height, 1)
+	assert.Equal(t, ErrInvalidArgument, err)
+	_, err = bc.FetchEventsByTopic("chain", 0, height, 1)
+	assert.Equal(t, ErrInvalidFilterRange, err)
+	_, err = bc.FetchEventsByContract(contract, 1, height, 0)
+	assert.Equal(t, ErrInvalidArgument, err)
+}
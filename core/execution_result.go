@@ -0,0 +1,110 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/alexlisong/go-nebulas/util"
+)
+
+const (
+	// MaxRevertReasonLength max length of the revert reason kept in execution result.
+	MaxRevertReasonLength = 256
+)
+
+var (
+	// RevertReasonForkHeight the revert reason is recorded in tx result event from this height, disabled by default.
+	RevertReasonForkHeight uint64 = math.MaxUint64
+)
+
+// ExecutionResult is the result of a contract execution. A revert is the contract
+// failing by itself, e.g. a throw, the other errors are failures of the engine.
+type ExecutionResult struct {
+	GasUsed      *util.Uint128
+	Value        string
+	Reverted     bool
+	RevertReason string
+	Err          error
+}
+
+// RevertError is the error of a reverted contract execution, it wraps ErrExecutionFailed.
+type RevertError struct {
+	Op     string
+	Reason string
+}
+
+// Error returns the op and reason of the revert.
+func (e *RevertError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Op, e.Reason)
+}
+
+// Cause returns ErrExecutionFailed.
+func (e *RevertError) Cause() error {
+	return ErrExecutionFailed
+}
+
+// Unwrap returns ErrExecutionFailed.
+func (e *RevertError) Unwrap() error {
+	return ErrExecutionFailed
+}
+
+// newExecutionResult from the gas, returned value and error of engine op.
+func newExecutionResult(op string, gasUsed *util.Uint128, value string, err error) *ExecutionResult {
+	result := &ExecutionResult{
+		GasUsed: gasUsed,
+		Value:   value,
+		Err:     err,
+	}
+	if err != ErrExecutionFailed {
+		return result
+	}
+
+	// the engine returns the thrown message as value.
+	result.Reverted = true
+	result.RevertReason = value
+	if len(result.RevertReason) > MaxRevertReasonLength {
+		result.RevertReason = result.RevertReason[:MaxRevertReasonLength]
+	}
+	if len(result.RevertReason) > 0 {
+		result.Err = &RevertError{Op: op, Reason: result.RevertReason}
+	}
+	return result
+}
+
+// Unpack returns the gas, value and error of result in the form of TxPayload.Execute.
+func (result *ExecutionResult) Unpack() (*util.Uint128, string, error) {
+	return result.GasUsed, result.Value, result.Err
+}
+
+// revertReasonOf return the revert reason in err, empty if it isn't a revert.
+func revertReasonOf(err error) string {
+	for err != nil {
+		if revertErr, ok := err.(*RevertError); ok {
+			return revertErr.Reason
+		}
+		c, ok := err.(causer)
+		if !ok {
+			break
+		}
+		err = c.Cause()
+	}
+	return ""
+}
@@ -0,0 +1,168 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/alexlisong/go-nebulas/core/state"
+	"github.com/alexlisong/go-nebulas/util"
+	"github.com/stretchr/testify/assert"
+)
+
+var errEngineCrashed = errors.New("engine crashed")
+
+// revertNvm throws a custom error object in "throw", and fails the engine in "crash".
+type revertNvm struct{}
+
+type revertEngine struct {
+	mockEngine
+}
+
+func (nvm *revertNvm) CreateEngine(block *Block, tx *Transaction, contract state.Account, ws WorldState) (SmartContractEngine, error) {
+	return &revertEngine{}, nil
+}
+
+func (engine *revertEngine) Call(source, sourceType, function, args string) (string, error) {
+	switch function {
+	case "throw":
+		return `{"name":"NotOwnerError","message":"caller is not the owner"}`, ErrExecutionFailed
+	case "crash":
+		return "", errEngineCrashed
+	}
+	return "ok", nil
+}
+
+func TestNewExecutionResult(t *testing.T) {
+	gas := util.NewUint128FromUint(10)
+
+	result := newExecutionResult("Call", gas, "ok", nil)
+	assert.False(t, result.Reverted)
+	assert.Nil(t, result.Err)
+
+	result = newExecutionResult("Call", gas, "", errEngineCrashed)
+	assert.False(t, result.Reverted)
+	assert.Equal(t, errEngineCrashed, result.Err)
+
+	result = newExecutionResult("Call", gas, "", ErrExecutionFailed)
+	assert.True(t, result.Reverted)
+	assert.Equal(t, ErrExecutionFailed, result.Err)
+
+	reason := strings.Repeat("x", MaxRevertReasonLength+1)
+	result = newExecutionResult("Deploy", gas, reason, ErrExecutionFailed)
+	assert.True(t, result.Reverted)
+	assert.Equal(t, reason[:MaxRevertReasonLength], result.RevertReason)
+	assert.Equal(t, reason, result.Value)
+	assert.Equal(t, ErrExecutionFailed, ErrorCause(result.Err))
+	assert.Equal(t, "Deploy: "+result.RevertReason, result.Err.Error())
+	assert.Equal(t, result.RevertReason, revertReasonOf(newTxError(result.Err, false, true)))
+}
+
+func TestTransaction_RevertReason(t *testing.T) {
+	defer func(height uint64) { RevertReasonForkHeight = height }(RevertReasonForkHeight)
+	RevertReasonForkHeight = 0
+
+	neb := testNeb(t)
+	bc := neb.chain
+	bc.nvm = &revertNvm{}
+	bc.tailBlock.nvm = bc.nvm
+
+	from := mockAddress()
+	signature := mockSignature(t, from)
+	balance, _ := util.NewUint128FromString("1000000000000000000")
+	bc.tailBlock.Begin()
+	acc, err := bc.tailBlock.worldState.GetOrCreateUserAccount(from.address)
+	assert.Nil(t, err)
+	assert.Nil(t, acc.AddBalance(balance))
+	bc.tailBlock.Commit()
+	bc.tailBlock.header.stateRoot = bc.tailBlock.worldState.AccountsRoot()
+	assert.Nil(t, bc.StoreBlockToStorage(bc.tailBlock))
+
+	mint := func(parent *Block, txs ...*Transaction) *Block {
+		block, err := bc.NewBlockFromParent(mockAddress(), parent)
+		assert.Nil(t, err)
+		block.header.timestamp = parent.Timestamp() + BlockIntervalInSecond
+		for _, tx := range txs {
+			txWorldState, err := block.WorldState().Prepare(tx.Hash().String())
+			assert.Nil(t, err)
+			_, err = block.ExecuteTransaction(tx, txWorldState)
+			assert.Nil(t, err)
+			_, err = txWorldState.CheckAndUpdate()
+			assert.Nil(t, err)
+			txWorldState.Close()
+			assert.Nil(t, block.dependency.AddNode(tx.Hash().String()))
+			block.transactions = append(block.transactions, tx)
+		}
+		assert.Nil(t, block.Seal())
+		signBlock(block)
+		assert.Nil(t, bc.BlockPool().Push(block))
+		return bc.GetBlock(block.Hash())
+	}
+
+	deployTx := mockDeployTransaction(bc.ChainID(), 1)
+	deployTx.from, deployTx.to = from, from
+	assert.Nil(t, deployTx.Sign(signature))
+	contract, err := deployTx.GenerateContractAddress()
+	assert.Nil(t, err)
+	value := util.NewUint128FromUint(100)
+	call := func(nonce uint64, function string) *Transaction {
+		tx := mockCallTransaction(bc.ChainID(), nonce, function, "")
+		tx.from, tx.to, tx.value = from, contract, value
+		assert.Nil(t, tx.Sign(signature))
+		return tx
+	}
+
+	throwTx, crashTx := call(2, "throw"), call(3, "crash")
+	block := mint(mint(bc.TailBlock(), deployTx), throwTx, crashTx)
+
+	resultOf := func(tx *Transaction) *TransactionEvent {
+		event, err := block.FetchExecutionResultEvent(tx.Hash())
+		assert.Nil(t, err)
+		txEvent := &TransactionEvent{}
+		assert.Nil(t, json.Unmarshal([]byte(event.Data), txEvent))
+		return txEvent
+	}
+
+	// the revert charges gas and resets the state, with the reason kept.
+	reason := `{"name":"NotOwnerError","message":"caller is not the owner"}`
+	thrown := resultOf(throwTx)
+	assert.Equal(t, int8(TxExecutionFailed), thrown.Status)
+	assert.Equal(t, reason, thrown.RevertReason)
+	assert.Equal(t, "Call: "+reason, thrown.Error)
+	assert.NotEqual(t, "0", thrown.GasUsed)
+
+	crashed := resultOf(crashTx)
+	assert.Equal(t, int8(TxExecutionFailed), crashed.Status)
+	assert.Empty(t, crashed.RevertReason)
+	assert.Equal(t, errEngineCrashed.Error(), crashed.Error)
+
+	acc, err = block.GetAccount(contract.address)
+	assert.Nil(t, err)
+	assert.Equal(t, util.NewUint128(), acc.Balance())
+
+	// the simulation returns the reason as result.
+	assert.Nil(t, bc.SetTailBlock(block))
+	result, err := bc.SimulateTransactionExecution(call(4, "throw"))
+	assert.Nil(t, err)
+	assert.Equal(t, reason, result.Msg)
+	assert.Equal(t, reason, revertReasonOf(result.Err))
+}
@@ -0,0 +1,126 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/alexlisong/go-nebulas/core/pb"
+	"github.com/alexlisong/go-nebulas/util"
+	"github.com/gogo/protobuf/proto"
+)
+
+var (
+	// GasTraceForkHeight from this height, the gas trace of tx is recorded in its receipt, disabled by default.
+	GasTraceForkHeight uint64 = math.MaxUint64
+)
+
+// GasTrace is the breakdown of the gas charged for a tx.
+type GasTrace struct {
+	BaseGas        *util.Uint128
+	PayloadBaseGas *util.Uint128
+	ExecutionGas   *util.Uint128
+
+	// Refund is the unused part of gas limit.
+	Refund *util.Uint128
+
+	// OutOfGasLimit is true if tx runs out of its gas limit, which is charged instead of the gas used.
+	OutOfGasLimit bool
+}
+
+func newGasTrace() *GasTrace {
+	return &GasTrace{
+		BaseGas:        util.NewUint128(),
+		PayloadBaseGas: util.NewUint128(),
+		ExecutionGas:   util.NewUint128(),
+		Refund:         util.NewUint128(),
+	}
+}
+
+// settle records the charged gas within gasLimit.
+func (trace *GasTrace) settle(gasLimit, gas *util.Uint128, outOfGasLimit bool) {
+	trace.OutOfGasLimit = outOfGasLimit
+	trace.Refund = util.NewUint128()
+	if refund, err := gasLimit.Sub(gas); err == nil {
+		trace.Refund = refund
+	}
+}
+
+// ToProto converts domain gas trace to proto gas trace
+func (trace *GasTrace) ToProto() (proto.Message, error) {
+	baseGas, err := trace.BaseGas.ToFixedSizeByteSlice()
+	if err != nil {
+		return nil, err
+	}
+	payloadBaseGas, err := trace.PayloadBaseGas.ToFixedSizeByteSlice()
+	if err != nil {
+		return nil, err
+	}
+	executionGas, err := trace.ExecutionGas.ToFixedSizeByteSlice()
+	if err != nil {
+		return nil, err
+	}
+	refund, err := trace.Refund.ToFixedSizeByteSlice()
+	if err != nil {
+		return nil, err
+	}
+	return &corepb.GasTrace{
+		BaseGas:        baseGas,
+		PayloadBaseGas: payloadBaseGas,
+		ExecutionGas:   executionGas,
+		Refund:         refund,
+		OutOfGasLimit:  trace.OutOfGasLimit,
+	}, nil
+}
+
+// FromProto converts proto gas trace to domain gas trace
+func (trace *GasTrace) FromProto(msg proto.Message) error {
+	if msg, ok := msg.(*corepb.GasTrace); ok {
+		if msg != nil {
+			var err error
+			if trace.BaseGas, err = util.NewUint128FromFixedSizeByteSlice(msg.BaseGas); err != nil {
+				return err
+			}
+			if trace.PayloadBaseGas, err = util.NewUint128FromFixedSizeByteSlice(msg.PayloadBaseGas); err != nil {
+				return err
+			}
+			if trace.ExecutionGas, err = util.NewUint128FromFixedSizeByteSlice(msg.ExecutionGas); err != nil {
+				return err
+			}
+			if trace.Refund, err = util.NewUint128FromFixedSizeByteSlice(msg.Refund); err != nil {
+				return err
+			}
+			trace.OutOfGasLimit = msg.OutOfGasLimit
+			return nil
+		}
+		return ErrInvalidProtoToGasTrace
+	}
+	return ErrInvalidProtoToGasTrace
+}
+
+func (trace *GasTrace) String() string {
+	return fmt.Sprintf(`{"base_gas": "%s", "payload_base_gas": "%s", "execution_gas": "%s", "refund": "%s", "out_of_gas_limit": %t}`,
+		trace.BaseGas,
+		trace.PayloadBaseGas,
+		trace.ExecutionGas,
+		trace.Refund,
+		trace.OutOfGasLimit,
+	)
+}
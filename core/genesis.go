@@ -39,6 +39,9 @@ var (
 	GenesisHash        = make([]byte, BlockHashLength)
 	GenesisTimestamp   = int64(0)
 	GenesisCoinbase, _ = NewAddressFromPublicKey(make([]byte, PublicKeyDataLength))
+
+	// GenesisChainConfigTxNonce the nonce of genesis tx carrying the chain config.
+	GenesisChainConfigTxNonce = uint64(2)
 )
 
 // LoadGenesisConf load genesis conf for file
@@ -182,11 +185,39 @@ func NewGenesisBlock(conf *corepb.Genesis, chain *BlockChain) (*Block, error) {
 		return nil, err
 	}
 
+	// the chain config is hashed into genesis txs, if it's not the default one.
+	config, err := NewChainConfig(conf)
+	if err != nil {
+		return nil, err
+	}
+	if !config.IsDefault() {
+		configTx, err := newGenesisChainConfigTx(chain.ChainID(), config)
+		if err != nil {
+			return nil, err
+		}
+		pbTx, err := configTx.ToProto()
+		if err != nil {
+			return nil, err
+		}
+		txBytes, err := proto.Marshal(pbTx)
+		if err != nil {
+			return nil, err
+		}
+		genesisBlock.transactions = append(genesisBlock.transactions, configTx)
+		if err := genesisBlock.worldState.PutTx(configTx.hash, txBytes); err != nil {
+			return nil, err
+		}
+	}
+
 	genesisBlock.Commit()
 
 	genesisBlock.header.stateRoot = genesisBlock.WorldState().AccountsRoot()
 	genesisBlock.header.txsRoot = genesisBlock.WorldState().TxsRoot()
 	genesisBlock.header.eventsRoot = genesisBlock.WorldState().EventsRoot()
+	if genesisBlock.height >= ReceiptsForkHeight {
+		genesisBlock.header.receiptsRoot = genesisBlock.WorldState().ReceiptsRoot()
+	}
+	genesisBlock.header.delegateRoot = genesisBlock.WorldState().DelegateRoot()
 	genesisBlock.header.consensusRoot = genesisBlock.WorldState().ConsensusRoot()
 
 	genesisBlock.sealed = true
@@ -194,6 +225,31 @@ func NewGenesisBlock(conf *corepb.Genesis, chain *BlockChain) (*Block, error) {
 	return genesisBlock, nil
 }
 
+func newGenesisChainConfigTx(chainID uint32, config *ChainConfig) (*Transaction, error) {
+	payload, err := proto.Marshal(config.ToProto())
+	if err != nil {
+		return nil, err
+	}
+	tx, err := NewTransaction(
+		chainID,
+		GenesisCoinbase, GenesisCoinbase,
+		util.Uint128Zero(), GenesisChainConfigTxNonce,
+		TxPayloadBinaryType,
+		payload,
+		TransactionGasPrice,
+		MinGasCountPerTransaction,
+	)
+	if err != nil {
+		return nil, err
+	}
+	tx.timestamp = 0
+	if tx.hash, err = tx.calHash(); err != nil {
+		return nil, err
+	}
+	tx.alg = keystore.SECP256K1
+	return tx, nil
+}
+
 // CheckGenesisBlock if a block is a genesis block
 func CheckGenesisBlock(block *Block) bool {
 	if block == nil {
@@ -253,12 +309,22 @@ func DumpGenesis(chain *BlockChain) (*corepb.Genesis, error) {
 			Value:   balance.String(),
 		})
 	}
+	var chainConfig *corepb.GenesisChainConfig
+	for _, tx := range genesis.transactions {
+		if CheckGenesisTransaction(tx) && tx.nonce == GenesisChainConfigTxNonce {
+			chainConfig = new(corepb.GenesisChainConfig)
+			if err := proto.Unmarshal(tx.Data(), chainConfig); err != nil {
+				return nil, err
+			}
+		}
+	}
 	return &corepb.Genesis{
 		Meta: &corepb.GenesisMeta{ChainId: genesis.ChainID()},
 		Consensus: &corepb.GenesisConsensus{
 			Dpos: &corepb.GenesisConsensusDpos{Dynasty: bootstrap},
 		},
 		TokenDistribution: distribution,
+		ChainConfig:       chainConfig,
 	}, nil
 }
 
@@ -293,6 +359,19 @@ func CheckGenesisConfByDB(pGenesisDB *corepb.Genesis, pGenesis *corepb.Genesis)
 
 		}
 
+		// check chain config equal
+		configDB, err := NewChainConfig(pGenesisDB)
+		if err != nil {
+			return err
+		}
+		config, err := NewChainConfig(pGenesis)
+		if err != nil {
+			return err
+		}
+		if !config.Equals(configDB) {
+			return ErrGenesisNotEqualChainConfigInDB
+		}
+
 		// check distribution equal
 		for _, confDistribution := range pGenesis.TokenDistribution {
 			contains := false
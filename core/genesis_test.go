@@ -21,6 +21,8 @@ package core
 import (
 	"testing"
 
+	"github.com/alexlisong/go-nebulas/core/pb"
+	"github.com/alexlisong/go-nebulas/util"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -50,3 +52,96 @@ func TestInvalidAddressInTokenDistribution(t *testing.T) {
 	_, err := NewGenesisBlock(mockConf, chain)
 	assert.Equal(t, err, ErrInvalidAddressFormat)
 }
+
+func TestGenesisChainConfig(t *testing.T) {
+	chain := testNeb(t).chain
+	defaultGenesis := chain.genesisBlock
+	assert.Equal(t, 1, len(defaultGenesis.transactions))
+	assert.True(t, chain.ChainConfig().IsDefault())
+
+	// explicit defaults are the same as omitted.
+	conf := MockGenesisConf()
+	conf.ChainConfig = DefaultChainConfig(conf.Meta.ChainId).ToProto()
+	genesis, err := NewGenesisBlock(conf, chain)
+	assert.Nil(t, err)
+	assert.Equal(t, defaultGenesis.TxsRoot(), genesis.TxsRoot())
+
+	admin := mockAddress()
+	conf.ChainConfig = &corepb.GenesisChainConfig{
+		MinGasCountPerTransaction: "100",
+		MaxDataPayloadLength:      16,
+		FreezeAdmins:              []string{admin.String()},
+	}
+	config, err := NewChainConfig(conf)
+	assert.Nil(t, err)
+	assert.False(t, config.IsDefault())
+	assert.True(t, config.IsFreezeAdmin(admin))
+	assert.False(t, config.IsFreezeAdmin(mockAddress()))
+	assert.Equal(t, TransactionMaxGasPrice, config.TransactionMaxGasPrice)
+	assert.Equal(t, GasCountPerByte, config.GasCountPerByte)
+	assert.Equal(t, "100", config.MinGasCountPerTransaction.String())
+	assert.Equal(t, 16, config.MaxDataPayLoadLength)
+
+	// the config is hashed into genesis txs.
+	genesis, err = NewGenesisBlock(conf, chain)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(genesis.transactions))
+	assert.NotEqual(t, defaultGenesis.TxsRoot(), genesis.TxsRoot())
+
+	assert.Nil(t, chain.StoreBlockToStorage(genesis))
+	dumpConf, err := DumpGenesis(chain)
+	assert.Nil(t, err)
+	dumpConfig, err := NewChainConfig(dumpConf)
+	assert.Nil(t, err)
+	assert.True(t, config.Equals(dumpConfig))
+	assert.Nil(t, CheckGenesisConfByDB(dumpConf, conf))
+	assert.Equal(t, ErrGenesisNotEqualChainConfigInDB, CheckGenesisConfByDB(dumpConf, MockGenesisConf()))
+
+	conf.ChainConfig = &corepb.GenesisChainConfig{TransactionMaxGasPrice: "0"}
+	_, err = NewChainConfig(conf)
+	assert.Equal(t, ErrInvalidChainConfig, err)
+
+	conf.ChainConfig = &corepb.GenesisChainConfig{FreezeAdmins: []string{"n1invalid"}}
+	_, err = NewChainConfig(conf)
+	assert.Equal(t, ErrInvalidChainConfig, err)
+}
+
+func TestChainConfigValidation(t *testing.T) {
+	chainID := uint32(1000)
+	conf := MockGenesisConf()
+	conf.Meta.ChainId = chainID
+	conf.ChainConfig = &corepb.GenesisChainConfig{
+		MinGasCountPerTransaction: "100",
+		GasCountPerByte:           "2",
+		MaxDataPayloadLength:      16,
+	}
+	config, err := NewChainConfig(conf)
+	assert.Nil(t, err)
+	RegisterChainConfig(config)
+	defer func() {
+		chainConfigsLock.Lock()
+		delete(chainConfigs, chainID)
+		chainConfigsLock.Unlock()
+	}()
+
+	from, to := mockAddress(), mockAddress()
+	_, err = NewTransaction(chainID, from, to, util.NewUint128(), 1, TxPayloadBinaryType, make([]byte, 17), TransactionGasPrice, TransactionMaxGas)
+	assert.Equal(t, ErrTxDataPayLoadOutOfMaxLength, err)
+	tx, err := NewTransaction(chainID, from, to, util.NewUint128(), 1, TxPayloadBinaryType, make([]byte, 16), TransactionGasPrice, TransactionMaxGas)
+	assert.Nil(t, err)
+	gas, err := tx.GasCountOfTxBase()
+	assert.Nil(t, err)
+	assert.Equal(t, "132", gas.String())
+
+	// other chains keep the defaults.
+	tx, err = NewTransaction(chainID+1, from, to, util.NewUint128(), 1, TxPayloadBinaryType, make([]byte, 17), TransactionGasPrice, TransactionMaxGas)
+	assert.Nil(t, err)
+	gas, err = tx.GasCountOfTxBase()
+	assert.Nil(t, err)
+	assert.Equal(t, "20017", gas.String())
+
+	pbTx, err := tx.ToProto()
+	assert.Nil(t, err)
+	pbTx.(*corepb.Transaction).ChainId = chainID
+	assert.Equal(t, ErrTxDataPayLoadOutOfMaxLength, new(Transaction).FromProto(pbTx))
+}
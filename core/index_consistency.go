@@ -0,0 +1,221 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"fmt"
+
+	"github.com/alexlisong/go-nebulas/common/trie"
+	"github.com/alexlisong/go-nebulas/storage"
+	"github.com/alexlisong/go-nebulas/util/byteutils"
+	"github.com/alexlisong/go-nebulas/util/logging"
+	"github.com/sirupsen/logrus"
+)
+
+// Index discrepancy kinds
+const (
+	// IndexMissingHeight the canonical height index has no entry for the block.
+	IndexMissingHeight = "missing_height_index"
+
+	// IndexWrongHeight the canonical height index points to another block.
+	IndexWrongHeight = "wrong_height_index"
+
+	// IndexMissingTx the tx in block body is not in the block's txs trie.
+	IndexMissingTx = "missing_tx"
+
+	// IndexMissingReceipt the tx in block body has no receipt in the block's receipts trie.
+	IndexMissingReceipt = "missing_receipt"
+
+	// IndexWrongReceipt the receipt of tx is recorded at another height.
+	IndexWrongReceipt = "wrong_receipt"
+)
+
+// IndexDiscrepancy is a mismatch found between the canonical chain and a store.
+// Tx is nil for block level discrepancies.
+type IndexDiscrepancy struct {
+	Kind     string
+	Height   uint64
+	Block    byteutils.Hash
+	Tx       byteutils.Hash
+	Detail   string
+	Repaired bool
+}
+
+func (d *IndexDiscrepancy) String() string {
+	if d.Tx == nil {
+		return fmt.Sprintf(`{"kind":"%s", "height":%d, "block":"%s", "detail":"%s", "repaired":%t}`,
+			d.Kind, d.Height, d.Block, d.Detail, d.Repaired)
+	}
+	return fmt.Sprintf(`{"kind":"%s", "height":%d, "block":"%s", "tx":"%s", "detail":"%s", "repaired":%t}`,
+		d.Kind, d.Height, d.Block, d.Tx, d.Detail, d.Repaired)
+}
+
+// IndexConsistencyReport is the result of VerifyIndexConsistency.
+type IndexConsistencyReport struct {
+	FromHeight    uint64
+	ToHeight      uint64
+	Blocks        uint64
+	Txs           uint64
+	Discrepancies []*IndexDiscrepancy
+}
+
+// Repaired return the count of repaired discrepancies.
+func (r *IndexConsistencyReport) Repaired() int {
+	count := 0
+	for _, d := range r.Discrepancies {
+		if d.Repaired {
+			count++
+		}
+	}
+	return count
+}
+
+// VerifyIndexConsistency walks the canonical blocks in [fromHeight, toHeight] from the tail by parent hash,
+// checks the canonical height index, and that every tx in block body is in the txs trie with a receipt.
+// toHeight 0 means the tail height. If repair is true, the height index is rebuilt from the walked chain.
+// The tries are consensus data committed in block header, so their discrepancies are only reported.
+func VerifyIndexConsistency(chain *BlockChain, fromHeight, toHeight uint64, repair bool) (*IndexConsistencyReport, error) {
+	if chain == nil {
+		return nil, ErrNilArgument
+	}
+	tail := chain.TailBlock()
+	if toHeight == 0 || toHeight > tail.Height() {
+		toHeight = tail.Height()
+	}
+	if fromHeight == 0 {
+		fromHeight = 1
+	}
+	if fromHeight > toHeight {
+		return nil, ErrInvalidArgument
+	}
+
+	report := &IndexConsistencyReport{
+		FromHeight: fromHeight,
+		ToHeight:   toHeight,
+	}
+	stor := chain.Storage()
+
+	block := tail
+	for block.Height() > toHeight {
+		if block = chain.GetBlock(block.ParentHash()); block == nil {
+			return nil, ErrMissingParentBlock
+		}
+	}
+	for {
+		report.Blocks++
+		if err := verifyHeightIndex(stor, block, repair, report); err != nil {
+			return nil, err
+		}
+		if err := verifyBlockTxs(block, report); err != nil {
+			return nil, err
+		}
+
+		if block.Height() <= fromHeight {
+			break
+		}
+		if block = chain.GetBlock(block.ParentHash()); block == nil {
+			return nil, ErrMissingParentBlock
+		}
+	}
+
+	logging.VLog().WithFields(logrus.Fields{
+		"from":          fromHeight,
+		"to":            toHeight,
+		"blocks":        report.Blocks,
+		"txs":           report.Txs,
+		"discrepancies": len(report.Discrepancies),
+		"repaired":      report.Repaired(),
+	}).Info("Verified index consistency.")
+	return report, nil
+}
+
+func verifyHeightIndex(stor storage.Storage, block *Block, repair bool, report *IndexConsistencyReport) error {
+	var d *IndexDiscrepancy
+	hash, err := stor.Get(heightStorageKey(block.Height()))
+	switch {
+	case err == storage.ErrKeyNotFound:
+		d = &IndexDiscrepancy{Kind: IndexMissingHeight}
+	case err != nil:
+		return err
+	case !block.Hash().Equals(hash):
+		d = &IndexDiscrepancy{Kind: IndexWrongHeight, Detail: "indexed " + byteutils.Hash(hash).String()}
+	default:
+		return nil
+	}
+	d.Height = block.Height()
+	d.Block = block.Hash()
+
+	if repair {
+		if err := stor.Put(heightStorageKey(block.Height()), block.Hash()); err != nil {
+			return err
+		}
+		d.Repaired = true
+	}
+	report.Discrepancies = append(report.Discrepancies, d)
+	return nil
+}
+
+func verifyBlockTxs(block *Block, report *IndexConsistencyReport) error {
+	for _, tx := range block.Transactions() {
+		report.Txs++
+		if _, err := block.GetTransaction(tx.Hash()); err != nil {
+			if !isTrieKeyNotFound(err) {
+				return err
+			}
+			report.Discrepancies = append(report.Discrepancies, &IndexDiscrepancy{
+				Kind:   IndexMissingTx,
+				Height: block.Height(),
+				Block:  block.Hash(),
+				Tx:     tx.Hash(),
+			})
+		}
+
+		// the declaration tx in genesis is not executed, and the receipts before the fork are not recorded.
+		if CheckGenesisBlock(block) || block.Height() < ReceiptsForkHeight {
+			continue
+		}
+		receipt, err := block.GetTransactionReceipt(tx.Hash())
+		if err != nil {
+			if !isTrieKeyNotFound(err) {
+				return err
+			}
+			report.Discrepancies = append(report.Discrepancies, &IndexDiscrepancy{
+				Kind:   IndexMissingReceipt,
+				Height: block.Height(),
+				Block:  block.Hash(),
+				Tx:     tx.Hash(),
+			})
+			continue
+		}
+		if receipt.BlockHeight() != block.Height() {
+			report.Discrepancies = append(report.Discrepancies, &IndexDiscrepancy{
+				Kind:   IndexWrongReceipt,
+				Height: block.Height(),
+				Block:  block.Hash(),
+				Tx:     tx.Hash(),
+				Detail: fmt.Sprintf("recorded at %d", receipt.BlockHeight()),
+			})
+		}
+	}
+	return nil
+}
+
+func isTrieKeyNotFound(err error) bool {
+	return err == storage.ErrKeyNotFound || err == trie.ErrNotFound
+}
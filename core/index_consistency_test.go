@@ -0,0 +1,149 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"testing"
+
+	"github.com/alexlisong/go-nebulas/crypto"
+	"github.com/alexlisong/go-nebulas/crypto/keystore"
+	"github.com/alexlisong/go-nebulas/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyIndexConsistency_HeightIndex(t *testing.T) {
+	neb := testNeb(t)
+	bc := neb.chain
+
+	block1, err := bc.NewBlock(mockAddress())
+	assert.Nil(t, err)
+	block1.header.timestamp = BlockInterval
+	assert.Nil(t, block1.Seal())
+	signBlock(block1)
+	assert.Nil(t, bc.BlockPool().Push(block1))
+
+	block2, err := bc.NewBlock(mockAddress())
+	assert.Nil(t, err)
+	block2.header.timestamp = BlockInterval * 2
+	assert.Nil(t, block2.Seal())
+	signBlock(block2)
+	assert.Nil(t, bc.BlockPool().Push(block2))
+	assert.Equal(t, block2.Hash(), bc.TailBlock().Hash())
+
+	// healthy chain, repair is a no-op.
+	report, err := VerifyIndexConsistency(bc, 0, 0, true)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), report.FromHeight)
+	assert.Equal(t, block2.Height(), report.ToHeight)
+	assert.Equal(t, uint64(3), report.Blocks)
+	assert.Equal(t, uint64(1), report.Txs)
+	assert.Equal(t, 0, len(report.Discrepancies))
+
+	// missing height index.
+	assert.Nil(t, bc.storage.Del(heightStorageKey(block1.Height())))
+	report, err = VerifyIndexConsistency(bc, 0, 0, false)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(report.Discrepancies))
+	assert.Equal(t, IndexMissingHeight, report.Discrepancies[0].Kind)
+	assert.Equal(t, block1.Hash(), report.Discrepancies[0].Block)
+	assert.Equal(t, 0, report.Repaired())
+	assert.Nil(t, bc.GetBlockOnCanonicalChainByHeight(block1.Height()))
+
+	report, err = VerifyIndexConsistency(bc, 0, 0, true)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, report.Repaired())
+	assert.Equal(t, block1.Hash(), bc.GetBlockOnCanonicalChainByHeight(block1.Height()).Hash())
+
+	// height index points to another block.
+	assert.Nil(t, bc.storage.Put(heightStorageKey(block2.Height()), block1.Hash()))
+	report, err = VerifyIndexConsistency(bc, block2.Height(), 0, true)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), report.Blocks)
+	assert.Equal(t, 1, len(report.Discrepancies))
+	assert.Equal(t, IndexWrongHeight, report.Discrepancies[0].Kind)
+	assert.True(t, report.Discrepancies[0].Repaired)
+	assert.Equal(t, block2.Hash(), bc.GetBlockOnCanonicalChainByHeight(block2.Height()).Hash())
+
+	report, err = VerifyIndexConsistency(bc, 0, 0, false)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(report.Discrepancies))
+
+	_, err = VerifyIndexConsistency(bc, 3, 2, false)
+	assert.Equal(t, ErrInvalidArgument, err)
+	_, err = VerifyIndexConsistency(nil, 0, 0, false)
+	assert.Equal(t, ErrNilArgument, err)
+}
+
+func TestVerifyIndexConsistency_Txs(t *testing.T) {
+	defer func(height uint64) { ReceiptsForkHeight = height }(ReceiptsForkHeight)
+	ReceiptsForkHeight = 2
+
+	neb := testNeb(t)
+	bc := neb.chain
+
+	from := mockAddress()
+	ks := keystore.DefaultKS
+	key, _ := ks.GetUnlocked(from.String())
+	signature, _ := crypto.NewSignature(keystore.SECP256K1)
+	signature.InitSign(key.(keystore.PrivateKey))
+
+	block, err := bc.NewBlock(mockAddress())
+	assert.Nil(t, err)
+	fromAcc, err := block.worldState.GetOrCreateUserAccount(from.address)
+	assert.Nil(t, err)
+	balance, _ := util.NewUint128FromString("1000000000000000000")
+	assert.Nil(t, fromAcc.AddBalance(balance))
+
+	tx := mockNormalTransaction(bc.chainID, 1)
+	tx.from = from
+	assert.Nil(t, tx.Sign(signature))
+	txWorldState, err := block.WorldState().Prepare(tx.Hash().String())
+	assert.Nil(t, err)
+	_, err = VerifyExecution(tx, block, txWorldState)
+	assert.Nil(t, err)
+	_, err = txWorldState.CheckAndUpdate()
+	assert.Nil(t, err)
+
+	// ghost is in block body but never executed.
+	ghost := mockNormalTransaction(bc.chainID, 2)
+	ghost.from = from
+	assert.Nil(t, ghost.Sign(signature))
+	block.transactions = append(block.transactions, tx, ghost)
+	assert.Nil(t, block.Seal())
+	signBlock(block)
+
+	assert.Nil(t, bc.StoreBlockToStorage(block))
+	assert.Nil(t, bc.storage.Put(heightStorageKey(block.Height()), block.Hash()))
+	bc.tailBlock, err = LoadBlockFromStorage(block.Hash(), bc)
+	assert.Nil(t, err)
+
+	report, err := VerifyIndexConsistency(bc, block.Height(), block.Height(), true)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2), report.Txs)
+	assert.Equal(t, 2, len(report.Discrepancies))
+	assert.Equal(t, IndexMissingTx, report.Discrepancies[0].Kind)
+	assert.Equal(t, ghost.Hash(), report.Discrepancies[0].Tx)
+	assert.Equal(t, IndexMissingReceipt, report.Discrepancies[1].Kind)
+	assert.Equal(t, ghost.Hash(), report.Discrepancies[1].Tx)
+
+	// the tries are consensus data, never repaired.
+	assert.Equal(t, 0, report.Repaired())
+	_, err = bc.tailBlock.GetTransactionReceipt(ghost.Hash())
+	assert.NotNil(t, err)
+}
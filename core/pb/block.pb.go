@@ -16,6 +16,10 @@ It has these top-level messages:
 	NetBlocks
 	NetBlock
 	DownloadBlock
+	TransactionReceipt
+	GasTrace
+	LightBlock
+	StateProof
 */
 package corepb
 
@@ -121,6 +125,10 @@ type Transaction struct {
 	GasLimit  []byte `protobuf:"bytes,10,opt,name=gas_limit,json=gasLimit,proto3" json:"gas_limit,omitempty"`
 	Alg       uint32 `protobuf:"varint,11,opt,name=alg,proto3" json:"alg,omitempty"`
 	Sign      []byte `protobuf:"bytes,12,opt,name=sign,proto3" json:"sign,omitempty"`
+	ExpiredAt int64  `protobuf:"varint,13,opt,name=expired_at,json=expiredAt,proto3" json:"expired_at,omitempty"`
+	// optional fee payer, who signs the hash with payer_sign and pays the gas.
+	Payer     []byte `protobuf:"bytes,14,opt,name=payer,proto3" json:"payer,omitempty"`
+	PayerSign []byte `protobuf:"bytes,15,opt,name=payer_sign,json=payerSign,proto3" json:"payer_sign,omitempty"`
 }
 
 func (m *Transaction) Reset()                    { *m = Transaction{} }
@@ -212,6 +220,27 @@ func (m *Transaction) GetSign() []byte {
 	return nil
 }
 
+func (m *Transaction) GetExpiredAt() int64 {
+	if m != nil {
+		return m.ExpiredAt
+	}
+	return 0
+}
+
+func (m *Transaction) GetPayer() []byte {
+	if m != nil {
+		return m.Payer
+	}
+	return nil
+}
+
+func (m *Transaction) GetPayerSign() []byte {
+	if m != nil {
+		return m.PayerSign
+	}
+	return nil
+}
+
 type BlockHeader struct {
 	Hash          []byte                     `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
 	ParentHash    []byte                     `protobuf:"bytes,2,opt,name=parent_hash,json=parentHash,proto3" json:"parent_hash,omitempty"`
@@ -224,6 +253,13 @@ type BlockHeader struct {
 	TxsRoot       []byte                     `protobuf:"bytes,10,opt,name=txs_root,json=txsRoot,proto3" json:"txs_root,omitempty"`
 	EventsRoot    []byte                     `protobuf:"bytes,11,opt,name=events_root,json=eventsRoot,proto3" json:"events_root,omitempty"`
 	ConsensusRoot *consensuspb.ConsensusRoot `protobuf:"bytes,12,opt,name=consensus_root,json=consensusRoot" json:"consensus_root,omitempty"`
+	ReceiptsRoot  []byte                     `protobuf:"bytes,13,opt,name=receipts_root,json=receiptsRoot,proto3" json:"receipts_root,omitempty"`
+	DelegateRoot  []byte                     `protobuf:"bytes,14,opt,name=delegate_root,json=delegateRoot,proto3" json:"delegate_root,omitempty"`
+	// max and actual sum of gas used by txs, uint128, set from BlockGasLimitForkHeight.
+	GasLimit []byte `protobuf:"bytes,15,opt,name=gas_limit,json=gasLimit,proto3" json:"gas_limit,omitempty"`
+	GasUsed  []byte `protobuf:"bytes,16,opt,name=gas_used,json=gasUsed,proto3" json:"gas_used,omitempty"`
+	// bloom filter of the txs' from, to addresses and event topics, set from BlockBloomForkHeight.
+	Bloom []byte `protobuf:"bytes,17,opt,name=bloom,proto3" json:"bloom,omitempty"`
 }
 
 func (m *BlockHeader) Reset()                    { *m = BlockHeader{} }
@@ -308,6 +344,41 @@ func (m *BlockHeader) GetConsensusRoot() *consensuspb.ConsensusRoot {
 	return nil
 }
 
+func (m *BlockHeader) GetReceiptsRoot() []byte {
+	if m != nil {
+		return m.ReceiptsRoot
+	}
+	return nil
+}
+
+func (m *BlockHeader) GetDelegateRoot() []byte {
+	if m != nil {
+		return m.DelegateRoot
+	}
+	return nil
+}
+
+func (m *BlockHeader) GetGasLimit() []byte {
+	if m != nil {
+		return m.GasLimit
+	}
+	return nil
+}
+
+func (m *BlockHeader) GetGasUsed() []byte {
+	if m != nil {
+		return m.GasUsed
+	}
+	return nil
+}
+
+func (m *BlockHeader) GetBloom() []byte {
+	if m != nil {
+		return m.Bloom
+	}
+	return nil
+}
+
 type Block struct {
 	Header       *BlockHeader   `protobuf:"bytes,1,opt,name=header" json:"header,omitempty"`
 	Transactions []*Transaction `protobuf:"bytes,2,rep,name=transactions" json:"transactions,omitempty"`
@@ -436,6 +507,200 @@ func (m *DownloadBlock) GetSign() []byte {
 	return nil
 }
 
+type TransactionReceipt struct {
+	Hash            []byte    `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	Status          uint32    `protobuf:"varint,2,opt,name=status,proto3" json:"status,omitempty"`
+	GasUsed         []byte    `protobuf:"bytes,3,opt,name=gas_used,json=gasUsed,proto3" json:"gas_used,omitempty"`
+	ContractAddress []byte    `protobuf:"bytes,4,opt,name=contract_address,json=contractAddress,proto3" json:"contract_address,omitempty"`
+	BlockHeight     uint64    `protobuf:"varint,5,opt,name=block_height,json=blockHeight,proto3" json:"block_height,omitempty"`
+	Error           string    `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	GasTrace        *GasTrace `protobuf:"bytes,7,opt,name=gas_trace,json=gasTrace" json:"gas_trace,omitempty"`
+}
+
+func (m *TransactionReceipt) Reset()                    { *m = TransactionReceipt{} }
+func (m *TransactionReceipt) String() string            { return proto.CompactTextString(m) }
+func (*TransactionReceipt) ProtoMessage()               {}
+func (*TransactionReceipt) Descriptor() ([]byte, []int) { return fileDescriptorBlock, []int{8} }
+
+func (m *TransactionReceipt) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
+func (m *TransactionReceipt) GetStatus() uint32 {
+	if m != nil {
+		return m.Status
+	}
+	return 0
+}
+
+func (m *TransactionReceipt) GetGasUsed() []byte {
+	if m != nil {
+		return m.GasUsed
+	}
+	return nil
+}
+
+func (m *TransactionReceipt) GetContractAddress() []byte {
+	if m != nil {
+		return m.ContractAddress
+	}
+	return nil
+}
+
+func (m *TransactionReceipt) GetBlockHeight() uint64 {
+	if m != nil {
+		return m.BlockHeight
+	}
+	return 0
+}
+
+func (m *TransactionReceipt) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *TransactionReceipt) GetGasTrace() *GasTrace {
+	if m != nil {
+		return m.GasTrace
+	}
+	return nil
+}
+
+type GasTrace struct {
+	BaseGas        []byte `protobuf:"bytes,1,opt,name=base_gas,json=baseGas,proto3" json:"base_gas,omitempty"`
+	PayloadBaseGas []byte `protobuf:"bytes,2,opt,name=payload_base_gas,json=payloadBaseGas,proto3" json:"payload_base_gas,omitempty"`
+	ExecutionGas   []byte `protobuf:"bytes,3,opt,name=execution_gas,json=executionGas,proto3" json:"execution_gas,omitempty"`
+	Refund         []byte `protobuf:"bytes,4,opt,name=refund,proto3" json:"refund,omitempty"`
+	OutOfGasLimit  bool   `protobuf:"varint,5,opt,name=out_of_gas_limit,json=outOfGasLimit,proto3" json:"out_of_gas_limit,omitempty"`
+}
+
+func (m *GasTrace) Reset()                    { *m = GasTrace{} }
+func (m *GasTrace) String() string            { return proto.CompactTextString(m) }
+func (*GasTrace) ProtoMessage()               {}
+func (*GasTrace) Descriptor() ([]byte, []int) { return fileDescriptorBlock, []int{9} }
+
+func (m *GasTrace) GetBaseGas() []byte {
+	if m != nil {
+		return m.BaseGas
+	}
+	return nil
+}
+
+func (m *GasTrace) GetPayloadBaseGas() []byte {
+	if m != nil {
+		return m.PayloadBaseGas
+	}
+	return nil
+}
+
+func (m *GasTrace) GetExecutionGas() []byte {
+	if m != nil {
+		return m.ExecutionGas
+	}
+	return nil
+}
+
+func (m *GasTrace) GetRefund() []byte {
+	if m != nil {
+		return m.Refund
+	}
+	return nil
+}
+
+func (m *GasTrace) GetOutOfGasLimit() bool {
+	if m != nil {
+		return m.OutOfGasLimit
+	}
+	return false
+}
+
+// LightBlock decodes the header and height of a stored block, the transactions are skipped.
+type LightBlock struct {
+	Header *BlockHeader `protobuf:"bytes,1,opt,name=header" json:"header,omitempty"`
+	Height uint64       `protobuf:"varint,4,opt,name=height,proto3" json:"height,omitempty"`
+}
+
+func (m *LightBlock) Reset()                    { *m = LightBlock{} }
+func (m *LightBlock) String() string            { return proto.CompactTextString(m) }
+func (*LightBlock) ProtoMessage()               {}
+func (*LightBlock) Descriptor() ([]byte, []int) { return fileDescriptorBlock, []int{10} }
+
+func (m *LightBlock) GetHeader() *BlockHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *LightBlock) GetHeight() uint64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+// StateProof proves the value of key in a state trie of block, the value of an absent key is empty.
+type StateProof struct {
+	BlockHash []byte   `protobuf:"bytes,1,opt,name=block_hash,json=blockHash,proto3" json:"block_hash,omitempty"`
+	Height    uint64   `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	Root      []byte   `protobuf:"bytes,3,opt,name=root,proto3" json:"root,omitempty"`
+	Key       []byte   `protobuf:"bytes,4,opt,name=key,proto3" json:"key,omitempty"`
+	Value     []byte   `protobuf:"bytes,5,opt,name=value,proto3" json:"value,omitempty"`
+	Nodes     [][]byte `protobuf:"bytes,6,rep,name=nodes" json:"nodes,omitempty"`
+}
+
+func (m *StateProof) Reset()                    { *m = StateProof{} }
+func (m *StateProof) String() string            { return proto.CompactTextString(m) }
+func (*StateProof) ProtoMessage()               {}
+func (*StateProof) Descriptor() ([]byte, []int) { return fileDescriptorBlock, []int{11} }
+
+func (m *StateProof) GetBlockHash() []byte {
+	if m != nil {
+		return m.BlockHash
+	}
+	return nil
+}
+
+func (m *StateProof) GetHeight() uint64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *StateProof) GetRoot() []byte {
+	if m != nil {
+		return m.Root
+	}
+	return nil
+}
+
+func (m *StateProof) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *StateProof) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (m *StateProof) GetNodes() [][]byte {
+	if m != nil {
+		return m.Nodes
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Account)(nil), "corepb.Account")
 	proto.RegisterType((*Data)(nil), "corepb.Data")
@@ -445,54 +710,80 @@ func init() {
 	proto.RegisterType((*NetBlocks)(nil), "corepb.NetBlocks")
 	proto.RegisterType((*NetBlock)(nil), "corepb.NetBlock")
 	proto.RegisterType((*DownloadBlock)(nil), "corepb.DownloadBlock")
+	proto.RegisterType((*TransactionReceipt)(nil), "corepb.TransactionReceipt")
+	proto.RegisterType((*GasTrace)(nil), "corepb.GasTrace")
+	proto.RegisterType((*LightBlock)(nil), "corepb.LightBlock")
+	proto.RegisterType((*StateProof)(nil), "corepb.StateProof")
 }
 
 func init() { proto.RegisterFile("block.proto", fileDescriptorBlock) }
 
 var fileDescriptorBlock = []byte{
-	// 698 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x54, 0x6d, 0x8b, 0xd3, 0x40,
-	0x10, 0x26, 0x6d, 0xfa, 0x36, 0x69, 0x8f, 0x63, 0x3d, 0x24, 0x56, 0xe5, 0x4a, 0x44, 0x28, 0x8a,
-	0x2d, 0x9c, 0xc2, 0xf9, 0xf5, 0xf4, 0x3e, 0x9c, 0x22, 0x72, 0x04, 0xbf, 0x08, 0x42, 0x99, 0x6c,
-	0xd6, 0x24, 0x98, 0xee, 0x86, 0xec, 0xf6, 0xbc, 0xfb, 0x19, 0xfe, 0x0f, 0xbf, 0xf8, 0x93, 0xfc,
-	0x27, 0xb2, 0xb3, 0xe9, 0xdb, 0x79, 0x20, 0x7e, 0xea, 0x3e, 0xf3, 0x64, 0xa6, 0xf3, 0x3c, 0x33,
-	0xbb, 0x10, 0x24, 0xa5, 0xe2, 0xdf, 0x66, 0x55, 0xad, 0x8c, 0x62, 0x5d, 0xae, 0x6a, 0x51, 0x25,
-	0xe3, 0xd3, 0xac, 0x30, 0xf9, 0x2a, 0x99, 0x71, 0xb5, 0x9c, 0x4b, 0x91, 0xac, 0x4a, 0xd4, 0x85,
-	0x9a, 0x67, 0xea, 0x45, 0x03, 0xe6, 0x5c, 0x2d, 0x97, 0x4a, 0xce, 0x53, 0xcc, 0xe6, 0x55, 0x62,
-	0x7f, 0x5c, 0x81, 0xf1, 0xeb, 0x7f, 0x27, 0x4a, 0x2d, 0xa4, 0x5e, 0x69, 0x9b, 0xa7, 0x0d, 0x1a,
-	0xe1, 0x32, 0xa3, 0x1f, 0x1e, 0xf4, 0xce, 0x38, 0x57, 0x2b, 0x69, 0x58, 0x08, 0x3d, 0x4c, 0xd3,
-	0x5a, 0x68, 0x1d, 0x7a, 0x13, 0x6f, 0x3a, 0x8c, 0xd7, 0xd0, 0x32, 0x09, 0x96, 0x28, 0xb9, 0x08,
-	0x5b, 0x8e, 0x69, 0x20, 0x3b, 0x82, 0x8e, 0x54, 0x36, 0xde, 0x9e, 0x78, 0x53, 0x3f, 0x76, 0x80,
-	0x3d, 0x84, 0xc1, 0x15, 0xd6, 0x7a, 0x91, 0xa3, 0xce, 0x43, 0x9f, 0x32, 0xfa, 0x36, 0x70, 0x81,
-	0x3a, 0x67, 0xc7, 0x10, 0x24, 0x45, 0x6d, 0xf2, 0x45, 0x55, 0x22, 0x17, 0x61, 0x87, 0x68, 0xa0,
-	0xd0, 0xa5, 0x8d, 0x44, 0xaf, 0xc0, 0x3f, 0x47, 0x83, 0x8c, 0x81, 0x6f, 0x6e, 0x2a, 0x41, 0xcd,
-	0x0c, 0x62, 0x3a, 0xdb, 0x4e, 0x2a, 0xbc, 0x29, 0x15, 0xa6, 0xeb, 0x4e, 0x1a, 0x18, 0xfd, 0x6c,
-	0x41, 0xf0, 0xa9, 0x46, 0xa9, 0x91, 0x9b, 0x42, 0x49, 0x9b, 0x4d, 0x7f, 0xef, 0xa4, 0xd0, 0xd9,
-	0xc6, 0xbe, 0xd6, 0x6a, 0xd9, 0xa4, 0xd2, 0x99, 0x1d, 0x40, 0xcb, 0x28, 0x6a, 0x7f, 0x18, 0xb7,
-	0x8c, 0xb2, 0x8a, 0xae, 0xb0, 0x5c, 0x89, 0xa6, 0x6f, 0x07, 0xb6, 0x3a, 0x3b, 0xbb, 0x3a, 0x1f,
-	0xc1, 0xc0, 0x14, 0x4b, 0xa1, 0x0d, 0x2e, 0xab, 0xb0, 0x3b, 0xf1, 0xa6, 0xed, 0x78, 0x1b, 0x60,
-	0x13, 0xf0, 0x53, 0x34, 0x18, 0xf6, 0x26, 0xde, 0x34, 0x38, 0x19, 0xce, 0xdc, 0x94, 0x67, 0x56,
-	0x5b, 0x4c, 0x0c, 0x7b, 0x00, 0x7d, 0x9e, 0x63, 0x21, 0x17, 0x45, 0x1a, 0xf6, 0x27, 0xde, 0x74,
-	0x14, 0xf7, 0x08, 0xbf, 0x4b, 0xad, 0x85, 0x19, 0xea, 0x45, 0x55, 0x17, 0x5c, 0x84, 0x03, 0x67,
-	0x61, 0x86, 0xfa, 0xd2, 0xe2, 0x35, 0x59, 0x16, 0xcb, 0xc2, 0x84, 0xb0, 0x21, 0x3f, 0x58, 0xcc,
-	0x0e, 0xa1, 0x8d, 0x65, 0x16, 0x06, 0x54, 0xcf, 0x1e, 0xad, 0x6c, 0x5d, 0x64, 0x32, 0x1c, 0x3a,
-	0xd9, 0xf6, 0x1c, 0xfd, 0x6e, 0x41, 0xf0, 0xc6, 0xee, 0xe0, 0x85, 0xc0, 0x54, 0xd4, 0x77, 0xda,
-	0x75, 0x0c, 0x41, 0x85, 0xb5, 0x90, 0xc6, 0x0d, 0xd2, 0xb9, 0x06, 0x2e, 0x44, 0xa3, 0x1c, 0x43,
-	0x9f, 0xab, 0x42, 0x26, 0xa8, 0xd7, 0x76, 0x6d, 0xf0, 0xbe, 0x37, 0x9d, 0xdb, 0xde, 0xec, 0x2a,
-	0xef, 0xee, 0x2b, 0x6f, 0xfa, 0xef, 0xfd, 0xdd, 0x7f, 0x7f, 0xdb, 0x3f, 0x7b, 0x0c, 0x40, 0x7b,
-	0xbc, 0xa8, 0x95, 0x32, 0x8d, 0x41, 0x03, 0x8a, 0xc4, 0x4a, 0x19, 0x5b, 0xdf, 0x5c, 0x6b, 0x47,
-	0x3a, 0x83, 0x7a, 0xe6, 0x5a, 0x13, 0x75, 0x0c, 0x81, 0xb8, 0x12, 0xd2, 0x34, 0x6c, 0xe0, 0x54,
-	0xb9, 0x10, 0x7d, 0x70, 0x06, 0x07, 0x9b, 0xfb, 0xe2, 0xbe, 0x19, 0xd2, 0x04, 0xc7, 0xb3, 0x4d,
-	0xb8, 0x4a, 0x66, 0x6f, 0xd7, 0x67, 0x9b, 0x13, 0x8f, 0xf8, 0x2e, 0x7c, 0xef, 0xf7, 0xdb, 0x87,
-	0x7e, 0xf4, 0xcb, 0x83, 0x0e, 0x79, 0xcc, 0x9e, 0x43, 0x37, 0x27, 0x9f, 0xc9, 0xdf, 0xe0, 0xe4,
-	0xde, 0x7a, 0x19, 0x76, 0x46, 0x10, 0x37, 0x9f, 0xb0, 0x53, 0x18, 0x9a, 0xed, 0x22, 0xeb, 0xb0,
-	0x35, 0x69, 0xef, 0xa6, 0xec, 0x2c, 0x79, 0xbc, 0xf7, 0x21, 0x7b, 0x06, 0x90, 0x8a, 0x4a, 0xc8,
-	0x54, 0x48, 0x7e, 0x43, 0x2b, 0x1d, 0x9c, 0xc0, 0x2c, 0xc5, 0x8c, 0xb6, 0x2e, 0x8b, 0x77, 0x58,
-	0x76, 0xdf, 0x76, 0x54, 0x64, 0xb9, 0xa1, 0xc1, 0xf9, 0x71, 0x83, 0xa2, 0x2f, 0x30, 0xf8, 0x28,
-	0x0c, 0xb5, 0xa5, 0x37, 0xf7, 0xa5, 0xb9, 0x81, 0x74, 0x5f, 0x8e, 0xa0, 0x93, 0xa0, 0xe1, 0x6e,
-	0x1d, 0xfc, 0xd8, 0x01, 0xf6, 0x14, 0xba, 0xf4, 0xa2, 0xe9, 0xb0, 0x4d, 0xdd, 0x8e, 0xf6, 0x04,
-	0xc6, 0x0d, 0x19, 0x7d, 0x86, 0xfe, 0xba, 0xfa, 0x7f, 0x14, 0x7f, 0x02, 0x1d, 0xca, 0x6f, 0x24,
-	0xdd, 0xaa, 0xed, 0xb8, 0xe8, 0x14, 0x46, 0xe7, 0xea, 0xbb, 0xb4, 0x6f, 0xc1, 0xa6, 0xfe, 0x5d,
-	0x0f, 0x00, 0x6d, 0x52, 0x6b, 0xbb, 0x49, 0x49, 0x97, 0x5e, 0xc2, 0x97, 0x7f, 0x02, 0x00, 0x00,
-	0xff, 0xff, 0xa7, 0xc4, 0x6c, 0xca, 0x93, 0x05, 0x00, 0x00,
+	// 1047 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x56, 0x4d, 0x6f, 0xe4, 0x44,
+	0x13, 0x96, 0xe7, 0x7b, 0xca, 0x33, 0xc9, 0xbc, 0xfd, 0xa2, 0xc8, 0x1b, 0x40, 0x19, 0x1c, 0x21,
+	0x06, 0xd0, 0x4e, 0xa4, 0x80, 0x14, 0xae, 0x59, 0x56, 0x4a, 0x40, 0x2b, 0x08, 0xde, 0xe5, 0x80,
+	0x84, 0x64, 0xb5, 0xed, 0x8e, 0xc7, 0xca, 0x8c, 0xdb, 0xea, 0x6e, 0x87, 0xcc, 0x8d, 0xbf, 0xc0,
+	0x81, 0x1f, 0xc2, 0x81, 0xbf, 0xc5, 0x9d, 0x1b, 0xaa, 0xea, 0xf6, 0x7c, 0x64, 0x83, 0x10, 0x9c,
+	0x52, 0xf5, 0x54, 0x77, 0x4d, 0xd7, 0x53, 0x4f, 0x95, 0x03, 0x7e, 0xb2, 0x94, 0xe9, 0xdd, 0xbc,
+	0x52, 0xd2, 0x48, 0xd6, 0x4b, 0xa5, 0x12, 0x55, 0x72, 0x7c, 0x91, 0x17, 0x66, 0x51, 0x27, 0xf3,
+	0x54, 0xae, 0xce, 0x4a, 0x91, 0xd4, 0x4b, 0xae, 0x0b, 0x79, 0x96, 0xcb, 0xe7, 0xce, 0x39, 0x4b,
+	0xe5, 0x6a, 0x25, 0xcb, 0xb3, 0x8c, 0xe7, 0x67, 0x55, 0x82, 0x7f, 0x6c, 0x82, 0xe3, 0x2f, 0xfe,
+	0xf9, 0x62, 0xa9, 0x45, 0xa9, 0x6b, 0x8d, 0xf7, 0xb4, 0xe1, 0x46, 0xd8, 0x9b, 0xe1, 0x2f, 0x1e,
+	0xf4, 0x2f, 0xd3, 0x54, 0xd6, 0xa5, 0x61, 0x01, 0xf4, 0x79, 0x96, 0x29, 0xa1, 0x75, 0xe0, 0x4d,
+	0xbd, 0xd9, 0x28, 0x6a, 0x5c, 0x8c, 0x24, 0x7c, 0xc9, 0xcb, 0x54, 0x04, 0x2d, 0x1b, 0x71, 0x2e,
+	0x7b, 0x07, 0xba, 0xa5, 0x44, 0xbc, 0x3d, 0xf5, 0x66, 0x9d, 0xc8, 0x3a, 0xec, 0x5d, 0x18, 0xde,
+	0x73, 0xa5, 0xe3, 0x05, 0xd7, 0x8b, 0xa0, 0x43, 0x37, 0x06, 0x08, 0x5c, 0x73, 0xbd, 0x60, 0x27,
+	0xe0, 0x27, 0x85, 0x32, 0x8b, 0xb8, 0x5a, 0xf2, 0x54, 0x04, 0x5d, 0x0a, 0x03, 0x41, 0x37, 0x88,
+	0x84, 0x9f, 0x43, 0xe7, 0x25, 0x37, 0x9c, 0x31, 0xe8, 0x98, 0x75, 0x25, 0xe8, 0x31, 0xc3, 0x88,
+	0x6c, 0x7c, 0x49, 0xc5, 0xd7, 0x4b, 0xc9, 0xb3, 0xe6, 0x25, 0xce, 0x0d, 0x7f, 0x6e, 0x83, 0xff,
+	0x46, 0xf1, 0x52, 0xf3, 0xd4, 0x14, 0xb2, 0xc4, 0xdb, 0xf4, 0xf3, 0xb6, 0x14, 0xb2, 0x11, 0xbb,
+	0x55, 0x72, 0xe5, 0xae, 0x92, 0xcd, 0x0e, 0xa0, 0x65, 0x24, 0x3d, 0x7f, 0x14, 0xb5, 0x8c, 0xc4,
+	0x8a, 0xee, 0xf9, 0xb2, 0x16, 0xee, 0xdd, 0xd6, 0xd9, 0xd6, 0xd9, 0xdd, 0xad, 0xf3, 0x3d, 0x18,
+	0x9a, 0x62, 0x25, 0xb4, 0xe1, 0xab, 0x2a, 0xe8, 0x4d, 0xbd, 0x59, 0x3b, 0xda, 0x02, 0x6c, 0x0a,
+	0x9d, 0x8c, 0x1b, 0x1e, 0xf4, 0xa7, 0xde, 0xcc, 0x3f, 0x1f, 0xcd, 0x6d, 0x97, 0xe7, 0x58, 0x5b,
+	0x44, 0x11, 0xf6, 0x0c, 0x06, 0xe9, 0x82, 0x17, 0x65, 0x5c, 0x64, 0xc1, 0x60, 0xea, 0xcd, 0xc6,
+	0x51, 0x9f, 0xfc, 0xaf, 0x32, 0xa4, 0x30, 0xe7, 0x3a, 0xae, 0x54, 0x91, 0x8a, 0x60, 0x68, 0x29,
+	0xcc, 0xb9, 0xbe, 0x41, 0xbf, 0x09, 0x2e, 0x8b, 0x55, 0x61, 0x02, 0xd8, 0x04, 0x5f, 0xa1, 0xcf,
+	0x26, 0xd0, 0xe6, 0xcb, 0x3c, 0xf0, 0x29, 0x1f, 0x9a, 0x58, 0xb6, 0x2e, 0xf2, 0x32, 0x18, 0xd9,
+	0xb2, 0xd1, 0x66, 0xef, 0x03, 0x88, 0x87, 0xaa, 0x50, 0x22, 0x8b, 0xb9, 0x09, 0xc6, 0xf6, 0xed,
+	0x0e, 0xb9, 0x34, 0x58, 0x6f, 0xc5, 0xd7, 0x42, 0x05, 0x07, 0x96, 0x05, 0x72, 0xf0, 0x12, 0x19,
+	0x31, 0xa5, 0x3b, 0xa4, 0xd0, 0x90, 0x90, 0xd7, 0x45, 0x5e, 0x86, 0x7f, 0xb6, 0xc1, 0x7f, 0x81,
+	0xba, 0xbe, 0x16, 0x3c, 0x13, 0xea, 0xc9, 0x16, 0x9c, 0x80, 0x5f, 0x71, 0x25, 0x4a, 0x63, 0xc5,
+	0x61, 0x3b, 0x01, 0x16, 0x22, 0x79, 0x1c, 0xc3, 0x20, 0x95, 0x45, 0x99, 0x70, 0xdd, 0xb4, 0x60,
+	0xe3, 0xef, 0xf3, 0xdd, 0x7d, 0xcc, 0xf7, 0x2e, 0x9b, 0xbd, 0x7d, 0x36, 0x1d, 0x27, 0xfd, 0xb7,
+	0x39, 0x19, 0xec, 0x73, 0x42, 0xb3, 0x11, 0x2b, 0x29, 0x8d, 0x23, 0x7d, 0x48, 0x48, 0x24, 0xa5,
+	0xc1, 0xfc, 0xe6, 0x41, 0xdb, 0xa0, 0x25, 0xbd, 0x6f, 0x1e, 0x34, 0x85, 0x4e, 0xc0, 0x17, 0xf7,
+	0xa2, 0x34, 0x2e, 0xea, 0xdb, 0xaa, 0x2c, 0x44, 0x07, 0x2e, 0xe1, 0x60, 0x33, 0x83, 0xf6, 0xcc,
+	0x88, 0x54, 0x71, 0x3c, 0xdf, 0xc0, 0x55, 0x32, 0xff, 0xb2, 0xb1, 0xf1, 0x4e, 0x34, 0x4e, 0x77,
+	0x5d, 0x76, 0x0a, 0x63, 0x25, 0x52, 0x51, 0x54, 0xcd, 0xaf, 0x8c, 0xe9, 0x57, 0x46, 0x0d, 0xd8,
+	0x1c, 0xca, 0xc4, 0x52, 0xe4, 0x9b, 0x2a, 0x6c, 0xff, 0x46, 0x0d, 0x48, 0x87, 0xf6, 0xe4, 0x73,
+	0xf8, 0x48, 0x3e, 0xcf, 0x00, 0xed, 0xb8, 0xd6, 0x22, 0x0b, 0x26, 0xb6, 0xca, 0x9c, 0xeb, 0xef,
+	0xb5, 0xc8, 0x50, 0x14, 0xc9, 0x52, 0xca, 0x55, 0xf0, 0x3f, 0x2b, 0x0a, 0x72, 0xbe, 0xee, 0x0c,
+	0xda, 0x93, 0x4e, 0xf8, 0x9b, 0x07, 0x5d, 0xea, 0x3d, 0xfb, 0x14, 0x7a, 0x0b, 0xea, 0x3f, 0xf5,
+	0xdd, 0x3f, 0xff, 0x7f, 0x23, 0xfc, 0x1d, 0x69, 0x44, 0xee, 0x08, 0xbb, 0x80, 0x91, 0xd9, 0x0e,
+	0xad, 0x0e, 0x5a, 0xd3, 0xf6, 0xee, 0x95, 0x9d, 0x81, 0x8e, 0xf6, 0x0e, 0xb2, 0x4f, 0x00, 0x32,
+	0x51, 0x89, 0x32, 0x13, 0x65, 0xba, 0xa6, 0xf1, 0xf5, 0xcf, 0x61, 0x9e, 0xf1, 0x9c, 0x26, 0x2c,
+	0x8f, 0x76, 0xa2, 0xec, 0x08, 0x5f, 0x54, 0xe4, 0x0b, 0x43, 0x82, 0xea, 0x44, 0xce, 0x0b, 0x7f,
+	0x84, 0xe1, 0x37, 0xc2, 0xd0, 0xb3, 0xf4, 0x66, 0x37, 0xb8, 0x6d, 0x83, 0x36, 0x15, 0xcc, 0x4d,
+	0x6a, 0x65, 0xda, 0x89, 0xac, 0xc3, 0x3e, 0x84, 0x1e, 0x6d, 0x6f, 0x1d, 0xb4, 0xe9, 0xb5, 0xe3,
+	0xbd, 0x02, 0x23, 0x17, 0x0c, 0x7f, 0x80, 0x41, 0x93, 0xfd, 0x5f, 0x24, 0x3f, 0x25, 0x8e, 0xd3,
+	0x3b, 0x57, 0xd2, 0xa3, 0xdc, 0x36, 0x16, 0x5e, 0xc0, 0xf8, 0xa5, 0xfc, 0xa9, 0xc4, 0xbd, 0xb7,
+	0xc9, 0xff, 0xd4, 0xb2, 0x23, 0x85, 0xb7, 0xb6, 0x0a, 0x0f, 0xff, 0xf0, 0x80, 0xed, 0x72, 0x6a,
+	0xa5, 0xf3, 0xe4, 0xf5, 0x23, 0xe8, 0xa1, 0xf4, 0x6b, 0x4d, 0x09, 0xc6, 0x91, 0xf3, 0xf6, 0xf4,
+	0xd1, 0xde, 0xd7, 0xc7, 0xc7, 0x30, 0x49, 0x65, 0x69, 0x14, 0x4f, 0x4d, 0xdc, 0x7c, 0x49, 0xec,
+	0x08, 0x1f, 0x36, 0xf8, 0xa5, 0x85, 0xd9, 0x07, 0x30, 0xa2, 0x52, 0x62, 0xd7, 0x18, 0xbb, 0x56,
+	0xed, 0x57, 0xf1, 0x9a, 0x20, 0xe4, 0x47, 0x28, 0x25, 0x15, 0xcd, 0xf2, 0x30, 0xb2, 0x0e, 0x7b,
+	0x6e, 0xb5, 0x8b, 0xc9, 0x84, 0xdb, 0xac, 0x93, 0x86, 0xa3, 0x2b, 0xae, 0xdf, 0x20, 0x4e, 0x6a,
+	0x26, 0x2b, 0xfc, 0xdd, 0x83, 0x41, 0x03, 0xe3, 0xd3, 0x71, 0x8d, 0xc4, 0x39, 0xdf, 0x7c, 0xe1,
+	0xd0, 0xbf, 0xe2, 0x9a, 0xcd, 0x60, 0xe2, 0x3e, 0x24, 0xf1, 0xe6, 0x88, 0x25, 0xee, 0xc0, 0xe1,
+	0x2f, 0xdc, 0xc9, 0x53, 0x18, 0x8b, 0x07, 0x91, 0xd6, 0xc8, 0x1f, 0x1d, 0xb3, 0x24, 0x8c, 0x36,
+	0x20, 0x1e, 0x3a, 0x82, 0x9e, 0x12, 0xb7, 0x75, 0x99, 0xb9, 0xfa, 0x9d, 0xc7, 0x3e, 0x82, 0x89,
+	0xac, 0x4d, 0x2c, 0x6f, 0xe3, 0xed, 0x00, 0x62, 0xe9, 0x83, 0x68, 0x2c, 0x6b, 0xf3, 0xed, 0xed,
+	0x95, 0x9b, 0xc2, 0xf0, 0x3b, 0x80, 0x57, 0xc8, 0xc2, 0x7f, 0x18, 0xa9, 0xbf, 0x53, 0xfb, 0xaf,
+	0x1e, 0xc0, 0x6b, 0x5c, 0x66, 0x37, 0x4a, 0xca, 0x5b, 0x5c, 0x76, 0xae, 0x03, 0xdb, 0xce, 0x0f,
+	0x2d, 0xff, 0xae, 0xfd, 0x2e, 0x4b, 0x6b, 0x37, 0x0b, 0x4a, 0x85, 0xf6, 0x8a, 0xad, 0x9a, 0x6c,
+	0xdc, 0xae, 0x77, 0x62, 0xed, 0x4a, 0x45, 0x73, 0xfb, 0x11, 0xed, 0xbe, 0xf5, 0x11, 0xcd, 0x84,
+	0x0e, 0x7a, 0xd3, 0x36, 0xa2, 0xe4, 0x24, 0x3d, 0xfa, 0x4f, 0xe4, 0xb3, 0xbf, 0x06, 0x00, 0x97,
+	0xd9, 0x3e, 0x1c, 0x13, 0x09, 0x00, 0x00,
 }
@@ -13,6 +13,7 @@ It has these top-level messages:
 	GenesisConsensus
 	GenesisConsensusDpos
 	GenesisTokenDistribution
+	GenesisChainConfig
 */
 package corepb
 
@@ -39,6 +40,8 @@ type Genesis struct {
 	// genesis token distribution address
 	// map<string, string> token_distribution = 3;
 	TokenDistribution []*GenesisTokenDistribution `protobuf:"bytes,3,rep,name=token_distribution,json=tokenDistribution" json:"token_distribution,omitempty"`
+	// genesis chain config, the defaults are used if omitted.
+	ChainConfig *GenesisChainConfig `protobuf:"bytes,4,opt,name=chain_config,json=chainConfig" json:"chain_config,omitempty"`
 }
 
 func (m *Genesis) Reset()                    { *m = Genesis{} }
@@ -67,6 +70,13 @@ func (m *Genesis) GetTokenDistribution() []*GenesisTokenDistribution {
 	return nil
 }
 
+func (m *Genesis) GetChainConfig() *GenesisChainConfig {
+	if m != nil {
+		return m.ChainConfig
+	}
+	return nil
+}
+
 type GenesisMeta struct {
 	// ChainID.
 	ChainId uint32 `protobuf:"varint,1,opt,name=chain_id,json=chainId,proto3" json:"chain_id,omitempty"`
@@ -142,33 +152,108 @@ func (m *GenesisTokenDistribution) GetValue() string {
 	return ""
 }
 
+type GenesisChainConfig struct {
+	// max gasPrice of transaction, uint128 string.
+	TransactionMaxGasPrice string `protobuf:"bytes,1,opt,name=transaction_max_gas_price,json=transactionMaxGasPrice,proto3" json:"transaction_max_gas_price,omitempty"`
+	// base gas of transaction, uint128 string.
+	MinGasCountPerTransaction string `protobuf:"bytes,2,opt,name=min_gas_count_per_transaction,json=minGasCountPerTransaction,proto3" json:"min_gas_count_per_transaction,omitempty"`
+	// gas per byte of transaction payload, uint128 string.
+	GasCountPerByte string `protobuf:"bytes,3,opt,name=gas_count_per_byte,json=gasCountPerByte,proto3" json:"gas_count_per_byte,omitempty"`
+	// max length of transaction payload.
+	MaxDataPayloadLength uint32 `protobuf:"varint,4,opt,name=max_data_payload_length,json=maxDataPayloadLength,proto3" json:"max_data_payload_length,omitempty"`
+	// admin addresses allowed to freeze & unfreeze accounts.
+	FreezeAdmins []string `protobuf:"bytes,5,rep,name=freeze_admins,json=freezeAdmins" json:"freeze_admins,omitempty"`
+	// max sum of gas used by txs in a block, uint128 string.
+	BlockGasLimit string `protobuf:"bytes,6,opt,name=block_gas_limit,json=blockGasLimit,proto3" json:"block_gas_limit,omitempty"`
+}
+
+func (m *GenesisChainConfig) Reset()                    { *m = GenesisChainConfig{} }
+func (m *GenesisChainConfig) String() string            { return proto.CompactTextString(m) }
+func (*GenesisChainConfig) ProtoMessage()               {}
+func (*GenesisChainConfig) Descriptor() ([]byte, []int) { return fileDescriptorGenesis, []int{5} }
+
+func (m *GenesisChainConfig) GetTransactionMaxGasPrice() string {
+	if m != nil {
+		return m.TransactionMaxGasPrice
+	}
+	return ""
+}
+
+func (m *GenesisChainConfig) GetMinGasCountPerTransaction() string {
+	if m != nil {
+		return m.MinGasCountPerTransaction
+	}
+	return ""
+}
+
+func (m *GenesisChainConfig) GetGasCountPerByte() string {
+	if m != nil {
+		return m.GasCountPerByte
+	}
+	return ""
+}
+
+func (m *GenesisChainConfig) GetMaxDataPayloadLength() uint32 {
+	if m != nil {
+		return m.MaxDataPayloadLength
+	}
+	return 0
+}
+
+func (m *GenesisChainConfig) GetFreezeAdmins() []string {
+	if m != nil {
+		return m.FreezeAdmins
+	}
+	return nil
+}
+
+func (m *GenesisChainConfig) GetBlockGasLimit() string {
+	if m != nil {
+		return m.BlockGasLimit
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*Genesis)(nil), "corepb.Genesis")
 	proto.RegisterType((*GenesisMeta)(nil), "corepb.GenesisMeta")
 	proto.RegisterType((*GenesisConsensus)(nil), "corepb.GenesisConsensus")
 	proto.RegisterType((*GenesisConsensusDpos)(nil), "corepb.GenesisConsensusDpos")
 	proto.RegisterType((*GenesisTokenDistribution)(nil), "corepb.GenesisTokenDistribution")
+	proto.RegisterType((*GenesisChainConfig)(nil), "corepb.GenesisChainConfig")
 }
 
 func init() { proto.RegisterFile("genesis.proto", fileDescriptorGenesis) }
 
 var fileDescriptorGenesis = []byte{
-	// 264 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x91, 0x51, 0x4b, 0xc3, 0x30,
-	0x14, 0x85, 0xa9, 0x9d, 0xab, 0xbd, 0x65, 0xa0, 0x71, 0x0f, 0x11, 0x7c, 0x28, 0x79, 0xb1, 0x4f,
-	0x65, 0x4c, 0xf0, 0x0f, 0x58, 0x10, 0x05, 0x11, 0x82, 0xef, 0x23, 0x6d, 0x2e, 0x1a, 0xd4, 0xa4,
-	0xf4, 0xa6, 0xc2, 0x7e, 0x9b, 0x7f, 0x4e, 0x96, 0xae, 0x38, 0x8a, 0x7b, 0x3c, 0x39, 0x5f, 0x2e,
-	0xe7, 0xdc, 0x0b, 0x8b, 0x37, 0xb4, 0x48, 0x86, 0xca, 0xb6, 0x73, 0xde, 0xb1, 0x79, 0xe3, 0x3a,
-	0x6c, 0x6b, 0xf1, 0x13, 0x41, 0xf2, 0x30, 0x38, 0xec, 0x06, 0x66, 0x5f, 0xe8, 0x15, 0x8f, 0xf2,
-	0xa8, 0xc8, 0xd6, 0x97, 0xe5, 0x80, 0x94, 0x7b, 0xfb, 0x19, 0xbd, 0x92, 0x01, 0x60, 0x77, 0x90,
-	0x36, 0xce, 0x12, 0x5a, 0xea, 0x89, 0x9f, 0x04, 0x9a, 0x4f, 0xe8, 0xfb, 0xd1, 0x97, 0x7f, 0x28,
-	0x7b, 0x01, 0xe6, 0xdd, 0x07, 0xda, 0x8d, 0x36, 0xe4, 0x3b, 0x53, 0xf7, 0xde, 0x38, 0xcb, 0xe3,
-	0x3c, 0x2e, 0xb2, 0x75, 0x3e, 0x19, 0xf0, 0xba, 0x03, 0xab, 0x03, 0x4e, 0x5e, 0xf8, 0xe9, 0x93,
-	0x28, 0x20, 0x3b, 0x48, 0xc7, 0xae, 0xe0, 0xac, 0x79, 0x57, 0xc6, 0x6e, 0x8c, 0x0e, 0x25, 0x16,
-	0x32, 0x09, 0xfa, 0x51, 0x8b, 0x0a, 0xce, 0xa7, 0xc9, 0xd8, 0x0a, 0x66, 0xba, 0x75, 0xb4, 0xef,
-	0x7b, 0x7d, 0xac, 0x41, 0xd5, 0x3a, 0x92, 0x81, 0x14, 0x2b, 0x58, 0xfe, 0xe7, 0x32, 0x0e, 0x89,
-	0xde, 0x5a, 0x45, 0x7e, 0xcb, 0xa3, 0x3c, 0x2e, 0x52, 0x39, 0x4a, 0xf1, 0x04, 0xfc, 0x58, 0xa1,
-	0xdd, 0x2f, 0xa5, 0x75, 0x87, 0x34, 0x44, 0x48, 0xe5, 0x28, 0xd9, 0x12, 0x4e, 0xbf, 0xd5, 0x67,
-	0x8f, 0x61, 0xb9, 0xa9, 0x1c, 0x44, 0x3d, 0x0f, 0xa7, 0xbb, 0xfd, 0x0d, 0x00, 0x00, 0xff, 0xff,
-	0xbe, 0xf4, 0x0e, 0x3f, 0xcb, 0x01, 0x00, 0x00,
+	// 461 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x93, 0xd1, 0x6b, 0x13, 0x41,
+	0x10, 0xc6, 0x49, 0xd3, 0x26, 0x66, 0xd2, 0xa3, 0xba, 0x06, 0xdd, 0x88, 0x42, 0x38, 0x41, 0x03,
+	0x42, 0x28, 0x15, 0x05, 0x1f, 0x04, 0x35, 0x81, 0xa0, 0xb4, 0x18, 0x96, 0xbe, 0x2f, 0x73, 0xb7,
+	0xdb, 0xeb, 0xd2, 0xbb, 0xdd, 0x63, 0x77, 0x23, 0x39, 0xff, 0x3c, 0xff, 0x2c, 0x9f, 0xe4, 0xf6,
+	0x12, 0x12, 0x4f, 0xfb, 0x38, 0xf3, 0xfd, 0x66, 0xee, 0x9b, 0x6f, 0x39, 0x88, 0x32, 0xa9, 0xa5,
+	0x53, 0x6e, 0x56, 0x5a, 0xe3, 0x0d, 0xe9, 0xa5, 0xc6, 0xca, 0x32, 0x89, 0x7f, 0x77, 0xa0, 0xbf,
+	0x6c, 0x14, 0xf2, 0x1a, 0x8e, 0x0b, 0xe9, 0x91, 0x76, 0x26, 0x9d, 0xe9, 0xf0, 0xe2, 0xf1, 0xac,
+	0x41, 0x66, 0x5b, 0xf9, 0x4a, 0x7a, 0x64, 0x01, 0x20, 0xef, 0x61, 0x90, 0x1a, 0xed, 0xa4, 0x76,
+	0x6b, 0x47, 0x8f, 0x02, 0x4d, 0x5b, 0xf4, 0x7c, 0xa7, 0xb3, 0x3d, 0x4a, 0xbe, 0x03, 0xf1, 0xe6,
+	0x4e, 0x6a, 0x2e, 0x94, 0xf3, 0x56, 0x25, 0x6b, 0xaf, 0x8c, 0xa6, 0xdd, 0x49, 0x77, 0x3a, 0xbc,
+	0x98, 0xb4, 0x16, 0x5c, 0xd7, 0xe0, 0xe2, 0x80, 0x63, 0x8f, 0x7c, 0xbb, 0x45, 0x3e, 0xc2, 0x69,
+	0x7a, 0x8b, 0x4a, 0xf3, 0xd4, 0xe8, 0x1b, 0x95, 0xd1, 0xe3, 0xe0, 0xe5, 0x59, 0xdb, 0x4b, 0x8d,
+	0xcc, 0x03, 0xc1, 0x86, 0xe9, 0xbe, 0x88, 0xa7, 0x30, 0x3c, 0x38, 0x8e, 0x8c, 0xe1, 0x41, 0xb3,
+	0x4d, 0x89, 0x90, 0x41, 0xc4, 0xfa, 0xa1, 0xfe, 0x2a, 0xe2, 0x05, 0x3c, 0x6c, 0x1f, 0x46, 0xce,
+	0xe1, 0x58, 0x94, 0xc6, 0x6d, 0xe3, 0x7a, 0x7e, 0x5f, 0x00, 0x8b, 0xd2, 0x38, 0x16, 0xc8, 0xf8,
+	0x1c, 0x46, 0xff, 0x53, 0x09, 0x85, 0xbe, 0xa8, 0x34, 0x3a, 0x5f, 0xd1, 0xce, 0xa4, 0x3b, 0x1d,
+	0xb0, 0x5d, 0x19, 0x7f, 0x03, 0x7a, 0x5f, 0x1e, 0xf5, 0x14, 0x0a, 0x61, 0xa5, 0x6b, 0x2c, 0x0c,
+	0xd8, 0xae, 0x24, 0x23, 0x38, 0xf9, 0x81, 0xf9, 0x5a, 0x86, 0xb7, 0x19, 0xb0, 0xa6, 0x88, 0x7f,
+	0x1d, 0x01, 0xf9, 0x37, 0x11, 0xf2, 0x01, 0xc6, 0xde, 0xa2, 0x76, 0x98, 0xd6, 0x5b, 0x79, 0x81,
+	0x1b, 0x9e, 0xa1, 0xe3, 0xa5, 0x55, 0xa9, 0xdc, 0x2e, 0x7e, 0x72, 0x00, 0x5c, 0xe1, 0x66, 0x89,
+	0x6e, 0x55, 0xab, 0xe4, 0x13, 0xbc, 0x28, 0x94, 0x0e, 0x78, 0x6a, 0xd6, 0xda, 0xf3, 0x52, 0x5a,
+	0x7e, 0xc0, 0x6e, 0xbf, 0x3f, 0x2e, 0x94, 0x5e, 0xa2, 0x9b, 0xd7, 0xc8, 0x4a, 0xda, 0xeb, 0x3d,
+	0x40, 0xde, 0x00, 0xf9, 0x7b, 0x3a, 0xa9, 0xbc, 0xa4, 0xdd, 0x30, 0x76, 0x96, 0xed, 0x67, 0xbe,
+	0x54, 0x5e, 0x92, 0x77, 0xf0, 0xb4, 0x76, 0x27, 0xd0, 0x23, 0x2f, 0xb1, 0xca, 0x0d, 0x0a, 0x9e,
+	0x4b, 0x9d, 0xf9, 0xdb, 0xf0, 0xf0, 0x11, 0x1b, 0x15, 0xb8, 0x59, 0xa0, 0xc7, 0x55, 0x23, 0x5e,
+	0x06, 0x8d, 0xbc, 0x84, 0xe8, 0xc6, 0x4a, 0xf9, 0x53, 0x72, 0x14, 0x85, 0xd2, 0x8e, 0x9e, 0x84,
+	0x8c, 0x4f, 0x9b, 0xe6, 0xe7, 0xd0, 0x23, 0xaf, 0xe0, 0x2c, 0xc9, 0x4d, 0x7a, 0x17, 0x8e, 0xc9,
+	0x55, 0xa1, 0x3c, 0xed, 0x05, 0x17, 0x51, 0x68, 0x2f, 0xd1, 0x5d, 0xd6, 0xcd, 0xa4, 0x17, 0x7e,
+	0x9f, 0xb7, 0x7f, 0x06, 0x00, 0x05, 0x9a, 0x17, 0x4f, 0x4f, 0x03, 0x00, 0x00,
 }
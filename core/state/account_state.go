@@ -182,6 +182,13 @@ func (acc *account) Iterator(prefix []byte) (Iterator, error) {
 	return acc.variables.Iterator(prefix)
 }
 
+// emptyIterator is the iterator of an empty storage.
+type emptyIterator struct{}
+
+func (it emptyIterator) Next() (bool, error) { return false, nil }
+func (it emptyIterator) Key() []byte         { return nil }
+func (it emptyIterator) Value() []byte       { return nil }
+
 func (acc *account) String() string {
 	return fmt.Sprintf("Account %p {Address: %v, Balance:%v; Nonce:%v; VarsHash:%v; BirthPlace:%v}",
 		acc,
@@ -214,6 +221,33 @@ func NewAccountState(root byteutils.Hash, storage storage.Storage) (AccountState
 	}, nil
 }
 
+// WalkAccountStateNodes visit the trie nodes of account state at root, including the variables tries of accounts.
+// The children of a node are skipped if visit returns false.
+func WalkAccountStateNodes(root byteutils.Hash, storage storage.Storage, visit func(hash byteutils.Hash) (bool, error)) error {
+	stateTrie, err := trie.NewTrie(root, storage, false)
+	if err != nil {
+		return err
+	}
+	return stateTrie.WalkNodes(func(hash []byte, val []byte) (bool, error) {
+		goOn, err := visit(hash)
+		if err != nil || !goOn || val == nil {
+			return goOn, err
+		}
+
+		pbAcc := &corepb.Account{}
+		if err := proto.Unmarshal(val, pbAcc); err != nil {
+			return false, err
+		}
+		varTrie, err := trie.NewTrie(pbAcc.VarsHash, storage, false)
+		if err != nil {
+			return false, err
+		}
+		return true, varTrie.WalkNodes(func(hash []byte, val []byte) (bool, error) {
+			return visit(hash)
+		})
+	})
+}
+
 func (as *accountState) recordDirtyAccount(addr byteutils.Hash, acc Account) {
 	as.dirtyAccount[addr.Hex()] = acc
 }
@@ -298,6 +332,17 @@ func (as *accountState) GetOrCreateUserAccount(addr byteutils.Hash) (Account, er
 	return acc, nil
 }
 
+// GenerateProof return the account bytes of addr and its merkle proof against RootHash,
+// the bytes is nil if the account doesn't exist.
+func (as *accountState) GenerateProof(addr byteutils.Hash) ([]byte, [][]byte, error) {
+	return as.stateTrie.ProvePath(addr)
+}
+
+// GetUserAccount according to the addr, return ErrAccountNotFound without creating it
+func (as *accountState) GetUserAccount(addr byteutils.Hash) (Account, error) {
+	return as.getAccount(addr)
+}
+
 // GetContractAccount from current AccountState
 func (as *accountState) GetContractAccount(addr byteutils.Hash) (Account, error) {
 	acc, err := as.getAccount(addr)
@@ -359,6 +404,53 @@ func (as *accountState) Replay(done AccountState) error {
 	return nil
 }
 
+// accountStateSnapshot is the state trie and the values of dirty accounts at a snapshot.
+type accountStateSnapshot struct {
+	stateTrie *trie.Snapshot
+	accounts  map[byteutils.HexHash]*accountSnapshot
+}
+
+type accountSnapshot struct {
+	acc        *account
+	balance    *util.Uint128
+	nonce      uint64
+	variables  *trie.Snapshot
+	birthPlace byteutils.Hash
+}
+
+func (as *accountState) snapshot() *accountStateSnapshot {
+	snapshot := &accountStateSnapshot{
+		stateTrie: as.stateTrie.Snapshot(),
+		accounts:  make(map[byteutils.HexHash]*accountSnapshot),
+	}
+	for addr, acc := range as.dirtyAccount {
+		acc := acc.(*account)
+		snapshot.accounts[addr] = &accountSnapshot{
+			acc:        acc,
+			balance:    acc.balance,
+			nonce:      acc.nonce,
+			variables:  acc.variables.Snapshot(),
+			birthPlace: acc.birthPlace,
+		}
+	}
+	return snapshot
+}
+
+// revertToSnapshot restore the dirty accounts in place, so the accounts got before
+// the snapshot are still valid. The accounts got after it should be got again.
+func (as *accountState) revertToSnapshot(snapshot *accountStateSnapshot) {
+	as.stateTrie.RevertToSnapshot(snapshot.stateTrie)
+	as.dirtyAccount = make(map[byteutils.HexHash]Account)
+	for addr, accSnapshot := range snapshot.accounts {
+		acc := accSnapshot.acc
+		acc.balance = accSnapshot.balance
+		acc.nonce = accSnapshot.nonce
+		acc.variables.RevertToSnapshot(accSnapshot.variables)
+		acc.birthPlace = accSnapshot.birthPlace
+		as.dirtyAccount[addr] = acc
+	}
+}
+
 // Clone an accountState
 func (as *accountState) Clone() (AccountState, error) {
 	stateTrie, err := as.stateTrie.Clone()
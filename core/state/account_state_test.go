@@ -79,3 +79,24 @@ func TestAccountState(t *testing.T) {
 	assert.Nil(t, err)
 	acc3.Put([]byte("var2"), []byte("value2"))
 }
+
+func TestAccountState_GetUserAccount(t *testing.T) {
+	stor, err := storage.NewMemoryStorage()
+	assert.Nil(t, err)
+	as, err := NewAccountState(nil, stor)
+	assert.Nil(t, err)
+
+	addr := []byte("accAddr")
+	_, err = as.GetUserAccount(addr)
+	assert.Equal(t, ErrAccountNotFound, err)
+	assert.Nil(t, as.Flush())
+	assert.Nil(t, as.RootHash())
+
+	acc, err := as.GetOrCreateUserAccount(addr)
+	assert.Nil(t, err)
+	acc.IncrNonce()
+	assert.Nil(t, as.Flush())
+	acc, err = as.GetUserAccount(addr)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), acc.Nonce())
+}
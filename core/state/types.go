@@ -39,11 +39,13 @@ var (
 	ErrCannotUpdateTxStateBeforePrepare    = errors.New("cannot update a tx state before prepare")
 	ErrCannotResetTxStateBeforePrepare     = errors.New("cannot reset a tx state before prepare")
 	ErrContractCheckFailed                 = errors.New("contract check failed")
+	ErrInvalidSnapshot                     = errors.New("cannot revert to an invalid snapshot")
 )
 
 // Iterator Variables in Account Storage
 type Iterator interface {
 	Next() (bool, error)
+	Key() []byte
 	Value() []byte
 }
 
@@ -82,7 +84,10 @@ type AccountState interface {
 	Clone() (AccountState, error)
 	Replay(AccountState) error
 
+	GenerateProof(byteutils.Hash) ([]byte, [][]byte, error)
+
 	GetOrCreateUserAccount(byteutils.Hash) (Account, error)
+	GetUserAccount(byteutils.Hash) (Account, error)
 	GetContractAccount(byteutils.Hash) (Account, error)
 	CreateContractAccount(byteutils.Hash, byteutils.Hash) (Account, error)
 }
@@ -121,12 +126,16 @@ type WorldState interface {
 
 	Prepare(interface{}) (TxWorldState, error)
 	Reset() error
+	Snapshot() int
+	RevertToSnapshot(int) error
 	Flush() error
 	Abort() error
 
 	LoadAccountsRoot(byteutils.Hash) error
 	LoadTxsRoot(byteutils.Hash) error
 	LoadEventsRoot(byteutils.Hash) error
+	LoadReceiptsRoot(byteutils.Hash) error
+	LoadDelegateRoot(byteutils.Hash) error
 	LoadConsensusRoot(*consensuspb.ConsensusRoot) error
 
 	NextConsensusState(int64) (ConsensusState, error)
@@ -134,21 +143,37 @@ type WorldState interface {
 
 	Clone() (WorldState, error)
 
+	GenerateAccountProof(addr byteutils.Hash) ([]byte, [][]byte, error)
+	GenerateTxProof(txHash byteutils.Hash) ([]byte, [][]byte, error)
+
 	AccountsRoot() byteutils.Hash
 	TxsRoot() byteutils.Hash
 	EventsRoot() byteutils.Hash
+	ReceiptsRoot() byteutils.Hash
+	DelegateRoot() byteutils.Hash
 	ConsensusRoot() *consensuspb.ConsensusRoot
 
 	Accounts() ([]Account, error)
 	GetOrCreateUserAccount(addr byteutils.Hash) (Account, error)
+	GetUserAccount(addr byteutils.Hash) (Account, error)
 	GetContractAccount(addr byteutils.Hash) (Account, error)
+	ContractStorageIterator(addr byteutils.Hash) (Iterator, error)
 	CreateContractAccount(owner byteutils.Hash, birthPlace byteutils.Hash) (Account, error)
 
 	GetTx(txHash byteutils.Hash) ([]byte, error)
 	PutTx(txHash byteutils.Hash, txBytes []byte) error
 
+	GetTxReceipt(txHash byteutils.Hash) ([]byte, error)
+	PutTxReceipt(txHash byteutils.Hash, receiptBytes []byte) error
+
+	GetDelegate(key []byte) ([]byte, error)
+	PutDelegate(key []byte, val []byte) error
+	DelDelegate(key []byte) error
+
 	RecordEvent(txHash byteutils.Hash, event *Event)
 	FetchEvents(byteutils.Hash) ([]*Event, error)
+	RecordExecutionResult(txHash byteutils.Hash, event *Event)
+	FetchExecutionResult(byteutils.Hash) (*Event, error)
 
 	Dynasty() ([]byteutils.Hash, error)
 	DynastyRoot() byteutils.Hash
@@ -162,22 +187,37 @@ type TxWorldState interface {
 	AccountsRoot() byteutils.Hash
 	TxsRoot() byteutils.Hash
 	EventsRoot() byteutils.Hash
+	ReceiptsRoot() byteutils.Hash
+	DelegateRoot() byteutils.Hash
 	ConsensusRoot() *consensuspb.ConsensusRoot
 
 	CheckAndUpdate() ([]interface{}, error)
 	Reset() error
+	Snapshot() int
+	RevertToSnapshot(int) error
 	Close() error
 
 	Accounts() ([]Account, error)
 	GetOrCreateUserAccount(addr byteutils.Hash) (Account, error)
+	GetUserAccount(addr byteutils.Hash) (Account, error)
 	GetContractAccount(addr byteutils.Hash) (Account, error)
+	ContractStorageIterator(addr byteutils.Hash) (Iterator, error)
 	CreateContractAccount(owner byteutils.Hash, birthPlace byteutils.Hash) (Account, error)
 
 	GetTx(txHash byteutils.Hash) ([]byte, error)
 	PutTx(txHash byteutils.Hash, txBytes []byte) error
 
+	GetTxReceipt(txHash byteutils.Hash) ([]byte, error)
+	PutTxReceipt(txHash byteutils.Hash, receiptBytes []byte) error
+
+	GetDelegate(key []byte) ([]byte, error)
+	PutDelegate(key []byte, val []byte) error
+	DelDelegate(key []byte) error
+
 	RecordEvent(txHash byteutils.Hash, event *Event)
 	FetchEvents(byteutils.Hash) ([]*Event, error)
+	RecordExecutionResult(txHash byteutils.Hash, event *Event)
+	FetchExecutionResult(byteutils.Hash) (*Event, error)
 
 	Dynasty() ([]byteutils.Hash, error)
 	DynastyRoot() byteutils.Hash
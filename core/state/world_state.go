@@ -52,6 +52,8 @@ type states struct {
 	accState       AccountState
 	txsState       *trie.Trie
 	eventsState    *trie.Trie
+	receiptsState  *trie.Trie
+	delegateState  *trie.Trie
 	consensusState ConsensusState
 
 	consensus Consensus
@@ -62,6 +64,19 @@ type states struct {
 
 	gasConsumed map[string]*util.Uint128
 	events      map[string][]*Event
+	results     map[string]*Event
+
+	snapshots []*statesSnapshot
+}
+
+// statesSnapshot is the states at a snapshot, the events and consensus state are not included.
+type statesSnapshot struct {
+	accState      *accountStateSnapshot
+	txsState      *trie.Snapshot
+	eventsState   *trie.Snapshot
+	receiptsState *trie.Snapshot
+	delegateState *trie.Snapshot
+	gasConsumed   map[string]*util.Uint128
 }
 
 func newStates(consensus Consensus, stor storage.Storage) (*states, error) {
@@ -86,6 +101,14 @@ func newStates(consensus Consensus, stor storage.Storage) (*states, error) {
 	if err != nil {
 		return nil, err
 	}
+	receiptsState, err := trie.NewTrie(nil, stateDB, false)
+	if err != nil {
+		return nil, err
+	}
+	delegateState, err := trie.NewTrie(nil, stateDB, false)
+	if err != nil {
+		return nil, err
+	}
 	consensusState, err := consensus.NewState(&consensuspb.ConsensusRoot{}, stateDB, false)
 	if err != nil {
 		return nil, err
@@ -95,6 +118,8 @@ func newStates(consensus Consensus, stor storage.Storage) (*states, error) {
 		accState:       accState,
 		txsState:       txsState,
 		eventsState:    eventsState,
+		receiptsState:  receiptsState,
+		delegateState:  delegateState,
 		consensusState: consensusState,
 
 		consensus: consensus,
@@ -105,6 +130,7 @@ func newStates(consensus Consensus, stor storage.Storage) (*states, error) {
 
 		gasConsumed: make(map[string]*util.Uint128),
 		events:      make(map[string][]*Event),
+		results:     make(map[string]*Event),
 	}, nil
 }
 
@@ -121,6 +147,14 @@ func (s *states) Replay(done *states) error {
 	if err != nil {
 		return err
 	}
+	_, err = s.receiptsState.Replay(done.receiptsState)
+	if err != nil {
+		return err
+	}
+	_, err = s.delegateState.Replay(done.delegateState)
+	if err != nil {
+		return err
+	}
 	err = s.consensusState.Replay(done.consensusState)
 	if err != nil {
 		return err
@@ -145,7 +179,8 @@ func (s *states) ReplayEvent(done *states) error {
 
 	tx := done.txid.(string)
 	events, ok := done.events[tx]
-	if !ok {
+	result, hasResult := done.results[tx]
+	if !ok && !hasResult {
 		return nil
 	}
 
@@ -169,9 +204,29 @@ func (s *states) ReplayEvent(done *states) error {
 		}
 	}
 	done.events = make(map[string][]*Event)
+
+	//replay execution result
+	if hasResult {
+		bytes, err := json.Marshal(result)
+		if err != nil {
+			return err
+		}
+		if _, err := s.eventsState.Put(executionResultKey(txHash), bytes); err != nil {
+			return err
+		}
+	}
+	done.results = make(map[string]*Event)
 	return nil
 }
 
+// executionResultKey is the key of tx's execution result in events trie,
+// the index 0 is never used by the events recorded from 1.
+func executionResultKey(txHash byteutils.Hash) []byte {
+	key := make([]byte, 0, len(txHash)+8)
+	key = append(key, txHash...)
+	return append(key, byteutils.FromInt64(0)...)
+}
+
 func (s *states) Clone() (*states, error) {
 	changelog, err := newChangeLog()
 	if err != nil {
@@ -194,6 +249,14 @@ func (s *states) Clone() (*states, error) {
 	if err != nil {
 		return nil, err
 	}
+	receiptsState, err := trie.NewTrie(s.receiptsState.RootHash(), stateDB, false)
+	if err != nil {
+		return nil, err
+	}
+	delegateState, err := trie.NewTrie(s.delegateState.RootHash(), stateDB, false)
+	if err != nil {
+		return nil, err
+	}
 	consensusState, err := s.consensus.NewState(s.consensusState.RootHash(), stateDB, false)
 	if err != nil {
 		return nil, err
@@ -203,6 +266,8 @@ func (s *states) Clone() (*states, error) {
 		accState:       accState,
 		txsState:       txsState,
 		eventsState:    eventsState,
+		receiptsState:  receiptsState,
+		delegateState:  delegateState,
 		consensusState: consensusState,
 
 		consensus: s.consensus,
@@ -213,6 +278,7 @@ func (s *states) Clone() (*states, error) {
 
 		gasConsumed: make(map[string]*util.Uint128),
 		events:      make(map[string][]*Event),
+		results:     make(map[string]*Event),
 	}, nil
 }
 
@@ -239,7 +305,9 @@ func (s *states) Commit() error {
 		return err
 	}
 
+	s.snapshots = nil
 	s.events = make(map[string][]*Event)
+	s.results = make(map[string]*Event)
 	s.gasConsumed = make(map[string]*util.Uint128)
 	return nil
 }
@@ -256,6 +324,7 @@ func (s *states) RollBack() error {
 	}
 
 	s.events = make(map[string][]*Event)
+	s.results = make(map[string]*Event)
 	s.gasConsumed = make(map[string]*util.Uint128)
 	return nil
 }
@@ -288,6 +357,14 @@ func (s *states) Prepare(txid interface{}) (*states, error) {
 	if err != nil {
 		return nil, err
 	}
+	receiptsState, err := trie.NewTrie(s.ReceiptsRoot(), stateDB, true)
+	if err != nil {
+		return nil, err
+	}
+	delegateState, err := trie.NewTrie(s.DelegateRoot(), stateDB, true)
+	if err != nil {
+		return nil, err
+	}
 	consensusState, err := s.consensus.NewState(s.ConsensusRoot(), stateDB, true)
 	if err != nil {
 		return nil, err
@@ -297,6 +374,8 @@ func (s *states) Prepare(txid interface{}) (*states, error) {
 		accState:       accState,
 		txsState:       txsState,
 		eventsState:    eventsState,
+		receiptsState:  receiptsState,
+		delegateState:  delegateState,
 		consensusState: consensusState,
 
 		consensus: s.consensus,
@@ -307,6 +386,7 @@ func (s *states) Prepare(txid interface{}) (*states, error) {
 
 		gasConsumed: make(map[string]*util.Uint128),
 		events:      make(map[string][]*Event),
+		results:     make(map[string]*Event),
 	}, nil
 }
 
@@ -325,6 +405,41 @@ func (s *states) CheckAndUpdateTo(parent *states) ([]interface{}, error) {
 	return dependency, nil
 }
 
+// Snapshot take a snapshot of states and return its id, snapshots can be nested.
+// The events are not reverted, they are kept as Reset does.
+func (s *states) Snapshot() int {
+	gasConsumed := make(map[string]*util.Uint128)
+	for from, gas := range s.gasConsumed {
+		gasConsumed[from] = gas
+	}
+	s.snapshots = append(s.snapshots, &statesSnapshot{
+		accState:      s.accState.(*accountState).snapshot(),
+		txsState:      s.txsState.Snapshot(),
+		eventsState:   s.eventsState.Snapshot(),
+		receiptsState: s.receiptsState.Snapshot(),
+		delegateState: s.delegateState.Snapshot(),
+		gasConsumed:   gasConsumed,
+	})
+	return len(s.snapshots) - 1
+}
+
+// RevertToSnapshot revert states to the snapshot with id, dropping the changes after it.
+// The snapshot and the ones taken after it are released.
+func (s *states) RevertToSnapshot(id int) error {
+	if id < 0 || id >= len(s.snapshots) {
+		return ErrInvalidSnapshot
+	}
+	snapshot := s.snapshots[id]
+	s.accState.(*accountState).revertToSnapshot(snapshot.accState)
+	s.txsState.RevertToSnapshot(snapshot.txsState)
+	s.eventsState.RevertToSnapshot(snapshot.eventsState)
+	s.receiptsState.RevertToSnapshot(snapshot.receiptsState)
+	s.delegateState.RevertToSnapshot(snapshot.delegateState)
+	s.gasConsumed = snapshot.gasConsumed
+	s.snapshots = s.snapshots[:id]
+	return nil
+}
+
 func (s *states) Reset() error {
 	if err := s.changelog.Reset(); err != nil {
 		return err
@@ -363,10 +478,29 @@ func (s *states) EventsRoot() byteutils.Hash {
 	return s.eventsState.RootHash()
 }
 
+func (s *states) ReceiptsRoot() byteutils.Hash {
+	return s.receiptsState.RootHash()
+}
+
+func (s *states) DelegateRoot() byteutils.Hash {
+	return s.delegateState.RootHash()
+}
+
 func (s *states) ConsensusRoot() *consensuspb.ConsensusRoot {
 	return s.consensusState.RootHash()
 }
 
+// GenerateAccountProof return the account bytes of addr and its merkle proof against AccountsRoot,
+// the dirty accounts are not included until flushed.
+func (s *states) GenerateAccountProof(addr byteutils.Hash) ([]byte, [][]byte, error) {
+	return s.accState.GenerateProof(addr)
+}
+
+// GenerateTxProof return the tx bytes of txHash and its merkle proof against TxsRoot.
+func (s *states) GenerateTxProof(txHash byteutils.Hash) ([]byte, [][]byte, error) {
+	return s.txsState.ProvePath(txHash)
+}
+
 func (s *states) Flush() error {
 	return s.accState.Flush()
 }
@@ -377,6 +511,7 @@ func (s *states) Abort() error {
 	// because we only use abort in reset, close and rollback
 	// in close & rollback, we won't use states any more
 	// in reset, we won't change the three states before we reset them
+	s.snapshots = nil
 	return s.accState.Abort()
 }
 
@@ -395,6 +530,14 @@ func (s *states) GetOrCreateUserAccount(addr byteutils.Hash) (Account, error) {
 	return s.recordAccount(acc)
 }
 
+func (s *states) GetUserAccount(addr byteutils.Hash) (Account, error) {
+	acc, err := s.accState.GetUserAccount(addr)
+	if err != nil {
+		return nil, err
+	}
+	return s.recordAccount(acc)
+}
+
 func (s *states) GetContractAccount(addr byteutils.Hash) (Account, error) {
 	acc, err := s.accState.GetContractAccount(addr)
 	if err != nil {
@@ -406,6 +549,19 @@ func (s *states) GetContractAccount(addr byteutils.Hash) (Account, error) {
 	return s.recordAccount(acc)
 }
 
+// ContractStorageIterator return the iterator of the contract's storage in key order.
+func (s *states) ContractStorageIterator(addr byteutils.Hash) (Iterator, error) {
+	acc, err := s.GetContractAccount(addr)
+	if err != nil {
+		return nil, err
+	}
+	iter, err := acc.Iterator(nil)
+	if err == storage.ErrKeyNotFound {
+		return emptyIterator{}, nil
+	}
+	return iter, err
+}
+
 func (s *states) CreateContractAccount(owner byteutils.Hash, birthPlace byteutils.Hash) (Account, error) {
 	acc, err := s.accState.CreateContractAccount(owner, birthPlace)
 	if err != nil {
@@ -430,6 +586,46 @@ func (s *states) PutTx(txHash byteutils.Hash, txBytes []byte) error {
 	return nil
 }
 
+func (s *states) GetTxReceipt(txHash byteutils.Hash) ([]byte, error) {
+	bytes, err := s.receiptsState.Get(txHash)
+	if err != nil {
+		return nil, err
+	}
+	return bytes, nil
+}
+
+func (s *states) PutTxReceipt(txHash byteutils.Hash, receiptBytes []byte) error {
+	_, err := s.receiptsState.Put(txHash, receiptBytes)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *states) GetDelegate(key []byte) ([]byte, error) {
+	bytes, err := s.delegateState.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	return bytes, nil
+}
+
+func (s *states) PutDelegate(key []byte, val []byte) error {
+	_, err := s.delegateState.Put(key, val)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *states) DelDelegate(key []byte) error {
+	_, err := s.delegateState.Del(key)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
 func (s *states) RecordEvent(txHash byteutils.Hash, event *Event) {
 	events, ok := s.events[txHash.String()]
 	if !ok {
@@ -438,6 +634,22 @@ func (s *states) RecordEvent(txHash byteutils.Hash, event *Event) {
 	s.events[txHash.String()] = append(events, event)
 }
 
+func (s *states) RecordExecutionResult(txHash byteutils.Hash, event *Event) {
+	s.results[txHash.String()] = event
+}
+
+func (s *states) FetchExecutionResult(txHash byteutils.Hash) (*Event, error) {
+	bytes, err := s.eventsState.Get(executionResultKey(txHash))
+	if err != nil {
+		return nil, err
+	}
+	event := new(Event)
+	if err := json.Unmarshal(bytes, event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
 func (s *states) FetchEvents(txHash byteutils.Hash) ([]*Event, error) {
 	events := []*Event{}
 	iter, err := s.eventsState.Iterator(txHash)
@@ -449,7 +661,16 @@ func (s *states) FetchEvents(txHash byteutils.Hash) ([]*Event, error) {
 		if err != nil {
 			return nil, err
 		}
+		resultKey := executionResultKey(txHash)
 		for exist {
+			// the execution result is fetched apart.
+			if byteutils.Equal(iter.Key(), resultKey) {
+				exist, err = iter.Next()
+				if err != nil {
+					return nil, err
+				}
+				continue
+			}
 			event := new(Event)
 			err = json.Unmarshal(iter.Value(), event)
 			if err != nil {
@@ -504,6 +725,24 @@ func (s *states) LoadEventsRoot(root byteutils.Hash) error {
 	return nil
 }
 
+func (s *states) LoadReceiptsRoot(root byteutils.Hash) error {
+	receiptsState, err := trie.NewTrie(root, s.stateDB, false)
+	if err != nil {
+		return err
+	}
+	s.receiptsState = receiptsState
+	return nil
+}
+
+func (s *states) LoadDelegateRoot(root byteutils.Hash) error {
+	delegateState, err := trie.NewTrie(root, s.stateDB, false)
+	if err != nil {
+		return err
+	}
+	s.delegateState = delegateState
+	return nil
+}
+
 func (s *states) LoadConsensusRoot(root *consensuspb.ConsensusRoot) error {
 	consensusState, err := s.consensus.NewState(root, s.stateDB, false)
 	if err != nil {
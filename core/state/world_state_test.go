@@ -0,0 +1,123 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package state
+
+import (
+	"testing"
+
+	"github.com/alexlisong/go-nebulas/consensus/pb"
+	"github.com/alexlisong/go-nebulas/storage"
+	"github.com/alexlisong/go-nebulas/util"
+	"github.com/alexlisong/go-nebulas/util/byteutils"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockConsensus struct{}
+
+func (c *mockConsensus) NewState(root *consensuspb.ConsensusRoot, stor storage.Storage, needChangelog bool) (ConsensusState, error) {
+	return &mockConsensusState{}, nil
+}
+
+type mockConsensusState struct{}
+
+func (cs *mockConsensusState) RootHash() *consensuspb.ConsensusRoot {
+	return &consensuspb.ConsensusRoot{}
+}
+func (cs *mockConsensusState) String() string                 { return "" }
+func (cs *mockConsensusState) Clone() (ConsensusState, error) { return cs, nil }
+func (cs *mockConsensusState) Replay(ConsensusState) error    { return nil }
+func (cs *mockConsensusState) Proposer() byteutils.Hash       { return nil }
+func (cs *mockConsensusState) TimeStamp() int64               { return 0 }
+func (cs *mockConsensusState) NextConsensusState(int64, WorldState) (ConsensusState, error) {
+	return cs, nil
+}
+func (cs *mockConsensusState) Dynasty() ([]byteutils.Hash, error) { return nil, nil }
+func (cs *mockConsensusState) DynastyRoot() byteutils.Hash        { return nil }
+
+func TestWorldState_Snapshot(t *testing.T) {
+	stor, err := storage.NewMemoryStorage()
+	assert.Nil(t, err)
+	ws, err := NewWorldState(&mockConsensus{}, stor)
+	assert.Nil(t, err)
+	assert.Nil(t, ws.Begin())
+
+	addr1, addr2, addr3 := []byte("accAddr1"), []byte("accAddr2"), []byte("accAddr3")
+	ten, _ := util.NewUint128FromInt(10)
+
+	// the outer writes.
+	tws, err := ws.Prepare("tx")
+	assert.Nil(t, err)
+	acc1, err := tws.GetOrCreateUserAccount(addr1)
+	assert.Nil(t, err)
+	assert.Nil(t, acc1.AddBalance(ten))
+	assert.Nil(t, acc1.Put([]byte("var"), []byte("outer")))
+	assert.Nil(t, tws.PutTx([]byte("tx"), []byte("outer")))
+	outer := tws.Snapshot()
+
+	// the inner writes are reverted, nested snapshots after it are released.
+	inner := tws.Snapshot()
+	assert.Nil(t, acc1.AddBalance(ten))
+	assert.Nil(t, acc1.Put([]byte("var"), []byte("inner")))
+	acc2, err := tws.GetOrCreateUserAccount(addr2)
+	assert.Nil(t, err)
+	assert.Nil(t, acc2.AddBalance(ten))
+	assert.Nil(t, tws.PutDelegate([]byte("delegate"), []byte("inner")))
+	nested := tws.Snapshot()
+	_, err = tws.GetOrCreateUserAccount(addr3)
+	assert.Nil(t, err)
+
+	assert.Nil(t, tws.RevertToSnapshot(inner))
+	assert.Equal(t, ErrInvalidSnapshot, tws.RevertToSnapshot(nested))
+	assert.Equal(t, ten, acc1.Balance())
+	val, err := acc1.Get([]byte("var"))
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("outer"), val)
+	val, err = tws.GetTx([]byte("tx"))
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("outer"), val)
+	_, err = tws.GetDelegate([]byte("delegate"))
+	assert.NotNil(t, err)
+	accounts, err := tws.(*txWorldState).accState.DirtyAccounts()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(accounts))
+
+	// the outer snapshot is still valid.
+	acc1.IncrNonce()
+	assert.Nil(t, tws.RevertToSnapshot(outer))
+	assert.Equal(t, uint64(0), acc1.Nonce())
+	assert.Equal(t, ErrInvalidSnapshot, tws.RevertToSnapshot(outer))
+
+	_, err = tws.CheckAndUpdate()
+	assert.Nil(t, err)
+	assert.Nil(t, tws.Close())
+	assert.Nil(t, ws.Flush())
+
+	// the same as the world state with only the outer writes.
+	expected, err := NewWorldState(&mockConsensus{}, stor)
+	assert.Nil(t, err)
+	acc, err := expected.GetOrCreateUserAccount(addr1)
+	assert.Nil(t, err)
+	assert.Nil(t, acc.AddBalance(ten))
+	assert.Nil(t, acc.Put([]byte("var"), []byte("outer")))
+	assert.Nil(t, expected.PutTx([]byte("tx"), []byte("outer")))
+	assert.Nil(t, expected.Flush())
+	assert.Equal(t, expected.AccountsRoot(), ws.AccountsRoot())
+	assert.Equal(t, expected.TxsRoot(), ws.TxsRoot())
+	assert.Equal(t, expected.DelegateRoot(), ws.DelegateRoot())
+}
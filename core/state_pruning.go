@@ -0,0 +1,208 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"sync/atomic"
+
+	"github.com/alexlisong/go-nebulas/core/state"
+	"github.com/alexlisong/go-nebulas/storage"
+	"github.com/alexlisong/go-nebulas/util/byteutils"
+	"github.com/alexlisong/go-nebulas/util/logging"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultStateRetention count of blocks behind LIB whose states are retained.
+	DefaultStateRetention = 4096
+
+	// PrunedHeight the height of the last pruned block in storage
+	PrunedHeight = "blockchain_pruned"
+
+	// PrunedStateKeyPrefix prefix of the marks of pruned blocks in storage
+	PrunedStateKeyPrefix = "prn_"
+)
+
+func prunedStateStorageKey(hash byteutils.Hash) []byte {
+	return append([]byte(PrunedStateKeyPrefix), hash...)
+}
+
+// EnableStatePruning prune the account states of canonical blocks falling retention blocks behind LIB, 0 for default.
+// The states of genesis, LIB, tail and detached tails are always kept.
+func (bc *BlockChain) EnableStatePruning(retention uint64) {
+	if retention == 0 {
+		retention = DefaultStateRetention
+	}
+	atomic.StoreUint64(&bc.stateRetention, retention)
+}
+
+// isStatePruned return if the account state of block with hash is pruned.
+func (bc *BlockChain) isStatePruned(hash byteutils.Hash) (bool, error) {
+	if _, err := bc.storage.Get(prunedStateStorageKey(hash)); err != nil {
+		if err == storage.ErrKeyNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (bc *BlockChain) prunedHeight() (uint64, error) {
+	bytes, err := bc.storage.Get([]byte(PrunedHeight))
+	if err != nil {
+		if err == storage.ErrKeyNotFound {
+			return bc.genesisBlock.Height(), nil
+		}
+		return 0, err
+	}
+	return byteutils.Uint64(bytes), nil
+}
+
+func (bc *BlockChain) triggerStatePruning() {
+	if atomic.LoadUint64(&bc.stateRetention) == 0 {
+		return
+	}
+	// at most one pruning at a time, the next one catches up the LIB.
+	if !atomic.CompareAndSwapInt32(&bc.pruning, 0, 1) {
+		return
+	}
+	go func() {
+		defer atomic.StoreInt32(&bc.pruning, 0)
+		if err := bc.PruneStates(); err != nil {
+			logging.VLog().WithFields(logrus.Fields{
+				"lib": bc.LIB(),
+				"err": err,
+			}).Error("Failed to prune states.")
+		}
+	}()
+}
+
+// PruneStates delete the trie nodes of account states of canonical blocks falling retention blocks behind LIB,
+// except the nodes referenced by retained states. The states of blocks on the forks below LIB are not pruned.
+func (bc *BlockChain) PruneStates() error {
+	retention := atomic.LoadUint64(&bc.stateRetention)
+	if retention == 0 {
+		return nil
+	}
+
+	bc.pruneMu.Lock()
+	defer bc.pruneMu.Unlock()
+
+	lib := bc.LIB()
+	if lib.Height() <= retention {
+		return nil
+	}
+	target := lib.Height() - retention
+	from, err := bc.prunedHeight()
+	if err != nil {
+		return err
+	}
+	if from >= target {
+		return nil
+	}
+
+	// new states are committed in block pool, hold it to keep them from referencing the deleted nodes.
+	bc.bkPool.mu.Lock()
+	defer bc.bkPool.mu.Unlock()
+
+	// mark the nodes referenced by retained states.
+	live := make(map[byteutils.HexHash]bool)
+	mark := func(hash byteutils.Hash) (bool, error) {
+		if live[hash.Hex()] {
+			return false, nil
+		}
+		live[hash.Hex()] = true
+		return true, nil
+	}
+	retained := []*Block{bc.genesisBlock, lib}
+	for block := bc.TailBlock(); block.Height() > target; {
+		retained = append(retained, block)
+		if block = bc.GetBlock(block.ParentHash()); block == nil {
+			return ErrMissingParentBlock
+		}
+	}
+	for _, block := range bc.DetachedTailBlocks() {
+		for block != nil && block.Height() > target {
+			canonical := bc.GetBlockOnCanonicalChainByHeight(block.Height())
+			if canonical != nil && canonical.Hash().Equals(block.Hash()) {
+				break
+			}
+			retained = append(retained, block)
+			block = bc.GetBlock(block.ParentHash())
+		}
+	}
+	for _, block := range retained {
+		if err := state.WalkAccountStateNodes(block.StateRoot(), bc.storage, mark); err != nil {
+			return err
+		}
+	}
+
+	// collect the nodes only referenced by pruned states.
+	pruned := []*Block{}
+	garbage := []byteutils.Hash{}
+	collected := make(map[byteutils.HexHash]bool)
+	collect := func(hash byteutils.Hash) (bool, error) {
+		if live[hash.Hex()] || collected[hash.Hex()] {
+			return false, nil
+		}
+		collected[hash.Hex()] = true
+		garbage = append(garbage, hash)
+		return true, nil
+	}
+	for height := from + 1; height <= target; height++ {
+		block := bc.GetBlockOnCanonicalChainByHeight(height)
+		if block == nil {
+			return ErrMissingParentBlock
+		}
+		if block.statePruned {
+			continue
+		}
+		if err := state.WalkAccountStateNodes(block.StateRoot(), bc.storage, collect); err != nil {
+			return err
+		}
+		pruned = append(pruned, block)
+	}
+
+	// mark the pruned blocks before deleting, a crash in deletion only leaves garbage.
+	for _, block := range pruned {
+		if err := bc.storage.Put(prunedStateStorageKey(block.Hash()), byteutils.FromUint64(block.Height())); err != nil {
+			return err
+		}
+		bc.cachedBlocks.Remove(block.Hash().Hex())
+	}
+	if err := bc.storage.Put([]byte(PrunedHeight), byteutils.FromUint64(target)); err != nil {
+		return err
+	}
+	for _, hash := range garbage {
+		if err := bc.storage.Del(hash); err != nil {
+			return err
+		}
+	}
+
+	logging.VLog().WithFields(logrus.Fields{
+		"from":      from + 1,
+		"to":        target,
+		"lib":       lib,
+		"blocks":    len(pruned),
+		"retained":  len(retained),
+		"liveNodes": len(live),
+		"deleted":   len(garbage),
+	}).Info("Succeed to prune states.")
+	return nil
+}
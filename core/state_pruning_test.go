@@ -0,0 +1,111 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"testing"
+
+	"github.com/alexlisong/go-nebulas/crypto"
+	"github.com/alexlisong/go-nebulas/crypto/keystore"
+	"github.com/alexlisong/go-nebulas/storage"
+	"github.com/alexlisong/go-nebulas/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockChain_PruneStates(t *testing.T) {
+	neb := testNeb(t)
+	bc := neb.chain
+	bc.EnableStatePruning(2)
+
+	coinbase := mockAddress()
+	key, err := keystore.DefaultKS.GetUnlocked(coinbase.String())
+	assert.Nil(t, err)
+	signature, err := crypto.NewSignature(keystore.SECP256K1)
+	assert.Nil(t, err)
+	signature.InitSign(key.(keystore.PrivateKey))
+
+	// the coinbase is rewarded in every block, so the states differ.
+	balances := map[uint64]*util.Uint128{}
+	mint := func(count int) {
+		for i := 0; i < count; i++ {
+			block, err := bc.NewBlock(coinbase)
+			assert.Nil(t, err)
+			block.SetTimestamp(bc.TailBlock().Timestamp() + BlockInterval)
+			assert.Nil(t, block.Seal())
+			assert.Nil(t, block.Sign(signature))
+			assert.Nil(t, bc.BlockPool().Push(block))
+			assert.Equal(t, block.Hash(), bc.TailBlock().Hash())
+
+			acc, err := bc.TailBlock().GetAccount(coinbase.Bytes())
+			assert.Nil(t, err)
+			balances[block.Height()] = acc.Balance()
+		}
+	}
+	mint(6)
+
+	// lib at height 6, the states in (genesis, 4] are pruned.
+	lib := bc.GetBlockOnCanonicalChainByHeight(6)
+	assert.Nil(t, bc.StoreLIBHashToStorage(lib))
+	bc.lib = lib
+	assert.Nil(t, bc.PruneStates())
+
+	for height := uint64(2); height <= 4; height++ {
+		block := bc.GetBlockOnCanonicalChainByHeight(height)
+		assert.NotNil(t, block)
+		assert.True(t, block.StatePruned())
+		_, err := block.GetAccount(coinbase.Bytes())
+		assert.Equal(t, ErrStatePruned, err)
+		_, err = bc.storage.Get(block.StateRoot())
+		assert.Equal(t, storage.ErrKeyNotFound, err)
+	}
+	for height := uint64(5); height <= bc.TailBlock().Height(); height++ {
+		block := bc.GetBlockOnCanonicalChainByHeight(height)
+		assert.False(t, block.StatePruned())
+		acc, err := block.GetAccount(coinbase.Bytes())
+		assert.Nil(t, err)
+		assert.Equal(t, 0, balances[height].Cmp(acc.Balance()))
+	}
+	_, err = bc.genesisBlock.GetAccount(coinbase.Bytes())
+	assert.Nil(t, err)
+
+	// nothing more to prune.
+	assert.Nil(t, bc.PruneStates())
+
+	// restart from storage and continue building blocks.
+	bc.cachedBlocks.Purge()
+	tail, err := bc.LoadTailFromStorage()
+	assert.Nil(t, err)
+	lib, err = bc.LoadLIBFromStorage()
+	assert.Nil(t, err)
+	bc.tailBlock = tail
+	bc.lib = lib
+	acc, err := tail.GetAccount(coinbase.Bytes())
+	assert.Nil(t, err)
+	assert.Equal(t, 0, balances[tail.Height()].Cmp(acc.Balance()))
+
+	mint(2)
+	assert.Equal(t, tail.Height()+2, bc.TailBlock().Height())
+
+	// a block can't be linked to a pruned parent.
+	pruned := bc.GetBlockOnCanonicalChainByHeight(3)
+	block, err := bc.NewBlockFromParent(coinbase, bc.TailBlock())
+	assert.Nil(t, err)
+	block.header.parentHash = pruned.Hash()
+	assert.Equal(t, ErrStatePruned, block.LinkParentBlock(bc, pruned))
+}
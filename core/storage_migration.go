@@ -0,0 +1,200 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"github.com/alexlisong/go-nebulas/core/pb"
+	"github.com/alexlisong/go-nebulas/storage"
+	"github.com/alexlisong/go-nebulas/util/byteutils"
+	"github.com/alexlisong/go-nebulas/util/logging"
+	"github.com/gogo/protobuf/proto"
+	"github.com/sirupsen/logrus"
+)
+
+// migration ids, each one is the schema version reached after the step.
+const (
+	MigrationNamespaceBlockKeys uint64 = 1
+)
+
+// MigrationBatchSize is the number of heights or blocks a migration commits at once.
+var MigrationBatchSize uint64 = 1024
+
+// NewStorageMigrator return a migrator with all core migration steps registered.
+func NewStorageMigrator(stor storage.Storage) (*storage.Migrator, error) {
+	migrator := storage.NewMigrator(stor)
+	steps := []*storage.MigrationStep{
+		{
+			ID:   MigrationNamespaceBlockKeys,
+			Name: "namespace block keys",
+			Up:   namespaceBlockKeys,
+		},
+	}
+	for _, step := range steps {
+		if err := migrator.Register(step); err != nil {
+			return nil, err
+		}
+	}
+	return migrator, nil
+}
+
+// MigrateStorage applies all pending migrations to the storage.
+func MigrateStorage(stor storage.Storage) error {
+	migrator, err := NewStorageMigrator(stor)
+	if err != nil {
+		return err
+	}
+	reports, err := migrator.Run()
+	if err != nil {
+		return err
+	}
+	for _, report := range reports {
+		logging.CLog().WithFields(logrus.Fields{
+			"id":   report.ID,
+			"name": report.Name,
+			"puts": report.Puts,
+			"dels": report.Dels,
+		}).Info("Migrated storage.")
+	}
+	return nil
+}
+
+// namespaceBlockKeys moves the block bodies from the raw block hash to
+// BlockKeyPrefix+hash, and the canonical height index from the raw height
+// to HeightKeyPrefix+height. The blocks on the canonical chain are moved by
+// height, the cursor is the next height to migrate, then the storage is
+// walked for the blocks off the canonical chain, the cursor is followed by
+// the key to walk from.
+//
+// The step runs with the storage batch enabled, the moves of a batch are
+// flushed together with its cursor by progress.Commit.
+func namespaceBlockKeys(stor storage.Storage, progress *storage.MigrationProgress) error {
+	tailHash, err := stor.Get([]byte(Tail))
+	if err == storage.ErrKeyNotFound {
+		// fresh storage, nothing to migrate.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	tailHeight, err := migrationBlockHeight(stor, tailHash)
+	if err != nil {
+		return err
+	}
+
+	// the genesis block is at height 1.
+	height := uint64(1)
+	var start []byte
+	if cursor := progress.Cursor(); cursor != nil {
+		height = byteutils.Uint64(cursor[:8])
+		start = cursor[8:]
+	}
+	for ; height <= tailHeight; height++ {
+		legacyKey := byteutils.FromUint64(height)
+		hash, err := getMovedKey(stor, legacyKey, heightStorageKey(height))
+		if err != nil {
+			return err
+		}
+		if err := moveKey(stor, legacyKey, heightStorageKey(height)); err != nil {
+			return err
+		}
+		if err := moveKey(stor, hash, blockStorageKey(hash)); err != nil {
+			return err
+		}
+
+		if height%MigrationBatchSize == 0 {
+			if err := progress.Commit(byteutils.FromUint64(height + 1)); err != nil {
+				return err
+			}
+		}
+	}
+	if len(start) == 0 {
+		if err := progress.Commit(byteutils.FromUint64(height)); err != nil {
+			return err
+		}
+	}
+
+	return namespaceForkedBlocks(stor, progress, height, start)
+}
+
+// namespaceForkedBlocks moves the blocks left at the raw block hash, which are
+// off the canonical chain. The storage is walked in key order from start, the
+// cursor is committed every MigrationBatchSize moved blocks, so a restarted
+// walk skips the keys visited already.
+func namespaceForkedBlocks(stor storage.Storage, progress *storage.MigrationProgress, height uint64, start []byte) error {
+	moved := uint64(0)
+	return storage.Iterate(stor, start, func(key []byte, value []byte) error {
+		if len(key) != BlockHashLength {
+			return nil
+		}
+		// the trie nodes are keyed by hash too.
+		pbBlock := new(corepb.Block)
+		if err := proto.Unmarshal(value, pbBlock); err != nil {
+			return nil
+		}
+		if pbBlock.Header == nil || !byteutils.Equal(pbBlock.Header.Hash, key) {
+			return nil
+		}
+
+		if err := moveKey(stor, key, blockStorageKey(key)); err != nil {
+			return err
+		}
+		moved++
+		if moved%MigrationBatchSize == 0 {
+			return progress.Commit(append(byteutils.FromUint64(height), key...))
+		}
+		return nil
+	})
+}
+
+// getMovedKey return the value of key, or of newKey if it was moved already.
+func getMovedKey(stor storage.Storage, key []byte, newKey []byte) ([]byte, error) {
+	value, err := stor.Get(key)
+	if err == storage.ErrKeyNotFound {
+		return stor.Get(newKey)
+	}
+	return value, err
+}
+
+// moveKey moves the value from key to newKey, a missing key means it was moved already.
+func moveKey(stor storage.Storage, key []byte, newKey []byte) error {
+	value, err := stor.Get(key)
+	if err == storage.ErrKeyNotFound {
+		_, err = stor.Get(newKey)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if err := stor.Put(newKey, value); err != nil {
+		return err
+	}
+	return stor.Del(key)
+}
+
+func migrationBlockHeight(stor storage.Storage, hash byteutils.Hash) (uint64, error) {
+	value, err := getMovedKey(stor, hash, blockStorageKey(hash))
+	if err != nil {
+		return 0, err
+	}
+	pbBlock := new(corepb.Block)
+	if err := proto.Unmarshal(value, pbBlock); err != nil {
+		return 0, err
+	}
+	return pbBlock.Height, nil
+}
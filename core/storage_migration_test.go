@@ -0,0 +1,236 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/alexlisong/go-nebulas/core/pb"
+	"github.com/alexlisong/go-nebulas/crypto/hash"
+	"github.com/alexlisong/go-nebulas/storage"
+	"github.com/alexlisong/go-nebulas/util/byteutils"
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+var errMockStorage = errors.New("mock storage failure")
+
+// failingStorage fails every write after the first limit ones, and records the start keys of walks.
+type failingStorage struct {
+	storage.Storage
+	limit  int
+	starts [][]byte
+}
+
+func (s *failingStorage) Iterate(start []byte, fn func(key []byte, value []byte) error) error {
+	s.starts = append(s.starts, start)
+	return storage.Iterate(s.Storage, start, fn)
+}
+
+func (s *failingStorage) Put(key []byte, value []byte) error {
+	if s.limit <= 0 {
+		return errMockStorage
+	}
+	s.limit--
+	return s.Storage.Put(key, value)
+}
+
+func (s *failingStorage) Del(key []byte) error {
+	if s.limit <= 0 {
+		return errMockStorage
+	}
+	s.limit--
+	return s.Storage.Del(key)
+}
+
+func mockLegacyChain(t *testing.T, stor storage.Storage, tailHeight uint64) map[uint64][]byte {
+	hashes := make(map[uint64][]byte)
+	for height := uint64(1); height <= tailHeight; height++ {
+		blockHash := hash.Sha3256(byteutils.FromUint64(height))
+		value, err := proto.Marshal(&corepb.Block{Height: height})
+		assert.Nil(t, err)
+		assert.Nil(t, stor.Put(blockHash, value))
+		assert.Nil(t, stor.Put(byteutils.FromUint64(height), blockHash))
+		hashes[height] = blockHash
+	}
+	assert.Nil(t, stor.Put([]byte(Tail), hashes[tailHeight]))
+	return hashes
+}
+
+func TestStorageMigration_NamespaceBlockKeys(t *testing.T) {
+	batchSize := MigrationBatchSize
+	MigrationBatchSize = 4
+	defer func() { MigrationBatchSize = batchSize }()
+
+	stor, _ := storage.NewMemoryStorage()
+	hashes := mockLegacyChain(t, stor, 21)
+
+	// a block off the canonical chain.
+	forkedHash := hash.Sha3256([]byte("forked"))
+	forked, err := proto.Marshal(&corepb.Block{Header: &corepb.BlockHeader{Hash: forkedHash}, Height: 21})
+	assert.Nil(t, err)
+	assert.Nil(t, stor.Put(forkedHash, forked))
+
+	// dry run reports the changes and leaves the storage untouched.
+	migrator, err := NewStorageMigrator(stor)
+	assert.Nil(t, err)
+	reports, err := migrator.DryRun()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(reports))
+	assert.Equal(t, 43, reports[0].Puts)
+	assert.Equal(t, 43, reports[0].Dels)
+	version, err := migrator.SchemaVersion()
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(0), version)
+	_, err = stor.Get(blockStorageKey(hashes[1]))
+	assert.Equal(t, storage.ErrKeyNotFound, err)
+
+	// interrupt the migration mid-way.
+	migrator, err = NewStorageMigrator(&failingStorage{Storage: stor, limit: 31})
+	assert.Nil(t, err)
+	_, err = migrator.Run()
+	assert.Equal(t, errMockStorage, err)
+	version, err = migrator.SchemaVersion()
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(0), version)
+
+	// resume from the committed progress.
+	assert.Nil(t, MigrateStorage(stor))
+	migrator, err = NewStorageMigrator(stor)
+	assert.Nil(t, err)
+	version, err = migrator.SchemaVersion()
+	assert.Nil(t, err)
+	assert.Equal(t, MigrationNamespaceBlockKeys, version)
+
+	for height, blockHash := range hashes {
+		_, err := stor.Get(byteutils.FromUint64(height))
+		assert.Equal(t, storage.ErrKeyNotFound, err)
+		_, err = stor.Get(blockHash)
+		assert.Equal(t, storage.ErrKeyNotFound, err)
+
+		value, err := stor.Get(heightStorageKey(height))
+		assert.Nil(t, err)
+		assert.Equal(t, blockHash, value)
+		value, err = stor.Get(blockStorageKey(blockHash))
+		assert.Nil(t, err)
+		pbBlock := new(corepb.Block)
+		assert.Nil(t, proto.Unmarshal(value, pbBlock))
+		assert.Equal(t, height, pbBlock.Height)
+	}
+
+	_, err = stor.Get(forkedHash)
+	assert.Equal(t, storage.ErrKeyNotFound, err)
+	value, err := stor.Get(blockStorageKey(forkedHash))
+	assert.Nil(t, err)
+	assert.Equal(t, forked, value)
+
+	// nothing left to apply.
+	reports, err = migrator.Run()
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(reports))
+}
+
+func TestStorageMigration_BatchCommit(t *testing.T) {
+	batchSize := MigrationBatchSize
+	MigrationBatchSize = 4
+	defer func() { MigrationBatchSize = batchSize }()
+
+	dir, err := ioutil.TempDir("", "storage_migration")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	stor, err := storage.NewDiskStorage(dir)
+	assert.Nil(t, err)
+	hashes := mockLegacyChain(t, stor, 21)
+
+	// a batch is 4 heights of 4 writes and its cursor, fail in the third batch.
+	migrator, err := NewStorageMigrator(&failingStorage{Storage: stor, limit: 42})
+	assert.Nil(t, err)
+	_, err = migrator.Run()
+	assert.Equal(t, errMockStorage, err)
+
+	// the moves of the failed batch are dropped together with its cursor.
+	for height, blockHash := range hashes {
+		_, err := stor.Get(heightStorageKey(height))
+		_, legacyErr := stor.Get(byteutils.FromUint64(height))
+		_, blockErr := stor.Get(blockHash)
+		if height <= 8 {
+			assert.Nil(t, err)
+			assert.Equal(t, storage.ErrKeyNotFound, legacyErr)
+			assert.Equal(t, storage.ErrKeyNotFound, blockErr)
+		} else {
+			assert.Equal(t, storage.ErrKeyNotFound, err)
+			assert.Nil(t, legacyErr)
+			assert.Nil(t, blockErr)
+		}
+	}
+
+	assert.Nil(t, MigrateStorage(stor))
+	for height, blockHash := range hashes {
+		value, err := stor.Get(heightStorageKey(height))
+		assert.Nil(t, err)
+		assert.Equal(t, blockHash, value)
+	}
+}
+
+func TestStorageMigration_ForkedBlocksProgress(t *testing.T) {
+	batchSize := MigrationBatchSize
+	MigrationBatchSize = 4
+	defer func() { MigrationBatchSize = batchSize }()
+
+	stor, _ := storage.NewMemoryStorage()
+	mockLegacyChain(t, stor, 4)
+	forkedHashes := []byteutils.Hash{}
+	for i := 0; i < 10; i++ {
+		forkedHash := hash.Sha3256(byteutils.FromUint64(uint64(100 + i)))
+		forked, err := proto.Marshal(&corepb.Block{Header: &corepb.BlockHeader{Hash: forkedHash}, Height: 4})
+		assert.Nil(t, err)
+		assert.Nil(t, stor.Put(forkedHash, forked))
+		forkedHashes = append(forkedHashes, forkedHash)
+	}
+	sort.Slice(forkedHashes, func(i, j int) bool {
+		return bytes.Compare(forkedHashes[i], forkedHashes[j]) < 0
+	})
+
+	// the canonical chain takes 18 writes, fail in the second batch of forked blocks.
+	migrator, err := NewStorageMigrator(&failingStorage{Storage: stor, limit: 30})
+	assert.Nil(t, err)
+	_, err = migrator.Run()
+	assert.Equal(t, errMockStorage, err)
+
+	// the walk is resumed after the committed forked blocks.
+	resumed := &failingStorage{Storage: stor, limit: 1000}
+	migrator, err = NewStorageMigrator(resumed)
+	assert.Nil(t, err)
+	_, err = migrator.Run()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(resumed.starts))
+	assert.Equal(t, []byte(forkedHashes[3]), resumed.starts[0])
+
+	for _, forkedHash := range forkedHashes {
+		_, err = stor.Get(forkedHash)
+		assert.Equal(t, storage.ErrKeyNotFound, err)
+		_, err = stor.Get(blockStorageKey(forkedHash))
+		assert.Nil(t, err)
+	}
+}
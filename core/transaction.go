@@ -19,8 +19,10 @@
 package core
 
 import (
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/alexlisong/go-nebulas/crypto/sha3"
@@ -42,10 +44,13 @@ import (
 const (
 	// TxHashByteLength invalid tx hash length(len of []byte)
 	TxHashByteLength = 32
+
+	// EstimateGasMaxIterations max simulations in binary searching the gas limit
+	EstimateGasMaxIterations = 20
 )
 
 var (
-	// TransactionMaxGasPrice max gasPrice:1 * 10 ** 12
+	// TransactionMaxGasPrice default max gasPrice of chain config:1 * 10 ** 12
 	TransactionMaxGasPrice, _ = util.NewUint128FromString("1000000000000")
 
 	// TransactionMaxGas max gas:50 * 10 ** 9
@@ -54,16 +59,49 @@ var (
 	// TransactionGasPrice default gasPrice : 10**6
 	TransactionGasPrice, _ = util.NewUint128FromInt(1000000)
 
-	// MinGasCountPerTransaction default gas for normal transaction
+	// MinGasCountPerTransaction default gas for normal transaction of chain config
 	MinGasCountPerTransaction, _ = util.NewUint128FromInt(20000)
 
-	// GasCountPerByte per byte of data attached to a transaction gas cost
+	// GasCountPerByte default gas cost per byte of data attached to a transaction of chain config
 	GasCountPerByte, _ = util.NewUint128FromInt(1)
 
-	// MaxDataPayLoadLength Max data length in transaction
+	// MaxDataPayLoadLength default max data length in transaction of chain config
 	MaxDataPayLoadLength = 128 * 1024
 	// MaxDataBinPayloadLength Max data length in binary transaction
 	MaxDataBinPayloadLength = 64
+	// MaxDataTypeLength Max data type length in transaction
+	MaxDataTypeLength = 32
+
+	// TxPayloadRegistryForkHeight txs with unregistered data type are rejected by pool from this height, disabled by default.
+	TxPayloadRegistryForkHeight uint64 = math.MaxUint64
+
+	// GasRefundForkHeight from this height, gasLimit * gasPrice is reserved from sender before execution,
+	// and the unused part is refunded after execution, disabled by default.
+	GasRefundForkHeight uint64 = math.MaxUint64
+
+	// TxPayerForkHeight from this height, a tx can carry a payer co-signing it to pay the gas, disabled by default.
+	TxPayerForkHeight uint64 = math.MaxUint64
+
+	// TxTimestampForkHeight from this height, txs whose timestamp is more than MaxTxTimestampAheadOfBlock
+	// ahead of the block's timestamp are rejected, disabled by default.
+	TxTimestampForkHeight uint64 = math.MaxUint64
+
+	// ExpiredAtForkHeight from this height, a tx can carry an expiredAt covered by its hash, disabled by default.
+	ExpiredAtForkHeight uint64 = math.MaxUint64
+
+	// Ed25519ForkHeight from this height, txs can be signed with ed25519, disabled by default.
+	Ed25519ForkHeight uint64 = math.MaxUint64
+
+	// MaxTxTimestampAheadOfBlock max seconds a tx's timestamp can be ahead of the including block's.
+	MaxTxTimestampAheadOfBlock int64 = 24 * 60 * 60
+
+	// ExecutionResultForkHeight from this height, the execution result event of tx is stored apart from
+	// the events it emits, and fetched by FetchExecutionResultEvent only, disabled by default.
+	ExecutionResultForkHeight uint64 = math.MaxUint64
+
+	// GasCountOnTxSize charge the per byte gas on the whole tx size instead of payload length,
+	// it must be the same in the network, disabled for compatibility.
+	GasCountOnTxSize = false
 
 	// MaxEventErrLength Max error length in event
 	MaxEventErrLength = 256
@@ -71,10 +109,13 @@ var (
 
 // TransactionEvent transaction event
 type TransactionEvent struct {
-	Hash    string `json:"hash"`
-	Status  int8   `json:"status"`
-	GasUsed string `json:"gas_used"`
-	Error   string `json:"error"`
+	Hash            string `json:"hash"`
+	Status          int8   `json:"status"`
+	GasUsed         string `json:"gas_used"`
+	Error           string `json:"error"`
+	ErrorCode       uint32 `json:"error_code,omitempty"`
+	ContractAddress string `json:"contract_address,omitempty"`
+	RevertReason    string `json:"revert_reason,omitempty"`
 }
 
 // Transaction type is used to handle all transaction data.
@@ -89,10 +130,17 @@ type Transaction struct {
 	chainID   uint32
 	gasPrice  *util.Uint128
 	gasLimit  *util.Uint128
+	expiredAt int64
 
 	// Signature
 	alg  keystore.Algorithm
 	sign byteutils.Hash // Signature values
+
+	// optional fee payer, signs the same hash with alg.
+	payer     *Address
+	payerSign byteutils.Hash
+
+	size int // cached length of proto bytes, 0 if not computed
 }
 
 // From return from address
@@ -125,16 +173,48 @@ func (tx *Transaction) Nonce() uint64 {
 	return tx.nonce
 }
 
-// Type return tx type
+// ExpiredAt return the timestamp after which tx can't be on chain, 0 if never expires
+func (tx *Transaction) ExpiredAt() int64 {
+	return tx.expiredAt
+}
+
+// Type return tx type, empty if tx has no data
 func (tx *Transaction) Type() string {
+	if tx.data == nil {
+		return ""
+	}
 	return tx.data.Type
 }
 
 // Data return tx data
 func (tx *Transaction) Data() []byte {
+	if tx.data == nil {
+		return nil
+	}
 	return tx.data.Payload
 }
 
+// Payer return the fee payer, nil if the sender pays
+func (tx *Transaction) Payer() *Address {
+	return tx.payer
+}
+
+// SetPayer set the fee payer, tx must be signed by both sender and payer after it.
+// The payer signs with the sender's signature algorithm, there is a single alg in tx.
+func (tx *Transaction) SetPayer(payer *Address) {
+	tx.payer = payer
+	tx.payerSign = nil
+	tx.size = 0
+}
+
+// feePayer return the address paying the gas of tx.
+func (tx *Transaction) feePayer() *Address {
+	if tx.payer != nil {
+		return tx.payer
+	}
+	return tx.from
+}
+
 // ToProto converts domain Tx to proto Tx
 func (tx *Transaction) ToProto() (proto.Message, error) {
 	value, err := tx.value.ToFixedSizeByteSlice()
@@ -149,7 +229,7 @@ func (tx *Transaction) ToProto() (proto.Message, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &corepb.Transaction{
+	pbTx := &corepb.Transaction{
 		Hash:      tx.hash,
 		From:      tx.from.address,
 		To:        tx.to.address,
@@ -160,15 +240,42 @@ func (tx *Transaction) ToProto() (proto.Message, error) {
 		ChainId:   tx.chainID,
 		GasPrice:  gasPrice,
 		GasLimit:  gasLimit,
+		ExpiredAt: tx.expiredAt,
 		Alg:       uint32(tx.alg),
 		Sign:      tx.sign,
-	}, nil
+	}
+	if tx.payer != nil {
+		pbTx.Payer = tx.payer.address
+		pbTx.PayerSign = tx.payerSign
+	}
+	return pbTx, nil
+}
+
+// FromProtoWithChainID converts proto Tx into domain Tx, rejecting a tx
+// of another chain before any of its fields are decoded.
+func (tx *Transaction) FromProtoWithChainID(msg proto.Message, chainID uint32) error {
+	if msg, ok := msg.(*corepb.Transaction); ok && msg != nil && msg.ChainId != chainID {
+		return ErrInvalidChainID
+	}
+	return tx.FromProto(msg)
+}
+
+// FromRelayedProto converts proto Tx relayed by peers into domain Tx, rejecting
+// a malformed data type before the tx is decoded.
+func (tx *Transaction) FromRelayedProto(msg proto.Message, chainID uint32) error {
+	if msg, ok := msg.(*corepb.Transaction); ok && msg != nil && msg.Data != nil {
+		if err := CheckTxDataType(msg.Data.Type); err != nil {
+			return err
+		}
+	}
+	return tx.FromProtoWithChainID(msg, chainID)
 }
 
 // FromProto converts proto Tx into domain Tx
 func (tx *Transaction) FromProto(msg proto.Message) error {
 	if msg, ok := msg.(*corepb.Transaction); ok {
 		if msg != nil {
+			tx.size = 0
 			tx.hash = msg.Hash
 			from, err := AddressParseFromBytes(msg.From)
 			if err != nil {
@@ -191,11 +298,12 @@ func (tx *Transaction) FromProto(msg proto.Message) error {
 			tx.nonce = msg.Nonce
 			tx.timestamp = msg.Timestamp
 			tx.chainID = msg.ChainId
+			config := GetChainConfig(tx.chainID)
 
 			if msg.Data == nil {
 				return ErrInvalidTransactionData
 			}
-			if len(msg.Data.Payload) > MaxDataPayLoadLength {
+			if len(msg.Data.Payload) > config.MaxDataPayLoadLength {
 				return ErrTxDataPayLoadOutOfMaxLength
 			}
 			if CheckGenesisTransaction(tx) == false &&
@@ -209,7 +317,7 @@ func (tx *Transaction) FromProto(msg proto.Message) error {
 			if err != nil {
 				return err
 			}
-			if gasPrice.Cmp(util.Uint128Zero()) <= 0 || gasPrice.Cmp(TransactionMaxGasPrice) > 0 {
+			if gasPrice.Cmp(util.Uint128Zero()) <= 0 || gasPrice.Cmp(config.TransactionMaxGasPrice) > 0 {
 				return ErrInvalidGasPrice
 			}
 			tx.gasPrice = gasPrice
@@ -223,6 +331,11 @@ func (tx *Transaction) FromProto(msg proto.Message) error {
 			}
 			tx.gasLimit = gasLimit
 
+			if msg.ExpiredAt < 0 {
+				return ErrInvalidExpiredAt
+			}
+			tx.expiredAt = msg.ExpiredAt
+
 			alg := keystore.Algorithm(msg.Alg)
 			if err := crypto.CheckAlgorithm(alg); err != nil {
 				return err
@@ -230,6 +343,16 @@ func (tx *Transaction) FromProto(msg proto.Message) error {
 
 			tx.alg = alg
 			tx.sign = msg.Sign
+
+			tx.payer = nil
+			if len(msg.Payer) > 0 {
+				payer, err := AddressParseFromBytes(msg.Payer)
+				if err != nil {
+					return err
+				}
+				tx.payer = payer
+			}
+			tx.payerSign = msg.PayerSign
 			return nil
 		}
 		return ErrInvalidProtoToTransaction
@@ -253,12 +376,177 @@ func (tx *Transaction) String() string {
 	)
 }
 
+// ToBytes return the proto bytes of tx
+func (tx *Transaction) ToBytes() ([]byte, error) {
+	pbTx, err := tx.ToProto()
+	if err != nil {
+		return nil, err
+	}
+	return proto.Marshal(pbTx)
+}
+
+// FromBytes recover tx from the proto bytes
+func (tx *Transaction) FromBytes(data []byte) error {
+	pbTx := new(corepb.Transaction)
+	if err := proto.Unmarshal(data, pbTx); err != nil {
+		return err
+	}
+	return tx.FromProto(pbTx)
+}
+
+// Size return the length of proto bytes, which is cached until tx is signed again.
+func (tx *Transaction) Size() int {
+	if tx.size == 0 {
+		data, err := tx.ToBytes()
+		if err != nil {
+			return 0
+		}
+		tx.size = len(data)
+	}
+	return tx.size
+}
+
+// txJSON is the canonical json form of transaction.
+type txJSON struct {
+	ChainID   uint32 `json:"chainID"`
+	Hash      string `json:"hash"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Value     string `json:"value"`
+	Nonce     uint64 `json:"nonce"`
+	Timestamp int64  `json:"timestamp"`
+	GasPrice  string `json:"gasPrice"`
+	GasLimit  string `json:"gasLimit"`
+	Type      string `json:"type"`
+	Payload   string `json:"payload"`
+	ExpiredAt int64  `json:"expiredAt,omitempty"`
+	Alg       uint32 `json:"alg"`
+	Sign      string `json:"sign"`
+	Payer     string `json:"payer,omitempty"`
+	PayerSign string `json:"payerSign,omitempty"`
+}
+
+// MarshalJSON return the canonical json of tx, payload is base64 encoded, hash & sign are hex encoded.
+func (tx *Transaction) MarshalJSON() ([]byte, error) {
+	obj := &txJSON{
+		ChainID:   tx.chainID,
+		Hash:      tx.hash.String(),
+		From:      tx.from.String(),
+		To:        tx.to.String(),
+		Value:     tx.value.String(),
+		Nonce:     tx.nonce,
+		Timestamp: tx.timestamp,
+		GasPrice:  tx.gasPrice.String(),
+		GasLimit:  tx.gasLimit.String(),
+		Type:      tx.Type(),
+		Payload:   base64.StdEncoding.EncodeToString(tx.Data()),
+		ExpiredAt: tx.expiredAt,
+		Alg:       uint32(tx.alg),
+		Sign:      tx.sign.String(),
+	}
+	if tx.payer != nil {
+		obj.Payer = tx.payer.String()
+		obj.PayerSign = tx.payerSign.String()
+	}
+	return json.Marshal(obj)
+}
+
+// UnmarshalJSON recover tx from the canonical json, the tx hash must match its content.
+func (tx *Transaction) UnmarshalJSON(data []byte) error {
+	obj := new(txJSON)
+	if err := json.Unmarshal(data, obj); err != nil {
+		return err
+	}
+
+	hash, err := byteutils.FromHex(obj.Hash)
+	if err != nil {
+		return ErrInvalidTxJSONHex
+	}
+	sign, err := byteutils.FromHex(obj.Sign)
+	if err != nil {
+		return ErrInvalidTxJSONHex
+	}
+	payload, err := base64.StdEncoding.DecodeString(obj.Payload)
+	if err != nil {
+		return ErrInvalidTxJSONBase64
+	}
+	from, err := AddressParse(obj.From)
+	if err != nil {
+		return err
+	}
+	to, err := AddressParse(obj.To)
+	if err != nil {
+		return err
+	}
+
+	fixedSizeBytes := func(str string) ([]byte, error) {
+		v, err := util.NewUint128FromString(str)
+		if err != nil {
+			return nil, err
+		}
+		return v.ToFixedSizeByteSlice()
+	}
+	value, err := fixedSizeBytes(obj.Value)
+	if err != nil {
+		return err
+	}
+	gasPrice, err := fixedSizeBytes(obj.GasPrice)
+	if err != nil {
+		return err
+	}
+	gasLimit, err := fixedSizeBytes(obj.GasLimit)
+	if err != nil {
+		return err
+	}
+
+	pbTx := &corepb.Transaction{
+		Hash:      hash,
+		From:      from.address,
+		To:        to.address,
+		Value:     value,
+		Nonce:     obj.Nonce,
+		Timestamp: obj.Timestamp,
+		Data:      &corepb.Data{Type: obj.Type, Payload: payload},
+		ChainId:   obj.ChainID,
+		GasPrice:  gasPrice,
+		GasLimit:  gasLimit,
+		ExpiredAt: obj.ExpiredAt,
+		Alg:       obj.Alg,
+		Sign:      sign,
+	}
+	if len(obj.Payer) > 0 {
+		payer, err := AddressParse(obj.Payer)
+		if err != nil {
+			return err
+		}
+		payerSign, err := byteutils.FromHex(obj.PayerSign)
+		if err != nil {
+			return ErrInvalidTxJSONHex
+		}
+		pbTx.Payer = payer.address
+		pbTx.PayerSign = payerSign
+	}
+	if err := tx.FromProto(pbTx); err != nil {
+		return err
+	}
+
+	wantedHash, err := tx.calHash()
+	if err != nil {
+		return err
+	}
+	if !wantedHash.Equals(tx.hash) {
+		return ErrInvalidTransactionHash
+	}
+	return nil
+}
+
 // Transactions is an alias of Transaction array.
 type Transactions []*Transaction
 
 // NewTransaction create #Transaction instance.
 func NewTransaction(chainID uint32, from, to *Address, value *util.Uint128, nonce uint64, payloadType string, payload []byte, gasPrice *util.Uint128, gasLimit *util.Uint128) (*Transaction, error) {
-	if gasPrice == nil || gasPrice.Cmp(util.NewUint128()) <= 0 || gasPrice.Cmp(TransactionMaxGasPrice) > 0 {
+	config := GetChainConfig(chainID)
+	if gasPrice == nil || gasPrice.Cmp(util.NewUint128()) <= 0 || gasPrice.Cmp(config.TransactionMaxGasPrice) > 0 {
 		return nil, ErrInvalidGasPrice
 	}
 	if gasLimit == nil || gasLimit.Cmp(util.NewUint128()) <= 0 || gasLimit.Cmp(TransactionMaxGas) > 0 {
@@ -269,7 +557,11 @@ func NewTransaction(chainID uint32, from, to *Address, value *util.Uint128, nonc
 		return nil, ErrInvalidArgument
 	}
 
-	if len(payload) > MaxDataPayLoadLength {
+	if err := CheckTxDataType(payloadType); err != nil {
+		return nil, err
+	}
+
+	if len(payload) > config.MaxDataPayLoadLength {
 		return nil, ErrTxDataPayLoadOutOfMaxLength
 	}
 
@@ -287,6 +579,24 @@ func NewTransaction(chainID uint32, from, to *Address, value *util.Uint128, nonc
 	return tx, nil
 }
 
+// NewTransactionWithExpiration create #Transaction instance which can't be on chain after expiredAt.
+func NewTransactionWithExpiration(chainID uint32, from, to *Address, value *util.Uint128, nonce uint64, payloadType string, payload []byte, gasPrice *util.Uint128, gasLimit *util.Uint128, expiredAt int64) (*Transaction, error) {
+	if expiredAt < 0 {
+		return nil, ErrInvalidExpiredAt
+	}
+	tx, err := NewTransaction(chainID, from, to, value, nonce, payloadType, payload, gasPrice, gasLimit)
+	if err != nil {
+		return nil, err
+	}
+	tx.expiredAt = expiredAt
+	return tx, nil
+}
+
+// IsExpired return if tx can't be on chain at the given timestamp.
+func (tx *Transaction) IsExpired(timestamp int64) bool {
+	return tx.expiredAt != 0 && timestamp > tx.expiredAt
+}
+
 // Hash return the hash of transaction.
 func (tx *Transaction) Hash() byteutils.Hash {
 	return tx.hash
@@ -302,15 +612,35 @@ func (tx *Transaction) GasLimit() *util.Uint128 {
 	return tx.gasLimit
 }
 
-// GasCountOfTxBase calculate the actual amount for a tx with data
+// Fee return the max gas fee of tx, gasPrice * gasLimit.
+func (tx *Transaction) Fee() (*util.Uint128, error) {
+	return tx.gasPrice.Mul(tx.gasLimit)
+}
+
+// Cost return the max balance spent by tx, Fee() + value.
+func (tx *Transaction) Cost() (*util.Uint128, error) {
+	fee, err := tx.Fee()
+	if err != nil {
+		return nil, err
+	}
+	return fee.Add(tx.value)
+}
+
+// GasCountOfTxBase calculate the actual amount for a tx with data, by the config of tx's chain.
+// If GasCountOnTxSize is set, the per byte gas is charged on the size of tx instead of payload.
 func (tx *Transaction) GasCountOfTxBase() (*util.Uint128, error) {
-	txGas := MinGasCountPerTransaction
-	if tx.DataLen() > 0 {
-		dataLen, err := util.NewUint128FromInt(int64(tx.DataLen()))
+	config := GetChainConfig(tx.chainID)
+	txGas := config.MinGasCountPerTransaction
+	chargedLen := tx.DataLen()
+	if GasCountOnTxSize {
+		chargedLen = tx.Size()
+	}
+	if chargedLen > 0 {
+		dataLen, err := util.NewUint128FromInt(int64(chargedLen))
 		if err != nil {
 			return nil, err
 		}
-		dataGas, err := dataLen.Mul(GasCountPerByte)
+		dataGas, err := dataLen.Mul(config.GasCountPerByte)
 		if err != nil {
 			return nil, err
 		}
@@ -325,7 +655,46 @@ func (tx *Transaction) GasCountOfTxBase() (*util.Uint128, error) {
 
 // DataLen return the length of payload
 func (tx *Transaction) DataLen() int {
-	return len(tx.data.Payload)
+	return len(tx.Data())
+}
+
+// CheckTxDataType check data type is non-empty, at most MaxDataTypeLength bytes and printable ascii.
+func CheckTxDataType(payloadType string) error {
+	if len(payloadType) == 0 || len(payloadType) > MaxDataTypeLength {
+		return ErrInvalidTxDataType
+	}
+	for i := 0; i < len(payloadType); i++ {
+		if payloadType[i] < 0x20 || payloadType[i] > 0x7e {
+			return ErrInvalidTxDataType
+		}
+	}
+	return nil
+}
+
+// IsRegisteredPayloadType return if the payload type can be loaded by LoadPayload and is activated at height.
+func IsRegisteredPayloadType(payloadType string, height uint64) bool {
+	switch payloadType {
+	case TxPayloadBinaryType, TxPayloadDeployType, TxPayloadCallType, TxPayloadRecoveryType, TxPayloadVoteType, TxPayloadBatchType, TxPayloadFreezeType, TxPayloadUpgradeType:
+		return height >= payloadForkHeight(payloadType)
+	}
+	return false
+}
+
+// payloadForkHeight return the height from which the payload type is activated.
+func payloadForkHeight(payloadType string) uint64 {
+	switch payloadType {
+	case TxPayloadRecoveryType:
+		return RecoveryForkHeight
+	case TxPayloadVoteType:
+		return VoteForkHeight
+	case TxPayloadBatchType:
+		return BatchForkHeight
+	case TxPayloadFreezeType:
+		return FreezeForkHeight
+	case TxPayloadUpgradeType:
+		return ContractUpgradeForkHeight
+	}
+	return 0
 }
 
 // LoadPayload returns tx's payload
@@ -342,13 +711,46 @@ func (tx *Transaction) LoadPayload() (TxPayload, error) {
 		payload, err = LoadDeployPayload(tx.data.Payload)
 	case TxPayloadCallType:
 		payload, err = LoadCallPayload(tx.data.Payload)
+	case TxPayloadRecoveryType:
+		payload, err = LoadRecoveryPayload(tx.data.Payload)
+	case TxPayloadVoteType:
+		payload, err = LoadVotePayload(tx.data.Payload)
+	case TxPayloadBatchType:
+		payload, err = LoadBatchPayload(tx.data.Payload)
+	case TxPayloadFreezeType:
+		payload, err = LoadFreezePayload(tx.data.Payload)
+	case TxPayloadUpgradeType:
+		payload, err = LoadUpgradePayload(tx.data.Payload)
 	default:
 		err = ErrInvalidTxPayloadType
 	}
 	return payload, err
 }
 
-func submitTx(tx *Transaction, block *Block, ws WorldState, gas *util.Uint128, exeErr error, exeErrTy string) (bool, error) {
+// loadExecutionPayload return the payload executed in block, which is the accept call
+// if tx is a binary transfer to a contract after ContractAcceptForkHeight.
+func (tx *Transaction) loadExecutionPayload(block *Block, ws WorldState) (TxPayload, error) {
+	// the payload types not activated yet are unknown to the block, as before their forks.
+	if block.Height() < payloadForkHeight(tx.data.Type) {
+		return nil, ErrInvalidTxPayloadType
+	}
+	payload, err := tx.LoadPayload()
+	if err != nil {
+		return nil, err
+	}
+	if tx.data.Type != TxPayloadBinaryType || block.Height() < ContractAcceptForkHeight {
+		return payload, nil
+	}
+	if _, err := ws.GetContractAccount(tx.to.address); err != nil {
+		if err == state.ErrAccountNotFound || err == state.ErrContractCheckFailed {
+			return payload, nil
+		}
+		return nil, err
+	}
+	return NewCallPayload(ContractAcceptFunction, "")
+}
+
+func submitTx(tx *Transaction, block *Block, ws WorldState, snapshot int, gas *util.Uint128, exeErr error, exeErrTy string, trace *GasTrace) (bool, error) {
 	if exeErr != nil {
 		logging.VLog().WithFields(logrus.Fields{
 			"err":         exeErr,
@@ -358,13 +760,41 @@ func submitTx(tx *Transaction, block *Block, ws WorldState, gas *util.Uint128, e
 	}
 
 	if exeErr != nil {
-		// if execution failed, the previous changes on world state should be reset
-		if err := ws.Reset(); err != nil {
-			// if reset failed, the tx should be given back
+		exeErr = newTxError(exeErr, false, true)
+
+		// if execution failed, the changes since the snapshot should be reverted
+		if err := ws.RevertToSnapshot(snapshot); err != nil {
+			// if revert failed, the tx should be given back
 			return true, err
 		}
 	}
 
+	// owner's activity cancels the pending recovery claim, even if execution failed.
+	if err := cancelRecoveryClaim(tx, block, ws); err != nil {
+		logging.VLog().WithFields(logrus.Fields{
+			"err":   err,
+			"tx":    tx,
+			"block": block,
+		}).Error("Failed to cancel recovery claim, unexpected error")
+		return true, err
+	}
+
+	trace.settle(tx.gasLimit, gas, ErrorCause(exeErr) == ErrOutOfGasLimit && gas.Cmp(tx.gasLimit) == 0)
+	if block.gasTraces != nil {
+		block.gasTraces[tx.hash.Hex()] = trace
+	}
+
+	// the reservation is kept only if execution succeeded, otherwise it has been reset.
+	if err := tx.settleGasFee(block, gas, exeErr == nil, ws); err != nil {
+		logging.VLog().WithFields(logrus.Fields{
+			"err":   err,
+			"tx":    tx,
+			"gas":   gas,
+			"block": block,
+		}).Error("Failed to settle gas fee, unexpected error")
+		return true, err
+	}
+
 	if err := tx.recordGas(gas, ws); err != nil {
 		logging.VLog().WithFields(logrus.Fields{
 			"err":   err,
@@ -374,7 +804,7 @@ func submitTx(tx *Transaction, block *Block, ws WorldState, gas *util.Uint128, e
 		}).Error("Failed to record gas, unexpected error")
 		return true, err
 	}
-	if err := tx.recordResultEvent(gas, exeErr, ws); err != nil {
+	if err := tx.recordResultEvent(block, gas, exeErr, ws); err != nil {
 		logging.VLog().WithFields(logrus.Fields{
 			"err":   err,
 			"tx":    tx,
@@ -383,12 +813,29 @@ func submitTx(tx *Transaction, block *Block, ws WorldState, gas *util.Uint128, e
 		}).Error("Failed to record result event, unexpected error")
 		return true, err
 	}
+	if err := tx.recordReceipt(block, gas, exeErr, trace, ws); err != nil {
+		logging.VLog().WithFields(logrus.Fields{
+			"err":   err,
+			"tx":    tx,
+			"gas":   gas,
+			"block": block,
+		}).Error("Failed to record receipt, unexpected error")
+		return true, err
+	}
 	// No error, won't giveback the tx
 	return false, nil
 }
 
-// VerifyExecution transaction and return result.
+// VerifyExecution transaction and return result, the error is a *TxError.
 func VerifyExecution(tx *Transaction, block *Block, ws WorldState) (bool, error) {
+	giveback, err := verifyExecution(tx, block, ws)
+	return giveback, newTxError(err, giveback, false)
+}
+
+func verifyExecution(tx *Transaction, block *Block, ws WorldState) (bool, error) {
+	// the failed execution is reverted to the snapshot, all changes of tx are dropped.
+	snapshot := ws.Snapshot()
+
 	// step0. perpare accounts.
 	fromAcc, err := ws.GetOrCreateUserAccount(tx.from.address)
 	if err != nil {
@@ -398,14 +845,20 @@ func VerifyExecution(tx *Transaction, block *Block, ws WorldState) (bool, error)
 	if err != nil {
 		return true, err
 	}
+	payerAcc := fromAcc
+	if tx.payer != nil {
+		if payerAcc, err = ws.GetOrCreateUserAccount(tx.payer.address); err != nil {
+			return true, err
+		}
+	}
 
-	// step1. check balance >= gasLimit * gasPrice
-	limitedFee, err := tx.gasLimit.Mul(tx.gasPrice)
+	// step1. check payer's balance >= gasLimit * gasPrice
+	limitedFee, err := tx.Fee()
 	if err != nil {
 		// Gas overflow, won't giveback the tx
 		return false, ErrGasFeeOverflow
 	}
-	if fromAcc.Balance().Cmp(limitedFee) < 0 {
+	if payerAcc.Balance().Cmp(limitedFee) < 0 {
 		// Balance is smaller than limitedFee, won't giveback the tx
 		return false, ErrInsufficientBalance
 	}
@@ -417,6 +870,8 @@ func VerifyExecution(tx *Transaction, block *Block, ws WorldState) (bool, error)
 		return false, ErrGasCntOverflow
 	}
 	gasUsed := baseGas
+	trace := newGasTrace()
+	trace.BaseGas = baseGas
 	if tx.gasLimit.Cmp(gasUsed) < 0 {
 		logging.VLog().WithFields(logrus.Fields{
 			"error":       ErrOutOfGasLimit,
@@ -431,9 +886,9 @@ func VerifyExecution(tx *Transaction, block *Block, ws WorldState) (bool, error)
 	// !!!!!!Attention: all txs passed here will be on chain.
 
 	// step3. check payload vaild.
-	payload, payloadErr := tx.LoadPayload()
+	payload, payloadErr := tx.loadExecutionPayload(block, ws)
 	if payloadErr != nil {
-		return submitTx(tx, block, ws, gasUsed, payloadErr, "Failed to load payload.")
+		return submitTx(tx, block, ws, snapshot, gasUsed, payloadErr, "Failed to load payload.", trace)
 	}
 
 	// step4. calculate base gas of payload
@@ -446,20 +901,38 @@ func VerifyExecution(tx *Transaction, block *Block, ws WorldState) (bool, error)
 			"payloadBaseGas": payload.BaseGasCount(),
 			"block":          block,
 		}).Error("Failed to add payload base gas, unexpected error")
-		return submitTx(tx, block, ws, gasUsed, ErrGasCntOverflow, "Failed to add the count of base payload gas")
+		return submitTx(tx, block, ws, snapshot, gasUsed, ErrGasCntOverflow, "Failed to add the count of base payload gas", trace)
 	}
 	gasUsed = payloadGas
+	trace.PayloadBaseGas = payload.BaseGasCount()
 	if tx.gasLimit.Cmp(gasUsed) < 0 {
-		return submitTx(tx, block, ws, tx.gasLimit, ErrOutOfGasLimit, "Failed to check gasLimit >= txBaseGas + payloasBaseGas.")
+		return submitTx(tx, block, ws, snapshot, tx.gasLimit, ErrOutOfGasLimit, "Failed to check gasLimit >= txBaseGas + payloasBaseGas.", trace)
 	}
 
-	// step5. check balance >= limitedFee + value. and transfer
-	minBalanceRequired, balanceErr := limitedFee.Add(tx.value)
-	if balanceErr != nil {
-		return submitTx(tx, block, ws, gasUsed, ErrGasFeeOverflow, "Failed to add tx.value")
+	// step5. check from is not frozen, balance >= limitedFee + value. and transfer
+	frozen, err := isAccountFrozen(fromAcc, block)
+	if err != nil {
+		return true, err
+	}
+	if frozen {
+		return submitTx(tx, block, ws, snapshot, gasUsed, ErrAccountFrozen, "Failed to transfer from a frozen account", trace)
+	}
+	// the payer covers limitedFee, so the sender only needs value.
+	minBalanceRequired := tx.value
+	if tx.payer == nil {
+		var balanceErr error
+		if minBalanceRequired, balanceErr = tx.Cost(); balanceErr != nil {
+			return submitTx(tx, block, ws, snapshot, gasUsed, ErrGasFeeOverflow, "Failed to add tx.value", trace)
+		}
 	}
 	if fromAcc.Balance().Cmp(minBalanceRequired) < 0 {
-		return submitTx(tx, block, ws, gasUsed, ErrInsufficientBalance, "Failed to check balance >= gasLimit * gasPrice + value")
+		return submitTx(tx, block, ws, snapshot, gasUsed, ErrInsufficientBalance, "Failed to check balance >= gasLimit * gasPrice + value", trace)
+	}
+	if block.Height() >= GasRefundForkHeight {
+		// reserve the limited fee, the contract sees the balance without it.
+		if err := payerAcc.SubBalance(limitedFee); err != nil {
+			return submitTx(tx, block, ws, snapshot, gasUsed, ErrInsufficientBalance, "Failed to reserve gasLimit * gasPrice", trace)
+		}
 	}
 	var transferSubErr, transferAddErr error
 	transferSubErr = fromAcc.SubBalance(tx.value)
@@ -475,7 +948,7 @@ func VerifyExecution(tx *Transaction, block *Block, ws WorldState) (bool, error)
 			"toBalance":   toAcc.Balance(),
 			"block":       block,
 		}).Error("Failed to transfer value, unexpected error")
-		return submitTx(tx, block, ws, gasUsed, ErrInvalidTransfer, "Failed to transfer tx.value")
+		return submitTx(tx, block, ws, snapshot, gasUsed, ErrInvalidTransfer, "Failed to transfer tx.value", trace)
 	}
 
 	// step6. calculate contract's limited gas
@@ -487,67 +960,76 @@ func VerifyExecution(tx *Transaction, block *Block, ws WorldState) (bool, error)
 			"gasUsed": gasUsed,
 			"block":   block,
 		}).Error("Failed to calculate payload's limit gas, unexpected error")
-		return submitTx(tx, block, ws, tx.gasLimit, ErrOutOfGasLimit, "Failed to calculate payload's limit gas")
+		return submitTx(tx, block, ws, snapshot, tx.gasLimit, ErrOutOfGasLimit, "Failed to calculate payload's limit gas", trace)
 	}
 
 	// step7. execute contract.
 	gasExecution, _, exeErr := payload.Execute(contractLimitedGas, tx, block, ws)
+	trace.ExecutionGas = gasExecution
 
 	// step8. calculate final gas.
 	allGas, gasErr := gasUsed.Add(gasExecution)
 	if gasErr != nil {
-		return submitTx(tx, block, ws, gasUsed, ErrGasCntOverflow, "Failed to add the fee of execution gas")
+		return submitTx(tx, block, ws, snapshot, gasUsed, ErrGasCntOverflow, "Failed to add the fee of execution gas", trace)
 	}
 	if tx.gasLimit.Cmp(allGas) < 0 {
-		return submitTx(tx, block, ws, tx.gasLimit, ErrOutOfGasLimit, "Failed to check gasLimit >= allGas")
+		return submitTx(tx, block, ws, snapshot, tx.gasLimit, ErrOutOfGasLimit, "Failed to check gasLimit >= allGas", trace)
 	}
 
 	// step9. over
-	return submitTx(tx, block, ws, allGas, exeErr, "Failed to execute payload")
+	return submitTx(tx, block, ws, snapshot, allGas, exeErr, "Failed to execute payload", trace)
 }
 
 // simulateExecution simulate execution and return gasUsed, executionResult and executionErr, sysErr if occurred.
 func (tx *Transaction) simulateExecution(block *Block) (*SimulateResult, error) {
+	return tx.simulateExecutionWithGasLimit(block, block.WorldState(), TransactionMaxGas)
+}
+
+// simulateExecutionWithGasLimit simulate execution in ws with the given gasLimit.
+func (tx *Transaction) simulateExecutionWithGasLimit(block *Block, ws WorldState, gasLimit *util.Uint128) (*SimulateResult, error) {
 	// hash is necessary in nvm
 	hash, err := tx.calHash()
 	if err != nil {
 		return nil, err
 	}
 	tx.hash = hash
+	tx.size = 0
 
-	// Generate world state
-	ws := block.WorldState()
-
-	// Get from account
-	fromAcc, err := ws.GetOrCreateUserAccount(tx.from.address)
-	if err != nil {
-		return nil, err
+	trace := newGasTrace()
+	simulated := func(gasUsed *util.Uint128, msg string, err error) *SimulateResult {
+		trace.settle(gasLimit, gasUsed, err == ErrOutOfGasLimit)
+		return &SimulateResult{gasUsed, msg, err, trace}
 	}
 
 	// calculate min gas.
 	gasUsed, err := tx.GasCountOfTxBase()
 	if err != nil {
-		return &SimulateResult{util.NewUint128(), "GasCountOfTxBase error", err}, nil
+		return simulated(util.NewUint128(), "GasCountOfTxBase error", err), nil
 	}
+	trace.BaseGas = gasUsed
 
-	payload, err := tx.LoadPayload()
+	payload, err := tx.loadExecutionPayload(block, ws)
 	if err != nil {
-		return &SimulateResult{gasUsed, "Invalid payload", err}, nil
+		return simulated(gasUsed, "Invalid payload", err), nil
 	}
 
 	payloasGas, err := gasUsed.Add(payload.BaseGasCount())
 	if err != nil {
-		return &SimulateResult{gasUsed, "GasCountOfTxBase + GasCountOfPayloadBase error", err}, nil
+		return simulated(gasUsed, "GasCountOfTxBase + GasCountOfPayloadBase error", err), nil
 	}
 	gasUsed = payloasGas
+	trace.PayloadBaseGas = payload.BaseGasCount()
+	if gasLimit.Cmp(gasUsed) < 0 {
+		return simulated(gasUsed, "", ErrOutOfGasLimit), nil
+	}
 
 	var (
 		result string
 		exeErr error
 	)
 
-	// try run smart contract if payload is.
-	if tx.data.Type == TxPayloadCallType || tx.data.Type == TxPayloadDeployType {
+	// try run smart contract if payload is, including the accept call of a binary tx to contract.
+	if _, isCall := payload.(*CallPayload); isCall || tx.data.Type == TxPayloadDeployType {
 
 		// transfer value to smart contract.
 		toAcc, err := ws.GetOrCreateUserAccount(tx.to.address)
@@ -556,32 +1038,138 @@ func (tx *Transaction) simulateExecution(block *Block) (*SimulateResult, error)
 		}
 		err = toAcc.AddBalance(tx.value)
 		if err != nil {
-			return &SimulateResult{gasUsed, "Too big value", err}, nil
+			return simulated(gasUsed, "Too big value", err), nil
 		}
 
 		// execute.
+		contractLimitedGas, err := gasLimit.Sub(gasUsed)
+		if err != nil {
+			return simulated(gasUsed, "", ErrOutOfGasLimit), nil
+		}
 		gasExecution := util.NewUint128()
-		gasExecution, result, exeErr = payload.Execute(TransactionMaxGas, tx, block, ws)
+		gasExecution, result, exeErr = payload.Execute(contractLimitedGas, tx, block, ws)
+		trace.ExecutionGas = gasExecution
 
 		// add gas.
 		executedGas, err := gasUsed.Add(gasExecution)
 		if err != nil {
-			return &SimulateResult{gasUsed, "CalFinalGasCount error", err}, nil
+			return simulated(gasUsed, "CalFinalGasCount error", err), nil
 		}
 		gasUsed = executedGas
 
 		if exeErr != nil {
-			return &SimulateResult{gasUsed, result, exeErr}, nil
+			return simulated(gasUsed, result, exeErr), nil
+		}
+		if gasLimit.Cmp(gasUsed) < 0 {
+			return simulated(gasUsed, result, ErrOutOfGasLimit), nil
 		}
 	}
 
-	// check balance.
-	err = checkBalanceForGasUsedAndValue(ws, fromAcc, tx.value, gasUsed, tx.gasPrice)
-	return &SimulateResult{gasUsed, result, err}, nil
+	// check balance, the unknown accounts are not created.
+	fromBalance, _, err := getUserAccountState(ws, tx.from.address)
+	if err != nil {
+		return nil, err
+	}
+	if tx.payer == nil {
+		err = checkBalanceForGasUsedAndValue(fromBalance, tx.value, gasUsed, tx.gasPrice)
+		return simulated(gasUsed, result, err), nil
+	}
+	payerBalance, _, err := getUserAccountState(ws, tx.payer.address)
+	if err != nil {
+		return nil, err
+	}
+	err = checkBalanceForGasUsedAndValue(payerBalance, util.NewUint128(), gasUsed, tx.gasPrice)
+	if err == nil && fromBalance.Cmp(tx.value) < 0 {
+		err = ErrInsufficientBalance
+	}
+	return simulated(gasUsed, result, err), nil
+}
+
+// simulateExecutionInTxWorldState simulate execution in a throwaway world state of block.
+func (tx *Transaction) simulateExecutionInTxWorldState(block *Block, gasLimit *util.Uint128, round int) (*SimulateResult, error) {
+	txWorldState, err := block.WorldState().Prepare(fmt.Sprintf("estimate-gas-%d", round))
+	if err != nil {
+		return nil, err
+	}
+	defer txWorldState.Close()
+
+	return tx.simulateExecutionWithGasLimit(block, txWorldState, gasLimit)
+}
+
+// estimateGas binary search the minimum gas limit with which tx executes successfully,
+// return the limit and the simulated result under it.
+func (tx *Transaction) estimateGas(block *Block) (*util.Uint128, *SimulateResult, error) {
+	round := 0
+	result, err := tx.simulateExecutionInTxWorldState(block, TransactionMaxGas, round)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// binary txs don't depend on the gas limit unless calling accept of contract,
+	// and a tx failed with max gas has nothing to search.
+	if (tx.data.Type == TxPayloadBinaryType && block.Height() < ContractAcceptForkHeight) || result.Err != nil {
+		return result.GasUsed, result, nil
+	}
+
+	// search in (lower, upper], upper always succeeds.
+	upper := TransactionMaxGas
+	baseGas, err := tx.GasCountOfTxBase()
+	if err != nil {
+		return nil, nil, err
+	}
+	lower, err := baseGas.Sub(util.NewUint128FromUint(1))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the gas used with max gas is most likely the answer, try it first.
+	candidate := result.GasUsed
+	for round = 1; round <= EstimateGasMaxIterations; round++ {
+		if candidate.Cmp(lower) <= 0 || candidate.Cmp(upper) >= 0 {
+			break
+		}
+		simulated, err := tx.simulateExecutionInTxWorldState(block, candidate, round)
+		if err != nil {
+			return nil, nil, err
+		}
+		if simulated.Err == nil {
+			upper, result = candidate, simulated
+		} else {
+			lower = candidate
+		}
+
+		// next candidate is the middle of (lower, upper].
+		diff, err := upper.Sub(lower)
+		if err != nil {
+			return nil, nil, err
+		}
+		half, err := diff.Div(util.NewUint128FromUint(2))
+		if err != nil {
+			return nil, nil, err
+		}
+		candidate, err = lower.Add(half)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return upper, result, nil
+}
+
+// getUserAccountState return the balance and nonce of addr without creating its account,
+// which are zero if the account is unknown.
+func getUserAccountState(ws WorldState, addr byteutils.Hash) (*util.Uint128, uint64, error) {
+	acc, err := ws.GetUserAccount(addr)
+	if err == state.ErrAccountNotFound {
+		return util.NewUint128(), 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	return acc.Balance(), acc.Nonce(), nil
 }
 
 // checkBalanceForGasUsedAndValue check balance >= gasUsed * gasPrice + value.
-func checkBalanceForGasUsedAndValue(ws WorldState, fromAcc state.Account, value, gasUsed, gasPrice *util.Uint128) error {
+func checkBalanceForGasUsedAndValue(balance, value, gasUsed, gasPrice *util.Uint128) error {
 	gasFee, err := gasPrice.Mul(gasUsed)
 	if err != nil {
 		return err
@@ -590,23 +1178,53 @@ func checkBalanceForGasUsedAndValue(ws WorldState, fromAcc state.Account, value,
 	if err != nil {
 		return err
 	}
-	if fromAcc.Balance().Cmp(balanceRequired) < 0 {
+	if balance.Cmp(balanceRequired) < 0 {
 		return ErrInsufficientBalance
 	}
 	return nil
 
 }
 
+// settleGasFee charge the gas fee from fee payer after the gas refund fork.
+// If limited fee was reserved, refund (gasLimit - gas) * gasPrice, else charge gas * gasPrice.
+func (tx *Transaction) settleGasFee(block *Block, gas *util.Uint128, reserved bool, ws WorldState) error {
+	if block.Height() < GasRefundForkHeight {
+		return nil
+	}
+
+	payerAcc, err := ws.GetOrCreateUserAccount(tx.feePayer().address)
+	if err != nil {
+		return err
+	}
+	if !reserved {
+		gasFee, err := tx.gasPrice.Mul(gas)
+		if err != nil {
+			return err
+		}
+		return payerAcc.SubBalance(gasFee)
+	}
+
+	unusedGas, err := tx.gasLimit.Sub(gas)
+	if err != nil {
+		return err
+	}
+	refund, err := tx.gasPrice.Mul(unusedGas)
+	if err != nil {
+		return err
+	}
+	return payerAcc.AddBalance(refund)
+}
+
 func (tx *Transaction) recordGas(gasCnt *util.Uint128, ws WorldState) error {
 	gasCost, err := tx.GasPrice().Mul(gasCnt)
 	if err != nil {
 		return err
 	}
 
-	return ws.RecordGas(tx.from.String(), gasCost)
+	return ws.RecordGas(tx.feePayer().String(), gasCost)
 }
 
-func (tx *Transaction) recordResultEvent(gasUsed *util.Uint128, err error, ws WorldState) error {
+func (tx *Transaction) recordResultEvent(block *Block, gasUsed *util.Uint128, err error, ws WorldState) error {
 	txEvent := &TransactionEvent{
 		Hash:    tx.hash.String(),
 		GasUsed: gasUsed.String(),
@@ -619,6 +1237,23 @@ func (tx *Transaction) recordResultEvent(gasUsed *util.Uint128, err error, ws Wo
 		if len(txEvent.Error) > MaxEventErrLength {
 			txEvent.Error = txEvent.Error[:MaxEventErrLength]
 		}
+		if block.Height() >= TxErrorCodeForkHeight {
+			txEvent.ErrorCode = uint32(TxErrorCodeOf(err))
+		}
+		if block.Height() >= RevertReasonForkHeight {
+			txEvent.RevertReason = revertReasonOf(err)
+		}
+	} else if tx.Type() == TxPayloadDeployType {
+		contractAddress, err := tx.ContractAddressAtHeight(block.Height())
+		if err != nil {
+			return err
+		}
+		txEvent.ContractAddress = contractAddress.String()
+
+		// the result event must be the last one, contract birth place check depends on it.
+		if err := tx.recordContractDeployEvent(contractAddress, ws); err != nil {
+			return err
+		}
 	}
 
 	txData, err := json.Marshal(txEvent)
@@ -630,6 +1265,34 @@ func (tx *Transaction) recordResultEvent(gasUsed *util.Uint128, err error, ws Wo
 		Topic: TopicTransactionExecutionResult,
 		Data:  string(txData),
 	}
+	if block.Height() >= ExecutionResultForkHeight {
+		ws.RecordExecutionResult(tx.hash, event)
+	} else {
+		ws.RecordEvent(tx.hash, event)
+	}
+	return nil
+}
+
+func (tx *Transaction) recordContractDeployEvent(contractAddress *Address, ws WorldState) error {
+	payload, err := LoadDeployPayload(tx.data.Payload)
+	if err != nil {
+		return err
+	}
+
+	deployEvent := &ContractDeployEvent{
+		ContractAddress: contractAddress.String(),
+		Deployer:        tx.from.String(),
+		SourceHash:      payload.SourceHash().String(),
+	}
+	eventData, err := json.Marshal(deployEvent)
+	if err != nil {
+		return err
+	}
+
+	event := &state.Event{
+		Topic: TopicContractDeploy,
+		Data:  string(eventData),
+	}
 	ws.RecordEvent(tx.hash, event)
 	return nil
 }
@@ -650,6 +1313,56 @@ func (tx *Transaction) Sign(signature keystore.Signature) error {
 	tx.hash = hash
 	tx.alg = signature.Algorithm()
 	tx.sign = sign
+	tx.size = 0
+	return nil
+}
+
+// SetSignature attach the signature computed by an external signer over the hash of HashPreimage,
+// the hash must match tx's content and the signature must recover to tx.from.
+func (tx *Transaction) SetSignature(alg keystore.Algorithm, hash, sign byteutils.Hash) error {
+	wantedHash, err := tx.calHash()
+	if err != nil {
+		return err
+	}
+	if !wantedHash.Equals(hash) {
+		return ErrInvalidTransactionHash
+	}
+	signer, err := RecoverSignerFromSignature(alg, hash, sign)
+	if err != nil {
+		return err
+	}
+	if !tx.from.Equals(signer) {
+		return ErrInvalidTransactionSigner
+	}
+	tx.hash = hash
+	tx.alg = alg
+	tx.sign = sign
+	tx.size = 0
+	return nil
+}
+
+// SignAsPayer co-sign transaction as the fee payer, the algorithm must be the same as sender's.
+func (tx *Transaction) SignAsPayer(signature keystore.Signature) error {
+	if signature == nil {
+		return ErrNilArgument
+	}
+	if tx.payer == nil {
+		return ErrTransactionWithoutPayer
+	}
+	if signature.Algorithm() != tx.alg {
+		return ErrInvalidTransactionAlg
+	}
+	hash, err := tx.calHash()
+	if err != nil {
+		return err
+	}
+	sign, err := signature.Sign(hash)
+	if err != nil {
+		return err
+	}
+	tx.hash = hash
+	tx.payerSign = sign
+	tx.size = 0
 	return nil
 }
 
@@ -669,6 +1382,11 @@ func (tx *Transaction) VerifyIntegrity(chainID uint32) error {
 		return ErrInvalidTransactionHash
 	}
 
+	// check Signature Algorithm, ahead of recovering the signer.
+	if err := crypto.CheckAlgorithm(tx.alg); err != nil {
+		return ErrInvalidTransactionAlg
+	}
+
 	// check Signature.
 	return tx.verifySign()
 
@@ -686,6 +1404,27 @@ func (tx *Transaction) verifySign() error {
 		}).Debug("Failed to verify tx's sign.")
 		return ErrInvalidTransactionSigner
 	}
+
+	if tx.payer == nil {
+		if len(tx.payerSign) > 0 {
+			return ErrInvalidTransactionPayerSigner
+		}
+		return nil
+	}
+	if len(tx.payerSign) == 0 {
+		return ErrInvalidTransactionPayerSigner
+	}
+	payer, err := RecoverSignerFromSignature(tx.alg, tx.hash, tx.payerSign)
+	if err != nil {
+		return err
+	}
+	if !tx.payer.Equals(payer) {
+		logging.VLog().WithFields(logrus.Fields{
+			"signer":   payer.String(),
+			"tx.payer": tx.payer,
+		}).Debug("Failed to verify tx's payer sign.")
+		return ErrInvalidTransactionPayerSigner
+	}
 	return nil
 }
 
@@ -697,6 +1436,35 @@ func (tx *Transaction) GenerateContractAddress() (*Address, error) {
 	return NewContractAddressFromData(tx.from.Bytes(), byteutils.FromUint64(tx.nonce))
 }
 
+// GenerateContractAddressWithSalt according to tx.chainID, tx.from, tx.nonce, and the salt & source of deploy payload.
+func (tx *Transaction) GenerateContractAddressWithSalt() (*Address, error) {
+	if TxPayloadDeployType != tx.Type() {
+		return nil, errors.New("playload type err")
+	}
+	payload, err := LoadDeployPayload(tx.data.Payload)
+	if err != nil {
+		return nil, err
+	}
+	if len(payload.Salt) == 0 {
+		return nil, ErrInvalidDeploySalt
+	}
+	return NewContractAddressWithSalt(tx.chainID, tx.from.Bytes(), byteutils.FromUint64(tx.nonce), []byte(payload.Salt), []byte(payload.Source))
+}
+
+// ContractAddressAtHeight return the address of contract deployed by tx at height,
+// the salted address after DeploySaltForkHeight if the payload has a salt, else the legacy one.
+func (tx *Transaction) ContractAddressAtHeight(height uint64) (*Address, error) {
+	if TxPayloadDeployType != tx.Type() {
+		return nil, errors.New("playload type err")
+	}
+	if height >= DeploySaltForkHeight {
+		if payload, err := LoadDeployPayload(tx.data.Payload); err == nil && len(payload.Salt) > 0 {
+			return tx.GenerateContractAddressWithSalt()
+		}
+	}
+	return tx.GenerateContractAddress()
+}
+
 // CheckContract check if contract is valid
 func CheckContract(addr *Address, ws WorldState) (state.Account, error) {
 	if addr == nil || ws == nil {
@@ -708,39 +1476,115 @@ func CheckContract(addr *Address, ws WorldState) (state.Account, error) {
 		return nil, err
 	}
 
-	birthEvents, err := ws.FetchEvents(contract.BirthPlace())
+	event, err := FetchExecutionResultEvent(contract.BirthPlace(), ws)
+	if err == ErrNotFoundTransactionResultEvent || err == ErrInvalidTransactionResultEvent {
+		return nil, ErrContractCheckFailed
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	result := false
-	if birthEvents != nil && len(birthEvents) > 0 {
-		event := birthEvents[len(birthEvents)-1]
-		if event.Topic == TopicTransactionExecutionResult {
-			txEvent := TransactionEvent{}
-			if err := json.Unmarshal([]byte(event.Data), &txEvent); err != nil {
-				return nil, err
-			}
-			if txEvent.Status == TxExecutionSuccess {
-				result = true
-			}
-		}
+	txEvent := TransactionEvent{}
+	if err := json.Unmarshal([]byte(event.Data), &txEvent); err != nil {
+		return nil, err
 	}
-	if !result {
+	if txEvent.Status != TxExecutionSuccess {
 		return nil, ErrContractCheckFailed
 	}
 
+	destroyed, err := isContractDestroyed(contract)
+	if err != nil {
+		return nil, err
+	}
+	if destroyed {
+		return nil, ErrContractDestroyed
+	}
+
 	return contract, nil
 }
 
-// CheckTransaction in a tx world state
-func CheckTransaction(tx *Transaction, ws WorldState) (bool, error) {
-	// check nonce
+// FetchExecutionResultEvent fetch the execution result event of tx in ws,
+// it's the last event of tx before ExecutionResultForkHeight.
+func FetchExecutionResultEvent(txHash byteutils.Hash, ws WorldState) (*state.Event, error) {
+	event, err := ws.FetchExecutionResult(txHash)
+	if err == nil {
+		return event, nil
+	}
+	if !isTrieKeyNotFound(err) {
+		return nil, err
+	}
+
+	events, err := ws.FetchEvents(txHash)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, ErrNotFoundTransactionResultEvent
+	}
+	if event := events[len(events)-1]; event.Topic == TopicTransactionExecutionResult {
+		return event, nil
+	}
+	logging.VLog().WithFields(logrus.Fields{
+		"tx":     txHash,
+		"events": events,
+	}).Debug("Failed to locate the result event")
+	return nil, ErrInvalidTransactionResultEvent
+}
+
+// CheckTransaction in a tx world state, the error is a *TxError.
+func CheckTransaction(tx *Transaction, block *Block, ws WorldState) (bool, error) {
+	giveback, err := checkTransaction(tx, block, ws)
+	return giveback, newTxError(err, giveback, false)
+}
+
+func checkTransaction(tx *Transaction, block *Block, ws WorldState) (bool, error) {
+	// check expiredAt
+	if tx.expiredAt != 0 && block.Height() < ExpiredAtForkHeight {
+		// ExpiredAt is not activated, won't giveback the tx
+		return false, ErrTxExpiredAtNotActivated
+	}
+
+	// check expiration
+	if tx.IsExpired(block.Timestamp()) {
+		// Tx is expired, won't giveback the tx
+		return false, ErrTransactionExpired
+	}
+
+	// check timestamp
+	if block.Height() >= TxTimestampForkHeight && tx.timestamp > block.Timestamp()+MaxTxTimestampAheadOfBlock {
+		// Tx is from the future, won't giveback the tx
+		return false, ErrTxTimestampAheadOfBlock
+	}
+
+	// check payer
+	if tx.payer != nil && block.Height() < TxPayerForkHeight {
+		// Payer is not activated, won't giveback the tx
+		return false, ErrTxPayerNotActivated
+	}
+
+	// check signature algorithm
+	if tx.alg == keystore.ED25519 && block.Height() < Ed25519ForkHeight {
+		// Ed25519 is not activated, won't giveback the tx
+		return false, ErrTxAlgNotActivated
+	}
+
 	fromAcc, err := ws.GetOrCreateUserAccount(tx.from.address)
 	if err != nil {
 		return true, err
 	}
 
+	// check frozen
+	frozen, err := isAccountFrozen(fromAcc, block)
+	if err != nil {
+		return true, err
+	}
+	if frozen {
+		// From is frozen, won't giveback the tx
+		return false, ErrAccountFrozen
+	}
+
+	// check nonce
+
 	// pass current Nonce.
 	currentNonce := fromAcc.Nonce()
 
@@ -754,14 +1598,23 @@ func CheckTransaction(tx *Transaction, ws WorldState) (bool, error) {
 	return false, nil
 }
 
-// AcceptTransaction in a tx world state
+// AcceptTransaction in a tx world state, the error is a *TxError.
 func AcceptTransaction(tx *Transaction, ws WorldState) (bool, error) {
-	// record tx
-	pbTx, err := tx.ToProto()
-	if err != nil {
+	giveback, err := acceptTransaction(tx, ws)
+	return giveback, newTxError(err, giveback, false)
+}
+
+func acceptTransaction(tx *Transaction, ws WorldState) (bool, error) {
+	// a tx can be recorded only once, even if it's replayed.
+	if _, err := ws.GetTx(tx.hash); err == nil {
+		// Tx is on chain, won't giveback the tx
+		return false, ErrDuplicatedTransaction
+	} else if !isTrieKeyNotFound(err) {
 		return true, err
 	}
-	txBytes, err := proto.Marshal(pbTx)
+
+	// record tx
+	txBytes, err := tx.ToBytes()
 	if err != nil {
 		return true, err
 	}
@@ -787,12 +1640,8 @@ func GetTransaction(hash byteutils.Hash, ws WorldState) (*Transaction, error) {
 	if err != nil {
 		return nil, err
 	}
-	pbTx := new(corepb.Transaction)
-	if err := proto.Unmarshal(bytes, pbTx); err != nil {
-		return nil, err
-	}
 	tx := new(Transaction)
-	if err = tx.FromProto(pbTx); err != nil {
+	if err = tx.FromBytes(bytes); err != nil {
 		return nil, err
 	}
 	return tx, nil
@@ -800,8 +1649,18 @@ func GetTransaction(hash byteutils.Hash, ws WorldState) (*Transaction, error) {
 
 // HashTransaction hash the transaction.
 func (tx *Transaction) calHash() (byteutils.Hash, error) {
+	preimage, err := tx.HashPreimage()
+	if err != nil {
+		return nil, err
+	}
+
 	hasher := sha3.New256()
+	hasher.Write(preimage)
+	return hasher.Sum(nil), nil
+}
 
+// HashPreimage return the bytes hashed into the tx hash by sha3-256, for the external signers.
+func (tx *Transaction) HashPreimage() ([]byte, error) {
 	value, err := tx.value.ToFixedSizeByteSlice()
 	if err != nil {
 		return nil, err
@@ -819,15 +1678,23 @@ func (tx *Transaction) calHash() (byteutils.Hash, error) {
 		return nil, err
 	}
 
-	hasher.Write(tx.from.address)
-	hasher.Write(tx.to.address)
-	hasher.Write(value)
-	hasher.Write(byteutils.FromUint64(tx.nonce))
-	hasher.Write(byteutils.FromInt64(tx.timestamp))
-	hasher.Write(data)
-	hasher.Write(byteutils.FromUint32(tx.chainID))
-	hasher.Write(gasPrice)
-	hasher.Write(gasLimit)
-
-	return hasher.Sum(nil), nil
+	var preimage []byte
+	preimage = append(preimage, tx.from.address...)
+	preimage = append(preimage, tx.to.address...)
+	preimage = append(preimage, value...)
+	preimage = append(preimage, byteutils.FromUint64(tx.nonce)...)
+	preimage = append(preimage, byteutils.FromInt64(tx.timestamp)...)
+	preimage = append(preimage, data...)
+	preimage = append(preimage, byteutils.FromUint32(tx.chainID)...)
+	preimage = append(preimage, gasPrice...)
+	preimage = append(preimage, gasLimit...)
+	// txs without expiration hash as before
+	if tx.expiredAt != 0 {
+		preimage = append(preimage, byteutils.FromInt64(tx.expiredAt)...)
+	}
+	// txs without payer hash as before
+	if tx.payer != nil {
+		preimage = append(preimage, tx.payer.address...)
+	}
+	return preimage, nil
 }
@@ -0,0 +1,194 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"encoding/json"
+	"math"
+
+	"github.com/alexlisong/go-nebulas/core/state"
+	"github.com/alexlisong/go-nebulas/util"
+)
+
+const (
+	// MaxBatchEntries max count of transfers in a batch payload
+	MaxBatchEntries = 200
+)
+
+var (
+	// BatchForkHeight batch payload is activated from this height, disabled by default.
+	BatchForkHeight uint64 = math.MaxUint64
+
+	// BatchEntryGasCount base gas count of each transfer in batch payload.
+	BatchEntryGasCount, _ = util.NewUint128FromInt(1000)
+)
+
+// BatchEntry is a transfer in batch payload.
+type BatchEntry struct {
+	To    string `json:"to"`
+	Value string `json:"value"`
+}
+
+// BatchPayload carry the transfers sent atomically from the sender.
+// The tx is sent to the sender itself, all transfers succeed or none.
+type BatchPayload struct {
+	Entries []*BatchEntry
+
+	to     []*Address
+	values []*util.Uint128
+}
+
+// BatchTransferEvent is recorded for each recipient of a batch tx.
+type BatchTransferEvent struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Value string `json:"value"`
+}
+
+// LoadBatchPayload from bytes
+func LoadBatchPayload(bytes []byte) (*BatchPayload, error) {
+	var entries []*BatchEntry
+	if err := json.Unmarshal(bytes, &entries); err != nil {
+		return nil, ErrInvalidArgument
+	}
+	return NewBatchPayload(entries)
+}
+
+// NewBatchPayload with entries, the recipients must be user accounts.
+func NewBatchPayload(entries []*BatchEntry) (*BatchPayload, error) {
+	if len(entries) == 0 || len(entries) > MaxBatchEntries {
+		return nil, ErrInvalidBatchEntries
+	}
+
+	payload := &BatchPayload{
+		Entries: entries,
+		to:      make([]*Address, len(entries)),
+		values:  make([]*util.Uint128, len(entries)),
+	}
+	for i, entry := range entries {
+		if entry == nil {
+			return nil, ErrInvalidBatchEntries
+		}
+		to, err := AddressParse(entry.To)
+		if err != nil {
+			return nil, err
+		}
+		if to.Type() != AccountAddress {
+			return nil, ErrInvalidBatchRecipient
+		}
+		value, err := util.NewUint128FromString(entry.Value)
+		if err != nil {
+			return nil, ErrInvalidBatchEntries
+		}
+		payload.to[i] = to
+		payload.values[i] = value
+	}
+	return payload, nil
+}
+
+// ToBytes serialize payload
+func (payload *BatchPayload) ToBytes() ([]byte, error) {
+	return json.Marshal(payload.Entries)
+}
+
+// BaseGasCount returns base gas count
+func (payload *BatchPayload) BaseGasCount() *util.Uint128 {
+	base, _ := BatchEntryGasCount.Mul(util.NewUint128FromUint(uint64(len(payload.Entries))))
+	return base
+}
+
+// Recipients return the recipients of transfers.
+func (payload *BatchPayload) Recipients() []*Address {
+	return payload.to
+}
+
+// TotalValue return the sum of transfers' value.
+func (payload *BatchPayload) TotalValue() (*util.Uint128, error) {
+	total := util.NewUint128()
+	for _, value := range payload.values {
+		sum, err := total.Add(value)
+		if err != nil {
+			return nil, ErrInvalidTransfer
+		}
+		total = sum
+	}
+	return total, nil
+}
+
+// Execute the transfers in batch payload. If any transfer fails,
+// the error resets the world state of tx, so the transfers are atomic.
+func (payload *BatchPayload) Execute(limitedGas *util.Uint128, tx *Transaction, block *Block, ws WorldState) (*util.Uint128, string, error) {
+	if block == nil || tx == nil {
+		return util.NewUint128(), "", ErrNilArgument
+	}
+	if block.Height() < BatchForkHeight {
+		return util.NewUint128(), "", ErrInvalidTxPayloadType
+	}
+	if !tx.From().Equals(tx.To()) {
+		return util.NewUint128(), "", ErrBatchTransactionAddressNotEqual
+	}
+
+	total, err := payload.TotalValue()
+	if err != nil {
+		return util.NewUint128(), "", err
+	}
+	// the gas fee is charged from sender after execution before the gas refund fork.
+	if block.Height() < GasRefundForkHeight && tx.payer == nil {
+		limitedFee, err := tx.Fee()
+		if err != nil {
+			return util.NewUint128(), "", ErrGasFeeOverflow
+		}
+		if total, err = total.Add(limitedFee); err != nil {
+			return util.NewUint128(), "", ErrInvalidTransfer
+		}
+	}
+	fromAcc, err := ws.GetOrCreateUserAccount(tx.from.address)
+	if err != nil {
+		return util.NewUint128(), "", err
+	}
+	if fromAcc.Balance().Cmp(total) < 0 {
+		return util.NewUint128(), "", ErrInsufficientBalance
+	}
+
+	for i, to := range payload.to {
+		if _, err := transfer(tx.from.address, to.address, payload.values[i], ws); err != nil {
+			return util.NewUint128(), "", err
+		}
+		if err := recordBatchTransferEvent(tx, to, payload.values[i], ws); err != nil {
+			return util.NewUint128(), "", err
+		}
+	}
+	return util.NewUint128(), "", nil
+}
+
+func recordBatchTransferEvent(tx *Transaction, to *Address, value *util.Uint128, ws WorldState) error {
+	data, err := json.Marshal(&BatchTransferEvent{
+		From:  tx.from.String(),
+		To:    to.String(),
+		Value: value.String(),
+	})
+	if err != nil {
+		return err
+	}
+	ws.RecordEvent(tx.hash, &state.Event{
+		Topic: TopicBatchTransfer,
+		Data:  string(data),
+	})
+	return nil
+}
@@ -19,9 +19,20 @@
 package core
 
 import (
+	"math"
+
 	"github.com/alexlisong/go-nebulas/util"
 )
 
+// ContractAcceptFunction is called when a binary tx transfers value to a contract.
+const ContractAcceptFunction = "accept"
+
+var (
+	// ContractAcceptForkHeight from this height, a binary tx to a contract is executed as a call to
+	// the contract's accept function, the contracts without it reject plain transfers. Disabled by default.
+	ContractAcceptForkHeight uint64 = math.MaxUint64
+)
+
 // BinaryPayload carry some data
 type BinaryPayload struct {
 	Data []byte
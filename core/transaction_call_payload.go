@@ -20,9 +20,11 @@ package core
 
 import (
 	"encoding/json"
-	"fmt"
 
 	"github.com/alexlisong/go-nebulas/util"
+	"github.com/alexlisong/go-nebulas/util/byteutils"
+	"github.com/alexlisong/go-nebulas/util/logging"
+	"github.com/sirupsen/logrus"
 )
 
 // CallPayload carry function call information
@@ -85,20 +87,29 @@ func (payload *CallPayload) Execute(limitedGas *util.Uint128, tx *Transaction, b
 		return util.NewUint128(), "", err
 	}
 
-	birthTx, err := GetTransaction(contract.BirthPlace(), ws)
-	if err != nil {
-		return util.NewUint128(), "", err
+	// the code of an upgraded contract is in its latest upgrade tx.
+	var version uint64
+	if block.Height() >= ContractUpgradeForkHeight {
+		if version, err = contractVersion(contract); err != nil {
+			return util.NewUint128(), "", err
+		}
 	}
-	/* // useless owner.
-	owner, err := ws.GetOrCreateUserAccount(birthTx.from.Bytes())
+	code, err := contractSource(contract, version, ws)
 	if err != nil {
 		return util.NewUint128(), "", err
-	} */
-	deploy, err := LoadDeployPayload(birthTx.data.Payload) // ToConfirm: move deploy payload in ctx.
+	}
+
+	runtime, err := contractRuntime(contract, block)
 	if err != nil {
 		return util.NewUint128(), "", err
 	}
 
+	// the storage put by the engine is charged after the fork.
+	meter := newStorageMeter(contract, block)
+	if meter != nil {
+		contract = meter
+	}
+
 	engine, err := block.nvm.CreateEngine(block, tx, contract, ws)
 	if err != nil {
 		return util.NewUint128(), "", err
@@ -108,15 +119,90 @@ func (payload *CallPayload) Execute(limitedGas *util.Uint128, tx *Transaction, b
 	if err := engine.SetExecutionLimits(limitedGas.Uint64(), DefaultLimitsOfTotalMemorySize); err != nil {
 		return util.NewUint128(), "", err
 	}
+	if err := setEngineRuntime(engine, runtime); err != nil {
+		return util.NewUint128(), "", err
+	}
 
-	result, exeErr := engine.Call(deploy.Source, deploy.SourceType, payload.Function, payload.Args)
+	result, exeErr := engine.Call(code.Source, code.SourceType, payload.Function, payload.Args)
 	gasCout := engine.ExecutionInstructions()
 	instructions, err := util.NewUint128FromInt(int64(gasCout))
 	if err != nil {
 		return util.NewUint128(), "", err
 	}
-	if exeErr != nil && exeErr == ErrExecutionFailed && len(result) > 0 {
-		exeErr = fmt.Errorf("Call: %s", result)
+	instructions, exeErr = meter.settle(instructions, exeErr)
+	return newExecutionResult("Call", instructions, result, exeErr).Unpack()
+}
+
+// CallContract call the function of contract read-only in a cloned world state of block,
+// from needs neither balance nor a valid nonce, nil for the contract itself. Return the result and gas used.
+func CallContract(block *Block, from, contract *Address, function string, args []byte) (string, *util.Uint128, error) {
+	if block == nil || contract == nil {
+		return "", nil, ErrNilArgument
+	}
+	if from == nil {
+		from = contract
+	}
+
+	payload, err := NewCallPayload(function, string(args))
+	if err != nil {
+		return "", nil, err
+	}
+	data, err := payload.ToBytes()
+	if err != nil {
+		return "", nil, err
+	}
+
+	sandbox, err := block.WorldState().Clone()
+	if err != nil {
+		return "", nil, err
+	}
+	if err := sandbox.Begin(); err != nil {
+		return "", nil, err
+	}
+	defer sandbox.RollBack()
+
+	if _, err := CheckContract(contract, sandbox); err != nil {
+		return "", nil, err
+	}
+
+	// a synthetic tx with the next nonce of from, it's never signed nor recorded.
+	fromAcc, err := sandbox.GetOrCreateUserAccount(from.address)
+	if err != nil {
+		return "", nil, err
+	}
+	tx, err := NewTransaction(block.ChainID(), from, contract, util.NewUint128(), fromAcc.Nonce()+1, TxPayloadCallType, data, TransactionGasPrice, TransactionMaxGas)
+	if err != nil {
+		return "", nil, err
+	}
+	if tx.hash, err = tx.calHash(); err != nil {
+		return "", nil, err
+	}
+
+	gasUsed, err := tx.GasCountOfTxBase()
+	if err != nil {
+		return "", nil, err
+	}
+	if gasUsed, err = gasUsed.Add(payload.BaseGasCount()); err != nil {
+		return "", nil, err
+	}
+	limitedGas, err := tx.gasLimit.Sub(gasUsed)
+	if err != nil {
+		return "", nil, ErrOutOfGasLimit
+	}
+
+	accountsRoot := block.WorldState().AccountsRoot()
+	gasExecution, result, exeErr := payload.Execute(limitedGas, tx, block, sandbox)
+	if !byteutils.Equal(accountsRoot, block.WorldState().AccountsRoot()) {
+		logging.VLog().WithFields(logrus.Fields{
+			"block":    block,
+			"contract": contract,
+			"function": function,
+		}).Error("Read-only contract call mutated the world state.")
+		return "", nil, ErrContractCallMutatedState
+	}
+
+	if gasUsed, err = gasUsed.Add(gasExecution); err != nil {
+		return "", nil, err
 	}
-	return instructions, result, exeErr
+	return result, gasUsed, exeErr
 }
@@ -20,9 +20,22 @@ package core
 
 import (
 	"encoding/json"
-	"fmt"
+	"math"
 
+	"github.com/alexlisong/go-nebulas/crypto/hash"
 	"github.com/alexlisong/go-nebulas/util"
+	"github.com/alexlisong/go-nebulas/util/byteutils"
+)
+
+const (
+	// MaxDeploySaltLength max length of salt in deploy payload
+	MaxDeploySaltLength = 64
+)
+
+var (
+	// DeploySaltForkHeight from this height, the contract deployed with a salt is at the address
+	// derived from chainID, from, nonce, salt and source, disabled by default.
+	DeploySaltForkHeight uint64 = math.MaxUint64
 )
 
 // DeployPayload carry contract deploy information
@@ -30,6 +43,17 @@ type DeployPayload struct {
 	SourceType string
 	Source     string
 	Args       string
+	Salt       string `json:",omitempty"`
+
+	// the runtime lib version the contract runs with, the node's default if empty.
+	RuntimeVersion string `json:",omitempty"`
+}
+
+// ContractDeployEvent event of a contract deployed by tx
+type ContractDeployEvent struct {
+	ContractAddress string `json:"contract_address"`
+	Deployer        string `json:"deployer"`
+	SourceHash      string `json:"source_hash"`
 }
 
 // CheckContractArgs check contract args
@@ -49,11 +73,21 @@ func LoadDeployPayload(bytes []byte) (*DeployPayload, error) {
 	if err := json.Unmarshal(bytes, payload); err != nil {
 		return nil, ErrInvalidArgument
 	}
-	return NewDeployPayload(payload.Source, payload.SourceType, payload.Args)
+	deploy, err := NewDeployPayloadWithSalt(payload.Source, payload.SourceType, payload.Args, payload.Salt)
+	if err != nil {
+		return nil, err
+	}
+	deploy.RuntimeVersion = payload.RuntimeVersion
+	return deploy, nil
 }
 
 // NewDeployPayload with source & args
 func NewDeployPayload(source, sourceType, args string) (*DeployPayload, error) {
+	return NewDeployPayloadWithSalt(source, sourceType, args, "")
+}
+
+// NewDeployPayloadWithSalt with source, args & salt, empty salt for the legacy contract address
+func NewDeployPayloadWithSalt(source, sourceType, args, salt string) (*DeployPayload, error) {
 	if len(source) == 0 {
 		return nil, ErrInvalidDeploySource
 	}
@@ -66,10 +100,15 @@ func NewDeployPayload(source, sourceType, args string) (*DeployPayload, error) {
 		return nil, ErrInvalidArgument
 	}
 
+	if len(salt) > MaxDeploySaltLength {
+		return nil, ErrInvalidDeploySalt
+	}
+
 	return &DeployPayload{
 		Source:     source,
 		SourceType: sourceType,
 		Args:       args,
+		Salt:       salt,
 	}, nil
 }
 
@@ -78,6 +117,11 @@ func (payload *DeployPayload) ToBytes() ([]byte, error) {
 	return json.Marshal(payload)
 }
 
+// SourceHash returns the sha3 hash of contract source
+func (payload *DeployPayload) SourceHash() byteutils.Hash {
+	return hash.Sha3256([]byte(payload.Source))
+}
+
 // BaseGasCount returns base gas count
 func (payload *DeployPayload) BaseGasCount() *util.Uint128 {
 	base, _ := util.NewUint128FromInt(60)
@@ -99,7 +143,7 @@ func (payload *DeployPayload) Execute(limitedGas *util.Uint128, tx *Transaction,
 		return util.NewUint128(), "", ErrOutOfGasLimit
 	}
 
-	addr, err := tx.GenerateContractAddress()
+	addr, err := tx.ContractAddressAtHeight(block.Height())
 	if err != nil {
 		return util.NewUint128(), "", err
 	}
@@ -113,6 +157,17 @@ func (payload *DeployPayload) Execute(limitedGas *util.Uint128, tx *Transaction,
 		return util.NewUint128(), "", err
 	}
 
+	runtime, err := pinContractRuntime(contract, payload, block)
+	if err != nil {
+		return util.NewUint128(), "", err
+	}
+
+	// the storage put by the engine is charged after the fork.
+	meter := newStorageMeter(contract, block)
+	if meter != nil {
+		contract = meter
+	}
+
 	engine, err := block.nvm.CreateEngine(block, tx, contract, ws)
 	if err != nil {
 		return util.NewUint128(), "", err
@@ -122,6 +177,9 @@ func (payload *DeployPayload) Execute(limitedGas *util.Uint128, tx *Transaction,
 	if err := engine.SetExecutionLimits(limitedGas.Uint64(), DefaultLimitsOfTotalMemorySize); err != nil {
 		return util.NewUint128(), "", err
 	}
+	if err := setEngineRuntime(engine, runtime); err != nil {
+		return util.NewUint128(), "", err
+	}
 
 	// Deploy and Init.
 	result, exeErr := engine.DeployAndInit(payload.Source, payload.SourceType, payload.Args)
@@ -130,8 +188,6 @@ func (payload *DeployPayload) Execute(limitedGas *util.Uint128, tx *Transaction,
 	if err != nil {
 		return util.NewUint128(), "", err
 	}
-	if exeErr != nil && exeErr == ErrExecutionFailed && len(result) > 0 {
-		exeErr = fmt.Errorf("Deploy: %s", result)
-	}
-	return instructions, result, exeErr
+	instructions, exeErr = meter.settle(instructions, exeErr)
+	return newExecutionResult("Deploy", instructions, result, exeErr).Unpack()
 }
@@ -0,0 +1,164 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"math"
+)
+
+// TxErrorCode is the stable code of a tx error, the codes are on chain and must never be renumbered.
+type TxErrorCode uint32
+
+// TxErrorCodes
+const (
+	TxErrorCodeNone    TxErrorCode = 0
+	TxErrorCodeUnknown TxErrorCode = 1
+
+	// errors of tx check & verification.
+	TxErrorCodeTransactionExpired    TxErrorCode = 100
+	TxErrorCodeTimestampAheadOfBlock TxErrorCode = 101
+	TxErrorCodePayerNotActivated     TxErrorCode = 102
+	TxErrorCodeSmallNonce            TxErrorCode = 103
+	TxErrorCodeLargeNonce            TxErrorCode = 104
+	TxErrorCodeAccountFrozen         TxErrorCode = 105
+	TxErrorCodeDuplicatedTransaction TxErrorCode = 106
+	TxErrorCodeGasFeeOverflow        TxErrorCode = 107
+	TxErrorCodeInsufficientBalance   TxErrorCode = 108
+	TxErrorCodeGasCntOverflow        TxErrorCode = 109
+	TxErrorCodeOutOfGasLimit         TxErrorCode = 110
+	TxErrorCodeInvalidTransfer       TxErrorCode = 111
+	TxErrorCodeInvalidTxPayloadType  TxErrorCode = 112
+	TxErrorCodeAlgNotActivated       TxErrorCode = 113
+	TxErrorCodeExpiredAtNotActivated TxErrorCode = 114
+
+	// errors of payload execution without a code of its own, the gas is charged.
+	TxErrorCodeExecutionFailed TxErrorCode = 200
+)
+
+var (
+	// TxErrorCodeForkHeight the error code is recorded in tx result event from this height, disabled by default.
+	TxErrorCodeForkHeight uint64 = math.MaxUint64
+
+	txErrorCodes = map[error]TxErrorCode{
+		ErrTransactionExpired:      TxErrorCodeTransactionExpired,
+		ErrTxTimestampAheadOfBlock: TxErrorCodeTimestampAheadOfBlock,
+		ErrTxPayerNotActivated:     TxErrorCodePayerNotActivated,
+		ErrSmallTransactionNonce:   TxErrorCodeSmallNonce,
+		ErrLargeTransactionNonce:   TxErrorCodeLargeNonce,
+		ErrAccountFrozen:           TxErrorCodeAccountFrozen,
+		ErrDuplicatedTransaction:   TxErrorCodeDuplicatedTransaction,
+		ErrGasFeeOverflow:          TxErrorCodeGasFeeOverflow,
+		ErrInsufficientBalance:     TxErrorCodeInsufficientBalance,
+		ErrGasCntOverflow:          TxErrorCodeGasCntOverflow,
+		ErrOutOfGasLimit:           TxErrorCodeOutOfGasLimit,
+		ErrInvalidTransfer:         TxErrorCodeInvalidTransfer,
+		ErrInvalidTxPayloadType:    TxErrorCodeInvalidTxPayloadType,
+		ErrTxAlgNotActivated:       TxErrorCodeAlgNotActivated,
+		ErrTxExpiredAtNotActivated: TxErrorCodeExpiredAtNotActivated,
+		ErrExecutionFailed:         TxErrorCodeExecutionFailed,
+	}
+)
+
+// TxError is the error of tx check & execution with its code,
+// the giveback decision and whether the gas is charged.
+// It wraps the original error, ErrorCause returns the sentinel errors.
+type TxError struct {
+	Code       TxErrorCode
+	Err        error
+	Giveback   bool
+	GasCharged bool
+}
+
+// newTxError wraps err, nil if err is nil.
+func newTxError(err error, giveback, gasCharged bool) error {
+	if err == nil {
+		return nil
+	}
+	if txErr, ok := err.(*TxError); ok {
+		return txErr
+	}
+	code, ok := txErrorCodes[err]
+	if !ok {
+		code = TxErrorCodeUnknown
+		if gasCharged {
+			code = TxErrorCodeExecutionFailed
+		}
+	}
+	return &TxError{
+		Code:       code,
+		Err:        err,
+		Giveback:   giveback,
+		GasCharged: gasCharged,
+	}
+}
+
+// Error returns the message of the wrapped error.
+func (e *TxError) Error() string {
+	return e.Err.Error()
+}
+
+// Cause returns the wrapped error.
+func (e *TxError) Cause() error {
+	return e.Err
+}
+
+// Unwrap returns the wrapped error.
+func (e *TxError) Unwrap() error {
+	return e.Err
+}
+
+// causer is an error wrapping another one, as in github.com/pkg/errors.
+type causer interface {
+	Cause() error
+}
+
+// ErrorCause returns the innermost error wrapped by err, err itself if it wraps nothing.
+func ErrorCause(err error) error {
+	for err != nil {
+		c, ok := err.(causer)
+		if !ok {
+			break
+		}
+		err = c.Cause()
+	}
+	return err
+}
+
+// TxErrorGiveback return if the tx failed with err is given back to pool,
+// the errors not coded are system errors and given back.
+func TxErrorGiveback(err error) bool {
+	if txErr, ok := err.(*TxError); ok {
+		return txErr.Giveback
+	}
+	return err != nil
+}
+
+// TxErrorCodeOf return the code of err.
+func TxErrorCodeOf(err error) TxErrorCode {
+	if err == nil {
+		return TxErrorCodeNone
+	}
+	if txErr, ok := err.(*TxError); ok {
+		return txErr.Code
+	}
+	if code, ok := txErrorCodes[err]; ok {
+		return code
+	}
+	return TxErrorCodeUnknown
+}
@@ -0,0 +1,156 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"encoding/json"
+	"math"
+
+	"github.com/alexlisong/go-nebulas/core/state"
+	"github.com/alexlisong/go-nebulas/storage"
+	"github.com/alexlisong/go-nebulas/util"
+)
+
+// Freeze Actions
+const (
+	// FreezeActionFreeze the admin freezes the outgoing transfers of tx.to.
+	FreezeActionFreeze = "freeze"
+
+	// FreezeActionUnfreeze the admin unfreezes tx.to.
+	FreezeActionUnfreeze = "unfreeze"
+)
+
+var (
+	// FreezeForkHeight freeze payload & frozen accounts check are activated from this height, disabled by default.
+	FreezeForkHeight uint64 = math.MaxUint64
+
+	// FrozenAccountKey the key of frozen flag in account variables.
+	FrozenAccountKey = []byte("__frozen__")
+)
+
+// FreezePayload carry the freeze action on tx.to, only the freeze admins in genesis can send it.
+type FreezePayload struct {
+	Action string
+}
+
+// FreezeEvent is the data of freeze events.
+type FreezeEvent struct {
+	Admin   string `json:"admin"`
+	Account string `json:"account"`
+}
+
+// LoadFreezePayload from bytes
+func LoadFreezePayload(bytes []byte) (*FreezePayload, error) {
+	payload := &FreezePayload{}
+	if err := json.Unmarshal(bytes, payload); err != nil {
+		return nil, ErrInvalidArgument
+	}
+	return NewFreezePayload(payload.Action)
+}
+
+// NewFreezePayload with action
+func NewFreezePayload(action string) (*FreezePayload, error) {
+	if action != FreezeActionFreeze && action != FreezeActionUnfreeze {
+		return nil, ErrInvalidFreezeAction
+	}
+	return &FreezePayload{
+		Action: action,
+	}, nil
+}
+
+// ToBytes serialize payload
+func (payload *FreezePayload) ToBytes() ([]byte, error) {
+	return json.Marshal(payload)
+}
+
+// BaseGasCount returns base gas count
+func (payload *FreezePayload) BaseGasCount() *util.Uint128 {
+	base, _ := util.NewUint128FromInt(20)
+	return base
+}
+
+// Execute the freeze payload in tx, update the frozen flag of tx.to
+func (payload *FreezePayload) Execute(limitedGas *util.Uint128, tx *Transaction, block *Block, ws WorldState) (*util.Uint128, string, error) {
+	if block == nil || tx == nil {
+		return util.NewUint128(), "", ErrNilArgument
+	}
+	if block.Height() < FreezeForkHeight {
+		return util.NewUint128(), "", ErrInvalidTxPayloadType
+	}
+	if !GetChainConfig(tx.chainID).IsFreezeAdmin(tx.from) {
+		return util.NewUint128(), "", ErrNotFreezeAdmin
+	}
+
+	acc, err := ws.GetOrCreateUserAccount(tx.to.address)
+	if err != nil {
+		return util.NewUint128(), "", err
+	}
+	frozen, err := isAccountFrozen(acc, block)
+	if err != nil {
+		return util.NewUint128(), "", err
+	}
+
+	var topic string
+	switch payload.Action {
+	case FreezeActionFreeze:
+		if frozen {
+			return util.NewUint128(), "", ErrAccountFrozen
+		}
+		err = acc.Put(FrozenAccountKey, []byte{1})
+		topic = TopicAccountFreeze
+	case FreezeActionUnfreeze:
+		if !frozen {
+			return util.NewUint128(), "", ErrAccountNotFrozen
+		}
+		err = acc.Del(FrozenAccountKey)
+		topic = TopicAccountUnfreeze
+	default:
+		err = ErrInvalidFreezeAction
+	}
+	if err != nil {
+		return util.NewUint128(), "", err
+	}
+
+	data, err := json.Marshal(&FreezeEvent{
+		Admin:   tx.from.String(),
+		Account: tx.to.String(),
+	})
+	if err != nil {
+		return util.NewUint128(), "", err
+	}
+	ws.RecordEvent(tx.hash, &state.Event{
+		Topic: topic,
+		Data:  string(data),
+	})
+	return util.NewUint128(), "", nil
+}
+
+// isAccountFrozen return if the outgoing transfers of acc are frozen in block.
+func isAccountFrozen(acc state.Account, block *Block) (bool, error) {
+	if block.Height() < FreezeForkHeight {
+		return false, nil
+	}
+	if _, err := acc.Get(FrozenAccountKey); err != nil {
+		if err == storage.ErrKeyNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
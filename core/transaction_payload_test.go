@@ -19,8 +19,11 @@
 package core
 
 import (
+	"encoding/json"
 	"testing"
 
+	"github.com/alexlisong/go-nebulas/crypto"
+	"github.com/alexlisong/go-nebulas/crypto/keystore"
 	"github.com/alexlisong/go-nebulas/util"
 	"github.com/stretchr/testify/assert"
 )
@@ -297,3 +300,500 @@ func TestPayload_Execute(t *testing.T) {
 
 	block.RollBack()
 }
+
+func TestRecoveryPayload_Lifecycle(t *testing.T) {
+	neb := testNeb(t)
+	bc := neb.chain
+
+	owner := mockAddress()
+	heir := mockAddress()
+	stranger := mockAddress()
+	balance, _ := util.NewUint128FromString("1000000000000000000")
+
+	block, err := bc.NewBlock(mockAddress())
+	assert.Nil(t, err)
+	for _, addr := range []*Address{owner, heir, stranger} {
+		acc, err := block.worldState.GetOrCreateUserAccount(addr.address)
+		assert.Nil(t, err)
+		assert.Nil(t, acc.AddBalance(balance))
+	}
+	block.Commit()
+	block, err = bc.NewBlockFromParent(bc.tailBlock.header.coinbase, block)
+	assert.Nil(t, err)
+
+	nonces := make(map[string]uint64)
+	newTx := func(from, to *Address, action, heir string, period uint64) *Transaction {
+		payloadType, payload := TxPayloadBinaryType, []byte(nil)
+		if len(action) > 0 {
+			payloadObj, err := NewRecoveryPayload(action, heir, period)
+			assert.Nil(t, err)
+			payloadType = TxPayloadRecoveryType
+			payload, _ = payloadObj.ToBytes()
+		}
+		nonces[from.String()]++
+		tx := mockTransaction(bc.chainID, nonces[from.String()], payloadType, payload)
+		tx.from = from
+		tx.to = to
+
+		key, _ := keystore.DefaultKS.GetUnlocked(from.String())
+		signature, _ := crypto.NewSignature(keystore.SECP256K1)
+		signature.InitSign(key.(keystore.PrivateKey))
+		assert.Nil(t, tx.Sign(signature))
+		return tx
+	}
+	// execute returns the topics of tx's events and the execution error.
+	execute := func(tx *Transaction) ([]string, string) {
+		txWorldState, err := block.WorldState().Prepare(tx.Hash().String())
+		assert.Nil(t, err)
+		giveback, err := block.ExecuteTransaction(tx, txWorldState)
+		assert.False(t, giveback)
+		assert.Nil(t, err)
+		_, err = txWorldState.CheckAndUpdate()
+		assert.Nil(t, err)
+
+		events, err := block.WorldState().FetchEvents(tx.Hash())
+		assert.Nil(t, err)
+		topics := []string{}
+		for _, event := range events {
+			topics = append(topics, event.Topic)
+		}
+		txEvent := TransactionEvent{}
+		assert.Nil(t, json.Unmarshal([]byte(events[len(events)-1].Data), &txEvent))
+		return topics, txEvent.Error
+	}
+
+	// contract can't be an heir.
+	contract, _ := mockDeployTransaction(bc.chainID, 0).GenerateContractAddress()
+	_, err = NewRecoveryPayload(RecoveryActionRegister, contract.String(), 3)
+	assert.Equal(t, ErrInvalidRecoveryHeir, err)
+
+	// not activated before fork.
+	defer func(height uint64) { RecoveryForkHeight = height }(RecoveryForkHeight)
+	topics, exeErr := execute(newTx(owner, owner, RecoveryActionRegister, heir.String(), 3))
+	assert.Equal(t, []string{TopicTransactionExecutionResult}, topics)
+	assert.Equal(t, ErrInvalidTxPayloadType.Error(), exeErr)
+	RecoveryForkHeight = block.Height()
+
+	// unregistered owner.
+	_, exeErr = execute(newTx(heir, owner, RecoveryActionClaim, "", 0))
+	assert.Equal(t, ErrRecoveryNotRegistered.Error(), exeErr)
+
+	topics, exeErr = execute(newTx(owner, owner, RecoveryActionRegister, heir.String(), 3))
+	assert.Equal(t, []string{TopicRecoveryRegister, TopicTransactionExecutionResult}, topics)
+	assert.Equal(t, "", exeErr)
+
+	// only the heir can claim.
+	_, exeErr = execute(newTx(stranger, owner, RecoveryActionClaim, "", 0))
+	assert.Equal(t, ErrRecoveryHeirMismatch.Error(), exeErr)
+
+	// owner's activity cancels the claim.
+	topics, _ = execute(newTx(heir, owner, RecoveryActionClaim, "", 0))
+	assert.Equal(t, []string{TopicRecoveryClaim, TopicTransactionExecutionResult}, topics)
+	topics, exeErr = execute(newTx(owner, mockAddress(), "", "", 0))
+	assert.Equal(t, []string{TopicRecoveryCancel, TopicTransactionExecutionResult}, topics)
+	assert.Equal(t, "", exeErr)
+	_, exeErr = execute(newTx(heir, owner, RecoveryActionSweep, "", 0))
+	assert.Equal(t, ErrRecoveryNoPendingClaim.Error(), exeErr)
+
+	// sweep within challenge period.
+	_, exeErr = execute(newTx(heir, owner, RecoveryActionClaim, "", 0))
+	assert.Equal(t, "", exeErr)
+	_, exeErr = execute(newTx(heir, owner, RecoveryActionClaim, "", 0))
+	assert.Equal(t, ErrRecoveryClaimPending.Error(), exeErr)
+	block.height += 2
+	_, exeErr = execute(newTx(heir, owner, RecoveryActionSweep, "", 0))
+	assert.Equal(t, ErrRecoveryChallengePeriod.Error(), exeErr)
+
+	// re-registration overwrites the pending claim.
+	topics, _ = execute(newTx(owner, owner, RecoveryActionRegister, heir.String(), 5))
+	assert.Equal(t, []string{TopicRecoveryRegister, TopicTransactionExecutionResult}, topics)
+	_, exeErr = execute(newTx(heir, owner, RecoveryActionSweep, "", 0))
+	assert.Equal(t, ErrRecoveryNoPendingClaim.Error(), exeErr)
+
+	// sweep after challenge period, owner's nonce is untouched.
+	_, exeErr = execute(newTx(heir, owner, RecoveryActionClaim, "", 0))
+	assert.Equal(t, "", exeErr)
+	block.height += 5
+	ownerAcc, err := block.WorldState().GetOrCreateUserAccount(owner.address)
+	assert.Nil(t, err)
+	ownerBalance := ownerAcc.Balance()
+	ownerNonce := ownerAcc.Nonce()
+	assert.Equal(t, nonces[owner.String()], ownerNonce)
+
+	sweepTx := newTx(heir, owner, RecoveryActionSweep, "", 0)
+	topics, exeErr = execute(sweepTx)
+	assert.Equal(t, []string{TopicRecoverySweep, TopicTransactionExecutionResult}, topics)
+	assert.Equal(t, "", exeErr)
+	events, err := block.WorldState().FetchEvents(sweepTx.Hash())
+	assert.Nil(t, err)
+	sweepEvent := RecoveryEvent{}
+	assert.Nil(t, json.Unmarshal([]byte(events[0].Data), &sweepEvent))
+	assert.Equal(t, ownerBalance.String(), sweepEvent.Amount)
+	assert.Equal(t, heir.String(), sweepEvent.Heir)
+
+	ownerAcc, err = block.WorldState().GetOrCreateUserAccount(owner.address)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, ownerAcc.Balance().Cmp(util.NewUint128()))
+	assert.Equal(t, ownerNonce, ownerAcc.Nonce())
+}
+
+func TestVotePayload(t *testing.T) {
+	neb := testNeb(t)
+	bc := neb.chain
+
+	candidate := mockAddress()
+	other := mockAddress()
+	voter := mockAddress()
+	balance, _ := util.NewUint128FromString("1000000000000000000000")
+
+	block, err := bc.NewBlock(mockAddress())
+	assert.Nil(t, err)
+	for _, addr := range []*Address{candidate, other, voter} {
+		acc, err := block.worldState.GetOrCreateUserAccount(addr.address)
+		assert.Nil(t, err)
+		assert.Nil(t, acc.AddBalance(balance))
+	}
+	block.Commit()
+	block, err = bc.NewBlockFromParent(bc.tailBlock.header.coinbase, block)
+	assert.Nil(t, err)
+
+	_, err = NewVotePayload("delegate", "")
+	assert.Equal(t, ErrInvalidDelegatePayloadAction, err)
+	_, err = NewVotePayload(VoteActionRegister, candidate.String())
+	assert.Equal(t, ErrInvalidArgument, err)
+	_, err = NewVotePayload(VoteActionVote, "")
+	assert.NotNil(t, err)
+
+	nonces := make(map[string]uint64)
+	// execute returns the execution error of a vote tx.
+	execute := func(from, to *Address, value *util.Uint128, action, target string) string {
+		payloadObj, err := NewVotePayload(action, target)
+		assert.Nil(t, err)
+		payload, _ := payloadObj.ToBytes()
+		nonces[from.String()]++
+		tx := mockTransaction(bc.chainID, nonces[from.String()], TxPayloadVoteType, payload)
+		tx.from, tx.to, tx.value = from, to, value
+
+		key, _ := keystore.DefaultKS.GetUnlocked(from.String())
+		signature, _ := crypto.NewSignature(keystore.SECP256K1)
+		signature.InitSign(key.(keystore.PrivateKey))
+		assert.Nil(t, tx.Sign(signature))
+
+		txWorldState, err := block.WorldState().Prepare(tx.Hash().String())
+		assert.Nil(t, err)
+		giveback, err := block.ExecuteTransaction(tx, txWorldState)
+		assert.False(t, giveback)
+		assert.Nil(t, err)
+		_, err = txWorldState.CheckAndUpdate()
+		assert.Nil(t, err)
+
+		events, err := block.WorldState().FetchEvents(tx.Hash())
+		assert.Nil(t, err)
+		txEvent := TransactionEvent{}
+		assert.Nil(t, json.Unmarshal([]byte(events[len(events)-1].Data), &txEvent))
+		return txEvent.Error
+	}
+	balanceOf := func(addr *Address) *util.Uint128 {
+		acc, err := block.WorldState().GetOrCreateUserAccount(addr.address)
+		assert.Nil(t, err)
+		return acc.Balance()
+	}
+	votesOf := func(addr *Address) uint64 {
+		record, err := getCandidateRecord(addr, block.WorldState())
+		assert.Nil(t, err)
+		return record.Votes
+	}
+	zero := util.NewUint128()
+
+	// not activated before fork.
+	defer func(height uint64) { VoteForkHeight = height }(VoteForkHeight)
+	assert.Equal(t, ErrInvalidTxPayloadType.Error(), execute(candidate, candidate, CandidateDeposit, VoteActionRegister, ""))
+	VoteForkHeight = block.Height()
+
+	assert.Equal(t, ErrVoteTransactionAddressNotEqual.Error(), execute(candidate, other, CandidateDeposit, VoteActionRegister, ""))
+
+	// cannot vote for a non-registered candidate.
+	assert.Equal(t, ErrInvalidDelegateToNonCandidate.Error(), execute(voter, voter, zero, VoteActionVote, candidate.String()))
+
+	// register with the exact deposit.
+	assert.Equal(t, ErrInvalidCandidateDeposit.Error(), execute(candidate, candidate, zero, VoteActionRegister, ""))
+	before := balanceOf(candidate)
+	assert.Equal(t, "", execute(candidate, candidate, CandidateDeposit, VoteActionRegister, ""))
+	locked, _ := before.Sub(CandidateDeposit)
+	assert.Equal(t, locked, balanceOf(candidate))
+	assert.NotNil(t, block.WorldState().DelegateRoot())
+	assert.Equal(t, ErrCandidateAlreadyRegistered.Error(), execute(candidate, candidate, CandidateDeposit, VoteActionRegister, ""))
+	assert.Equal(t, "", execute(other, other, CandidateDeposit, VoteActionRegister, ""))
+
+	// vote & unvote.
+	assert.Equal(t, "", execute(voter, voter, zero, VoteActionVote, candidate.String()))
+	assert.Equal(t, uint64(1), votesOf(candidate))
+	assert.Equal(t, ErrDuplicatedVote.Error(), execute(voter, voter, zero, VoteActionVote, candidate.String()))
+	assert.Equal(t, ErrInvalidUnDelegateFromNonDelegatee.Error(), execute(voter, voter, zero, VoteActionUnvote, other.String()))
+	assert.Equal(t, "", execute(voter, voter, zero, VoteActionUnvote, candidate.String()))
+	assert.Equal(t, uint64(0), votesOf(candidate))
+	assert.Equal(t, ErrInvalidUnDelegateFromNonDelegatee.Error(), execute(voter, voter, zero, VoteActionUnvote, candidate.String()))
+
+	// a new vote replaces the previous one.
+	assert.Equal(t, "", execute(voter, voter, zero, VoteActionVote, candidate.String()))
+	assert.Equal(t, "", execute(voter, voter, zero, VoteActionVote, other.String()))
+	assert.Equal(t, uint64(0), votesOf(candidate))
+	assert.Equal(t, uint64(1), votesOf(other))
+
+	// unregister refunds the candidate deposit.
+	assert.Equal(t, "", execute(candidate, candidate, zero, VoteActionUnregister, ""))
+	assert.Equal(t, before, balanceOf(candidate))
+	_, err = getCandidateRecord(candidate, block.WorldState())
+	assert.Equal(t, ErrCandidateNotRegistered, err)
+	assert.Equal(t, ErrCandidateNotRegistered.Error(), execute(candidate, candidate, zero, VoteActionUnregister, ""))
+}
+
+func TestBatchPayload(t *testing.T) {
+	neb := testNeb(t)
+	bc := neb.chain
+
+	sender := mockAddress()
+	recipients := []*Address{mockAddress(), mockAddress(), mockAddress()}
+	balance := util.NewUint128FromUint(1000)
+	fee, _ := TransactionMaxGas.Mul(TransactionGasPrice)
+	fund, _ := balance.Add(fee)
+
+	block, err := bc.NewBlock(mockAddress())
+	assert.Nil(t, err)
+	acc, err := block.worldState.GetOrCreateUserAccount(sender.address)
+	assert.Nil(t, err)
+	assert.Nil(t, acc.AddBalance(fund))
+	block.Commit()
+	block, err = bc.NewBlockFromParent(bc.tailBlock.header.coinbase, block)
+	assert.Nil(t, err)
+
+	_, err = NewBatchPayload(nil)
+	assert.Equal(t, ErrInvalidBatchEntries, err)
+	_, err = NewBatchPayload(make([]*BatchEntry, MaxBatchEntries+1))
+	assert.Equal(t, ErrInvalidBatchEntries, err)
+	contract, _ := mockDeployTransaction(bc.chainID, 0).GenerateContractAddress()
+	_, err = NewBatchPayload([]*BatchEntry{{To: contract.String(), Value: "1"}})
+	assert.Equal(t, ErrInvalidBatchRecipient, err)
+	_, err = NewBatchPayload([]*BatchEntry{{To: recipients[0].String(), Value: "-1"}})
+	assert.Equal(t, ErrInvalidBatchEntries, err)
+
+	nonce := uint64(0)
+	// execute returns the topics & execution error of a batch tx.
+	execute := func(to *Address, values ...uint64) ([]string, string) {
+		entries := make([]*BatchEntry, len(values))
+		for i, value := range values {
+			entries[i] = &BatchEntry{To: recipients[i].String(), Value: util.NewUint128FromUint(value).String()}
+		}
+		payloadObj, err := NewBatchPayload(entries)
+		assert.Nil(t, err)
+		expectedGas, _ := BatchEntryGasCount.Mul(util.NewUint128FromUint(uint64(len(values))))
+		assert.Equal(t, expectedGas, payloadObj.BaseGasCount())
+		payload, _ := payloadObj.ToBytes()
+		nonce++
+		tx := mockTransaction(bc.chainID, nonce, TxPayloadBatchType, payload)
+		tx.from, tx.to = sender, to
+
+		key, _ := keystore.DefaultKS.GetUnlocked(sender.String())
+		signature, _ := crypto.NewSignature(keystore.SECP256K1)
+		signature.InitSign(key.(keystore.PrivateKey))
+		assert.Nil(t, tx.Sign(signature))
+
+		txWorldState, err := block.WorldState().Prepare(tx.Hash().String())
+		assert.Nil(t, err)
+		giveback, err := block.ExecuteTransaction(tx, txWorldState)
+		assert.False(t, giveback)
+		assert.Nil(t, err)
+		_, err = txWorldState.CheckAndUpdate()
+		assert.Nil(t, err)
+
+		events, err := block.WorldState().FetchEvents(tx.Hash())
+		assert.Nil(t, err)
+		topics := []string{}
+		for _, event := range events {
+			topics = append(topics, event.Topic)
+		}
+		txEvent := TransactionEvent{}
+		assert.Nil(t, json.Unmarshal([]byte(events[len(events)-1].Data), &txEvent))
+		return topics, txEvent.Error
+	}
+	balanceOf := func(addr *Address) string {
+		acc, err := block.WorldState().GetOrCreateUserAccount(addr.address)
+		assert.Nil(t, err)
+		return acc.Balance().String()
+	}
+
+	// not activated before fork.
+	defer func(height uint64) { BatchForkHeight = height }(BatchForkHeight)
+	_, exeErr := execute(sender, 100)
+	assert.Equal(t, ErrInvalidTxPayloadType.Error(), exeErr)
+	BatchForkHeight = block.Height()
+
+	_, exeErr = execute(recipients[0], 100)
+	assert.Equal(t, ErrBatchTransactionAddressNotEqual.Error(), exeErr)
+
+	// total value exceeds the balance, nothing is transferred.
+	_, exeErr = execute(sender, 100, 2000, 100)
+	assert.Equal(t, ErrInsufficientBalance.Error(), exeErr)
+	for _, recipient := range recipients {
+		assert.Equal(t, "0", balanceOf(recipient))
+	}
+
+	topics, exeErr := execute(sender, 100, 200, 300)
+	assert.Equal(t, "", exeErr)
+	assert.Equal(t, []string{TopicBatchTransfer, TopicBatchTransfer, TopicBatchTransfer, TopicTransactionExecutionResult}, topics)
+	assert.Equal(t, "100", balanceOf(recipients[0]))
+	assert.Equal(t, "200", balanceOf(recipients[1]))
+	assert.Equal(t, "300", balanceOf(recipients[2]))
+}
+
+func TestFreezePayload(t *testing.T) {
+	neb := testNeb(t)
+	bc := neb.chain
+
+	admin := mockAddress()
+	owner := mockAddress()
+	stranger := mockAddress()
+	balance, _ := util.NewUint128FromString("1000000000000000000")
+
+	config := GetChainConfig(bc.chainID)
+	defer RegisterChainConfig(config)
+	adminConfig := *config
+	adminConfig.FreezeAdmins = []*Address{admin}
+	RegisterChainConfig(&adminConfig)
+
+	block, err := bc.NewBlock(mockAddress())
+	assert.Nil(t, err)
+	for _, addr := range []*Address{admin, owner, stranger} {
+		acc, err := block.worldState.GetOrCreateUserAccount(addr.address)
+		assert.Nil(t, err)
+		assert.Nil(t, acc.AddBalance(balance))
+	}
+	block.Commit()
+	block, err = bc.NewBlockFromParent(bc.tailBlock.header.coinbase, block)
+	assert.Nil(t, err)
+
+	nonces := make(map[string]uint64)
+	newTx := func(from, to *Address, action string) *Transaction {
+		payloadType, payload := TxPayloadBinaryType, []byte(nil)
+		if len(action) > 0 {
+			payloadObj, err := NewFreezePayload(action)
+			assert.Nil(t, err)
+			payloadType = TxPayloadFreezeType
+			payload, _ = payloadObj.ToBytes()
+		}
+		nonces[from.String()]++
+		tx := mockTransaction(bc.chainID, nonces[from.String()], payloadType, payload)
+		tx.from = from
+		tx.to = to
+
+		key, _ := keystore.DefaultKS.GetUnlocked(from.String())
+		signature, _ := crypto.NewSignature(keystore.SECP256K1)
+		signature.InitSign(key.(keystore.PrivateKey))
+		assert.Nil(t, tx.Sign(signature))
+		return tx
+	}
+	// execute returns the topics of tx's events and the execution error.
+	execute := func(tx *Transaction) ([]string, string) {
+		txWorldState, err := block.WorldState().Prepare(tx.Hash().String())
+		assert.Nil(t, err)
+		giveback, err := VerifyExecution(tx, block, txWorldState)
+		assert.False(t, giveback)
+		assert.Nil(t, err)
+		_, err = txWorldState.CheckAndUpdate()
+		assert.Nil(t, err)
+
+		events, err := block.WorldState().FetchEvents(tx.Hash())
+		assert.Nil(t, err)
+		topics := []string{}
+		for _, event := range events {
+			topics = append(topics, event.Topic)
+		}
+		txEvent := TransactionEvent{}
+		assert.Nil(t, json.Unmarshal([]byte(events[len(events)-1].Data), &txEvent))
+		return topics, txEvent.Error
+	}
+
+	_, err = NewFreezePayload("lock")
+	assert.Equal(t, ErrInvalidFreezeAction, err)
+
+	// not activated before fork.
+	defer func(height uint64) { FreezeForkHeight = height }(FreezeForkHeight)
+	_, exeErr := execute(newTx(admin, owner, FreezeActionFreeze))
+	assert.Equal(t, ErrInvalidTxPayloadType.Error(), exeErr)
+	FreezeForkHeight = block.Height()
+
+	_, exeErr = execute(newTx(stranger, owner, FreezeActionFreeze))
+	assert.Equal(t, ErrNotFreezeAdmin.Error(), exeErr)
+	_, exeErr = execute(newTx(admin, owner, FreezeActionUnfreeze))
+	assert.Equal(t, ErrAccountNotFrozen.Error(), exeErr)
+
+	topics, exeErr := execute(newTx(admin, owner, FreezeActionFreeze))
+	assert.Equal(t, []string{TopicAccountFreeze, TopicTransactionExecutionResult}, topics)
+	assert.Equal(t, "", exeErr)
+
+	// outgoing transfers are rejected by pool check, and fail with gas charged on chain.
+	tx := newTx(owner, stranger, "")
+	giveback, err := CheckTransaction(tx, block, block.WorldState())
+	assert.False(t, giveback)
+	assert.Equal(t, ErrAccountFrozen, ErrorCause(err))
+	_, exeErr = execute(tx)
+	assert.Equal(t, ErrAccountFrozen.Error(), exeErr)
+	acc, err := block.WorldState().GetOrCreateUserAccount(owner.address)
+	assert.Nil(t, err)
+	assert.Equal(t, -1, acc.Balance().Cmp(balance))
+
+	// incoming transfers are not affected.
+	_, exeErr = execute(newTx(stranger, owner, ""))
+	assert.Equal(t, "", exeErr)
+
+	topics, exeErr = execute(newTx(admin, owner, FreezeActionUnfreeze))
+	assert.Equal(t, []string{TopicAccountUnfreeze, TopicTransactionExecutionResult}, topics)
+	assert.Equal(t, "", exeErr)
+	_, exeErr = execute(newTx(owner, stranger, ""))
+	assert.Equal(t, "", exeErr)
+}
+
+func TestCallContract(t *testing.T) {
+	neb := testNeb(t)
+	bc := neb.chain
+
+	from := mockAddress()
+	balance, _ := util.NewUint128FromString("1000000000000000000")
+	key, _ := keystore.DefaultKS.GetUnlocked(from.String())
+	signature, _ := crypto.NewSignature(keystore.SECP256K1)
+	signature.InitSign(key.(keystore.PrivateKey))
+
+	deployTx := mockDeployTransaction(bc.chainID, 1)
+	deployTx.from, deployTx.to = from, from
+	assert.Nil(t, deployTx.Sign(signature))
+	contract, _ := deployTx.GenerateContractAddress()
+
+	block := bc.tailBlock
+	assert.Nil(t, block.Begin())
+	acc, err := block.WorldState().GetOrCreateUserAccount(from.Bytes())
+	assert.Nil(t, err)
+	assert.Nil(t, acc.AddBalance(balance))
+	_, err = block.ExecuteTransaction(deployTx, block.WorldState())
+	assert.Nil(t, err)
+	block.Commit()
+
+	// the caller has neither balance nor nonce.
+	caller := mockAddress()
+	accountsRoot := block.WorldState().AccountsRoot()
+	result, gasUsed, err := CallContract(block, caller, contract, "totalSupply", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "", result)
+	assert.True(t, gasUsed.Cmp(util.NewUint128FromUint(100)) > 0)
+	assert.Equal(t, accountsRoot, block.WorldState().AccountsRoot())
+
+	callerAcc, err := block.WorldState().GetOrCreateUserAccount(caller.Bytes())
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(0), callerAcc.Nonce())
+
+	_, _, err = CallContract(block, nil, contract, "totalSupply", nil)
+	assert.Nil(t, err)
+
+	// not a deployed contract.
+	_, _, err = CallContract(block, caller, mockAddress(), "totalSupply", nil)
+	assert.NotNil(t, err)
+}
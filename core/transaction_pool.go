@@ -19,8 +19,10 @@
 package core
 
 import (
+	"encoding/json"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/alexlisong/go-nebulas/core/state"
@@ -28,6 +30,7 @@ import (
 	"github.com/gogo/protobuf/proto"
 	"github.com/alexlisong/go-nebulas/common/sorted"
 	"github.com/alexlisong/go-nebulas/core/pb"
+	"github.com/alexlisong/go-nebulas/crypto/keystore"
 	"github.com/alexlisong/go-nebulas/net"
 	"github.com/alexlisong/go-nebulas/util"
 	"github.com/alexlisong/go-nebulas/util/byteutils"
@@ -39,8 +42,39 @@ var (
 	metricUpdateInterval = time.Second
 	txEvictInterval      = time.Minute
 	txLifetime           = time.Minute * 90
+
+	// txVerdicts are the verdicts of the received txs failed to push, the others are neutral.
+	txVerdicts = map[error]net.Verdict{
+		ErrDuplicatedTransaction:         net.VerdictUseless,
+		ErrInvalidChainID:                net.VerdictInvalid,
+		ErrInvalidTransactionHash:        net.VerdictInvalid,
+		ErrInvalidTransactionAlg:         net.VerdictInvalid,
+		ErrInvalidTransactionSigner:      net.VerdictInvalid,
+		ErrInvalidTransactionPayerSigner: net.VerdictInvalid,
+		ErrInvalidTxDataType:             net.VerdictInvalid,
+	}
+)
+
+const (
+	// DefaultTxReplacePriceBump is the default min gasPrice bump in percent to replace a pending tx.
+	DefaultTxReplacePriceBump = 10
+
+	// DefaultTxTimestampMaxDrift is the default max seconds a received tx's timestamp can be ahead of the node's clock.
+	DefaultTxTimestampMaxDrift = 24 * 60 * 60
 )
 
+// nonceKey identify txs by from address and nonce.
+type nonceKey struct {
+	from  byteutils.HexHash
+	nonce uint64
+}
+
+// packingTx is a tx popped out of the pool to be packed into a proposing block.
+type packingTx struct {
+	tx       *Transaction
+	poppedAt time.Time
+}
+
 // TransactionPool cache txs, is thread safe
 type TransactionPool struct {
 	receivedMessageCh chan net.Message
@@ -58,8 +92,28 @@ type TransactionPool struct {
 	minGasPrice *util.Uint128 // the lowest gasPrice.
 	maxGasLimit *util.Uint128 // the maximum gasLimit.
 
-	eventEmitter *EventEmitter
-	bc           *BlockChain
+	replacePriceBump  uint32 // the min gasPrice bump in percent to replace a tx.
+	timestampMaxDrift int64  // the max seconds a tx's timestamp can be ahead of the node's clock.
+	packing           map[nonceKey]*packingTx
+	replacedTxs       uint64
+	journal           *txJournal // nil if journaling is disabled.
+
+	packingGasLimit *util.Uint128 // the max gas used by txs in a proposing block, nil for no limit.
+	packingMaxTxs   uint32        // the max count of txs in a proposing block, 0 for no limit.
+
+	locals                map[byteutils.HexHash]*localTx
+	rebroadcastBlocks     uint64 // the count of blocks a local tx can stay pending before it's re-broadcast.
+	rebroadcastMaxRetries uint32 // the max count of re-broadcast attempts of a local tx.
+	rebroadcastedTxs      uint64
+
+	reserveQueued bool // count the queued txs in PendingBalance.
+
+	eventEmitter    *EventEmitter
+	reorgSubscriber *EventSubscriber
+	tailSubscriber  *EventSubscriber
+	bc              *BlockChain
+
+	pendingSubs map[*PendingTxSubscriber]bool
 }
 
 func nonceCmp(a interface{}, b interface{}) int {
@@ -70,28 +124,57 @@ func nonceCmp(a interface{}, b interface{}) int {
 	} else if txa.Nonce() > txb.Nonce() {
 		return 1
 	} else {
-		return txb.GasPrice().Cmp(txa.GasPrice())
+		return feeCmp(txa, txb)
 	}
 }
 
 func gasCmp(a interface{}, b interface{}) int {
 	txa := a.(*Transaction)
 	txb := b.(*Transaction)
-	return txb.GasPrice().Cmp(txa.GasPrice())
+	return feeCmp(txa, txb)
+}
+
+// feeCmp orders txs by gasPrice desc, then by the effective fee desc, then by timestamp asc.
+func feeCmp(txa, txb *Transaction) int {
+	if cmp := txb.GasPrice().Cmp(txa.GasPrice()); cmp != 0 {
+		return cmp
+	}
+	feea, erra := txa.Fee()
+	feeb, errb := txb.Fee()
+	if erra == nil && errb == nil {
+		if cmp := feeb.Cmp(feea); cmp != 0 {
+			return cmp
+		}
+	}
+	if txa.timestamp < txb.timestamp {
+		return -1
+	} else if txa.timestamp > txb.timestamp {
+		return 1
+	}
+	return 0
 }
 
 // NewTransactionPool create a new TransactionPool
 func NewTransactionPool(size int) (*TransactionPool, error) {
 	return &TransactionPool{
-		receivedMessageCh: make(chan net.Message, size),
-		quitCh:            make(chan int, 1),
-		size:              size,
-		candidates:        sorted.NewSlice(gasCmp),
-		buckets:           make(map[byteutils.HexHash]*sorted.Slice),
-		all:               make(map[byteutils.HexHash]*Transaction),
-		bucketsLastUpdate: make(map[byteutils.HexHash]time.Time),
-		minGasPrice:       TransactionGasPrice,
-		maxGasLimit:       TransactionMaxGas,
+		receivedMessageCh:     make(chan net.Message, size),
+		quitCh:                make(chan int, 1),
+		size:                  size,
+		candidates:            sorted.NewSlice(gasCmp),
+		buckets:               make(map[byteutils.HexHash]*sorted.Slice),
+		all:                   make(map[byteutils.HexHash]*Transaction),
+		bucketsLastUpdate:     make(map[byteutils.HexHash]time.Time),
+		minGasPrice:           TransactionGasPrice,
+		maxGasLimit:           TransactionMaxGas,
+		replacePriceBump:      DefaultTxReplacePriceBump,
+		timestampMaxDrift:     DefaultTxTimestampMaxDrift,
+		packing:               make(map[nonceKey]*packingTx),
+		pendingSubs:           make(map[*PendingTxSubscriber]bool),
+		locals:                make(map[byteutils.HexHash]*localTx),
+		rebroadcastBlocks:     DefaultTxRebroadcastBlocks,
+		rebroadcastMaxRetries: DefaultTxRebroadcastMaxRetries,
+		reorgSubscriber:       NewEventSubscriber(128, []string{TopicChainReorg}),
+		tailSubscriber:        NewEventSubscriber(128, []string{TopicNewTailBlock}),
 	}, nil
 }
 
@@ -114,9 +197,51 @@ func (pool *TransactionPool) SetGasConfig(gasPrice, gasLimit *util.Uint128) erro
 	return nil
 }
 
+// MinGasPrice return the lowest gasPrice accepted by the pool, it's a local relay policy not checked in blocks.
+func (pool *TransactionPool) MinGasPrice() *util.Uint128 {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	return pool.minGasPrice
+}
+
+// SetReplacePriceBump config the min gasPrice bump in percent to replace a pending tx, 0 for default.
+func (pool *TransactionPool) SetReplacePriceBump(bump uint32) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	if bump == 0 {
+		bump = DefaultTxReplacePriceBump
+	}
+	pool.replacePriceBump = bump
+}
+
+// SetTimestampMaxDrift config the max seconds a tx's timestamp can be ahead of the node's clock, 0 for default.
+func (pool *TransactionPool) SetTimestampMaxDrift(drift int64) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	if drift <= 0 {
+		drift = DefaultTxTimestampMaxDrift
+	}
+	pool.timestampMaxDrift = drift
+}
+
+// ReplacedTransactions return the count of txs replaced by a higher gasPrice.
+func (pool *TransactionPool) ReplacedTransactions() uint64 {
+	return atomic.LoadUint64(&pool.replacedTxs)
+}
+
 // RegisterInNetwork register message subscriber in network.
 func (pool *TransactionPool) RegisterInNetwork(ns net.Service) {
-	ns.Register(net.NewSubscriber(pool, pool.receivedMessageCh, true, MessageTypeNewTx, net.MessageWeightNewTx))
+	// tx gossip yields to blocks in dispatcher.
+	ns.Register(net.NewSubscriber(pool, []string{MessageTypeNewTx},
+		net.WithMessageChan(pool.receivedMessageCh),
+		net.WithDoFilter(),
+		net.WithMessageWeight(MessageTypeNewTx, net.MessageWeightNewTx),
+		net.WithPriority(net.MessagePriorityLow),
+		net.WithName("TransactionPool"),
+	))
 	pool.ns = ns
 }
 
@@ -126,6 +251,7 @@ func (pool *TransactionPool) setBlockChain(bc *BlockChain) {
 
 func (pool *TransactionPool) setEventEmitter(emitter *EventEmitter) {
 	pool.eventEmitter = emitter
+	emitter.Register(pool.reorgSubscriber, pool.tailSubscriber)
 }
 
 // Start start loop.
@@ -134,6 +260,12 @@ func (pool *TransactionPool) Start() {
 		"size": pool.size,
 	}).Info("Starting TransactionPool...")
 
+	if _, err := pool.ReplayJournal(); err != nil {
+		logging.CLog().WithFields(logrus.Fields{
+			"err": err,
+		}).Error("Failed to replay tx journal.")
+	}
+
 	go pool.loop()
 }
 
@@ -159,6 +291,12 @@ func (pool *TransactionPool) loop() {
 		case <-evictChan:
 			pool.evictExpiredTransactions()
 
+		case e := <-pool.reorgSubscriber.eventCh:
+			pool.handleChainReorg(e)
+
+		case <-pool.tailSubscriber.eventCh:
+			pool.rebroadcastLocalTxs()
+
 		case <-pool.quitCh:
 			logging.CLog().WithFields(logrus.Fields{
 				"size": pool.size,
@@ -182,14 +320,16 @@ func (pool *TransactionPool) loop() {
 					"msg":     msg,
 					"err":     err,
 				}).Debug("Failed to unmarshal data.")
+				pool.reportPeer(msg, net.VerdictInvalid)
 				continue
 			}
-			if err := tx.FromProto(pbTx); err != nil {
+			if err := tx.FromRelayedProto(pbTx, pool.bc.ChainID()); err != nil {
 				logging.VLog().WithFields(logrus.Fields{
 					"msgType": msg.MessageType(),
 					"msg":     msg,
 					"err":     err,
 				}).Debug("Failed to recover a tx from proto data.")
+				pool.reportPeer(msg, net.VerdictInvalid)
 				continue
 			}
 
@@ -200,12 +340,80 @@ func (pool *TransactionPool) loop() {
 					"transaction": tx,
 					"err":         err,
 				}).Debug("Failed to push a tx into tx pool.")
+				if verdict, ok := txVerdicts[err]; ok {
+					pool.reportPeer(msg, verdict)
+				}
+				continue
+			}
+			pool.reportPeer(msg, net.VerdictValid)
+		}
+	}
+}
+
+// reportPeer report the verdict of the tx message to its sender.
+func (pool *TransactionPool) reportPeer(msg net.Message, verdict net.Verdict) {
+	if pool.ns == nil || msg.MessageFrom() == NoSender {
+		return
+	}
+	pool.ns.ReportPeer(msg.MessageFrom(), verdict)
+}
+
+// handleChainReorg give back the txs in reverted blocks which are not packed in applied blocks.
+func (pool *TransactionPool) handleChainReorg(e *state.Event) {
+	reorg := &ChainReorgEvent{}
+	if err := json.Unmarshal([]byte(e.Data), reorg); err != nil {
+		logging.VLog().WithFields(logrus.Fields{
+			"event": e,
+			"err":   err,
+		}).Debug("Failed to unmarshal chain reorg event.")
+		return
+	}
+
+	applied := make(map[byteutils.HexHash]bool)
+	for _, hash := range reorg.Applied {
+		block := pool.loadReorgBlock(hash)
+		if block == nil {
+			continue
+		}
+		for _, tx := range block.transactions {
+			applied[tx.hash.Hex()] = true
+		}
+	}
+
+	for _, hash := range reorg.Reverted {
+		block := pool.loadReorgBlock(hash)
+		if block == nil {
+			continue
+		}
+		for _, tx := range block.transactions {
+			if applied[tx.hash.Hex()] {
 				continue
 			}
+			if err := pool.Push(tx); err != nil {
+				logging.VLog().WithFields(logrus.Fields{
+					"block": block,
+					"tx":    tx,
+					"err":   err,
+				}).Debug("Failed to give back tx in reverted block.")
+			}
 		}
 	}
 }
 
+func (pool *TransactionPool) loadReorgBlock(hash string) *Block {
+	bytes, err := byteutils.FromHex(hash)
+	if err != nil {
+		return nil
+	}
+	block := pool.bc.GetBlock(bytes)
+	if block == nil {
+		logging.VLog().WithFields(logrus.Fields{
+			"hash": hash,
+		}).Debug("Failed to load block in chain reorg event.")
+	}
+	return block
+}
+
 // GetTransaction return transaction of given hash from transaction pool.
 func (pool *TransactionPool) GetTransaction(hash byteutils.Hash) *Transaction {
 	pool.mu.Lock()
@@ -214,6 +422,32 @@ func (pool *TransactionPool) GetTransaction(hash byteutils.Hash) *Transaction {
 	return pool.all[hash.Hex()]
 }
 
+// GetPendingNonce return the nonce of the last continuous pending tx after the given nonce
+// of the account, or the given nonce if there isn't any.
+func (pool *TransactionPool) GetPendingNonce(addr *Address, nonce uint64) uint64 {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	return pool.getPendingNonce(addr, nonce)
+}
+
+func (pool *TransactionPool) getPendingNonce(addr *Address, nonce uint64) uint64 {
+	bucket, ok := pool.buckets[addr.address.Hex()]
+	if !ok {
+		return nonce
+	}
+	for i := 0; i < bucket.Len(); i++ {
+		tx := bucket.Index(i).(*Transaction)
+		if tx.nonce > nonce+1 {
+			break
+		}
+		if tx.nonce == nonce+1 {
+			nonce++
+		}
+	}
+	return nonce
+}
+
 // PushAndRelay push tx into pool and relay it
 func (pool *TransactionPool) PushAndRelay(tx *Transaction) error {
 	if err := pool.Push(tx); err != nil {
@@ -239,6 +473,7 @@ func (pool *TransactionPool) PushAndBroadcast(tx *Transaction) error {
 		return err
 	}
 
+	pool.trackLocal(tx)
 	pool.ns.Broadcast(MessageTypeNewTx, tx, net.MessagePriorityNormal)
 	return nil
 }
@@ -272,14 +507,36 @@ func (pool *TransactionPool) Push(tx *Transaction) error {
 		}
 	}
 
+	// verify data type before the expensive signature verification
+	if err := CheckTxDataType(tx.Type()); err != nil {
+		return err
+	}
+	if pool.bc.TailBlock().Height() >= TxPayloadRegistryForkHeight && !IsRegisteredPayloadType(tx.Type(), pool.bc.TailBlock().Height()+1) {
+		return ErrUnregisteredTxDataType
+	}
+	if tx.expiredAt != 0 && pool.bc.TailBlock().Height()+1 < ExpiredAtForkHeight {
+		return ErrTxExpiredAtNotActivated
+	}
+	if tx.payer != nil && pool.bc.TailBlock().Height()+1 < TxPayerForkHeight {
+		return ErrTxPayerNotActivated
+	}
+	if tx.alg == keystore.ED25519 && pool.bc.TailBlock().Height()+1 < Ed25519ForkHeight {
+		return ErrTxAlgNotActivated
+	}
+
+	// reject tx from the future
+	if tx.timestamp > time.Now().Unix()+pool.timestampMaxDrift {
+		return ErrTxTimestampAheadOfNode
+	}
+
 	// verify non-dup tx
 	if _, ok := pool.all[tx.hash.Hex()]; ok {
 		return ErrDuplicatedTransaction
 	} // ToRefine: refine the lock scope
 
-	// if tx's gasPrice below the pool config lowest gasPrice, return ErrBelowGasPrice
+	// if tx's gasPrice below the pool config lowest gasPrice, return ErrGasPriceBelowMinimum
 	if tx.gasPrice.Cmp(pool.minGasPrice) < 0 {
-		return ErrBelowGasPrice
+		return ErrGasPriceBelowMinimum
 	}
 
 	if tx.gasLimit.Cmp(util.NewUint128()) <= 0 {
@@ -290,17 +547,34 @@ func (pool *TransactionPool) Push(tx *Transaction) error {
 		return ErrOutOfGasLimit
 	}
 
+	if tx.IsExpired(time.Now().Unix()) {
+		return ErrTransactionExpired
+	}
+
 	// verify hash & sign of tx
 	if err := tx.VerifyIntegrity(pool.bc.chainID); err != nil {
 		return err
 	}
 
+	// replace the pending tx with the same nonce
+	replaced, err := pool.replaceableTx(tx)
+	if err != nil {
+		return err
+	}
+	if replaced != nil {
+		pool.replaceTx(replaced, tx)
+	}
+
 	// cache the verified tx
 	pool.pushTx(tx)
+	pool.journalTx(tx)
+	pool.notifyPending(tx)
 	// drop max tx in longest bucket if full
 	if len(pool.all) > pool.size {
 		poollen := len(pool.all)
-		pool.dropTx()
+		if drop := pool.dropTx(); drop != nil {
+			pool.notifyDropped(drop, DropReasonPoolFull)
+		}
 
 		logging.VLog().WithFields(logrus.Fields{
 			"tx":         tx,
@@ -360,7 +634,83 @@ func (pool *TransactionPool) popTx(tx *Transaction) {
 	}
 }
 
-func (pool *TransactionPool) dropTx() {
+// replaceableTx return the pending tx which has the same from and nonce with tx and can be replaced by it.
+func (pool *TransactionPool) replaceableTx(tx *Transaction) (*Transaction, error) {
+	key := nonceKey{from: tx.from.address.Hex(), nonce: tx.nonce}
+	if packing, ok := pool.packing[key]; ok {
+		if !packing.tx.hash.Equals(tx.hash) {
+			return nil, ErrReplacePackingTransaction
+		}
+		// the packing tx is given back.
+		delete(pool.packing, key)
+	}
+
+	bucket, ok := pool.buckets[key.from]
+	if !ok {
+		return nil, nil
+	}
+	for i := 0; i < bucket.Len(); i++ {
+		old := bucket.Index(i).(*Transaction)
+		if old.nonce > tx.nonce {
+			break
+		}
+		if old.nonce == tx.nonce {
+			if !pool.isPriceBumped(old.gasPrice, tx.gasPrice) {
+				return nil, ErrUnderpricedReplacement
+			}
+			return old, nil
+		}
+	}
+	return nil, nil
+}
+
+// isPriceBumped return if gasPrice * 100 >= oldGasPrice * (100 + replacePriceBump).
+func (pool *TransactionPool) isPriceBumped(oldGasPrice, gasPrice *util.Uint128) bool {
+	bump := util.NewUint128FromUint(uint64(100 + pool.replacePriceBump))
+	threshold, err := oldGasPrice.Mul(bump)
+	if err != nil {
+		return false
+	}
+	bumped, err := gasPrice.Mul(util.NewUint128FromUint(100))
+	if err != nil {
+		return false
+	}
+	return bumped.Cmp(threshold) >= 0
+}
+
+// replaceTx remove the replaced tx from pool, tx is pushed right after.
+func (pool *TransactionPool) replaceTx(replaced *Transaction, tx *Transaction) {
+	slot := replaced.from.address.Hex()
+	bucket := pool.buckets[slot]
+	oldCandidate := bucket.Left()
+	bucket.Del(replaced)
+	delete(pool.all, replaced.hash.Hex())
+	delete(pool.locals, replaced.hash.Hex())
+	if oldCandidate == replaced {
+		pool.candidates.Del(replaced)
+		if bucket.Len() > 0 {
+			pool.candidates.Push(bucket.Left())
+		}
+	}
+	if bucket.Len() == 0 {
+		delete(pool.buckets, slot)
+	}
+	atomic.AddUint64(&pool.replacedTxs, 1)
+
+	logging.VLog().WithFields(logrus.Fields{
+		"replaced": replaced.hash.Hex(),
+		"tx":       tx,
+	}).Debug("Replace transaction.")
+
+	event := &state.Event{
+		Topic: TopicDropTransaction,
+		Data:  replaced.String(),
+	}
+	pool.eventEmitter.Trigger(event)
+	pool.notifyDropped(replaced, DropReasonReplaced)
+}
+
+func (pool *TransactionPool) dropTx() *Transaction {
 	var longestSlice *sorted.Slice
 	longestLen := 0
 	for _, v := range pool.buckets {
@@ -384,7 +734,9 @@ func (pool *TransactionPool) dropTx() {
 				delete(pool.bucketsLastUpdate, drop.from.address.Hex())
 			}
 		}
+		return drop
 	}
+	return nil
 }
 
 // PopWithBlacklist return a tx with highest gasprice and not in the blocklist
@@ -406,6 +758,7 @@ func (pool *TransactionPool) PopWithBlacklist(fromBlacklist *sync.Map, toBlackli
 			if _, ok := toBlacklist.Load(tx.to.address.Hex()); !ok {
 				pool.candidates.Del(tx)
 				pool.popTx(tx)
+				pool.markPacking(tx)
 				return tx
 			}
 		}
@@ -428,11 +781,28 @@ func (pool *TransactionPool) Pop() *Transaction {
 	return tx
 }
 
+func (pool *TransactionPool) markPacking(tx *Transaction) {
+	key := nonceKey{from: tx.from.address.Hex(), nonce: tx.nonce}
+	pool.packing[key] = &packingTx{tx: tx, poppedAt: time.Now()}
+}
+
+// dropPacking clear the packing mark of tx which is neither packed nor given back, so it can be replaced.
+func (pool *TransactionPool) dropPacking(tx *Transaction) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	delete(pool.packing, nonceKey{from: tx.from.address.Hex(), nonce: tx.nonce})
+}
+
 // Del a transaction from pool
 func (pool *TransactionPool) Del(tx *Transaction) {
 	pool.mu.Lock()
 	defer pool.mu.Unlock()
 
+	pool.notifyOnChain(tx)
+	delete(pool.packing, nonceKey{from: tx.from.address.Hex(), nonce: tx.nonce})
+	delete(pool.locals, tx.hash.Hex())
+
 	bucket := pool.buckets[tx.from.address.Hex()]
 	if bucket != nil && bucket.Len() > 0 {
 		oldCandidate := bucket.Left()
@@ -440,6 +810,10 @@ func (pool *TransactionPool) Del(tx *Transaction) {
 		for left.Nonce() <= tx.Nonce() {
 			bucket.PopLeft()
 			delete(pool.all, left.Hash().Hex())
+			delete(pool.locals, left.Hash().Hex())
+			if !left.Hash().Equals(tx.Hash()) {
+				pool.notifyDropped(left, DropReasonNonceUsed)
+			}
 
 			// trigger pending transaction
 			event := &state.Event{
@@ -493,6 +867,13 @@ func (pool *TransactionPool) evictExpiredTransactions() {
 	pool.mu.Lock()
 	defer pool.mu.Unlock()
 
+	// packing txs neither given back nor on chain, e.g. failed in execution.
+	for key, packing := range pool.packing {
+		if time.Since(packing.poppedAt) > txLifetime {
+			delete(pool.packing, key)
+		}
+	}
+
 	for slot := range pool.buckets {
 		if timeLastDate, ok := pool.bucketsLastUpdate[slot]; ok {
 			if time.Since(timeLastDate) > txLifetime {
@@ -518,6 +899,7 @@ func (pool *TransactionPool) evictExpiredTransactions() {
 							Data:  tx.String(),
 						}
 						pool.eventEmitter.Trigger(event)
+						pool.notifyDropped(tx, DropReasonExpired)
 					}
 
 					val = bucket.PopLeft()
@@ -0,0 +1,168 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"github.com/alexlisong/go-nebulas/core/state"
+	"github.com/alexlisong/go-nebulas/util"
+	"github.com/alexlisong/go-nebulas/util/byteutils"
+	"github.com/alexlisong/go-nebulas/util/logging"
+	"github.com/sirupsen/logrus"
+)
+
+// TxPoolStats is a summary of the txs in the pool.
+type TxPoolStats struct {
+	Pending  int `json:"pending"`
+	Queued   int `json:"queued"`
+	Packing  int `json:"packing"`
+	Accounts int `json:"accounts"`
+
+	// the gasPrice range of the txs in the pool, nil if the pool is empty.
+	MinGasPrice *util.Uint128 `json:"min_gas_price"`
+	MaxGasPrice *util.Uint128 `json:"max_gas_price"`
+}
+
+// poolSnapshot is a copy of the pool buckets, so the pool lock isn't held while reading account states.
+type poolSnapshot struct {
+	buckets map[byteutils.HexHash]Transactions
+	packing map[nonceKey]*Transaction
+}
+
+// snapshot copies the txs of addr sorted by nonce, or of all accounts if addr is nil.
+func (pool *TransactionPool) snapshot(addr *Address) *poolSnapshot {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+	return pool.snapshotLocked(addr)
+}
+
+func (pool *TransactionPool) snapshotLocked(addr *Address) *poolSnapshot {
+	snap := &poolSnapshot{
+		buckets: make(map[byteutils.HexHash]Transactions),
+		packing: make(map[nonceKey]*Transaction),
+	}
+	for slot, bucket := range pool.buckets {
+		if addr != nil && slot != addr.address.Hex() {
+			continue
+		}
+		txs := make(Transactions, bucket.Len())
+		for i := 0; i < bucket.Len(); i++ {
+			txs[i] = bucket.Index(i).(*Transaction)
+		}
+		snap.buckets[slot] = txs
+	}
+	for key, packing := range pool.packing {
+		if addr == nil || key.from == addr.address.Hex() {
+			snap.packing[key] = packing.tx
+		}
+	}
+	return snap
+}
+
+// split separates the txs of each account into pending ones, continuous from the account nonce
+// on tail block or txs being packed, and queued ones after a nonce gap.
+// Without the account state, txs are continuous from the lowest nonce in the pool.
+func (snap *poolSnapshot) split(ws state.WorldState) (map[string]Transactions, map[string]Transactions) {
+	pending := make(map[string]Transactions)
+	queued := make(map[string]Transactions)
+	for slot, txs := range snap.buckets {
+		if len(txs) == 0 {
+			continue
+		}
+		from := txs[0].from
+		nonce := txs[0].nonce - 1
+		if ws != nil {
+			if _, accNonce, err := getUserAccountState(ws, from.address); err == nil {
+				nonce = accNonce
+			}
+		}
+
+		gap := false
+		for _, tx := range txs {
+			for snap.packing[nonceKey{from: slot, nonce: nonce + 1}] != nil {
+				nonce++
+			}
+			if tx.nonce > nonce+1 {
+				gap = true
+			}
+			if gap {
+				queued[from.String()] = append(queued[from.String()], tx)
+				continue
+			}
+			pending[from.String()] = append(pending[from.String()], tx)
+			if tx.nonce == nonce+1 {
+				nonce++
+			}
+		}
+	}
+	return pending, queued
+}
+
+// tailWorldState return a copy of the tail block's world state, or nil if it's unavailable.
+func (pool *TransactionPool) tailWorldState() state.WorldState {
+	if pool.bc == nil {
+		return nil
+	}
+	snapshot, err := pool.bc.TailSnapshot()
+	if err != nil {
+		logging.VLog().WithFields(logrus.Fields{
+			"err": err,
+		}).Debug("Failed to clone tail world state for pool content.")
+		return nil
+	}
+	return snapshot.WorldState()
+}
+
+// PendingByAddress return a copy of the pending txs of addr sorted by nonce.
+func (pool *TransactionPool) PendingByAddress(addr *Address) Transactions {
+	pending, _ := pool.snapshot(addr).split(pool.tailWorldState())
+	return pending[addr.String()]
+}
+
+// PoolContent return copies of the pending and queued txs in the pool by from address, sorted by nonce.
+func (pool *TransactionPool) PoolContent() (pending, queued map[string]Transactions) {
+	return pool.snapshot(nil).split(pool.tailWorldState())
+}
+
+// Stats return the summary of the txs in the pool.
+func (pool *TransactionPool) Stats() *TxPoolStats {
+	snap := pool.snapshot(nil)
+	pending, queued := snap.split(pool.tailWorldState())
+
+	stats := &TxPoolStats{
+		Packing:  len(snap.packing),
+		Accounts: len(snap.buckets),
+	}
+	for _, txs := range pending {
+		stats.Pending += len(txs)
+	}
+	for _, txs := range queued {
+		stats.Queued += len(txs)
+	}
+	for _, txs := range snap.buckets {
+		for _, tx := range txs {
+			if stats.MinGasPrice == nil || tx.gasPrice.Cmp(stats.MinGasPrice) < 0 {
+				stats.MinGasPrice = tx.gasPrice
+			}
+			if stats.MaxGasPrice == nil || tx.gasPrice.Cmp(stats.MaxGasPrice) > 0 {
+				stats.MaxGasPrice = tx.gasPrice
+			}
+		}
+	}
+	return stats
+}
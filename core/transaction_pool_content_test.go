@@ -0,0 +1,106 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/alexlisong/go-nebulas/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionPool_PoolContent(t *testing.T) {
+	bc := testNeb(t).chain
+	txPool, _ := NewTransactionPool(16)
+	txPool.setBlockChain(bc)
+	txPool.setEventEmitter(bc.eventEmitter)
+
+	from, other := mockAddress(), mockAddress()
+
+	gasLimit, _ := util.NewUint128FromInt(200000)
+	newTx := func(addr *Address, nonce uint64, percent uint64) *Transaction {
+		gasPrice, _ := TransactionGasPrice.Mul(util.NewUint128FromUint(percent))
+		gasPrice, _ = gasPrice.Div(util.NewUint128FromUint(100))
+		tx, _ := NewTransaction(bc.ChainID(), addr, mockAddress(), util.NewUint128(), nonce, TxPayloadBinaryType, nil, gasPrice, gasLimit)
+		assert.Nil(t, tx.Sign(mockSignature(t, addr)))
+		return tx
+	}
+	nonces := func(txs Transactions) []uint64 {
+		var res []uint64
+		for _, tx := range txs {
+			res = append(res, tx.Nonce())
+		}
+		return res
+	}
+
+	stats := txPool.Stats()
+	assert.Equal(t, 0, stats.Accounts)
+	assert.Nil(t, stats.MinGasPrice)
+	assert.Nil(t, stats.MaxGasPrice)
+
+	// nonce gap at 3, pushed out of order.
+	for _, nonce := range []uint64{5, 2, 1, 4} {
+		assert.Nil(t, txPool.Push(newTx(from, nonce, 100)))
+	}
+	assert.Nil(t, txPool.Push(newTx(other, 2, 100)))
+
+	// replace nonce 2.
+	replacing := newTx(from, 2, 200)
+	assert.Nil(t, txPool.Push(replacing))
+
+	pending, queued := txPool.PoolContent()
+	assert.Equal(t, []uint64{1, 2}, nonces(pending[from.String()]))
+	assert.Equal(t, replacing, pending[from.String()][1])
+	assert.Equal(t, []uint64{4, 5}, nonces(queued[from.String()]))
+	assert.Nil(t, pending[other.String()])
+	assert.Equal(t, []uint64{2}, nonces(queued[other.String()]))
+	assert.Equal(t, []uint64{1, 2}, nonces(txPool.PendingByAddress(from)))
+	assert.Nil(t, txPool.PendingByAddress(other))
+
+	// returned txs are copies of the pool buckets.
+	pending[from.String()][0] = nil
+	assert.NotNil(t, txPool.PendingByAddress(from)[0])
+
+	stats = txPool.Stats()
+	assert.Equal(t, 2, stats.Pending)
+	assert.Equal(t, 3, stats.Queued)
+	assert.Equal(t, 0, stats.Packing)
+	assert.Equal(t, 2, stats.Accounts)
+	assert.Equal(t, 0, TransactionGasPrice.Cmp(stats.MinGasPrice))
+	assert.Equal(t, 0, replacing.GasPrice().Cmp(stats.MaxGasPrice))
+
+	// txs being packed keep the nonces continuous.
+	fromBlacklist := new(sync.Map)
+	fromBlacklist.Store(other.address.Hex(), true)
+	packing := txPool.PopWithBlacklist(fromBlacklist, nil)
+	assert.Equal(t, uint64(1), packing.Nonce())
+	packing = txPool.PopWithBlacklist(fromBlacklist, nil)
+	assert.Equal(t, replacing, packing)
+	assert.Nil(t, txPool.Push(newTx(from, 3, 100)))
+
+	pending, queued = txPool.PoolContent()
+	assert.Equal(t, []uint64{3, 4, 5}, nonces(pending[from.String()]))
+	assert.Nil(t, queued[from.String()])
+
+	stats = txPool.Stats()
+	assert.Equal(t, 3, stats.Pending)
+	assert.Equal(t, 1, stats.Queued)
+	assert.Equal(t, 2, stats.Packing)
+}
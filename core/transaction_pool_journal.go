@@ -0,0 +1,219 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"time"
+
+	"github.com/alexlisong/go-nebulas/storage"
+	"github.com/alexlisong/go-nebulas/util/byteutils"
+	"github.com/alexlisong/go-nebulas/util/logging"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultTxJournalSize max count of txs in journal.
+	DefaultTxJournalSize = 4096
+
+	// DefaultTxJournalRate max count of txs journaled per second.
+	DefaultTxJournalRate = 100
+
+	// TxJournalHead the count of txs ever journaled in storage
+	TxJournalHead = "txpool_journal_head"
+
+	// TxJournalKeyPrefix prefix of the journal slots in storage
+	TxJournalKeyPrefix = "txj_"
+)
+
+// txJournal is a ring of slots in storage, the txs accepted by pool are appended,
+// the oldest ones are overwritten when it's full.
+type txJournal struct {
+	size uint64
+	rate int
+	head uint64
+
+	// the journaled count in the second of window.
+	window  int64
+	written int
+	skipped uint64
+
+	replaying bool
+}
+
+func txJournalStorageKey(slot uint64) []byte {
+	return append([]byte(TxJournalKeyPrefix), byteutils.FromUint64(slot)...)
+}
+
+// EnableJournal journal the txs accepted by pool in storage to restore them on restart, 0 for default.
+func (pool *TransactionPool) EnableJournal(size, rate uint32) error {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	if size == 0 {
+		size = DefaultTxJournalSize
+	}
+	if rate == 0 {
+		rate = DefaultTxJournalRate
+	}
+	head := uint64(0)
+	bytes, err := pool.bc.storage.Get([]byte(TxJournalHead))
+	if err != nil && err != storage.ErrKeyNotFound {
+		return err
+	}
+	if err == nil {
+		head = byteutils.Uint64(bytes)
+	}
+	pool.journal = &txJournal{
+		size: uint64(size),
+		rate: int(rate),
+		head: head,
+	}
+	return nil
+}
+
+// journalTx append the accepted tx to journal, at most rate txs are journaled per second.
+func (pool *TransactionPool) journalTx(tx *Transaction) {
+	j := pool.journal
+	if j == nil || j.replaying {
+		return
+	}
+
+	if now := time.Now().Unix(); now != j.window {
+		j.window = now
+		j.written = 0
+	}
+	if j.written >= j.rate {
+		j.skipped++
+		return
+	}
+
+	bytes, err := tx.ToBytes()
+	if err == nil {
+		err = pool.bc.storage.Put(txJournalStorageKey(j.head%j.size), bytes)
+	}
+	if err == nil {
+		err = pool.bc.storage.Put([]byte(TxJournalHead), byteutils.FromUint64(j.head+1))
+	}
+	if err != nil {
+		logging.VLog().WithFields(logrus.Fields{
+			"tx":  tx,
+			"err": err,
+		}).Debug("Failed to journal tx.")
+		return
+	}
+	j.head++
+	j.written++
+}
+
+// ReplayJournal push the journaled txs into pool again, the ones invalid on tail are dropped from journal.
+// It return the count of restored txs.
+func (pool *TransactionPool) ReplayJournal() (int, error) {
+	pool.mu.Lock()
+	j := pool.journal
+	if j == nil {
+		pool.mu.Unlock()
+		return 0, nil
+	}
+	j.replaying = true
+	pool.mu.Unlock()
+
+	defer func() {
+		pool.mu.Lock()
+		j.replaying = false
+		pool.mu.Unlock()
+	}()
+
+	tail := pool.bc.TailBlock()
+	ws, err := tail.WorldState().Clone()
+	if err != nil {
+		return 0, err
+	}
+
+	restored := 0
+	for slot := uint64(0); slot < j.size && slot < j.head; slot++ {
+		key := txJournalStorageKey(slot)
+		bytes, err := pool.bc.storage.Get(key)
+		if err != nil {
+			if err == storage.ErrKeyNotFound {
+				continue
+			}
+			return restored, err
+		}
+
+		tx := new(Transaction)
+		if err = tx.FromBytes(bytes); err == nil {
+			err = pool.checkJournaledTx(tx, tail, ws)
+		}
+		if err == nil {
+			err = pool.Push(tx)
+		}
+		if err != nil {
+			logging.VLog().WithFields(logrus.Fields{
+				"tx":  tx,
+				"err": err,
+			}).Debug("Dropped journaled tx.")
+			if err := pool.bc.storage.Del(key); err != nil {
+				return restored, err
+			}
+			continue
+		}
+		restored++
+	}
+
+	logging.CLog().WithFields(logrus.Fields{
+		"tail":     tail,
+		"restored": restored,
+	}).Info("Replayed tx journal.")
+	return restored, nil
+}
+
+// checkJournaledTx check the tx is still packable after tail, the ones with stale nonce
+// or insufficient balance are invalid.
+func (pool *TransactionPool) checkJournaledTx(tx *Transaction, tail *Block, ws WorldState) error {
+	if _, err := CheckTransaction(tx, tail, ws); err != nil && !TxErrorGiveback(err) {
+		return err
+	}
+
+	fromBalance, _, err := getUserAccountState(ws, tx.from.address)
+	if err != nil {
+		return err
+	}
+	cost, err := tx.Cost()
+	if err != nil {
+		return err
+	}
+	if tx.payer != nil {
+		payerBalance, _, err := getUserAccountState(ws, tx.payer.address)
+		if err != nil {
+			return err
+		}
+		fee, err := tx.Fee()
+		if err != nil {
+			return err
+		}
+		if payerBalance.Cmp(fee) < 0 {
+			return ErrInsufficientBalance
+		}
+		cost = tx.value
+	}
+	if fromBalance.Cmp(cost) < 0 {
+		return ErrInsufficientBalance
+	}
+	return nil
+}
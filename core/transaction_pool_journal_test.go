@@ -0,0 +1,143 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alexlisong/go-nebulas/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionPool_ReplayJournal(t *testing.T) {
+	neb := testNeb(t)
+	bc := neb.chain
+	assert.Nil(t, bc.txPool.EnableJournal(0, 0))
+
+	from := mockAddress()
+	balance, _ := util.NewUint128FromString("1000000000000000000")
+	bc.tailBlock.Begin()
+	fromAcc, err := bc.tailBlock.worldState.GetOrCreateUserAccount(from.address)
+	assert.Nil(t, err)
+	assert.Nil(t, fromAcc.AddBalance(balance))
+	bc.tailBlock.Commit()
+	bc.tailBlock.header.stateRoot = bc.tailBlock.worldState.AccountsRoot()
+	assert.Nil(t, bc.StoreBlockToStorage(bc.tailBlock))
+
+	gasLimit, _ := util.NewUint128FromInt(200000)
+	newTx := func(from *Address, nonce uint64) *Transaction {
+		tx, err := NewTransaction(bc.ChainID(), from, from, util.NewUint128(), nonce, TxPayloadBinaryType, nil, TransactionGasPrice, gasLimit)
+		assert.Nil(t, err)
+		assert.Nil(t, tx.Sign(mockSignature(t, from)))
+		return tx
+	}
+
+	// tx1 is mined before restart, its nonce is stale then.
+	tx1 := newTx(from, 1)
+	assert.Nil(t, bc.txPool.Push(tx1))
+	coinbase := mockAddress()
+	block, err := bc.NewBlock(coinbase)
+	assert.Nil(t, err)
+	block.CollectTransactions(time.Now().Unix() + 1)
+	assert.Equal(t, 1, len(block.transactions))
+	assert.Nil(t, block.Seal())
+	assert.Nil(t, block.Sign(mockSignature(t, coinbase)))
+	assert.Nil(t, bc.BlockPool().Push(block))
+	assert.Equal(t, block.Hash(), bc.TailBlock().Hash())
+
+	// the sender of poor can't pay the gas.
+	tx2, tx3 := newTx(from, 2), newTx(from, 3)
+	poor := newTx(mockAddress(), 1)
+	for _, tx := range []*Transaction{tx2, tx3, poor} {
+		assert.Nil(t, bc.txPool.Push(tx))
+	}
+	assert.Equal(t, uint64(4), bc.txPool.journal.head)
+
+	// restart the chain with the same storage.
+	reloaded := &mockNeb{
+		genesis:   neb.genesis,
+		config:    neb.config,
+		storage:   neb.storage,
+		emitter:   NewEventEmitter(1024),
+		consensus: new(mockConsensus),
+		am:        neb.am,
+		ns:        neb.ns,
+		nvm:       neb.nvm,
+	}
+	reloaded.chain, err = NewBlockChain(reloaded)
+	assert.Nil(t, err)
+	assert.Nil(t, reloaded.consensus.Setup(reloaded))
+	assert.Nil(t, reloaded.chain.Setup(reloaded))
+	pool := reloaded.chain.txPool
+	assert.Nil(t, pool.EnableJournal(0, 0))
+	assert.True(t, pool.Empty())
+
+	restored, err := pool.ReplayJournal()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, restored)
+	assert.NotNil(t, pool.GetTransaction(tx2.Hash()))
+	assert.NotNil(t, pool.GetTransaction(tx3.Hash()))
+	assert.Nil(t, pool.GetTransaction(tx1.Hash()))
+	assert.Nil(t, pool.GetTransaction(poor.Hash()))
+
+	// the restored txs are not journaled again, the dropped ones are removed from journal.
+	assert.Equal(t, uint64(4), pool.journal.head)
+	_, err = reloaded.storage.Get(txJournalStorageKey(0))
+	assert.NotNil(t, err)
+
+	block, err = reloaded.chain.NewBlock(coinbase)
+	assert.Nil(t, err)
+	block.CollectTransactions(time.Now().Unix() + 1)
+	assert.Equal(t, 2, len(block.transactions))
+}
+
+func TestTransactionPool_JournalBounds(t *testing.T) {
+	bc := testNeb(t).chain
+	assert.Nil(t, bc.txPool.EnableJournal(2, 1))
+	journal := bc.txPool.journal
+
+	txs := []*Transaction{}
+	for i := 0; i < 4; i++ {
+		tx := mockNormalTransaction(bc.ChainID(), 1)
+		assert.Nil(t, tx.Sign(mockSignature(t, tx.from)))
+		txs = append(txs, tx)
+	}
+
+	// at most rate txs are journaled in a second.
+	assert.Nil(t, bc.txPool.Push(txs[0]))
+	journal.window = time.Now().Unix()
+	assert.Nil(t, bc.txPool.Push(txs[1]))
+	assert.Equal(t, uint64(1), journal.head)
+	assert.Equal(t, uint64(1), journal.skipped)
+
+	// the oldest slot is overwritten when journal is full.
+	for _, tx := range txs[2:] {
+		journal.window = 0
+		assert.Nil(t, bc.txPool.Push(tx))
+	}
+	assert.Equal(t, uint64(3), journal.head)
+	for slot, expect := range []*Transaction{txs[3], txs[2]} {
+		bytes, err := bc.storage.Get(txJournalStorageKey(uint64(slot)))
+		assert.Nil(t, err)
+		tx := new(Transaction)
+		assert.Nil(t, tx.FromBytes(bytes))
+		assert.Equal(t, expect.Hash(), tx.Hash())
+	}
+}
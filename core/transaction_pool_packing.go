@@ -0,0 +1,112 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"github.com/alexlisong/go-nebulas/util"
+)
+
+// SetPackingLimit config the max gas used and the max count of txs packed in a proposing block,
+// nil or 0 for no limit.
+func (pool *TransactionPool) SetPackingLimit(gasLimit *util.Uint128, maxTxs uint32) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	if gasLimit != nil && gasLimit.Cmp(util.NewUint128()) == 0 {
+		gasLimit = nil
+	}
+	pool.packingGasLimit = gasLimit
+	pool.packingMaxTxs = maxTxs
+}
+
+// newPackingQuota return the quota of a proposing block, limited by the block gas limit of chain if activated.
+func (pool *TransactionPool) newPackingQuota(block *Block) *packingQuota {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	gasLimit := pool.packingGasLimit
+	if chainGasLimit := block.gasLimitOfChain(); chainGasLimit != nil {
+		if gasLimit == nil || chainGasLimit.Cmp(gasLimit) < 0 {
+			gasLimit = chainGasLimit
+		}
+	}
+	return &packingQuota{
+		gasLimit: gasLimit,
+		maxTxs:   pool.packingMaxTxs,
+		gas:      util.NewUint128(),
+	}
+}
+
+// packingQuota tracks the gas and count of txs packed or being packed in a proposing block, not thread safe.
+// The gasLimit of a tx being packed is reserved, so the gas used by txs never exceeds the limit.
+type packingQuota struct {
+	gasLimit *util.Uint128 // nil for no limit.
+	maxTxs   uint32        // 0 for no limit.
+
+	gas *util.Uint128
+	txs uint32
+}
+
+// reserve take the quota of tx, return false if the block can't hold it.
+func (q *packingQuota) reserve(tx *Transaction) bool {
+	if q.maxTxs > 0 && q.txs >= q.maxTxs {
+		return false
+	}
+	gas, err := q.gas.Add(tx.gasLimit)
+	if err != nil {
+		return false
+	}
+	if q.gasLimit != nil && gas.Cmp(q.gasLimit) > 0 {
+		return false
+	}
+	q.gas = gas
+	q.txs++
+	return true
+}
+
+// release give back the quota of a tx failed to be packed.
+func (q *packingQuota) release(tx *Transaction) {
+	if gas, err := q.gas.Sub(tx.gasLimit); err == nil {
+		q.gas = gas
+	}
+	if q.txs > 0 {
+		q.txs--
+	}
+}
+
+// settle replace the reserved gasLimit of a packed tx with its gas used.
+func (q *packingQuota) settle(tx *Transaction, gasUsed *util.Uint128) {
+	if gas, err := q.gas.Sub(tx.gasLimit); err == nil {
+		if gas, err = gas.Add(gasUsed); err == nil {
+			q.gas = gas
+		}
+	}
+}
+
+// full return true if no more tx can be packed.
+func (q *packingQuota) full() bool {
+	if q.maxTxs > 0 && q.txs >= q.maxTxs {
+		return true
+	}
+	if q.gasLimit == nil {
+		return false
+	}
+	left, err := q.gasLimit.Sub(q.gas)
+	return err != nil || left.Cmp(MinGasCountPerTransaction) < 0
+}
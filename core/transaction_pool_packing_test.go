@@ -0,0 +1,82 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alexlisong/go-nebulas/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlock_CollectTransactionsByPrice(t *testing.T) {
+	neb := testNeb(t)
+	bc := neb.chain
+
+	senderA, senderB, senderC, unfunded := mockAddress(), mockAddress(), mockAddress(), mockAddress()
+
+	balance, _ := util.NewUint128FromString("1000000000000000000")
+	bc.tailBlock.Begin()
+	for _, addr := range []*Address{senderA, senderB, senderC} {
+		acc, err := bc.tailBlock.worldState.GetOrCreateUserAccount(addr.address)
+		assert.Nil(t, err)
+		assert.Nil(t, acc.AddBalance(balance))
+	}
+	bc.tailBlock.Commit()
+	bc.tailBlock.header.stateRoot = bc.tailBlock.worldState.AccountsRoot()
+	assert.Nil(t, bc.StoreBlockToStorage(bc.tailBlock))
+
+	gasLimit, _ := util.NewUint128FromInt(200000)
+	now := time.Now().Unix()
+	newTx := func(from *Address, nonce uint64, price uint64, ahead int64) *Transaction {
+		gasPrice, _ := TransactionGasPrice.Mul(util.NewUint128FromUint(price))
+		tx, _ := NewTransaction(bc.ChainID(), from, mockAddress(), util.NewUint128(), nonce, TxPayloadBinaryType, nil, gasPrice, gasLimit)
+		tx.timestamp = now + ahead
+		assert.Nil(t, tx.Sign(mockSignature(t, from)))
+		assert.Nil(t, bc.txPool.Push(tx))
+		return tx
+	}
+
+	a1 := newTx(senderA, 1, 1, 0)
+	a2 := newTx(senderA, 2, 5, 1)
+	b1 := newTx(senderB, 1, 3, 2)
+	b2 := newTx(senderB, 2, 3, 5)
+	c1 := newTx(senderC, 1, 3, 3)
+	c2 := newTx(senderC, 2, 2, 4)
+	// the highest priced sender can't afford its txs, it's skipped entirely.
+	newTx(unfunded, 1, 10, 0)
+	d2 := newTx(unfunded, 2, 10, 1)
+
+	// txs are packed by the price of each sender's lowest nonce, same price by timestamp.
+	bc.txPool.SetPackingLimit(nil, 4)
+	block, err := bc.NewBlock(bc.tailBlock.header.coinbase)
+	assert.Nil(t, err)
+	block.CollectTransactions(time.Now().Unix()*1000 + 1000)
+	assert.Equal(t, Transactions{b1, c1, b2, c2}, Transactions(block.transactions))
+	assert.NotNil(t, bc.txPool.GetTransaction(d2.Hash()))
+
+	// the block gas limit holds one more tx.
+	bc.txPool.SetPackingLimit(gasLimit, 0)
+	block, err = bc.NewBlock(bc.tailBlock.header.coinbase)
+	assert.Nil(t, err)
+	block.CollectTransactions(time.Now().Unix()*1000 + 1000)
+	assert.Equal(t, Transactions{a1}, Transactions(block.transactions))
+	assert.NotNil(t, bc.txPool.GetTransaction(a2.Hash()))
+}
@@ -0,0 +1,129 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"github.com/alexlisong/go-nebulas/util"
+	"github.com/alexlisong/go-nebulas/util/logging"
+	"github.com/sirupsen/logrus"
+)
+
+// SetReserveQueued config if the queued txs after a nonce gap are counted in PendingBalance.
+func (pool *TransactionPool) SetReserveQueued(reserve bool) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	pool.reserveQueued = reserve
+}
+
+// accountReservation is the nonce and balance of an account on tail block with its txs in pool.
+type accountReservation struct {
+	balance  *util.Uint128
+	nonce    uint64
+	pending  Transactions
+	queued   Transactions
+	reserved *util.Uint128
+}
+
+// reservation copies the txs of addr in pool and the tail world state under the pool lock,
+// so the txs dropped from pool on a new tail are either on the tail or in the copy.
+func (pool *TransactionPool) reservation(addr *Address) (*accountReservation, error) {
+	pool.mu.RLock()
+	snap := pool.snapshotLocked(addr)
+	ws := pool.tailWorldState()
+	reserveQueued := pool.reserveQueued
+	pool.mu.RUnlock()
+
+	res := &accountReservation{balance: util.NewUint128(), reserved: util.NewUint128()}
+	if ws != nil {
+		balance, nonce, err := getUserAccountState(ws, addr.address)
+		if err != nil {
+			return nil, err
+		}
+		res.balance, res.nonce = balance, nonce
+	}
+
+	// one tx a nonce, the txs on tail or replaced are skipped.
+	txs := make(map[uint64]*Transaction)
+	for _, tx := range snap.packing {
+		txs[tx.nonce] = tx
+	}
+	for _, tx := range snap.buckets[addr.address.Hex()] {
+		if old, ok := txs[tx.nonce]; ok && old.gasPrice.Cmp(tx.gasPrice) >= 0 {
+			continue
+		}
+		txs[tx.nonce] = tx
+	}
+
+	nonce := res.nonce
+	for ; txs[nonce+1] != nil; nonce++ {
+		res.pending = append(res.pending, txs[nonce+1])
+	}
+	for _, tx := range snap.buckets[addr.address.Hex()] {
+		if tx.nonce > nonce+1 && txs[tx.nonce] == tx {
+			res.queued = append(res.queued, tx)
+		}
+	}
+
+	reserved := res.pending
+	if reserveQueued {
+		reserved = append(append(Transactions{}, res.pending...), res.queued...)
+	}
+	for _, tx := range reserved {
+		// the payer pays the fee of tx.
+		cost := tx.value
+		if tx.payer == nil {
+			var err error
+			if cost, err = tx.Cost(); err != nil {
+				return nil, err
+			}
+		}
+		var err error
+		if res.reserved, err = res.reserved.Add(cost); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// PendingNonce return the nonce of addr on tail block plus its continuous txs being packed or pending in pool,
+// the nonce of the next tx is PendingNonce() + 1.
+func (pool *TransactionPool) PendingNonce(addr *Address) uint64 {
+	res, err := pool.reservation(addr)
+	if err != nil {
+		logging.VLog().WithFields(logrus.Fields{
+			"addr": addr,
+			"err":  err,
+		}).Debug("Failed to get the pending nonce.")
+		return 0
+	}
+	return res.nonce + uint64(len(res.pending))
+}
+
+// PendingBalance return the balance of addr on tail block minus the value and max fee of its pending txs in pool,
+// 0 if they spend more than the balance. The queued txs are counted if SetReserveQueued.
+func (pool *TransactionPool) PendingBalance(addr *Address) (*util.Uint128, error) {
+	res, err := pool.reservation(addr)
+	if err != nil {
+		return nil, err
+	}
+	if res.balance.Cmp(res.reserved) <= 0 {
+		return util.NewUint128(), nil
+	}
+	return res.balance.Sub(res.reserved)
+}
@@ -0,0 +1,108 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/alexlisong/go-nebulas/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionPool_PendingNonceAndBalance(t *testing.T) {
+	bc := testNeb(t).chain
+	txPool, _ := NewTransactionPool(16)
+	txPool.setBlockChain(bc)
+	txPool.setEventEmitter(bc.eventEmitter)
+
+	from := mockAddress()
+	balance, _ := util.NewUint128FromString("1000000000000000000")
+	bc.tailBlock.Begin()
+	acc, err := bc.tailBlock.worldState.GetOrCreateUserAccount(from.address)
+	assert.Nil(t, err)
+	assert.Nil(t, acc.AddBalance(balance))
+	acc.IncrNonce()
+	bc.tailBlock.Commit()
+
+	gasLimit, _ := util.NewUint128FromInt(200000)
+	newTx := func(nonce uint64, percent uint64) *Transaction {
+		gasPrice, _ := TransactionGasPrice.Mul(util.NewUint128FromUint(percent))
+		gasPrice, _ = gasPrice.Div(util.NewUint128FromUint(100))
+		tx, _ := NewTransaction(bc.ChainID(), from, mockAddress(), util.NewUint128FromUint(1000), nonce, TxPayloadBinaryType, nil, gasPrice, gasLimit)
+		assert.Nil(t, tx.Sign(mockSignature(t, from)))
+		return tx
+	}
+	reserved := func(txs ...*Transaction) *util.Uint128 {
+		left := balance
+		for _, tx := range txs {
+			cost, err := tx.Cost()
+			assert.Nil(t, err)
+			left, err = left.Sub(cost)
+			assert.Nil(t, err)
+		}
+		return left
+	}
+
+	assert.Equal(t, uint64(1), txPool.PendingNonce(from))
+	pendingBalance, err := txPool.PendingBalance(from)
+	assert.Nil(t, err)
+	assert.Equal(t, balance, pendingBalance)
+
+	// nonce 3 is replaced, nonce 5 is queued after a gap.
+	tx2, tx3, tx5 := newTx(2, 100), newTx(3, 100), newTx(5, 100)
+	for _, tx := range []*Transaction{tx2, tx3, tx5} {
+		assert.Nil(t, txPool.Push(tx))
+	}
+	replacing := newTx(3, 200)
+	assert.Nil(t, txPool.Push(replacing))
+
+	assert.Equal(t, uint64(3), txPool.PendingNonce(from))
+	pendingBalance, err = txPool.PendingBalance(from)
+	assert.Nil(t, err)
+	assert.Equal(t, reserved(tx2, replacing), pendingBalance)
+
+	txPool.SetReserveQueued(true)
+	pendingBalance, err = txPool.PendingBalance(from)
+	assert.Nil(t, err)
+	assert.Equal(t, reserved(tx2, replacing, tx5), pendingBalance)
+	txPool.SetReserveQueued(false)
+
+	// the txs being packed are still reserved.
+	packing := txPool.PopWithBlacklist(new(sync.Map), nil)
+	assert.Equal(t, tx2, packing)
+	assert.Equal(t, uint64(3), txPool.PendingNonce(from))
+	pendingBalance, err = txPool.PendingBalance(from)
+	assert.Nil(t, err)
+	assert.Equal(t, reserved(tx2, replacing), pendingBalance)
+
+	// filling the gap makes the queued tx pending.
+	tx4 := newTx(4, 100)
+	assert.Nil(t, txPool.Push(tx4))
+	assert.Equal(t, uint64(5), txPool.PendingNonce(from))
+	pendingBalance, err = txPool.PendingBalance(from)
+	assert.Nil(t, err)
+	assert.Equal(t, reserved(tx2, replacing, tx4, tx5), pendingBalance)
+
+	unknown := mockAddress()
+	assert.Equal(t, uint64(0), txPool.PendingNonce(unknown))
+	pendingBalance, err = txPool.PendingBalance(unknown)
+	assert.Nil(t, err)
+	assert.True(t, pendingBalance.Cmp(util.NewUint128()) == 0)
+}
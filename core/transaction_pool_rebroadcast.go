@@ -0,0 +1,122 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"sync/atomic"
+
+	"github.com/alexlisong/go-nebulas/net"
+	"github.com/alexlisong/go-nebulas/util/logging"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultTxRebroadcastBlocks is the default count of blocks a local tx can stay pending before it's re-broadcast.
+	DefaultTxRebroadcastBlocks = 5
+
+	// DefaultTxRebroadcastMaxRetries is the default max count of re-broadcast attempts of a local tx.
+	DefaultTxRebroadcastMaxRetries = 5
+)
+
+// localTx is a tx submitted through the node's own API, it's re-broadcast until it's on chain,
+// in case the initial broadcast was lost.
+type localTx struct {
+	tx         *Transaction
+	attempts   uint32
+	nextHeight uint64 // the tail height to re-broadcast it at.
+}
+
+// SetRebroadcast config the count of blocks a local tx can stay pending before it's re-broadcast,
+// and the max count of attempts, 0 for default.
+func (pool *TransactionPool) SetRebroadcast(blocks, maxRetries uint32) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	if blocks == 0 {
+		blocks = DefaultTxRebroadcastBlocks
+	}
+	if maxRetries == 0 {
+		maxRetries = DefaultTxRebroadcastMaxRetries
+	}
+	pool.rebroadcastBlocks = uint64(blocks)
+	pool.rebroadcastMaxRetries = maxRetries
+}
+
+// RebroadcastedTransactions return the count of re-broadcast attempts of local txs.
+func (pool *TransactionPool) RebroadcastedTransactions() uint64 {
+	return atomic.LoadUint64(&pool.rebroadcastedTxs)
+}
+
+// trackLocal start tracking a local tx accepted by the pool.
+func (pool *TransactionPool) trackLocal(tx *Transaction) {
+	height := pool.bc.TailBlock().height
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	pool.locals[tx.hash.Hex()] = &localTx{
+		tx:         tx,
+		nextHeight: height + pool.rebroadcastBlocks,
+	}
+}
+
+// rebroadcastLocalTxs broadcast the local txs still pending, the interval is doubled after each attempt.
+func (pool *TransactionPool) rebroadcastLocalTxs() {
+	height := pool.bc.TailBlock().height
+
+	var txs []*Transaction
+	pool.mu.Lock()
+	for hash, local := range pool.locals {
+		if _, ok := pool.all[hash]; !ok {
+			// txs being packed are tracked until they are on chain or given back.
+			key := nonceKey{from: local.tx.from.address.Hex(), nonce: local.tx.nonce}
+			if packing, ok := pool.packing[key]; !ok || !packing.tx.hash.Equals(local.tx.hash) {
+				delete(pool.locals, hash)
+			}
+			continue
+		}
+		if height < local.nextHeight {
+			continue
+		}
+		if local.attempts >= pool.rebroadcastMaxRetries {
+			delete(pool.locals, hash)
+			logging.VLog().WithFields(logrus.Fields{
+				"tx":       local.tx,
+				"attempts": local.attempts,
+			}).Info("Stop re-broadcasting a local tx.")
+			continue
+		}
+		local.attempts++
+		local.nextHeight = height + pool.rebroadcastBlocks<<local.attempts
+		txs = append(txs, local.tx)
+
+		logging.VLog().WithFields(logrus.Fields{
+			"tx":         local.tx,
+			"height":     height,
+			"attempts":   local.attempts,
+			"nextHeight": local.nextHeight,
+		}).Info("Re-broadcast a pending local tx.")
+	}
+	pool.mu.Unlock()
+
+	for _, tx := range txs {
+		atomic.AddUint64(&pool.rebroadcastedTxs, 1)
+		pool.ns.Broadcast(MessageTypeNewTx, tx, net.MessagePriorityNormal)
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"testing"
+
+	"github.com/alexlisong/go-nebulas/net"
+	"github.com/alexlisong/go-nebulas/util"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockBroadcastNetService record the broadcast txs, which are never received by others.
+type mockBroadcastNetService struct {
+	mockNetService
+	broadcasts []*Transaction
+}
+
+func (n *mockBroadcastNetService) Broadcast(name string, msg net.Serializable, priority int) {
+	n.broadcasts = append(n.broadcasts, msg.(*Transaction))
+}
+
+func TestTransactionPool_RebroadcastLocalTxs(t *testing.T) {
+	bc := testNeb(t).chain
+	txPool, _ := NewTransactionPool(16)
+	txPool.setBlockChain(bc)
+	txPool.setEventEmitter(bc.eventEmitter)
+	ns := new(mockBroadcastNetService)
+	txPool.RegisterInNetwork(ns)
+	txPool.SetRebroadcast(2, 3)
+
+	gasLimit, _ := util.NewUint128FromInt(200000)
+	newTx := func(from *Address, percent uint64) *Transaction {
+		gasPrice, _ := TransactionGasPrice.Mul(util.NewUint128FromUint(percent))
+		gasPrice, _ = gasPrice.Div(util.NewUint128FromUint(100))
+		tx, _ := NewTransaction(bc.ChainID(), from, mockAddress(), util.NewUint128(), 1, TxPayloadBinaryType, nil, gasPrice, gasLimit)
+		assert.Nil(t, tx.Sign(mockSignature(t, from)))
+		return tx
+	}
+	// mint n blocks and re-broadcast after each one.
+	mint := func(n int) {
+		for i := 0; i < n; i++ {
+			mockHeaderChain(t, bc, 1, 0)
+			txPool.rebroadcastLocalTxs()
+		}
+	}
+
+	// the first broadcast of local txs are lost.
+	local, replaced := newTx(mockAddress(), 100), newTx(mockAddress(), 100)
+	assert.Nil(t, txPool.PushAndBroadcast(local))
+	assert.Nil(t, txPool.PushAndBroadcast(replaced))
+	remote := newTx(mockAddress(), 100)
+	assert.Nil(t, txPool.PushAndRelay(remote))
+	assert.Equal(t, 2, len(ns.broadcasts))
+
+	// replaced by a tx of higher gasPrice.
+	replacing := newTx(replaced.from, 200)
+	assert.Nil(t, txPool.PushAndRelay(replacing))
+
+	mint(1)
+	assert.Equal(t, uint64(0), txPool.RebroadcastedTransactions())
+	mint(1)
+	assert.Equal(t, uint64(1), txPool.RebroadcastedTransactions())
+	assert.Equal(t, local, ns.broadcasts[2])
+
+	// backoff doubles the interval.
+	mint(3)
+	assert.Equal(t, uint64(1), txPool.RebroadcastedTransactions())
+	mint(1)
+	assert.Equal(t, uint64(2), txPool.RebroadcastedTransactions())
+
+	// stop once the tx is on chain.
+	txPool.Del(local)
+	mint(16)
+	assert.Equal(t, uint64(2), txPool.RebroadcastedTransactions())
+	assert.Equal(t, 4, len(ns.broadcasts))
+
+	// stop after max retries.
+	txPool.SetRebroadcast(1, 1)
+	assert.Nil(t, txPool.PushAndBroadcast(newTx(mockAddress(), 100)))
+	mint(4)
+	assert.Equal(t, uint64(3), txPool.RebroadcastedTransactions())
+	assert.Equal(t, 0, len(txPool.locals))
+}
@@ -0,0 +1,188 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"github.com/alexlisong/go-nebulas/util/byteutils"
+	"github.com/alexlisong/go-nebulas/util/logging"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// PendingTxNoticePending a matched tx is accepted by transaction pool.
+	PendingTxNoticePending = "pending"
+
+	// PendingTxNoticeDropped a notified tx leaves transaction pool without being on chain.
+	PendingTxNoticeDropped = "dropped"
+)
+
+const (
+	// DropReasonPoolFull the tx is evicted because transaction pool is full.
+	DropReasonPoolFull = "pool_full"
+
+	// DropReasonExpired the tx stays in transaction pool longer than txLifetime.
+	DropReasonExpired = "expired"
+
+	// DropReasonNonceUsed another tx with the same nonce is on chain.
+	DropReasonNonceUsed = "nonce_used"
+
+	// DropReasonReplaced the tx is replaced by a tx with the same nonce and a higher gasPrice.
+	DropReasonReplaced = "replaced"
+)
+
+// PendingTxFilter filters the txs accepted by transaction pool.
+// An empty field matches everything.
+type PendingTxFilter struct {
+	toAddresses  map[byteutils.HexHash]bool
+	payloadTypes map[string]bool
+}
+
+// NewPendingTxFilter returns a PendingTxFilter on to addresses and payload types activated at height.
+func NewPendingTxFilter(toAddresses []string, payloadTypes []string, height uint64) (*PendingTxFilter, error) {
+	filter := &PendingTxFilter{
+		toAddresses:  make(map[byteutils.HexHash]bool),
+		payloadTypes: make(map[string]bool),
+	}
+	for _, v := range toAddresses {
+		addr, err := AddressParse(v)
+		if err != nil {
+			return nil, err
+		}
+		filter.toAddresses[addr.address.Hex()] = true
+	}
+	for _, v := range payloadTypes {
+		if !IsRegisteredPayloadType(v, height) {
+			return nil, ErrInvalidTxPayloadType
+		}
+		filter.payloadTypes[v] = true
+	}
+	return filter, nil
+}
+
+// Match returns if the tx passes the filter.
+func (f *PendingTxFilter) Match(tx *Transaction) bool {
+	if f == nil {
+		return true
+	}
+	if len(f.toAddresses) > 0 && !f.toAddresses[tx.to.address.Hex()] {
+		return false
+	}
+	if len(f.payloadTypes) > 0 && !f.payloadTypes[tx.Type()] {
+		return false
+	}
+	return true
+}
+
+// PendingTxNotice is sent to PendingTxSubscriber.
+type PendingTxNotice struct {
+	Type   string
+	Hash   byteutils.Hash
+	Reason string
+	Tx     *Transaction
+}
+
+// PendingTxSubscriber receives notices of matched txs in transaction pool.
+type PendingTxSubscriber struct {
+	noticeCh chan *PendingTxNotice
+	filter   *PendingTxFilter
+
+	// notified txs still in pool, guarded by pool.mu
+	notified map[byteutils.HexHash]bool
+}
+
+// NewPendingTxSubscriber returns a PendingTxSubscriber
+func NewPendingTxSubscriber(size int, filter *PendingTxFilter) *PendingTxSubscriber {
+	return &PendingTxSubscriber{
+		noticeCh: make(chan *PendingTxNotice, size),
+		filter:   filter,
+		notified: make(map[byteutils.HexHash]bool),
+	}
+}
+
+// NoticeChan returns subscriber's noticeCh
+func (s *PendingTxSubscriber) NoticeChan() chan *PendingTxNotice {
+	return s.noticeCh
+}
+
+func (s *PendingTxSubscriber) send(notice *PendingTxNotice) bool {
+	select {
+	case s.noticeCh <- notice:
+		return true
+	default:
+		logging.VLog().WithFields(logrus.Fields{
+			"type": notice.Type,
+			"hash": notice.Hash,
+		}).Warn("timeout to dispatch pending tx notice.")
+		return false
+	}
+}
+
+// SubscribePending register subscriber on txs accepted by the pool.
+func (pool *TransactionPool) SubscribePending(subscriber *PendingTxSubscriber) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	pool.pendingSubs[subscriber] = true
+}
+
+// UnsubscribePending deregister subscriber.
+func (pool *TransactionPool) UnsubscribePending(subscriber *PendingTxSubscriber) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	delete(pool.pendingSubs, subscriber)
+}
+
+func (pool *TransactionPool) notifyPending(tx *Transaction) {
+	for subscriber := range pool.pendingSubs {
+		if !subscriber.filter.Match(tx) {
+			continue
+		}
+		notice := &PendingTxNotice{
+			Type: PendingTxNoticePending,
+			Hash: tx.hash,
+			Tx:   tx,
+		}
+		if subscriber.send(notice) {
+			subscriber.notified[tx.hash.Hex()] = true
+		}
+	}
+}
+
+func (pool *TransactionPool) notifyDropped(tx *Transaction, reason string) {
+	for subscriber := range pool.pendingSubs {
+		if !subscriber.notified[tx.hash.Hex()] {
+			continue
+		}
+		delete(subscriber.notified, tx.hash.Hex())
+		notice := &PendingTxNotice{
+			Type:   PendingTxNoticeDropped,
+			Hash:   tx.hash,
+			Reason: reason,
+			Tx:     tx,
+		}
+		subscriber.send(notice)
+	}
+}
+
+func (pool *TransactionPool) notifyOnChain(tx *Transaction) {
+	for subscriber := range pool.pendingSubs {
+		delete(subscriber.notified, tx.hash.Hex())
+	}
+}
@@ -194,19 +194,20 @@ func TestTransactionPool(t *testing.T) {
 	// put tx with different chainID, should fail
 	assert.Nil(t, txs[4].Sign(signature1))
 	assert.NotNil(t, txPool.Push(txs[4]))
-	// put one new, replace txs[1]
+	// put one with the same nonce and higher gasPrice, replace txs[2]
 	assert.Equal(t, len(txPool.all), 3)
 	assert.Nil(t, txs[6].Sign(signature1))
 	assert.Nil(t, txPool.Push(txs[6]))
-	assert.Equal(t, len(txPool.all), 4)
+	assert.Equal(t, len(txPool.all), 3)
+	assert.Nil(t, txPool.all[txs[2].hash.Hex()])
 	// get from: other, nonce: 1, data: "da"
 	tx := txPool.Pop()
 	assert.Equal(t, txs[6].data.Payload, tx.data.Payload)
 	// put one new
-	assert.Equal(t, len(txPool.all), 3)
+	assert.Equal(t, len(txPool.all), 2)
 	assert.Nil(t, txs[5].Sign(signature2))
 	assert.Nil(t, txPool.Push(txs[5]))
-	assert.Equal(t, len(txPool.all), 4)
+	assert.Equal(t, len(txPool.all), 3)
 	// get 2 txs, txs[5], txs[0]
 	tx = txPool.Pop()
 	assert.Equal(t, txs[5].from.address, tx.from.address)
@@ -229,6 +230,7 @@ func TestGasConfig(t *testing.T) {
 	gasLimit, _ := util.NewUint128FromInt(1)
 	txPool.SetGasConfig(gasPrice, gasLimit)
 	assert.Equal(t, txPool.minGasPrice, gasPrice)
+	assert.Equal(t, txPool.MinGasPrice(), gasPrice)
 	assert.Equal(t, txPool.maxGasLimit, gasLimit)
 }
 
@@ -265,7 +267,7 @@ func TestPushTxs(t *testing.T) {
 	_, err = NewTransaction(bc.ChainID(), from, to, util.NewUint128(), 10, TxPayloadBinaryType, []byte("datadata"), TransactionGasPrice, MaxGasPlus1)
 	assert.Equal(t, err, ErrInvalidGasLimit)
 	txs := []*Transaction{tx1}
-	assert.Equal(t, txPool.Push(txs[0]), ErrBelowGasPrice)
+	assert.Equal(t, txPool.Push(txs[0]), ErrGasPriceBelowMinimum)
 }
 
 func TestTransactionPool_Pop(t *testing.T) {
@@ -408,3 +410,190 @@ func TestTransactionPoolBucketUpdateTimeAndEvict(t *testing.T) {
 	assert.Equal(t, ok, false)
 
 }
+
+func TestTransactionPool_SubscribePending(t *testing.T) {
+	bc := testNeb(t).chain
+	txPool, _ := NewTransactionPool(3)
+	txPool.setBlockChain(bc)
+	txPool.setEventEmitter(bc.eventEmitter)
+
+	from := mockAddress()
+	other := mockAddress()
+	contract := mockAddress()
+	ks := keystore.DefaultKS
+	key1, _ := ks.GetUnlocked(from.String())
+	signature1, _ := crypto.NewSignature(keystore.SECP256K1)
+	signature1.InitSign(key1.(keystore.PrivateKey))
+	key2, _ := ks.GetUnlocked(other.String())
+	signature2, _ := crypto.NewSignature(keystore.SECP256K1)
+	signature2.InitSign(key2.(keystore.PrivateKey))
+
+	height := bc.TailBlock().Height() + 1
+	_, err := NewPendingTxFilter([]string{"invalid"}, nil, height)
+	assert.NotNil(t, err)
+	_, err = NewPendingTxFilter(nil, []string{"invalid"}, height)
+	assert.Equal(t, ErrInvalidTxPayloadType, err)
+	_, err = NewPendingTxFilter(nil, []string{TxPayloadUpgradeType}, height)
+	assert.Equal(t, ErrInvalidTxPayloadType, err)
+
+	filter, err := NewPendingTxFilter([]string{contract.String()}, []string{TxPayloadCallType}, height)
+	assert.Nil(t, err)
+	subscriber := NewPendingTxSubscriber(16, filter)
+	txPool.SubscribePending(subscriber)
+	defer txPool.UnsubscribePending(subscriber)
+
+	callPayload, _ := NewCallPayload("totalSupply", "")
+	payload, _ := callPayload.ToBytes()
+	gasLimit, _ := util.NewUint128FromInt(200000)
+
+	matched, _ := NewTransaction(bc.ChainID(), from, contract, util.NewUint128(), 1, TxPayloadCallType, payload, TransactionGasPrice, gasLimit)
+	assert.Nil(t, matched.Sign(signature1))
+	wrongTo, _ := NewTransaction(bc.ChainID(), from, other, util.NewUint128(), 2, TxPayloadCallType, payload, TransactionGasPrice, gasLimit)
+	assert.Nil(t, wrongTo.Sign(signature1))
+	wrongType, _ := NewTransaction(bc.ChainID(), other, contract, util.NewUint128(), 1, TxPayloadBinaryType, nil, TransactionGasPrice, gasLimit)
+	assert.Nil(t, wrongType.Sign(signature2))
+
+	assert.Nil(t, txPool.Push(matched))
+	assert.Nil(t, txPool.Push(wrongTo))
+	assert.Nil(t, txPool.Push(wrongType))
+
+	notice := <-subscriber.NoticeChan()
+	assert.Equal(t, PendingTxNoticePending, notice.Type)
+	assert.Equal(t, matched.Hash(), notice.Hash)
+	assert.Equal(t, matched, notice.Tx)
+	assert.Equal(t, 0, len(subscriber.NoticeChan()))
+
+	// the pool is full, the matched tx in the longest bucket is evicted.
+	matched2, _ := NewTransaction(bc.ChainID(), from, contract, util.NewUint128(), 3, TxPayloadCallType, payload, TransactionGasPrice, gasLimit)
+	assert.Nil(t, matched2.Sign(signature1))
+	assert.Nil(t, txPool.Push(matched2))
+
+	notice = <-subscriber.NoticeChan()
+	assert.Equal(t, PendingTxNoticePending, notice.Type)
+	assert.Equal(t, matched2.Hash(), notice.Hash)
+	notice = <-subscriber.NoticeChan()
+	assert.Equal(t, PendingTxNoticeDropped, notice.Type)
+	assert.Equal(t, matched2.Hash(), notice.Hash)
+	assert.Equal(t, DropReasonPoolFull, notice.Reason)
+
+	// the matched tx is replaced by another tx with the same nonce on chain.
+	onChain, _ := NewTransaction(bc.ChainID(), from, other, util.NewUint128(), 1, TxPayloadBinaryType, nil, TransactionGasPrice, gasLimit)
+	assert.Nil(t, onChain.Sign(signature1))
+	txPool.Del(onChain)
+
+	notice = <-subscriber.NoticeChan()
+	assert.Equal(t, PendingTxNoticeDropped, notice.Type)
+	assert.Equal(t, matched.Hash(), notice.Hash)
+	assert.Equal(t, DropReasonNonceUsed, notice.Reason)
+	assert.Equal(t, 0, len(subscriber.NoticeChan()))
+}
+
+func TestTransactionPool_ReplaceByFee(t *testing.T) {
+	bc := testNeb(t).chain
+	txPool, _ := NewTransactionPool(16)
+	txPool.setBlockChain(bc)
+	txPool.setEventEmitter(bc.eventEmitter)
+
+	from := mockAddress()
+	ks := keystore.DefaultKS
+	key, _ := ks.GetUnlocked(from.String())
+	signature, _ := crypto.NewSignature(keystore.SECP256K1)
+	signature.InitSign(key.(keystore.PrivateKey))
+
+	subscriber := NewPendingTxSubscriber(16, nil)
+	txPool.SubscribePending(subscriber)
+	defer txPool.UnsubscribePending(subscriber)
+
+	gasLimit, _ := util.NewUint128FromInt(200000)
+	price := func(percent uint64) *util.Uint128 {
+		v, _ := TransactionGasPrice.Mul(util.NewUint128FromUint(percent))
+		v, _ = v.Div(util.NewUint128FromUint(100))
+		return v
+	}
+	newTx := func(nonce uint64, gasPrice *util.Uint128, data string) *Transaction {
+		tx, _ := NewTransaction(bc.ChainID(), from, mockAddress(), util.NewUint128(), nonce, TxPayloadBinaryType, []byte(data), gasPrice, gasLimit)
+		assert.Nil(t, tx.Sign(signature))
+		return tx
+	}
+
+	tx1 := newTx(1, TransactionGasPrice, "1")
+	tx2 := newTx(2, TransactionGasPrice, "2")
+	assert.Nil(t, txPool.Push(tx1))
+	assert.Nil(t, txPool.Push(tx2))
+	<-subscriber.NoticeChan()
+	<-subscriber.NoticeChan()
+
+	// bumped less than 10%.
+	assert.Equal(t, ErrUnderpricedReplacement, txPool.Push(newTx(1, price(109), "underpriced")))
+	assert.Equal(t, uint64(0), txPool.ReplacedTransactions())
+
+	// bumped 10%, replace tx1.
+	replacing := newTx(1, price(110), "replacing")
+	assert.Nil(t, txPool.Push(replacing))
+	assert.Equal(t, uint64(1), txPool.ReplacedTransactions())
+	assert.Equal(t, 2, len(txPool.all))
+	assert.Nil(t, txPool.GetTransaction(tx1.Hash()))
+	assert.Equal(t, replacing, txPool.candidates.Left())
+
+	notice := <-subscriber.NoticeChan()
+	assert.Equal(t, PendingTxNoticeDropped, notice.Type)
+	assert.Equal(t, tx1.Hash(), notice.Hash)
+	assert.Equal(t, DropReasonReplaced, notice.Reason)
+	notice = <-subscriber.NoticeChan()
+	assert.Equal(t, PendingTxNoticePending, notice.Type)
+	assert.Equal(t, replacing.Hash(), notice.Hash)
+
+	// configurable bump.
+	txPool.SetReplacePriceBump(50)
+	assert.Equal(t, ErrUnderpricedReplacement, txPool.Push(newTx(2, price(149), "underpriced")))
+	assert.Nil(t, txPool.Push(newTx(2, price(150), "replacing")))
+	assert.Equal(t, uint64(2), txPool.ReplacedTransactions())
+
+	// a tx being packed into a proposing block can't be replaced.
+	packing := txPool.PopWithBlacklist(nil, nil)
+	assert.Equal(t, replacing, packing)
+	assert.Equal(t, ErrReplacePackingTransaction, txPool.Push(newTx(1, price(1000), "packing")))
+	// but can be given back.
+	assert.Nil(t, txPool.Push(packing))
+	assert.Equal(t, uint64(2), txPool.ReplacedTransactions())
+
+	// a packing tx failed in execution doesn't block the same nonce.
+	packing = txPool.PopWithBlacklist(nil, nil)
+	txPool.dropPacking(packing)
+	assert.Nil(t, txPool.Push(newTx(1, price(100), "after failed")))
+
+	// the bump isn't rounded down.
+	txPool.SetReplacePriceBump(10)
+	assert.False(t, txPool.isPriceBumped(util.NewUint128FromUint(15), util.NewUint128FromUint(16)))
+	assert.True(t, txPool.isPriceBumped(util.NewUint128FromUint(15), util.NewUint128FromUint(17)))
+}
+
+func TestTransactionPool_TimestampDrift(t *testing.T) {
+	bc := testNeb(t).chain
+	txPool, _ := NewTransactionPool(16)
+	txPool.setBlockChain(bc)
+	txPool.setEventEmitter(bc.eventEmitter)
+
+	from := mockAddress()
+	ks := keystore.DefaultKS
+	key, _ := ks.GetUnlocked(from.String())
+	signature, _ := crypto.NewSignature(keystore.SECP256K1)
+	signature.InitSign(key.(keystore.PrivateKey))
+
+	gasLimit, _ := util.NewUint128FromInt(200000)
+	newTx := func(nonce uint64, ahead int64) *Transaction {
+		tx, _ := NewTransaction(bc.ChainID(), from, mockAddress(), util.NewUint128(), nonce, TxPayloadBinaryType, nil, TransactionGasPrice, gasLimit)
+		tx.timestamp += ahead
+		assert.Nil(t, tx.Sign(signature))
+		return tx
+	}
+
+	assert.Nil(t, txPool.Push(newTx(1, 60)))
+	assert.Equal(t, ErrTxTimestampAheadOfNode, txPool.Push(newTx(2, DefaultTxTimestampMaxDrift+60)))
+
+	txPool.SetTimestampMaxDrift(DefaultTxTimestampMaxDrift * 2)
+	assert.Nil(t, txPool.Push(newTx(2, DefaultTxTimestampMaxDrift+60)))
+
+	txPool.SetTimestampMaxDrift(0)
+	assert.Equal(t, int64(DefaultTxTimestampMaxDrift), txPool.timestampMaxDrift)
+}
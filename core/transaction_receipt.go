@@ -0,0 +1,212 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"fmt"
+
+	"github.com/alexlisong/go-nebulas/core/pb"
+	"github.com/alexlisong/go-nebulas/util"
+	"github.com/alexlisong/go-nebulas/util/byteutils"
+	"github.com/gogo/protobuf/proto"
+)
+
+// TransactionReceipt is the persisted execution outcome of a transaction.
+type TransactionReceipt struct {
+	hash            byteutils.Hash
+	status          uint32
+	gasUsed         *util.Uint128
+	contractAddress *Address
+	blockHeight     uint64
+	err             string
+	gasTrace        *GasTrace
+}
+
+// Hash return tx hash
+func (r *TransactionReceipt) Hash() byteutils.Hash {
+	return r.hash
+}
+
+// Status return execution status, TxExecutionSuccess or TxExecutionFailed
+func (r *TransactionReceipt) Status() uint32 {
+	return r.status
+}
+
+// GasUsed return gas used by the execution
+func (r *TransactionReceipt) GasUsed() *util.Uint128 {
+	return r.gasUsed
+}
+
+// ContractAddress return the deployed contract address, nil if not a successful deploy
+func (r *TransactionReceipt) ContractAddress() *Address {
+	return r.contractAddress
+}
+
+// BlockHeight return the height of the block including the tx
+func (r *TransactionReceipt) BlockHeight() uint64 {
+	return r.blockHeight
+}
+
+// GasTrace return the breakdown of gas used, nil if not recorded
+func (r *TransactionReceipt) GasTrace() *GasTrace {
+	return r.gasTrace
+}
+
+// Error return execution error message
+func (r *TransactionReceipt) Error() string {
+	return r.err
+}
+
+// ToProto converts domain receipt to proto receipt
+func (r *TransactionReceipt) ToProto() (proto.Message, error) {
+	gasUsed, err := r.gasUsed.ToFixedSizeByteSlice()
+	if err != nil {
+		return nil, err
+	}
+	var contractAddress []byte
+	if r.contractAddress != nil {
+		contractAddress = r.contractAddress.address
+	}
+	var gasTrace *corepb.GasTrace
+	if r.gasTrace != nil {
+		msg, err := r.gasTrace.ToProto()
+		if err != nil {
+			return nil, err
+		}
+		gasTrace = msg.(*corepb.GasTrace)
+	}
+	return &corepb.TransactionReceipt{
+		Hash:            r.hash,
+		Status:          r.status,
+		GasUsed:         gasUsed,
+		ContractAddress: contractAddress,
+		BlockHeight:     r.blockHeight,
+		Error:           r.err,
+		GasTrace:        gasTrace,
+	}, nil
+}
+
+// FromProto converts proto receipt to domain receipt
+func (r *TransactionReceipt) FromProto(msg proto.Message) error {
+	if msg, ok := msg.(*corepb.TransactionReceipt); ok {
+		if msg != nil {
+			r.hash = msg.Hash
+			r.status = msg.Status
+
+			gasUsed, err := util.NewUint128FromFixedSizeByteSlice(msg.GasUsed)
+			if err != nil {
+				return err
+			}
+			r.gasUsed = gasUsed
+
+			if len(msg.ContractAddress) > 0 {
+				contractAddress, err := AddressParseFromBytes(msg.ContractAddress)
+				if err != nil {
+					return err
+				}
+				r.contractAddress = contractAddress
+			}
+
+			if msg.GasTrace != nil {
+				gasTrace := new(GasTrace)
+				if err := gasTrace.FromProto(msg.GasTrace); err != nil {
+					return err
+				}
+				r.gasTrace = gasTrace
+			}
+
+			r.blockHeight = msg.BlockHeight
+			r.err = msg.Error
+			return nil
+		}
+		return ErrInvalidProtoToTransactionReceipt
+	}
+	return ErrInvalidProtoToTransactionReceipt
+}
+
+func (r *TransactionReceipt) String() string {
+	return fmt.Sprintf(`{"hash": "%s", "status": %d, "gas_used": "%s", "block_height": %d, "error": "%s"}`,
+		r.hash,
+		r.status,
+		r.gasUsed,
+		r.blockHeight,
+		r.err,
+	)
+}
+
+func (tx *Transaction) recordReceipt(block *Block, gasUsed *util.Uint128, exeErr error, trace *GasTrace, ws WorldState) error {
+	// the receipts root is not in header before the fork, its receipts would be lost after restart.
+	if block.height < ReceiptsForkHeight {
+		return nil
+	}
+
+	receipt := &TransactionReceipt{
+		hash:        tx.hash,
+		status:      TxExecutionSuccess,
+		gasUsed:     gasUsed,
+		blockHeight: block.height,
+	}
+	if block.height >= GasTraceForkHeight {
+		receipt.gasTrace = trace
+	}
+
+	if exeErr != nil {
+		receipt.status = TxExecutionFailed
+		receipt.err = exeErr.Error()
+		if len(receipt.err) > MaxEventErrLength {
+			receipt.err = receipt.err[:MaxEventErrLength]
+		}
+	} else if tx.Type() == TxPayloadDeployType {
+		contractAddress, err := tx.ContractAddressAtHeight(block.height)
+		if err != nil {
+			return err
+		}
+		receipt.contractAddress = contractAddress
+	}
+
+	pbReceipt, err := receipt.ToProto()
+	if err != nil {
+		return err
+	}
+	receiptBytes, err := proto.Marshal(pbReceipt)
+	if err != nil {
+		return err
+	}
+	return ws.PutTxReceipt(tx.hash, receiptBytes)
+}
+
+// GetTransactionReceipt from receipts Trie
+func GetTransactionReceipt(hash byteutils.Hash, ws WorldState) (*TransactionReceipt, error) {
+	if len(hash) != TxHashByteLength {
+		return nil, ErrInvalidArgument
+	}
+	bytes, err := ws.GetTxReceipt(hash)
+	if err != nil {
+		return nil, err
+	}
+	pbReceipt := new(corepb.TransactionReceipt)
+	if err := proto.Unmarshal(bytes, pbReceipt); err != nil {
+		return nil, err
+	}
+	receipt := new(TransactionReceipt)
+	if err = receipt.FromProto(pbReceipt); err != nil {
+		return nil, err
+	}
+	return receipt, nil
+}
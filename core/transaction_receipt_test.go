@@ -0,0 +1,122 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"testing"
+
+	"github.com/alexlisong/go-nebulas/crypto"
+	"github.com/alexlisong/go-nebulas/crypto/keystore"
+	"github.com/alexlisong/go-nebulas/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionReceipt(t *testing.T) {
+	defer func(height uint64) { ReceiptsForkHeight = height }(ReceiptsForkHeight)
+	ReceiptsForkHeight = 0
+
+	neb := testNeb(t)
+	bc := neb.chain
+
+	coinbase := mockAddress()
+	from := mockAddress()
+	balance, _ := util.NewUint128FromString("1000000000000000000")
+
+	ks := keystore.DefaultKS
+	key, _ := ks.GetUnlocked(from.String())
+	signature, _ := crypto.NewSignature(keystore.SECP256K1)
+	signature.InitSign(key.(keystore.PrivateKey))
+
+	block, err := bc.NewBlock(coinbase)
+	assert.Nil(t, err)
+	fromAcc, err := block.worldState.GetOrCreateUserAccount(from.address)
+	assert.Nil(t, err)
+	fromAcc.AddBalance(balance)
+	block.Commit()
+
+	block, err = bc.NewBlockFromParent(bc.tailBlock.header.coinbase, block)
+	assert.Nil(t, err)
+
+	// successful deploy
+	deployTx := mockDeployTransaction(bc.chainID, 1)
+	deployTx.from = from
+	deployTx.to = from
+	deployTx.value = util.NewUint128()
+	assert.Nil(t, deployTx.Sign(signature))
+
+	// value exceeds balance
+	failedTx := mockNormalTransaction(bc.chainID, 2)
+	failedTx.from = from
+	failedTx.value, _ = util.NewUint128FromString("2000000000000000000")
+	assert.Nil(t, failedTx.Sign(signature))
+
+	for _, tx := range []*Transaction{deployTx, failedTx} {
+		txWorldState, err := block.WorldState().Prepare(tx.Hash().String())
+		assert.Nil(t, err)
+		giveback, err := VerifyExecution(tx, block, txWorldState)
+		assert.False(t, giveback)
+		assert.Nil(t, err)
+		_, err = txWorldState.CheckAndUpdate()
+		assert.Nil(t, err)
+	}
+	block.Commit()
+
+	receipt, err := GetTransactionReceipt(deployTx.Hash(), block.worldState)
+	assert.Nil(t, err)
+	assert.Equal(t, deployTx.Hash(), receipt.Hash())
+	assert.Equal(t, uint32(TxExecutionSuccess), receipt.Status())
+	assert.Equal(t, block.Height(), receipt.BlockHeight())
+	assert.True(t, receipt.GasUsed().Cmp(util.NewUint128()) > 0)
+	contractAddr, err := deployTx.GenerateContractAddress()
+	assert.Nil(t, err)
+	assert.Equal(t, contractAddr.String(), receipt.ContractAddress().String())
+	assert.Equal(t, "", receipt.Error())
+
+	receipt, err = GetTransactionReceipt(failedTx.Hash(), block.worldState)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(TxExecutionFailed), receipt.Status())
+	assert.Nil(t, receipt.ContractAddress())
+	assert.Equal(t, ErrInsufficientBalance.Error(), receipt.Error())
+
+	_, err = GetTransactionReceipt(mockNormalTransaction(bc.chainID, 3).Hash(), block.worldState)
+	assert.NotNil(t, err)
+	_, err = GetTransactionReceipt([]byte("invalid"), block.worldState)
+	assert.Equal(t, ErrInvalidArgument, err)
+
+	// receipts are not recorded before the fork.
+	block, err = bc.NewBlockFromParent(bc.tailBlock.header.coinbase, block)
+	assert.Nil(t, err)
+	ReceiptsForkHeight = block.Height() + 1
+	tx := mockNormalTransaction(bc.chainID, 3)
+	tx.from = from
+	assert.Nil(t, tx.Sign(signature))
+	txWorldState, err := block.WorldState().Prepare(tx.Hash().String())
+	assert.Nil(t, err)
+	giveback, err := VerifyExecution(tx, block, txWorldState)
+	assert.False(t, giveback)
+	assert.Nil(t, err)
+	_, err = txWorldState.CheckAndUpdate()
+	assert.Nil(t, err)
+	block.Commit()
+
+	_, err = GetTransactionReceipt(tx.Hash(), block.worldState)
+	assert.NotNil(t, err)
+	_, err = block.GetTransactionReceipt(tx.Hash())
+	assert.Equal(t, ErrReceiptsNotAvailable, err)
+}
@@ -0,0 +1,310 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"encoding/json"
+	"math"
+
+	"github.com/alexlisong/go-nebulas/core/state"
+	"github.com/alexlisong/go-nebulas/storage"
+	"github.com/alexlisong/go-nebulas/util"
+)
+
+// Recovery Actions
+const (
+	// RecoveryActionRegister the owner designates an heir and a challenge period.
+	RecoveryActionRegister = "register"
+
+	// RecoveryActionClaim the heir starts the challenge period.
+	RecoveryActionClaim = "claim"
+
+	// RecoveryActionSweep the heir takes the owner's full balance after the challenge period.
+	RecoveryActionSweep = "sweep"
+)
+
+var (
+	// RecoveryForkHeight recovery payload is activated from this height, disabled by default.
+	RecoveryForkHeight uint64 = math.MaxUint64
+
+	// RecoveryRecordKey the key of recovery record in owner's account variables.
+	RecoveryRecordKey = []byte("__recovery__")
+)
+
+// RecoveryPayload carry dead-man's-switch recovery information.
+// register: from owner, carries Heir & Period.
+// claim & sweep: from heir, to owner.
+type RecoveryPayload struct {
+	Action string
+	Heir   string
+	Period uint64
+}
+
+// recoveryRecord stored in owner's account variables.
+type recoveryRecord struct {
+	Heir   string
+	Period uint64
+	// ClaimHeight is the height of the pending claim, 0 if no claim.
+	ClaimHeight uint64
+}
+
+// RecoveryEvent is the data of recovery events.
+type RecoveryEvent struct {
+	Owner       string `json:"owner"`
+	Heir        string `json:"heir"`
+	Period      uint64 `json:"period"`
+	ClaimHeight uint64 `json:"claim_height,omitempty"`
+	Amount      string `json:"amount,omitempty"`
+}
+
+// LoadRecoveryPayload from bytes
+func LoadRecoveryPayload(bytes []byte) (*RecoveryPayload, error) {
+	payload := &RecoveryPayload{}
+	if err := json.Unmarshal(bytes, payload); err != nil {
+		return nil, ErrInvalidArgument
+	}
+	return NewRecoveryPayload(payload.Action, payload.Heir, payload.Period)
+}
+
+// NewRecoveryPayload with action, heir & period
+func NewRecoveryPayload(action, heir string, period uint64) (*RecoveryPayload, error) {
+	switch action {
+	case RecoveryActionRegister:
+		if period == 0 {
+			return nil, ErrInvalidRecoveryPeriod
+		}
+		addr, err := AddressParse(heir)
+		if err != nil {
+			return nil, ErrInvalidRecoveryHeir
+		}
+		// a contract cannot sign the claim & sweep txs.
+		if addr.Type() == ContractAddress {
+			return nil, ErrInvalidRecoveryHeir
+		}
+	case RecoveryActionClaim, RecoveryActionSweep:
+		if len(heir) > 0 || period > 0 {
+			return nil, ErrInvalidArgument
+		}
+	default:
+		return nil, ErrInvalidRecoveryAction
+	}
+
+	return &RecoveryPayload{
+		Action: action,
+		Heir:   heir,
+		Period: period,
+	}, nil
+}
+
+// ToBytes serialize payload
+func (payload *RecoveryPayload) ToBytes() ([]byte, error) {
+	return json.Marshal(payload)
+}
+
+// BaseGasCount returns base gas count
+func (payload *RecoveryPayload) BaseGasCount() *util.Uint128 {
+	base, _ := util.NewUint128FromInt(20)
+	return base
+}
+
+// Execute the recovery payload in tx.
+// The heir's txs are paid and nonced by the heir, the dormant owner's nonce is never touched.
+func (payload *RecoveryPayload) Execute(limitedGas *util.Uint128, tx *Transaction, block *Block, ws WorldState) (*util.Uint128, string, error) {
+	if block == nil || tx == nil {
+		return util.NewUint128(), "", ErrNilArgument
+	}
+	if block.Height() < RecoveryForkHeight {
+		return util.NewUint128(), "", ErrInvalidTxPayloadType
+	}
+
+	var err error
+	switch payload.Action {
+	case RecoveryActionRegister:
+		err = payload.register(tx, ws)
+	case RecoveryActionClaim:
+		err = payload.claim(tx, block, ws)
+	case RecoveryActionSweep:
+		err = payload.sweep(tx, block, ws)
+	default:
+		err = ErrInvalidRecoveryAction
+	}
+	return util.NewUint128(), "", err
+}
+
+// register overwrites the previous record, a pending claim is dropped.
+func (payload *RecoveryPayload) register(tx *Transaction, ws WorldState) error {
+	heir, err := AddressParse(payload.Heir)
+	if err != nil {
+		return ErrInvalidRecoveryHeir
+	}
+	if heir.Equals(tx.from) {
+		return ErrInvalidRecoveryHeir
+	}
+
+	owner, err := ws.GetOrCreateUserAccount(tx.from.address)
+	if err != nil {
+		return err
+	}
+	record := &recoveryRecord{
+		Heir:   heir.String(),
+		Period: payload.Period,
+	}
+	if err := putRecoveryRecord(owner, record); err != nil {
+		return err
+	}
+	return recordRecoveryEvent(tx, TopicRecoveryRegister, tx.from, record, nil, ws)
+}
+
+func (payload *RecoveryPayload) claim(tx *Transaction, block *Block, ws WorldState) error {
+	owner, record, err := loadRecoveryRecordOfHeir(tx, ws)
+	if err != nil {
+		return err
+	}
+	if record.ClaimHeight > 0 {
+		return ErrRecoveryClaimPending
+	}
+
+	record.ClaimHeight = block.Height()
+	if err := putRecoveryRecord(owner, record); err != nil {
+		return err
+	}
+	return recordRecoveryEvent(tx, TopicRecoveryClaim, tx.to, record, nil, ws)
+}
+
+// sweep keeps the registration so that the heir can claim the later deposits again.
+func (payload *RecoveryPayload) sweep(tx *Transaction, block *Block, ws WorldState) error {
+	owner, record, err := loadRecoveryRecordOfHeir(tx, ws)
+	if err != nil {
+		return err
+	}
+	if record.ClaimHeight == 0 {
+		return ErrRecoveryNoPendingClaim
+	}
+	if record.Period > math.MaxUint64-record.ClaimHeight || block.Height() < record.ClaimHeight+record.Period {
+		return ErrRecoveryChallengePeriod
+	}
+
+	heir, err := ws.GetOrCreateUserAccount(tx.from.address)
+	if err != nil {
+		return err
+	}
+	amount := owner.Balance()
+	if err := owner.SubBalance(amount); err != nil {
+		return err
+	}
+	if err := heir.AddBalance(amount); err != nil {
+		return err
+	}
+
+	claimed := *record
+	record.ClaimHeight = 0
+	if err := putRecoveryRecord(owner, record); err != nil {
+		return err
+	}
+	return recordRecoveryEvent(tx, TopicRecoverySweep, tx.to, &claimed, amount, ws)
+}
+
+// cancelRecoveryClaim drops the pending claim on tx.from, any signed tx proves the owner is alive.
+func cancelRecoveryClaim(tx *Transaction, block *Block, ws WorldState) error {
+	if block.Height() < RecoveryForkHeight {
+		return nil
+	}
+
+	owner, err := ws.GetOrCreateUserAccount(tx.from.address)
+	if err != nil {
+		return err
+	}
+	record, err := getRecoveryRecord(owner)
+	if err != nil {
+		if err == ErrRecoveryNotRegistered {
+			return nil
+		}
+		return err
+	}
+	if record.ClaimHeight == 0 {
+		return nil
+	}
+
+	cancelled := *record
+	record.ClaimHeight = 0
+	if err := putRecoveryRecord(owner, record); err != nil {
+		return err
+	}
+	return recordRecoveryEvent(tx, TopicRecoveryCancel, tx.from, &cancelled, nil, ws)
+}
+
+// loadRecoveryRecordOfHeir returns the record of tx.to, which must designate tx.from as heir.
+func loadRecoveryRecordOfHeir(tx *Transaction, ws WorldState) (state.Account, *recoveryRecord, error) {
+	owner, err := ws.GetOrCreateUserAccount(tx.to.address)
+	if err != nil {
+		return nil, nil, err
+	}
+	record, err := getRecoveryRecord(owner)
+	if err != nil {
+		return nil, nil, err
+	}
+	if record.Heir != tx.from.String() {
+		return nil, nil, ErrRecoveryHeirMismatch
+	}
+	return owner, record, nil
+}
+
+func getRecoveryRecord(acc state.Account) (*recoveryRecord, error) {
+	bytes, err := acc.Get(RecoveryRecordKey)
+	if err != nil {
+		if err == storage.ErrKeyNotFound {
+			return nil, ErrRecoveryNotRegistered
+		}
+		return nil, err
+	}
+	record := &recoveryRecord{}
+	if err := json.Unmarshal(bytes, record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+func putRecoveryRecord(acc state.Account, record *recoveryRecord) error {
+	bytes, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return acc.Put(RecoveryRecordKey, bytes)
+}
+
+func recordRecoveryEvent(tx *Transaction, topic string, owner *Address, record *recoveryRecord, amount *util.Uint128, ws WorldState) error {
+	recoveryEvent := &RecoveryEvent{
+		Owner:       owner.String(),
+		Heir:        record.Heir,
+		Period:      record.Period,
+		ClaimHeight: record.ClaimHeight,
+	}
+	if amount != nil {
+		recoveryEvent.Amount = amount.String()
+	}
+	data, err := json.Marshal(recoveryEvent)
+	if err != nil {
+		return err
+	}
+	ws.RecordEvent(tx.hash, &state.Event{
+		Topic: topic,
+		Data:  string(data),
+	})
+	return nil
+}
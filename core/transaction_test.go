@@ -21,15 +21,21 @@ package core
 import (
 	"encoding/json"
 	"fmt"
+	"math"
+	"math/rand"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/gogo/protobuf/proto"
+	"github.com/alexlisong/go-nebulas/common/dag"
 	"github.com/alexlisong/go-nebulas/core/pb"
+	"github.com/alexlisong/go-nebulas/core/state"
 	"github.com/alexlisong/go-nebulas/crypto"
 	"github.com/alexlisong/go-nebulas/crypto/hash"
 	"github.com/alexlisong/go-nebulas/crypto/keystore"
+	"github.com/alexlisong/go-nebulas/crypto/keystore/secp256k1"
 	"github.com/alexlisong/go-nebulas/util"
 	"github.com/alexlisong/go-nebulas/util/byteutils"
 	"github.com/stretchr/testify/assert"
@@ -546,7 +552,7 @@ func TestTransaction_VerifyExecution(t *testing.T) {
 			assert.Nil(t, err)
 
 			giveback, executionErr := VerifyExecution(tt.tx, block, txWorldState)
-			assert.Equal(t, tt.wanted, executionErr)
+			assert.Equal(t, tt.wanted, ErrorCause(executionErr))
 			assert.Equal(t, giveback, tt.giveback)
 			fromAcc, err = txWorldState.GetOrCreateUserAccount(tt.tx.from.address)
 			assert.Nil(t, err)
@@ -764,6 +770,1347 @@ func TestDeployAndCall(t *testing.T) {
 	}
 }
 
+func TestTransaction_SignatureAlgorithms(t *testing.T) {
+	secpKey, err := crypto.NewPrivateKey(keystore.SECP256K1, nil)
+	assert.Nil(t, err)
+	edKey, err := crypto.NewPrivateKey(keystore.ED25519, nil)
+	assert.Nil(t, err)
+
+	to := mockAddress()
+	gasLimit, _ := util.NewUint128FromInt(200000)
+	txs := make(map[keystore.Algorithm]*Transaction)
+	for _, key := range []keystore.PrivateKey{secpKey, edKey} {
+		pubdata, err := key.PublicKey().Encoded()
+		assert.Nil(t, err)
+		from, err := NewAddressFromPublicKey(pubdata)
+		assert.Nil(t, err)
+		signature, err := crypto.NewSignature(key.Algorithm())
+		assert.Nil(t, err)
+		assert.Nil(t, signature.InitSign(key))
+
+		tx, err := NewTransaction(1, from, to, util.NewUint128(), 1, TxPayloadBinaryType, []byte("datadata"), TransactionGasPrice, gasLimit)
+		assert.Nil(t, err)
+		assert.Nil(t, tx.Sign(signature))
+		assert.Equal(t, key.Algorithm(), tx.alg)
+		assert.Nil(t, tx.VerifyIntegrity(1))
+
+		// survives proto round trip.
+		pbTx, err := tx.ToProto()
+		assert.Nil(t, err)
+		restored := new(Transaction)
+		assert.Nil(t, restored.FromProto(pbTx))
+		assert.Nil(t, restored.VerifyIntegrity(1))
+
+		signer, err := RecoverSignerFromSignature(tx.alg, tx.hash, tx.sign)
+		assert.Nil(t, err)
+		assert.Equal(t, from, signer)
+		txs[key.Algorithm()] = tx
+	}
+
+	// the signature is bound to its algorithm.
+	secpTx, edTx := txs[keystore.SECP256K1], txs[keystore.ED25519]
+	secpTx.alg, edTx.alg = keystore.ED25519, keystore.SECP256K1
+	assert.NotNil(t, secpTx.VerifyIntegrity(1))
+	assert.NotNil(t, edTx.VerifyIntegrity(1))
+
+	// the ed25519 public key carried in the signature must be the signer's.
+	edTx.alg = keystore.ED25519
+	otherKey, err := crypto.NewPrivateKey(keystore.ED25519, nil)
+	assert.Nil(t, err)
+	signature, _ := crypto.NewSignature(keystore.ED25519)
+	assert.Nil(t, signature.InitSign(otherKey))
+	sign, err := signature.Sign(edTx.hash)
+	assert.Nil(t, err)
+	edTx.sign = sign
+	assert.Equal(t, ErrInvalidTransactionSigner, edTx.VerifyIntegrity(1))
+
+	// unknown algorithm is rejected ahead of recovery.
+	edTx.alg = keystore.Algorithm(99)
+	assert.Equal(t, ErrInvalidTransactionAlg, edTx.VerifyIntegrity(1))
+}
+
+func TestTransaction_Ed25519Fork(t *testing.T) {
+	defer func(height uint64) { Ed25519ForkHeight = height }(Ed25519ForkHeight)
+
+	neb := testNeb(t)
+	bc := neb.chain
+
+	key, err := crypto.NewPrivateKey(keystore.ED25519, nil)
+	assert.Nil(t, err)
+	pubdata, err := key.PublicKey().Encoded()
+	assert.Nil(t, err)
+	from, err := NewAddressFromPublicKey(pubdata)
+	assert.Nil(t, err)
+	signature, err := crypto.NewSignature(keystore.ED25519)
+	assert.Nil(t, err)
+	assert.Nil(t, signature.InitSign(key))
+
+	gasLimit, _ := util.NewUint128FromInt(200000)
+	tx, err := NewTransaction(bc.ChainID(), from, mockAddress(), util.NewUint128(), 1, TxPayloadBinaryType, nil, TransactionGasPrice, gasLimit)
+	assert.Nil(t, err)
+	assert.Nil(t, tx.Sign(signature))
+
+	check := func() error {
+		block, err := NewBlock(bc.ChainID(), mockAddress(), bc.tailBlock)
+		assert.Nil(t, err)
+		defer block.RollBack()
+		ws, err := block.WorldState().Prepare(tx.Hash().String())
+		assert.Nil(t, err)
+		defer ws.Close()
+		fromAcc, err := ws.GetOrCreateUserAccount(from.address)
+		assert.Nil(t, err)
+		balance, _ := util.NewUint128FromString("1000000000000000000")
+		assert.Nil(t, fromAcc.AddBalance(balance))
+		_, err = checkTransaction(tx, block, ws)
+		return err
+	}
+
+	// ed25519 txs are rejected by pool and block before the fork.
+	Ed25519ForkHeight = bc.tailBlock.Height() + 2
+	assert.Equal(t, ErrTxAlgNotActivated, bc.txPool.Push(tx))
+	assert.Equal(t, ErrTxAlgNotActivated, check())
+
+	Ed25519ForkHeight = bc.tailBlock.Height() + 1
+	assert.Nil(t, check())
+	assert.Nil(t, bc.txPool.Push(tx))
+}
+
+func TestTransaction_ExpiredAtHashCompatibility(t *testing.T) {
+	gasLimit, _ := util.NewUint128FromInt(200000)
+	tx := &Transaction{
+		from:      &Address{address: []byte("from")},
+		to:        &Address{address: []byte("to")},
+		value:     util.NewUint128(),
+		nonce:     1,
+		timestamp: 1516464510,
+		data:      &corepb.Data{Type: TxPayloadBinaryType, Payload: []byte("hello")},
+		chainID:   100,
+		gasPrice:  TransactionGasPrice,
+		gasLimit:  gasLimit,
+	}
+
+	// txs without expiration must hash exactly as before ExpiredAt was introduced.
+	hash, err := tx.calHash()
+	assert.Nil(t, err)
+	assert.Equal(t, "81b25f3f495224558053110d178d2391a8dc8a5bca6891f1969e49081ee4ea93", hash.String())
+
+	tx.expiredAt = 1516464600
+	hash, err = tx.calHash()
+	assert.Nil(t, err)
+	assert.Equal(t, "003bf6e061e0bcdaa33ac0ed9a7ebec898ab63b5409546d3e173342428535f5e", hash.String())
+}
+
+func TestTransaction_ExpiredAt(t *testing.T) {
+	defer func(height uint64) { ExpiredAtForkHeight = height }(ExpiredAtForkHeight)
+
+	neb := testNeb(t)
+	bc := neb.chain
+
+	from := mockAddress()
+	to := mockAddress()
+	ks := keystore.DefaultKS
+	key, _ := ks.GetUnlocked(from.String())
+	signature, _ := crypto.NewSignature(keystore.SECP256K1)
+	signature.InitSign(key.(keystore.PrivateKey))
+	gasLimit, _ := util.NewUint128FromInt(200000)
+
+	_, err := NewTransactionWithExpiration(bc.ChainID(), from, to, util.NewUint128(), 1, TxPayloadBinaryType, nil, TransactionGasPrice, gasLimit, -1)
+	assert.Equal(t, ErrInvalidExpiredAt, err)
+
+	block, err := bc.NewBlock(from)
+	assert.Nil(t, err)
+	block.header.timestamp = time.Now().Unix()
+
+	// txs with expiredAt are rejected by pool and block before the fork.
+	ExpiredAtForkHeight = block.Height() + 1
+	tx, err := NewTransactionWithExpiration(bc.ChainID(), from, to, util.NewUint128(), 1, TxPayloadBinaryType, nil, TransactionGasPrice, gasLimit, block.Timestamp()+BlockInterval)
+	assert.Nil(t, err)
+	assert.Nil(t, tx.Sign(signature))
+	assert.Equal(t, ErrTxExpiredAtNotActivated, bc.txPool.Push(tx))
+	ws, err := block.WorldState().Prepare(tx.Hash().String())
+	assert.Nil(t, err)
+	giveback, err := checkTransaction(tx, block, ws)
+	assert.False(t, giveback)
+	assert.Equal(t, ErrTxExpiredAtNotActivated, err)
+	assert.Nil(t, ws.Close())
+	ExpiredAtForkHeight = block.Height()
+
+	expiredAt := block.Timestamp() - 1
+	tx, err = NewTransactionWithExpiration(bc.ChainID(), from, to, util.NewUint128(), 1, TxPayloadBinaryType, nil, TransactionGasPrice, gasLimit, expiredAt)
+	assert.Nil(t, err)
+	assert.Nil(t, tx.Sign(signature))
+	assert.Equal(t, expiredAt, tx.ExpiredAt())
+	assert.True(t, tx.IsExpired(block.Timestamp()))
+
+	// expiredAt survives proto round trip and is covered by the signature.
+	pbTx, err := tx.ToProto()
+	assert.Nil(t, err)
+	restored := new(Transaction)
+	assert.Nil(t, restored.FromProto(pbTx))
+	assert.Equal(t, expiredAt, restored.ExpiredAt())
+	assert.Nil(t, restored.VerifyIntegrity(bc.ChainID()))
+	restored.expiredAt = 0
+	assert.NotNil(t, restored.VerifyIntegrity(bc.ChainID()))
+
+	// expired tx is dropped permanently, not given back.
+	ws, err = block.WorldState().Prepare(tx.Hash().String())
+	assert.Nil(t, err)
+	giveback, err = CheckTransaction(tx, block, ws)
+	assert.False(t, giveback)
+	assert.Equal(t, ErrTransactionExpired, ErrorCause(err))
+	assert.Nil(t, ws.Close())
+
+	assert.Equal(t, ErrTransactionExpired, bc.txPool.Push(tx))
+
+	// tx not expired yet.
+	tx, err = NewTransactionWithExpiration(bc.ChainID(), from, to, util.NewUint128(), 1, TxPayloadBinaryType, nil, TransactionGasPrice, gasLimit, block.Timestamp()+BlockInterval)
+	assert.Nil(t, err)
+	assert.Nil(t, tx.Sign(signature))
+	assert.False(t, tx.IsExpired(block.Timestamp()))
+	ws, err = block.WorldState().Prepare(tx.Hash().String())
+	assert.Nil(t, err)
+	_, err = CheckTransaction(tx, block, ws)
+	assert.Nil(t, err)
+	assert.Nil(t, ws.Close())
+	assert.Nil(t, bc.txPool.Push(tx))
+}
+
 func Test1(t *testing.T) {
 	fmt.Println(len(hash.Sha3256([]byte("abc"))))
 }
+
+func TestTransaction_DataType(t *testing.T) {
+	tests := []struct {
+		dataType string
+		wantErr  error
+	}{
+		{TxPayloadBinaryType, nil},
+		{"custom type~", nil},
+		{strings.Repeat("a", MaxDataTypeLength), nil},
+		{"", ErrInvalidTxDataType},
+		{strings.Repeat("a", MaxDataTypeLength+1), ErrInvalidTxDataType},
+		{"bin\nary", ErrInvalidTxDataType},
+		{"\x7f", ErrInvalidTxDataType},
+		{"类型", ErrInvalidTxDataType},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.wantErr, CheckTxDataType(tt.dataType), tt.dataType)
+		_, err := NewTransaction(0, mockAddress(), mockAddress(), util.NewUint128(), 1, tt.dataType, nil, TransactionGasPrice, TransactionMaxGas)
+		assert.Equal(t, tt.wantErr, err, tt.dataType)
+	}
+
+	// nil data
+	tx := &Transaction{}
+	assert.Equal(t, "", tx.Type())
+	assert.Equal(t, 0, tx.DataLen())
+	assert.Nil(t, tx.Data())
+
+	// fuzz FromProto with hostile data.
+	pbTx, err := mockNormalTransaction(0, 1).ToProto()
+	assert.Nil(t, err)
+	r := rand.New(rand.NewSource(0))
+	for i := 0; i < 1000; i++ {
+		data := &corepb.Data{}
+		if r.Intn(10) > 0 {
+			typeBytes := make([]byte, r.Intn(2*MaxDataTypeLength))
+			r.Read(typeBytes)
+			data.Type = string(typeBytes)
+		}
+		if r.Intn(2) > 0 {
+			data.Payload = make([]byte, r.Intn(2*MaxDataBinPayloadLength))
+			r.Read(data.Payload)
+		}
+		pbTx.(*corepb.Transaction).Data = data
+		err := new(Transaction).FromRelayedProto(pbTx, 0)
+		if CheckTxDataType(data.Type) != nil {
+			assert.Equal(t, ErrInvalidTxDataType, err)
+		}
+	}
+
+	neb := testNeb(t)
+	bc := neb.chain
+	from := mockAddress()
+	key, _ := keystore.DefaultKS.GetUnlocked(from.String())
+	signature, _ := crypto.NewSignature(keystore.SECP256K1)
+	signature.InitSign(key.(keystore.PrivateKey))
+
+	// relayed tx with a 1MB type is rejected before signature verification.
+	tx = mockNormalTransaction(bc.ChainID(), 1)
+	tx.from = from
+	assert.Nil(t, tx.Sign(signature))
+	pbMsg, err := tx.ToProto()
+	assert.Nil(t, err)
+	pbMsg.(*corepb.Transaction).Data.Type = strings.Repeat("a", 1024*1024)
+	bytes, err := proto.Marshal(pbMsg)
+	assert.Nil(t, err)
+	pbTx = new(corepb.Transaction)
+	assert.Nil(t, proto.Unmarshal(bytes, pbTx))
+	assert.Equal(t, ErrInvalidTxDataType, new(Transaction).FromRelayedProto(pbTx, bc.ChainID()))
+	// but decoded in block, checked once linked.
+	assert.Nil(t, new(Transaction).FromProto(pbTx))
+
+	tx.data.Type = strings.Repeat("a", 1024*1024)
+	assert.Equal(t, ErrInvalidTxDataType, bc.txPool.Push(tx))
+
+	// unregistered type is rejected by pool after fork.
+	defer func(height uint64) { TxPayloadRegistryForkHeight = height }(TxPayloadRegistryForkHeight)
+	tx = mockTransaction(bc.ChainID(), 1, "custom", nil)
+	tx.from = from
+	assert.Nil(t, tx.Sign(signature))
+	TxPayloadRegistryForkHeight = bc.TailBlock().Height()
+	assert.Equal(t, ErrUnregisteredTxDataType, bc.txPool.Push(tx))
+	TxPayloadRegistryForkHeight = bc.TailBlock().Height() + 1
+	assert.Nil(t, bc.txPool.Push(tx))
+
+	// the data type of txs in block is checked from the fork at the linked height,
+	// whatever height the proto claims.
+	pbBlock, err := bc.TailBlock().ToProto()
+	assert.Nil(t, err)
+	pbBlock.(*corepb.Block).Header.ParentHash = bc.TailBlock().Hash()
+	pbBlock.(*corepb.Block).Transactions = []*corepb.Transaction{pbTx}
+	pbBlock.(*corepb.Block).Height = 0
+	block := new(Block)
+	assert.Nil(t, block.FromProto(pbBlock))
+	assert.Equal(t, ErrInvalidTxDataType, block.LinkParentBlock(bc, bc.TailBlock()))
+	TxPayloadRegistryForkHeight = bc.TailBlock().Height() + 2
+	assert.Nil(t, block.LinkParentBlock(bc, bc.TailBlock()))
+
+	// payload types are registered from their forks.
+	assert.True(t, IsRegisteredPayloadType(TxPayloadCallType, bc.TailBlock().Height()+1))
+	assert.False(t, IsRegisteredPayloadType(TxPayloadUpgradeType, bc.TailBlock().Height()+1))
+}
+
+func TestTransaction_JSON(t *testing.T) {
+	signTx := func(tx *Transaction) *Transaction {
+		from := mockAddress()
+		key, _ := keystore.DefaultKS.GetUnlocked(from.String())
+		signature, _ := crypto.NewSignature(keystore.SECP256K1)
+		signature.InitSign(key.(keystore.PrivateKey))
+		tx.from = from
+		assert.Nil(t, tx.Sign(signature))
+		return tx
+	}
+
+	txs := []*Transaction{
+		signTx(mockNormalTransaction(100, 1)),
+		signTx(mockDeployTransaction(100, 2)),
+		signTx(mockCallTransaction(100, 3, "totalSupply", "")),
+	}
+	expired := mockNormalTransaction(100, 4)
+	expired.expiredAt = expired.timestamp + 60
+	txs = append(txs, signTx(expired))
+
+	for _, tx := range txs {
+		data, err := json.Marshal(tx)
+		assert.Nil(t, err)
+
+		fields := make(map[string]interface{})
+		assert.Nil(t, json.Unmarshal(data, &fields))
+		for _, key := range []string{"chainID", "hash", "from", "to", "value", "nonce", "timestamp", "gasPrice", "gasLimit", "type", "payload", "alg", "sign"} {
+			assert.Contains(t, fields, key)
+		}
+		assert.Equal(t, tx.hash.String(), fields["hash"])
+
+		got := new(Transaction)
+		assert.Nil(t, json.Unmarshal(data, got))
+		assert.Equal(t, tx.hash, got.hash)
+		assert.Equal(t, tx.Type(), got.Type())
+		assert.Equal(t, tx.Data(), got.Data())
+		assert.Equal(t, tx.expiredAt, got.expiredAt)
+		assert.Nil(t, got.VerifyIntegrity(100))
+	}
+
+	tx := txs[1]
+	data, err := json.Marshal(tx)
+	assert.Nil(t, err)
+	fields := make(map[string]interface{})
+	assert.Nil(t, json.Unmarshal(data, &fields))
+
+	tests := []struct {
+		name    string
+		key     string
+		value   interface{}
+		wantErr error
+	}{
+		{"malformed hash", "hash", "0xzz", ErrInvalidTxJSONHex},
+		{"odd hash", "hash", "abc", ErrInvalidTxJSONHex},
+		{"malformed sign", "sign", "not hex", ErrInvalidTxJSONHex},
+		{"malformed payload", "payload", "!!!", ErrInvalidTxJSONBase64},
+		{"tampered payload", "payload", "e30=", ErrInvalidTransactionHash},
+		{"tampered nonce", "nonce", 100, ErrInvalidTransactionHash},
+		{"invalid type", "type", "", ErrInvalidTxDataType},
+		{"invalid gas price", "gasPrice", "0", ErrInvalidGasPrice},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			malformed := make(map[string]interface{})
+			for k, v := range fields {
+				malformed[k] = v
+			}
+			malformed[tt.key] = tt.value
+			data, err := json.Marshal(malformed)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.wantErr, json.Unmarshal(data, new(Transaction)))
+		})
+	}
+}
+
+type balanceObserverNvm struct {
+	exeErr   error
+	balances []*util.Uint128
+}
+
+type balanceObserverEngine struct {
+	mockEngine
+	nvm *balanceObserverNvm
+	tx  *Transaction
+	ws  WorldState
+}
+
+func (nvm *balanceObserverNvm) CreateEngine(block *Block, tx *Transaction, contract state.Account, ws WorldState) (SmartContractEngine, error) {
+	return &balanceObserverEngine{nvm: nvm, tx: tx, ws: ws}, nil
+}
+
+func (engine *balanceObserverEngine) DeployAndInit(source, sourceType, args string) (string, error) {
+	acc, err := engine.ws.GetOrCreateUserAccount(engine.tx.from.address)
+	if err != nil {
+		return "", err
+	}
+	engine.nvm.balances = append(engine.nvm.balances, acc.Balance())
+	return "", engine.nvm.exeErr
+}
+
+type eventEmitterNvm struct {
+	count int
+}
+
+type eventEmitterEngine struct {
+	mockEngine
+	nvm *eventEmitterNvm
+	tx  *Transaction
+	ws  WorldState
+}
+
+func (nvm *eventEmitterNvm) CreateEngine(block *Block, tx *Transaction, contract state.Account, ws WorldState) (SmartContractEngine, error) {
+	return &eventEmitterEngine{nvm: nvm, tx: tx, ws: ws}, nil
+}
+
+func (engine *eventEmitterEngine) DeployAndInit(source, sourceType, args string) (string, error) {
+	for i := 0; i < engine.nvm.count; i++ {
+		engine.ws.RecordEvent(engine.tx.hash, &state.Event{Topic: "chain.contract.transfer", Data: fmt.Sprintf("%d", i)})
+	}
+	return "", nil
+}
+
+func TestTransaction_ExecutionResultApart(t *testing.T) {
+	defer func(height uint64) { ExecutionResultForkHeight = height }(ExecutionResultForkHeight)
+
+	neb := testNeb(t)
+	bc := neb.chain
+
+	from, coinbase := mockAddress(), mockAddress()
+	balance, _ := util.NewUint128FromString("1000000000000000000")
+	bc.tailBlock.Begin()
+	acc, err := bc.tailBlock.worldState.GetOrCreateUserAccount(from.Bytes())
+	assert.Nil(t, err)
+	assert.Nil(t, acc.AddBalance(balance))
+	bc.tailBlock.Commit()
+
+	key, _ := keystore.DefaultKS.GetUnlocked(from.String())
+	signature, _ := crypto.NewSignature(keystore.SECP256K1)
+	signature.InitSign(key.(keystore.PrivateKey))
+	deployTx := mockDeployTransaction(bc.ChainID(), 1)
+	deployTx.from, deployTx.to = from, from
+	assert.Nil(t, deployTx.Sign(signature))
+	contract, err := deployTx.GenerateContractAddress()
+	assert.Nil(t, err)
+
+	userEvents := 10
+	for _, forkHeight := range []uint64{math.MaxUint64, bc.tailBlock.Height() + 1} {
+		ExecutionResultForkHeight = forkHeight
+		block, err := NewBlock(bc.ChainID(), coinbase, bc.tailBlock)
+		assert.Nil(t, err)
+		block.nvm = &eventEmitterNvm{count: userEvents}
+		block.dependency = dag.NewDag()
+		assert.Nil(t, block.dependency.AddNode(deployTx.Hash().String()))
+		block.transactions = append(block.transactions, deployTx)
+		assert.Nil(t, block.execute())
+		ws := block.WorldState()
+
+		// user events and the deploy event, the result event is apart since the fork.
+		events, err := ws.FetchEvents(deployTx.Hash())
+		assert.Nil(t, err)
+		if forkHeight == math.MaxUint64 {
+			assert.Equal(t, userEvents+2, len(events))
+			assert.Equal(t, TopicTransactionExecutionResult, events[len(events)-1].Topic)
+		} else {
+			assert.Equal(t, userEvents+1, len(events))
+			for _, event := range events {
+				assert.NotEqual(t, TopicTransactionExecutionResult, event.Topic)
+			}
+		}
+		for i := 0; i < userEvents; i++ {
+			assert.Equal(t, fmt.Sprintf("%d", i), events[i].Data)
+		}
+
+		event, err := FetchExecutionResultEvent(deployTx.Hash(), ws)
+		assert.Nil(t, err)
+		txEvent := TransactionEvent{}
+		assert.Nil(t, json.Unmarshal([]byte(event.Data), &txEvent))
+		assert.Equal(t, int8(TxExecutionSuccess), txEvent.Status)
+		assert.Equal(t, contract.String(), txEvent.ContractAddress)
+
+		events, err = fetchEventsWithExecutionResult(deployTx.Hash(), ws)
+		assert.Nil(t, err)
+		assert.Equal(t, userEvents+2, len(events))
+		assert.Equal(t, event, events[len(events)-1])
+
+		_, err = CheckContract(contract, ws)
+		assert.Nil(t, err)
+		block.RollBack()
+	}
+}
+
+func TestTransaction_GasRefund(t *testing.T) {
+	defer func(height uint64) { GasRefundForkHeight = height }(GasRefundForkHeight)
+
+	neb := testNeb(t)
+	bc := neb.chain
+
+	from, coinbase := mockAddress(), mockAddress()
+	balance, _ := util.NewUint128FromString("1000000000000000000")
+	bc.tailBlock.Begin()
+	acc, err := bc.tailBlock.worldState.GetOrCreateUserAccount(from.Bytes())
+	assert.Nil(t, err)
+	assert.Nil(t, acc.AddBalance(balance))
+	bc.tailBlock.Commit()
+	bc.tailBlock.header.stateRoot = bc.tailBlock.worldState.AccountsRoot()
+	assert.Nil(t, bc.StoreBlockToStorage(bc.tailBlock))
+
+	key, _ := keystore.DefaultKS.GetUnlocked(from.String())
+	signature, _ := crypto.NewSignature(keystore.SECP256K1)
+	signature.InitSign(key.(keystore.PrivateKey))
+	gasLimit, _ := util.NewUint128FromInt(2000000)
+	limitedFee, _ := gasLimit.Mul(TransactionGasPrice)
+
+	type result struct {
+		observed []*util.Uint128
+		from     *util.Uint128
+		coinbase *util.Uint128
+	}
+	execute := func(forkHeight uint64, exeErr error) *result {
+		GasRefundForkHeight = forkHeight
+		nvm := &balanceObserverNvm{exeErr: exeErr}
+		block, err := NewBlock(bc.ChainID(), coinbase, bc.tailBlock)
+		assert.Nil(t, err)
+		block.nvm = nvm
+		block.dependency = dag.NewDag()
+		for nonce := uint64(1); nonce <= 2; nonce++ {
+			tx := mockDeployTransaction(bc.ChainID(), nonce)
+			tx.from, tx.to = from, from
+			tx.gasLimit = gasLimit
+			assert.Nil(t, tx.Sign(signature))
+			assert.Nil(t, block.dependency.AddNode(tx.Hash().String()))
+			if len(block.transactions) > 0 {
+				assert.Nil(t, block.dependency.AddEdge(block.transactions[0].Hash().String(), tx.Hash().String()))
+			}
+			block.transactions = append(block.transactions, tx)
+		}
+		assert.Nil(t, block.execute())
+
+		fromAcc, err := block.WorldState().GetOrCreateUserAccount(from.Bytes())
+		assert.Nil(t, err)
+		coinbaseAcc, err := block.WorldState().GetOrCreateUserAccount(coinbase.Bytes())
+		assert.Nil(t, err)
+		r := &result{observed: nvm.balances, from: fromAcc.Balance(), coinbase: coinbaseAcc.Balance()}
+		block.RollBack()
+		return r
+	}
+
+	for _, exeErr := range []error{nil, ErrExecutionFailed} {
+		legacy := execute(math.MaxUint64, exeErr)
+		refund := execute(bc.tailBlock.Height()+1, exeErr)
+
+		// same fee is charged under both rules.
+		assert.Equal(t, legacy.from, refund.from)
+		assert.Equal(t, legacy.coinbase, refund.coinbase)
+		assert.True(t, legacy.from.Cmp(balance) < 0)
+
+		// legacy rules charge gas after block execution.
+		assert.Equal(t, balance, legacy.observed[0])
+		assert.Equal(t, balance, legacy.observed[1])
+
+		// refund rules reserve limited fee before execution, and charge gas at once.
+		reserved, _ := balance.Sub(limitedFee)
+		assert.Equal(t, reserved, refund.observed[0])
+		charged, _ := balance.Sub(refund.from)
+		perTxFee, _ := charged.Div(util.NewUint128FromUint(2))
+		reserved, _ = reserved.Sub(perTxFee)
+		assert.Equal(t, reserved, refund.observed[1])
+	}
+}
+
+type acceptRecorderNvm struct {
+	exeErr    error
+	functions []string
+}
+
+type acceptRecorderEngine struct {
+	mockEngine
+	nvm *acceptRecorderNvm
+}
+
+func (nvm *acceptRecorderNvm) CreateEngine(block *Block, tx *Transaction, contract state.Account, ws WorldState) (SmartContractEngine, error) {
+	return &acceptRecorderEngine{nvm: nvm}, nil
+}
+
+func (engine *acceptRecorderEngine) Call(source, sourceType, function, args string) (string, error) {
+	engine.nvm.functions = append(engine.nvm.functions, function)
+	return "", engine.nvm.exeErr
+}
+
+func TestTransaction_ContractAccept(t *testing.T) {
+	defer func(height uint64) { ContractAcceptForkHeight = height }(ContractAcceptForkHeight)
+	defer func(height uint64) { ReceiptsForkHeight = height }(ReceiptsForkHeight)
+	ReceiptsForkHeight = 0
+
+	neb := testNeb(t)
+	bc := neb.chain
+
+	from := mockAddress()
+	balance, _ := util.NewUint128FromString("1000000000000000000")
+	bc.tailBlock.Begin()
+	acc, err := bc.tailBlock.worldState.GetOrCreateUserAccount(from.Bytes())
+	assert.Nil(t, err)
+	assert.Nil(t, acc.AddBalance(balance))
+	bc.tailBlock.Commit()
+	bc.tailBlock.header.stateRoot = bc.tailBlock.worldState.AccountsRoot()
+	assert.Nil(t, bc.StoreBlockToStorage(bc.tailBlock))
+
+	key, _ := keystore.DefaultKS.GetUnlocked(from.String())
+	signature, _ := crypto.NewSignature(keystore.SECP256K1)
+	signature.InitSign(key.(keystore.PrivateKey))
+
+	type result struct {
+		functions []string
+		simulated *SimulateResult
+		receipt   *TransactionReceipt
+		contract  *util.Uint128
+	}
+	transfer := func(forkHeight uint64, exeErr error) *result {
+		ContractAcceptForkHeight = forkHeight
+		nvm := &acceptRecorderNvm{exeErr: exeErr}
+		block, err := NewBlock(bc.ChainID(), mockAddress(), bc.tailBlock)
+		assert.Nil(t, err)
+		block.nvm = nvm
+
+		deployTx := mockDeployTransaction(bc.ChainID(), 1)
+		deployTx.from, deployTx.to = from, from
+		assert.Nil(t, deployTx.Sign(signature))
+		contract, err := deployTx.GenerateContractAddress()
+		assert.Nil(t, err)
+
+		transferTx := mockNormalTransaction(bc.ChainID(), 2)
+		transferTx.from, transferTx.to = from, contract
+		transferTx.value = util.NewUint128FromUint(100)
+		assert.Nil(t, transferTx.Sign(signature))
+
+		r := &result{}
+		for _, tx := range []*Transaction{deployTx, transferTx} {
+			if tx == transferTx {
+				r.simulated, err = tx.simulateExecutionInTxWorldState(block, TransactionMaxGas, 0)
+				assert.Nil(t, err)
+			}
+			txWorldState, err := block.WorldState().Prepare(tx.Hash().String())
+			assert.Nil(t, err)
+			_, err = block.ExecuteTransaction(tx, txWorldState)
+			assert.Nil(t, err)
+			_, err = txWorldState.CheckAndUpdate()
+			assert.Nil(t, err)
+		}
+
+		r.functions = nvm.functions
+		r.receipt, err = GetTransactionReceipt(transferTx.Hash(), block.WorldState())
+		assert.Nil(t, err)
+		contractAcc, err := block.WorldState().GetOrCreateUserAccount(contract.Bytes())
+		assert.Nil(t, err)
+		r.contract = contractAcc.Balance()
+		block.RollBack()
+		return r
+	}
+
+	// legacy rules transfer the value into contract without calling it.
+	legacy := transfer(math.MaxUint64, nil)
+	assert.Equal(t, 0, len(legacy.functions))
+	assert.Equal(t, uint32(TxExecutionSuccess), legacy.receipt.Status())
+	assert.Equal(t, "100", legacy.contract.String())
+
+	// contract accepts the transfer, accept is called in both simulation and execution.
+	accepted := transfer(0, nil)
+	assert.Equal(t, []string{ContractAcceptFunction, ContractAcceptFunction}, accepted.functions)
+	assert.Equal(t, uint32(TxExecutionSuccess), accepted.receipt.Status())
+	assert.Equal(t, legacy.contract.String(), accepted.contract.String())
+	assert.True(t, accepted.receipt.GasUsed().Cmp(legacy.receipt.GasUsed()) > 0)
+	assert.Nil(t, accepted.simulated.Err)
+	assert.Equal(t, accepted.receipt.GasUsed().String(), accepted.simulated.GasUsed.String())
+
+	// contract without accept rejects the transfer, and simulation reports the same error.
+	rejected := transfer(0, ErrExecutionFailed)
+	assert.Equal(t, uint32(TxExecutionFailed), rejected.receipt.Status())
+	assert.Equal(t, ErrExecutionFailed.Error(), rejected.receipt.Error())
+	assert.Equal(t, "0", rejected.contract.String())
+	assert.Equal(t, ErrExecutionFailed, rejected.simulated.Err)
+}
+
+func TestTransaction_Payer(t *testing.T) {
+	defer func(height uint64) { TxPayerForkHeight = height }(TxPayerForkHeight)
+	defer func(height uint64) { GasRefundForkHeight = height }(GasRefundForkHeight)
+
+	neb := testNeb(t)
+	bc := neb.chain
+
+	from, to, payer, coinbase := mockAddress(), mockAddress(), mockAddress(), mockAddress()
+	value := util.NewUint128FromUint(100)
+	balance, _ := util.NewUint128FromString("1000000000000000000")
+	bc.tailBlock.Begin()
+	fromAcc, err := bc.tailBlock.worldState.GetOrCreateUserAccount(from.Bytes())
+	assert.Nil(t, err)
+	assert.Nil(t, fromAcc.AddBalance(value))
+	payerAcc, err := bc.tailBlock.worldState.GetOrCreateUserAccount(payer.Bytes())
+	assert.Nil(t, err)
+	assert.Nil(t, payerAcc.AddBalance(balance))
+	bc.tailBlock.Commit()
+	bc.tailBlock.header.stateRoot = bc.tailBlock.worldState.AccountsRoot()
+	assert.Nil(t, bc.StoreBlockToStorage(bc.tailBlock))
+
+	newSignature := func(addr *Address) keystore.Signature {
+		key, _ := keystore.DefaultKS.GetUnlocked(addr.String())
+		signature, _ := crypto.NewSignature(keystore.SECP256K1)
+		signature.InitSign(key.(keystore.PrivateKey))
+		return signature
+	}
+
+	tx := mockNormalTransaction(bc.ChainID(), 1)
+	tx.from, tx.to, tx.value = from, to, value
+	legacyHash, err := tx.calHash()
+	assert.Nil(t, err)
+	assert.Equal(t, ErrTransactionWithoutPayer, tx.SignAsPayer(newSignature(payer)))
+
+	// the payer is covered by the hash, both sender and payer sign it.
+	tx.SetPayer(payer)
+	assert.Nil(t, tx.Sign(newSignature(from)))
+	assert.NotEqual(t, legacyHash, tx.Hash())
+	assert.Equal(t, ErrInvalidTransactionPayerSigner, tx.VerifyIntegrity(bc.ChainID()))
+	assert.Nil(t, tx.SignAsPayer(newSignature(from)))
+	assert.Equal(t, ErrInvalidTransactionPayerSigner, tx.VerifyIntegrity(bc.ChainID()))
+	assert.Nil(t, tx.SignAsPayer(newSignature(payer)))
+	assert.Nil(t, tx.VerifyIntegrity(bc.ChainID()))
+
+	// payer survives proto & json round trip.
+	pbTx, err := tx.ToProto()
+	assert.Nil(t, err)
+	restored := new(Transaction)
+	assert.Nil(t, restored.FromProto(pbTx))
+	assert.Equal(t, payer, restored.Payer())
+	assert.Nil(t, restored.VerifyIntegrity(bc.ChainID()))
+	data, err := json.Marshal(tx)
+	assert.Nil(t, err)
+	restored = new(Transaction)
+	assert.Nil(t, restored.UnmarshalJSON(data))
+	assert.Equal(t, payer, restored.Payer())
+	assert.Nil(t, restored.VerifyIntegrity(bc.ChainID()))
+
+	// txs with payer are rejected before the fork.
+	TxPayerForkHeight = math.MaxUint64
+	assert.Equal(t, ErrTxPayerNotActivated, bc.txPool.Push(tx))
+	block, err := NewBlock(bc.ChainID(), coinbase, bc.tailBlock)
+	assert.Nil(t, err)
+	ws, err := block.WorldState().Prepare(tx.Hash().String())
+	assert.Nil(t, err)
+	giveback, err := CheckTransaction(tx, block, ws)
+	assert.False(t, giveback)
+	assert.Equal(t, ErrTxPayerNotActivated, ErrorCause(err))
+	assert.Nil(t, ws.Close())
+	block.RollBack()
+
+	TxPayerForkHeight = bc.tailBlock.Height() + 1
+	assert.Nil(t, bc.txPool.Push(tx))
+
+	// the sender only pays value, the payer pays the gas under both gas rules.
+	var charged []*util.Uint128
+	for _, forkHeight := range []uint64{math.MaxUint64, bc.tailBlock.Height() + 1} {
+		GasRefundForkHeight = forkHeight
+		block, err := NewBlock(bc.ChainID(), coinbase, bc.tailBlock)
+		assert.Nil(t, err)
+		block.dependency = dag.NewDag()
+		assert.Nil(t, block.dependency.AddNode(tx.Hash().String()))
+		block.transactions = append(block.transactions, tx)
+		assert.Nil(t, block.execute())
+
+		fromAcc, err := block.WorldState().GetOrCreateUserAccount(from.Bytes())
+		assert.Nil(t, err)
+		toAcc, err := block.WorldState().GetOrCreateUserAccount(to.Bytes())
+		assert.Nil(t, err)
+		payerAcc, err := block.WorldState().GetOrCreateUserAccount(payer.Bytes())
+		assert.Nil(t, err)
+		assert.Equal(t, "0", fromAcc.Balance().String())
+		assert.Equal(t, value.String(), toAcc.Balance().String())
+		fee, err := balance.Sub(payerAcc.Balance())
+		assert.Nil(t, err)
+		charged = append(charged, fee)
+		block.RollBack()
+	}
+	assert.True(t, charged[0].Cmp(util.NewUint128()) > 0)
+	assert.Equal(t, charged[0].String(), charged[1].String())
+}
+
+func TestTransaction_ContractAddressWithSalt(t *testing.T) {
+	defer func(height uint64) { DeploySaltForkHeight = height }(DeploySaltForkHeight)
+	defer func(height uint64) { ReceiptsForkHeight = height }(ReceiptsForkHeight)
+	ReceiptsForkHeight = 0
+
+	neb := testNeb(t)
+	bc := neb.chain
+
+	from, coinbase := mockAddress(), mockAddress()
+	balance, _ := util.NewUint128FromString("1000000000000000000")
+	bc.tailBlock.Begin()
+	acc, err := bc.tailBlock.worldState.GetOrCreateUserAccount(from.Bytes())
+	assert.Nil(t, err)
+	assert.Nil(t, acc.AddBalance(balance))
+	bc.tailBlock.Commit()
+	bc.tailBlock.header.stateRoot = bc.tailBlock.worldState.AccountsRoot()
+	assert.Nil(t, bc.StoreBlockToStorage(bc.tailBlock))
+
+	source := `"use strict";var Contract=function(){};Contract.prototype={init:function(){}};module.exports=Contract;`
+	newDeployTx := func(chainID uint32, salt string) *Transaction {
+		payload, err := NewDeployPayloadWithSalt(source, SourceTypeJavaScript, "", salt)
+		assert.Nil(t, err)
+		data, err := payload.ToBytes()
+		assert.Nil(t, err)
+		tx := mockTransaction(chainID, 1, TxPayloadDeployType, data)
+		tx.from, tx.to = from, from
+		return tx
+	}
+
+	// payloads without salt serialize and derive the address as before.
+	legacy := newDeployTx(bc.ChainID(), "")
+	assert.NotContains(t, string(legacy.Data()), "Salt")
+	legacyAddr, err := legacy.GenerateContractAddress()
+	assert.Nil(t, err)
+	addr, err := legacy.ContractAddressAtHeight(0)
+	assert.Nil(t, err)
+	assert.Equal(t, legacyAddr, addr)
+	_, err = legacy.GenerateContractAddressWithSalt()
+	assert.Equal(t, ErrInvalidDeploySalt, err)
+
+	_, err = NewDeployPayloadWithSalt(source, SourceTypeJavaScript, "", strings.Repeat("s", MaxDeploySaltLength+1))
+	assert.Equal(t, ErrInvalidDeploySalt, err)
+
+	// the salted address depends on chainID & salt.
+	salted := newDeployTx(bc.ChainID(), "salt")
+	saltedAddr, err := salted.GenerateContractAddressWithSalt()
+	assert.Nil(t, err)
+	assert.NotEqual(t, legacyAddr, saltedAddr)
+	otherChainAddr, err := newDeployTx(bc.ChainID()+1, "salt").GenerateContractAddressWithSalt()
+	assert.Nil(t, err)
+	assert.NotEqual(t, saltedAddr, otherChainAddr)
+	otherSaltAddr, err := newDeployTx(bc.ChainID(), "pepper").GenerateContractAddressWithSalt()
+	assert.Nil(t, err)
+	assert.NotEqual(t, saltedAddr, otherSaltAddr)
+
+	// the salt is ignored before the fork.
+	addr, err = salted.ContractAddressAtHeight(bc.tailBlock.Height() + 1)
+	assert.Nil(t, err)
+	assert.Equal(t, legacyAddr, addr)
+
+	DeploySaltForkHeight = bc.tailBlock.Height() + 1
+	addr, err = salted.ContractAddressAtHeight(bc.tailBlock.Height() + 1)
+	assert.Nil(t, err)
+	assert.Equal(t, saltedAddr, addr)
+
+	// the deployed address is recorded in receipt & result event.
+	key, _ := keystore.DefaultKS.GetUnlocked(from.String())
+	signature, _ := crypto.NewSignature(keystore.SECP256K1)
+	signature.InitSign(key.(keystore.PrivateKey))
+	assert.Nil(t, salted.Sign(signature))
+
+	block, err := NewBlock(bc.ChainID(), coinbase, bc.tailBlock)
+	assert.Nil(t, err)
+	block.dependency = dag.NewDag()
+	assert.Nil(t, block.dependency.AddNode(salted.Hash().String()))
+	block.transactions = append(block.transactions, salted)
+	assert.Nil(t, block.execute())
+
+	_, err = block.WorldState().GetContractAccount(saltedAddr.Bytes())
+	assert.Nil(t, err)
+	receipt, err := block.GetTransactionReceipt(salted.Hash())
+	assert.Nil(t, err)
+	assert.Equal(t, saltedAddr, receipt.ContractAddress())
+	event, err := block.FetchExecutionResultEvent(salted.Hash())
+	assert.Nil(t, err)
+	txEvent := TransactionEvent{}
+	assert.Nil(t, json.Unmarshal([]byte(event.Data), &txEvent))
+	assert.Equal(t, saltedAddr.String(), txEvent.ContractAddress)
+	block.RollBack()
+}
+
+func TestTransaction_ContractDeployEvent(t *testing.T) {
+	neb := testNeb(t)
+	bc := neb.chain
+
+	from, coinbase := mockAddress(), mockAddress()
+	balance, _ := util.NewUint128FromString("1000000000000000000")
+	bc.tailBlock.Begin()
+	acc, err := bc.tailBlock.worldState.GetOrCreateUserAccount(from.Bytes())
+	assert.Nil(t, err)
+	assert.Nil(t, acc.AddBalance(balance))
+	bc.tailBlock.Commit()
+
+	deployTx := mockDeployTransaction(bc.ChainID(), 1)
+	deployTx.from, deployTx.to = from, from
+	key, _ := keystore.DefaultKS.GetUnlocked(from.String())
+	signature, _ := crypto.NewSignature(keystore.SECP256K1)
+	signature.InitSign(key.(keystore.PrivateKey))
+	assert.Nil(t, deployTx.Sign(signature))
+	contractAddr, err := deployTx.GenerateContractAddress()
+	assert.Nil(t, err)
+
+	block, err := NewBlock(bc.ChainID(), coinbase, bc.tailBlock)
+	assert.Nil(t, err)
+	block.dependency = dag.NewDag()
+	assert.Nil(t, block.dependency.AddNode(deployTx.Hash().String()))
+	block.transactions = append(block.transactions, deployTx)
+	assert.Nil(t, block.execute())
+
+	// the deploy event is followed by the result event.
+	events, err := block.WorldState().FetchEvents(deployTx.Hash())
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(events))
+
+	assert.Equal(t, TopicContractDeploy, events[0].Topic)
+	deployEvent := ContractDeployEvent{}
+	assert.Nil(t, json.Unmarshal([]byte(events[0].Data), &deployEvent))
+	assert.Equal(t, contractAddr.String(), deployEvent.ContractAddress)
+	assert.Equal(t, from.String(), deployEvent.Deployer)
+	payload, err := LoadDeployPayload(deployTx.Data())
+	assert.Nil(t, err)
+	assert.Equal(t, payload.SourceHash().String(), deployEvent.SourceHash)
+
+	assert.Equal(t, TopicTransactionExecutionResult, events[1].Topic)
+	txEvent := TransactionEvent{}
+	assert.Nil(t, json.Unmarshal([]byte(events[1].Data), &txEvent))
+	assert.Equal(t, contractAddr.String(), txEvent.ContractAddress)
+
+	// the birth place check still finds the result event.
+	_, err = CheckContract(contractAddr, block.WorldState())
+	assert.Nil(t, err)
+	block.RollBack()
+}
+
+func TestTransaction_TimestampAheadOfBlock(t *testing.T) {
+	defer func(height uint64) { TxTimestampForkHeight = height }(TxTimestampForkHeight)
+
+	neb := testNeb(t)
+	bc := neb.chain
+	block, err := bc.NewBlock(mockAddress())
+	assert.Nil(t, err)
+
+	tx := mockNormalTransaction(bc.ChainID(), 1)
+	tx.timestamp = block.Timestamp() + MaxTxTimestampAheadOfBlock + 1
+
+	// not checked before the fork.
+	_, err = CheckTransaction(tx, block, block.worldState)
+	assert.NotEqual(t, ErrTxTimestampAheadOfBlock, ErrorCause(err))
+
+	TxTimestampForkHeight = block.Height()
+	giveback, err := CheckTransaction(tx, block, block.worldState)
+	assert.False(t, giveback)
+	assert.Equal(t, ErrTxTimestampAheadOfBlock, ErrorCause(err))
+
+	tx.timestamp = block.Timestamp() + MaxTxTimestampAheadOfBlock
+	_, err = CheckTransaction(tx, block, block.worldState)
+	assert.NotEqual(t, ErrTxTimestampAheadOfBlock, ErrorCause(err))
+}
+
+func TestTransaction_FromProtoWithChainID(t *testing.T) {
+	const chainA, chainB = uint32(100), uint32(1001)
+
+	serialize := func(chainID uint32, mutate func(*corepb.Transaction)) []byte {
+		tx := mockNormalTransaction(chainID, 1)
+		pbTx, err := tx.ToProto()
+		assert.Nil(t, err)
+		if mutate != nil {
+			mutate(pbTx.(*corepb.Transaction))
+		}
+		data, err := proto.Marshal(pbTx)
+		assert.Nil(t, err)
+		return data
+	}
+	badFrom := func(msg *corepb.Transaction) { msg.From = []byte("foreign") }
+	badValue := func(msg *corepb.Transaction) { msg.Value = []byte{0x01} }
+
+	tests := []struct {
+		name       string
+		data       []byte
+		chainErr   error
+		noChainErr error
+	}{
+		{"same chain", serialize(chainA, nil), nil, nil},
+		{"foreign chain", serialize(chainB, nil), ErrInvalidChainID, nil},
+		{"same chain bad address", serialize(chainA, badFrom), ErrInvalidAddressFormat, ErrInvalidAddressFormat},
+		{"foreign chain bad address", serialize(chainB, badFrom), ErrInvalidChainID, ErrInvalidAddressFormat},
+		{"foreign chain bad value", serialize(chainB, badValue), ErrInvalidChainID, util.ErrUint128InvalidBytesSize},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pbTx := new(corepb.Transaction)
+			assert.Nil(t, proto.Unmarshal(tt.data, pbTx))
+			assert.Equal(t, tt.chainErr, new(Transaction).FromProtoWithChainID(pbTx, chainA))
+			assert.Equal(t, tt.noChainErr, new(Transaction).FromProto(pbTx))
+		})
+	}
+
+	// a block of another chain is rejected by its header.
+	neb := testNeb(t)
+	block, err := neb.chain.NewBlock(mockAddress())
+	assert.Nil(t, err)
+	pbBlock, err := block.ToProto()
+	assert.Nil(t, err)
+	assert.Equal(t, ErrInvalidChainID, new(Block).FromProtoWithChainID(pbBlock, neb.chain.ChainID()+1))
+	assert.Nil(t, new(Block).FromProtoWithChainID(pbBlock, neb.chain.ChainID()))
+}
+
+func TestTransaction_TxErrorCode(t *testing.T) {
+	// the codes are on chain, they must never change.
+	stableCodes := map[error]uint32{
+		nil:                        0,
+		ErrInvalidArgument:         1,
+		ErrTransactionExpired:      100,
+		ErrTxTimestampAheadOfBlock: 101,
+		ErrTxPayerNotActivated:     102,
+		ErrSmallTransactionNonce:   103,
+		ErrLargeTransactionNonce:   104,
+		ErrAccountFrozen:           105,
+		ErrDuplicatedTransaction:   106,
+		ErrGasFeeOverflow:          107,
+		ErrInsufficientBalance:     108,
+		ErrGasCntOverflow:          109,
+		ErrOutOfGasLimit:           110,
+		ErrInvalidTransfer:         111,
+		ErrInvalidTxPayloadType:    112,
+		ErrTxAlgNotActivated:       113,
+		ErrTxExpiredAtNotActivated: 114,
+		ErrExecutionFailed:         200,
+	}
+	for err, code := range stableCodes {
+		assert.Equal(t, code, uint32(TxErrorCodeOf(err)), "%v", err)
+	}
+	assert.Equal(t, TxErrorCodeExecutionFailed, newTxError(ErrInvalidArgument, false, true).(*TxError).Code)
+
+	neb := testNeb(t)
+	bc := neb.chain
+	from := mockAddress()
+	fee, _ := TransactionMaxGas.Mul(TransactionGasPrice)
+	fund, _ := fee.Add(fee)
+
+	block, err := bc.NewBlock(mockAddress())
+	assert.Nil(t, err)
+	acc, err := block.worldState.GetOrCreateUserAccount(from.address)
+	assert.Nil(t, err)
+	assert.Nil(t, acc.AddBalance(fund))
+	block.Commit()
+	block, err = bc.NewBlockFromParent(bc.tailBlock.header.coinbase, block)
+	assert.Nil(t, err)
+
+	// check errors keep the sentinel & giveback decision.
+	tx := mockNormalTransaction(bc.ChainID(), 2)
+	tx.from = from
+	giveback, err := CheckTransaction(tx, block, block.WorldState())
+	assert.True(t, giveback)
+	assert.Equal(t, ErrLargeTransactionNonce, ErrorCause(err))
+	assert.Equal(t, ErrLargeTransactionNonce.Error(), err.Error())
+	txErr, ok := err.(*TxError)
+	assert.True(t, ok)
+	assert.Equal(t, TxErrorCodeLargeNonce, txErr.Code)
+	assert.True(t, txErr.Giveback)
+	assert.False(t, txErr.GasCharged)
+	assert.True(t, TxErrorGiveback(err))
+
+	// the errors not coded are system errors, given back.
+	assert.True(t, TxErrorGiveback(ErrInvalidArgument))
+	assert.False(t, TxErrorGiveback(nil))
+	assert.Equal(t, ErrInvalidArgument, ErrorCause(ErrInvalidArgument))
+
+	// the code of the charged execution error is recorded in result event from the fork.
+	defer func(height uint64) { TxErrorCodeForkHeight = height }(TxErrorCodeForkHeight)
+	execute := func(nonce uint64) *TransactionEvent {
+		tx := mockNormalTransaction(bc.ChainID(), nonce)
+		tx.from = from
+		tx.gasLimit = TransactionMaxGas
+		tx.value = fund
+		txWorldState, err := block.WorldState().Prepare(tx.Hash().String())
+		assert.Nil(t, err)
+		giveback, err := VerifyExecution(tx, block, txWorldState)
+		assert.False(t, giveback)
+		assert.Nil(t, err)
+		_, err = txWorldState.CheckAndUpdate()
+		assert.Nil(t, err)
+
+		event, err := FetchExecutionResultEvent(tx.Hash(), block.WorldState())
+		assert.Nil(t, err)
+		txEvent := &TransactionEvent{}
+		assert.Nil(t, json.Unmarshal([]byte(event.Data), txEvent))
+		return txEvent
+	}
+	txEvent := execute(1)
+	assert.Equal(t, ErrInsufficientBalance.Error(), txEvent.Error)
+	assert.Equal(t, uint32(0), txEvent.ErrorCode)
+
+	TxErrorCodeForkHeight = block.Height()
+	txEvent = execute(2)
+	assert.Equal(t, ErrInsufficientBalance.Error(), txEvent.Error)
+	assert.Equal(t, uint32(TxErrorCodeInsufficientBalance), txEvent.ErrorCode)
+}
+
+func TestTransaction_GasTrace(t *testing.T) {
+	defer func(height uint64) { GasTraceForkHeight = height }(GasTraceForkHeight)
+	defer func(height uint64) { ReceiptsForkHeight = height }(ReceiptsForkHeight)
+	ReceiptsForkHeight = 0
+
+	neb := testNeb(t)
+	bc := neb.chain
+
+	from, coinbase := mockAddress(), mockAddress()
+	balance, _ := util.NewUint128FromString("1000000000000000000")
+	bc.tailBlock.Begin()
+	acc, err := bc.tailBlock.worldState.GetOrCreateUserAccount(from.Bytes())
+	assert.Nil(t, err)
+	assert.Nil(t, acc.AddBalance(balance))
+	bc.tailBlock.Commit()
+
+	key, _ := keystore.DefaultKS.GetUnlocked(from.String())
+	signature, _ := crypto.NewSignature(keystore.SECP256K1)
+	signature.InitSign(key.(keystore.PrivateKey))
+	newDeployTx := func(nonce uint64, gasLimit *util.Uint128) *Transaction {
+		tx := mockDeployTransaction(bc.ChainID(), nonce)
+		tx.from, tx.to = from, from
+		tx.gasLimit = gasLimit
+		assert.Nil(t, tx.Sign(signature))
+		return tx
+	}
+
+	gasLimit, _ := util.NewUint128FromInt(2000000)
+	tx := newDeployTx(1, gasLimit)
+	baseGas, err := tx.GasCountOfTxBase()
+	assert.Nil(t, err)
+	payload, err := tx.LoadPayload()
+	assert.Nil(t, err)
+
+	// simulation splits the gas used.
+	block, err := NewBlock(bc.ChainID(), coinbase, bc.tailBlock)
+	assert.Nil(t, err)
+	result, err := tx.simulateExecutionInTxWorldState(block, gasLimit, 0)
+	assert.Nil(t, err)
+	assert.Nil(t, result.Err)
+	trace := result.GasTrace
+	assert.Equal(t, 0, trace.BaseGas.Cmp(baseGas))
+	assert.Equal(t, 0, trace.PayloadBaseGas.Cmp(payload.BaseGasCount()))
+	assert.Equal(t, 0, trace.ExecutionGas.Cmp(util.NewUint128FromUint(100)))
+	gasUsed, _ := baseGas.Add(trace.PayloadBaseGas)
+	gasUsed, _ = gasUsed.Add(trace.ExecutionGas)
+	assert.Equal(t, 0, result.GasUsed.Cmp(gasUsed))
+	refund, _ := gasLimit.Sub(gasUsed)
+	assert.Equal(t, 0, trace.Refund.Cmp(refund))
+	assert.False(t, trace.OutOfGasLimit)
+
+	result, err = tx.simulateExecutionInTxWorldState(block, baseGas, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, ErrOutOfGasLimit, result.Err)
+	assert.True(t, result.GasTrace.OutOfGasLimit)
+	block.RollBack()
+
+	// receipts record the trace from the fork, including the out of gas limit branch.
+	execute := func(forkHeight uint64) []*TransactionReceipt {
+		GasTraceForkHeight = forkHeight
+		block, err := NewBlock(bc.ChainID(), coinbase, bc.tailBlock)
+		assert.Nil(t, err)
+		block.dependency = dag.NewDag()
+		for nonce, limit := range []*util.Uint128{gasLimit, baseGas} {
+			tx := newDeployTx(uint64(nonce+1), limit)
+			assert.Nil(t, block.dependency.AddNode(tx.Hash().String()))
+			if len(block.transactions) > 0 {
+				assert.Nil(t, block.dependency.AddEdge(block.transactions[0].Hash().String(), tx.Hash().String()))
+			}
+			block.transactions = append(block.transactions, tx)
+		}
+		assert.Nil(t, block.execute())
+
+		receipts := []*TransactionReceipt{}
+		for _, tx := range block.transactions {
+			receipt, err := block.GetTransactionReceipt(tx.Hash())
+			assert.Nil(t, err)
+			receipts = append(receipts, receipt)
+		}
+		block.RollBack()
+		return receipts
+	}
+
+	for _, receipt := range execute(math.MaxUint64) {
+		assert.Nil(t, receipt.GasTrace())
+	}
+
+	receipts := execute(bc.tailBlock.Height() + 1)
+	trace = receipts[0].GasTrace()
+	assert.Equal(t, 0, trace.BaseGas.Cmp(baseGas))
+	assert.Equal(t, 0, trace.PayloadBaseGas.Cmp(payload.BaseGasCount()))
+	assert.Equal(t, 0, trace.ExecutionGas.Cmp(util.NewUint128FromUint(100)))
+	assert.Equal(t, 0, trace.Refund.Cmp(refund))
+	assert.False(t, trace.OutOfGasLimit)
+
+	assert.Equal(t, uint32(TxExecutionFailed), receipts[1].Status())
+	assert.Equal(t, 0, receipts[1].GasUsed().Cmp(baseGas))
+	trace = receipts[1].GasTrace()
+	assert.Equal(t, 0, trace.BaseGas.Cmp(baseGas))
+	assert.Equal(t, 0, trace.PayloadBaseGas.Cmp(payload.BaseGasCount()))
+	assert.Equal(t, 0, trace.ExecutionGas.Cmp(util.NewUint128()))
+	assert.Equal(t, 0, trace.Refund.Cmp(util.NewUint128()))
+	assert.True(t, trace.OutOfGasLimit)
+}
+
+func TestTransaction_FeeCostSize(t *testing.T) {
+	from := mockAddress()
+	key, _ := keystore.DefaultKS.GetUnlocked(from.String())
+	signature, _ := crypto.NewSignature(keystore.SECP256K1)
+	signature.InitSign(key.(keystore.PrivateKey))
+
+	tx := mockNormalTransaction(100, 1)
+	tx.from = from
+	tx.value = util.NewUint128FromUint(100)
+
+	fee, err := tx.Fee()
+	assert.Nil(t, err)
+	expectedFee, _ := tx.gasPrice.Mul(tx.gasLimit)
+	assert.Equal(t, expectedFee.String(), fee.String())
+	cost, err := tx.Cost()
+	assert.Nil(t, err)
+	expectedCost, _ := expectedFee.Add(tx.value)
+	assert.Equal(t, expectedCost.String(), cost.String())
+
+	// size is cached, and refreshed after signing.
+	unsigned := tx.Size()
+	data, err := tx.ToBytes()
+	assert.Nil(t, err)
+	assert.Equal(t, len(data), unsigned)
+	assert.Nil(t, tx.Sign(signature))
+	data, err = tx.ToBytes()
+	assert.Nil(t, err)
+	assert.Equal(t, len(data), tx.Size())
+	assert.True(t, tx.Size() > unsigned)
+
+	restored := new(Transaction)
+	assert.Nil(t, restored.FromBytes(data))
+	assert.Equal(t, tx.Hash(), restored.Hash())
+	assert.Equal(t, tx.Size(), restored.Size())
+	assert.Nil(t, restored.VerifyIntegrity(100))
+	assert.NotNil(t, restored.FromBytes([]byte("invalid")))
+
+	// base gas is charged on the tx size with the flag.
+	defer func(flag bool) { GasCountOnTxSize = flag }(GasCountOnTxSize)
+	baseGas, err := tx.GasCountOfTxBase()
+	assert.Nil(t, err)
+	assert.Equal(t, MinGasCountPerTransaction.String(), baseGas.String())
+	GasCountOnTxSize = true
+	baseGas, err = tx.GasCountOfTxBase()
+	assert.Nil(t, err)
+	sizeGas, _ := GasCountPerByte.Mul(util.NewUint128FromUint(uint64(tx.Size())))
+	expectedGas, _ := MinGasCountPerTransaction.Add(sizeGas)
+	assert.Equal(t, expectedGas.String(), baseGas.String())
+
+	// pool orders by gasPrice, then by fee.
+	higherLimit := mockNormalTransaction(100, 1)
+	higherLimit.gasLimit, _ = tx.gasLimit.Add(util.NewUint128FromUint(1))
+	assert.Equal(t, 1, gasCmp(tx, higherLimit))
+	assert.Equal(t, -1, gasCmp(higherLimit, tx))
+	higherPrice := mockNormalTransaction(100, 1)
+	higherPrice.gasPrice, _ = tx.gasPrice.Add(util.NewUint128FromUint(1))
+	assert.Equal(t, 1, gasCmp(higherLimit, higherPrice))
+}
+
+func TestTransaction_ExternalSignature(t *testing.T) {
+	// the key lives in an external signer, only the address is known.
+	priv := secp256k1.GeneratePrivateKey()
+	seckey, err := priv.Encoded()
+	assert.Nil(t, err)
+	pub, err := priv.PublicKey().Encoded()
+	assert.Nil(t, err)
+	from, err := NewAddressFromPublicKey(pub)
+	assert.Nil(t, err)
+
+	tx := mockNormalTransaction(100, 1)
+	tx.from = from
+	tx.expiredAt = tx.timestamp + 60
+
+	preimage, err := tx.HashPreimage()
+	assert.Nil(t, err)
+	txHash := hash.Sha3256(preimage)
+	wantedHash, err := tx.calHash()
+	assert.Nil(t, err)
+	assert.Equal(t, wantedHash, byteutils.Hash(txHash))
+
+	sign, err := secp256k1.Sign(txHash, seckey)
+	assert.Nil(t, err)
+
+	// hash must match the content, and signature must recover to from.
+	assert.Equal(t, ErrInvalidTransactionHash, tx.SetSignature(keystore.SECP256K1, hash.Sha3256([]byte("other")), sign))
+	otherSign, err := secp256k1.Sign(txHash, mockSeckey(t, mockAddress()))
+	assert.Nil(t, err)
+	assert.Equal(t, ErrInvalidTransactionSigner, tx.SetSignature(keystore.SECP256K1, txHash, otherSign))
+
+	assert.Nil(t, tx.SetSignature(keystore.SECP256K1, txHash, sign))
+	assert.Nil(t, tx.VerifyIntegrity(100))
+
+	data, err := tx.ToBytes()
+	assert.Nil(t, err)
+	restored := new(Transaction)
+	assert.Nil(t, restored.FromBytes(data))
+	assert.Nil(t, restored.VerifyIntegrity(100))
+}
+
+func mockSeckey(t *testing.T, addr *Address) []byte {
+	key, err := keystore.DefaultKS.GetUnlocked(addr.String())
+	assert.Nil(t, err)
+	seckey, err := key.(keystore.PrivateKey).Encoded()
+	assert.Nil(t, err)
+	return seckey
+}
+
+func TestTransaction_SimulateFromUnknownAddress(t *testing.T) {
+	neb := testNeb(t)
+	bc := neb.chain
+
+	block, err := bc.NewBlock(bc.tailBlock.header.coinbase)
+	assert.Nil(t, err)
+	assert.Nil(t, block.WorldState().Flush())
+	root := block.WorldState().AccountsRoot()
+
+	from, payer := mockAddress(), mockAddress()
+	tx := mockNormalTransaction(bc.ChainID(), 1)
+	tx.from = from
+	result, err := tx.simulateExecution(block)
+	assert.Nil(t, err)
+	assert.Equal(t, ErrInsufficientBalance, result.Err)
+
+	tx.payer = payer
+	result, err = tx.simulateExecution(block)
+	assert.Nil(t, err)
+	assert.Equal(t, ErrInsufficientBalance, result.Err)
+
+	// neither from nor payer is created.
+	assert.Nil(t, block.WorldState().Flush())
+	assert.Equal(t, root, block.WorldState().AccountsRoot())
+	_, err = block.WorldState().GetUserAccount(from.address)
+	assert.Equal(t, state.ErrAccountNotFound, err)
+	_, err = block.WorldState().GetUserAccount(payer.address)
+	assert.Equal(t, state.ErrAccountNotFound, err)
+}
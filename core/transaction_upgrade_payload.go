@@ -0,0 +1,158 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/alexlisong/go-nebulas/core/state"
+	"github.com/alexlisong/go-nebulas/crypto/hash"
+	"github.com/alexlisong/go-nebulas/storage"
+	"github.com/alexlisong/go-nebulas/util"
+	"github.com/alexlisong/go-nebulas/util/byteutils"
+)
+
+var (
+	// ContractUpgradeForkHeight upgrade payload & contract code versions are activated from this height, disabled by default.
+	ContractUpgradeForkHeight uint64 = math.MaxUint64
+
+	// ContractVersionKey the key of code version in contract variables, absent for the deployed code.
+	ContractVersionKey = []byte("__version__")
+)
+
+// UpgradePayload carry the new code of contract tx.to, only its deployer can send it.
+type UpgradePayload struct {
+	SourceType string
+	Source     string
+}
+
+// ContractUpgradeEvent is the data of contract upgrade events.
+type ContractUpgradeEvent struct {
+	ContractAddress string `json:"contract_address"`
+	Version         uint64 `json:"version"`
+	SourceHash      string `json:"source_hash"`
+}
+
+// LoadUpgradePayload from bytes
+func LoadUpgradePayload(bytes []byte) (*UpgradePayload, error) {
+	payload := &UpgradePayload{}
+	if err := json.Unmarshal(bytes, payload); err != nil {
+		return nil, ErrInvalidArgument
+	}
+	return NewUpgradePayload(payload.Source, payload.SourceType)
+}
+
+// NewUpgradePayload with source
+func NewUpgradePayload(source, sourceType string) (*UpgradePayload, error) {
+	if len(source) == 0 {
+		return nil, ErrInvalidDeploySource
+	}
+
+	if sourceType != SourceTypeTypeScript && sourceType != SourceTypeJavaScript {
+		return nil, ErrInvalidDeploySourceType
+	}
+
+	return &UpgradePayload{
+		Source:     source,
+		SourceType: sourceType,
+	}, nil
+}
+
+// ToBytes serialize payload
+func (payload *UpgradePayload) ToBytes() ([]byte, error) {
+	return json.Marshal(payload)
+}
+
+// SourceHash returns the sha3 hash of contract source
+func (payload *UpgradePayload) SourceHash() byteutils.Hash {
+	return hash.Sha3256([]byte(payload.Source))
+}
+
+// BaseGasCount returns base gas count
+func (payload *UpgradePayload) BaseGasCount() *util.Uint128 {
+	base, _ := util.NewUint128FromInt(60)
+	return base
+}
+
+// Execute the upgrade payload in tx, the code of contract tx.to is replaced and its storage is kept.
+func (payload *UpgradePayload) Execute(limitedGas *util.Uint128, tx *Transaction, block *Block, ws WorldState) (*util.Uint128, string, error) {
+	if block == nil || tx == nil {
+		return util.NewUint128(), "", ErrNilArgument
+	}
+	if block.Height() < ContractUpgradeForkHeight {
+		return util.NewUint128(), "", ErrInvalidTxPayloadType
+	}
+
+	contract, err := CheckContract(tx.to, ws)
+	if err != nil {
+		return util.NewUint128(), "", err
+	}
+	birthTx, err := GetTransaction(contract.BirthPlace(), ws)
+	if err != nil {
+		return util.NewUint128(), "", err
+	}
+	if !birthTx.from.Equals(tx.from) {
+		return util.NewUint128(), "", ErrNotContractDeployer
+	}
+
+	version, err := contractVersion(contract)
+	if err != nil {
+		return util.NewUint128(), "", err
+	}
+	version++
+	if err := contract.Put(contractCodeKey(version), tx.hash); err != nil {
+		return util.NewUint128(), "", err
+	}
+	if err := contract.Put(ContractVersionKey, byteutils.FromUint64(version)); err != nil {
+		return util.NewUint128(), "", err
+	}
+
+	data, err := json.Marshal(&ContractUpgradeEvent{
+		ContractAddress: tx.to.String(),
+		Version:         version,
+		SourceHash:      payload.SourceHash().String(),
+	})
+	if err != nil {
+		return util.NewUint128(), "", err
+	}
+	ws.RecordEvent(tx.hash, &state.Event{
+		Topic: TopicContractUpgrade,
+		Data:  string(data),
+	})
+	return util.NewUint128(), "", nil
+}
+
+// contractCodeKey the key of the upgrade tx hash of code version in contract variables.
+func contractCodeKey(version uint64) []byte {
+	return []byte(fmt.Sprintf("__code_%d__", version))
+}
+
+// contractVersion return the code version of contract, 0 for the deployed code.
+func contractVersion(contract state.Account) (uint64, error) {
+	version, err := contract.Get(ContractVersionKey)
+	if err != nil {
+		if err == storage.ErrKeyNotFound {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return byteutils.Uint64(version), nil
+}
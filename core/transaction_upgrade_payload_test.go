@@ -0,0 +1,171 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/alexlisong/go-nebulas/core/state"
+	"github.com/alexlisong/go-nebulas/util"
+	"github.com/stretchr/testify/assert"
+)
+
+// codeNvm returns the source of the called contract.
+type codeNvm struct{}
+
+type codeEngine struct {
+	mockEngine
+}
+
+func (nvm *codeNvm) CreateEngine(block *Block, tx *Transaction, contract state.Account, ws WorldState) (SmartContractEngine, error) {
+	return &codeEngine{}, nil
+}
+
+func (engine *codeEngine) Call(source, sourceType, function, args string) (string, error) {
+	return source, nil
+}
+
+func TestUpgradePayload(t *testing.T) {
+	_, err := NewUpgradePayload("", SourceTypeJavaScript)
+	assert.Equal(t, ErrInvalidDeploySource, err)
+	_, err = NewUpgradePayload("module.exports = {};", "py")
+	assert.Equal(t, ErrInvalidDeploySourceType, err)
+
+	payload, err := NewUpgradePayload("module.exports = {};", SourceTypeJavaScript)
+	assert.Nil(t, err)
+	bytes, err := payload.ToBytes()
+	assert.Nil(t, err)
+	loaded, err := LoadUpgradePayload(bytes)
+	assert.Nil(t, err)
+	assert.Equal(t, payload, loaded)
+	_, err = LoadUpgradePayload([]byte("{"))
+	assert.Equal(t, ErrInvalidArgument, err)
+}
+
+func TestUpgradePayload_Execute(t *testing.T) {
+	defer func(height uint64) { ContractUpgradeForkHeight = height }(ContractUpgradeForkHeight)
+
+	neb := testNeb(t)
+	bc := neb.chain
+	bc.nvm = &codeNvm{}
+	bc.tailBlock.nvm = bc.nvm
+
+	deployer, stranger := mockAddress(), mockAddress()
+	balance, _ := util.NewUint128FromString("1000000000000000000")
+	bc.tailBlock.Begin()
+	for _, addr := range []*Address{deployer, stranger} {
+		acc, err := bc.tailBlock.worldState.GetOrCreateUserAccount(addr.address)
+		assert.Nil(t, err)
+		assert.Nil(t, acc.AddBalance(balance))
+	}
+	bc.tailBlock.Commit()
+	bc.tailBlock.header.stateRoot = bc.tailBlock.worldState.AccountsRoot()
+	assert.Nil(t, bc.StoreBlockToStorage(bc.tailBlock))
+
+	mint := func(parent *Block, txs ...*Transaction) *Block {
+		block, err := bc.NewBlockFromParent(mockAddress(), parent)
+		assert.Nil(t, err)
+		block.header.timestamp = parent.Timestamp() + BlockIntervalInSecond
+		for _, tx := range txs {
+			txWorldState, err := block.WorldState().Prepare(tx.Hash().String())
+			assert.Nil(t, err)
+			_, err = block.ExecuteTransaction(tx, txWorldState)
+			assert.Nil(t, err)
+			_, err = txWorldState.CheckAndUpdate()
+			assert.Nil(t, err)
+			txWorldState.Close()
+			assert.Nil(t, block.dependency.AddNode(tx.Hash().String()))
+			block.transactions = append(block.transactions, tx)
+		}
+		assert.Nil(t, block.Seal())
+		signBlock(block)
+		assert.Nil(t, bc.BlockPool().Push(block))
+		return bc.GetBlock(block.Hash())
+	}
+
+	deployTx := mockDeployTransaction(bc.ChainID(), 1)
+	deployTx.from, deployTx.to = deployer, deployer
+	assert.Nil(t, deployTx.Sign(mockSignature(t, deployer)))
+	contract, err := deployTx.GenerateContractAddress()
+	assert.Nil(t, err)
+	deploy, err := LoadDeployPayload(deployTx.data.Payload)
+	assert.Nil(t, err)
+
+	upgradeSource := "module.exports = {};"
+	upgrade := func(from *Address, nonce uint64) *Transaction {
+		payload, err := NewUpgradePayload(upgradeSource, SourceTypeJavaScript)
+		assert.Nil(t, err)
+		data, err := payload.ToBytes()
+		assert.Nil(t, err)
+		tx := mockTransaction(bc.ChainID(), nonce, TxPayloadUpgradeType, data)
+		tx.from, tx.to = from, contract
+		assert.Nil(t, tx.Sign(mockSignature(t, from)))
+		return tx
+	}
+	resultOf := func(block *Block, tx *Transaction) *TransactionEvent {
+		event, err := block.FetchExecutionResultEvent(tx.Hash())
+		assert.Nil(t, err)
+		txEvent := &TransactionEvent{}
+		assert.Nil(t, json.Unmarshal([]byte(event.Data), txEvent))
+		return txEvent
+	}
+
+	deployed := mint(bc.TailBlock(), deployTx)
+	ContractUpgradeForkHeight = deployed.Height() + 2
+
+	// the upgrade is rejected before the fork.
+	earlyTx := upgrade(deployer, 2)
+	early := mint(deployed, earlyTx)
+	assert.Equal(t, ErrInvalidTxPayloadType.Error(), resultOf(early, earlyTx).Error)
+
+	strangerTx, upgradeTx := upgrade(stranger, 1), upgrade(deployer, 3)
+	upgraded := mint(early, strangerTx, upgradeTx)
+	assert.Equal(t, ErrNotContractDeployer.Error(), resultOf(upgraded, strangerTx).Error)
+	assert.Equal(t, int8(TxExecutionSuccess), resultOf(upgraded, upgradeTx).Status)
+
+	events, err := upgraded.FetchEvents(upgradeTx.Hash())
+	assert.Nil(t, err)
+	assert.Equal(t, TopicContractUpgrade, events[0].Topic)
+
+	// the calls run the upgraded code.
+	result, _, err := CallContract(early, nil, contract, "source", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, deploy.Source, result)
+	result, _, err = CallContract(upgraded, nil, contract, "source", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, upgradeSource, result)
+
+	source, err := upgraded.GetContractSource(contract)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), source.Version)
+	assert.Equal(t, upgradeSource, source.Source)
+	assert.Equal(t, upgradeTx.Hash().String(), source.UpgradeTx)
+	assert.Equal(t, deployTx.Hash().String(), source.DeployTx)
+
+	// the replaced code is still readable.
+	ws, err := upgraded.WorldState().Clone()
+	assert.Nil(t, err)
+	source, err = GetContractSourceAtVersion(contract, 0, ws)
+	assert.Nil(t, err)
+	assert.Equal(t, deploy.Source, source.Source)
+	assert.Empty(t, source.UpgradeTx)
+	_, err = GetContractSourceAtVersion(contract, 2, ws)
+	assert.Equal(t, ErrContractVersionNotFound, err)
+}
@@ -0,0 +1,292 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import (
+	"encoding/json"
+	"math"
+
+	"github.com/alexlisong/go-nebulas/common/trie"
+	"github.com/alexlisong/go-nebulas/storage"
+	"github.com/alexlisong/go-nebulas/util"
+)
+
+// Vote Actions
+const (
+	// VoteActionRegister the sender registers as a candidate with the deposit in tx.value.
+	VoteActionRegister = "register"
+
+	// VoteActionUnregister the candidate quits and takes back the deposit.
+	VoteActionUnregister = "unregister"
+
+	// VoteActionVote the sender votes for the target candidate.
+	VoteActionVote = "vote"
+
+	// VoteActionUnvote the sender takes back the vote from the target candidate.
+	VoteActionUnvote = "unvote"
+)
+
+// Keys prefix in delegate trie
+var (
+	candidatePrefix = []byte("c")
+	delegatePrefix  = []byte("d")
+)
+
+var (
+	// VoteForkHeight vote payload is activated from this height, disabled by default.
+	VoteForkHeight uint64 = math.MaxUint64
+
+	// CandidateDeposit the deposit to register as a candidate, 100 NAS.
+	CandidateDeposit, _ = util.NewUint128FromString("100000000000000000000")
+)
+
+// VotePayload carry vote & candidate registration information.
+// All actions are sent to the sender itself, the target is the candidate of vote & unvote.
+type VotePayload struct {
+	Action string
+	Target string
+}
+
+// candidateRecord stored in delegate trie, key: candidatePrefix + candidate.
+type candidateRecord struct {
+	Deposit string
+	Votes   uint64
+}
+
+// LoadVotePayload from bytes
+func LoadVotePayload(bytes []byte) (*VotePayload, error) {
+	payload := &VotePayload{}
+	if err := json.Unmarshal(bytes, payload); err != nil {
+		return nil, ErrInvalidArgument
+	}
+	return NewVotePayload(payload.Action, payload.Target)
+}
+
+// NewVotePayload with action & target
+func NewVotePayload(action, target string) (*VotePayload, error) {
+	switch action {
+	case VoteActionRegister, VoteActionUnregister:
+		if len(target) > 0 {
+			return nil, ErrInvalidArgument
+		}
+	case VoteActionVote, VoteActionUnvote:
+		if _, err := AddressParse(target); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, ErrInvalidDelegatePayloadAction
+	}
+
+	return &VotePayload{
+		Action: action,
+		Target: target,
+	}, nil
+}
+
+// ToBytes serialize payload
+func (payload *VotePayload) ToBytes() ([]byte, error) {
+	return json.Marshal(payload)
+}
+
+// BaseGasCount returns base gas count
+func (payload *VotePayload) BaseGasCount() *util.Uint128 {
+	base, _ := util.NewUint128FromInt(20)
+	return base
+}
+
+// Execute the vote payload in tx, update the delegate trie
+func (payload *VotePayload) Execute(limitedGas *util.Uint128, tx *Transaction, block *Block, ws WorldState) (*util.Uint128, string, error) {
+	if block == nil || tx == nil {
+		return util.NewUint128(), "", ErrNilArgument
+	}
+	if block.Height() < VoteForkHeight {
+		return util.NewUint128(), "", ErrInvalidTxPayloadType
+	}
+	if !tx.From().Equals(tx.To()) {
+		return util.NewUint128(), "", ErrVoteTransactionAddressNotEqual
+	}
+
+	var err error
+	switch payload.Action {
+	case VoteActionRegister:
+		err = payload.register(tx, ws)
+	case VoteActionUnregister:
+		err = payload.unregister(tx, ws)
+	case VoteActionVote:
+		err = payload.vote(tx, ws)
+	case VoteActionUnvote:
+		err = payload.unvote(tx, ws)
+	default:
+		err = ErrInvalidDelegatePayloadAction
+	}
+	return util.NewUint128(), "", err
+}
+
+// register locks tx.value of the candidate as deposit.
+func (payload *VotePayload) register(tx *Transaction, ws WorldState) error {
+	if _, err := getCandidateRecord(tx.from, ws); err != ErrCandidateNotRegistered {
+		if err == nil {
+			return ErrCandidateAlreadyRegistered
+		}
+		return err
+	}
+	if tx.value.Cmp(CandidateDeposit) != 0 {
+		return ErrInvalidCandidateDeposit
+	}
+
+	candidate, err := ws.GetOrCreateUserAccount(tx.from.address)
+	if err != nil {
+		return err
+	}
+	if err := candidate.SubBalance(tx.value); err != nil {
+		return err
+	}
+	return putCandidateRecord(tx.from, &candidateRecord{Deposit: tx.value.String()}, ws)
+}
+
+// unregister refunds the deposit, the votes for the candidate are dropped.
+func (payload *VotePayload) unregister(tx *Transaction, ws WorldState) error {
+	record, err := getCandidateRecord(tx.from, ws)
+	if err != nil {
+		return err
+	}
+	deposit, err := util.NewUint128FromString(record.Deposit)
+	if err != nil {
+		return err
+	}
+
+	candidate, err := ws.GetOrCreateUserAccount(tx.from.address)
+	if err != nil {
+		return err
+	}
+	if err := candidate.AddBalance(deposit); err != nil {
+		return err
+	}
+	return ws.DelDelegate(delegateKey(candidatePrefix, tx.from))
+}
+
+// vote for target, the previous vote of the sender is replaced.
+func (payload *VotePayload) vote(tx *Transaction, ws WorldState) error {
+	target, err := AddressParse(payload.Target)
+	if err != nil {
+		return err
+	}
+	record, err := getCandidateRecord(target, ws)
+	if err != nil {
+		if err == ErrCandidateNotRegistered {
+			return ErrInvalidDelegateToNonCandidate
+		}
+		return err
+	}
+
+	delegatee, err := getDelegatee(tx.from, ws)
+	if err != nil {
+		return err
+	}
+	if delegatee != nil {
+		if delegatee.Equals(target) {
+			return ErrDuplicatedVote
+		}
+		if err := subVote(delegatee, ws); err != nil {
+			return err
+		}
+	}
+
+	record.Votes++
+	if err := putCandidateRecord(target, record, ws); err != nil {
+		return err
+	}
+	return ws.PutDelegate(delegateKey(delegatePrefix, tx.from), target.address)
+}
+
+// unvote takes back the vote from target, which must be the sender's delegatee.
+func (payload *VotePayload) unvote(tx *Transaction, ws WorldState) error {
+	target, err := AddressParse(payload.Target)
+	if err != nil {
+		return err
+	}
+	delegatee, err := getDelegatee(tx.from, ws)
+	if err != nil {
+		return err
+	}
+	if delegatee == nil || !delegatee.Equals(target) {
+		return ErrInvalidUnDelegateFromNonDelegatee
+	}
+
+	if err := subVote(target, ws); err != nil {
+		return err
+	}
+	return ws.DelDelegate(delegateKey(delegatePrefix, tx.from))
+}
+
+// subVote decreases the votes of candidate, if it's still registered.
+func subVote(candidate *Address, ws WorldState) error {
+	record, err := getCandidateRecord(candidate, ws)
+	if err != nil {
+		if err == ErrCandidateNotRegistered {
+			return nil
+		}
+		return err
+	}
+	if record.Votes > 0 {
+		record.Votes--
+	}
+	return putCandidateRecord(candidate, record, ws)
+}
+
+func delegateKey(prefix []byte, addr *Address) []byte {
+	key := make([]byte, 0, len(prefix)+len(addr.address))
+	key = append(key, prefix...)
+	return append(key, addr.address...)
+}
+
+func getCandidateRecord(candidate *Address, ws WorldState) (*candidateRecord, error) {
+	bytes, err := ws.GetDelegate(delegateKey(candidatePrefix, candidate))
+	if err != nil {
+		if err == storage.ErrKeyNotFound || err == trie.ErrNotFound {
+			return nil, ErrCandidateNotRegistered
+		}
+		return nil, err
+	}
+	record := &candidateRecord{}
+	if err := json.Unmarshal(bytes, record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+func putCandidateRecord(candidate *Address, record *candidateRecord, ws WorldState) error {
+	bytes, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return ws.PutDelegate(delegateKey(candidatePrefix, candidate), bytes)
+}
+
+// getDelegatee returns the candidate voted by voter, nil if none.
+func getDelegatee(voter *Address, ws WorldState) (*Address, error) {
+	bytes, err := ws.GetDelegate(delegateKey(delegatePrefix, voter))
+	if err != nil {
+		if err == storage.ErrKeyNotFound || err == trie.ErrNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return AddressParseFromBytes(bytes)
+}
@@ -40,9 +40,14 @@ import (
 
 // Payload Types
 const (
-	TxPayloadBinaryType = "binary"
-	TxPayloadDeployType = "deploy"
-	TxPayloadCallType   = "call"
+	TxPayloadBinaryType   = "binary"
+	TxPayloadDeployType   = "deploy"
+	TxPayloadCallType     = "call"
+	TxPayloadRecoveryType = "recovery"
+	TxPayloadVoteType     = "vote"
+	TxPayloadBatchType    = "batch"
+	TxPayloadFreezeType   = "freeze"
+	TxPayloadUpgradeType  = "upgrade"
 )
 
 // Const.
@@ -76,13 +81,15 @@ var (
 	ErrInvalidBlockCannotFindParentInLocalAndTryDownload = errors.New("invalid block received, download its parent from others")
 	ErrInvalidBlockCannotFindParentInLocalAndTrySync     = errors.New("invalid block received, sync its parent from others")
 
-	ErrInvalidConfigChainID          = errors.New("invalid chainID, genesis chainID not equal to chainID in config")
-	ErrCannotLoadGenesisConf         = errors.New("cannot load genesis conf")
-	ErrGenesisNotEqualChainIDInDB    = errors.New("Failed to check. genesis chainID not equal in db")
-	ErrGenesisNotEqualDynastyInDB    = errors.New("Failed to check. genesis dynasty not equal in db")
-	ErrGenesisNotEqualTokenInDB      = errors.New("Failed to check. genesis TokenDistribution not equal in db")
-	ErrGenesisNotEqualDynastyLenInDB = errors.New("Failed to check. genesis dynasty length not equal in db")
-	ErrGenesisNotEqualTokenLenInDB   = errors.New("Failed to check. genesis TokenDistribution length not equal in db")
+	ErrInvalidConfigChainID           = errors.New("invalid chainID, genesis chainID not equal to chainID in config")
+	ErrCannotLoadGenesisConf          = errors.New("cannot load genesis conf")
+	ErrGenesisNotEqualChainIDInDB     = errors.New("Failed to check. genesis chainID not equal in db")
+	ErrGenesisNotEqualDynastyInDB     = errors.New("Failed to check. genesis dynasty not equal in db")
+	ErrGenesisNotEqualTokenInDB       = errors.New("Failed to check. genesis TokenDistribution not equal in db")
+	ErrGenesisNotEqualDynastyLenInDB  = errors.New("Failed to check. genesis dynasty length not equal in db")
+	ErrGenesisNotEqualTokenLenInDB    = errors.New("Failed to check. genesis TokenDistribution length not equal in db")
+	ErrGenesisNotEqualChainConfigInDB = errors.New("Failed to check. genesis ChainConfig not equal in db")
+	ErrInvalidChainConfig             = errors.New("invalid chain config in genesis")
 
 	ErrLinkToWrongParentBlock = errors.New("link the block to a block who is not its parent")
 	ErrMissingParentBlock     = errors.New("cannot find the block's parent block in storage")
@@ -90,14 +97,51 @@ var (
 	ErrDoubleSealBlock        = errors.New("cannot seal a block twice")
 	ErrDuplicatedBlock        = errors.New("duplicated block")
 	ErrDoubleBlockMinted      = errors.New("double block minted")
-
-	ErrInvalidChainID           = errors.New("invalid transaction chainID")
-	ErrInvalidTransactionSigner = errors.New("invalid transaction signer")
-	ErrInvalidTransactionHash   = errors.New("invalid transaction hash")
-	ErrInvalidSignature         = errors.New("invalid transaction signature")
-	ErrInvalidTxPayloadType     = errors.New("invalid transaction data payload type")
-	ErrInvalidGasPrice          = errors.New("invalid gas price, should be in (0, 10^12]")
-	ErrInvalidGasLimit          = errors.New("invalid gas limit, should be in (0, 5*10^10]")
+	ErrBlockReceivedTimeout   = errors.New("block is received too late")
+	ErrInvalidTxIndex         = errors.New("the block indexed by the transaction is missing or doesn't include it")
+	ErrCanonicalChainChanged  = errors.New("canonical chain is changed during iteration")
+	ErrBlockEvictedOnArrival  = errors.New("block pool is full of higher blocks, the block is evicted on arrival")
+	ErrBlockPoolFull          = errors.New("block pool is full of blocks at the same height")
+	ErrStatePruned            = errors.New("the account state of block is pruned")
+	ErrBlockNotFound          = errors.New("cannot find the block in storage")
+	ErrInvalidChainFile       = errors.New("invalid chain file")
+	ErrMismatchedChainFile    = errors.New("the chain id of chain file doesn't match")
+	ErrSnapshotBehindTail     = errors.New("the snapshot block is not ahead of tail")
+	ErrInvalidHeaderTimestamp = errors.New("invalid header timestamp, should be a later slot than its parent")
+	ErrInvalidHeaderSigner    = errors.New("the header is not signed by its proposer")
+
+	ErrBlockTimestampBehindParent = errors.New("block timestamp is not after its parent's")
+	ErrBlockTooFarInFuture        = errors.New("block timestamp is too far ahead of the node's clock")
+	ErrInvalidBlockSlot           = errors.New("block timestamp is not aligned to the block slots")
+
+	ErrBlockGasLimitExceeded = errors.New("gas used by txs exceeds the block gas limit")
+	ErrInvalidBlockGasLimit  = errors.New("block gas limit doesn't match the chain config")
+	ErrInvalidBlockGasUsed   = errors.New("invalid block gas used")
+
+	ErrInvalidBlockBloom  = errors.New("invalid block bloom")
+	ErrInvalidFilterRange = errors.New("invalid block height range to filter")
+
+	ErrSubscriptionOverflow = errors.New("the subscriber falls behind and its event buffer overflows")
+
+	ErrInvalidChainID                = errors.New("invalid transaction chainID")
+	ErrInvalidTransactionSigner      = errors.New("invalid transaction signer")
+	ErrInvalidTransactionHash        = errors.New("invalid transaction hash")
+	ErrInvalidSignature              = errors.New("invalid transaction signature")
+	ErrInvalidTransactionAlg         = errors.New("invalid transaction signature algorithm")
+	ErrInvalidTxPayloadType          = errors.New("invalid transaction data payload type")
+	ErrInvalidTxDataType             = errors.New("invalid transaction data type, should be 1-32 printable ascii chars")
+	ErrUnregisteredTxDataType        = errors.New("unregistered transaction data type")
+	ErrInvalidGasPrice               = errors.New("invalid gas price, should be in (0, 10^12]")
+	ErrInvalidGasLimit               = errors.New("invalid gas limit, should be in (0, 5*10^10]")
+	ErrInvalidTxJSONHex              = errors.New("invalid hex string of hash or sign in transaction json")
+	ErrInvalidTxJSONBase64           = errors.New("invalid base64 string of payload in transaction json")
+	ErrInvalidTransactionPayerSigner = errors.New("invalid transaction payer signer")
+	ErrTransactionWithoutPayer       = errors.New("transaction has no payer")
+	ErrTxPayerNotActivated           = errors.New("transaction payer is not activated")
+	ErrTxAlgNotActivated             = errors.New("transaction signature algorithm is not activated")
+	ErrTxExpiredAtNotActivated       = errors.New("transaction expiredAt is not activated")
+	ErrTxTimestampAheadOfNode        = errors.New("transaction timestamp is too far ahead of the node's clock")
+	ErrTxTimestampAheadOfBlock       = errors.New("transaction timestamp is too far ahead of the block's timestamp")
 
 	ErrNoTimeToPackTransactions       = errors.New("no time left to pack transactions in a block")
 	ErrTxDataPayLoadOutOfMaxLength    = errors.New("data's payload is out of max data length")
@@ -106,7 +150,7 @@ var (
 	ErrInvalidArgument                = errors.New("invalid argument(s)")
 
 	ErrInsufficientBalance                = errors.New("insufficient balance")
-	ErrBelowGasPrice                      = errors.New("below the gas price")
+	ErrGasPriceBelowMinimum               = errors.New("gas price is below the minimum accepted by the pool")
 	ErrGasCntOverflow                     = errors.New("the count of gas used is overflow")
 	ErrGasFeeOverflow                     = errors.New("the fee of gas used is overflow")
 	ErrInvalidTransfer                    = errors.New("transfer error: overflow or insufficient balance")
@@ -114,14 +158,23 @@ var (
 	ErrOutOfGasLimit                      = errors.New("out of gas limit")
 	ErrTxExecutionFailed                  = errors.New("transaction execution failed")
 	ErrZeroGasPrice                       = errors.New("gas price should be greater than zero")
+	ErrTransactionExpired                 = errors.New("transaction is expired")
+	ErrInvalidExpiredAt                   = errors.New("invalid transaction expiration timestamp")
 	ErrZeroGasLimit                       = errors.New("gas limit should be greater than zero")
 	ErrContractDeployFailed               = errors.New("contract deploy failed")
 	ErrContractCheckFailed                = errors.New("contract check failed")
 	ErrContractTransactionAddressNotEqual = errors.New("contract transaction from-address not equal to to-address")
-
-	ErrDuplicatedTransaction = errors.New("duplicated transaction")
-	ErrSmallTransactionNonce = errors.New("cannot accept a transaction with smaller nonce")
-	ErrLargeTransactionNonce = errors.New("cannot accept a transaction with too bigger nonce")
+	ErrContractCallMutatedState           = errors.New("read-only contract call mutated the world state")
+	ErrContractBirthTxNotFound            = errors.New("the deploy transaction of contract is not found, it may be pruned")
+	ErrInvalidContractBirthTx             = errors.New("the birth place of contract is not a deploy transaction")
+	ErrSimulationFailed                   = errors.New("transaction simulation failed")
+	ErrEstimatedFeeExceedsMax             = errors.New("estimated fee exceeds the max fee")
+
+	ErrDuplicatedTransaction     = errors.New("duplicated transaction")
+	ErrSmallTransactionNonce     = errors.New("cannot accept a transaction with smaller nonce")
+	ErrLargeTransactionNonce     = errors.New("cannot accept a transaction with too bigger nonce")
+	ErrUnderpricedReplacement    = errors.New("replacement transaction gas price is not bumped enough")
+	ErrReplacePackingTransaction = errors.New("cannot replace a transaction being packed")
 
 	ErrInvalidAddress         = errors.New("address: invalid address")
 	ErrInvalidAddressFormat   = errors.New("address: invalid address format")
@@ -132,20 +185,34 @@ var (
 	ErrInvalidDelegatePayloadAction      = errors.New("invalid transaction vote payload action")
 	ErrInvalidDelegateToNonCandidate     = errors.New("cannot delegate to non-candidate")
 	ErrInvalidUnDelegateFromNonDelegatee = errors.New("cannot un-delegate from non-delegatee")
-
-	ErrCloneWorldState           = errors.New("Failed to clone world state")
-	ErrCloneAccountState         = errors.New("Failed to clone account state")
-	ErrCloneTxsState             = errors.New("Failed to clone txs state")
-	ErrCloneEventsState          = errors.New("Failed to clone events state")
-	ErrInvalidBlockStateRoot     = errors.New("invalid block state root hash")
-	ErrInvalidBlockTxsRoot       = errors.New("invalid block txs root hash")
-	ErrInvalidBlockEventsRoot    = errors.New("invalid block events root hash")
-	ErrInvalidBlockConsensusRoot = errors.New("invalid block consensus root hash")
-	ErrInvalidProtoToBlock       = errors.New("protobuf message cannot be converted into Block")
-	ErrInvalidProtoToBlockHeader = errors.New("protobuf message cannot be converted into BlockHeader")
-	ErrInvalidProtoToTransaction = errors.New("protobuf message cannot be converted into Transaction")
-	ErrInvalidTransactionData    = errors.New("invalid data in tx from Proto")
-	ErrInvalidDagBlock           = errors.New("block's dag is incorrect")
+	ErrCandidateAlreadyRegistered        = errors.New("candidate is already registered")
+	ErrCandidateNotRegistered            = errors.New("candidate is not registered")
+	ErrInvalidCandidateDeposit           = errors.New("invalid candidate deposit, tx value should equal to the deposit")
+	ErrDuplicatedVote                    = errors.New("cannot vote for the current delegatee again")
+	ErrVoteTransactionAddressNotEqual    = errors.New("vote transaction from-address not equal to to-address")
+
+	ErrInvalidBatchEntries             = errors.New("invalid entries of batch payload, should be 1-200 transfers")
+	ErrInvalidBatchRecipient           = errors.New("invalid recipient of batch payload, should be a user account")
+	ErrBatchTransactionAddressNotEqual = errors.New("batch transaction from-address not equal to to-address")
+
+	ErrCloneWorldState                  = errors.New("Failed to clone world state")
+	ErrCloneAccountState                = errors.New("Failed to clone account state")
+	ErrCloneTxsState                    = errors.New("Failed to clone txs state")
+	ErrCloneEventsState                 = errors.New("Failed to clone events state")
+	ErrInvalidBlockStateRoot            = errors.New("invalid block state root hash")
+	ErrInvalidBlockTxsRoot              = errors.New("invalid block txs root hash")
+	ErrInvalidBlockEventsRoot           = errors.New("invalid block events root hash")
+	ErrInvalidBlockReceiptsRoot         = errors.New("invalid block receipts root hash")
+	ErrReceiptsNotAvailable             = errors.New("receipts not available before fork")
+	ErrInvalidBlockDelegateRoot         = errors.New("invalid block delegate root hash")
+	ErrInvalidBlockConsensusRoot        = errors.New("invalid block consensus root hash")
+	ErrInvalidProtoToBlock              = errors.New("protobuf message cannot be converted into Block")
+	ErrInvalidProtoToBlockHeader        = errors.New("protobuf message cannot be converted into BlockHeader")
+	ErrInvalidProtoToTransaction        = errors.New("protobuf message cannot be converted into Transaction")
+	ErrInvalidProtoToTransactionReceipt = errors.New("protobuf message cannot be converted into TransactionReceipt")
+	ErrInvalidProtoToGasTrace           = errors.New("protobuf message cannot be converted into GasTrace")
+	ErrInvalidTransactionData           = errors.New("invalid data in tx from Proto")
+	ErrInvalidDagBlock                  = errors.New("block's dag is incorrect")
 
 	ErrCannotRevertLIB        = errors.New("cannot revert latest irreversible block")
 	ErrCannotLoadGenesisBlock = errors.New("cannot load genesis block from storage")
@@ -155,13 +222,37 @@ var (
 
 	ErrInvalidDeploySource     = errors.New("invalid source of deploy payload")
 	ErrInvalidDeploySourceType = errors.New("invalid source type of deploy payload")
+	ErrInvalidDeploySalt       = errors.New("invalid salt of deploy payload")
 	ErrInvalidCallFunction     = errors.New("invalid function of call payload")
 
+	ErrInvalidRecoveryAction   = errors.New("invalid action of recovery payload")
+	ErrInvalidRecoveryPeriod   = errors.New("invalid challenge period of recovery payload")
+	ErrInvalidRecoveryHeir     = errors.New("invalid heir of recovery payload, heir should be another user account")
+	ErrRecoveryNotRegistered   = errors.New("account has not registered a recovery heir")
+	ErrRecoveryHeirMismatch    = errors.New("transaction sender is not the designated heir")
+	ErrRecoveryClaimPending    = errors.New("recovery claim is already pending")
+	ErrRecoveryNoPendingClaim  = errors.New("no pending recovery claim")
+	ErrRecoveryChallengePeriod = errors.New("recovery challenge period has not expired")
+
+	ErrInvalidFreezeAction = errors.New("invalid action of freeze payload")
+	ErrNotFreezeAdmin      = errors.New("transaction sender is not a freeze admin")
+	ErrAccountFrozen       = errors.New("account is frozen")
+	ErrAccountNotFrozen    = errors.New("account is not frozen")
+
+	ErrContractDestroyDisabled   = errors.New("contract destroy is not activated")
+	ErrContractDestroyed         = errors.New("contract is destroyed")
+	ErrInvalidDestroyBeneficiary = errors.New("invalid beneficiary of contract destroy, should be another address")
+
+	ErrNotContractDeployer     = errors.New("transaction sender is not the deployer of contract")
+	ErrContractVersionNotFound = errors.New("contract code version is not found")
+
 	ErrInvalidTransactionResultEvent  = errors.New("invalid transaction result event, the last event in tx's events should be result event")
 	ErrNotFoundTransactionResultEvent = errors.New("transaction result event is not found ")
 
 	// nvm error
-	ErrExecutionFailed = errors.New("execution failed")
+	ErrExecutionFailed           = errors.New("execution failed")
+	ErrStorageLimitExceeded      = errors.New("contract storage written in one execution exceeds the limit")
+	ErrUnsupportedRuntimeVersion = errors.New("contract runtime version is not supported")
 
 	// unsupported keyword error in smart contract
 	ErrUnsupportedKeyword = errors.New("transaction data has unsupported keyword")
@@ -237,6 +328,7 @@ type AccountManager interface {
 	SignBlock(*Address, *Block) error
 	SignTransaction(*Address, *Transaction) error
 	SignTransactionWithPassphrase(*Address, *Transaction, []byte) error
+	SignTransactionAsPayerWithPassphrase(*Address, *Transaction, []byte) error
 
 	Update(*Address, []byte, []byte) error
 	Load([]byte, []byte) (*Address, error)
@@ -252,6 +344,7 @@ type NVM interface {
 // SmartContractEngine interface
 type SmartContractEngine interface {
 	SetExecutionLimits(uint64, uint64) error
+	SetRuntimeVersion(version string) error
 	DeployAndInit(source, sourceType, args string) (string, error)
 	Call(source, sourceType, function, args string) (string, error)
 	ExecutionInstructions() uint64
@@ -277,14 +370,25 @@ type Neblet interface {
 // WorldState needed by core
 type WorldState interface {
 	GetOrCreateUserAccount(addr byteutils.Hash) (state.Account, error)
+	GetUserAccount(addr byteutils.Hash) (state.Account, error)
 	GetContractAccount(addr byteutils.Hash) (state.Account, error)
+	ContractStorageIterator(addr byteutils.Hash) (state.Iterator, error)
 	CreateContractAccount(owner byteutils.Hash, birthPlace byteutils.Hash) (state.Account, error)
 
 	GetTx(txHash byteutils.Hash) ([]byte, error)
 	PutTx(txHash byteutils.Hash, txBytes []byte) error
 
+	GetTxReceipt(txHash byteutils.Hash) ([]byte, error)
+	PutTxReceipt(txHash byteutils.Hash, receiptBytes []byte) error
+
+	GetDelegate(key []byte) ([]byte, error)
+	PutDelegate(key []byte, val []byte) error
+	DelDelegate(key []byte) error
+
 	RecordEvent(txHash byteutils.Hash, event *state.Event)
 	FetchEvents(byteutils.Hash) ([]*state.Event, error)
+	RecordExecutionResult(txHash byteutils.Hash, event *state.Event)
+	FetchExecutionResult(byteutils.Hash) (*state.Event, error)
 
 	Dynasty() ([]byteutils.Hash, error)
 	DynastyRoot() byteutils.Hash
@@ -292,4 +396,6 @@ type WorldState interface {
 	RecordGas(from string, gas *util.Uint128) error
 
 	Reset() error
+	Snapshot() int
+	RevertToSnapshot(id int) error
 }
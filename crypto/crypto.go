@@ -22,6 +22,7 @@ import (
 	"errors"
 
 	"github.com/alexlisong/go-nebulas/crypto/keystore"
+	"github.com/alexlisong/go-nebulas/crypto/keystore/ed25519"
 	"github.com/alexlisong/go-nebulas/crypto/keystore/secp256k1"
 )
 
@@ -48,6 +49,20 @@ func NewPrivateKey(alg keystore.Algorithm, data []byte) (keystore.PrivateKey, er
 			return nil, err
 		}
 		return priv, nil
+	case keystore.ED25519:
+		var priv *ed25519.PrivateKey
+		if len(data) == 0 {
+			priv = ed25519.GeneratePrivateKey()
+		} else {
+			priv = new(ed25519.PrivateKey)
+			if err := priv.Decode(data); err != nil {
+				return nil, err
+			}
+		}
+		if priv == nil {
+			return nil, ErrAlgorithmInvalid
+		}
+		return priv, nil
 	default:
 		return nil, ErrAlgorithmInvalid
 	}
@@ -58,6 +73,8 @@ func NewSignature(alg keystore.Algorithm) (keystore.Signature, error) {
 	switch alg {
 	case keystore.SECP256K1:
 		return new(secp256k1.Signature), nil
+	case keystore.ED25519:
+		return new(ed25519.Signature), nil
 	default:
 		return nil, ErrAlgorithmInvalid
 	}
@@ -66,7 +83,7 @@ func NewSignature(alg keystore.Algorithm) (keystore.Signature, error) {
 // CheckAlgorithm check if support the input Algorithm
 func CheckAlgorithm(alg keystore.Algorithm) error {
 	switch alg {
-	case keystore.SECP256K1:
+	case keystore.SECP256K1, keystore.ED25519:
 		return nil
 	default:
 		return ErrAlgorithmInvalid
@@ -0,0 +1,83 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package ed25519
+
+import (
+	"errors"
+
+	"golang.org/x/crypto/ed25519"
+)
+
+// ed25519 has no public key recovery, the signature carries the public key
+// of the signer before the signature bytes.
+const (
+	// PrivateKeyLength private key length
+	PrivateKeyLength = ed25519.PrivateKeySize
+
+	// SeedLength private key seed length
+	SeedLength = ed25519.SeedSize
+
+	// PublicKeyLength public key length
+	PublicKeyLength = ed25519.PublicKeySize
+
+	// SignatureLength signature length, including the public key
+	SignatureLength = PublicKeyLength + ed25519.SignatureSize
+)
+
+var (
+	// ErrInvalidSignature invalid signature length
+	ErrInvalidSignature = errors.New("invalid signature")
+
+	// ErrInvalidPrivateKey invalid private key
+	ErrInvalidPrivateKey = errors.New("invalid private key")
+
+	// ErrInvalidPublicKey invalid public key
+	ErrInvalidPublicKey = errors.New("invalid public key")
+
+	// ErrVerifyFailed signature verification failed
+	ErrVerifyFailed = errors.New("signature verification failed")
+)
+
+// Verify verify the signature of data with the public key carried in signature.
+func Verify(data []byte, signature []byte, pub []byte) (bool, error) {
+	if len(signature) != SignatureLength {
+		return false, ErrInvalidSignature
+	}
+	if len(pub) != PublicKeyLength {
+		return false, ErrInvalidPublicKey
+	}
+	return ed25519.Verify(ed25519.PublicKey(pub), data, signature[PublicKeyLength:]), nil
+}
+
+// RecoverPublicKey returns the public key carried in signature, after verifying the signature.
+func RecoverPublicKey(data []byte, signature []byte) ([]byte, error) {
+	if len(signature) != SignatureLength {
+		return nil, ErrInvalidSignature
+	}
+	pub := make([]byte, PublicKeyLength)
+	copy(pub, signature[:PublicKeyLength])
+	ok, err := Verify(data, signature, pub)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrVerifyFailed
+	}
+	return pub, nil
+}
@@ -0,0 +1,74 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package ed25519
+
+import (
+	"testing"
+
+	"github.com/alexlisong/go-nebulas/crypto/hash"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignAndRecover(t *testing.T) {
+	priv := GeneratePrivateKey()
+	data := hash.Sha3256([]byte("hello nebulas"))
+
+	signer := new(Signature)
+	assert.Nil(t, signer.InitSign(priv))
+	signature, err := signer.Sign(data)
+	assert.Nil(t, err)
+	assert.Equal(t, SignatureLength, len(signature))
+
+	pub, err := new(Signature).RecoverPublic(data, signature)
+	assert.Nil(t, err)
+	expected, _ := priv.PublicKey().Encoded()
+	actual, _ := pub.Encoded()
+	assert.Equal(t, expected, actual)
+
+	verifier := new(Signature)
+	assert.Nil(t, verifier.InitVerify(priv.PublicKey()))
+	ok, err := verifier.Verify(data, signature)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	// tampered data or signature.
+	_, err = new(Signature).RecoverPublic(hash.Sha3256([]byte("other")), signature)
+	assert.Equal(t, ErrVerifyFailed, err)
+	signature[SignatureLength-1] ^= 0xff
+	_, err = new(Signature).RecoverPublic(data, signature)
+	assert.Equal(t, ErrVerifyFailed, err)
+	_, err = new(Signature).RecoverPublic(data, signature[1:])
+	assert.Equal(t, ErrInvalidSignature, err)
+}
+
+func TestPrivateKeyDecode(t *testing.T) {
+	priv := GeneratePrivateKey()
+	encoded, err := priv.Encoded()
+	assert.Nil(t, err)
+
+	full := new(PrivateKey)
+	assert.Nil(t, full.Decode(encoded))
+	assert.Equal(t, priv.PublicKey(), full.PublicKey())
+
+	seed := new(PrivateKey)
+	assert.Nil(t, seed.Decode(encoded[:SeedLength]))
+	assert.Equal(t, priv.PublicKey(), seed.PublicKey())
+
+	assert.Equal(t, ErrInvalidPrivateKey, new(PrivateKey).Decode([]byte("short")))
+}
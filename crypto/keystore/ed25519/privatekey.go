@@ -0,0 +1,93 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package ed25519
+
+import (
+	"crypto/rand"
+
+	"github.com/alexlisong/go-nebulas/crypto/keystore"
+	"github.com/alexlisong/go-nebulas/crypto/utils"
+	"github.com/alexlisong/go-nebulas/util/logging"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ed25519"
+)
+
+// PrivateKey ed25519 privatekey
+type PrivateKey struct {
+	seckey ed25519.PrivateKey
+}
+
+// GeneratePrivateKey generate a new private key
+func GeneratePrivateKey() *PrivateKey {
+	_, seckey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		logging.VLog().WithFields(logrus.Fields{
+			"err": err,
+		}).Error("Failed to generate private key.")
+		return nil
+	}
+	return &PrivateKey{seckey: seckey}
+}
+
+// Algorithm algorithm name
+func (k *PrivateKey) Algorithm() keystore.Algorithm {
+	return keystore.ED25519
+}
+
+// Encoded encoded to byte
+func (k *PrivateKey) Encoded() ([]byte, error) {
+	return k.seckey, nil
+}
+
+// Decode decode data to key, data is either the seed or the full private key.
+func (k *PrivateKey) Decode(data []byte) error {
+	switch len(data) {
+	case SeedLength:
+		k.seckey = ed25519.NewKeyFromSeed(data)
+	case PrivateKeyLength:
+		k.seckey = ed25519.PrivateKey(data)
+	default:
+		return ErrInvalidPrivateKey
+	}
+	return nil
+}
+
+// Clear clear key content
+func (k *PrivateKey) Clear() {
+	utils.ZeroBytes(k.seckey)
+}
+
+// PublicKey returns publickey
+func (k *PrivateKey) PublicKey() keystore.PublicKey {
+	if len(k.seckey) != PrivateKeyLength {
+		return nil
+	}
+	return NewPublicKey(k.seckey.Public().(ed25519.PublicKey))
+}
+
+// Sign sign data with privatekey, the public key is prefixed to the signature.
+func (k *PrivateKey) Sign(data []byte) ([]byte, error) {
+	if len(k.seckey) != PrivateKeyLength {
+		return nil, ErrInvalidPrivateKey
+	}
+	pub := k.seckey.Public().(ed25519.PublicKey)
+	signature := make([]byte, 0, SignatureLength)
+	signature = append(signature, pub...)
+	return append(signature, ed25519.Sign(k.seckey, data)...), nil
+}
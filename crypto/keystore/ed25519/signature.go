@@ -0,0 +1,75 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package ed25519
+
+import (
+	"errors"
+
+	"github.com/alexlisong/go-nebulas/crypto/keystore"
+)
+
+// Signature signature ed25519
+type Signature struct {
+	privateKey *PrivateKey
+
+	publicKey *PublicKey
+}
+
+// Algorithm ed25519 algorithm
+func (s *Signature) Algorithm() keystore.Algorithm {
+	return keystore.ED25519
+}
+
+// InitSign ed25519 init sign
+func (s *Signature) InitSign(priv keystore.PrivateKey) error {
+	s.privateKey = priv.(*PrivateKey)
+	return nil
+}
+
+// Sign ed25519 sign
+func (s *Signature) Sign(data []byte) (out []byte, err error) {
+	if s.privateKey == nil {
+		return nil, errors.New("please get private key first")
+	}
+	return s.privateKey.Sign(data)
+}
+
+// RecoverPublic returns the public key carried in the signature
+func (s *Signature) RecoverPublic(data []byte, signature []byte) (keystore.PublicKey, error) {
+	pub, err := RecoverPublicKey(data, signature)
+	if err != nil {
+		return nil, err
+	}
+	s.publicKey = NewPublicKey(pub)
+	return s.publicKey, nil
+}
+
+// InitVerify ed25519 verify init
+func (s *Signature) InitVerify(pub keystore.PublicKey) error {
+	s.publicKey = pub.(*PublicKey)
+	return nil
+}
+
+// Verify ed25519 verify
+func (s *Signature) Verify(data []byte, signature []byte) (bool, error) {
+	if s.publicKey == nil {
+		return false, errors.New("please give public key first")
+	}
+	return s.publicKey.Verify(data, signature)
+}
@@ -25,6 +25,9 @@ const (
 	// SECP256K1 a type of signer
 	SECP256K1 Algorithm = 1
 
+	// ED25519 a type of signer
+	ED25519 Algorithm = 2
+
 	// SCRYPT a type of encrypt
 	SCRYPT Algorithm = 1 << 4
 )
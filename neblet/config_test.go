@@ -306,3 +306,9 @@ func (n mockNetService) SendMessageToPeer(messageName string, data []byte, prior
 func (n mockNetService) ClosePeer(peerID string, reason error) {}
 
 func (n mockNetService) BroadcastNetworkID([]byte) {}
+
+func (n mockNetService) PeerCapabilities(peerID string) (net.Capability, bool) {
+	return net.LocalCapabilities, true
+}
+
+func (n mockNetService) ReportPeer(peerID string, verdict net.Verdict) {}
@@ -145,9 +145,16 @@ func (n *Neblet) Setup() {
 			"err": err,
 		}).Fatal("Failed to setup blockchain.")
 	}
+	n.netService.Node().SetGenesisHash(n.blockChain.GenesisBlock().Hash())
 
 	// sync
 	n.syncService = nsync.NewService(n.blockChain, n.netService)
+	if err := n.syncService.SetSyncMode(n.config.Chain.SyncMode); err != nil {
+		logging.CLog().WithFields(logrus.Fields{
+			"err":  err,
+			"mode": n.config.Chain.SyncMode,
+		}).Fatal("Failed to setup sync service.")
+	}
 	n.blockChain.SetSyncService(n.syncService)
 
 	// rpc
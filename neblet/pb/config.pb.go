@@ -130,6 +130,11 @@ type NetworkConfig struct {
 	NetworkId            uint32 `protobuf:"varint,4,opt,name=network_id,json=networkId,proto3" json:"network_id"`
 	StreamLimits         int32  `protobuf:"varint,5,opt,name=stream_limits,json=streamLimits,proto3" json:"stream_limits"`
 	ReservedStreamLimits int32  `protobuf:"varint,6,opt,name=reserved_stream_limits,json=reservedStreamLimits,proto3" json:"reserved_stream_limits"`
+	// Max milliseconds to dispatch the received messages on stop, 0 for default.
+	DispatcherDrainTimeout uint32 `protobuf:"varint,7,opt,name=dispatcher_drain_timeout,json=dispatcherDrainTimeout,proto3" json:"dispatcher_drain_timeout"`
+	// Size and seconds to live of the dedup cache of dispatched messages, 0 for default.
+	DispatcherDedupSize uint32 `protobuf:"varint,8,opt,name=dispatcher_dedup_size,json=dispatcherDedupSize,proto3" json:"dispatcher_dedup_size"`
+	DispatcherDedupTtl  uint32 `protobuf:"varint,9,opt,name=dispatcher_dedup_ttl,json=dispatcherDedupTtl,proto3" json:"dispatcher_dedup_ttl"`
 }
 
 func (m *NetworkConfig) Reset()                    { *m = NetworkConfig{} }
@@ -179,6 +184,27 @@ func (m *NetworkConfig) GetReservedStreamLimits() int32 {
 	return 0
 }
 
+func (m *NetworkConfig) GetDispatcherDrainTimeout() uint32 {
+	if m != nil {
+		return m.DispatcherDrainTimeout
+	}
+	return 0
+}
+
+func (m *NetworkConfig) GetDispatcherDedupSize() uint32 {
+	if m != nil {
+		return m.DispatcherDedupSize
+	}
+	return 0
+}
+
+func (m *NetworkConfig) GetDispatcherDedupTtl() uint32 {
+	if m != nil {
+		return m.DispatcherDedupTtl
+	}
+	return 0
+}
+
 type ChainConfig struct {
 	// ChainID.
 	ChainId uint32 `protobuf:"varint,1,opt,name=chain_id,json=chainId,proto3" json:"chain_id"`
@@ -208,6 +234,46 @@ type ChainConfig struct {
 	SignatureCiphers   []string `protobuf:"bytes,28,rep,name=signature_ciphers,json=signatureCiphers" json:"signature_ciphers"`
 	SuperNode          bool     `protobuf:"varint,30,opt,name=super_node,json=superNode,proto3" json:"super_node"`
 	UnsupportedKeyword string   `protobuf:"bytes,31,opt,name=unsupported_keyword,json=unsupportedKeyword,proto3" json:"unsupported_keyword"`
+	// Min gasPrice bump in percent to replace a pending tx with the same nonce, default 10.
+	TxReplacePriceBump uint32 `protobuf:"varint,32,opt,name=tx_replace_price_bump,json=txReplacePriceBump,proto3" json:"tx_replace_price_bump"`
+	// Bounded queue size of each block pipeline stage, default 128.
+	BlockPipelineQueueSize uint32 `protobuf:"varint,33,opt,name=block_pipeline_queue_size,json=blockPipelineQueueSize,proto3" json:"block_pipeline_queue_size"`
+	// Worker counts of block pipeline stages, execution and commit are always single-threaded.
+	BlockPipelineDecodeWorkers    uint32 `protobuf:"varint,34,opt,name=block_pipeline_decode_workers,json=blockPipelineDecodeWorkers,proto3" json:"block_pipeline_decode_workers"`
+	BlockPipelineHeaderWorkers    uint32 `protobuf:"varint,35,opt,name=block_pipeline_header_workers,json=blockPipelineHeaderWorkers,proto3" json:"block_pipeline_header_workers"`
+	BlockPipelineSignatureWorkers uint32 `protobuf:"varint,36,opt,name=block_pipeline_signature_workers,json=blockPipelineSignatureWorkers,proto3" json:"block_pipeline_signature_workers"`
+	// Max seconds a received tx's timestamp can be ahead of the node's clock, default 86400.
+	TxMaxTimestampDrift int64 `protobuf:"varint,37,opt,name=tx_max_timestamp_drift,json=txMaxTimestampDrift,proto3" json:"tx_max_timestamp_drift"`
+	// Max count of blocks waiting for parents in block pool, default 4096.
+	BlockPoolSize uint32 `protobuf:"varint,38,opt,name=block_pool_size,json=blockPoolSize,proto3" json:"block_pool_size"`
+	// Seconds a block waits for its parent in block pool before expired, default 300.
+	BlockPoolOrphanTtl int64 `protobuf:"varint,39,opt,name=block_pool_orphan_ttl,json=blockPoolOrphanTtl,proto3" json:"block_pool_orphan_ttl"`
+	// Prune the account states of blocks falling behind latest irreversible block.
+	StatePruning bool `protobuf:"varint,40,opt,name=state_pruning,json=statePruning,proto3" json:"state_pruning"`
+	// Count of blocks behind latest irreversible block whose states are retained, default 4096.
+	StateRetention uint64 `protobuf:"varint,41,opt,name=state_retention,json=stateRetention,proto3" json:"state_retention"`
+	// Sync mode of a fresh node, "full" replays all blocks, "snapshot" downloads the states at latest irreversible block, default "full".
+	SyncMode string `protobuf:"bytes,42,opt,name=sync_mode,json=syncMode,proto3" json:"sync_mode"`
+	// Count of latest canonical blocks to estimate gas price from, default 64.
+	GasPriceWindow uint32 `protobuf:"varint,43,opt,name=gas_price_window,json=gasPriceWindow,proto3" json:"gas_price_window"`
+	// Max seconds a received block's timestamp can be ahead of the node's clock, default 15.
+	BlockMaxTimestampDrift int64 `protobuf:"varint,44,opt,name=block_max_timestamp_drift,json=blockMaxTimestampDrift,proto3" json:"block_max_timestamp_drift"`
+	// Journal the pending txs in storage and restore them on restart.
+	TxJournal bool `protobuf:"varint,45,opt,name=tx_journal,json=txJournal,proto3" json:"tx_journal"`
+	// Max count of txs in journal, the oldest ones are overwritten, default 4096.
+	TxJournalSize uint32 `protobuf:"varint,46,opt,name=tx_journal_size,json=txJournalSize,proto3" json:"tx_journal_size"`
+	// Max count of txs journaled per second, default 100.
+	TxJournalRate uint32 `protobuf:"varint,47,opt,name=tx_journal_rate,json=txJournalRate,proto3" json:"tx_journal_rate"`
+	// Max gas used by txs packed in a proposing block, empty for no limit besides the block gas limit of chain.
+	BlockGasLimit string `protobuf:"bytes,48,opt,name=block_gas_limit,json=blockGasLimit,proto3" json:"block_gas_limit"`
+	// Max count of txs packed in a proposing block, 0 for no limit.
+	BlockMaxTxs uint32 `protobuf:"varint,49,opt,name=block_max_txs,json=blockMaxTxs,proto3" json:"block_max_txs"`
+	// Count of blocks a local tx can stay pending before it's re-broadcast, doubled after each attempt, default 5.
+	TxRebroadcastBlocks uint32 `protobuf:"varint,50,opt,name=tx_rebroadcast_blocks,json=txRebroadcastBlocks,proto3" json:"tx_rebroadcast_blocks"`
+	// Max count of re-broadcast attempts of a local tx, default 5.
+	TxRebroadcastMaxRetries uint32 `protobuf:"varint,51,opt,name=tx_rebroadcast_max_retries,json=txRebroadcastMaxRetries,proto3" json:"tx_rebroadcast_max_retries"`
+	// Count the queued txs, after a nonce gap, in the pending balance of accounts besides the pending ones.
+	TxReserveQueuedBalance bool `protobuf:"varint,52,opt,name=tx_reserve_queued_balance,json=txReserveQueuedBalance,proto3" json:"tx_reserve_queued_balance"`
 }
 
 func (m *ChainConfig) Reset()                    { *m = ChainConfig{} }
@@ -320,6 +386,153 @@ func (m *ChainConfig) GetUnsupportedKeyword() string {
 	return ""
 }
 
+func (m *ChainConfig) GetTxReplacePriceBump() uint32 {
+	if m != nil {
+		return m.TxReplacePriceBump
+	}
+	return 0
+}
+
+func (m *ChainConfig) GetBlockPipelineQueueSize() uint32 {
+	if m != nil {
+		return m.BlockPipelineQueueSize
+	}
+	return 0
+}
+
+func (m *ChainConfig) GetBlockPipelineDecodeWorkers() uint32 {
+	if m != nil {
+		return m.BlockPipelineDecodeWorkers
+	}
+	return 0
+}
+
+func (m *ChainConfig) GetBlockPipelineHeaderWorkers() uint32 {
+	if m != nil {
+		return m.BlockPipelineHeaderWorkers
+	}
+	return 0
+}
+
+func (m *ChainConfig) GetBlockPipelineSignatureWorkers() uint32 {
+	if m != nil {
+		return m.BlockPipelineSignatureWorkers
+	}
+	return 0
+}
+
+func (m *ChainConfig) GetTxMaxTimestampDrift() int64 {
+	if m != nil {
+		return m.TxMaxTimestampDrift
+	}
+	return 0
+}
+
+func (m *ChainConfig) GetBlockPoolSize() uint32 {
+	if m != nil {
+		return m.BlockPoolSize
+	}
+	return 0
+}
+
+func (m *ChainConfig) GetBlockPoolOrphanTtl() int64 {
+	if m != nil {
+		return m.BlockPoolOrphanTtl
+	}
+	return 0
+}
+
+func (m *ChainConfig) GetStatePruning() bool {
+	if m != nil {
+		return m.StatePruning
+	}
+	return false
+}
+
+func (m *ChainConfig) GetStateRetention() uint64 {
+	if m != nil {
+		return m.StateRetention
+	}
+	return 0
+}
+
+func (m *ChainConfig) GetSyncMode() string {
+	if m != nil {
+		return m.SyncMode
+	}
+	return ""
+}
+
+func (m *ChainConfig) GetGasPriceWindow() uint32 {
+	if m != nil {
+		return m.GasPriceWindow
+	}
+	return 0
+}
+
+func (m *ChainConfig) GetBlockMaxTimestampDrift() int64 {
+	if m != nil {
+		return m.BlockMaxTimestampDrift
+	}
+	return 0
+}
+
+func (m *ChainConfig) GetTxJournal() bool {
+	if m != nil {
+		return m.TxJournal
+	}
+	return false
+}
+
+func (m *ChainConfig) GetTxJournalSize() uint32 {
+	if m != nil {
+		return m.TxJournalSize
+	}
+	return 0
+}
+
+func (m *ChainConfig) GetTxJournalRate() uint32 {
+	if m != nil {
+		return m.TxJournalRate
+	}
+	return 0
+}
+
+func (m *ChainConfig) GetBlockGasLimit() string {
+	if m != nil {
+		return m.BlockGasLimit
+	}
+	return ""
+}
+
+func (m *ChainConfig) GetBlockMaxTxs() uint32 {
+	if m != nil {
+		return m.BlockMaxTxs
+	}
+	return 0
+}
+
+func (m *ChainConfig) GetTxRebroadcastBlocks() uint32 {
+	if m != nil {
+		return m.TxRebroadcastBlocks
+	}
+	return 0
+}
+
+func (m *ChainConfig) GetTxRebroadcastMaxRetries() uint32 {
+	if m != nil {
+		return m.TxRebroadcastMaxRetries
+	}
+	return 0
+}
+
+func (m *ChainConfig) GetTxReserveQueuedBalance() bool {
+	if m != nil {
+		return m.TxReserveQueuedBalance
+	}
+	return false
+}
+
 type RPCConfig struct {
 	// RPC listen addresses.
 	RpcListen []string `protobuf:"bytes,1,rep,name=rpc_listen,json=rpcListen" json:"rpc_listen"`
@@ -609,68 +822,100 @@ func init() {
 func init() { proto.RegisterFile("config.proto", fileDescriptorConfig) }
 
 var fileDescriptorConfig = []byte{
-	// 1002 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x56, 0x4f, 0x6f, 0x1b, 0xb7,
-	0x13, 0xfd, 0x49, 0xfe, 0x13, 0xed, 0x48, 0x76, 0x14, 0xda, 0xb1, 0xe9, 0xf8, 0x57, 0xdb, 0x55,
-	0x61, 0x40, 0x40, 0x0a, 0x17, 0x75, 0x73, 0xe9, 0xa1, 0x87, 0x40, 0x40, 0x01, 0xc3, 0x76, 0x60,
-	0xac, 0xdb, 0xf3, 0x62, 0xb5, 0x3b, 0x5a, 0x11, 0x5e, 0x2d, 0x09, 0x92, 0x72, 0xe2, 0x5b, 0x2f,
-	0x3d, 0xf6, 0xeb, 0xb5, 0x9f, 0xa6, 0x40, 0x31, 0xb3, 0x5c, 0xfd, 0x43, 0x6e, 0x9c, 0xf7, 0x1e,
-	0x39, 0xcb, 0x99, 0xc7, 0x91, 0xa0, 0x97, 0xe9, 0x6a, 0xa2, 0x8a, 0x2b, 0x63, 0xb5, 0xd7, 0xa2,
-	0x53, 0xe1, 0xb8, 0x44, 0x6f, 0xc6, 0x83, 0xbf, 0xda, 0xb0, 0x3b, 0x62, 0x4a, 0xfc, 0x08, 0xaf,
-	0x2a, 0xf4, 0x9f, 0xb5, 0x7d, 0x92, 0xad, 0x8b, 0xd6, 0xb0, 0x7b, 0x7d, 0x7c, 0xd5, 0xc8, 0xae,
-	0x3e, 0xd5, 0x44, 0xad, 0x8c, 0x1b, 0x9d, 0x78, 0x0f, 0x3b, 0xd9, 0x34, 0x55, 0x95, 0x6c, 0xf3,
-	0x86, 0xb7, 0xcb, 0x0d, 0x23, 0x82, 0x83, 0xbc, 0xd6, 0x88, 0x4b, 0xd8, 0xb2, 0x26, 0x93, 0x5b,
-	0x2c, 0x3d, 0x58, 0x4a, 0xe3, 0x87, 0x51, 0x10, 0x12, 0x4f, 0x67, 0x3a, 0x9f, 0x7a, 0x27, 0xf3,
-	0xcd, 0x33, 0x1f, 0x09, 0x6e, 0xce, 0x64, 0x8d, 0x18, 0xc2, 0xf6, 0x4c, 0xb9, 0x4c, 0x22, 0x6b,
-	0x0f, 0x97, 0xda, 0x7b, 0xe5, 0xb2, 0x20, 0x65, 0x05, 0x65, 0x4f, 0x8d, 0x91, 0x93, 0xcd, 0xec,
-	0x1f, 0x8d, 0x69, 0xb2, 0xa7, 0xc6, 0x0c, 0xfe, 0x6e, 0xc1, 0xde, 0xda, 0x65, 0x85, 0x80, 0x6d,
-	0x87, 0x98, 0xcb, 0xd6, 0xc5, 0xd6, 0x30, 0x8a, 0x79, 0x2d, 0x8e, 0x60, 0xb7, 0x54, 0xce, 0x23,
-	0x5d, 0x9c, 0xd0, 0x10, 0x89, 0x73, 0xe8, 0x1a, 0xab, 0x9e, 0x53, 0x8f, 0xc9, 0x13, 0xbe, 0xf0,
-	0x55, 0xa3, 0x18, 0x02, 0x74, 0x8b, 0x2f, 0xe2, 0x1b, 0x80, 0x50, 0xbb, 0x44, 0xe5, 0x72, 0xfb,
-	0xa2, 0x35, 0xdc, 0x8b, 0xa3, 0x80, 0xdc, 0xe4, 0xe2, 0x3b, 0xd8, 0x73, 0xde, 0x62, 0x3a, 0x4b,
-	0x4a, 0x35, 0x53, 0xde, 0xc9, 0x9d, 0x8b, 0xd6, 0x70, 0x27, 0xee, 0xd5, 0xe0, 0x1d, 0x63, 0xe2,
-	0x03, 0x1c, 0x59, 0x74, 0x68, 0x9f, 0x31, 0x4f, 0xd6, 0xd5, 0xbb, 0xac, 0x3e, 0x6c, 0xd8, 0xc7,
-	0x95, 0x5d, 0x83, 0x3f, 0xb7, 0xa1, 0xbb, 0xd2, 0x14, 0x71, 0x02, 0x1d, 0x6e, 0x0b, 0x7d, 0x47,
-	0x8b, 0xbf, 0xe3, 0x15, 0xc7, 0x37, 0xb9, 0x90, 0xf0, 0xaa, 0xc0, 0x0a, 0x9d, 0x72, 0xdc, 0xd7,
-	0x28, 0x6e, 0x42, 0x62, 0xf2, 0xd4, 0xa7, 0xb9, 0xb2, 0xb2, 0x5b, 0x33, 0x21, 0xa4, 0x8a, 0x3c,
-	0xe1, 0x0b, 0x11, 0x3d, 0x26, 0x42, 0x44, 0x17, 0x76, 0x3e, 0xb5, 0x3e, 0x99, 0xa9, 0x0a, 0xe5,
-	0xe1, 0x45, 0x6b, 0xd8, 0x89, 0x23, 0x46, 0xee, 0x55, 0x85, 0xe2, 0x1d, 0x74, 0x32, 0xad, 0xaa,
-	0x71, 0xea, 0x50, 0xbe, 0xe5, 0x8d, 0x8b, 0x58, 0x1c, 0xc2, 0x0e, 0x6d, 0xb2, 0xf2, 0x88, 0x89,
-	0x3a, 0x10, 0x67, 0x00, 0x26, 0x75, 0xce, 0x4c, 0x2d, 0xed, 0x39, 0x0e, 0x15, 0x5e, 0x20, 0xe2,
-	0x67, 0x38, 0xc1, 0x2a, 0x1d, 0x97, 0x98, 0x58, 0x9c, 0x69, 0x8f, 0x89, 0x53, 0x45, 0x95, 0x70,
-	0x41, 0xac, 0x94, 0x9c, 0xff, 0xa8, 0x16, 0xc4, 0xcc, 0x3f, 0xaa, 0xa2, 0x7a, 0x64, 0x56, 0x7c,
-	0x0f, 0xe2, 0x2b, 0x7b, 0x4e, 0x38, 0x45, 0xdf, 0x6e, 0xaa, 0x4f, 0x21, 0x2a, 0x52, 0x97, 0x18,
-	0xab, 0x32, 0x94, 0xef, 0xea, 0x6f, 0x2f, 0x52, 0xf7, 0x40, 0x71, 0x43, 0x72, 0x5f, 0xe4, 0xe9,
-	0x82, 0xe4, 0x5e, 0x88, 0xf7, 0xf0, 0x86, 0x12, 0xa4, 0x7e, 0x6e, 0x31, 0xc9, 0x94, 0x99, 0xa2,
-	0x75, 0xf2, 0xff, 0x6c, 0xa4, 0xfe, 0x82, 0x18, 0xd5, 0x38, 0x17, 0x70, 0x6e, 0xd0, 0x26, 0x95,
-	0xce, 0x51, 0x9e, 0x85, 0x02, 0x12, 0xf2, 0x49, 0xe7, 0x28, 0x7e, 0x80, 0x83, 0x79, 0xe5, 0xe6,
-	0xc6, 0x68, 0xeb, 0x31, 0x27, 0xd7, 0x7d, 0xd6, 0x36, 0x97, 0xe7, 0x9c, 0x52, 0xac, 0x50, 0xb7,
-	0x35, 0x33, 0xf8, 0xa7, 0x05, 0xd1, 0xe2, 0xc5, 0xd1, 0xe9, 0xd6, 0x64, 0x49, 0x30, 0x73, 0x6d,
-	0xf1, 0xc8, 0x9a, 0xec, 0x6e, 0xe1, 0xe7, 0xa9, 0xf7, 0x26, 0x59, 0x33, 0x3b, 0x10, 0xb4, 0x21,
-	0x98, 0xe9, 0x7c, 0x5e, 0xa2, 0xdc, 0x5a, 0x0a, 0xee, 0x19, 0xa1, 0xbb, 0x66, 0xba, 0xaa, 0x30,
-	0xf3, 0x4a, 0x57, 0x8d, 0x4f, 0xb7, 0xd9, 0xa7, 0xfd, 0x25, 0x11, 0x9c, 0xbd, 0x4c, 0xb7, 0x62,
-	0xfe, 0x90, 0x8e, 0x05, 0xa7, 0x10, 0xb1, 0x20, 0xd3, 0x96, 0xdc, 0x4e, 0xc9, 0x3a, 0x04, 0x8c,
-	0xb4, 0x75, 0x83, 0x7f, 0x5b, 0x10, 0x2d, 0x5e, 0x33, 0x49, 0x4b, 0x5d, 0x24, 0x25, 0x3e, 0x63,
-	0xc9, 0x06, 0x8f, 0xe2, 0x4e, 0xa9, 0x8b, 0x3b, 0x8a, 0xc9, 0xfc, 0x44, 0x4e, 0x54, 0x89, 0x8d,
-	0xc5, 0x4b, 0x5d, 0xfc, 0xaa, 0x4a, 0x14, 0xc7, 0x40, 0xcb, 0x24, 0x2d, 0x90, 0x9f, 0xef, 0x5e,
-	0xbc, 0x5b, 0xea, 0xe2, 0x63, 0x81, 0xe2, 0x0a, 0x0e, 0x82, 0xb1, 0x32, 0x9b, 0xba, 0x69, 0x62,
-	0x91, 0x0a, 0xcb, 0x77, 0xe9, 0xc4, 0x6f, 0x6a, 0x6a, 0x44, 0x4c, 0xcc, 0x84, 0x18, 0x42, 0x7f,
-	0x55, 0x98, 0xcc, 0x6d, 0xc9, 0x37, 0x8a, 0xe2, 0xfd, 0x6c, 0x29, 0xfb, 0xdd, 0x96, 0x34, 0xf1,
-	0x8c, 0xb1, 0x7a, 0xc2, 0xef, 0x77, 0x6d, 0xe2, 0x3d, 0x10, 0xdc, 0x4c, 0x3c, 0xd6, 0xd0, 0x13,
-	0x7c, 0x46, 0xeb, 0x94, 0xae, 0x78, 0x40, 0x46, 0x71, 0x13, 0x0e, 0x2a, 0xe8, 0xae, 0xe8, 0x37,
-	0x7b, 0x57, 0x97, 0x60, 0xb5, 0x77, 0x67, 0x00, 0x99, 0x99, 0xd3, 0x8e, 0x65, 0x19, 0x56, 0x10,
-	0xe2, 0x67, 0x38, 0x6b, 0xf8, 0x30, 0xcb, 0x96, 0xc8, 0xe0, 0x16, 0x60, 0x39, 0x65, 0xc5, 0x2f,
-	0x70, 0x9a, 0xe3, 0x24, 0x9d, 0x97, 0x9e, 0x4c, 0xe8, 0xbc, 0xb6, 0xc8, 0xf5, 0x25, 0x83, 0xa3,
-	0x0d, 0xe9, 0x65, 0x90, 0xdc, 0x06, 0x05, 0x55, 0x7c, 0x44, 0xfc, 0xe0, 0x8f, 0x36, 0x74, 0x57,
-	0xe6, 0xbb, 0xb8, 0x84, 0xfd, 0x50, 0xed, 0x19, 0x7a, 0xab, 0x32, 0xc7, 0x27, 0x74, 0xe2, 0xbd,
-	0x1a, 0xbd, 0xaf, 0x41, 0xf1, 0x00, 0xfd, 0xba, 0xbc, 0xaa, 0x2a, 0x1a, 0x13, 0x92, 0x4b, 0xf7,
-	0xaf, 0x2f, 0xbf, 0xfa, 0xbb, 0x71, 0x15, 0x37, 0xea, 0xda, 0x9f, 0xf1, 0x6b, 0xbb, 0x0e, 0x88,
-	0x0f, 0xd0, 0x51, 0xd5, 0xa4, 0x9c, 0x7f, 0xc9, 0xc7, 0x3c, 0xe3, 0xba, 0xd7, 0x72, 0x79, 0xd2,
-	0x4d, 0x60, 0x42, 0x4b, 0x16, 0x4a, 0xf1, 0x2d, 0xf4, 0xc2, 0x77, 0x26, 0x3e, 0x2d, 0x9c, 0xec,
-	0xb1, 0x37, 0xbb, 0x01, 0xfb, 0x2d, 0x2d, 0xdc, 0xe0, 0x1c, 0x5e, 0x6f, 0x24, 0x17, 0x3d, 0xe8,
-	0x34, 0x27, 0xf6, 0xff, 0x37, 0xf8, 0x02, 0xfb, 0xeb, 0xe7, 0xd3, 0x4f, 0xcf, 0x54, 0x3b, 0x1f,
-	0x8a, 0xc7, 0x6b, 0xc2, 0xd8, 0x77, 0x6d, 0x36, 0x27, 0xaf, 0xc5, 0x3e, 0xb4, 0xf3, 0x71, 0xe8,
-	0x50, 0x3b, 0x1f, 0x93, 0x66, 0xee, 0xd0, 0xb2, 0x37, 0xa3, 0x98, 0xd7, 0x34, 0x69, 0x69, 0x4a,
-	0xf2, 0x74, 0xa8, 0x6d, 0xb8, 0x88, 0xc7, 0xbb, 0xfc, 0xaf, 0xe0, 0xa7, 0xff, 0x02, 0x00, 0x00,
-	0xff, 0xff, 0xb9, 0xef, 0xf9, 0x0f, 0x25, 0x08, 0x00, 0x00,
+	// 1506 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x57, 0xcf, 0x77, 0x1b, 0xb7,
+	0x11, 0x2e, 0x25, 0x59, 0x26, 0x41, 0x4a, 0x56, 0xa0, 0x1f, 0x86, 0xed, 0xda, 0x66, 0x98, 0x3a,
+	0x61, 0xeb, 0x54, 0x89, 0x65, 0x1f, 0xda, 0xd7, 0xd7, 0x83, 0x2d, 0xbf, 0xa6, 0xae, 0xad, 0x54,
+	0x5d, 0xa9, 0x2f, 0x47, 0x3c, 0x70, 0x77, 0xb4, 0x44, 0xbd, 0x5c, 0xa0, 0x00, 0xd6, 0xa2, 0x72,
+	0xea, 0xb5, 0x87, 0xfe, 0x7d, 0xfd, 0x4f, 0x7a, 0xeb, 0x7b, 0x79, 0x33, 0xd8, 0xe5, 0x92, 0x8c,
+	0x6e, 0xc4, 0x7c, 0xdf, 0xcc, 0x2c, 0x06, 0x1f, 0x30, 0x43, 0x36, 0x48, 0x4d, 0x79, 0xa5, 0xf3,
+	0x63, 0xeb, 0x4c, 0x30, 0xbc, 0x5b, 0xc2, 0xa4, 0x80, 0x60, 0x27, 0xa3, 0xff, 0x6c, 0xb0, 0xed,
+	0x53, 0x82, 0xf8, 0x0b, 0x76, 0xb7, 0x84, 0x70, 0x6d, 0xdc, 0x47, 0xd1, 0x19, 0x76, 0xc6, 0xfd,
+	0x93, 0xfb, 0xc7, 0x0d, 0xed, 0xf8, 0xfb, 0x08, 0x44, 0x66, 0xd2, 0xf0, 0xf8, 0x73, 0x76, 0x27,
+	0x9d, 0x2a, 0x5d, 0x8a, 0x0d, 0x72, 0x38, 0x6c, 0x1d, 0x4e, 0xd1, 0x5c, 0xd3, 0x23, 0x87, 0x3f,
+	0x63, 0x9b, 0xce, 0xa6, 0x62, 0x93, 0xa8, 0xfb, 0x2d, 0x35, 0x39, 0x3f, 0xad, 0x89, 0x88, 0x63,
+	0x4c, 0x1f, 0x54, 0xf0, 0x22, 0x5b, 0x8f, 0x79, 0x81, 0xe6, 0x26, 0x26, 0x71, 0xf8, 0x98, 0x6d,
+	0xcd, 0xb4, 0x4f, 0x05, 0x10, 0xf7, 0xa0, 0xe5, 0x9e, 0x69, 0x9f, 0xd6, 0x54, 0x62, 0x60, 0x76,
+	0x65, 0xad, 0xb8, 0x5a, 0xcf, 0xfe, 0xda, 0xda, 0x26, 0xbb, 0xb2, 0x76, 0xf4, 0xbf, 0x0d, 0xb6,
+	0xb3, 0xb2, 0x59, 0xce, 0xd9, 0x96, 0x07, 0xc8, 0x44, 0x67, 0xb8, 0x39, 0xee, 0x25, 0xf4, 0x9b,
+	0x1f, 0xb1, 0xed, 0x42, 0xfb, 0x00, 0xb8, 0x71, 0xb4, 0xd6, 0x2b, 0xfe, 0x94, 0xf5, 0xad, 0xd3,
+	0x9f, 0x54, 0x00, 0xf9, 0x11, 0x6e, 0x68, 0xab, 0xbd, 0x84, 0xd5, 0xa6, 0xf7, 0x70, 0xc3, 0x1f,
+	0x33, 0x56, 0xd7, 0x4e, 0xea, 0x4c, 0x6c, 0x0d, 0x3b, 0xe3, 0x9d, 0xa4, 0x57, 0x5b, 0xde, 0x65,
+	0xfc, 0x0b, 0xb6, 0xe3, 0x83, 0x03, 0x35, 0x93, 0x85, 0x9e, 0xe9, 0xe0, 0xc5, 0x9d, 0x61, 0x67,
+	0x7c, 0x27, 0x19, 0x44, 0xe3, 0x07, 0xb2, 0xf1, 0x57, 0xec, 0xc8, 0x81, 0x07, 0xf7, 0x09, 0x32,
+	0xb9, 0xca, 0xde, 0x26, 0xf6, 0x41, 0x83, 0x5e, 0x2c, 0x7b, 0xfd, 0x8e, 0x89, 0x4c, 0x7b, 0xab,
+	0x42, 0x3a, 0x05, 0x27, 0x33, 0xa7, 0x74, 0x29, 0x83, 0x9e, 0x81, 0xa9, 0x82, 0xb8, 0x4b, 0xdf,
+	0x71, 0xd4, 0xe2, 0x6f, 0x11, 0xbe, 0x8c, 0x28, 0x3f, 0x61, 0x87, 0xcb, 0x9e, 0x90, 0x55, 0x56,
+	0x7a, 0xfd, 0x23, 0x88, 0x2e, 0xb9, 0xed, 0x2f, 0xb9, 0x21, 0x76, 0xa1, 0x7f, 0x04, 0xfe, 0x2d,
+	0x3b, 0xf8, 0x99, 0x4f, 0x08, 0x85, 0xe8, 0x91, 0x0b, 0x5f, 0x73, 0xb9, 0x0c, 0xc5, 0xe8, 0xdf,
+	0x03, 0xd6, 0x5f, 0x12, 0x0d, 0x7f, 0xc0, 0xba, 0x24, 0x1b, 0xac, 0x53, 0x87, 0xbc, 0xee, 0xd2,
+	0xfa, 0x5d, 0xc6, 0x05, 0xbb, 0x9b, 0x43, 0x09, 0x5e, 0x7b, 0xd2, 0x5d, 0x2f, 0x69, 0x96, 0x88,
+	0x64, 0x2a, 0xa8, 0x4c, 0x3b, 0xd1, 0x8f, 0x48, 0xbd, 0xc4, 0x13, 0xfb, 0x08, 0x37, 0x08, 0x0c,
+	0x08, 0xa8, 0x57, 0x78, 0x20, 0x3e, 0x28, 0x17, 0xe4, 0x4c, 0x97, 0x20, 0x0e, 0x86, 0x9d, 0x71,
+	0x37, 0xe9, 0x91, 0xe5, 0x4c, 0x97, 0xc0, 0x1f, 0xb2, 0x6e, 0x6a, 0x74, 0x39, 0x51, 0x1e, 0xc4,
+	0x21, 0x39, 0x2e, 0xd6, 0xfc, 0x80, 0xdd, 0x41, 0x27, 0x27, 0x8e, 0x08, 0x88, 0x0b, 0xfe, 0x84,
+	0x31, 0xab, 0xbc, 0xb7, 0x53, 0x87, 0x3e, 0xf7, 0x6b, 0x05, 0x2c, 0x2c, 0xfc, 0xf7, 0xec, 0x01,
+	0x94, 0x6a, 0x52, 0x80, 0x74, 0x30, 0x33, 0x01, 0xa4, 0xd7, 0x79, 0x29, 0xe9, 0xc0, 0x9c, 0x10,
+	0x94, 0xff, 0x28, 0x12, 0x12, 0xc2, 0x2f, 0x74, 0x5e, 0x5e, 0x10, 0xca, 0xbf, 0x66, 0xfc, 0x16,
+	0x9f, 0x07, 0x94, 0x62, 0xcf, 0xad, 0xb3, 0x1f, 0xb1, 0x5e, 0xae, 0xbc, 0xb4, 0x4e, 0xa7, 0x20,
+	0x1e, 0xc6, 0x6f, 0xcf, 0x95, 0x3f, 0xc7, 0x75, 0x03, 0x92, 0x6e, 0xc4, 0xa3, 0x05, 0x48, 0x5a,
+	0xe1, 0xcf, 0xd9, 0x67, 0x98, 0x40, 0x85, 0xca, 0x81, 0x4c, 0xb5, 0x9d, 0x82, 0xf3, 0xe2, 0x97,
+	0x24, 0xf4, 0xbd, 0x05, 0x70, 0x1a, 0xed, 0x54, 0xc0, 0xca, 0x82, 0x93, 0xa5, 0xc9, 0x40, 0x3c,
+	0xa9, 0x0b, 0x88, 0x96, 0xef, 0x4d, 0x06, 0xfc, 0x1b, 0xb6, 0x5f, 0x95, 0xbe, 0xb2, 0xd6, 0xb8,
+	0x00, 0x19, 0xde, 0x8a, 0x6b, 0xe3, 0x32, 0xf1, 0x94, 0x52, 0xf2, 0x25, 0xe8, 0x7d, 0x44, 0xf8,
+	0x0b, 0x76, 0x18, 0xe6, 0xd2, 0x81, 0x2d, 0x54, 0x0a, 0xf1, 0xeb, 0xe5, 0xa4, 0x9a, 0x59, 0x31,
+	0x8c, 0xd2, 0x09, 0xf3, 0x24, 0x62, 0xb4, 0x91, 0x37, 0xd5, 0xcc, 0x62, 0x49, 0x27, 0x85, 0x49,
+	0x3f, 0x4a, 0xab, 0x2d, 0x14, 0xba, 0x04, 0xf9, 0xcf, 0x0a, 0x2a, 0x88, 0x22, 0xfd, 0x3c, 0x6a,
+	0x9b, 0x08, 0xe7, 0x35, 0xfe, 0x37, 0x84, 0x49, 0xa7, 0xaf, 0xd9, 0xe3, 0x35, 0xd7, 0x0c, 0x52,
+	0x93, 0x81, 0xc4, 0x0b, 0x89, 0xdb, 0x1e, 0x91, 0xfb, 0xc3, 0x15, 0xf7, 0xb7, 0x44, 0xf9, 0x21,
+	0x32, 0x6e, 0x09, 0x31, 0x05, 0x95, 0x81, 0x5b, 0x84, 0xf8, 0xe2, 0x96, 0x10, 0x7f, 0x26, 0x4a,
+	0x13, 0xe2, 0x3b, 0x36, 0x5c, 0x0b, 0xd1, 0xd6, 0xbf, 0x89, 0xf2, 0x2b, 0x8a, 0xf2, 0x78, 0x25,
+	0xca, 0x45, 0xc3, 0x6a, 0x02, 0xbd, 0x64, 0x47, 0x61, 0x2e, 0x67, 0x6a, 0x4e, 0x57, 0xdb, 0x07,
+	0x35, 0xb3, 0x32, 0x73, 0xfa, 0x2a, 0x88, 0x67, 0xc3, 0xce, 0x78, 0x33, 0xd9, 0x0f, 0xf3, 0x33,
+	0x35, 0xbf, 0x6c, 0xb0, 0xb7, 0x08, 0xf1, 0x2f, 0xd9, 0xbd, 0x3a, 0xbb, 0x31, 0x45, 0x2c, 0xda,
+	0x97, 0x94, 0x6c, 0x27, 0x26, 0x33, 0xa6, 0xa0, 0x5a, 0xbd, 0x60, 0x87, 0x4b, 0x3c, 0xe3, 0xec,
+	0x54, 0x95, 0x74, 0xa9, 0xbf, 0xa2, 0xd8, 0x7c, 0xc1, 0xfe, 0x2b, 0x41, 0x97, 0xa1, 0x88, 0xef,
+	0x19, 0xbe, 0x86, 0xd6, 0x55, 0xa5, 0x2e, 0x73, 0x31, 0x26, 0x7d, 0x0c, 0xc8, 0x78, 0x1e, 0x6d,
+	0xfc, 0x2b, 0x76, 0x2f, 0x92, 0x1c, 0x04, 0x28, 0x83, 0x36, 0xa5, 0xf8, 0xf5, 0xb0, 0x33, 0xde,
+	0x4a, 0x76, 0xc9, 0x9c, 0x34, 0x56, 0x14, 0xad, 0xbf, 0x29, 0x53, 0x39, 0x43, 0xa5, 0xfd, 0x26,
+	0x8a, 0x16, 0x0d, 0x67, 0x28, 0xb4, 0x31, 0xdb, 0x5b, 0xc8, 0x5d, 0x5e, 0xeb, 0x32, 0x33, 0xd7,
+	0xe2, 0x39, 0x6d, 0x63, 0xb7, 0x51, 0xfd, 0x0f, 0x64, 0x6d, 0xe5, 0x72, 0x5b, 0x9d, 0xbe, 0xa6,
+	0xbd, 0x44, 0xb9, 0xfc, 0xbc, 0x54, 0x8f, 0x19, 0x0b, 0x73, 0xf9, 0x0f, 0x53, 0xb9, 0x52, 0x15,
+	0xe2, 0xb7, 0x51, 0xec, 0x61, 0xfe, 0x97, 0x68, 0xc0, 0x4a, 0xb6, 0x70, 0xac, 0xe4, 0x71, 0xac,
+	0xe4, 0x82, 0x43, 0x95, 0x5c, 0xe5, 0x39, 0x15, 0x40, 0x7c, 0xb3, 0xc6, 0x4b, 0x54, 0x80, 0xf6,
+	0x64, 0xda, 0xbb, 0xfa, 0x2d, 0x6d, 0x3b, 0x9e, 0xcc, 0x77, 0xcd, 0x85, 0x1d, 0xb1, 0x9d, 0xa5,
+	0x1d, 0xcd, 0xbd, 0x78, 0x41, 0xd1, 0xfa, 0x8b, 0x5d, 0xcc, 0x3d, 0x3f, 0xa9, 0xef, 0xd5, 0xc4,
+	0x19, 0x95, 0xa5, 0xca, 0x07, 0x49, 0xa8, 0x17, 0x27, 0xf1, 0x15, 0xc7, 0x7b, 0xb5, 0xc0, 0xde,
+	0x10, 0xc4, 0xff, 0xc0, 0x1e, 0xae, 0xf9, 0x60, 0x02, 0x07, 0xc1, 0x69, 0xf0, 0xe2, 0x25, 0x39,
+	0xde, 0x5f, 0x71, 0x3c, 0x53, 0xf3, 0x24, 0xc2, 0x58, 0x66, 0x72, 0xa6, 0x67, 0x2a, 0xde, 0xc8,
+	0x4c, 0x4e, 0x54, 0xa1, 0xca, 0x14, 0xc4, 0xab, 0xf8, 0xd0, 0xa1, 0x2f, 0xe1, 0x74, 0x23, 0xb3,
+	0x37, 0x11, 0x1d, 0xfd, 0xb7, 0xc3, 0x7a, 0x8b, 0xa9, 0x00, 0x8b, 0xee, 0x6c, 0x2a, 0xeb, 0x86,
+	0x1b, 0xdb, 0x70, 0xcf, 0xd9, 0xf4, 0xc3, 0xa2, 0xe7, 0x4e, 0x43, 0xb0, 0x72, 0xa5, 0x21, 0x33,
+	0x34, 0xad, 0x11, 0x66, 0x26, 0xab, 0x0a, 0x10, 0x9b, 0x2d, 0xe1, 0x8c, 0x2c, 0xf8, 0xde, 0xa5,
+	0xa6, 0x2c, 0x21, 0x45, 0x95, 0x35, 0xbd, 0x74, 0x8b, 0x7a, 0xe9, 0x5e, 0x0b, 0xd4, 0x7d, 0xb4,
+	0x4d, 0xb7, 0xd4, 0xa0, 0xeb, 0x74, 0x44, 0x78, 0xc4, 0x7a, 0x44, 0x48, 0x8d, 0xc3, 0x8e, 0x8c,
+	0xc9, 0xba, 0x68, 0x38, 0x35, 0xce, 0x8f, 0xfe, 0xdf, 0x61, 0xbd, 0xc5, 0xc4, 0x81, 0xd4, 0xc2,
+	0xe4, 0xb2, 0x80, 0x4f, 0x50, 0x50, 0x93, 0xeb, 0x25, 0xdd, 0xc2, 0xe4, 0x1f, 0x70, 0x8d, 0x0d,
+	0x10, 0xc1, 0x2b, 0x5d, 0x40, 0xd3, 0xe6, 0x0a, 0x93, 0xff, 0x49, 0x17, 0xc0, 0xef, 0x33, 0xfc,
+	0x29, 0x55, 0x0e, 0x34, 0x62, 0xec, 0x24, 0xdb, 0x85, 0xc9, 0x5f, 0xe7, 0xc0, 0x8f, 0xd9, 0x7e,
+	0xdd, 0x5c, 0x52, 0xa7, 0xfc, 0x14, 0x9f, 0x51, 0xe3, 0x02, 0xed, 0xa5, 0x9b, 0x7c, 0x16, 0xa1,
+	0x53, 0x44, 0x12, 0x02, 0xf0, 0xd2, 0x2c, 0x13, 0x65, 0xe5, 0x0a, 0xda, 0x51, 0x2f, 0xd9, 0x4d,
+	0x5b, 0xda, 0xdf, 0x5d, 0x81, 0x53, 0x99, 0xb5, 0xce, 0x5c, 0x89, 0xed, 0xf5, 0xa9, 0xec, 0x1c,
+	0xcd, 0xcd, 0x54, 0x46, 0x1c, 0x6c, 0xc3, 0x9f, 0xc0, 0x79, 0xbc, 0xc9, 0x59, 0xfc, 0xf2, 0x7a,
+	0x39, 0x2a, 0x59, 0x7f, 0x89, 0xbf, 0x7e, 0x76, 0xb1, 0x04, 0xcb, 0x67, 0xf7, 0x84, 0xb1, 0xd4,
+	0x56, 0xe8, 0xd1, 0x96, 0x61, 0xc9, 0x82, 0xf8, 0x0c, 0x66, 0x0d, 0x5e, 0xcf, 0x5b, 0xad, 0x65,
+	0xf4, 0x9e, 0xb1, 0x76, 0x12, 0xe4, 0x7f, 0x64, 0x8f, 0x32, 0xb8, 0x52, 0x55, 0x11, 0xb0, 0x11,
+	0xf9, 0x60, 0x1c, 0x50, 0x7d, 0xb1, 0xc9, 0x81, 0xab, 0xd3, 0x8b, 0x9a, 0xf2, 0xbe, 0x66, 0x60,
+	0xc5, 0x4f, 0x11, 0x1f, 0xfd, 0x6b, 0x83, 0xf5, 0x97, 0x66, 0x50, 0xfe, 0x8c, 0xed, 0xd6, 0xd5,
+	0x9e, 0xa1, 0xe6, 0x53, 0x4f, 0x11, 0xba, 0xc9, 0x4e, 0xb4, 0x9e, 0x45, 0x23, 0x3f, 0x67, 0x7b,
+	0xb1, 0xbc, 0xba, 0xcc, 0x1b, 0x11, 0xa2, 0x4a, 0x77, 0x4f, 0x9e, 0xdd, 0x3a, 0xdb, 0x1e, 0x27,
+	0x0d, 0x3b, 0xea, 0x33, 0xb9, 0xe7, 0x56, 0x0d, 0xfc, 0x15, 0xeb, 0xea, 0xf2, 0xaa, 0xa8, 0xe6,
+	0xd9, 0x84, 0xe6, 0x9c, 0xfe, 0x89, 0x68, 0x23, 0xbd, 0xab, 0x91, 0xfa, 0x48, 0x16, 0x4c, 0xfe,
+	0x39, 0x1b, 0xd4, 0xdf, 0x29, 0x83, 0xca, 0xbd, 0x18, 0x90, 0x36, 0xfb, 0xb5, 0xed, 0x52, 0xe5,
+	0x7e, 0xf4, 0x94, 0xdd, 0x5b, 0x4b, 0xce, 0x07, 0xac, 0xdb, 0x44, 0xdc, 0xfb, 0xc5, 0x68, 0xce,
+	0x76, 0x57, 0xe3, 0xe3, 0x78, 0x3c, 0x35, 0x3e, 0xd4, 0xc5, 0xa3, 0xdf, 0x68, 0x23, 0xdd, 0x6d,
+	0x90, 0x38, 0xe9, 0x37, 0xdf, 0x65, 0x1b, 0xd9, 0xa4, 0x3e, 0xa1, 0x8d, 0x6c, 0x82, 0x9c, 0xca,
+	0x83, 0x23, 0x6d, 0xf6, 0x12, 0xfa, 0x8d, 0xd3, 0x16, 0x4e, 0x4a, 0x34, 0x21, 0x44, 0x19, 0x2e,
+	0xd6, 0x93, 0x6d, 0xfa, 0xe7, 0xf2, 0xf2, 0xa7, 0x01, 0x00, 0x8f, 0x9f, 0x56, 0xdf, 0xc9, 0x0c,
+	0x00, 0x00,
 }
@@ -0,0 +1,87 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package net
+
+import (
+	"errors"
+
+	"github.com/alexlisong/go-nebulas/util/byteutils"
+)
+
+// NebProtocolVersion is the version of handshake, old clients don't send it.
+const NebProtocolVersion uint32 = 1
+
+// Capability is the bitset of optional protocol features of a peer.
+type Capability uint64
+
+// Capabilities
+const (
+	// CapabilityCompression peer negotiates the compression codec.
+	CapabilityCompression Capability = 1 << iota
+	// CapabilityHeaders peer serves block headers of a range.
+	CapabilityHeaders
+	// CapabilitySnapshot peer serves state snapshots.
+	CapabilitySnapshot
+)
+
+// LocalCapabilities is the capabilities advertised in handshake.
+var LocalCapabilities = CapabilityCompression | CapabilityHeaders | CapabilitySnapshot
+
+// messageCapabilities is the capability a peer must have to receive the message type,
+// message types not listed here are understood by every peer.
+var messageCapabilities = map[string]Capability{
+	HeadersRequest:        CapabilityHeaders,
+	HeadersResponse:       CapabilityHeaders,
+	SnapshotRequest:       CapabilitySnapshot,
+	SnapshotResponse:      CapabilitySnapshot,
+	SnapshotNodesRequest:  CapabilitySnapshot,
+	SnapshotNodesResponse: CapabilitySnapshot,
+}
+
+// Handshake Errors
+var (
+	ErrUnsupportedMessage  = errors.New("message is not supported by peer")
+	ErrChainIDMismatch     = errors.New("chain id of peer mismatch")
+	ErrGenesisHashMismatch = errors.New("genesis hash of peer mismatch")
+)
+
+// Has returns whether c has all capabilities of o.
+func (c Capability) Has(o Capability) bool {
+	return c&o == o
+}
+
+// Supports returns whether the peer of c understands messages of msgType.
+func (c Capability) Supports(msgType string) bool {
+	required, ok := messageCapabilities[msgType]
+	return !ok || c.Has(required)
+}
+
+// verifyHandshake checks the chain of peer in handshake, old clients without protocol version are not checked.
+func verifyHandshake(protocolVersion uint32, chainID uint32, genesisHash []byte, localChainID uint32, localGenesisHash []byte) error {
+	if protocolVersion == 0 {
+		return nil
+	}
+	if chainID != localChainID {
+		return ErrChainIDMismatch
+	}
+	if len(localGenesisHash) > 0 && !byteutils.Equal(genesisHash, localGenesisHash) {
+		return ErrGenesisHashMismatch
+	}
+	return nil
+}
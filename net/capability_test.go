@@ -0,0 +1,66 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package net
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyHandshake(t *testing.T) {
+	genesis := []byte("genesis")
+
+	// old clients send no protocol version.
+	assert.Nil(t, verifyHandshake(0, 0, nil, 100, genesis))
+
+	assert.Nil(t, verifyHandshake(NebProtocolVersion, 100, genesis, 100, genesis))
+	assert.Equal(t, ErrChainIDMismatch, verifyHandshake(NebProtocolVersion, 1, genesis, 100, genesis))
+	assert.Equal(t, ErrGenesisHashMismatch, verifyHandshake(NebProtocolVersion, 100, []byte("other"), 100, genesis))
+
+	// genesis is not checked before it's set.
+	assert.Nil(t, verifyHandshake(NebProtocolVersion, 100, []byte("other"), 100, nil))
+}
+
+func TestCapabilityGatedMessages(t *testing.T) {
+	gated := []string{HeadersRequest, HeadersResponse, SnapshotRequest, SnapshotResponse, SnapshotNodesRequest, SnapshotNodesResponse}
+	plain := []string{"newblock", "newtx", ChunkHeadersRequest, SYNCROUTE}
+
+	// a peer of old client advertises nothing in handshake.
+	old := mockStream(nil)
+	for _, msgType := range gated {
+		assert.Equal(t, ErrUnsupportedMessage, old.SendMessage(msgType, []byte("data"), MessagePriorityNormal))
+	}
+	assert.Equal(t, 0, len(old.highPriorityMessageChan)+len(old.normalPriorityMessageChan)+len(old.lowPriorityMessageChan))
+	for _, msgType := range plain {
+		assert.Nil(t, old.SendMessage(msgType, []byte("data"), MessagePriorityNormal))
+	}
+	assert.Equal(t, len(plain), len(old.normalPriorityMessageChan))
+
+	s := mockStream(SupportedCompressions())
+	atomic.StoreUint64(&s.capabilities, uint64(LocalCapabilities))
+	for _, msgType := range append(gated, plain...) {
+		assert.Nil(t, s.SendMessage(msgType, []byte("data"), MessagePriorityNormal))
+	}
+	assert.Equal(t, len(gated)+len(plain), len(s.normalPriorityMessageChan))
+
+	assert.True(t, LocalCapabilities.Has(CapabilityHeaders|CapabilitySnapshot))
+	assert.False(t, CapabilityHeaders.Has(CapabilityHeaders|CapabilitySnapshot))
+}
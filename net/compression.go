@@ -0,0 +1,127 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package net
+
+import (
+	"errors"
+
+	"github.com/alexlisong/go-nebulas/util/logging"
+	"github.com/golang/snappy"
+	"github.com/sirupsen/logrus"
+)
+
+// Compression codecs, recorded in the second reserved byte of messages.
+const (
+	CompressionNone   byte = 0
+	CompressionSnappy byte = 1
+)
+
+// Reserved flags in the first reserved byte of messages.
+const (
+	// reservedSnappyFlag old clients snappy all messages to peers setting it.
+	reservedSnappyFlag byte = 0x80
+	// reservedCodecFlag the codec of data is in the second reserved byte.
+	reservedCodecFlag byte = 0x40
+)
+
+// CompressionThreshold is the min data length of messages to compress.
+var CompressionThreshold = 1024
+
+// Compression Errors
+var (
+	ErrUnknownCompression = errors.New("unknown compression codec")
+)
+
+type compressionCodec struct {
+	name   string
+	encode func([]byte) ([]byte, error)
+	decode func([]byte) ([]byte, error)
+}
+
+// compressionCodecs the codecs this node supports.
+var compressionCodecs = map[byte]*compressionCodec{
+	CompressionNone: {
+		name:   "none",
+		encode: func(data []byte) ([]byte, error) { return data, nil },
+		decode: func(data []byte) ([]byte, error) { return data, nil },
+	},
+	CompressionSnappy: {
+		name:   "snappy",
+		encode: func(data []byte) ([]byte, error) { return snappy.Encode(nil, data), nil },
+		decode: func(data []byte) ([]byte, error) { return snappy.Decode(nil, data) },
+	},
+}
+
+// compressionPreference the codecs advertised in handshake, by preference, each of them is in compressionCodecs.
+var compressionPreference = []byte{CompressionSnappy, CompressionNone}
+
+// SupportedCompressions returns the names of supported codecs, by preference.
+func SupportedCompressions() []string {
+	names := make([]string, 0, len(compressionPreference))
+	for _, id := range compressionPreference {
+		if c, ok := compressionCodecs[id]; ok {
+			names = append(names, c.name)
+		}
+	}
+	return names
+}
+
+// negotiateCompression returns the first codec of peer's preference this node supports, unknown ones are skipped.
+func negotiateCompression(peerCompressions []string) byte {
+	for _, name := range peerCompressions {
+		for id, c := range compressionCodecs {
+			if c.name == name {
+				return id
+			}
+		}
+		logging.VLog().WithFields(logrus.Fields{
+			"codec": name,
+		}).Debug("Unknown compression codec of peer, skip it.")
+	}
+	return CompressionNone
+}
+
+// compress encodes data with codec if it's large enough, returns the actual codec used.
+func compress(codec byte, data []byte) (byte, []byte) {
+	c, ok := compressionCodecs[codec]
+	if !ok || codec == CompressionNone || len(data) < CompressionThreshold {
+		return CompressionNone, data
+	}
+	encoded, err := c.encode(data)
+	if err != nil {
+		logging.VLog().WithFields(logrus.Fields{
+			"codec": c.name,
+			"err":   err,
+		}).Debug("Failed to compress message data, send it uncompressed.")
+		return CompressionNone, data
+	}
+	return codec, encoded
+}
+
+// decompress decodes data of codec.
+func decompress(codec byte, data []byte) ([]byte, error) {
+	c, ok := compressionCodecs[codec]
+	if !ok {
+		logging.VLog().WithFields(logrus.Fields{
+			"codec": codec,
+		}).Debug("Received message of unknown compression codec.")
+		return nil, ErrUnknownCompression
+	}
+	return c.decode(data)
+}
@@ -0,0 +1,142 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package net
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/alexlisong/go-nebulas/core/pb"
+	"github.com/alexlisong/go-nebulas/crypto/hash"
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func mockStream(compressions []string) *Stream {
+	s := newStreamInstance("peer", nil, nil, &Node{config: NewConfigFromDefaults()})
+	s.negotiateCompression(compressions)
+	return s
+}
+
+// mockBlockData returns a serialized block of contract call transactions.
+func mockBlockData(n int) []byte {
+	block := &corepb.Block{
+		Header: &corepb.BlockHeader{
+			Hash:       hash.Sha3256([]byte("block")),
+			ParentHash: hash.Sha3256([]byte("parent")),
+			Coinbase:   hash.Sha3256([]byte("coinbase"))[:26],
+			Timestamp:  1540000000,
+			ChainId:    100,
+			StateRoot:  hash.Sha3256([]byte("state")),
+			TxsRoot:    hash.Sha3256([]byte("txs")),
+		},
+		Height: 1000,
+	}
+	for i := 0; i < n; i++ {
+		block.Transactions = append(block.Transactions, &corepb.Transaction{
+			Hash:     hash.Sha3256([]byte(fmt.Sprintf("tx%d", i))),
+			From:     hash.Sha3256([]byte(fmt.Sprintf("from%d", i%10)))[:26],
+			To:       hash.Sha3256([]byte("contract"))[:26],
+			Value:    make([]byte, 16),
+			Nonce:    uint64(i),
+			ChainId:  100,
+			GasPrice: []byte{0x0f, 0x42, 0x40},
+			GasLimit: []byte{0x4e, 0x20},
+			Data: &corepb.Data{
+				Type:    "call",
+				Payload: []byte(fmt.Sprintf(`{"Function":"transfer","Args":"[\"n1FF1nz6tarkDVwWQkMnnwFPuPKUaQTdptE\", %d]"}`, i)),
+			},
+			Alg:  1,
+			Sign: hash.Sha3256([]byte(fmt.Sprintf("sign%d", i))),
+		})
+	}
+	data, _ := proto.Marshal(block)
+	return data
+}
+
+func TestCompressionRoundTrip(t *testing.T) {
+	small := []byte("small data")
+	large := mockBlockData(10)
+
+	for codec, c := range compressionCodecs {
+		used, data := compress(codec, small)
+		assert.Equal(t, CompressionNone, used, c.name)
+		assert.Equal(t, small, data)
+
+		used, data = compress(codec, large)
+		assert.Equal(t, codec, used, c.name)
+		decoded, err := decompress(used, data)
+		assert.Nil(t, err)
+		assert.Equal(t, large, decoded, c.name)
+	}
+
+	_, err := decompress(0xff, large)
+	assert.Equal(t, ErrUnknownCompression, err)
+}
+
+func TestNegotiateCompression(t *testing.T) {
+	assert.Equal(t, CompressionSnappy, negotiateCompression([]string{"zstd", "snappy", "none"}))
+	assert.Equal(t, CompressionNone, negotiateCompression([]string{"brotli", "none"}))
+	assert.Equal(t, CompressionNone, negotiateCompression([]string{"brotli"}))
+	assert.Equal(t, []string{"snappy", "none"}, SupportedCompressions())
+
+	s := mockStream(nil)
+	_, ok := s.negotiatedCompression()
+	assert.False(t, ok)
+
+	s = mockStream(SupportedCompressions())
+	codec, ok := s.negotiatedCompression()
+	assert.True(t, ok)
+	assert.Equal(t, CompressionSnappy, codec)
+}
+
+func TestNebMessageCompression(t *testing.T) {
+	data := mockBlockData(500)
+
+	for _, compressions := range [][]string{{"snappy"}, {"none"}, {"zstd"}} {
+		s := mockStream(compressions)
+		msg, err := NewNebMessage(s, DefaultReserved, 0, "newblock", data)
+		assert.Nil(t, err)
+		assert.Equal(t, reservedCodecFlag, msg.Reserved()[0]&reservedCodecFlag)
+		if msg.Reserved()[1] == CompressionSnappy {
+			assert.True(t, len(msg.Data()) < len(data)/2)
+		}
+
+		// dedup hash is over the uncompressed data.
+		decoded, err := mockStream(SupportedCompressions()).messageData(msg)
+		assert.Nil(t, err)
+		assert.Equal(t, NewBaseMessage("newblock", "", data).Hash(), NewBaseMessage("newblock", "", decoded).Hash())
+	}
+
+	// old peers snappy everything without the codec.
+	old := mockStream(nil)
+	old.compressFlag.Store(old.pid.Pretty(), reservedSnappyFlag)
+	msg, err := NewNebMessage(old, DefaultReserved, 0, "newblock", data)
+	assert.Nil(t, err)
+	assert.Equal(t, byte(0), msg.Reserved()[0]&reservedCodecFlag)
+	decoded, err := mockStream(nil).messageData(msg)
+	assert.Nil(t, err)
+	assert.Equal(t, data, decoded)
+
+	// unknown codec.
+	msg, err = NewNebMessage(mockStream(nil), []byte{reservedCodecFlag, 0xff, 0}, 0, "newblock", data)
+	assert.Nil(t, err)
+	_, err = mockStream(nil).messageData(msg)
+	assert.Equal(t, ErrUncompressMessageFailed, err)
+}
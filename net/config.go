@@ -49,6 +49,10 @@ var (
 	RouteTableInternalNodeFileName = "conf/internal_list.txt"
 
 	MaxPeersCountForSyncResp = 32
+
+	DefaultDispatcherDrainTimeout = 5 * time.Second
+	DefaultDispatcherDedupSize    = 51200
+	DefaultDispatcherDedupTTL     = 10 * time.Minute
 )
 
 // Config TODO: move to proto config.
@@ -63,6 +67,9 @@ type Config struct {
 	RoutingTableDir      string
 	StreamLimits         int32
 	ReservedStreamLimits int32
+	DrainTimeout         time.Duration
+	DedupSize            int
+	DedupTTL             time.Duration
 }
 
 // Neblet interface breaks cycle import dependency.
@@ -123,6 +130,19 @@ func NewP2PConfig(n Neblet) *Config {
 		config.ReservedStreamLimits = networkConf.ReservedStreamLimits
 	}
 
+	// max wait to dispatch the received messages on stop
+	if networkConf.GetDispatcherDrainTimeout() > 0 {
+		config.DrainTimeout = time.Duration(networkConf.DispatcherDrainTimeout) * time.Millisecond
+	}
+
+	// dedup cache of dispatched messages
+	if networkConf.GetDispatcherDedupSize() > 0 {
+		config.DedupSize = int(networkConf.DispatcherDedupSize)
+	}
+	if networkConf.GetDispatcherDedupTtl() > 0 {
+		config.DedupTTL = time.Duration(networkConf.DispatcherDedupTtl) * time.Second
+	}
+
 	return config
 }
 
@@ -155,5 +175,8 @@ func NewConfigFromDefaults() *Config {
 		DefaultRoutingTableDir,
 		DefaultMaxStreamNum,
 		DefaultReservedStreamNum,
+		DefaultDispatcherDrainTimeout,
+		DefaultDispatcherDedupSize,
+		DefaultDispatcherDedupTTL,
 	}
 }
@@ -19,113 +19,615 @@
 package net
 
 import (
+	"context"
 	"sync"
+	"sync/atomic"
+	"time"
+
 	"github.com/hashicorp/golang-lru"
 	"github.com/alexlisong/go-nebulas/util/logging"
 	"github.com/sirupsen/logrus"
 )
 
+// DispatcherStarvationLimit is the max count of higher priority messages dispatched while a lower priority message waits.
+var DispatcherStarvationLimit = 16
+
+// dispatcherQueueSizes is the capacity of received message queue of each priority.
+var dispatcherQueueSizes = [MessagePriorityLow + 1]int{
+	MessagePriorityHigh:   16384,
+	MessagePriorityNormal: 65536,
+	MessagePriorityLow:    131072,
+}
+
+// messageTypeConf is the effective dispatch config of a message type, derived from its subscribers.
+type messageTypeConf struct {
+	// doFilter if any subscriber filters dup messages.
+	doFilter bool
+	// priority the highest priority of subscribers.
+	priority int
+}
+
+// seenMessage is the dedup entry of a message, with the peers known to have it.
+type seenMessage struct {
+	firstSeen time.Time
+	peers     map[string]bool
+}
+
+// blockingDelivery waits for the full msgChan of a DispatchBlock subscriber in its own goroutine,
+// then a slow subscriber never stalls the dispatch of other messages and subscribers.
+type blockingDelivery struct {
+	subscriber *Subscriber
+	// pending messages keep their order while the goroutine waits, closed when dispatch loop exits.
+	pending chan Message
+	// quitCh is closed on Deregister.
+	quitCh chan struct{}
+}
 
 // Dispatcher a message dispatcher service.
 type Dispatcher struct {
-	subscribersMap     *sync.Map
-	quitCh             chan bool
-	receivedMessageCh  chan Message
+	subscribersMap *sync.Map
+	subscribersMu  sync.Mutex
+
+	// dispatchedMessages hash -> *seenMessage, entries older than dedupTTL are expired.
+	// The peers of entry are the origins and the receivers of the message, relays skip them.
 	dispatchedMessages *lru.Cache
-	filters            map[string]bool
+	dedupTTL           time.Duration
+	dedupMu            sync.Mutex
+	dedupHits          uint64
+	dedupMisses        uint64
+
+	// typeConfs map[string]*messageTypeConf, it's read only and replaced as a whole on Register/Deregister.
+	typeConfs atomic.Value
+
+	// received messages queued by the priority of their type.
+	receivedMessageChs [MessagePriorityLow + 1]chan Message
+	skipped            [MessagePriorityLow + 1]int
+
+	// count of messages dropped for no subscriber of its type, over rate limit of peer, or full queue.
+	unhandledMessages   uint64
+	rateLimitedMessages uint64
+	queueFullMessages   uint64
+
+	rateLimiter *rateLimiter
+
+	// blockingDeliveries *Subscriber -> *blockingDelivery, created on the first message to a DispatchBlock subscriber.
+	blockingDeliveries *sync.Map
+	blockingWg         sync.WaitGroup
+
+	// quitCh is closed on stop, then new messages are refused and the queued ones are drained.
+	// ctx is canceled when the drain timed out, it aborts the waiting deliveries.
+	quitCh       chan struct{}
+	doneCh       chan struct{}
+	ctx          context.Context
+	cancel       context.CancelFunc
+	drainTimeout time.Duration
+	started      bool
+	stopOnce     sync.Once
+	mu           sync.Mutex
 }
 
 // NewDispatcher create Dispatcher instance.
 func NewDispatcher() *Dispatcher {
 	dp := &Dispatcher{
-		subscribersMap:    new(sync.Map),
-		quitCh:            make(chan bool, 10),
-		receivedMessageCh: make(chan Message, 65536),
-		filters:           make(map[string]bool),
+		subscribersMap:     new(sync.Map),
+		quitCh:             make(chan struct{}),
+		doneCh:             make(chan struct{}),
+		drainTimeout:       DefaultDispatcherDrainTimeout,
+		dedupTTL:           DefaultDispatcherDedupTTL,
+		rateLimiter:        newRateLimiter(),
+		blockingDeliveries: new(sync.Map),
+	}
+	dp.ctx, dp.cancel = context.WithCancel(context.Background())
+	dp.typeConfs.Store(make(map[string]*messageTypeConf))
+	for p, size := range dispatcherQueueSizes {
+		dp.receivedMessageChs[p] = make(chan Message, size)
 	}
 
-	dp.dispatchedMessages, _ = lru.New(51200)
+	dp.dispatchedMessages, _ = lru.New(DefaultDispatcherDedupSize)
 
 	return dp
 }
 
-// Register register subscribers.
+// Register register subscribers to all of their message types.
 func (dp *Dispatcher) Register(subscribers ...*Subscriber) {
+	dp.subscribersMu.Lock()
+	defer dp.subscribersMu.Unlock()
+
 	for _, v := range subscribers {
-		mt := v.MessageType()
-		m, _ := dp.subscribersMap.LoadOrStore(mt, new(sync.Map))
-		m.(*sync.Map).Store(v, true)
-		dp.filters[mt] = v.DoFilter()
+		for _, mt := range v.MessageTypes() {
+			m, _ := dp.subscribersMap.LoadOrStore(mt, new(sync.Map))
+			m.(*sync.Map).Store(v, true)
+		}
 	}
+	dp.updateTypeConfs(subscribers)
 }
 
-// Deregister deregister subscribers.
+// Deregister deregister subscribers from all of their message types.
 func (dp *Dispatcher) Deregister(subscribers ...*Subscriber) {
+	dp.subscribersMu.Lock()
+	defer dp.subscribersMu.Unlock()
 
 	for _, v := range subscribers {
-		mt := v.MessageType()
+		for _, mt := range v.MessageTypes() {
+			m, _ := dp.subscribersMap.Load(mt)
+			if m == nil {
+				continue
+			}
+			m.(*sync.Map).Delete(v)
+
+			// messages of the type nobody subscribes are unhandled.
+			empty := true
+			m.(*sync.Map).Range(func(key, value interface{}) bool {
+				empty = false
+				return false
+			})
+			if empty {
+				dp.subscribersMap.Delete(mt)
+			}
+		}
+
+		if b, ok := dp.blockingDeliveries.Load(v); ok {
+			dp.blockingDeliveries.Delete(v)
+			close(b.(*blockingDelivery).quitCh)
+		}
+	}
+	dp.updateTypeConfs(subscribers)
+}
+
+// updateTypeConfs recomputes the confs of message types of subscribers from the remaining subscribers.
+func (dp *Dispatcher) updateTypeConfs(subscribers []*Subscriber) {
+	old := dp.typeConfs.Load().(map[string]*messageTypeConf)
+	confs := make(map[string]*messageTypeConf, len(old))
+	for mt, conf := range old {
+		confs[mt] = conf
+	}
+
+	msgTypes := make(map[string]bool)
+	for _, v := range subscribers {
+		for _, mt := range v.MessageTypes() {
+			msgTypes[mt] = true
+		}
+	}
+
+	for mt := range msgTypes {
+		delete(confs, mt)
+
 		m, _ := dp.subscribersMap.Load(mt)
 		if m == nil {
 			continue
 		}
-		m.(*sync.Map).Delete(v)
-		delete(dp.filters, mt)
+		m.(*sync.Map).Range(func(key, value interface{}) bool {
+			s := key.(*Subscriber)
+			conf, ok := confs[mt]
+			if !ok {
+				conf = &messageTypeConf{priority: s.Priority()}
+				confs[mt] = conf
+			}
+			conf.doFilter = conf.doFilter || s.DoFilter(mt)
+			if s.Priority() < conf.priority {
+				conf.priority = s.Priority()
+			}
+			return true
+		})
 	}
+	dp.typeConfs.Store(confs)
 }
 
-// Start start message dispatch goroutine.
+func (dp *Dispatcher) typeConf(msgType string) *messageTypeConf {
+	return dp.typeConfs.Load().(map[string]*messageTypeConf)[msgType]
+}
+
+// SetDedupCache set the size and entry ttl of the dedup cache of dispatched messages, 0 for unchanged.
+// It should be called before Start.
+func (dp *Dispatcher) SetDedupCache(size int, ttl time.Duration) {
+	dp.dedupMu.Lock()
+	defer dp.dedupMu.Unlock()
+
+	if size > 0 {
+		dp.dispatchedMessages, _ = lru.New(size)
+	}
+	if ttl > 0 {
+		dp.dedupTTL = ttl
+	}
+}
+
+// DedupStats returns the hit and miss counts of the dedup cache.
+func (dp *Dispatcher) DedupStats() (hits uint64, misses uint64) {
+	return atomic.LoadUint64(&dp.dedupHits), atomic.LoadUint64(&dp.dedupMisses)
+}
+
+// dispatched checks whether the message of hash was dispatched in dedupTTL, and records it if not.
+// The peer is recorded to have the message either way.
+func (dp *Dispatcher) dispatched(hash string, peerID string) bool {
+	dp.dedupMu.Lock()
+	defer dp.dedupMu.Unlock()
+
+	seen, ok := dp.seen(hash, time.Now())
+	seen.peers[peerID] = true
+	if ok {
+		atomic.AddUint64(&dp.dedupHits, 1)
+		return true
+	}
+	atomic.AddUint64(&dp.dedupMisses, 1)
+	return false
+}
+
+// forget removes the entry of hash, the message is not dispatched.
+func (dp *Dispatcher) forget(hash string) {
+	dp.dedupMu.Lock()
+	defer dp.dedupMu.Unlock()
+
+	dp.dispatchedMessages.Remove(hash)
+}
+
+// seen returns the unexpired entry of hash, a new one is added if not found.
+func (dp *Dispatcher) seen(hash string, now time.Time) (*seenMessage, bool) {
+	if v, ok := dp.dispatchedMessages.Peek(hash); ok {
+		if seen := v.(*seenMessage); now.Sub(seen.firstSeen) < dp.dedupTTL {
+			return seen, true
+		}
+	}
+	seen := &seenMessage{firstSeen: now, peers: make(map[string]bool)}
+	dp.dispatchedMessages.Add(hash, seen)
+	return seen, false
+}
+
+// KnownBy returns whether the peer is known to have the message of hash.
+func (dp *Dispatcher) KnownBy(hash string, peerID string) bool {
+	dp.dedupMu.Lock()
+	defer dp.dedupMu.Unlock()
+
+	v, ok := dp.dispatchedMessages.Peek(hash)
+	if !ok {
+		return false
+	}
+	seen := v.(*seenMessage)
+	return time.Since(seen.firstSeen) < dp.dedupTTL && seen.peers[peerID]
+}
+
+// MarkKnown records the peers to have the message of hash, e.g. the message is sent to them.
+func (dp *Dispatcher) MarkKnown(hash string, peerIDs ...string) {
+	dp.dedupMu.Lock()
+	defer dp.dedupMu.Unlock()
+
+	seen, _ := dp.seen(hash, time.Now())
+	for _, id := range peerIDs {
+		seen.peers[id] = true
+	}
+}
+
+// SetDrainTimeout set the max wait to dispatch the queued messages on stop, it should be called before Start.
+func (dp *Dispatcher) SetDrainTimeout(timeout time.Duration) {
+	if timeout > 0 {
+		dp.drainTimeout = timeout
+	}
+}
+
+// Start start message dispatch goroutine, it does nothing if started or stopped already.
 func (dp *Dispatcher) Start() {
+	dp.mu.Lock()
+	defer dp.mu.Unlock()
+
+	select {
+	case <-dp.quitCh:
+		return
+	default:
+	}
+	if dp.started {
+		return
+	}
+	dp.started = true
+
 	logging.CLog().Info("Starting NebService Dispatcher...")
 	go dp.loop()
 }
 
 func (dp *Dispatcher) loop() {
 	logging.CLog().Info("Started NewService Dispatcher.")
+	defer close(dp.doneCh)
+	defer dp.closeBlockingDeliveries()
 
 	for {
+		msg := dp.next()
+		if msg == nil {
+			// all queues are empty, wait for any.
+			select {
+			case <-dp.quitCh:
+				logging.CLog().Info("Stoped NebService Dispatcher.")
+				return
+			case msg = <-dp.receivedMessageChs[MessagePriorityHigh]:
+			case msg = <-dp.receivedMessageChs[MessagePriorityNormal]:
+			case msg = <-dp.receivedMessageChs[MessagePriorityLow]:
+			}
+		}
+
+		if dp.ctx.Err() != nil {
+			logging.CLog().WithFields(logrus.Fields{
+				"timeout": dp.drainTimeout,
+				"dropped": dp.QueueDepth(MessagePriorityHigh) + dp.QueueDepth(MessagePriorityNormal) + dp.QueueDepth(MessagePriorityLow) + 1,
+			}).Warn("Stoped NebService Dispatcher before the queued messages are dispatched.")
+			return
+		}
+
+		msgType := msg.MessageType()
+
+		v, _ := dp.subscribersMap.Load(msgType)
+		m, _ := v.(*sync.Map)
+		w, _ := dp.subscribersMap.Load(MessageTypeWildcard)
+		wm, _ := w.(*sync.Map)
+
+		if m == nil && wm == nil {
+			atomic.AddUint64(&dp.unhandledMessages, 1)
+			logging.VLog().WithFields(logrus.Fields{
+				"msgType": msgType,
+				"from":    msg.MessageFrom(),
+			}).Debug("No subscriber of message type, drop it.")
+			continue
+		}
+
+		if m != nil {
+			dp.dispatch(m, msg)
+		}
+		if wm != nil {
+			dp.dispatch(wm, msg)
+		}
+	}
+}
+
+// next pops the queued message of highest priority, nil if all queues are empty.
+// A lower priority message waited for DispatcherStarvationLimit messages goes first.
+func (dp *Dispatcher) next() Message {
+	for p := MessagePriorityLow; p > MessagePriorityHigh; p-- {
+		if dp.skipped[p] < DispatcherStarvationLimit {
+			continue
+		}
+		if msg := dp.pop(p); msg != nil {
+			return msg
+		}
+		dp.skipped[p] = 0
+	}
+	for p := MessagePriorityHigh; p <= MessagePriorityLow; p++ {
+		if msg := dp.pop(p); msg != nil {
+			return msg
+		}
+	}
+	return nil
+}
+
+func (dp *Dispatcher) pop(priority int) Message {
+	select {
+	case msg := <-dp.receivedMessageChs[priority]:
+		dp.skipped[priority] = 0
+		for p := priority + 1; p <= MessagePriorityLow; p++ {
+			if len(dp.receivedMessageChs[p]) > 0 {
+				dp.skipped[p]++
+			}
+		}
+		return msg
+	default:
+		return nil
+	}
+}
+
+// dispatch sends msg to the subscribers in m.
+func (dp *Dispatcher) dispatch(m *sync.Map, msg Message) {
+	m.Range(func(key, value interface{}) bool {
+		subscriber := key.(*Subscriber)
+		if !dp.deliver(subscriber, msg) {
+			dp.dropped(subscriber, msg)
+		}
+		return true
+	})
+}
+
+// dropped logs msg dropped by subscriber.
+func (dp *Dispatcher) dropped(subscriber *Subscriber, msg Message) {
+	subscriber.drop()
+	logging.VLog().WithFields(logrus.Fields{
+		"msgType":    msg.MessageType(),
+		"subscriber": subscriber.Name(),
+		"policy":     subscriber.DispatchPolicy(),
+		"dropped":    subscriber.DroppedMessages(),
+	}).Warn("timeout to dispatch message.")
+}
+
+// deliver sends msg to subscriber following its dispatch policy, returns false if msg is dropped.
+func (dp *Dispatcher) deliver(subscriber *Subscriber, msg Message) bool {
+	if subscriber.policy == DispatchBlock {
+		// backpressure, the blocking delivery waits until subscriber drains msgChan.
 		select {
+		case dp.blockingDeliveryOf(subscriber).pending <- msg:
+			return true
+		default:
+			return false
+		}
+	}
 
-		case <-dp.quitCh:
-			logging.CLog().Info("Stoped NebService Dispatcher.")
+	select {
+	case subscriber.msgChan <- msg:
+		return true
+	default:
+	}
+
+	switch subscriber.policy {
+	case DispatchDropOldest:
+		select {
+		case <-subscriber.msgChan:
+			subscriber.drop()
+		default:
+		}
+		select {
+		case subscriber.msgChan <- msg:
+			return true
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+// blockingDeliveryOf returns the blocking delivery of subscriber, its goroutine is started if not found.
+// It's called only by the dispatch loop.
+func (dp *Dispatcher) blockingDeliveryOf(subscriber *Subscriber) *blockingDelivery {
+	if b, ok := dp.blockingDeliveries.Load(subscriber); ok {
+		return b.(*blockingDelivery)
+	}
+
+	b := &blockingDelivery{
+		subscriber: subscriber,
+		pending:    make(chan Message, subscriber.bufferSize),
+		quitCh:     make(chan struct{}),
+	}
+	dp.blockingDeliveries.Store(subscriber, b)
+	dp.blockingWg.Add(1)
+	go dp.blockingLoop(b)
+	return b
+}
+
+func (dp *Dispatcher) blockingLoop(b *blockingDelivery) {
+	defer dp.blockingWg.Done()
+
+	for {
+		select {
+		case msg, ok := <-b.pending:
+			if !ok {
+				return
+			}
+			if !dp.waitDeliver(b.subscriber, msg) {
+				dp.dropped(b.subscriber, msg)
+			}
+		case <-b.quitCh:
 			return
-		case msg := <-dp.receivedMessageCh:
-			msgType := msg.MessageType()
-
-			v, _ := dp.subscribersMap.Load(msgType)
-			m, _ := v.(*sync.Map)
-
-			m.Range(func(key, value interface{}) bool {
-				select {
-				case key.(*Subscriber).msgChan <- msg:
-				default:
-					logging.VLog().WithFields(logrus.Fields{
-						"msgType": msgType,
-					}).Warn("timeout to dispatch message.")
-				}
-				return true
-			})
 		}
 	}
 }
 
-// Stop stop goroutine.
+// waitDeliver waits until subscriber drains msgChan, up to its blockTimeout.
+func (dp *Dispatcher) waitDeliver(subscriber *Subscriber, msg Message) bool {
+	select {
+	case subscriber.msgChan <- msg:
+		return true
+	default:
+	}
+
+	timer := time.NewTimer(subscriber.blockTimeout)
+	defer timer.Stop()
+	select {
+	case subscriber.msgChan <- msg:
+		return true
+	case <-timer.C:
+		return false
+	case <-dp.ctx.Done():
+		return false
+	}
+}
+
+// closeBlockingDeliveries closes the pending of blocking deliveries, they exit after the pending messages are delivered.
+func (dp *Dispatcher) closeBlockingDeliveries() {
+	dp.blockingDeliveries.Range(func(key, value interface{}) bool {
+		close(value.(*blockingDelivery).pending)
+		return true
+	})
+}
+
+// UnhandledMessageCount returns the count of messages dropped for no subscriber.
+func (dp *Dispatcher) UnhandledMessageCount() uint64 {
+	return atomic.LoadUint64(&dp.unhandledMessages)
+}
+
+// RateLimitedMessageCount returns the count of messages dropped for exceeding the rate limit of peer.
+func (dp *Dispatcher) RateLimitedMessageCount() uint64 {
+	return atomic.LoadUint64(&dp.rateLimitedMessages)
+}
+
+// QueueFullMessageCount returns the count of messages dropped for the full queue.
+func (dp *Dispatcher) QueueFullMessageCount() uint64 {
+	return atomic.LoadUint64(&dp.queueFullMessages)
+}
+
+// SetRateLimit set the rate limit of msgType per peer.
+func (dp *Dispatcher) SetRateLimit(msgType string, limit RateLimit) {
+	dp.rateLimiter.setLimit(msgType, limit)
+}
+
+// SetRateLimitListener set the listener notified when a peer exceeds the rate limit.
+func (dp *Dispatcher) SetRateLimitListener(listener RateLimitListener) {
+	dp.rateLimiter.setListener(listener)
+}
+
+// QueueDepth returns the count of received messages waiting in the queue of priority.
+func (dp *Dispatcher) QueueDepth(priority int) int {
+	if priority < MessagePriorityHigh || priority > MessagePriorityLow {
+		return 0
+	}
+	return len(dp.receivedMessageChs[priority])
+}
+
+// Stop refuses new messages and returns after the queued ones are delivered or the drain timed out.
+// It's safe to call Stop multiple times and concurrently.
 func (dp *Dispatcher) Stop() {
-	logging.CLog().Info("Stopping NebService Dispatcher...")
+	dp.mu.Lock()
+	started := dp.started
+	dp.stopOnce.Do(func() {
+		logging.CLog().Info("Stopping NebService Dispatcher...")
+		close(dp.quitCh)
+	})
+	dp.mu.Unlock()
 
-	dp.quitCh <- true
+	if started {
+		timer := time.AfterFunc(dp.drainTimeout, dp.cancel)
+		<-dp.doneCh
+		dp.blockingWg.Wait()
+		timer.Stop()
+	}
+	dp.cancel()
 }
 
 // PutMessage put new message to chan, then subscribers will be notified to process.
 func (dp *Dispatcher) PutMessage(msg Message) {
+	// limit before dedup, the dropped message is allowed from other peers.
+	if from := msg.MessageFrom(); from != "" && !dp.rateLimiter.allow(from, msg.MessageType()) {
+		atomic.AddUint64(&dp.rateLimitedMessages, 1)
+		return
+	}
+
+	// stopped, refuse new messages.
+	select {
+	case <-dp.quitCh:
+		return
+	default:
+	}
+
 	// it's a optimize strategy for message dispatch, according to https://github.com/alexlisong/go-nebulas/issues/50
 	hash := msg.Hash()
-	if dp.filters[msg.MessageType()] {
-		if exist, _ := dp.dispatchedMessages.ContainsOrAdd(hash, hash); exist == true {
+	conf := dp.typeConf(msg.MessageType())
+	filtered := conf != nil && conf.doFilter
+	if filtered {
+		if dp.dispatched(hash, msg.MessageFrom()) {
 			// duplicated message, ignore.
 			return
 		}
 	}
 
-	dp.receivedMessageCh <- msg
+	select {
+	case dp.receivedMessageChs[priorityOf(conf)] <- msg:
+	default:
+		// the dropped message is allowed from other peers.
+		if filtered {
+			dp.forget(hash)
+		}
+		atomic.AddUint64(&dp.queueFullMessages, 1)
+		logging.VLog().WithFields(logrus.Fields{
+			"msgType": msg.MessageType(),
+			"from":    msg.MessageFrom(),
+		}).Debug("Received message queue is full, drop it.")
+	}
 }
 
+// priorityOf returns the priority of message type, messages nobody subscribed are low priority.
+func priorityOf(conf *messageTypeConf) int {
+	if conf == nil {
+		return MessagePriorityLow
+	}
+	return conf.priority
+}
@@ -0,0 +1,446 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package net
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func receiveMessage(t *testing.T, ch chan Message) Message {
+	select {
+	case msg := <-ch:
+		return msg
+	case <-time.After(time.Second):
+		t.Fatal("timeout to receive message")
+	}
+	return nil
+}
+
+func TestDispatcherUnregisteredMessageType(t *testing.T) {
+	dp := NewDispatcher()
+	ch := make(chan Message, 10)
+	dp.Register(NewSubscriber(t, []string{"newblock"}, WithMessageChan(ch)))
+	dp.Start()
+	defer dp.Stop()
+
+	dp.PutMessage(NewBaseMessage("unknown", "", []byte("1")))
+	dp.PutMessage(NewBaseMessage("newblock", "", []byte("2")))
+
+	msg := receiveMessage(t, ch)
+	assert.Equal(t, "newblock", msg.MessageType())
+	assert.Equal(t, []byte("2"), msg.Data())
+	assert.Equal(t, uint64(1), dp.UnhandledMessageCount())
+}
+
+func TestDispatcherWildcardSubscriber(t *testing.T) {
+	dp := NewDispatcher()
+	ch := make(chan Message, 10)
+	all := make(chan Message, 10)
+	dp.Register(
+		NewSubscriber(t, []string{"newblock"}, WithMessageChan(ch)),
+		NewSubscriber(t, []string{MessageTypeWildcard}, WithMessageChan(all)),
+	)
+	dp.Start()
+	defer dp.Stop()
+
+	dp.PutMessage(NewBaseMessage("newblock", "", []byte("1")))
+	dp.PutMessage(NewBaseMessage("unknown", "", []byte("2")))
+
+	assert.Equal(t, "newblock", receiveMessage(t, ch).MessageType())
+	assert.Equal(t, "newblock", receiveMessage(t, all).MessageType())
+	assert.Equal(t, "unknown", receiveMessage(t, all).MessageType())
+	assert.Equal(t, uint64(0), dp.UnhandledMessageCount())
+}
+
+func TestDispatcherPriorityQueues(t *testing.T) {
+	dp := NewDispatcher()
+	blockCh := make(chan Message, 1)
+	txCh := make(chan Message, 1)
+	all := make(chan Message, 1)
+	dp.Register(
+		NewSubscriber(t, []string{"newblock"}, WithMessageChan(blockCh), WithPriority(MessagePriorityHigh)),
+		NewSubscriber(t, []string{"newtx"}, WithMessageChan(txCh), WithDispatchPolicy(DispatchBlock), WithPriority(MessagePriorityLow)),
+		NewSubscriber(t, []string{MessageTypeWildcard}, WithMessageChan(all)),
+	)
+
+	for i := 0; i < 100000; i++ {
+		dp.PutMessage(NewBaseMessage("newtx", "", []byte(fmt.Sprintf("tx%d", i))))
+	}
+	dp.PutMessage(NewBaseMessage("newblock", "", []byte("block")))
+	assert.Equal(t, 100000, dp.QueueDepth(MessagePriorityLow))
+	assert.Equal(t, 1, dp.QueueDepth(MessagePriorityHigh))
+
+	dp.SetDrainTimeout(10 * time.Millisecond)
+	dp.Start()
+	defer dp.Stop()
+
+	// the tx subscriber is never drained, the block goes ahead of the backlog.
+	msg := receiveMessage(t, blockCh)
+	assert.Equal(t, []byte("block"), msg.Data())
+	assert.Equal(t, []byte("block"), receiveMessage(t, all).Data())
+}
+
+func TestDispatcherStalledBlockingSubscriber(t *testing.T) {
+	dp := NewDispatcher()
+	dp.SetDrainTimeout(10 * time.Millisecond)
+	stalled := make(chan Message, 1)
+	txCh := make(chan Message, 10)
+	s := NewSubscriber(t, []string{"newblock"}, WithMessageChan(stalled), WithDispatchPolicy(DispatchBlock), WithBlockTimeout(time.Minute))
+	dp.Register(
+		s,
+		NewSubscriber(t, []string{"newtx"}, WithMessageChan(txCh), WithPriority(MessagePriorityHigh)),
+	)
+	dp.Start()
+	defer dp.Stop()
+
+	// the block subscriber is never drained, it waits for room of the second block.
+	start := time.Now()
+	dp.PutMessage(NewBaseMessage("newblock", "", []byte("block0")))
+	dp.PutMessage(NewBaseMessage("newblock", "", []byte("block1")))
+	dp.PutMessage(NewBaseMessage("newtx", "", []byte("tx")))
+	assert.Equal(t, []byte("tx"), receiveMessage(t, txCh).Data())
+	assert.True(t, time.Since(start) < 100*time.Millisecond)
+	assert.Equal(t, uint64(0), s.DroppedMessages())
+}
+
+func TestDispatcherStarvationGuard(t *testing.T) {
+	dp := NewDispatcher()
+	size := 4*DispatcherStarvationLimit + 1
+	all := make(chan Message, size)
+	dp.Register(
+		NewSubscriber(t, []string{"newblock"}, WithMessageChan(make(chan Message, size)), WithPriority(MessagePriorityHigh)),
+		NewSubscriber(t, []string{"newtx"}, WithMessageChan(make(chan Message, size)), WithPriority(MessagePriorityLow)),
+		NewSubscriber(t, []string{MessageTypeWildcard}, WithMessageChan(all)),
+	)
+
+	dp.PutMessage(NewBaseMessage("newtx", "", []byte("tx")))
+	for i := 0; i < size-1; i++ {
+		dp.PutMessage(NewBaseMessage("newblock", "", []byte(fmt.Sprintf("block%d", i))))
+	}
+
+	dp.Start()
+	defer dp.Stop()
+
+	for i := 0; i < size; i++ {
+		if receiveMessage(t, all).MessageType() == "newtx" {
+			assert.Equal(t, DispatcherStarvationLimit, i)
+			return
+		}
+	}
+	t.Fatal("tx is starved")
+}
+
+// saturate puts n messages to a slow subscriber of policy, waits until all are dispatched.
+func saturate(t *testing.T, subscriber *Subscriber, n int) {
+	dp := NewDispatcher()
+	// wildcard subscribers get a message after the typed ones.
+	all := make(chan Message, n)
+	dp.Register(subscriber, NewSubscriber(t, []string{MessageTypeWildcard}, WithMessageChan(all)))
+	dp.Start()
+	defer dp.Stop()
+
+	for i := 0; i < n; i++ {
+		dp.PutMessage(NewBaseMessage("newblock", "", []byte(fmt.Sprintf("%d", i))))
+	}
+	for i := 0; i < n; i++ {
+		receiveMessage(t, all)
+	}
+}
+
+func TestDispatchPolicy(t *testing.T) {
+	ch := make(chan Message, 2)
+	s := NewSubscriber(t, []string{"newblock"}, WithMessageChan(ch))
+	saturate(t, s, 5)
+	assert.Equal(t, uint64(3), s.DroppedMessages())
+	assert.Equal(t, []byte("0"), (<-ch).Data())
+	assert.Equal(t, []byte("1"), (<-ch).Data())
+
+	ch = make(chan Message, 2)
+	s = NewSubscriber(t, []string{"newblock"}, WithMessageChan(ch), WithDispatchPolicy(DispatchDropOldest))
+	saturate(t, s, 5)
+	assert.Equal(t, uint64(3), s.DroppedMessages())
+	assert.Equal(t, []byte("3"), (<-ch).Data())
+	assert.Equal(t, []byte("4"), (<-ch).Data())
+
+	ch = make(chan Message, 2)
+	s = NewSubscriber(t, []string{"newblock"}, WithMessageChan(ch), WithDispatchPolicy(DispatchBlock), WithBlockTimeout(50*time.Millisecond))
+	start := time.Now()
+	saturate(t, s, 3)
+	assert.True(t, time.Since(start) >= 50*time.Millisecond)
+	assert.Equal(t, uint64(1), s.DroppedMessages())
+	assert.Equal(t, []byte("0"), (<-ch).Data())
+	assert.Equal(t, []byte("1"), (<-ch).Data())
+
+	// a slow consumer within the timeout gets every message.
+	ch = make(chan Message, 1)
+	s = NewSubscriber(t, []string{"newblock"}, WithMessageChan(ch), WithDispatchPolicy(DispatchBlock))
+	received := make(chan int)
+	go func() {
+		for i := 0; i < 5; i++ {
+			time.Sleep(10 * time.Millisecond)
+			assert.Equal(t, []byte(fmt.Sprintf("%d", i)), (<-ch).Data())
+		}
+		received <- 5
+	}()
+	saturate(t, s, 5)
+	assert.Equal(t, 5, <-received)
+	assert.Equal(t, uint64(0), s.DroppedMessages())
+}
+
+func TestDispatcherStopDrain(t *testing.T) {
+	dp := NewDispatcher()
+	ch := make(chan Message, 1000)
+	dp.Register(NewSubscriber(t, []string{"newblock"}, WithMessageChan(ch)))
+	for i := 0; i < 1000; i++ {
+		dp.PutMessage(NewBaseMessage("newblock", "", []byte(fmt.Sprintf("%d", i))))
+	}
+	dp.Start()
+	dp.Stop()
+	assert.Equal(t, 1000, len(ch))
+
+	// refused after stop, and restart does nothing.
+	dp.PutMessage(NewBaseMessage("newblock", "", []byte("new")))
+	dp.Start()
+	assert.Equal(t, 0, dp.QueueDepth(MessagePriorityNormal)+dp.QueueDepth(MessagePriorityLow))
+	assert.Equal(t, 1000, len(ch))
+}
+
+func TestDispatcherStopDrainTimeout(t *testing.T) {
+	dp := NewDispatcher()
+	dp.SetDrainTimeout(100 * time.Millisecond)
+	ch := make(chan Message, 1)
+	dp.Register(NewSubscriber(t, []string{"newblock"}, WithMessageChan(ch), WithDispatchPolicy(DispatchBlock)))
+	for i := 0; i < 1000; i++ {
+		dp.PutMessage(NewBaseMessage("newblock", "", []byte(fmt.Sprintf("%d", i))))
+	}
+	dp.Start()
+
+	start := time.Now()
+	done := make(chan bool)
+	for i := 0; i < 3; i++ {
+		go func() {
+			dp.Stop()
+			done <- true
+		}()
+	}
+	for i := 0; i < 3; i++ {
+		<-done
+	}
+	elapsed := time.Since(start)
+	assert.True(t, elapsed >= 100*time.Millisecond)
+	assert.True(t, elapsed < DefaultDispatchBlockTimeout)
+	assert.Equal(t, 1, len(ch))
+}
+
+func TestDispatcherFilterOfRemainingSubscribers(t *testing.T) {
+	dp := NewDispatcher()
+	ch := make(chan Message, 10)
+	filtered := NewSubscriber("filtered", []string{"newtx"}, WithMessageChan(ch), WithDoFilter())
+	unfiltered := NewSubscriber("unfiltered", []string{"newtx"}, WithMessageChan(ch))
+	dp.Register(filtered, unfiltered)
+
+	msg := NewBaseMessage("newtx", "", []byte("tx"))
+	dp.PutMessage(msg)
+	dp.PutMessage(msg)
+	assert.Equal(t, 1, dp.QueueDepth(MessagePriorityNormal))
+
+	// the other subscriber still filters dup messages.
+	dp.Deregister(unfiltered)
+	dp.PutMessage(msg)
+	assert.Equal(t, 1, dp.QueueDepth(MessagePriorityNormal))
+
+	dp.Deregister(filtered)
+	dp.Register(unfiltered)
+	dp.PutMessage(msg)
+	assert.Equal(t, 2, dp.QueueDepth(MessagePriorityNormal))
+}
+
+func TestDispatcherConcurrentRegister(t *testing.T) {
+	dp := NewDispatcher()
+	dp.Start()
+	defer dp.Stop()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				s := NewSubscriber(i, []string{"newtx"}, WithBufferSize(1))
+				if j%2 == 0 {
+					s = NewSubscriber(i, []string{"newtx"}, WithBufferSize(1), WithDoFilter())
+				}
+				dp.Register(s)
+				dp.Deregister(s)
+			}
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				dp.PutMessage(NewBaseMessage("newtx", "", []byte(fmt.Sprintf("%d-%d", i, j))))
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestDispatcherDedupTTL(t *testing.T) {
+	dp := NewDispatcher()
+	dp.SetDedupCache(16, 50*time.Millisecond)
+	dp.Register(NewSubscriber(t, []string{"newtx"}, WithMessageChan(make(chan Message, 10)), WithDoFilter()))
+
+	msg := NewBaseMessage("newtx", "", []byte("tx"))
+	dp.PutMessage(msg)
+	dp.PutMessage(msg)
+	assert.Equal(t, 1, dp.QueueDepth(MessagePriorityNormal))
+
+	// re-sent after ttl is dispatched again.
+	time.Sleep(60 * time.Millisecond)
+	dp.PutMessage(msg)
+	dp.PutMessage(msg)
+	assert.Equal(t, 2, dp.QueueDepth(MessagePriorityNormal))
+
+	hits, misses := dp.DedupStats()
+	assert.Equal(t, uint64(2), hits)
+	assert.Equal(t, uint64(2), misses)
+}
+
+func TestDispatcherDedupQueueFull(t *testing.T) {
+	defer func(size int) { dispatcherQueueSizes[MessagePriorityNormal] = size }(dispatcherQueueSizes[MessagePriorityNormal])
+	dispatcherQueueSizes[MessagePriorityNormal] = 1
+
+	dp := NewDispatcher()
+	ch := make(chan Message, 10)
+	dp.Register(NewSubscriber(t, []string{"newblock"}, WithMessageChan(ch), WithDoFilter()))
+
+	// the first copy is dropped for the full queue.
+	dp.PutMessage(NewBaseMessage("newblock", "peer0", []byte("filler")))
+	dp.PutMessage(NewBaseMessage("newblock", "peer1", []byte("block")))
+	assert.Equal(t, uint64(1), dp.QueueFullMessageCount())
+	assert.False(t, dp.KnownBy(NewBaseMessage("newblock", "", []byte("block")).Hash(), "peer1"))
+
+	dp.Start()
+	defer dp.Stop()
+	assert.Equal(t, []byte("filler"), receiveMessage(t, ch).Data())
+
+	// the copy of another peer is delivered.
+	dp.PutMessage(NewBaseMessage("newblock", "peer2", []byte("block")))
+	msg := receiveMessage(t, ch)
+	assert.Equal(t, []byte("block"), msg.Data())
+	assert.Equal(t, "peer2", msg.MessageFrom())
+}
+
+type mockRateLimitListener struct {
+	peers map[string]uint64
+}
+
+func (l *mockRateLimitListener) OnRateLimited(peerID string, msgType string, violations uint64) {
+	l.peers[peerID] = violations
+}
+
+func TestDispatcherRateLimit(t *testing.T) {
+	dp := NewDispatcher()
+	dp.SetRateLimit("newtx", RateLimit{Rate: 0.01, Burst: 10})
+	listener := &mockRateLimitListener{peers: make(map[string]uint64)}
+	dp.SetRateLimitListener(listener)
+	dp.Register(NewSubscriber(t, []string{"newtx"}, WithMessageChan(make(chan Message, 10))))
+
+	for i := 0; i < 100; i++ {
+		dp.PutMessage(NewBaseMessage("newtx", "abusive", []byte(fmt.Sprintf("a%d", i))))
+		if i%10 == 0 {
+			dp.PutMessage(NewBaseMessage("newtx", "honest", []byte(fmt.Sprintf("h%d", i))))
+		}
+	}
+
+	// 10 of the abusive peer in burst and all 10 of the honest one.
+	assert.Equal(t, 20, dp.QueueDepth(MessagePriorityNormal))
+	assert.Equal(t, uint64(90), dp.RateLimitedMessageCount())
+	assert.Equal(t, uint64(0), dp.QueueFullMessageCount())
+	assert.Equal(t, map[string]uint64{"abusive": 90}, listener.peers)
+
+	// other message types have their own buckets.
+	dp.PutMessage(NewBaseMessage("newblock", "abusive", []byte("block")))
+	assert.Equal(t, uint64(90), dp.RateLimitedMessageCount())
+}
+
+func TestSubscriberOptions(t *testing.T) {
+	s := NewSubscriber(t, []string{"newblock", "dlreply"})
+	assert.Equal(t, "*testing.T", s.Name())
+	assert.Equal(t, DefaultSubscriberBufferSize, cap(s.MessageChan()))
+	assert.False(t, s.DoFilter("newblock"))
+	assert.Equal(t, MessagePriorityNormal, s.Priority())
+	assert.Equal(t, DispatchDropNewest, s.DispatchPolicy())
+
+	ch := make(chan Message, 3)
+	s = NewSubscriber(t, []string{"newblock", "dlreply"},
+		WithMessageChan(ch),
+		WithBufferSize(10),
+		WithDoFilter("newblock"),
+		WithMessageWeight("newblock", MessageWeightNewBlock),
+		WithPriority(MessagePriorityHigh-1),
+		WithDispatchPolicy(DispatchBlock),
+		WithName("BlockPool"),
+	)
+	assert.Equal(t, "BlockPool", s.Name())
+	assert.Equal(t, ch, s.MessageChan())
+	assert.True(t, s.DoFilter("newblock"))
+	assert.False(t, s.DoFilter("dlreply"))
+	assert.Equal(t, MessageWeightNewBlock, s.MessageWeight("newblock"))
+	assert.Equal(t, MessageWeightZero, s.MessageWeight("dlreply"))
+	assert.Equal(t, MessagePriorityHigh, s.Priority())
+	assert.True(t, s.Blocking())
+
+	s = NewSubscriber(t, []string{"newtx"}, WithBufferSize(10), WithDoFilter())
+	assert.Equal(t, 10, cap(s.MessageChan()))
+	assert.True(t, s.DoFilter("newtx"))
+}
+
+func TestDispatcherMultiTypeSubscriber(t *testing.T) {
+	dp := NewDispatcher()
+	s := NewSubscriber(t, []string{"newblock", "dlreply"}, WithBufferSize(10), WithDoFilter("newblock"))
+	dp.Register(s)
+	dp.Start()
+	defer dp.Stop()
+
+	// dup messages are filtered only for newblock.
+	for i := 0; i < 2; i++ {
+		dp.PutMessage(NewBaseMessage("newblock", "", []byte("block")))
+		dp.PutMessage(NewBaseMessage("dlreply", "", []byte("reply")))
+	}
+	types := make(map[string]int)
+	for i := 0; i < 3; i++ {
+		types[receiveMessage(t, s.MessageChan()).MessageType()]++
+	}
+	assert.Equal(t, map[string]int{"newblock": 1, "dlreply": 2}, types)
+
+	// deregistered from all types.
+	dp.Deregister(s)
+	assert.Nil(t, dp.typeConf("newblock"))
+	assert.Nil(t, dp.typeConf("dlreply"))
+	dp.PutMessage(NewBaseMessage("newblock", "", []byte("other block")))
+	dp.PutMessage(NewBaseMessage("dlreply", "", []byte("other reply")))
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 0, len(s.MessageChan()))
+	assert.Equal(t, uint64(2), dp.UnhandledMessageCount())
+}
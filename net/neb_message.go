@@ -174,10 +174,14 @@ func (message *NebMessage) Length() uint64 {
 // NewNebMessage new neb message
 func NewNebMessage(s *Stream, reserved []byte, version byte, messageName string, data []byte) (*NebMessage, error) {
 	chainID := s.node.config.ChainID
-	// if remote peer version >= compress version, compress message data.
 	if messageName != HELLO {
-		if v, ok := s.compressFlag.Load(s.pid.Pretty()); ok {
-			if (v.(byte) & 0x80) > 0 {
+		if codec, ok := s.negotiatedCompression(); ok {
+			// record the codec actually used, small data is not compressed.
+			codec, data = compress(codec, data)
+			reserved = []byte{reserved[0] | reservedCodecFlag, codec, reserved[2]}
+		} else if v, ok := s.compressFlag.Load(s.pid.Pretty()); ok {
+			// if remote peer version >= compress version, compress message data.
+			if (v.(byte) & reservedSnappyFlag) > 0 {
 				data = snappy.Encode(nil, data)
 			}
 		}
@@ -35,7 +35,8 @@ func NewNebService(n Neblet) (*NebService, error) {
 		logging.CLog().Fatal("Failed to find network config in config file")
 		return nil, ErrConfigLackNetWork
 	}
-	node, err := NewNode(NewP2PConfig(n))
+	config := NewP2PConfig(n)
+	node, err := NewNode(config)
 	if err != nil {
 		return nil, err
 	}
@@ -44,6 +45,10 @@ func NewNebService(n Neblet) (*NebService, error) {
 		node:       node,
 		dispatcher: NewDispatcher(),
 	}
+	ns.dispatcher.SetDrainTimeout(config.DrainTimeout)
+	ns.dispatcher.SetDedupCache(config.DedupSize, config.DedupTTL)
+	ns.dispatcher.SetRateLimitListener(node.streamManager)
+	node.streamManager.dispatcher = ns.dispatcher
 	node.SetNebService(ns)
 
 	return ns, nil
@@ -107,6 +112,16 @@ func (ns *NebService) Relay(name string, msg Serializable, priority int) {
 	ns.node.RelayMessage(name, msg, priority)
 }
 
+// PeerCapabilities return the capabilities of the connected peer, services choose fallback paths for the missing ones.
+func (ns *NebService) PeerCapabilities(peerID string) (Capability, bool) {
+	return ns.node.PeerCapabilities(peerID)
+}
+
+// ReportPeer report the validation verdict of a message from the peer, the misbehaving peer is banned.
+func (ns *NebService) ReportPeer(peerID string, verdict Verdict) {
+	ns.node.streamManager.ReportPeer(peerID, verdict)
+}
+
 // BroadcastNetworkID broadcast networkID when changed.
 func (ns *NebService) BroadcastNetworkID(msg []byte) {
 	// TODO: @robin networkID.
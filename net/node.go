@@ -61,6 +61,7 @@ type Node struct {
 	host          *basichost.BasicHost
 	streamManager *StreamManager
 	routeTable    *RouteTable
+	genesisHash   []byte
 }
 
 // NewNode return new Node according to the config.
@@ -158,6 +159,30 @@ func (node *Node) Config() *Config {
 	return node.config
 }
 
+// GenesisHash return the genesis hash checked in handshake.
+func (node *Node) GenesisHash() []byte {
+	return node.genesisHash
+}
+
+// SetGenesisHash set the genesis hash checked in handshake, it should be called before Start.
+func (node *Node) SetGenesisHash(hash []byte) {
+	node.genesisHash = hash
+}
+
+// PeerCapabilities return the capabilities of the connected peer.
+func (node *Node) PeerCapabilities(peerID string) (Capability, bool) {
+	stream := node.streamManager.FindByPeerID(peerID)
+	if stream == nil {
+		return 0, false
+	}
+	return stream.Capabilities(), true
+}
+
+// PeerScores return the reputation of the peers, the lowest score first.
+func (node *Node) PeerScores() []*PeerScore {
+	return node.streamManager.PeerScores()
+}
+
 // SetNebService set netService
 func (node *Node) SetNebService(ns *NebService) {
 	node.netService = ns
@@ -33,6 +33,13 @@ const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
 type Hello struct {
 	NodeId        string `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
 	ClientVersion string `protobuf:"bytes,2,opt,name=client_version,json=clientVersion,proto3" json:"client_version,omitempty"`
+	// supported compression codecs, by preference.
+	Compressions []string `protobuf:"bytes,3,rep,name=compressions" json:"compressions,omitempty"`
+	// unset by old clients.
+	ProtocolVersion uint32 `protobuf:"varint,4,opt,name=protocol_version,json=protocolVersion,proto3" json:"protocol_version,omitempty"`
+	ChainId         uint32 `protobuf:"varint,5,opt,name=chain_id,json=chainId,proto3" json:"chain_id,omitempty"`
+	GenesisHash     []byte `protobuf:"bytes,6,opt,name=genesis_hash,json=genesisHash,proto3" json:"genesis_hash,omitempty"`
+	Capabilities    uint64 `protobuf:"varint,7,opt,name=capabilities,proto3" json:"capabilities,omitempty"`
 }
 
 func (m *Hello) Reset()                    { *m = Hello{} }
@@ -54,9 +61,51 @@ func (m *Hello) GetClientVersion() string {
 	return ""
 }
 
+func (m *Hello) GetCompressions() []string {
+	if m != nil {
+		return m.Compressions
+	}
+	return nil
+}
+
+func (m *Hello) GetProtocolVersion() uint32 {
+	if m != nil {
+		return m.ProtocolVersion
+	}
+	return 0
+}
+
+func (m *Hello) GetChainId() uint32 {
+	if m != nil {
+		return m.ChainId
+	}
+	return 0
+}
+
+func (m *Hello) GetGenesisHash() []byte {
+	if m != nil {
+		return m.GenesisHash
+	}
+	return nil
+}
+
+func (m *Hello) GetCapabilities() uint64 {
+	if m != nil {
+		return m.Capabilities
+	}
+	return 0
+}
+
 type OK struct {
 	NodeId        string `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
 	ClientVersion string `protobuf:"bytes,2,opt,name=client_version,json=clientVersion,proto3" json:"client_version,omitempty"`
+	// supported compression codecs, by preference.
+	Compressions []string `protobuf:"bytes,3,rep,name=compressions" json:"compressions,omitempty"`
+	// unset by old clients.
+	ProtocolVersion uint32 `protobuf:"varint,4,opt,name=protocol_version,json=protocolVersion,proto3" json:"protocol_version,omitempty"`
+	ChainId         uint32 `protobuf:"varint,5,opt,name=chain_id,json=chainId,proto3" json:"chain_id,omitempty"`
+	GenesisHash     []byte `protobuf:"bytes,6,opt,name=genesis_hash,json=genesisHash,proto3" json:"genesis_hash,omitempty"`
+	Capabilities    uint64 `protobuf:"varint,7,opt,name=capabilities,proto3" json:"capabilities,omitempty"`
 }
 
 func (m *OK) Reset()                    { *m = OK{} }
@@ -78,6 +127,41 @@ func (m *OK) GetClientVersion() string {
 	return ""
 }
 
+func (m *OK) GetCompressions() []string {
+	if m != nil {
+		return m.Compressions
+	}
+	return nil
+}
+
+func (m *OK) GetProtocolVersion() uint32 {
+	if m != nil {
+		return m.ProtocolVersion
+	}
+	return 0
+}
+
+func (m *OK) GetChainId() uint32 {
+	if m != nil {
+		return m.ChainId
+	}
+	return 0
+}
+
+func (m *OK) GetGenesisHash() []byte {
+	if m != nil {
+		return m.GenesisHash
+	}
+	return nil
+}
+
+func (m *OK) GetCapabilities() uint64 {
+	if m != nil {
+		return m.Capabilities
+	}
+	return 0
+}
+
 type Peers struct {
 	Peers []*PeerInfo `protobuf:"bytes,1,rep,name=peers" json:"peers,omitempty"`
 }
@@ -128,17 +212,23 @@ func init() {
 func init() { proto.RegisterFile("message.proto", fileDescriptorMessage) }
 
 var fileDescriptorMessage = []byte{
-	// 188 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0xcd, 0x4d, 0x2d, 0x2e,
-	0x4e, 0x4c, 0x4f, 0xd5, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x62, 0xcd, 0x4b, 0x2d, 0x29, 0x48,
-	0x52, 0x72, 0xe7, 0x62, 0xf5, 0x48, 0xcd, 0xc9, 0xc9, 0x17, 0x12, 0xe7, 0x62, 0xcf, 0xcb, 0x4f,
-	0x49, 0x8d, 0xcf, 0x4c, 0x91, 0x60, 0x54, 0x60, 0xd4, 0xe0, 0x0c, 0x62, 0x03, 0x71, 0x3d, 0x53,
-	0x84, 0x54, 0xb9, 0xf8, 0x92, 0x73, 0x32, 0x53, 0xf3, 0x4a, 0xe2, 0xcb, 0x52, 0x8b, 0x8a, 0x33,
-	0xf3, 0xf3, 0x24, 0x98, 0xc0, 0xf2, 0xbc, 0x10, 0xd1, 0x30, 0x88, 0xa0, 0x92, 0x0b, 0x17, 0x93,
-	0xbf, 0x37, 0xc5, 0xa6, 0xe8, 0x71, 0xb1, 0x06, 0xa4, 0xa6, 0x16, 0x15, 0x0b, 0xa9, 0x72, 0xb1,
-	0x16, 0x80, 0x18, 0x12, 0x8c, 0x0a, 0xcc, 0x1a, 0xdc, 0x46, 0xfc, 0x7a, 0x60, 0xe7, 0xea, 0x81,
-	0x24, 0x3d, 0xf3, 0xd2, 0xf2, 0x83, 0x20, 0xb2, 0x4a, 0x06, 0x5c, 0x1c, 0x30, 0x21, 0x21, 0x3e,
-	0x2e, 0x26, 0xb8, 0xb5, 0x4c, 0x99, 0x29, 0x42, 0x22, 0x5c, 0xac, 0x89, 0x29, 0x29, 0x45, 0xc5,
-	0x12, 0x4c, 0x0a, 0xcc, 0x1a, 0x9c, 0x41, 0x10, 0x4e, 0x12, 0x1b, 0xd8, 0xfb, 0xc6, 0x80, 0x00,
-	0x00, 0x00, 0xff, 0xff, 0x69, 0x57, 0x77, 0xcd, 0x0f, 0x01, 0x00, 0x00,
+	// 279 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe4, 0x92, 0xcd, 0x6a, 0xc3, 0x30,
+	0x10, 0x84, 0x91, 0x13, 0xe7, 0x67, 0xf3, 0x57, 0x44, 0xa1, 0xea, 0x4d, 0x35, 0x04, 0xd4, 0x8b,
+	0x29, 0xed, 0x4b, 0x24, 0xf4, 0xd0, 0xa2, 0x43, 0xaf, 0x41, 0xb1, 0xb6, 0xb1, 0xc0, 0x91, 0x8c,
+	0xd7, 0xf4, 0xcd, 0x0b, 0x3d, 0x16, 0xcb, 0x75, 0x20, 0xcf, 0xd0, 0x9b, 0xe6, 0x1b, 0xb1, 0x9a,
+	0x11, 0x0b, 0xab, 0x33, 0x12, 0x99, 0x13, 0xe6, 0x75, 0x13, 0xda, 0xc0, 0x53, 0x8f, 0x6d, 0x7d,
+	0xcc, 0x7e, 0x18, 0xa4, 0x3b, 0xac, 0xaa, 0xc0, 0xef, 0x60, 0xea, 0x83, 0xc5, 0x83, 0xb3, 0x82,
+	0x49, 0xa6, 0xe6, 0x7a, 0xd2, 0xc9, 0xbd, 0xe5, 0x5b, 0x58, 0x17, 0x95, 0x43, 0xdf, 0x1e, 0xbe,
+	0xb0, 0x21, 0x17, 0xbc, 0x48, 0xa2, 0xbf, 0xea, 0xe9, 0x47, 0x0f, 0x79, 0x06, 0xcb, 0x22, 0x9c,
+	0xeb, 0x06, 0xa9, 0x93, 0x24, 0x46, 0x72, 0xa4, 0xe6, 0xfa, 0x8a, 0xf1, 0x47, 0xb8, 0x89, 0xaf,
+	0x17, 0xa1, 0xba, 0x0c, 0x1b, 0x4b, 0xa6, 0x56, 0x7a, 0x33, 0xf0, 0x61, 0xdc, 0x3d, 0xcc, 0x8a,
+	0xd2, 0x38, 0xdf, 0xe5, 0x49, 0xe3, 0x95, 0x69, 0xd4, 0x7b, 0xcb, 0x1f, 0x60, 0x79, 0x42, 0x8f,
+	0xe4, 0xe8, 0x50, 0x1a, 0x2a, 0xc5, 0x44, 0x32, 0xb5, 0xd4, 0x8b, 0x3f, 0xb6, 0x33, 0x54, 0xc6,
+	0x30, 0xa6, 0x36, 0x47, 0x57, 0xb9, 0xd6, 0x21, 0x89, 0xa9, 0x64, 0x6a, 0xac, 0xaf, 0x58, 0xf6,
+	0xcd, 0x20, 0x79, 0x7b, 0xfd, 0x7f, 0xbd, 0x73, 0x48, 0xdf, 0x11, 0x1b, 0xe2, 0x5b, 0x48, 0xeb,
+	0xee, 0x20, 0x98, 0x1c, 0xa9, 0xc5, 0xf3, 0x26, 0x8f, 0x2b, 0x91, 0x77, 0xe6, 0xde, 0x7f, 0x06,
+	0xdd, 0xbb, 0xd9, 0x13, 0xcc, 0x06, 0xc4, 0xd7, 0x90, 0x5c, 0xfe, 0x29, 0x71, 0x96, 0xdf, 0x42,
+	0x6a, 0xac, 0x6d, 0x48, 0x24, 0xb1, 0x75, 0x2f, 0x8e, 0x93, 0x58, 0xea, 0xe5, 0x77, 0x00, 0x56,
+	0x40, 0x34, 0xa1, 0x73, 0x02, 0x00, 0x00,
 }
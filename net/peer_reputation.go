@@ -0,0 +1,276 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package net
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alexlisong/go-nebulas/util/logging"
+	"github.com/sirupsen/logrus"
+)
+
+// Verdict is the validation outcome of a received message, reported by its consumer.
+type Verdict int
+
+// Verdicts of the received messages.
+const (
+	// VerdictValid the message is valid and new.
+	VerdictValid Verdict = iota
+	// VerdictUseless the message is valid but duplicated or stale.
+	VerdictUseless
+	// VerdictInvalid the message fails the validation, e.g. bad signature or wrong chainID.
+	VerdictInvalid
+)
+
+// Peer reputation, a peer is banned when its score drops to PeerBanThreshold.
+// Scores decay to zero by half every PeerScoreHalfLife, so are the penalties of honest mistakes.
+var (
+	PeerVerdictScores = map[Verdict]float64{
+		VerdictValid:   1,
+		VerdictUseless: -1,
+		VerdictInvalid: -20,
+	}
+
+	PeerScoreMax      = 100.0
+	PeerBanThreshold  = -100.0
+	PeerScoreHalfLife = 10 * time.Minute
+
+	// the ban duration doubles on every ban of a peer, up to MaxPeerBanDuration.
+	PeerBanDuration    = 10 * time.Minute
+	MaxPeerBanDuration = 24 * time.Hour
+
+	PeerBanListFileName = "banlist.cache"
+)
+
+// Error types
+var (
+	ErrPeerBanned = errors.New("peer is banned")
+)
+
+// PeerScore is the reputation of a peer.
+type PeerScore struct {
+	PeerID      string
+	Score       float64
+	Bans        uint32
+	BannedUntil time.Time
+}
+
+// Banned return if the peer is banned at now.
+func (s *PeerScore) Banned(now time.Time) bool {
+	return now.Before(s.BannedUntil)
+}
+
+type peerRecord struct {
+	score       float64
+	bans        uint32
+	bannedUntil time.Time
+	updatedAt   time.Time
+}
+
+// decay halves the score every PeerScoreHalfLife since last update.
+func (r *peerRecord) decay(now time.Time) {
+	if elapsed := now.Sub(r.updatedAt); elapsed > 0 && PeerScoreHalfLife > 0 {
+		r.score *= math.Pow(0.5, float64(elapsed)/float64(PeerScoreHalfLife))
+	}
+	r.updatedAt = now
+}
+
+// peerReputation scores the peers by the verdicts of their messages, and bans the misbehaving ones.
+// The bans are persisted in file, the scores are not.
+type peerReputation struct {
+	peers    map[string]*peerRecord
+	filePath string
+	mu       sync.Mutex
+}
+
+func newPeerReputation(filePath string) *peerReputation {
+	return &peerReputation{
+		peers:    make(map[string]*peerRecord),
+		filePath: filePath,
+	}
+}
+
+// report applies the verdict to the score of peer, return true if the peer is banned just now.
+func (r *peerReputation) report(peerID string, verdict Verdict, now time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p, ok := r.peers[peerID]
+	if !ok {
+		p = &peerRecord{updatedAt: now}
+		r.peers[peerID] = p
+	}
+	p.decay(now)
+	p.score = math.Min(p.score+PeerVerdictScores[verdict], PeerScoreMax)
+
+	if now.Before(p.bannedUntil) || p.score > PeerBanThreshold {
+		return false
+	}
+
+	p.bans++
+	duration := PeerBanDuration
+	for i := uint32(1); i < p.bans && duration < MaxPeerBanDuration; i++ {
+		duration *= 2
+	}
+	if duration > MaxPeerBanDuration {
+		duration = MaxPeerBanDuration
+	}
+	p.bannedUntil = now.Add(duration)
+	p.score = 0
+
+	logging.VLog().WithFields(logrus.Fields{
+		"pid":      peerID,
+		"bans":     p.bans,
+		"duration": duration,
+	}).Warn("Ban the misbehaving peer.")
+
+	r.save()
+	return true
+}
+
+func (r *peerReputation) banned(peerID string, now time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p, ok := r.peers[peerID]
+	return ok && now.Before(p.bannedUntil)
+}
+
+// scores return the decayed scores of peers, sorted by score.
+func (r *peerReputation) scores(now time.Time) []*PeerScore {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.prune(now)
+	scores := make([]*PeerScore, 0, len(r.peers))
+	for id, p := range r.peers {
+		p.decay(now)
+		scores = append(scores, &PeerScore{
+			PeerID:      id,
+			Score:       p.score,
+			Bans:        p.bans,
+			BannedUntil: p.bannedUntil,
+		})
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].Score != scores[j].Score {
+			return scores[i].Score < scores[j].Score
+		}
+		return scores[i].PeerID < scores[j].PeerID
+	})
+	return scores
+}
+
+// prune forgets the peers of neutral score, and the bans expired longer than MaxPeerBanDuration.
+func (r *peerReputation) prune(now time.Time) {
+	for id, p := range r.peers {
+		p.decay(now)
+		if math.Abs(p.score) >= 1 {
+			continue
+		}
+		if p.bans == 0 || now.Sub(p.bannedUntil) > MaxPeerBanDuration {
+			delete(r.peers, id)
+		}
+	}
+}
+
+// load the bans from file, each line is "<pid> <bans> <banned until in unix seconds>".
+func (r *peerReputation) load() {
+	if r.filePath == "" {
+		return
+	}
+	file, err := os.Open(r.filePath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logging.VLog().WithFields(logrus.Fields{
+				"filePath": r.filePath,
+				"err":      err,
+			}).Warn("Failed to open ban list file.")
+		}
+		return
+	}
+	defer file.Close()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			logging.VLog().WithFields(logrus.Fields{
+				"text": line,
+			}).Warn("Invalid line in ban list file.")
+			continue
+		}
+		bans, err1 := strconv.ParseUint(fields[1], 10, 32)
+		until, err2 := strconv.ParseInt(fields[2], 10, 64)
+		if err1 != nil || err2 != nil {
+			logging.VLog().WithFields(logrus.Fields{
+				"text": line,
+			}).Warn("Invalid line in ban list file.")
+			continue
+		}
+		r.peers[fields[0]] = &peerRecord{
+			bans:        uint32(bans),
+			bannedUntil: time.Unix(until, 0),
+			updatedAt:   now,
+		}
+	}
+	r.prune(now)
+}
+
+// save the bans to file, must be called with lock held.
+func (r *peerReputation) save() {
+	if r.filePath == "" {
+		return
+	}
+	file, err := os.Create(r.filePath)
+	if err != nil {
+		logging.VLog().WithFields(logrus.Fields{
+			"filePath": r.filePath,
+			"err":      err,
+		}).Warn("Failed to open ban list file.")
+		return
+	}
+	defer file.Close()
+
+	file.WriteString(fmt.Sprintf("# %s\n", time.Now().String()))
+	for id, p := range r.peers {
+		if p.bans == 0 {
+			continue
+		}
+		file.WriteString(fmt.Sprintf("%s %d %d\n", id, p.bans, p.bannedUntil.Unix()))
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package net
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeerBannedForInvalidBlocks(t *testing.T) {
+	sm := NewStreamManager(NewConfigFromDefaults())
+
+	bad := mockStream(nil).pid.Pretty()
+	honest := "honest"
+	for i := 0; i < 100; i++ {
+		sm.ReportPeer(honest, VerdictValid)
+		if i%10 == 0 {
+			// a duplicated block relayed by the honest peer.
+			sm.ReportPeer(honest, VerdictUseless)
+		}
+		if !sm.IsBanned(bad) {
+			sm.ReportPeer(bad, VerdictInvalid)
+		}
+	}
+
+	assert.True(t, sm.IsBanned(bad))
+	assert.False(t, sm.IsBanned(honest))
+
+	scores := sm.PeerScores()
+	assert.Equal(t, 2, len(scores))
+	assert.Equal(t, bad, scores[0].PeerID)
+	assert.Equal(t, uint32(1), scores[0].Bans)
+	assert.Equal(t, honest, scores[1].PeerID)
+	assert.True(t, scores[1].Score > 0)
+	assert.Equal(t, uint32(0), scores[1].Bans)
+
+	// the stream of banned peer is refused.
+	sm.AddStream(mockStream(nil))
+	assert.Equal(t, int32(0), sm.Count())
+	assert.Nil(t, sm.FindByPeerID(bad))
+}
+
+func TestPeerBanBackoff(t *testing.T) {
+	r := newPeerReputation("")
+	now := time.Now()
+
+	banUntilNextBan := func() time.Duration {
+		for !r.report("bad", VerdictInvalid, now) {
+			if r.banned("bad", now) {
+				now = r.peers["bad"].bannedUntil
+			}
+		}
+		return r.peers["bad"].bannedUntil.Sub(now)
+	}
+
+	assert.Equal(t, PeerBanDuration, banUntilNextBan())
+	assert.True(t, r.banned("bad", now))
+	assert.Equal(t, 2*PeerBanDuration, banUntilNextBan())
+	assert.Equal(t, 4*PeerBanDuration, banUntilNextBan())
+	for i := 0; i < 10; i++ {
+		banUntilNextBan()
+	}
+	assert.Equal(t, MaxPeerBanDuration, banUntilNextBan())
+}
+
+func TestPeerScoreDecay(t *testing.T) {
+	r := newPeerReputation("")
+	now := time.Now()
+
+	// penalties close to the threshold are forgiven in time.
+	for i := 0; i < 4; i++ {
+		assert.False(t, r.report("peer", VerdictInvalid, now))
+	}
+	now = now.Add(PeerScoreHalfLife)
+	assert.InDelta(t, -40, r.scores(now)[0].Score, 0.001)
+	assert.False(t, r.report("peer", VerdictInvalid, now))
+	assert.False(t, r.banned("peer", now))
+
+	// the neutral peers are pruned.
+	now = now.Add(10 * PeerScoreHalfLife)
+	assert.Equal(t, 0, len(r.scores(now)))
+
+	// the good score is capped.
+	for i := 0; i < 1000; i++ {
+		r.report("peer", VerdictValid, now)
+	}
+	assert.Equal(t, PeerScoreMax, r.scores(now)[0].Score)
+}
+
+func TestPeerBansPersisted(t *testing.T) {
+	dir, err := ioutil.TempDir("", "banlist")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	config := NewConfigFromDefaults()
+	config.RoutingTableDir = dir
+	sm := NewStreamManager(config)
+	for !sm.IsBanned("bad") {
+		sm.ReportPeer("bad", VerdictInvalid)
+	}
+	sm.ReportPeer("honest", VerdictValid)
+
+	restarted := NewStreamManager(config)
+	restarted.reputation.load()
+	assert.True(t, restarted.IsBanned("bad"))
+	assert.False(t, restarted.IsBanned("honest"))
+
+	// the backoff continues after restart.
+	scores := restarted.PeerScores()
+	assert.Equal(t, 1, len(scores))
+	assert.Equal(t, uint32(1), scores[0].Bans)
+	assert.Equal(t, sm.PeerScores()[0].BannedUntil.Unix(), scores[0].BannedUntil.Unix())
+}
@@ -0,0 +1,163 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package net
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimit is the token bucket of a message type per peer, Rate messages per second up to Burst.
+type RateLimit struct {
+	Rate  float64
+	Burst float64
+}
+
+// Rate limits of received messages per peer, blocks are generous while tx gossip is tighter.
+var (
+	DefaultRateLimit = RateLimit{Rate: 100, Burst: 1000}
+
+	DefaultRateLimits = map[string]RateLimit{
+		"newblock": {Rate: 50, Burst: 500},
+		"dlreply":  {Rate: 50, Burst: 500},
+		"newtx":    {Rate: 20, Burst: 200},
+	}
+
+	// MaxRateLimitViolations is the count of rate limited messages of a peer before it's disconnected.
+	MaxRateLimitViolations uint64 = 1000
+
+	// rateLimiterIdlePeer is the idle time of a peer before its buckets are released.
+	rateLimiterIdlePeer = time.Minute
+)
+
+// RateLimitListener is notified when a peer exceeds the rate limit of a message type.
+type RateLimitListener interface {
+	OnRateLimited(peerID string, msgType string, violations uint64)
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// take refills the bucket since last take, and takes a token if any.
+func (b *tokenBucket) take(limit RateLimit, now time.Time) bool {
+	b.tokens += now.Sub(b.last).Seconds() * limit.Rate
+	if b.tokens > limit.Burst {
+		b.tokens = limit.Burst
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+type peerBuckets struct {
+	buckets    map[string]*tokenBucket
+	violations uint64
+	lastSeen   time.Time
+}
+
+// rateLimiter limits the received messages per peer and message type.
+type rateLimiter struct {
+	limits       map[string]RateLimit
+	defaultLimit RateLimit
+	peers        map[string]*peerBuckets
+	lastPrune    time.Time
+	listener     RateLimitListener
+	mu           sync.Mutex
+}
+
+func newRateLimiter() *rateLimiter {
+	rl := &rateLimiter{
+		limits:       make(map[string]RateLimit),
+		defaultLimit: DefaultRateLimit,
+		peers:        make(map[string]*peerBuckets),
+		lastPrune:    time.Now(),
+	}
+	for t, limit := range DefaultRateLimits {
+		rl.limits[t] = limit
+	}
+	return rl
+}
+
+func (rl *rateLimiter) setLimit(msgType string, limit RateLimit) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.limits[msgType] = limit
+}
+
+func (rl *rateLimiter) setListener(listener RateLimitListener) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.listener = listener
+}
+
+// allow takes a token of msgType from the bucket of peer, the listener is notified if none left.
+func (rl *rateLimiter) allow(peerID string, msgType string) bool {
+	rl.mu.Lock()
+
+	now := time.Now()
+	if now.Sub(rl.lastPrune) > rateLimiterIdlePeer {
+		rl.prune(now)
+	}
+
+	p, ok := rl.peers[peerID]
+	if !ok {
+		p = &peerBuckets{buckets: make(map[string]*tokenBucket)}
+		rl.peers[peerID] = p
+	}
+	p.lastSeen = now
+
+	limit, ok := rl.limits[msgType]
+	if !ok {
+		limit = rl.defaultLimit
+	}
+	b, ok := p.buckets[msgType]
+	if !ok {
+		b = &tokenBucket{tokens: limit.Burst, last: now}
+		p.buckets[msgType] = b
+	}
+	if b.take(limit, now) {
+		rl.mu.Unlock()
+		return true
+	}
+
+	p.violations++
+	violations, listener := p.violations, rl.listener
+	rl.mu.Unlock()
+
+	if listener != nil {
+		listener.OnRateLimited(peerID, msgType, violations)
+	}
+	return false
+}
+
+// prune releases the buckets of idle peers.
+func (rl *rateLimiter) prune(now time.Time) {
+	for id, p := range rl.peers {
+		if now.Sub(p.lastSeen) > rateLimiterIdlePeer {
+			delete(rl.peers, id)
+		}
+	}
+	rl.lastPrune = now
+}
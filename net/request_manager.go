@@ -0,0 +1,323 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package net
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/alexlisong/go-nebulas/util/byteutils"
+	"github.com/alexlisong/go-nebulas/util/logging"
+	"github.com/sirupsen/logrus"
+)
+
+// Request Errors
+var (
+	ErrRequestTimeout           = errors.New("request timeout")
+	ErrTooManyInflightRequests  = errors.New("too many in-flight requests to peer")
+	ErrUnknownRequestType       = errors.New("unknown request type")
+	ErrRequestManagerNotRunning = errors.New("request manager is not running")
+)
+
+// Default request config
+var (
+	DefaultMaxInflightRequestsPerPeer = 16
+	DefaultRequestRetries             = 2
+)
+
+// requestMagic prefixes the data of requests and responses carrying a request id.
+var requestMagic = []byte{0x4e, 0x52, 0x51, 0x31}
+
+const requestHeaderLength = 12
+
+// EncodeRequestData returns the data of request or response of id.
+func EncodeRequestData(id uint64, payload []byte) []byte {
+	data := make([]byte, requestHeaderLength+len(payload))
+	copy(data, requestMagic)
+	copy(data[len(requestMagic):], byteutils.FromUint64(id))
+	copy(data[requestHeaderLength:], payload)
+	return data
+}
+
+// ParseRequestData returns the request id and payload of data, false if data carries no request id.
+func ParseRequestData(data []byte) (uint64, []byte, bool) {
+	if len(data) < requestHeaderLength || !byteutils.Equal(data[:len(requestMagic)], requestMagic) {
+		return 0, nil, false
+	}
+	return byteutils.Uint64(data[len(requestMagic):requestHeaderLength]), data[requestHeaderLength:], true
+}
+
+// Response is the result of a request.
+type Response struct {
+	PeerID string
+	Data   []byte
+	Err    error
+}
+
+type pendingRequest struct {
+	id      uint64
+	msgType string
+	data    []byte
+	timeout time.Duration
+	timer   *time.Timer
+
+	// peerID the peer currently asked, tried all peers asked.
+	peerID  string
+	tried   map[string]bool
+	retries int
+
+	responseCh chan *Response
+}
+
+// RequestManager correlates the requests to peers with their responses by request id.
+// Responses are still dispatched to other subscribers of the response type.
+type RequestManager struct {
+	ns            Service
+	responseTypes map[string]string
+	messageCh     chan Message
+	quitCh        chan bool
+	running       bool
+
+	nextID      uint64
+	pending     map[uint64]*pendingRequest
+	inflight    map[string]int
+	maxInflight int
+	retries     int
+	mu          sync.Mutex
+
+	unmatchedResponses uint64
+}
+
+// NewRequestManager create RequestManager instance.
+func NewRequestManager(ns Service) *RequestManager {
+	return &RequestManager{
+		ns:            ns,
+		responseTypes: make(map[string]string),
+		messageCh:     make(chan Message, 1024),
+		quitCh:        make(chan bool, 1),
+		pending:       make(map[uint64]*pendingRequest),
+		inflight:      make(map[string]int),
+		maxInflight:   DefaultMaxInflightRequestsPerPeer,
+		retries:       DefaultRequestRetries,
+	}
+}
+
+// SetLimits set the max in-flight requests per peer and retries on timeout, it should be called before Start.
+func (rm *RequestManager) SetLimits(maxInflight int, retries int) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	if maxInflight > 0 {
+		rm.maxInflight = maxInflight
+	}
+	if retries >= 0 {
+		rm.retries = retries
+	}
+}
+
+// Handle subscribes responseType as the response of requestType, it should be called before Start.
+func (rm *RequestManager) Handle(requestType string, responseType string) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	rm.responseTypes[requestType] = responseType
+	rm.ns.Register(NewSubscriber(rm, []string{responseType}, WithMessageChan(rm.messageCh), WithName("RequestManager")))
+}
+
+// Start start loop.
+func (rm *RequestManager) Start() {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	rm.running = true
+	go rm.loop()
+}
+
+// Stop stop loop, the pending requests fail.
+func (rm *RequestManager) Stop() {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	if !rm.running {
+		return
+	}
+	rm.running = false
+	rm.quitCh <- true
+
+	for id, p := range rm.pending {
+		p.timer.Stop()
+		delete(rm.pending, id)
+		p.responseCh <- &Response{PeerID: p.peerID, Err: ErrRequestManagerNotRunning}
+	}
+	rm.inflight = make(map[string]int)
+}
+
+func (rm *RequestManager) loop() {
+	for {
+		select {
+		case <-rm.quitCh:
+			return
+		case msg := <-rm.messageCh:
+			rm.onResponse(msg)
+		}
+	}
+}
+
+// UnmatchedResponses returns the count of duplicate, late or unsolicited responses.
+func (rm *RequestManager) UnmatchedResponses() uint64 {
+	return atomic.LoadUint64(&rm.unmatchedResponses)
+}
+
+// SendRequest sends payload to peer, the returned channel receives the response, or an error if all retries timed out.
+// Another peer is asked on timeout.
+func (rm *RequestManager) SendRequest(peerID string, msgType string, payload []byte, timeout time.Duration) (<-chan *Response, error) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	if !rm.running {
+		return nil, ErrRequestManagerNotRunning
+	}
+	if _, ok := rm.responseTypes[msgType]; !ok {
+		return nil, ErrUnknownRequestType
+	}
+	if rm.inflight[peerID] >= rm.maxInflight {
+		return nil, ErrTooManyInflightRequests
+	}
+
+	rm.nextID++
+	p := &pendingRequest{
+		id:         rm.nextID,
+		msgType:    msgType,
+		data:       EncodeRequestData(rm.nextID, payload),
+		timeout:    timeout,
+		peerID:     peerID,
+		tried:      map[string]bool{peerID: true},
+		responseCh: make(chan *Response, 1),
+	}
+	if err := rm.ns.SendMessageToPeer(msgType, p.data, MessagePriorityNormal, peerID); err != nil {
+		return nil, err
+	}
+
+	rm.pending[p.id] = p
+	rm.inflight[peerID]++
+	p.timer = time.AfterFunc(timeout, func() { rm.onTimeout(p.id) })
+	return p.responseCh, nil
+}
+
+// SendResponse sends the response of request id to peer.
+func (rm *RequestManager) SendResponse(peerID string, msgType string, id uint64, payload []byte) error {
+	return rm.ns.SendMessageToPeer(msgType, EncodeRequestData(id, payload), MessagePriorityNormal, peerID)
+}
+
+func (rm *RequestManager) onTimeout(id uint64) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	p, ok := rm.pending[id]
+	if !ok {
+		return
+	}
+	rm.release(p.peerID)
+
+	if p.retries < rm.retries {
+		peers := rm.ns.SendMessageToPeers(p.msgType, p.data, MessagePriorityNormal, &untriedPeerFilter{tried: p.tried})
+		if len(peers) > 0 {
+			logging.VLog().WithFields(logrus.Fields{
+				"id":      id,
+				"msgType": p.msgType,
+				"from":    p.peerID,
+				"to":      peers[0],
+			}).Debug("Request timeout, retry another peer.")
+
+			p.retries++
+			p.peerID = peers[0]
+			p.tried[p.peerID] = true
+			rm.inflight[p.peerID]++
+			p.timer.Reset(p.timeout)
+			return
+		}
+	}
+
+	delete(rm.pending, id)
+	p.responseCh <- &Response{PeerID: p.peerID, Err: ErrRequestTimeout}
+}
+
+func (rm *RequestManager) onResponse(msg Message) {
+	id, payload, ok := ParseRequestData(msg.Data())
+	if !ok {
+		// response without request id, for other subscribers.
+		return
+	}
+
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	p, ok := rm.pending[id]
+	// the late response of a peer asked before retry is still accepted.
+	if !ok || !p.tried[msg.MessageFrom()] || rm.responseTypes[p.msgType] != msg.MessageType() {
+		atomic.AddUint64(&rm.unmatchedResponses, 1)
+		return
+	}
+
+	p.timer.Stop()
+	rm.release(p.peerID)
+	delete(rm.pending, id)
+	p.responseCh <- &Response{PeerID: msg.MessageFrom(), Data: payload}
+}
+
+func (rm *RequestManager) release(peerID string) {
+	rm.inflight[peerID]--
+	if rm.inflight[peerID] <= 0 {
+		delete(rm.inflight, peerID)
+	}
+}
+
+// untriedPeerFilter selects a random peer not tried yet.
+type untriedPeerFilter struct {
+	tried map[string]bool
+}
+
+// Filter implements PeerFilterAlgorithm interface
+func (filter *untriedPeerFilter) Filter(peers PeersSlice) PeersSlice {
+	candidates := make(PeersSlice, 0, len(peers))
+	for _, v := range peers {
+		if !filter.tried[peerIDOf(v)] {
+			candidates = append(candidates, v)
+		}
+	}
+	if len(candidates) == 0 {
+		return candidates
+	}
+	selection := rand.Intn(len(candidates))
+	return candidates[selection : selection+1]
+}
+
+// peerIDOf returns the id of the peer in PeersSlice.
+func peerIDOf(v interface{}) string {
+	switch peer := v.(type) {
+	case *Stream:
+		return peer.pid.Pretty()
+	case string:
+		return peer
+	}
+	return ""
+}
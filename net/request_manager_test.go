@@ -0,0 +1,201 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package net
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// requestTestService sends requests to the peers in memory, responses go through the dispatcher.
+type requestTestService struct {
+	Service
+	dp    *Dispatcher
+	peers []string
+	mu    sync.Mutex
+	sent  map[string][][]byte
+}
+
+func newRequestTestService(peers ...string) *requestTestService {
+	return &requestTestService{
+		dp:    NewDispatcher(),
+		peers: peers,
+		sent:  make(map[string][][]byte),
+	}
+}
+
+func (s *requestTestService) Register(subscribers ...*Subscriber) {
+	s.dp.Register(subscribers...)
+}
+
+func (s *requestTestService) SendMessageToPeer(messageName string, data []byte, priority int, peerID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sent[peerID] = append(s.sent[peerID], data)
+	return nil
+}
+
+func (s *requestTestService) SendMessageToPeers(messageName string, data []byte, priority int, filter PeerFilterAlgorithm) []string {
+	peers := make(PeersSlice, 0)
+	for _, id := range s.peers {
+		peers = append(peers, id)
+	}
+	ids := []string{}
+	for _, v := range filter.Filter(peers) {
+		id := v.(string)
+		s.SendMessageToPeer(messageName, data, priority, id)
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// respond answers the last request sent to peer.
+func (s *requestTestService) respond(peerID string, payload string) {
+	s.mu.Lock()
+	requests := s.sent[peerID]
+	s.mu.Unlock()
+	id, _, _ := ParseRequestData(requests[len(requests)-1])
+	s.dp.PutMessage(NewBaseMessage("headers", peerID, EncodeRequestData(id, []byte(payload))))
+}
+
+func (s *requestTestService) sentCount(peerID string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.sent[peerID])
+}
+
+func receiveResponse(t *testing.T, ch <-chan *Response) *Response {
+	select {
+	case resp := <-ch:
+		return resp
+	case <-time.After(time.Second):
+		t.Fatal("timeout to receive response")
+	}
+	return nil
+}
+
+func startRequestManager(ns *requestTestService) (*RequestManager, chan Message) {
+	rm := NewRequestManager(ns)
+	rm.Handle("getheaders", "headers")
+	unsolicited := make(chan Message, 10)
+	ns.Register(NewSubscriber("other", []string{"headers"}, WithMessageChan(unsolicited)))
+	ns.dp.Start()
+	rm.Start()
+	return rm, unsolicited
+}
+
+func TestRequestManagerResponse(t *testing.T) {
+	ns := newRequestTestService("a", "b")
+	rm, unsolicited := startRequestManager(ns)
+	defer ns.dp.Stop()
+	defer rm.Stop()
+
+	ch, err := rm.SendRequest("a", "getheaders", []byte("req"), time.Second)
+	assert.Nil(t, err)
+	id, payload, ok := ParseRequestData(ns.sent["a"][0])
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1), id)
+	assert.Equal(t, []byte("req"), payload)
+
+	ns.respond("a", "resp")
+	resp := receiveResponse(t, ch)
+	assert.Nil(t, resp.Err)
+	assert.Equal(t, "a", resp.PeerID)
+	assert.Equal(t, []byte("resp"), resp.Data)
+
+	// other subscribers get the response too.
+	receiveMessage(t, unsolicited)
+
+	// duplicate response is dropped.
+	ns.respond("a", "again")
+	receiveMessage(t, unsolicited)
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, uint64(1), rm.UnmatchedResponses())
+	select {
+	case <-ch:
+		t.Fatal("duplicate response resolved the request")
+	default:
+	}
+
+	_, err = rm.SendRequest("a", "unknown", nil, time.Second)
+	assert.Equal(t, ErrUnknownRequestType, err)
+}
+
+func TestRequestManagerTimeout(t *testing.T) {
+	ns := newRequestTestService("a")
+	rm, _ := startRequestManager(ns)
+	defer ns.dp.Stop()
+	defer rm.Stop()
+
+	// no other peer to retry.
+	ch, err := rm.SendRequest("a", "getheaders", nil, 50*time.Millisecond)
+	assert.Nil(t, err)
+	resp := receiveResponse(t, ch)
+	assert.Equal(t, ErrRequestTimeout, resp.Err)
+	assert.Equal(t, 1, ns.sentCount("a"))
+}
+
+func TestRequestManagerLateResponseAfterRetry(t *testing.T) {
+	ns := newRequestTestService("a", "b")
+	rm, _ := startRequestManager(ns)
+	defer ns.dp.Stop()
+	defer rm.Stop()
+
+	ch, err := rm.SendRequest("a", "getheaders", nil, 200*time.Millisecond)
+	assert.Nil(t, err)
+
+	// retried against b after timeout.
+	time.Sleep(250 * time.Millisecond)
+	assert.Equal(t, 1, ns.sentCount("b"))
+
+	// the late response of a still resolves the request, the one of b is dropped.
+	ns.respond("a", "late")
+	resp := receiveResponse(t, ch)
+	assert.Nil(t, resp.Err)
+	assert.Equal(t, "a", resp.PeerID)
+	assert.Equal(t, []byte("late"), resp.Data)
+
+	ns.respond("b", "retried")
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, uint64(1), rm.UnmatchedResponses())
+}
+
+func TestRequestManagerInflightLimit(t *testing.T) {
+	ns := newRequestTestService("a", "b")
+	rm, _ := startRequestManager(ns)
+	rm.SetLimits(1, 0)
+	defer ns.dp.Stop()
+	defer rm.Stop()
+
+	_, err := rm.SendRequest("a", "getheaders", nil, time.Second)
+	assert.Nil(t, err)
+	_, err = rm.SendRequest("a", "getheaders", nil, time.Second)
+	assert.Equal(t, ErrTooManyInflightRequests, err)
+	_, err = rm.SendRequest("b", "getheaders", nil, time.Second)
+	assert.Nil(t, err)
+
+	// released after the response.
+	ns.respond("a", "resp")
+	time.Sleep(10 * time.Millisecond)
+	_, err = rm.SendRequest("a", "getheaders", nil, time.Second)
+	assert.Nil(t, err)
+}
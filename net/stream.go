@@ -23,16 +23,18 @@ import (
 	"fmt"
 	"hash/crc32"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/golang/snappy"
 
+	netpb "github.com/alexlisong/go-nebulas/net/pb"
+	"github.com/alexlisong/go-nebulas/util/byteutils"
+	"github.com/alexlisong/go-nebulas/util/logging"
 	"github.com/gogo/protobuf/proto"
 	libnet "github.com/libp2p/go-libp2p-net"
 	peer "github.com/libp2p/go-libp2p-peer"
 	ma "github.com/multiformats/go-multiaddr"
-	netpb "github.com/alexlisong/go-nebulas/net/pb"
-	"github.com/alexlisong/go-nebulas/util/logging"
 	"github.com/sirupsen/logrus"
 )
 
@@ -81,6 +83,10 @@ type Stream struct {
 	latestWriteAt             int64
 	msgCount                  map[string]int
 	compressFlag              *sync.Map
+	// compression codec negotiated in handshake, -1 for old peers.
+	compression int32
+	// capabilities of peer advertised in handshake, none for old peers.
+	capabilities uint64
 }
 
 // NewStream return a new Stream
@@ -111,6 +117,7 @@ func newStreamInstance(pid peer.ID, addr ma.Multiaddr, stream libnet.Stream, nod
 		latestWriteAt:             0,
 		msgCount:                  make(map[string]int),
 		compressFlag:              new(sync.Map),
+		compression:               -1,
 	}
 }
 
@@ -175,12 +182,20 @@ func (s *Stream) SendProtoMessage(messageName string, pb proto.Message, priority
 
 // SendMessage send msg to buffer
 func (s *Stream) SendMessage(messageName string, data []byte, priority int) error {
+	if !s.Capabilities().Supports(messageName) {
+		logging.VLog().WithFields(logrus.Fields{
+			"messageName":  messageName,
+			"capabilities": s.Capabilities(),
+			"stream":       s.String(),
+		}).Debug("Peer doesn't support the message, skip it.")
+		return ErrUnsupportedMessage
+	}
+
 	message, err := NewNebMessage(s, DefaultReserved, 0, messageName, data)
 	if err != nil {
 		return err
 	}
 
-
 	// send to pool.
 	message.FlagSendMessageAt()
 
@@ -243,7 +258,6 @@ func (s *Stream) Write(data []byte) error {
 	}
 	s.latestWriteAt = time.Now().Unix()
 
-
 	return nil
 }
 
@@ -431,21 +445,11 @@ func (s *Stream) writeLoop() {
 
 func (s *Stream) handleMessage(message *NebMessage) error {
 	messageName := message.MessageName()
-	compressFlag := message.Reserved()[0] & 0x80
-	s.compressFlag.Store(s.pid.Pretty(), compressFlag)
 	s.msgCount[messageName]++
 
-	// Network data compression compatible with old clients.
-	// uncompress message data.
-	var data = message.Data()
-	if messageName != HELLO {
-		if compressFlag > 0 {
-			var err error
-			data, err = snappy.Decode(nil, message.Data())
-			if err != nil {
-				return ErrUncompressMessageFailed
-			}
-		}
+	data, err := s.messageData(message)
+	if err != nil {
+		return err
 	}
 
 	switch messageName {
@@ -477,6 +481,55 @@ func (s *Stream) handleMessage(message *NebMessage) error {
 	return nil
 }
 
+// messageData returns the uncompressed data of message.
+func (s *Stream) messageData(message *NebMessage) ([]byte, error) {
+	reserved := message.Reserved()
+	compressFlag := reserved[0] & reservedSnappyFlag
+	s.compressFlag.Store(s.pid.Pretty(), compressFlag)
+
+	if message.MessageName() == HELLO {
+		return message.Data(), nil
+	}
+
+	// the codec is recorded by new clients.
+	if reserved[0]&reservedCodecFlag > 0 {
+		data, err := decompress(reserved[1], message.Data())
+		if err != nil {
+			return nil, ErrUncompressMessageFailed
+		}
+		return data, nil
+	}
+
+	// Network data compression compatible with old clients.
+	if compressFlag > 0 {
+		data, err := snappy.Decode(nil, message.Data())
+		if err != nil {
+			return nil, ErrUncompressMessageFailed
+		}
+		return data, nil
+	}
+	return message.Data(), nil
+}
+
+// Capabilities returns the capabilities of peer.
+func (s *Stream) Capabilities() Capability {
+	return Capability(atomic.LoadUint64(&s.capabilities))
+}
+
+// negotiatedCompression returns the codec negotiated with peer, false if the peer doesn't negotiate.
+func (s *Stream) negotiatedCompression() (byte, bool) {
+	c := atomic.LoadInt32(&s.compression)
+	return byte(c), c >= 0
+}
+
+// negotiateCompression picks the codec from the ones of peer, old peers advertise nothing.
+func (s *Stream) negotiateCompression(peerCompressions []string) {
+	if len(peerCompressions) == 0 {
+		return
+	}
+	atomic.StoreInt32(&s.compression, int32(negotiateCompression(peerCompressions)))
+}
+
 // Close close the stream
 func (s *Stream) close(reason error) {
 	// Add lock & close flag to prevent multi call.
@@ -522,8 +575,13 @@ func (s *Stream) onBye(message *NebMessage) error {
 // Hello say hello in the stream
 func (s *Stream) Hello() error {
 	msg := &netpb.Hello{
-		NodeId:        s.node.id.String(),
-		ClientVersion: ClientVersion,
+		NodeId:          s.node.id.String(),
+		ClientVersion:   ClientVersion,
+		Compressions:    SupportedCompressions(),
+		ProtocolVersion: NebProtocolVersion,
+		ChainId:         s.node.config.ChainID,
+		GenesisHash:     s.node.GenesisHash(),
+		Capabilities:    uint64(LocalCapabilities),
 	}
 	return s.WriteProtoMessage(HELLO, msg)
 }
@@ -545,6 +603,19 @@ func (s *Stream) onHello(message *NebMessage, data []byte) error {
 		return ErrShouldCloseConnectionAndExitLoop
 	}
 
+	if err := verifyHandshake(msg.ProtocolVersion, msg.ChainId, msg.GenesisHash, s.node.config.ChainID, s.node.GenesisHash()); err != nil {
+		logging.VLog().WithFields(logrus.Fields{
+			"pid":                s.pid.Pretty(),
+			"address":            s.addr,
+			"hello.chain_id":     msg.ChainId,
+			"hello.genesis_hash": byteutils.Hex(msg.GenesisHash),
+			"reason":             err,
+		}).Warn("Peer is on another chain, disconnect it.")
+		return ErrShouldCloseConnectionAndExitLoop
+	}
+	atomic.StoreUint64(&s.capabilities, msg.Capabilities)
+	s.negotiateCompression(msg.Compressions)
+
 	// add to route table.
 	s.node.routeTable.AddPeerStream(s)
 
@@ -558,8 +629,13 @@ func (s *Stream) onHello(message *NebMessage, data []byte) error {
 func (s *Stream) Ok() error {
 	// send OK.
 	resp := &netpb.OK{
-		NodeId:        s.node.id.String(),
-		ClientVersion: ClientVersion,
+		NodeId:          s.node.id.String(),
+		ClientVersion:   ClientVersion,
+		Compressions:    SupportedCompressions(),
+		ProtocolVersion: NebProtocolVersion,
+		ChainId:         s.node.config.ChainID,
+		GenesisHash:     s.node.GenesisHash(),
+		Capabilities:    uint64(LocalCapabilities),
 	}
 
 	return s.WriteProtoMessage(OK, resp)
@@ -582,6 +658,19 @@ func (s *Stream) onOk(message *NebMessage, data []byte) error {
 		return ErrShouldCloseConnectionAndExitLoop
 	}
 
+	if err := verifyHandshake(msg.ProtocolVersion, msg.ChainId, msg.GenesisHash, s.node.config.ChainID, s.node.GenesisHash()); err != nil {
+		logging.VLog().WithFields(logrus.Fields{
+			"pid":             s.pid.Pretty(),
+			"address":         s.addr,
+			"ok.chain_id":     msg.ChainId,
+			"ok.genesis_hash": byteutils.Hex(msg.GenesisHash),
+			"reason":          err,
+		}).Warn("Peer is on another chain, disconnect it.")
+		return ErrShouldCloseConnectionAndExitLoop
+	}
+	atomic.StoreUint64(&s.capabilities, msg.Capabilities)
+	s.negotiateCompression(msg.Compressions)
+
 	// add to route table.
 	s.node.routeTable.AddPeerStream(s)
 
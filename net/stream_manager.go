@@ -22,9 +22,11 @@ import (
 	"errors"
 	"fmt"
 	"hash/crc32"
+	"path"
 	"sort"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -47,6 +49,7 @@ var (
 	ErrExceedMaxStreamNum = errors.New("too many streams connected")
 	ErrElimination        = errors.New("eliminated for low value")
 	ErrDeprecatedStream   = errors.New("deprecated stream")
+	ErrRateLimited        = errors.New("exceed rate limit repeatedly")
 )
 
 // StreamManager manages all streams
@@ -57,6 +60,11 @@ type StreamManager struct {
 	activePeersCount  int32
 	maxStreamNum      int32
 	reservedStreamNum int32
+	reputation        *peerReputation
+
+	// dispatcher tracks the peers known to have the messages, nil for no tracking.
+	dispatcher       *Dispatcher
+	suppressedRelays uint64
 }
 
 // NewStreamManager return a new stream manager
@@ -67,7 +75,16 @@ func NewStreamManager(config *Config) *StreamManager {
 		activePeersCount:  0,
 		maxStreamNum:      config.StreamLimits,
 		reservedStreamNum: config.ReservedStreamLimits,
+		reputation:        newPeerReputation(banListFilePath(config)),
+	}
+}
+
+// banListFilePath return the path of ban list file, the bans are not persisted without routing table dir.
+func banListFilePath(config *Config) string {
+	if config.RoutingTableDir == "" {
+		return ""
 	}
+	return path.Join(config.RoutingTableDir, PeerBanListFileName)
 }
 
 // Count return active peers count in the stream manager
@@ -79,6 +96,7 @@ func (sm *StreamManager) Count() int32 {
 func (sm *StreamManager) Start() {
 	logging.CLog().Info("Starting NebService StreamManager...")
 
+	sm.reputation.load()
 	go sm.loop()
 }
 
@@ -108,6 +126,17 @@ func (sm *StreamManager) AddStream(stream *Stream) {
 		return
 	}
 
+	if sm.reputation.banned(stream.pid.Pretty(), time.Now()) {
+		logging.VLog().WithFields(logrus.Fields{
+			"pid": stream.pid.Pretty(),
+		}).Debug("Refused the stream of banned peer.")
+
+		if stream.stream != nil {
+			stream.stream.Close()
+		}
+		return
+	}
+
 	// check & close old stream
 	if v, ok := sm.allStreams.Load(stream.pid.Pretty()); ok {
 		old, _ := v.(*Stream)
@@ -212,16 +241,7 @@ func (sm *StreamManager) BroadcastMessage(messageName string, messageContent Ser
 	if err != nil {
 		return
 	}
-
-	dataCheckSum := crc32.ChecksumIEEE(data)
-
-	sm.allStreams.Range(func(key, value interface{}) bool {
-		stream := value.(*Stream)
-		if stream.IsHandshakeSucceed() && !HasRecvMessage(stream, dataCheckSum) {
-			stream.SendMessage(messageName, data, priority)
-		}
-		return true
-	})
+	sm.gossip(messageName, data, priority)
 }
 
 // RelayMessage relay the message
@@ -231,16 +251,39 @@ func (sm *StreamManager) RelayMessage(messageName string, messageContent Seriali
 	if err != nil {
 		return
 	}
+	sm.gossip(messageName, data, priority)
+}
 
+// gossip sends the message to the peers except those known to have it, the origins and the former receivers.
+func (sm *StreamManager) gossip(messageName string, data []byte, priority int) {
 	dataCheckSum := crc32.ChecksumIEEE(data)
+	hash := messageHash(data)
 
+	sent := make([]string, 0)
 	sm.allStreams.Range(func(key, value interface{}) bool {
 		stream := value.(*Stream)
-		if stream.IsHandshakeSucceed() && !HasRecvMessage(stream, dataCheckSum) {
-			stream.SendMessage(messageName, data, priority)
+		if !stream.IsHandshakeSucceed() {
+			return true
+		}
+		pid := stream.pid.Pretty()
+		if HasRecvMessage(stream, dataCheckSum) || (sm.dispatcher != nil && sm.dispatcher.KnownBy(hash, pid)) {
+			atomic.AddUint64(&sm.suppressedRelays, 1)
+			return true
+		}
+		if err := stream.SendMessage(messageName, data, priority); err == nil {
+			sent = append(sent, pid)
 		}
 		return true
 	})
+
+	if sm.dispatcher != nil && len(sent) > 0 {
+		sm.dispatcher.MarkKnown(hash, sent...)
+	}
+}
+
+// SuppressedRelayCount return the count of messages not relayed to the peers known to have them.
+func (sm *StreamManager) SuppressedRelayCount() uint64 {
+	return atomic.LoadUint64(&sm.suppressedRelays)
 }
 
 // SendMessageToPeers send the message to the peers filtered by the filter algorithm
@@ -276,6 +319,40 @@ func (sm *StreamManager) CloseStream(peerID string, reason error) {
 	}
 }
 
+// OnRateLimited disconnects the peer exceeded the rate limits repeatedly.
+func (sm *StreamManager) OnRateLimited(peerID string, msgType string, violations uint64) {
+	if violations%MaxRateLimitViolations != 0 {
+		return
+	}
+	logging.VLog().WithFields(logrus.Fields{
+		"pid":        peerID,
+		"msgType":    msgType,
+		"violations": violations,
+	}).Warn("Peer exceeds rate limit repeatedly, close it.")
+	sm.CloseStream(peerID, ErrRateLimited)
+}
+
+// ReportPeer applies the verdict of a message to the score of its origin peer,
+// the peer is disconnected once it's banned.
+func (sm *StreamManager) ReportPeer(peerID string, verdict Verdict) {
+	if peerID == "" {
+		return
+	}
+	if sm.reputation.report(peerID, verdict, time.Now()) {
+		sm.CloseStream(peerID, ErrPeerBanned)
+	}
+}
+
+// IsBanned return if the peer is banned.
+func (sm *StreamManager) IsBanned(peerID string) bool {
+	return sm.reputation.banned(peerID, time.Now())
+}
+
+// PeerScores return the scores of the peers, the lowest first.
+func (sm *StreamManager) PeerScores() []*PeerScore {
+	return sm.reputation.scores(time.Now())
+}
+
 // cleanup eliminating low value streams if reaching the limit
 func (sm *StreamManager) cleanup() {
 
@@ -310,7 +387,7 @@ func (sm *StreamManager) cleanup() {
 			v, _ := stream.node.netService.dispatcher.subscribersMap.Load(t)
 			if m, ok := v.(*sync.Map); ok {
 				m.Range(func(key, value interface{}) bool {
-					msgWeight[t] = key.(*Subscriber).MessageWeight()
+					msgWeight[t] = key.(*Subscriber).MessageWeight(t)
 					return false
 				})
 			}
@@ -9,8 +9,11 @@ import (
 	"testing"
 	"time"
 
+	netpb "github.com/alexlisong/go-nebulas/net/pb"
+	"github.com/gogo/protobuf/proto"
 	peer "github.com/libp2p/go-libp2p-peer"
 	ma "github.com/multiformats/go-multiaddr"
+	"github.com/stretchr/testify/assert"
 )
 
 const (
@@ -165,3 +168,93 @@ func orderedString(mc *map[string]int) string {
 	}
 	return buffer.String()
 }
+
+type mockSerializable struct {
+	data string
+}
+
+func (m *mockSerializable) ToProto() (proto.Message, error) {
+	return &netpb.Hello{NodeId: m.data}, nil
+}
+
+func (m *mockSerializable) FromProto(msg proto.Message) error {
+	m.data = msg.(*netpb.Hello).NodeId
+	return nil
+}
+
+// gossipNode is a node of the loop test, its streams are connected to the other nodes.
+type gossipNode struct {
+	id         peer.ID
+	dispatcher *Dispatcher
+	sm         *StreamManager
+}
+
+func newGossipNode(id string) *gossipNode {
+	n := &gossipNode{
+		id:         peer.ID(id),
+		dispatcher: NewDispatcher(),
+		sm:         NewStreamManager(NewConfigFromDefaults()),
+	}
+	n.sm.dispatcher = n.dispatcher
+	n.dispatcher.Register(NewSubscriber(n, []string{"newblock"}, WithMessageChan(make(chan Message, 10)), WithDoFilter()))
+	return n
+}
+
+func (n *gossipNode) connect(other *gossipNode) {
+	s := newStreamInstance(other.id, nil, nil, &Node{config: NewConfigFromDefaults()})
+	s.status = streamStatusHandshakeSucceed
+	n.sm.allStreams.Store(other.id.Pretty(), s)
+}
+
+// deliver moves the messages queued to other into its dispatcher, return the count of them.
+func (n *gossipNode) deliver(other *gossipNode) int {
+	v, _ := n.sm.allStreams.Load(other.id.Pretty())
+	s := v.(*Stream)
+
+	count := 0
+	for len(s.normalPriorityMessageChan) > 0 {
+		msg := <-s.normalPriorityMessageChan
+		data, err := s.messageData(msg)
+		if err != nil {
+			panic(err)
+		}
+		other.dispatcher.PutMessage(NewBaseMessage(msg.MessageName(), n.id.Pretty(), data))
+		count++
+	}
+	return count
+}
+
+func TestRelayEcho(t *testing.T) {
+	a, b := newGossipNode("a"), newGossipNode("b")
+	a.connect(b)
+	b.connect(a)
+	block := &mockSerializable{data: "block"}
+
+	a.sm.BroadcastMessage("newblock", block, MessagePriorityNormal)
+	assert.Equal(t, 1, a.deliver(b))
+
+	// b relays the block, a is the origin.
+	b.sm.RelayMessage("newblock", block, MessagePriorityNormal)
+	assert.Equal(t, 0, b.deliver(a))
+	assert.Equal(t, uint64(1), b.sm.SuppressedRelayCount())
+
+	// a relays the block again, b is the receiver.
+	a.sm.RelayMessage("newblock", block, MessagePriorityNormal)
+	assert.Equal(t, 0, a.deliver(b))
+	assert.Equal(t, uint64(1), a.sm.SuppressedRelayCount())
+
+	// a new peer of b still receives it.
+	c := newGossipNode("c")
+	b.connect(c)
+	b.sm.RelayMessage("newblock", block, MessagePriorityNormal)
+	assert.Equal(t, 1, b.deliver(c))
+	assert.Equal(t, 0, b.deliver(a))
+
+	// the untracked node echoes the block back.
+	a.sm.dispatcher, b.sm.dispatcher = nil, nil
+	other := &mockSerializable{data: "other block"}
+	a.sm.BroadcastMessage("newblock", other, MessagePriorityNormal)
+	assert.Equal(t, 1, a.deliver(b))
+	b.sm.RelayMessage("newblock", other, MessagePriorityNormal)
+	assert.Equal(t, 1, b.deliver(a))
+}
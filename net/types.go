@@ -21,6 +21,8 @@ package net
 import (
 	"errors"
 	"fmt"
+	"sync/atomic"
+	"time"
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/alexlisong/go-nebulas/crypto/hash"
@@ -40,6 +42,14 @@ const (
 	ChunkHeadersResponse = "chunks"    // ChainChunks
 	ChunkDataRequest     = "getchunk"  // ChainGetChunk
 	ChunkDataResponse    = "chunkdata" // ChainChunkData
+
+	SnapshotRequest       = "getsnapshot" // SnapshotSync
+	SnapshotResponse      = "snapshot"    // SnapshotBlock
+	SnapshotNodesRequest  = "getnodes"    // SnapshotNodeHashes
+	SnapshotNodesResponse = "nodes"       // SnapshotNodes
+
+	HeadersRequest  = "getheaders" // HeadersRequest
+	HeadersResponse = "headers"    // Headers, handled by light clients
 )
 
 // Sync Errors
@@ -50,6 +60,9 @@ var (
 // MessageType a string for message type.
 type MessageType string
 
+// MessageTypeWildcard subscribers of it receive messages of every type.
+const MessageTypeWildcard = "*"
+
 // Message interface for message.
 type Message interface {
 	MessageType() string
@@ -91,6 +104,10 @@ type Service interface {
 
 	ClosePeer(peerID string, reason error)
 
+	PeerCapabilities(peerID string) (Capability, bool)
+
+	ReportPeer(peerID string, verdict Verdict)
+
 	BroadcastNetworkID([]byte)
 }
 
@@ -107,31 +124,153 @@ const (
 	MessageWeightChainChunkData
 )
 
+// DispatchPolicy decides what dispatcher does when the msgChan of a subscriber is full.
+type DispatchPolicy int
+
+// Dispatch Policy.
+const (
+	// DispatchDropNewest drops the incoming message.
+	DispatchDropNewest DispatchPolicy = iota
+	// DispatchDropOldest drops the oldest message in msgChan to make room, like a ring buffer.
+	DispatchDropOldest
+	// DispatchBlock waits for room up to the block timeout, then drops the incoming message.
+	// The wait is in a goroutine of subscriber with bufferSize pending messages, other subscribers are not delayed.
+	DispatchBlock
+)
+
+// DefaultDispatchBlockTimeout is the default max wait of DispatchBlock subscribers.
+const DefaultDispatchBlockTimeout = 5 * time.Second
+
+// DefaultSubscriberBufferSize is the default capacity of the msgChan created by subscriber.
+const DefaultSubscriberBufferSize = 1024
+
 // Subscriber subscriber.
 type Subscriber struct {
 	// id usually the owner/creator, used for troubleshooting .
 	id interface{}
 
+	// name human-readable name of the subscriber in logs, the type of id by default.
+	name string
+
 	// msgChan chan for subscribed message.
-	msgChan chan Message
+	msgChan    chan Message
+	bufferSize int
 
-	// msgType message type to subscribe
-	msgType string
+	// msgTypes message types to subscribe
+	msgTypes []string
 
-	// msgWeight weight of msgType
-	msgWeight MessageWeight
+	// msgWeights weight of msgTypes, MessageWeightZero by default.
+	msgWeights map[string]MessageWeight
 
-	// doFilter dup message
-	doFilter bool
+	// doFilter dup message of the types
+	doFilter map[string]bool
+
+	// policy what dispatcher does when msgChan is full.
+	policy       DispatchPolicy
+	blockTimeout time.Duration
+
+	// dropped count of messages dropped by dispatcher.
+	dropped uint64
+
+	// priority dispatch priority of msgTypes, MessagePriorityNormal by default.
+	priority int
+}
+
+// SubscriberOption configures a Subscriber in NewSubscriber.
+type SubscriberOption func(*Subscriber)
+
+// WithMessageChan delivers the messages to msgChan, which may be shared by subscribers.
+func WithMessageChan(msgChan chan Message) SubscriberOption {
+	return func(s *Subscriber) {
+		s.msgChan = msgChan
+	}
 }
 
-// func NewSubscriber(id interface{}, msgChan chan Message, doFilter bool, msgTypes ...string) *Subscriber {
-// 	return &Subscriber{id, msgChan, msgTypes, doFilter}
-// }
+// WithBufferSize set the capacity of msgChan created by subscriber, it's ignored WithMessageChan.
+func WithBufferSize(size int) SubscriberOption {
+	return func(s *Subscriber) {
+		if size > 0 {
+			s.bufferSize = size
+		}
+	}
+}
+
+// WithDoFilter filters the dup messages of the types, all subscribed types if none given.
+func WithDoFilter(msgTypes ...string) SubscriberOption {
+	return func(s *Subscriber) {
+		if len(msgTypes) == 0 {
+			msgTypes = s.msgTypes
+		}
+		for _, t := range msgTypes {
+			s.doFilter[t] = true
+		}
+	}
+}
+
+// WithMessageWeight set the weight of message type, streams are valued by the weighted messages.
+func WithMessageWeight(msgType string, weight MessageWeight) SubscriberOption {
+	return func(s *Subscriber) {
+		s.msgWeights[msgType] = weight
+	}
+}
 
-// NewSubscriber return new Subscriber instance.
-func NewSubscriber(id interface{}, msgChan chan Message, doFilter bool, msgType string, weight MessageWeight) *Subscriber {
-	return &Subscriber{id, msgChan, msgType, weight, doFilter}
+// WithPriority set the dispatch priority of subscriber.
+func WithPriority(priority int) SubscriberOption {
+	return func(s *Subscriber) {
+		if priority < MessagePriorityHigh {
+			priority = MessagePriorityHigh
+		}
+		if priority > MessagePriorityLow {
+			priority = MessagePriorityLow
+		}
+		s.priority = priority
+	}
+}
+
+// WithDispatchPolicy set what dispatcher does when msgChan is full.
+func WithDispatchPolicy(policy DispatchPolicy) SubscriberOption {
+	return func(s *Subscriber) {
+		s.policy = policy
+	}
+}
+
+// WithBlockTimeout set the max wait of DispatchBlock policy.
+func WithBlockTimeout(timeout time.Duration) SubscriberOption {
+	return func(s *Subscriber) {
+		if timeout > 0 {
+			s.blockTimeout = timeout
+		}
+	}
+}
+
+// WithName set the name of subscriber in logs, e.g. who is slow to consume messages.
+func WithName(name string) SubscriberOption {
+	return func(s *Subscriber) {
+		s.name = name
+	}
+}
+
+// NewSubscriber return new Subscriber instance of the message types.
+// By default messages are not filtered, and messages to the full msgChan are dropped.
+func NewSubscriber(id interface{}, msgTypes []string, opts ...SubscriberOption) *Subscriber {
+	s := &Subscriber{
+		id:           id,
+		name:         fmt.Sprintf("%T", id),
+		bufferSize:   DefaultSubscriberBufferSize,
+		msgTypes:     msgTypes,
+		msgWeights:   make(map[string]MessageWeight),
+		doFilter:     make(map[string]bool),
+		priority:     MessagePriorityNormal,
+		policy:       DispatchDropNewest,
+		blockTimeout: DefaultDispatchBlockTimeout,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.msgChan == nil {
+		s.msgChan = make(chan Message, s.bufferSize)
+	}
+	return s
 }
 
 // ID return id.
@@ -139,9 +278,14 @@ func (s *Subscriber) ID() interface{} {
 	return s.id
 }
 
-// MessageType return msgTypes.
-func (s *Subscriber) MessageType() string {
-	return s.msgType
+// Name return name.
+func (s *Subscriber) Name() string {
+	return s.name
+}
+
+// MessageTypes return msgTypes.
+func (s *Subscriber) MessageTypes() []string {
+	return s.msgTypes
 }
 
 // MessageChan return msgChan.
@@ -150,13 +294,37 @@ func (s *Subscriber) MessageChan() chan Message {
 }
 
 // MessageWeight return weight of msgType
-func (s *Subscriber) MessageWeight() MessageWeight {
-	return s.msgWeight
+func (s *Subscriber) MessageWeight(msgType string) MessageWeight {
+	return s.msgWeights[msgType]
+}
+
+// DoFilter return whether dup messages of msgType are filtered
+func (s *Subscriber) DoFilter(msgType string) bool {
+	return s.doFilter[msgType]
+}
+
+// Blocking return whether dispatcher waits for the full msgChan
+func (s *Subscriber) Blocking() bool {
+	return s.policy == DispatchBlock
 }
 
-// DoFilter return doFilter
-func (s *Subscriber) DoFilter() bool {
-	return s.doFilter
+// DispatchPolicy return policy
+func (s *Subscriber) DispatchPolicy() DispatchPolicy {
+	return s.policy
+}
+
+// DroppedMessages return the count of messages dropped by dispatcher
+func (s *Subscriber) DroppedMessages() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+func (s *Subscriber) drop() {
+	atomic.AddUint64(&s.dropped, 1)
+}
+
+// Priority return priority
+func (s *Subscriber) Priority() int {
+	return s.priority
 }
 
 // BaseMessage base message
@@ -188,7 +356,11 @@ func (msg *BaseMessage) Data() []byte {
 
 // Hash return the message hash
 func (msg *BaseMessage) Hash() string {
-	return byteutils.Hex(hash.Sha3256(msg.data))
+	return messageHash(msg.data)
+}
+
+func messageHash(data []byte) string {
+	return byteutils.Hex(hash.Sha3256(data))
 }
 
 // String get the message to string
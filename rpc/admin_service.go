@@ -19,13 +19,16 @@
 package rpc
 
 import (
+	"errors"
+	"fmt"
+	"sort"
 	"time"
 
-	"github.com/gogo/protobuf/proto"
 	"github.com/alexlisong/go-nebulas/core"
 	"github.com/alexlisong/go-nebulas/crypto/keystore"
 	"github.com/alexlisong/go-nebulas/net"
 	"github.com/alexlisong/go-nebulas/rpc/pb"
+	"github.com/alexlisong/go-nebulas/util"
 	"golang.org/x/net/context"
 )
 
@@ -142,11 +145,29 @@ func (s *AdminService) SignTransactionWithPassphrase(ctx context.Context, req *r
 	if err := neb.AccountManager().SignTransactionWithPassphrase(tx.From(), tx, []byte(req.Passphrase)); err != nil {
 		return nil, err
 	}
-	pbMsg, err := tx.ToProto()
+	data, err := tx.ToBytes()
 	if err != nil {
 		return nil, err
 	}
-	data, err := proto.Marshal(pbMsg)
+
+	return &rpcpb.SignTransactionPassphraseResponse{Data: data}, nil
+}
+
+// SignTransactionAsPayer co-sign the sender signed transaction with the payer passphrase
+func (s *AdminService) SignTransactionAsPayer(ctx context.Context, req *rpcpb.SignTransactionAsPayerRequest) (*rpcpb.SignTransactionPassphraseResponse, error) {
+
+	neb := s.server.Neblet()
+	tx := new(core.Transaction)
+	if err := tx.FromBytes(req.Data); err != nil {
+		return nil, err
+	}
+	if tx.Payer() == nil {
+		return nil, core.ErrTransactionWithoutPayer
+	}
+	if err := neb.AccountManager().SignTransactionAsPayerWithPassphrase(tx.Payer(), tx, []byte(req.Passphrase)); err != nil {
+		return nil, err
+	}
+	data, err := tx.ToBytes()
 	if err != nil {
 		return nil, err
 	}
@@ -169,6 +190,279 @@ func (s *AdminService) SendTransactionWithPassphrase(ctx context.Context, req *r
 	return handleTransactionResponse(neb, tx)
 }
 
+// SendTransactionSafe simulate the transaction, and only sign and send it if the simulation succeeds.
+func (s *AdminService) SendTransactionSafe(ctx context.Context, req *rpcpb.SendTransactionSafeRequest) (*rpcpb.SendTransactionSafeResponse, error) {
+	neb := s.server.Neblet()
+	if req.Transaction == nil {
+		return nil, errors.New("invalid transaction")
+	}
+
+	reqTx := *req.Transaction
+	if len(reqTx.GasPrice) == 0 {
+		reqTx.GasPrice = neb.BlockChain().GasPrice().String()
+	}
+	opts := &core.SafeSendOptions{
+		AllowedErrors: req.AllowedErrors,
+		FillGasLimit:  len(reqTx.GasLimit) == 0,
+	}
+	if opts.FillGasLimit {
+		reqTx.GasLimit = core.TransactionMaxGas.String()
+	}
+	if req.Strict {
+		maxFee, err := util.NewUint128FromString(req.MaxFee)
+		if err != nil {
+			return nil, errors.New("invalid maxFee")
+		}
+		opts.MaxFee = maxFee
+	}
+	if reqTx.Nonce == 0 {
+		fromAddr, err := core.AddressParse(reqTx.From)
+		if err != nil {
+			return nil, err
+		}
+		acc, err := neb.BlockChain().TailBlock().GetAccount(fromAddr.Bytes())
+		if err != nil {
+			return nil, err
+		}
+		reqTx.Nonce = neb.BlockChain().TransactionPool().GetPendingNonce(fromAddr, acc.Nonce()) + 1
+	}
+
+	tx, err := parseTransaction(neb, &reqTx)
+	if err != nil {
+		return nil, err
+	}
+	if tx.Type() == core.TxPayloadDeployType && !tx.From().Equals(tx.To()) {
+		return nil, core.ErrContractTransactionAddressNotEqual
+	}
+
+	sign := func(tx *core.Transaction) error {
+		if len(req.Passphrase) > 0 {
+			return neb.AccountManager().SignTransactionWithPassphrase(tx.From(), tx, []byte(req.Passphrase))
+		}
+		return neb.AccountManager().SignTransaction(tx.From(), tx)
+	}
+	summary, err := neb.BlockChain().SendTransactionSafe(tx, opts, sign)
+	if err != nil {
+		if summary == nil || (err != core.ErrSimulationFailed && err != core.ErrEstimatedFeeExceedsMax) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%s: %v, gas used %s (base %s, payload %s, execution %s), estimated fee %s",
+			err, summary.Err, summary.GasUsed, summary.BaseGas, summary.PayloadGas, summary.ExecutionGas, summary.Fee)
+	}
+
+	resp := &rpcpb.SendTransactionSafeResponse{
+		Txhash:       summary.Tx.Hash().String(),
+		Result:       summary.Result,
+		GasUsed:      summary.GasUsed.String(),
+		GasBase:      summary.BaseGas.String(),
+		GasPayload:   summary.PayloadGas.String(),
+		GasExecution: summary.ExecutionGas.String(),
+		GasLimit:     summary.Tx.GasLimit().String(),
+		EstimateFee:  summary.Fee.String(),
+	}
+	if summary.Err != nil {
+		resp.ExecuteErr = summary.Err.Error()
+	}
+	if tx.Type() == core.TxPayloadDeployType {
+		addr, err := summary.Tx.ContractAddressAtHeight(neb.BlockChain().TailBlock().Height() + 1)
+		if err != nil {
+			return nil, err
+		}
+		resp.ContractAddress = addr.String()
+	}
+	return resp, nil
+}
+
+// VerifyIndexConsistency is the RPC API handler.
+func (s *AdminService) VerifyIndexConsistency(ctx context.Context, req *rpcpb.VerifyIndexConsistencyRequest) (*rpcpb.VerifyIndexConsistencyResponse, error) {
+	neb := s.server.Neblet()
+
+	report, err := core.VerifyIndexConsistency(neb.BlockChain(), req.FromHeight, req.ToHeight, req.Repair)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &rpcpb.VerifyIndexConsistencyResponse{
+		FromHeight: report.FromHeight,
+		ToHeight:   report.ToHeight,
+		Blocks:     report.Blocks,
+		Txs:        report.Txs,
+		Repaired:   uint32(report.Repaired()),
+	}
+	for _, d := range report.Discrepancies {
+		discrepancy := &rpcpb.IndexDiscrepancy{
+			Kind:     d.Kind,
+			Height:   d.Height,
+			Block:    d.Block.String(),
+			Detail:   d.Detail,
+			Repaired: d.Repaired,
+		}
+		if d.Tx != nil {
+			discrepancy.Tx = d.Tx.String()
+		}
+		resp.Discrepancies = append(resp.Discrepancies, discrepancy)
+	}
+	return resp, nil
+}
+
+// GetPoolContent is the RPC API handler.
+func (s *AdminService) GetPoolContent(ctx context.Context, req *rpcpb.PoolContentRequest) (*rpcpb.PoolContentResponse, error) {
+	neb := s.server.Neblet()
+	pool := neb.BlockChain().TransactionPool()
+
+	if len(req.Address) > 0 {
+		if _, err := core.AddressParse(req.Address); err != nil {
+			return nil, err
+		}
+	}
+
+	pending, queued := pool.PoolContent()
+	addrs := make(map[string]bool)
+	for addr := range pending {
+		addrs[addr] = true
+	}
+	for addr := range queued {
+		addrs[addr] = true
+	}
+
+	resp := &rpcpb.PoolContentResponse{}
+	for addr := range addrs {
+		if len(req.Address) > 0 && addr != req.Address {
+			continue
+		}
+		content := &rpcpb.PoolAccountContent{Address: addr}
+		for _, tx := range pending[addr] {
+			content.Pending = append(content.Pending, toPoolTransactionResponse(tx))
+		}
+		for _, tx := range queued[addr] {
+			content.Queued = append(content.Queued, toPoolTransactionResponse(tx))
+		}
+		resp.Accounts = append(resp.Accounts, content)
+	}
+	sort.Slice(resp.Accounts, func(i, j int) bool {
+		return resp.Accounts[i].Address < resp.Accounts[j].Address
+	})
+
+	stats := pool.Stats()
+	resp.Stats = &rpcpb.PoolStats{
+		Pending:  uint32(stats.Pending),
+		Queued:   uint32(stats.Queued),
+		Packing:  uint32(stats.Packing),
+		Accounts: uint32(stats.Accounts),
+	}
+	if stats.MinGasPrice != nil {
+		resp.Stats.MinGasPrice = stats.MinGasPrice.String()
+		resp.Stats.MaxGasPrice = stats.MaxGasPrice.String()
+	}
+	return resp, nil
+}
+
+func toPoolTransactionResponse(tx *core.Transaction) *rpcpb.TransactionResponse {
+	resp := &rpcpb.TransactionResponse{
+		ChainId:   tx.ChainID(),
+		Hash:      tx.Hash().String(),
+		From:      tx.From().String(),
+		To:        tx.To().String(),
+		Value:     tx.Value().String(),
+		Nonce:     tx.Nonce(),
+		Timestamp: tx.Timestamp(),
+		Type:      tx.Type(),
+		Data:      tx.Data(),
+		GasPrice:  tx.GasPrice().String(),
+		GasLimit:  tx.GasLimit().String(),
+		Status:    core.TxExecutionPendding,
+		ExpiredAt: tx.ExpiredAt(),
+	}
+	if tx.Payer() != nil {
+		resp.Payer = tx.Payer().String()
+	}
+	return resp
+}
+
+// GetContractStorage is the RPC API handler.
+func (s *AdminService) GetContractStorage(req *rpcpb.ContractStorageRequest, gs rpcpb.AdminService_GetContractStorageServer) error {
+	neb := s.server.Neblet()
+
+	addr, err := core.AddressParse(req.Address)
+	if err != nil {
+		return err
+	}
+	block, err := canonicalBlockAt(neb, req.Height)
+	if err != nil {
+		return err
+	}
+
+	iter, err := core.ContractStorageIterator(block, addr)
+	if err != nil {
+		return err
+	}
+	for {
+		exist, err := iter.Next()
+		if err != nil {
+			return err
+		}
+		if !exist {
+			return nil
+		}
+		if err := gs.Send(&rpcpb.ContractStorageResponse{Key: iter.Key(), Value: iter.Value()}); err != nil {
+			return err
+		}
+	}
+}
+
+// DiffContractStorage is the RPC API handler.
+func (s *AdminService) DiffContractStorage(req *rpcpb.ContractStorageDiffRequest, gs rpcpb.AdminService_DiffContractStorageServer) error {
+	neb := s.server.Neblet()
+
+	addr, err := core.AddressParse(req.Address)
+	if err != nil {
+		return err
+	}
+	from, err := canonicalBlockAt(neb, req.FromHeight)
+	if err != nil {
+		return err
+	}
+	to, err := canonicalBlockAt(neb, req.ToHeight)
+	if err != nil {
+		return err
+	}
+
+	iter, err := core.DiffContractStorage(from, to, addr)
+	if err != nil {
+		return err
+	}
+	for {
+		exist, err := iter.Next()
+		if err != nil {
+			return err
+		}
+		if !exist {
+			return nil
+		}
+		change := iter.Change()
+		if err := gs.Send(&rpcpb.ContractStorageChange{
+			Type:     change.Type,
+			Key:      change.Key,
+			OldValue: change.Old,
+			NewValue: change.New,
+		}); err != nil {
+			return err
+		}
+	}
+}
+
+// canonicalBlockAt return the canonical block at height, the tail block if height is 0.
+func canonicalBlockAt(neb core.Neblet, height uint64) (*core.Block, error) {
+	if height == 0 {
+		return neb.BlockChain().TailBlock(), nil
+	}
+	block := neb.BlockChain().GetBlockOnCanonicalChainByHeight(height)
+	if block == nil {
+		return nil, errors.New("block not found")
+	}
+	return block, nil
+}
+
 // StartPprof start pprof
 func (s *AdminService) StartPprof(ctx context.Context, req *rpcpb.PprofRequest) (*rpcpb.PprofResponse, error) {
 	neb := s.server.Neblet()
@@ -225,3 +519,22 @@ func (s *AdminService) NodeInfo(ctx context.Context, req *rpcpb.NonParamsRequest
 
 	return resp, nil
 }
+
+// GetPeerScores return the reputation scores of the peers, the lowest first.
+func (s *AdminService) GetPeerScores(ctx context.Context, req *rpcpb.NonParamsRequest) (*rpcpb.PeerScoresResponse, error) {
+	now := time.Now()
+	resp := &rpcpb.PeerScoresResponse{}
+	for _, score := range s.server.Neblet().NetService().Node().PeerScores() {
+		peer := &rpcpb.PeerScore{
+			Id:     score.PeerID,
+			Score:  score.Score,
+			Bans:   score.Bans,
+			Banned: score.Banned(now),
+		}
+		if score.Bans > 0 {
+			peer.BannedUntil = score.BannedUntil.Unix()
+		}
+		resp.Peers = append(resp.Peers, peer)
+	}
+	return resp, nil
+}
@@ -27,9 +27,7 @@ import (
 
 	"encoding/json"
 
-	"github.com/gogo/protobuf/proto"
 	"github.com/alexlisong/go-nebulas/core"
-	"github.com/alexlisong/go-nebulas/core/pb"
 	"github.com/alexlisong/go-nebulas/net"
 	"github.com/alexlisong/go-nebulas/rpc/pb"
 	"github.com/alexlisong/go-nebulas/util"
@@ -146,7 +144,7 @@ func parseTransaction(neb core.Neblet, reqTx *rpcpb.TransactionRequest) (*core.T
 	if reqTx.Contract != nil {
 		if len(reqTx.Contract.Source) > 0 && len(reqTx.Contract.Function) == 0 { // TODO: reqTx.DeployContract, reqTx.CallContract
 			payloadType = core.TxPayloadDeployType
-			payloadObj, err := core.NewDeployPayload(reqTx.Contract.Source, reqTx.Contract.SourceType, reqTx.Contract.Args)
+			payloadObj, err := core.NewDeployPayloadWithSalt(reqTx.Contract.Source, reqTx.Contract.SourceType, reqTx.Contract.Args, reqTx.Contract.Salt)
 			if err != nil {
 				return nil, err
 			}
@@ -173,10 +171,17 @@ func parseTransaction(neb core.Neblet, reqTx *rpcpb.TransactionRequest) (*core.T
 		}
 	}
 
-	tx, err := core.NewTransaction(neb.BlockChain().ChainID(), fromAddr, toAddr, value, reqTx.Nonce, payloadType, payload, gasPrice, gasLimit)
+	tx, err := core.NewTransactionWithExpiration(neb.BlockChain().ChainID(), fromAddr, toAddr, value, reqTx.Nonce, payloadType, payload, gasPrice, gasLimit, reqTx.ExpiredAt)
 	if err != nil {
 		return nil, err
 	}
+	if len(reqTx.Payer) > 0 {
+		payer, err := core.AddressParse(reqTx.Payer)
+		if err != nil {
+			return nil, err
+		}
+		tx.SetPayer(payer)
+	}
 	return tx, nil
 }
 
@@ -217,7 +222,7 @@ func handleTransactionResponse(neb core.Neblet, tx *core.Transaction) (resp *rpc
 
 	var contract string
 	if tx.Type() == core.TxPayloadDeployType {
-		addr, err := core.NewContractAddressFromData(tx.From().Bytes(), byteutils.FromUint64(tx.Nonce()))
+		addr, err := tx.ContractAddressAtHeight(tailBlock.Height() + 1)
 		if err != nil {
 			return nil, err
 		}
@@ -233,12 +238,8 @@ func (s *APIService) SendRawTransaction(ctx context.Context, req *rpcpb.SendRawT
 	// Validate and sign the tx, then submit it to the tx pool.
 	neb := s.server.Neblet()
 
-	pbTx := new(corepb.Transaction)
-	if err := proto.Unmarshal(req.GetData(), pbTx); err != nil {
-		return nil, err
-	}
 	tx := new(core.Transaction)
-	if err := tx.FromProto(pbTx); err != nil {
+	if err := tx.FromBytes(req.GetData()); err != nil {
 		return nil, err
 	}
 
@@ -346,25 +347,39 @@ func (s *APIService) GetTransactionReceipt(ctx context.Context, req *rpcpb.GetTr
 
 func (s *APIService) toTransactionResponse(tx *core.Transaction) (*rpcpb.TransactionResponse, error) {
 	var (
-		status  int32
-		gasUsed string
+		status       int32
+		gasUsed      string
+		blockHeight  uint64
+		executeError string
 	)
 	neb := s.server.Neblet()
-	event, err := neb.BlockChain().TailBlock().FetchExecutionResultEvent(tx.Hash())
-	if err != nil && err != core.ErrNotFoundTransactionResultEvent {
+	receipt, err := neb.BlockChain().GetTransactionReceipt(tx.Hash())
+	if err != nil && err != storage.ErrKeyNotFound && err != core.ErrReceiptsNotAvailable {
 		return nil, err
 	}
 
-	if event != nil {
-		txEvent := core.TransactionEvent{}
-		err := json.Unmarshal([]byte(event.Data), &txEvent)
-		if err != nil {
+	if receipt != nil {
+		status = int32(receipt.Status())
+		gasUsed = receipt.GasUsed().String()
+		blockHeight = receipt.BlockHeight()
+		executeError = receipt.Error()
+	} else {
+		// blocks sealed before receipts were introduced only have the result event.
+		event, err := neb.BlockChain().TailBlock().FetchExecutionResultEvent(tx.Hash())
+		if err != nil && err != core.ErrNotFoundTransactionResultEvent {
 			return nil, err
 		}
-		status = int32(txEvent.Status)
-		gasUsed = txEvent.GasUsed
-	} else {
-		status = core.TxExecutionPendding
+		if event != nil {
+			txEvent := core.TransactionEvent{}
+			err := json.Unmarshal([]byte(event.Data), &txEvent)
+			if err != nil {
+				return nil, err
+			}
+			status = int32(txEvent.Status)
+			gasUsed = txEvent.GasUsed
+		} else {
+			status = core.TxExecutionPendding
+		}
 	}
 
 	resp := &rpcpb.TransactionResponse{
@@ -381,10 +396,19 @@ func (s *APIService) toTransactionResponse(tx *core.Transaction) (*rpcpb.Transac
 		GasLimit:  tx.GasLimit().String(),
 		Status:    status,
 		GasUsed:   gasUsed,
+
+		BlockHeight:  blockHeight,
+		ExecuteError: executeError,
+		ExpiredAt:    tx.ExpiredAt(),
+	}
+	if tx.Payer() != nil {
+		resp.Payer = tx.Payer().String()
 	}
 
-	if tx.Type() == core.TxPayloadDeployType {
-		contractAddr, err := tx.GenerateContractAddress()
+	if receipt != nil && receipt.ContractAddress() != nil {
+		resp.ContractAddress = receipt.ContractAddress().String()
+	} else if tx.Type() == core.TxPayloadDeployType {
+		contractAddr, err := tx.ContractAddressAtHeight(neb.BlockChain().TailBlock().Height() + 1)
 		if err != nil {
 			return nil, err
 		}
@@ -398,7 +422,28 @@ func (s *APIService) Subscribe(req *rpcpb.SubscribeRequest, gs rpcpb.ApiService_
 
 	neb := s.server.Neblet()
 
-	eventSub := core.NewEventSubscriber(1024, req.Topics)
+	topics := req.Topics
+	var noticeCh chan *core.PendingTxNotice
+	if isPendingTxFiltered(req) {
+		filter, err := core.NewPendingTxFilter(req.ToAddresses, req.PayloadTypes, neb.BlockChain().TailBlock().Height()+1)
+		if err != nil {
+			return err
+		}
+		pendingSub := core.NewPendingTxSubscriber(1024, filter)
+		neb.BlockChain().TransactionPool().SubscribePending(pendingSub)
+		defer neb.BlockChain().TransactionPool().UnsubscribePending(pendingSub)
+		noticeCh = pendingSub.NoticeChan()
+
+		// pending and drop notices come from the pool subscription instead.
+		topics = []string{}
+		for _, topic := range req.Topics {
+			if topic != core.TopicPendingTransaction && topic != core.TopicDropTransaction {
+				topics = append(topics, topic)
+			}
+		}
+	}
+
+	eventSub := core.NewEventSubscriber(1024, topics)
 	neb.EventEmitter().Register(eventSub)
 	defer neb.EventEmitter().Deregister(eventSub)
 
@@ -412,10 +457,52 @@ func (s *APIService) Subscribe(req *rpcpb.SubscribeRequest, gs rpcpb.ApiService_
 			if err != nil {
 				return err
 			}
+		case notice := <-noticeCh:
+			resp, err := toPendingTxResponse(notice, req.FullTx)
+			if err != nil {
+				return err
+			}
+			err = gs.Send(resp)
+			if err != nil {
+				return err
+			}
 		}
 	}
 }
 
+func isPendingTxFiltered(req *rpcpb.SubscribeRequest) bool {
+	if len(req.ToAddresses) == 0 && len(req.PayloadTypes) == 0 && !req.FullTx {
+		return false
+	}
+	for _, topic := range req.Topics {
+		if topic == core.TopicPendingTransaction {
+			return true
+		}
+	}
+	return false
+}
+
+type pendingTxNoticeData struct {
+	Hash   string `json:"hash"`
+	Reason string `json:"reason,omitempty"`
+}
+
+func toPendingTxResponse(notice *core.PendingTxNotice, fullTx bool) (*rpcpb.SubscribeResponse, error) {
+	if notice.Type == core.PendingTxNoticePending && fullTx {
+		return &rpcpb.SubscribeResponse{Topic: core.TopicPendingTransaction, Data: notice.Tx.String()}, nil
+	}
+
+	topic := core.TopicPendingTransaction
+	if notice.Type == core.PendingTxNoticeDropped {
+		topic = core.TopicDropTransaction
+	}
+	data, err := json.Marshal(&pendingTxNoticeData{Hash: notice.Hash.String(), Reason: notice.Reason})
+	if err != nil {
+		return nil, err
+	}
+	return &rpcpb.SubscribeResponse{Topic: topic, Data: string(data)}, nil
+}
+
 // GetGasPrice get gas price from chain.
 func (s *APIService) GetGasPrice(ctx context.Context, req *rpcpb.NonParamsRequest) (*rpcpb.GasPriceResponse, error) {
 	neb := s.server.Neblet()
@@ -423,6 +510,46 @@ func (s *APIService) GetGasPrice(ctx context.Context, req *rpcpb.NonParamsReques
 	return &rpcpb.GasPriceResponse{GasPrice: gasPrice.String()}, nil
 }
 
+// GetMinGasPrice get the lowest gas price accepted by the node's transaction pool.
+func (s *APIService) GetMinGasPrice(ctx context.Context, req *rpcpb.NonParamsRequest) (*rpcpb.GasPriceResponse, error) {
+	neb := s.server.Neblet()
+	gasPrice := neb.BlockChain().TransactionPool().MinGasPrice()
+	return &rpcpb.GasPriceResponse{GasPrice: gasPrice.String()}, nil
+}
+
+// GetContractSource return the source of a deployed contract.
+func (s *APIService) GetContractSource(ctx context.Context, req *rpcpb.GetContractSourceRequest) (*rpcpb.GetContractSourceResponse, error) {
+	neb := s.server.Neblet()
+
+	addr, err := core.AddressParse(req.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	block := neb.BlockChain().TailBlock()
+	if req.Height > 0 {
+		block = neb.BlockChain().GetBlockOnCanonicalChainByHeight(req.Height)
+		if block == nil {
+			return nil, errors.New("block not found")
+		}
+	}
+
+	source, err := block.GetContractSource(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rpcpb.GetContractSourceResponse{
+		Source:     source.Source,
+		SourceType: source.SourceType,
+		Deployer:   source.Deployer,
+		DeployTx:   source.DeployTx,
+		Salt:       source.Salt,
+		Version:    source.Version,
+		UpgradeTx:  source.UpgradeTx,
+	}, nil
+}
+
 // EstimateGas Compute the smart contract gas consumption.
 func (s *APIService) EstimateGas(ctx context.Context, req *rpcpb.TransactionRequest) (*rpcpb.GasResponse, error) {
 	neb := s.server.Neblet()
@@ -431,7 +558,7 @@ func (s *APIService) EstimateGas(ctx context.Context, req *rpcpb.TransactionRequ
 		return nil, err
 	}
 
-	result, err := neb.BlockChain().SimulateTransactionExecution(tx)
+	gasLimit, result, err := neb.BlockChain().EstimateGas(tx)
 	if err != nil {
 		return nil, err
 	}
@@ -440,7 +567,13 @@ func (s *APIService) EstimateGas(ctx context.Context, req *rpcpb.TransactionRequ
 	if result.Err != nil {
 		errMsg = result.Err.Error()
 	}
-	return &rpcpb.GasResponse{Gas: result.GasUsed.String(), Err: errMsg}, nil
+	resp := &rpcpb.GasResponse{Gas: result.GasUsed.String(), GasLimit: gasLimit.String(), Err: errMsg}
+	if trace := result.GasTrace; trace != nil {
+		resp.GasBase = trace.BaseGas.String()
+		resp.GasPayload = trace.PayloadBaseGas.String()
+		resp.GasExecution = trace.ExecutionGas.String()
+	}
+	return resp, nil
 }
 
 // GetEventsByHash return events by tx hash.
@@ -462,7 +595,7 @@ func (s *APIService) GetEventsByHash(ctx context.Context, req *rpcpb.HashRequest
 		return nil, err
 	}
 
-	result, err := tailBlock.FetchEvents(tx.Hash())
+	result, err := tailBlock.FetchEventsWithExecutionResult(tx.Hash())
 	if err != nil {
 		return nil, err
 	}
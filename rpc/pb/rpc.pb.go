@@ -48,6 +48,24 @@ It has these top-level messages:
 	PprofRequest
 	PprofResponse
 	GetConfigResponse
+	SendTransactionSafeRequest
+	SendTransactionSafeResponse
+	VerifyIndexConsistencyRequest
+	VerifyIndexConsistencyResponse
+	IndexDiscrepancy
+	SignTransactionAsPayerRequest
+	PoolContentRequest
+	PoolContentResponse
+	PoolAccountContent
+	PoolStats
+	ContractStorageRequest
+	ContractStorageResponse
+	ContractStorageDiffRequest
+	ContractStorageChange
+	GetContractSourceRequest
+	GetContractSourceResponse
+	PeerScoresResponse
+	PeerScore
 */
 package rpcpb
 
@@ -77,6 +95,12 @@ const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
 // Request message of Subscribe rpc
 type SubscribeRequest struct {
 	Topics []string `protobuf:"bytes,1,rep,name=topics" json:"topics,omitempty"`
+	// only pending txs sent to these addresses are pushed, empty for all.
+	ToAddresses []string `protobuf:"bytes,2,rep,name=to_addresses,json=toAddresses" json:"to_addresses,omitempty"`
+	// only pending txs of these payload types are pushed, empty for all.
+	PayloadTypes []string `protobuf:"bytes,3,rep,name=payload_types,json=payloadTypes" json:"payload_types,omitempty"`
+	// push the full tx json instead of the tx hash.
+	FullTx bool `protobuf:"varint,4,opt,name=full_tx,json=fullTx,proto3" json:"full_tx,omitempty"`
 }
 
 func (m *SubscribeRequest) Reset()                    { *m = SubscribeRequest{} }
@@ -91,6 +115,27 @@ func (m *SubscribeRequest) GetTopics() []string {
 	return nil
 }
 
+func (m *SubscribeRequest) GetToAddresses() []string {
+	if m != nil {
+		return m.ToAddresses
+	}
+	return nil
+}
+
+func (m *SubscribeRequest) GetPayloadTypes() []string {
+	if m != nil {
+		return m.PayloadTypes
+	}
+	return nil
+}
+
+func (m *SubscribeRequest) GetFullTx() bool {
+	if m != nil {
+		return m.FullTx
+	}
+	return false
+}
+
 // Request message of Subscribe rpc
 type SubscribeResponse struct {
 	Topic string `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
@@ -470,6 +515,10 @@ type TransactionRequest struct {
 	Contract *ContractRequest `protobuf:"bytes,7,opt,name=contract" json:"contract,omitempty"`
 	// binary data for transaction
 	Binary []byte `protobuf:"bytes,10,opt,name=binary,proto3" json:"binary,omitempty"`
+	// the transaction can't be on chain after this timestamp, 0 if never expires.
+	ExpiredAt int64 `protobuf:"varint,11,opt,name=expired_at,json=expiredAt,proto3" json:"expired_at,omitempty"`
+	// Hex string of the fee payer account address, who pays the gas instead of the sender.
+	Payer string `protobuf:"bytes,12,opt,name=payer,proto3" json:"payer,omitempty"`
 }
 
 func (m *TransactionRequest) Reset()                    { *m = TransactionRequest{} }
@@ -533,6 +582,20 @@ func (m *TransactionRequest) GetBinary() []byte {
 	return nil
 }
 
+func (m *TransactionRequest) GetExpiredAt() int64 {
+	if m != nil {
+		return m.ExpiredAt
+	}
+	return 0
+}
+
+func (m *TransactionRequest) GetPayer() string {
+	if m != nil {
+		return m.Payer
+	}
+	return ""
+}
+
 type ContractRequest struct {
 	// contract source code.
 	Source string `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
@@ -542,6 +605,8 @@ type ContractRequest struct {
 	Function string `protobuf:"bytes,3,opt,name=function,proto3" json:"function,omitempty"`
 	// the params of contract.
 	Args string `protobuf:"bytes,4,opt,name=args,proto3" json:"args,omitempty"`
+	// the salt of deploy, the contract address is derived from chainID, from, nonce, salt and source if not empty.
+	Salt string `protobuf:"bytes,5,opt,name=salt,proto3" json:"salt,omitempty"`
 }
 
 func (m *ContractRequest) Reset()                    { *m = ContractRequest{} }
@@ -577,6 +642,13 @@ func (m *ContractRequest) GetArgs() string {
 	return ""
 }
 
+func (m *ContractRequest) GetSalt() string {
+	if m != nil {
+		return m.Salt
+	}
+	return ""
+}
+
 // Request message of SendRawTransactionRequest rpc.
 type SendRawTransactionRequest struct {
 	// Signed data of transaction
@@ -851,6 +923,14 @@ type TransactionResponse struct {
 	Status int32 `protobuf:"varint,13,opt,name=status,proto3" json:"status,omitempty"`
 	// transaction gas used
 	GasUsed string `protobuf:"bytes,14,opt,name=gas_used,json=gasUsed,proto3" json:"gas_used,omitempty"`
+	// height of the block including the transaction
+	BlockHeight uint64 `protobuf:"varint,15,opt,name=block_height,json=blockHeight,proto3" json:"block_height,omitempty"`
+	// error message if the transaction execution failed
+	ExecuteError string `protobuf:"bytes,16,opt,name=execute_error,json=executeError,proto3" json:"execute_error,omitempty"`
+	// the transaction can't be on chain after this timestamp, 0 if never expires.
+	ExpiredAt int64 `protobuf:"varint,17,opt,name=expired_at,json=expiredAt,proto3" json:"expired_at,omitempty"`
+	// Hex string of the fee payer account address, empty if the sender pays.
+	Payer string `protobuf:"bytes,18,opt,name=payer,proto3" json:"payer,omitempty"`
 }
 
 func (m *TransactionResponse) Reset()                    { *m = TransactionResponse{} }
@@ -956,6 +1036,34 @@ func (m *TransactionResponse) GetGasUsed() string {
 	return ""
 }
 
+func (m *TransactionResponse) GetBlockHeight() uint64 {
+	if m != nil {
+		return m.BlockHeight
+	}
+	return 0
+}
+
+func (m *TransactionResponse) GetExecuteError() string {
+	if m != nil {
+		return m.ExecuteError
+	}
+	return ""
+}
+
+func (m *TransactionResponse) GetExpiredAt() int64 {
+	if m != nil {
+		return m.ExpiredAt
+	}
+	return 0
+}
+
+func (m *TransactionResponse) GetPayer() string {
+	if m != nil {
+		return m.Payer
+	}
+	return ""
+}
+
 type NewAccountRequest struct {
 	Passphrase string `protobuf:"bytes,1,opt,name=passphrase,proto3" json:"passphrase,omitempty"`
 }
@@ -1166,9 +1274,9 @@ func (m *SignTransactionPassphraseResponse) GetData() []byte {
 }
 
 type SendTransactionPassphraseRequest struct {
-	// transaction struct
+	// transaction signed by the sender, the output of SignTransactionWithPassphrase.
 	Transaction *TransactionRequest `protobuf:"bytes,1,opt,name=transaction" json:"transaction,omitempty"`
-	// from account passphrase
+	// payer account passphrase
 	Passphrase string `protobuf:"bytes,2,opt,name=passphrase,proto3" json:"passphrase,omitempty"`
 }
 
@@ -1194,6 +1302,7 @@ func (m *SendTransactionPassphraseRequest) GetPassphrase() string {
 }
 
 type GasPriceResponse struct {
+	// transaction struct
 	GasPrice string `protobuf:"bytes,1,opt,name=gas_price,json=gasPrice,proto3" json:"gas_price,omitempty"`
 }
 
@@ -1209,9 +1318,7 @@ func (m *GasPriceResponse) GetGasPrice() string {
 	return ""
 }
 
-// Request message of GetTransactionByHash rpc.
 type HashRequest struct {
-	// Hex string of block/transaction hash.
 	Hash string `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
 }
 
@@ -1227,9 +1334,15 @@ func (m *HashRequest) GetHash() string {
 	return ""
 }
 
+// Request message of GetTransactionByHash rpc.
 type GasResponse struct {
-	Gas string `protobuf:"bytes,1,opt,name=gas,proto3" json:"gas,omitempty"`
-	Err string `protobuf:"bytes,2,opt,name=err,proto3" json:"err,omitempty"`
+	// Hex string of block/transaction hash.
+	Gas          string `protobuf:"bytes,1,opt,name=gas,proto3" json:"gas,omitempty"`
+	Err          string `protobuf:"bytes,2,opt,name=err,proto3" json:"err,omitempty"`
+	GasLimit     string `protobuf:"bytes,3,opt,name=gas_limit,json=gasLimit,proto3" json:"gas_limit,omitempty"`
+	GasBase      string `protobuf:"bytes,4,opt,name=gas_base,json=gasBase,proto3" json:"gas_base,omitempty"`
+	GasPayload   string `protobuf:"bytes,5,opt,name=gas_payload,json=gasPayload,proto3" json:"gas_payload,omitempty"`
+	GasExecution string `protobuf:"bytes,6,opt,name=gas_execution,json=gasExecution,proto3" json:"gas_execution,omitempty"`
 }
 
 func (m *GasResponse) Reset()                    { *m = GasResponse{} }
@@ -1251,6 +1364,34 @@ func (m *GasResponse) GetErr() string {
 	return ""
 }
 
+func (m *GasResponse) GetGasLimit() string {
+	if m != nil {
+		return m.GasLimit
+	}
+	return ""
+}
+
+func (m *GasResponse) GetGasBase() string {
+	if m != nil {
+		return m.GasBase
+	}
+	return ""
+}
+
+func (m *GasResponse) GetGasPayload() string {
+	if m != nil {
+		return m.GasPayload
+	}
+	return ""
+}
+
+func (m *GasResponse) GetGasExecution() string {
+	if m != nil {
+		return m.GasExecution
+	}
+	return ""
+}
+
 type EventsResponse struct {
 	Events []*Event `protobuf:"bytes,1,rep,name=events" json:"events,omitempty"`
 }
@@ -1324,7 +1465,6 @@ func (m *PprofResponse) GetResult() bool {
 }
 
 type GetConfigResponse struct {
-	// Config
 	Config *nebletpb.Config `protobuf:"bytes,1,opt,name=config" json:"config,omitempty"`
 }
 
@@ -1340,182 +1480,964 @@ func (m *GetConfigResponse) GetConfig() *nebletpb.Config {
 	return nil
 }
 
-func init() {
-	proto.RegisterType((*SubscribeRequest)(nil), "rpcpb.SubscribeRequest")
-	proto.RegisterType((*SubscribeResponse)(nil), "rpcpb.SubscribeResponse")
-	proto.RegisterType((*NonParamsRequest)(nil), "rpcpb.NonParamsRequest")
-	proto.RegisterType((*NodeInfoResponse)(nil), "rpcpb.NodeInfoResponse")
-	proto.RegisterType((*RouteTable)(nil), "rpcpb.RouteTable")
-	proto.RegisterType((*GetNebStateResponse)(nil), "rpcpb.GetNebStateResponse")
-	proto.RegisterType((*AccountsResponse)(nil), "rpcpb.AccountsResponse")
-	proto.RegisterType((*GetAccountStateRequest)(nil), "rpcpb.GetAccountStateRequest")
-	proto.RegisterType((*GetAccountStateResponse)(nil), "rpcpb.GetAccountStateResponse")
-	proto.RegisterType((*CallResponse)(nil), "rpcpb.CallResponse")
-	proto.RegisterType((*ByBlockHeightRequest)(nil), "rpcpb.ByBlockHeightRequest")
-	proto.RegisterType((*GetDynastyResponse)(nil), "rpcpb.GetDynastyResponse")
-	proto.RegisterType((*TransactionRequest)(nil), "rpcpb.TransactionRequest")
-	proto.RegisterType((*ContractRequest)(nil), "rpcpb.ContractRequest")
-	proto.RegisterType((*SendRawTransactionRequest)(nil), "rpcpb.SendRawTransactionRequest")
-	proto.RegisterType((*SendTransactionResponse)(nil), "rpcpb.SendTransactionResponse")
-	proto.RegisterType((*GetBlockByHashRequest)(nil), "rpcpb.GetBlockByHashRequest")
-	proto.RegisterType((*GetBlockByHeightRequest)(nil), "rpcpb.GetBlockByHeightRequest")
-	proto.RegisterType((*GetTransactionByHashRequest)(nil), "rpcpb.GetTransactionByHashRequest")
-	proto.RegisterType((*BlockResponse)(nil), "rpcpb.BlockResponse")
-	proto.RegisterType((*TransactionResponse)(nil), "rpcpb.TransactionResponse")
-	proto.RegisterType((*NewAccountRequest)(nil), "rpcpb.NewAccountRequest")
-	proto.RegisterType((*NewAccountResponse)(nil), "rpcpb.NewAccountResponse")
-	proto.RegisterType((*UnlockAccountRequest)(nil), "rpcpb.UnlockAccountRequest")
-	proto.RegisterType((*UnlockAccountResponse)(nil), "rpcpb.UnlockAccountResponse")
-	proto.RegisterType((*LockAccountRequest)(nil), "rpcpb.LockAccountRequest")
-	proto.RegisterType((*LockAccountResponse)(nil), "rpcpb.LockAccountResponse")
-	proto.RegisterType((*SignHashRequest)(nil), "rpcpb.SignHashRequest")
-	proto.RegisterType((*SignHashResponse)(nil), "rpcpb.SignHashResponse")
-	proto.RegisterType((*SignTransactionPassphraseRequest)(nil), "rpcpb.SignTransactionPassphraseRequest")
-	proto.RegisterType((*SignTransactionPassphraseResponse)(nil), "rpcpb.SignTransactionPassphraseResponse")
-	proto.RegisterType((*SendTransactionPassphraseRequest)(nil), "rpcpb.SendTransactionPassphraseRequest")
-	proto.RegisterType((*GasPriceResponse)(nil), "rpcpb.GasPriceResponse")
-	proto.RegisterType((*HashRequest)(nil), "rpcpb.HashRequest")
-	proto.RegisterType((*GasResponse)(nil), "rpcpb.GasResponse")
-	proto.RegisterType((*EventsResponse)(nil), "rpcpb.EventsResponse")
-	proto.RegisterType((*Event)(nil), "rpcpb.Event")
-	proto.RegisterType((*PprofRequest)(nil), "rpcpb.PprofRequest")
-	proto.RegisterType((*PprofResponse)(nil), "rpcpb.PprofResponse")
-	proto.RegisterType((*GetConfigResponse)(nil), "rpcpb.GetConfigResponse")
+type SendTransactionSafeRequest struct {
+	// Config
+	Transaction   *TransactionRequest `protobuf:"bytes,1,opt,name=transaction" json:"transaction,omitempty"`
+	Passphrase    string              `protobuf:"bytes,2,opt,name=passphrase,proto3" json:"passphrase,omitempty"`
+	AllowedErrors []string            `protobuf:"bytes,3,rep,name=allowed_errors,json=allowedErrors" json:"allowed_errors,omitempty"`
+	Strict        bool                `protobuf:"varint,4,opt,name=strict,proto3" json:"strict,omitempty"`
+	MaxFee        string              `protobuf:"bytes,5,opt,name=max_fee,json=maxFee,proto3" json:"max_fee,omitempty"`
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
-
-// Client API for ApiService service
+func (m *SendTransactionSafeRequest) Reset()                    { *m = SendTransactionSafeRequest{} }
+func (m *SendTransactionSafeRequest) String() string            { return proto.CompactTextString(m) }
+func (*SendTransactionSafeRequest) ProtoMessage()               {}
+func (*SendTransactionSafeRequest) Descriptor() ([]byte, []int) { return fileDescriptorRpc, []int{40} }
 
-type ApiServiceClient interface {
-	// Return the state of the neb.
-	GetNebState(ctx context.Context, in *NonParamsRequest, opts ...grpc.CallOption) (*GetNebStateResponse, error)
-	// Return the latest irreversible block.
-	LatestIrreversibleBlock(ctx context.Context, in *NonParamsRequest, opts ...grpc.CallOption) (*BlockResponse, error)
-	// Return the state of the account.
-	GetAccountState(ctx context.Context, in *GetAccountStateRequest, opts ...grpc.CallOption) (*GetAccountStateResponse, error)
-	// Call transaction
-	Call(ctx context.Context, in *TransactionRequest, opts ...grpc.CallOption) (*CallResponse, error)
-	// Submit the signed transaction.
-	SendRawTransaction(ctx context.Context, in *SendRawTransactionRequest, opts ...grpc.CallOption) (*SendTransactionResponse, error)
-	// Get block info by the block hash.
-	GetBlockByHash(ctx context.Context, in *GetBlockByHashRequest, opts ...grpc.CallOption) (*BlockResponse, error)
-	// Get block info by the block height.
-	GetBlockByHeight(ctx context.Context, in *GetBlockByHeightRequest, opts ...grpc.CallOption) (*BlockResponse, error)
-	// Get transactionReceipt info by tansaction hash.
-	GetTransactionReceipt(ctx context.Context, in *GetTransactionByHashRequest, opts ...grpc.CallOption) (*TransactionResponse, error)
-	// Subscribe message
-	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (ApiService_SubscribeClient, error)
-	// Get GasPrice
-	GetGasPrice(ctx context.Context, in *NonParamsRequest, opts ...grpc.CallOption) (*GasPriceResponse, error)
-	// EstimateGas
-	EstimateGas(ctx context.Context, in *TransactionRequest, opts ...grpc.CallOption) (*GasResponse, error)
-	GetEventsByHash(ctx context.Context, in *HashRequest, opts ...grpc.CallOption) (*EventsResponse, error)
-	GetDynasty(ctx context.Context, in *ByBlockHeightRequest, opts ...grpc.CallOption) (*GetDynastyResponse, error)
+func (m *SendTransactionSafeRequest) GetTransaction() *TransactionRequest {
+	if m != nil {
+		return m.Transaction
+	}
+	return nil
 }
 
-type apiServiceClient struct {
-	cc *grpc.ClientConn
+func (m *SendTransactionSafeRequest) GetPassphrase() string {
+	if m != nil {
+		return m.Passphrase
+	}
+	return ""
 }
 
-func NewApiServiceClient(cc *grpc.ClientConn) ApiServiceClient {
-	return &apiServiceClient{cc}
+func (m *SendTransactionSafeRequest) GetAllowedErrors() []string {
+	if m != nil {
+		return m.AllowedErrors
+	}
+	return nil
 }
 
-func (c *apiServiceClient) GetNebState(ctx context.Context, in *NonParamsRequest, opts ...grpc.CallOption) (*GetNebStateResponse, error) {
-	out := new(GetNebStateResponse)
-	err := grpc.Invoke(ctx, "/rpcpb.ApiService/GetNebState", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *SendTransactionSafeRequest) GetStrict() bool {
+	if m != nil {
+		return m.Strict
 	}
-	return out, nil
+	return false
 }
 
-func (c *apiServiceClient) LatestIrreversibleBlock(ctx context.Context, in *NonParamsRequest, opts ...grpc.CallOption) (*BlockResponse, error) {
-	out := new(BlockResponse)
-	err := grpc.Invoke(ctx, "/rpcpb.ApiService/LatestIrreversibleBlock", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *SendTransactionSafeRequest) GetMaxFee() string {
+	if m != nil {
+		return m.MaxFee
 	}
-	return out, nil
+	return ""
 }
 
-func (c *apiServiceClient) GetAccountState(ctx context.Context, in *GetAccountStateRequest, opts ...grpc.CallOption) (*GetAccountStateResponse, error) {
-	out := new(GetAccountStateResponse)
-	err := grpc.Invoke(ctx, "/rpcpb.ApiService/GetAccountState", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+// Request message of SendTransactionSafe rpc.
+type SendTransactionSafeResponse struct {
+	// transaction struct, nonce is filled if 0, gas price & gas limit are filled if empty.
+	Txhash string `protobuf:"bytes,1,opt,name=txhash,proto3" json:"txhash,omitempty"`
+	// from account passphrase, the unlocked key is used if empty.
+	ContractAddress string `protobuf:"bytes,2,opt,name=contract_address,json=contractAddress,proto3" json:"contract_address,omitempty"`
+	// execution errors tolerated by the simulation.
+	Result string `protobuf:"bytes,3,opt,name=result,proto3" json:"result,omitempty"`
+	// if true, the estimated fee must not exceed max_fee.
+	ExecuteErr string `protobuf:"bytes,4,opt,name=execute_err,json=executeErr,proto3" json:"execute_err,omitempty"`
+	// max estimated fee, gasUsed * gasPrice.
+	GasUsed      string `protobuf:"bytes,5,opt,name=gas_used,json=gasUsed,proto3" json:"gas_used,omitempty"`
+	GasBase      string `protobuf:"bytes,6,opt,name=gas_base,json=gasBase,proto3" json:"gas_base,omitempty"`
+	GasPayload   string `protobuf:"bytes,7,opt,name=gas_payload,json=gasPayload,proto3" json:"gas_payload,omitempty"`
+	GasExecution string `protobuf:"bytes,8,opt,name=gas_execution,json=gasExecution,proto3" json:"gas_execution,omitempty"`
+	GasLimit     string `protobuf:"bytes,9,opt,name=gas_limit,json=gasLimit,proto3" json:"gas_limit,omitempty"`
+	EstimateFee  string `protobuf:"bytes,10,opt,name=estimate_fee,json=estimateFee,proto3" json:"estimate_fee,omitempty"`
+}
+
+func (m *SendTransactionSafeResponse) Reset()                    { *m = SendTransactionSafeResponse{} }
+func (m *SendTransactionSafeResponse) String() string            { return proto.CompactTextString(m) }
+func (*SendTransactionSafeResponse) ProtoMessage()               {}
+func (*SendTransactionSafeResponse) Descriptor() ([]byte, []int) { return fileDescriptorRpc, []int{41} }
+
+func (m *SendTransactionSafeResponse) GetTxhash() string {
+	if m != nil {
+		return m.Txhash
 	}
-	return out, nil
+	return ""
 }
 
-func (c *apiServiceClient) Call(ctx context.Context, in *TransactionRequest, opts ...grpc.CallOption) (*CallResponse, error) {
-	out := new(CallResponse)
-	err := grpc.Invoke(ctx, "/rpcpb.ApiService/Call", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *SendTransactionSafeResponse) GetContractAddress() string {
+	if m != nil {
+		return m.ContractAddress
 	}
-	return out, nil
+	return ""
 }
 
-func (c *apiServiceClient) SendRawTransaction(ctx context.Context, in *SendRawTransactionRequest, opts ...grpc.CallOption) (*SendTransactionResponse, error) {
-	out := new(SendTransactionResponse)
-	err := grpc.Invoke(ctx, "/rpcpb.ApiService/SendRawTransaction", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *SendTransactionSafeResponse) GetResult() string {
+	if m != nil {
+		return m.Result
 	}
-	return out, nil
+	return ""
 }
 
-func (c *apiServiceClient) GetBlockByHash(ctx context.Context, in *GetBlockByHashRequest, opts ...grpc.CallOption) (*BlockResponse, error) {
-	out := new(BlockResponse)
-	err := grpc.Invoke(ctx, "/rpcpb.ApiService/GetBlockByHash", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *SendTransactionSafeResponse) GetExecuteErr() string {
+	if m != nil {
+		return m.ExecuteErr
 	}
-	return out, nil
+	return ""
 }
 
-func (c *apiServiceClient) GetBlockByHeight(ctx context.Context, in *GetBlockByHeightRequest, opts ...grpc.CallOption) (*BlockResponse, error) {
-	out := new(BlockResponse)
-	err := grpc.Invoke(ctx, "/rpcpb.ApiService/GetBlockByHeight", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *SendTransactionSafeResponse) GetGasUsed() string {
+	if m != nil {
+		return m.GasUsed
 	}
-	return out, nil
+	return ""
 }
 
-func (c *apiServiceClient) GetTransactionReceipt(ctx context.Context, in *GetTransactionByHashRequest, opts ...grpc.CallOption) (*TransactionResponse, error) {
-	out := new(TransactionResponse)
-	err := grpc.Invoke(ctx, "/rpcpb.ApiService/GetTransactionReceipt", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *SendTransactionSafeResponse) GetGasBase() string {
+	if m != nil {
+		return m.GasBase
 	}
-	return out, nil
+	return ""
 }
 
-func (c *apiServiceClient) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (ApiService_SubscribeClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_ApiService_serviceDesc.Streams[0], c.cc, "/rpcpb.ApiService/Subscribe", opts...)
-	if err != nil {
-		return nil, err
-	}
-	x := &apiServiceSubscribeClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
-	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+func (m *SendTransactionSafeResponse) GetGasPayload() string {
+	if m != nil {
+		return m.GasPayload
 	}
-	return x, nil
+	return ""
 }
 
-type ApiService_SubscribeClient interface {
+func (m *SendTransactionSafeResponse) GetGasExecution() string {
+	if m != nil {
+		return m.GasExecution
+	}
+	return ""
+}
+
+func (m *SendTransactionSafeResponse) GetGasLimit() string {
+	if m != nil {
+		return m.GasLimit
+	}
+	return ""
+}
+
+func (m *SendTransactionSafeResponse) GetEstimateFee() string {
+	if m != nil {
+		return m.EstimateFee
+	}
+	return ""
+}
+
+// Response message of SendTransactionSafe rpc.
+type VerifyIndexConsistencyRequest struct {
+	// Hex string of transaction hash.
+	FromHeight uint64 `protobuf:"varint,1,opt,name=from_height,json=fromHeight,proto3" json:"from_height,omitempty"`
+	// Hex string of contract address if transaction is deploy type
+	ToHeight uint64 `protobuf:"varint,2,opt,name=to_height,json=toHeight,proto3" json:"to_height,omitempty"`
+	// result of simulation.
+	Repair bool `protobuf:"varint,3,opt,name=repair,proto3" json:"repair,omitempty"`
+}
+
+func (m *VerifyIndexConsistencyRequest) Reset()         { *m = VerifyIndexConsistencyRequest{} }
+func (m *VerifyIndexConsistencyRequest) String() string { return proto.CompactTextString(m) }
+func (*VerifyIndexConsistencyRequest) ProtoMessage()    {}
+func (*VerifyIndexConsistencyRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptorRpc, []int{42}
+}
+
+func (m *VerifyIndexConsistencyRequest) GetFromHeight() uint64 {
+	if m != nil {
+		return m.FromHeight
+	}
+	return 0
+}
+
+func (m *VerifyIndexConsistencyRequest) GetToHeight() uint64 {
+	if m != nil {
+		return m.ToHeight
+	}
+	return 0
+}
+
+func (m *VerifyIndexConsistencyRequest) GetRepair() bool {
+	if m != nil {
+		return m.Repair
+	}
+	return false
+}
+
+// Request message of VerifyIndexConsistency rpc.
+type VerifyIndexConsistencyResponse struct {
+	// first block height to verify, 0 means genesis.
+	FromHeight uint64 `protobuf:"varint,1,opt,name=from_height,json=fromHeight,proto3" json:"from_height,omitempty"`
+	// last block height to verify, 0 means tail.
+	ToHeight uint64 `protobuf:"varint,2,opt,name=to_height,json=toHeight,proto3" json:"to_height,omitempty"`
+	// rebuild the height index of discrepancies.
+	Blocks        uint64              `protobuf:"varint,3,opt,name=blocks,proto3" json:"blocks,omitempty"`
+	Txs           uint64              `protobuf:"varint,4,opt,name=txs,proto3" json:"txs,omitempty"`
+	Discrepancies []*IndexDiscrepancy `protobuf:"bytes,5,rep,name=discrepancies" json:"discrepancies,omitempty"`
+	Repaired      uint32              `protobuf:"varint,6,opt,name=repaired,proto3" json:"repaired,omitempty"`
+}
+
+func (m *VerifyIndexConsistencyResponse) Reset()         { *m = VerifyIndexConsistencyResponse{} }
+func (m *VerifyIndexConsistencyResponse) String() string { return proto.CompactTextString(m) }
+func (*VerifyIndexConsistencyResponse) ProtoMessage()    {}
+func (*VerifyIndexConsistencyResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptorRpc, []int{43}
+}
+
+func (m *VerifyIndexConsistencyResponse) GetFromHeight() uint64 {
+	if m != nil {
+		return m.FromHeight
+	}
+	return 0
+}
+
+func (m *VerifyIndexConsistencyResponse) GetToHeight() uint64 {
+	if m != nil {
+		return m.ToHeight
+	}
+	return 0
+}
+
+func (m *VerifyIndexConsistencyResponse) GetBlocks() uint64 {
+	if m != nil {
+		return m.Blocks
+	}
+	return 0
+}
+
+func (m *VerifyIndexConsistencyResponse) GetTxs() uint64 {
+	if m != nil {
+		return m.Txs
+	}
+	return 0
+}
+
+func (m *VerifyIndexConsistencyResponse) GetDiscrepancies() []*IndexDiscrepancy {
+	if m != nil {
+		return m.Discrepancies
+	}
+	return nil
+}
+
+func (m *VerifyIndexConsistencyResponse) GetRepaired() uint32 {
+	if m != nil {
+		return m.Repaired
+	}
+	return 0
+}
+
+// Response message of VerifyIndexConsistency rpc.
+type IndexDiscrepancy struct {
+	// verified block height range.
+	Kind   string `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	Height uint64 `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	// count of verified blocks & txs.
+	Block  string `protobuf:"bytes,3,opt,name=block,proto3" json:"block,omitempty"`
+	Tx     string `protobuf:"bytes,4,opt,name=tx,proto3" json:"tx,omitempty"`
+	Detail string `protobuf:"bytes,5,opt,name=detail,proto3" json:"detail,omitempty"`
+	// count of repaired discrepancies.
+	Repaired bool `protobuf:"varint,6,opt,name=repaired,proto3" json:"repaired,omitempty"`
+}
+
+func (m *IndexDiscrepancy) Reset()                    { *m = IndexDiscrepancy{} }
+func (m *IndexDiscrepancy) String() string            { return proto.CompactTextString(m) }
+func (*IndexDiscrepancy) ProtoMessage()               {}
+func (*IndexDiscrepancy) Descriptor() ([]byte, []int) { return fileDescriptorRpc, []int{44} }
+
+func (m *IndexDiscrepancy) GetKind() string {
+	if m != nil {
+		return m.Kind
+	}
+	return ""
+}
+
+func (m *IndexDiscrepancy) GetHeight() uint64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *IndexDiscrepancy) GetBlock() string {
+	if m != nil {
+		return m.Block
+	}
+	return ""
+}
+
+func (m *IndexDiscrepancy) GetTx() string {
+	if m != nil {
+		return m.Tx
+	}
+	return ""
+}
+
+func (m *IndexDiscrepancy) GetDetail() string {
+	if m != nil {
+		return m.Detail
+	}
+	return ""
+}
+
+func (m *IndexDiscrepancy) GetRepaired() bool {
+	if m != nil {
+		return m.Repaired
+	}
+	return false
+}
+
+type SignTransactionAsPayerRequest struct {
+	// kind of discrepancy, e.g. missing_height_index.
+	Data       []byte `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	Passphrase string `protobuf:"bytes,2,opt,name=passphrase,proto3" json:"passphrase,omitempty"`
+}
+
+func (m *SignTransactionAsPayerRequest) Reset()         { *m = SignTransactionAsPayerRequest{} }
+func (m *SignTransactionAsPayerRequest) String() string { return proto.CompactTextString(m) }
+func (*SignTransactionAsPayerRequest) ProtoMessage()    {}
+func (*SignTransactionAsPayerRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptorRpc, []int{45}
+}
+
+func (m *SignTransactionAsPayerRequest) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *SignTransactionAsPayerRequest) GetPassphrase() string {
+	if m != nil {
+		return m.Passphrase
+	}
+	return ""
+}
+
+// Request message of GetPoolContent rpc.
+type PoolContentRequest struct {
+	// Hex string of the account address, empty for all accounts.
+	Address string `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+}
+
+func (m *PoolContentRequest) Reset()                    { *m = PoolContentRequest{} }
+func (m *PoolContentRequest) String() string            { return proto.CompactTextString(m) }
+func (*PoolContentRequest) ProtoMessage()               {}
+func (*PoolContentRequest) Descriptor() ([]byte, []int) { return fileDescriptorRpc, []int{46} }
+
+func (m *PoolContentRequest) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+// Response message of GetPoolContent rpc.
+type PoolContentResponse struct {
+	Accounts []*PoolAccountContent `protobuf:"bytes,1,rep,name=accounts" json:"accounts,omitempty"`
+	Stats    *PoolStats            `protobuf:"bytes,2,opt,name=stats" json:"stats,omitempty"`
+}
+
+func (m *PoolContentResponse) Reset()                    { *m = PoolContentResponse{} }
+func (m *PoolContentResponse) String() string            { return proto.CompactTextString(m) }
+func (*PoolContentResponse) ProtoMessage()               {}
+func (*PoolContentResponse) Descriptor() ([]byte, []int) { return fileDescriptorRpc, []int{47} }
+
+func (m *PoolContentResponse) GetAccounts() []*PoolAccountContent {
+	if m != nil {
+		return m.Accounts
+	}
+	return nil
+}
+
+func (m *PoolContentResponse) GetStats() *PoolStats {
+	if m != nil {
+		return m.Stats
+	}
+	return nil
+}
+
+type PoolAccountContent struct {
+	// Hex string of the account address.
+	Address string `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	// txs continuous from the account nonce, sorted by nonce.
+	Pending []*TransactionResponse `protobuf:"bytes,2,rep,name=pending" json:"pending,omitempty"`
+	// txs after a nonce gap, sorted by nonce.
+	Queued []*TransactionResponse `protobuf:"bytes,3,rep,name=queued" json:"queued,omitempty"`
+}
+
+func (m *PoolAccountContent) Reset()                    { *m = PoolAccountContent{} }
+func (m *PoolAccountContent) String() string            { return proto.CompactTextString(m) }
+func (*PoolAccountContent) ProtoMessage()               {}
+func (*PoolAccountContent) Descriptor() ([]byte, []int) { return fileDescriptorRpc, []int{48} }
+
+func (m *PoolAccountContent) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *PoolAccountContent) GetPending() []*TransactionResponse {
+	if m != nil {
+		return m.Pending
+	}
+	return nil
+}
+
+func (m *PoolAccountContent) GetQueued() []*TransactionResponse {
+	if m != nil {
+		return m.Queued
+	}
+	return nil
+}
+
+type PoolStats struct {
+	Pending uint32 `protobuf:"varint,1,opt,name=pending,proto3" json:"pending,omitempty"`
+	Queued  uint32 `protobuf:"varint,2,opt,name=queued,proto3" json:"queued,omitempty"`
+	// count of txs being packed into a proposing block.
+	Packing  uint32 `protobuf:"varint,3,opt,name=packing,proto3" json:"packing,omitempty"`
+	Accounts uint32 `protobuf:"varint,4,opt,name=accounts,proto3" json:"accounts,omitempty"`
+	// the gasPrice range of the txs in the pool, empty if the pool is empty.
+	MinGasPrice string `protobuf:"bytes,5,opt,name=min_gas_price,json=minGasPrice,proto3" json:"min_gas_price,omitempty"`
+	MaxGasPrice string `protobuf:"bytes,6,opt,name=max_gas_price,json=maxGasPrice,proto3" json:"max_gas_price,omitempty"`
+}
+
+func (m *PoolStats) Reset()                    { *m = PoolStats{} }
+func (m *PoolStats) String() string            { return proto.CompactTextString(m) }
+func (*PoolStats) ProtoMessage()               {}
+func (*PoolStats) Descriptor() ([]byte, []int) { return fileDescriptorRpc, []int{49} }
+
+func (m *PoolStats) GetPending() uint32 {
+	if m != nil {
+		return m.Pending
+	}
+	return 0
+}
+
+func (m *PoolStats) GetQueued() uint32 {
+	if m != nil {
+		return m.Queued
+	}
+	return 0
+}
+
+func (m *PoolStats) GetPacking() uint32 {
+	if m != nil {
+		return m.Packing
+	}
+	return 0
+}
+
+func (m *PoolStats) GetAccounts() uint32 {
+	if m != nil {
+		return m.Accounts
+	}
+	return 0
+}
+
+func (m *PoolStats) GetMinGasPrice() string {
+	if m != nil {
+		return m.MinGasPrice
+	}
+	return ""
+}
+
+func (m *PoolStats) GetMaxGasPrice() string {
+	if m != nil {
+		return m.MaxGasPrice
+	}
+	return ""
+}
+
+// Request message of GetContractStorage rpc.
+type ContractStorageRequest struct {
+	// Hex string of the contract address.
+	Address string `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	// height of the canonical block, 0 for the tail block.
+	Height uint64 `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+}
+
+func (m *ContractStorageRequest) Reset()                    { *m = ContractStorageRequest{} }
+func (m *ContractStorageRequest) String() string            { return proto.CompactTextString(m) }
+func (*ContractStorageRequest) ProtoMessage()               {}
+func (*ContractStorageRequest) Descriptor() ([]byte, []int) { return fileDescriptorRpc, []int{50} }
+
+func (m *ContractStorageRequest) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *ContractStorageRequest) GetHeight() uint64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+// Response message of GetContractStorage rpc.
+type ContractStorageResponse struct {
+	Key   []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (m *ContractStorageResponse) Reset()                    { *m = ContractStorageResponse{} }
+func (m *ContractStorageResponse) String() string            { return proto.CompactTextString(m) }
+func (*ContractStorageResponse) ProtoMessage()               {}
+func (*ContractStorageResponse) Descriptor() ([]byte, []int) { return fileDescriptorRpc, []int{51} }
+
+func (m *ContractStorageResponse) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *ContractStorageResponse) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+// Request message of DiffContractStorage rpc.
+type ContractStorageDiffRequest struct {
+	// Hex string of the contract address.
+	Address string `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	// heights of the canonical blocks to diff from and to, 0 for the tail block.
+	FromHeight uint64 `protobuf:"varint,2,opt,name=from_height,json=fromHeight,proto3" json:"from_height,omitempty"`
+	ToHeight   uint64 `protobuf:"varint,3,opt,name=to_height,json=toHeight,proto3" json:"to_height,omitempty"`
+}
+
+func (m *ContractStorageDiffRequest) Reset()                    { *m = ContractStorageDiffRequest{} }
+func (m *ContractStorageDiffRequest) String() string            { return proto.CompactTextString(m) }
+func (*ContractStorageDiffRequest) ProtoMessage()               {}
+func (*ContractStorageDiffRequest) Descriptor() ([]byte, []int) { return fileDescriptorRpc, []int{52} }
+
+func (m *ContractStorageDiffRequest) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *ContractStorageDiffRequest) GetFromHeight() uint64 {
+	if m != nil {
+		return m.FromHeight
+	}
+	return 0
+}
+
+func (m *ContractStorageDiffRequest) GetToHeight() uint64 {
+	if m != nil {
+		return m.ToHeight
+	}
+	return 0
+}
+
+// Response message of DiffContractStorage rpc.
+type ContractStorageChange struct {
+	// added, changed or removed.
+	Type string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Key  []byte `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	// the value in from block, empty if the key is added.
+	OldValue []byte `protobuf:"bytes,3,opt,name=old_value,json=oldValue,proto3" json:"old_value,omitempty"`
+	// the value in to block, empty if the key is removed.
+	NewValue []byte `protobuf:"bytes,4,opt,name=new_value,json=newValue,proto3" json:"new_value,omitempty"`
+}
+
+func (m *ContractStorageChange) Reset()                    { *m = ContractStorageChange{} }
+func (m *ContractStorageChange) String() string            { return proto.CompactTextString(m) }
+func (*ContractStorageChange) ProtoMessage()               {}
+func (*ContractStorageChange) Descriptor() ([]byte, []int) { return fileDescriptorRpc, []int{53} }
+
+func (m *ContractStorageChange) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *ContractStorageChange) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *ContractStorageChange) GetOldValue() []byte {
+	if m != nil {
+		return m.OldValue
+	}
+	return nil
+}
+
+func (m *ContractStorageChange) GetNewValue() []byte {
+	if m != nil {
+		return m.NewValue
+	}
+	return nil
+}
+
+// Request message of GetContractSource rpc.
+type GetContractSourceRequest struct {
+	// Hex string of the contract address.
+	Address string `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	// block state with height. If not specified, use 0 as tail height.
+	Height uint64 `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+}
+
+func (m *GetContractSourceRequest) Reset()                    { *m = GetContractSourceRequest{} }
+func (m *GetContractSourceRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetContractSourceRequest) ProtoMessage()               {}
+func (*GetContractSourceRequest) Descriptor() ([]byte, []int) { return fileDescriptorRpc, []int{54} }
+
+func (m *GetContractSourceRequest) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *GetContractSourceRequest) GetHeight() uint64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+// Response message of GetContractSource rpc.
+type GetContractSourceResponse struct {
+	Source     string `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	SourceType string `protobuf:"bytes,2,opt,name=source_type,json=sourceType,proto3" json:"source_type,omitempty"`
+	// the deployer and hash of the deploy transaction.
+	Deployer string `protobuf:"bytes,3,opt,name=deployer,proto3" json:"deployer,omitempty"`
+	DeployTx string `protobuf:"bytes,4,opt,name=deploy_tx,json=deployTx,proto3" json:"deploy_tx,omitempty"`
+	// salt of the contract address, empty for the legacy address.
+	Salt string `protobuf:"bytes,5,opt,name=salt,proto3" json:"salt,omitempty"`
+	// the code version and the transaction upgrading to it, 0 and empty for the deployed code.
+	Version   uint64 `protobuf:"varint,6,opt,name=version,proto3" json:"version,omitempty"`
+	UpgradeTx string `protobuf:"bytes,7,opt,name=upgrade_tx,json=upgradeTx,proto3" json:"upgrade_tx,omitempty"`
+}
+
+func (m *GetContractSourceResponse) Reset()                    { *m = GetContractSourceResponse{} }
+func (m *GetContractSourceResponse) String() string            { return proto.CompactTextString(m) }
+func (*GetContractSourceResponse) ProtoMessage()               {}
+func (*GetContractSourceResponse) Descriptor() ([]byte, []int) { return fileDescriptorRpc, []int{55} }
+
+func (m *GetContractSourceResponse) GetSource() string {
+	if m != nil {
+		return m.Source
+	}
+	return ""
+}
+
+func (m *GetContractSourceResponse) GetSourceType() string {
+	if m != nil {
+		return m.SourceType
+	}
+	return ""
+}
+
+func (m *GetContractSourceResponse) GetDeployer() string {
+	if m != nil {
+		return m.Deployer
+	}
+	return ""
+}
+
+func (m *GetContractSourceResponse) GetDeployTx() string {
+	if m != nil {
+		return m.DeployTx
+	}
+	return ""
+}
+
+func (m *GetContractSourceResponse) GetSalt() string {
+	if m != nil {
+		return m.Salt
+	}
+	return ""
+}
+
+func (m *GetContractSourceResponse) GetVersion() uint64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *GetContractSourceResponse) GetUpgradeTx() string {
+	if m != nil {
+		return m.UpgradeTx
+	}
+	return ""
+}
+
+// Response message of GetPeerScores rpc.
+type PeerScoresResponse struct {
+	Peers []*PeerScore `protobuf:"bytes,1,rep,name=peers" json:"peers,omitempty"`
+}
+
+func (m *PeerScoresResponse) Reset()                    { *m = PeerScoresResponse{} }
+func (m *PeerScoresResponse) String() string            { return proto.CompactTextString(m) }
+func (*PeerScoresResponse) ProtoMessage()               {}
+func (*PeerScoresResponse) Descriptor() ([]byte, []int) { return fileDescriptorRpc, []int{56} }
+
+func (m *PeerScoresResponse) GetPeers() []*PeerScore {
+	if m != nil {
+		return m.Peers
+	}
+	return nil
+}
+
+type PeerScore struct {
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// decayed score of the validation verdicts of the peer's messages.
+	Score float64 `protobuf:"fixed64,2,opt,name=score,proto3" json:"score,omitempty"`
+	// count of the bans, the ban duration doubles on every ban.
+	Bans uint32 `protobuf:"varint,3,opt,name=bans,proto3" json:"bans,omitempty"`
+	// unix seconds the ban ends, 0 if never banned.
+	BannedUntil int64 `protobuf:"varint,4,opt,name=banned_until,json=bannedUntil,proto3" json:"banned_until,omitempty"`
+	Banned      bool  `protobuf:"varint,5,opt,name=banned,proto3" json:"banned,omitempty"`
+}
+
+func (m *PeerScore) Reset()                    { *m = PeerScore{} }
+func (m *PeerScore) String() string            { return proto.CompactTextString(m) }
+func (*PeerScore) ProtoMessage()               {}
+func (*PeerScore) Descriptor() ([]byte, []int) { return fileDescriptorRpc, []int{57} }
+
+func (m *PeerScore) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *PeerScore) GetScore() float64 {
+	if m != nil {
+		return m.Score
+	}
+	return 0
+}
+
+func (m *PeerScore) GetBans() uint32 {
+	if m != nil {
+		return m.Bans
+	}
+	return 0
+}
+
+func (m *PeerScore) GetBannedUntil() int64 {
+	if m != nil {
+		return m.BannedUntil
+	}
+	return 0
+}
+
+func (m *PeerScore) GetBanned() bool {
+	if m != nil {
+		return m.Banned
+	}
+	return false
+}
+
+func init() {
+	proto.RegisterType((*SubscribeRequest)(nil), "rpcpb.SubscribeRequest")
+	proto.RegisterType((*SubscribeResponse)(nil), "rpcpb.SubscribeResponse")
+	proto.RegisterType((*NonParamsRequest)(nil), "rpcpb.NonParamsRequest")
+	proto.RegisterType((*NodeInfoResponse)(nil), "rpcpb.NodeInfoResponse")
+	proto.RegisterType((*RouteTable)(nil), "rpcpb.RouteTable")
+	proto.RegisterType((*GetNebStateResponse)(nil), "rpcpb.GetNebStateResponse")
+	proto.RegisterType((*AccountsResponse)(nil), "rpcpb.AccountsResponse")
+	proto.RegisterType((*GetAccountStateRequest)(nil), "rpcpb.GetAccountStateRequest")
+	proto.RegisterType((*GetAccountStateResponse)(nil), "rpcpb.GetAccountStateResponse")
+	proto.RegisterType((*CallResponse)(nil), "rpcpb.CallResponse")
+	proto.RegisterType((*ByBlockHeightRequest)(nil), "rpcpb.ByBlockHeightRequest")
+	proto.RegisterType((*GetDynastyResponse)(nil), "rpcpb.GetDynastyResponse")
+	proto.RegisterType((*TransactionRequest)(nil), "rpcpb.TransactionRequest")
+	proto.RegisterType((*ContractRequest)(nil), "rpcpb.ContractRequest")
+	proto.RegisterType((*SendRawTransactionRequest)(nil), "rpcpb.SendRawTransactionRequest")
+	proto.RegisterType((*SendTransactionResponse)(nil), "rpcpb.SendTransactionResponse")
+	proto.RegisterType((*GetBlockByHashRequest)(nil), "rpcpb.GetBlockByHashRequest")
+	proto.RegisterType((*GetBlockByHeightRequest)(nil), "rpcpb.GetBlockByHeightRequest")
+	proto.RegisterType((*GetTransactionByHashRequest)(nil), "rpcpb.GetTransactionByHashRequest")
+	proto.RegisterType((*BlockResponse)(nil), "rpcpb.BlockResponse")
+	proto.RegisterType((*TransactionResponse)(nil), "rpcpb.TransactionResponse")
+	proto.RegisterType((*NewAccountRequest)(nil), "rpcpb.NewAccountRequest")
+	proto.RegisterType((*NewAccountResponse)(nil), "rpcpb.NewAccountResponse")
+	proto.RegisterType((*UnlockAccountRequest)(nil), "rpcpb.UnlockAccountRequest")
+	proto.RegisterType((*UnlockAccountResponse)(nil), "rpcpb.UnlockAccountResponse")
+	proto.RegisterType((*LockAccountRequest)(nil), "rpcpb.LockAccountRequest")
+	proto.RegisterType((*LockAccountResponse)(nil), "rpcpb.LockAccountResponse")
+	proto.RegisterType((*SignHashRequest)(nil), "rpcpb.SignHashRequest")
+	proto.RegisterType((*SignHashResponse)(nil), "rpcpb.SignHashResponse")
+	proto.RegisterType((*SignTransactionPassphraseRequest)(nil), "rpcpb.SignTransactionPassphraseRequest")
+	proto.RegisterType((*SignTransactionPassphraseResponse)(nil), "rpcpb.SignTransactionPassphraseResponse")
+	proto.RegisterType((*SendTransactionPassphraseRequest)(nil), "rpcpb.SendTransactionPassphraseRequest")
+	proto.RegisterType((*GasPriceResponse)(nil), "rpcpb.GasPriceResponse")
+	proto.RegisterType((*HashRequest)(nil), "rpcpb.HashRequest")
+	proto.RegisterType((*GasResponse)(nil), "rpcpb.GasResponse")
+	proto.RegisterType((*EventsResponse)(nil), "rpcpb.EventsResponse")
+	proto.RegisterType((*Event)(nil), "rpcpb.Event")
+	proto.RegisterType((*PprofRequest)(nil), "rpcpb.PprofRequest")
+	proto.RegisterType((*PprofResponse)(nil), "rpcpb.PprofResponse")
+	proto.RegisterType((*GetConfigResponse)(nil), "rpcpb.GetConfigResponse")
+	proto.RegisterType((*SendTransactionSafeRequest)(nil), "rpcpb.SendTransactionSafeRequest")
+	proto.RegisterType((*SendTransactionSafeResponse)(nil), "rpcpb.SendTransactionSafeResponse")
+	proto.RegisterType((*VerifyIndexConsistencyRequest)(nil), "rpcpb.VerifyIndexConsistencyRequest")
+	proto.RegisterType((*VerifyIndexConsistencyResponse)(nil), "rpcpb.VerifyIndexConsistencyResponse")
+	proto.RegisterType((*IndexDiscrepancy)(nil), "rpcpb.IndexDiscrepancy")
+	proto.RegisterType((*SignTransactionAsPayerRequest)(nil), "rpcpb.SignTransactionAsPayerRequest")
+	proto.RegisterType((*PoolContentRequest)(nil), "rpcpb.PoolContentRequest")
+	proto.RegisterType((*PoolContentResponse)(nil), "rpcpb.PoolContentResponse")
+	proto.RegisterType((*PoolAccountContent)(nil), "rpcpb.PoolAccountContent")
+	proto.RegisterType((*PoolStats)(nil), "rpcpb.PoolStats")
+	proto.RegisterType((*ContractStorageRequest)(nil), "rpcpb.ContractStorageRequest")
+	proto.RegisterType((*ContractStorageResponse)(nil), "rpcpb.ContractStorageResponse")
+	proto.RegisterType((*ContractStorageDiffRequest)(nil), "rpcpb.ContractStorageDiffRequest")
+	proto.RegisterType((*ContractStorageChange)(nil), "rpcpb.ContractStorageChange")
+	proto.RegisterType((*GetContractSourceRequest)(nil), "rpcpb.GetContractSourceRequest")
+	proto.RegisterType((*GetContractSourceResponse)(nil), "rpcpb.GetContractSourceResponse")
+	proto.RegisterType((*PeerScoresResponse)(nil), "rpcpb.PeerScoresResponse")
+	proto.RegisterType((*PeerScore)(nil), "rpcpb.PeerScore")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for ApiService service
+
+type ApiServiceClient interface {
+	// Return the state of the neb.
+	GetNebState(ctx context.Context, in *NonParamsRequest, opts ...grpc.CallOption) (*GetNebStateResponse, error)
+	// Return the latest irreversible block.
+	LatestIrreversibleBlock(ctx context.Context, in *NonParamsRequest, opts ...grpc.CallOption) (*BlockResponse, error)
+	// Return the state of the account.
+	GetAccountState(ctx context.Context, in *GetAccountStateRequest, opts ...grpc.CallOption) (*GetAccountStateResponse, error)
+	// Call transaction
+	Call(ctx context.Context, in *TransactionRequest, opts ...grpc.CallOption) (*CallResponse, error)
+	// Submit the signed transaction.
+	SendRawTransaction(ctx context.Context, in *SendRawTransactionRequest, opts ...grpc.CallOption) (*SendTransactionResponse, error)
+	// Get block info by the block hash.
+	GetBlockByHash(ctx context.Context, in *GetBlockByHashRequest, opts ...grpc.CallOption) (*BlockResponse, error)
+	// Get block info by the block height.
+	GetBlockByHeight(ctx context.Context, in *GetBlockByHeightRequest, opts ...grpc.CallOption) (*BlockResponse, error)
+	// Get transactionReceipt info by tansaction hash.
+	GetTransactionReceipt(ctx context.Context, in *GetTransactionByHashRequest, opts ...grpc.CallOption) (*TransactionResponse, error)
+	// Subscribe message
+	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (ApiService_SubscribeClient, error)
+	// Get GasPrice
+	GetGasPrice(ctx context.Context, in *NonParamsRequest, opts ...grpc.CallOption) (*GasPriceResponse, error)
+	// EstimateGas
+	EstimateGas(ctx context.Context, in *TransactionRequest, opts ...grpc.CallOption) (*GasResponse, error)
+	GetEventsByHash(ctx context.Context, in *HashRequest, opts ...grpc.CallOption) (*EventsResponse, error)
+	GetDynasty(ctx context.Context, in *ByBlockHeightRequest, opts ...grpc.CallOption) (*GetDynastyResponse, error)
+	// Get the lowest gasPrice accepted by the node's transaction pool
+	GetMinGasPrice(ctx context.Context, in *NonParamsRequest, opts ...grpc.CallOption) (*GasPriceResponse, error)
+	// Return the source of a deployed contract.
+	GetContractSource(ctx context.Context, in *GetContractSourceRequest, opts ...grpc.CallOption) (*GetContractSourceResponse, error)
+}
+
+type apiServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewApiServiceClient(cc *grpc.ClientConn) ApiServiceClient {
+	return &apiServiceClient{cc}
+}
+
+func (c *apiServiceClient) GetNebState(ctx context.Context, in *NonParamsRequest, opts ...grpc.CallOption) (*GetNebStateResponse, error) {
+	out := new(GetNebStateResponse)
+	err := grpc.Invoke(ctx, "/rpcpb.ApiService/GetNebState", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiServiceClient) LatestIrreversibleBlock(ctx context.Context, in *NonParamsRequest, opts ...grpc.CallOption) (*BlockResponse, error) {
+	out := new(BlockResponse)
+	err := grpc.Invoke(ctx, "/rpcpb.ApiService/LatestIrreversibleBlock", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiServiceClient) GetAccountState(ctx context.Context, in *GetAccountStateRequest, opts ...grpc.CallOption) (*GetAccountStateResponse, error) {
+	out := new(GetAccountStateResponse)
+	err := grpc.Invoke(ctx, "/rpcpb.ApiService/GetAccountState", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiServiceClient) Call(ctx context.Context, in *TransactionRequest, opts ...grpc.CallOption) (*CallResponse, error) {
+	out := new(CallResponse)
+	err := grpc.Invoke(ctx, "/rpcpb.ApiService/Call", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiServiceClient) SendRawTransaction(ctx context.Context, in *SendRawTransactionRequest, opts ...grpc.CallOption) (*SendTransactionResponse, error) {
+	out := new(SendTransactionResponse)
+	err := grpc.Invoke(ctx, "/rpcpb.ApiService/SendRawTransaction", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiServiceClient) GetBlockByHash(ctx context.Context, in *GetBlockByHashRequest, opts ...grpc.CallOption) (*BlockResponse, error) {
+	out := new(BlockResponse)
+	err := grpc.Invoke(ctx, "/rpcpb.ApiService/GetBlockByHash", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiServiceClient) GetBlockByHeight(ctx context.Context, in *GetBlockByHeightRequest, opts ...grpc.CallOption) (*BlockResponse, error) {
+	out := new(BlockResponse)
+	err := grpc.Invoke(ctx, "/rpcpb.ApiService/GetBlockByHeight", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiServiceClient) GetTransactionReceipt(ctx context.Context, in *GetTransactionByHashRequest, opts ...grpc.CallOption) (*TransactionResponse, error) {
+	out := new(TransactionResponse)
+	err := grpc.Invoke(ctx, "/rpcpb.ApiService/GetTransactionReceipt", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiServiceClient) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (ApiService_SubscribeClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_ApiService_serviceDesc.Streams[0], c.cc, "/rpcpb.ApiService/Subscribe", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &apiServiceSubscribeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ApiService_SubscribeClient interface {
 	Recv() (*SubscribeResponse, error)
 	grpc.ClientStream
 }
@@ -1568,6 +2490,24 @@ func (c *apiServiceClient) GetDynasty(ctx context.Context, in *ByBlockHeightRequ
 	return out, nil
 }
 
+func (c *apiServiceClient) GetMinGasPrice(ctx context.Context, in *NonParamsRequest, opts ...grpc.CallOption) (*GasPriceResponse, error) {
+	out := new(GasPriceResponse)
+	err := grpc.Invoke(ctx, "/rpcpb.ApiService/GetMinGasPrice", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiServiceClient) GetContractSource(ctx context.Context, in *GetContractSourceRequest, opts ...grpc.CallOption) (*GetContractSourceResponse, error) {
+	out := new(GetContractSourceResponse)
+	err := grpc.Invoke(ctx, "/rpcpb.ApiService/GetContractSource", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for ApiService service
 
 type ApiServiceServer interface {
@@ -1595,6 +2535,10 @@ type ApiServiceServer interface {
 	EstimateGas(context.Context, *TransactionRequest) (*GasResponse, error)
 	GetEventsByHash(context.Context, *HashRequest) (*EventsResponse, error)
 	GetDynasty(context.Context, *ByBlockHeightRequest) (*GetDynastyResponse, error)
+	// Get the lowest gasPrice accepted by the node's transaction pool
+	GetMinGasPrice(context.Context, *NonParamsRequest) (*GasPriceResponse, error)
+	// Return the source of a deployed contract.
+	GetContractSource(context.Context, *GetContractSourceRequest) (*GetContractSourceResponse, error)
 }
 
 func RegisterApiServiceServer(s *grpc.Server, srv ApiServiceServer) {
@@ -1838,6 +2782,42 @@ func _ApiService_GetDynasty_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ApiService_GetMinGasPrice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NonParamsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApiServiceServer).GetMinGasPrice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcpb.ApiService/GetMinGasPrice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApiServiceServer).GetMinGasPrice(ctx, req.(*NonParamsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ApiService_GetContractSource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetContractSourceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApiServiceServer).GetContractSource(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcpb.ApiService/GetContractSource",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApiServiceServer).GetContractSource(ctx, req.(*GetContractSourceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _ApiService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "rpcpb.ApiService",
 	HandlerType: (*ApiServiceServer)(nil),
@@ -1890,6 +2870,14 @@ var _ApiService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetDynasty",
 			Handler:    _ApiService_GetDynasty_Handler,
 		},
+		{
+			MethodName: "GetMinGasPrice",
+			Handler:    _ApiService_GetMinGasPrice_Handler,
+		},
+		{
+			MethodName: "GetContractSource",
+			Handler:    _ApiService_GetContractSource_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -1925,6 +2913,20 @@ type AdminServiceClient interface {
 	GetConfig(ctx context.Context, in *NonParamsRequest, opts ...grpc.CallOption) (*GetConfigResponse, error)
 	// Return the p2p node info.
 	NodeInfo(ctx context.Context, in *NonParamsRequest, opts ...grpc.CallOption) (*NodeInfoResponse, error)
+	// Simulate the transaction, and only sign and send it if the simulation succeeds.
+	SendTransactionSafe(ctx context.Context, in *SendTransactionSafeRequest, opts ...grpc.CallOption) (*SendTransactionSafeResponse, error)
+	// Verify the canonical height index, txs and receipts of blocks, and repair the height index.
+	VerifyIndexConsistency(ctx context.Context, in *VerifyIndexConsistencyRequest, opts ...grpc.CallOption) (*VerifyIndexConsistencyResponse, error)
+	// SignTransactionAsPayer co-sign the sender signed transaction as its fee payer
+	SignTransactionAsPayer(ctx context.Context, in *SignTransactionAsPayerRequest, opts ...grpc.CallOption) (*SignTransactionPassphraseResponse, error)
+	// Return the pending and queued transactions in the transaction pool.
+	GetPoolContent(ctx context.Context, in *PoolContentRequest, opts ...grpc.CallOption) (*PoolContentResponse, error)
+	// Stream the key/value storage of a contract, in key order.
+	GetContractStorage(ctx context.Context, in *ContractStorageRequest, opts ...grpc.CallOption) (AdminService_GetContractStorageClient, error)
+	// Stream the keys added, changed or removed in the storage of a contract between two blocks, in key order.
+	DiffContractStorage(ctx context.Context, in *ContractStorageDiffRequest, opts ...grpc.CallOption) (AdminService_DiffContractStorageClient, error)
+	// Return the reputation scores of the peers, the lowest first.
+	GetPeerScores(ctx context.Context, in *NonParamsRequest, opts ...grpc.CallOption) (*PeerScoresResponse, error)
 }
 
 type adminServiceClient struct {
@@ -2034,6 +3036,115 @@ func (c *adminServiceClient) NodeInfo(ctx context.Context, in *NonParamsRequest,
 	return out, nil
 }
 
+func (c *adminServiceClient) SendTransactionSafe(ctx context.Context, in *SendTransactionSafeRequest, opts ...grpc.CallOption) (*SendTransactionSafeResponse, error) {
+	out := new(SendTransactionSafeResponse)
+	err := grpc.Invoke(ctx, "/rpcpb.AdminService/SendTransactionSafe", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) VerifyIndexConsistency(ctx context.Context, in *VerifyIndexConsistencyRequest, opts ...grpc.CallOption) (*VerifyIndexConsistencyResponse, error) {
+	out := new(VerifyIndexConsistencyResponse)
+	err := grpc.Invoke(ctx, "/rpcpb.AdminService/VerifyIndexConsistency", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) SignTransactionAsPayer(ctx context.Context, in *SignTransactionAsPayerRequest, opts ...grpc.CallOption) (*SignTransactionPassphraseResponse, error) {
+	out := new(SignTransactionPassphraseResponse)
+	err := grpc.Invoke(ctx, "/rpcpb.AdminService/SignTransactionAsPayer", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetPoolContent(ctx context.Context, in *PoolContentRequest, opts ...grpc.CallOption) (*PoolContentResponse, error) {
+	out := new(PoolContentResponse)
+	err := grpc.Invoke(ctx, "/rpcpb.AdminService/GetPoolContent", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetContractStorage(ctx context.Context, in *ContractStorageRequest, opts ...grpc.CallOption) (AdminService_GetContractStorageClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_AdminService_serviceDesc.Streams[0], c.cc, "/rpcpb.AdminService/GetContractStorage", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &adminServiceGetContractStorageClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type AdminService_GetContractStorageClient interface {
+	Recv() (*ContractStorageResponse, error)
+	grpc.ClientStream
+}
+
+type adminServiceGetContractStorageClient struct {
+	grpc.ClientStream
+}
+
+func (x *adminServiceGetContractStorageClient) Recv() (*ContractStorageResponse, error) {
+	m := new(ContractStorageResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *adminServiceClient) DiffContractStorage(ctx context.Context, in *ContractStorageDiffRequest, opts ...grpc.CallOption) (AdminService_DiffContractStorageClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_AdminService_serviceDesc.Streams[1], c.cc, "/rpcpb.AdminService/DiffContractStorage", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &adminServiceDiffContractStorageClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type AdminService_DiffContractStorageClient interface {
+	Recv() (*ContractStorageChange, error)
+	grpc.ClientStream
+}
+
+type adminServiceDiffContractStorageClient struct {
+	grpc.ClientStream
+}
+
+func (x *adminServiceDiffContractStorageClient) Recv() (*ContractStorageChange, error) {
+	m := new(ContractStorageChange)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *adminServiceClient) GetPeerScores(ctx context.Context, in *NonParamsRequest, opts ...grpc.CallOption) (*PeerScoresResponse, error) {
+	out := new(PeerScoresResponse)
+	err := grpc.Invoke(ctx, "/rpcpb.AdminService/GetPeerScores", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for AdminService service
 
 type AdminServiceServer interface {
@@ -2058,6 +3169,20 @@ type AdminServiceServer interface {
 	GetConfig(context.Context, *NonParamsRequest) (*GetConfigResponse, error)
 	// Return the p2p node info.
 	NodeInfo(context.Context, *NonParamsRequest) (*NodeInfoResponse, error)
+	// Simulate the transaction, and only sign and send it if the simulation succeeds.
+	SendTransactionSafe(context.Context, *SendTransactionSafeRequest) (*SendTransactionSafeResponse, error)
+	// Verify the canonical height index, txs and receipts of blocks, and repair the height index.
+	VerifyIndexConsistency(context.Context, *VerifyIndexConsistencyRequest) (*VerifyIndexConsistencyResponse, error)
+	// SignTransactionAsPayer co-sign the sender signed transaction as its fee payer
+	SignTransactionAsPayer(context.Context, *SignTransactionAsPayerRequest) (*SignTransactionPassphraseResponse, error)
+	// Return the pending and queued transactions in the transaction pool.
+	GetPoolContent(context.Context, *PoolContentRequest) (*PoolContentResponse, error)
+	// Stream the key/value storage of a contract, in key order.
+	GetContractStorage(*ContractStorageRequest, AdminService_GetContractStorageServer) error
+	// Stream the keys added, changed or removed in the storage of a contract between two blocks, in key order.
+	DiffContractStorage(*ContractStorageDiffRequest, AdminService_DiffContractStorageServer) error
+	// Return the reputation scores of the peers, the lowest first.
+	GetPeerScores(context.Context, *NonParamsRequest) (*PeerScoresResponse, error)
 }
 
 func RegisterAdminServiceServer(s *grpc.Server, srv AdminServiceServer) {
@@ -2262,6 +3387,138 @@ func _AdminService_NodeInfo_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AdminService_SendTransactionSafe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendTransactionSafeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).SendTransactionSafe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcpb.AdminService/SendTransactionSafe",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).SendTransactionSafe(ctx, req.(*SendTransactionSafeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_VerifyIndexConsistency_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyIndexConsistencyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).VerifyIndexConsistency(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcpb.AdminService/VerifyIndexConsistency",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).VerifyIndexConsistency(ctx, req.(*VerifyIndexConsistencyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_SignTransactionAsPayer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignTransactionAsPayerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).SignTransactionAsPayer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcpb.AdminService/SignTransactionAsPayer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).SignTransactionAsPayer(ctx, req.(*SignTransactionAsPayerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetPoolContent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PoolContentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetPoolContent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcpb.AdminService/GetPoolContent",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetPoolContent(ctx, req.(*PoolContentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetContractStorage_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ContractStorageRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AdminServiceServer).GetContractStorage(m, &adminServiceGetContractStorageServer{stream})
+}
+
+type AdminService_GetContractStorageServer interface {
+	Send(*ContractStorageResponse) error
+	grpc.ServerStream
+}
+
+type adminServiceGetContractStorageServer struct {
+	grpc.ServerStream
+}
+
+func (x *adminServiceGetContractStorageServer) Send(m *ContractStorageResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _AdminService_DiffContractStorage_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ContractStorageDiffRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AdminServiceServer).DiffContractStorage(m, &adminServiceDiffContractStorageServer{stream})
+}
+
+type AdminService_DiffContractStorageServer interface {
+	Send(*ContractStorageChange) error
+	grpc.ServerStream
+}
+
+type adminServiceDiffContractStorageServer struct {
+	grpc.ServerStream
+}
+
+func (x *adminServiceDiffContractStorageServer) Send(m *ContractStorageChange) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _AdminService_GetPeerScores_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NonParamsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetPeerScores(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcpb.AdminService/GetPeerScores",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetPeerScores(ctx, req.(*NonParamsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _AdminService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "rpcpb.AdminService",
 	HandlerType: (*AdminServiceServer)(nil),
@@ -2310,150 +3567,246 @@ var _AdminService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "NodeInfo",
 			Handler:    _AdminService_NodeInfo_Handler,
 		},
+		{
+			MethodName: "SendTransactionSafe",
+			Handler:    _AdminService_SendTransactionSafe_Handler,
+		},
+		{
+			MethodName: "VerifyIndexConsistency",
+			Handler:    _AdminService_VerifyIndexConsistency_Handler,
+		},
+		{
+			MethodName: "SignTransactionAsPayer",
+			Handler:    _AdminService_SignTransactionAsPayer_Handler,
+		},
+		{
+			MethodName: "GetPoolContent",
+			Handler:    _AdminService_GetPoolContent_Handler,
+		},
+		{
+			MethodName: "GetPeerScores",
+			Handler:    _AdminService_GetPeerScores_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "GetContractStorage",
+			Handler:       _AdminService_GetContractStorage_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "DiffContractStorage",
+			Handler:       _AdminService_DiffContractStorage_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "rpc.proto",
 }
 
 func init() { proto.RegisterFile("rpc.proto", fileDescriptorRpc) }
 
 var fileDescriptorRpc = []byte{
-	// 2190 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x59, 0x4b, 0x6f, 0x1b, 0xc9,
-	0x11, 0x06, 0x49, 0x3d, 0xc8, 0x22, 0x25, 0xd1, 0xad, 0xd7, 0x88, 0x96, 0x64, 0xb9, 0xbd, 0xd8,
-	0xd5, 0x1a, 0x59, 0x71, 0xad, 0x05, 0x9c, 0xc0, 0xc1, 0x06, 0xb0, 0x1d, 0xaf, 0xd6, 0x81, 0x61,
-	0x38, 0x23, 0x6f, 0xb2, 0x40, 0xe2, 0x10, 0xcd, 0x61, 0x8b, 0xec, 0xec, 0x68, 0x66, 0x32, 0xdd,
-	0xb4, 0x2d, 0x5d, 0x02, 0xec, 0x3d, 0xa7, 0x5c, 0x72, 0xc8, 0x3f, 0xc8, 0xaf, 0x09, 0x72, 0xc8,
-	0x25, 0xc7, 0x9c, 0x72, 0xcb, 0x3f, 0x08, 0xba, 0xa6, 0x7b, 0x5e, 0x1c, 0x8a, 0x71, 0x0e, 0xb9,
-	0x4d, 0x55, 0x77, 0x57, 0x55, 0xd7, 0xe3, 0xab, 0x6a, 0x12, 0x5a, 0x71, 0xe4, 0x9d, 0x44, 0x71,
-	0xa8, 0x42, 0xb2, 0x1c, 0x47, 0x5e, 0x34, 0xec, 0xed, 0x8f, 0xc3, 0x70, 0xec, 0xf3, 0x3e, 0x8b,
-	0x44, 0x9f, 0x05, 0x41, 0xa8, 0x98, 0x12, 0x61, 0x20, 0x93, 0x4d, 0xbd, 0x1f, 0x8d, 0x85, 0x9a,
-	0x4c, 0x87, 0x27, 0x5e, 0x78, 0xd9, 0x0f, 0xf8, 0x70, 0xea, 0x33, 0x29, 0xc2, 0xfe, 0x38, 0xfc,
-	0xcc, 0x10, 0x7d, 0x2f, 0x0c, 0x24, 0x0f, 0xe4, 0x54, 0xf6, 0xa3, 0x61, 0x5f, 0x2a, 0xa6, 0xb8,
-	0x39, 0xf9, 0x70, 0xd1, 0xc9, 0x80, 0x0f, 0x7d, 0xae, 0xf4, 0x31, 0x2f, 0x0c, 0x2e, 0xc4, 0x38,
-	0x39, 0x47, 0xef, 0x43, 0xf7, 0x7c, 0x3a, 0x94, 0x5e, 0x2c, 0x86, 0xdc, 0xe5, 0xbf, 0x9b, 0x72,
-	0xa9, 0xc8, 0x0e, 0xac, 0xa8, 0x30, 0x12, 0x9e, 0x74, 0x6a, 0x47, 0x8d, 0xe3, 0x96, 0x6b, 0x28,
-	0xfa, 0x25, 0xdc, 0xca, 0xed, 0x95, 0x91, 0xb6, 0x85, 0x6c, 0xc1, 0x32, 0x2e, 0x3b, 0xb5, 0xa3,
-	0xda, 0x71, 0xcb, 0x4d, 0x08, 0x42, 0x60, 0x69, 0xc4, 0x14, 0x73, 0xea, 0xc8, 0xc4, 0x6f, 0x4a,
-	0xa0, 0xfb, 0x32, 0x0c, 0x5e, 0xb1, 0x98, 0x5d, 0x4a, 0xa3, 0x8a, 0xfe, 0xb9, 0xae, 0x99, 0x23,
-	0xfe, 0x3c, 0xb8, 0x08, 0x53, 0x91, 0xeb, 0x50, 0x17, 0x23, 0x23, 0xaf, 0x2e, 0x46, 0x64, 0x0f,
-	0x9a, 0xde, 0x84, 0x89, 0x60, 0x20, 0x46, 0x28, 0x70, 0xcd, 0x5d, 0x45, 0xfa, 0xf9, 0x88, 0xf4,
-	0xa0, 0xe9, 0x85, 0x22, 0x18, 0x32, 0xc9, 0x9d, 0x06, 0x1e, 0x48, 0x69, 0x72, 0x00, 0x10, 0x71,
-	0x1e, 0x0f, 0xbc, 0x70, 0x1a, 0x28, 0x67, 0x09, 0x0f, 0xb6, 0x34, 0xe7, 0xa9, 0x66, 0x10, 0x0a,
-	0x1d, 0x79, 0x15, 0x78, 0x93, 0x38, 0x0c, 0xc4, 0x35, 0x1f, 0x39, 0xcb, 0x47, 0xb5, 0xe3, 0xa6,
-	0x5b, 0xe0, 0x91, 0x3b, 0xd0, 0x1e, 0x4e, 0xbd, 0xef, 0xb8, 0x1a, 0x48, 0x71, 0xcd, 0x9d, 0x95,
-	0xa3, 0xda, 0xf1, 0xb2, 0x0b, 0x09, 0xeb, 0x5c, 0x5c, 0x73, 0xf2, 0x29, 0x74, 0xd1, 0x8f, 0x5e,
-	0xe8, 0x0f, 0xde, 0xf2, 0x58, 0x8a, 0x30, 0x70, 0x00, 0xed, 0xd8, 0xb0, 0xfc, 0x5f, 0x24, 0x6c,
-	0x72, 0x0a, 0xed, 0x38, 0x9c, 0x2a, 0x3e, 0x50, 0x6c, 0xe8, 0x73, 0xa7, 0x7d, 0xd4, 0x38, 0x6e,
-	0x9f, 0xde, 0x3a, 0xc1, 0xb4, 0x38, 0x71, 0xf5, 0xca, 0x6b, 0xbd, 0xe0, 0x42, 0x9c, 0x7e, 0xd3,
-	0x87, 0x00, 0xd9, 0xca, 0x8c, 0x5f, 0x1c, 0x58, 0x65, 0xa3, 0x51, 0xcc, 0xa5, 0x74, 0xea, 0x18,
-	0x28, 0x4b, 0xd2, 0xbf, 0xd7, 0x60, 0xf3, 0x8c, 0xab, 0x97, 0x7c, 0x78, 0xae, 0x73, 0x24, 0xf5,
-	0x6c, 0xde, 0x93, 0xb5, 0xa2, 0x27, 0x09, 0x2c, 0x29, 0x26, 0x7c, 0x1b, 0x31, 0xfd, 0x4d, 0xba,
-	0xd0, 0xf0, 0xc5, 0xd0, 0x38, 0x56, 0x7f, 0xea, 0xd4, 0x98, 0x70, 0x31, 0x9e, 0x24, 0xfe, 0x5c,
-	0x72, 0x0d, 0x55, 0xe9, 0x87, 0x95, 0x6a, 0x3f, 0x94, 0xfd, 0xbe, 0x5a, 0xe1, 0x77, 0x07, 0x56,
-	0xad, 0x94, 0x26, 0x4a, 0xb1, 0x24, 0xfd, 0x1c, 0xba, 0x8f, 0x3d, 0x8c, 0xa8, 0x4c, 0x6f, 0xb5,
-	0x0f, 0x2d, 0x73, 0x71, 0x6e, 0x53, 0x36, 0x63, 0xd0, 0x9f, 0xc1, 0xce, 0x19, 0x57, 0xe6, 0x90,
-	0x71, 0x47, 0x92, 0xe7, 0x39, 0xff, 0x25, 0x4e, 0xb5, 0x64, 0xee, 0x9a, 0xf5, 0xfc, 0x35, 0xe9,
-	0x1b, 0xd8, 0x9d, 0x91, 0x65, 0x8c, 0x70, 0x60, 0x75, 0xc8, 0x7c, 0x16, 0x78, 0xdc, 0x0a, 0x33,
-	0xa4, 0xae, 0x90, 0x20, 0xd4, 0xfc, 0x44, 0x56, 0x42, 0xa0, 0xbf, 0xaf, 0xa2, 0x24, 0x6b, 0xd7,
-	0x5c, 0xfc, 0xa6, 0xbf, 0x85, 0xce, 0x53, 0xe6, 0xfb, 0xa9, 0xcc, 0x1d, 0x58, 0x89, 0xb9, 0x9c,
-	0xfa, 0xca, 0x88, 0x34, 0x94, 0x4e, 0x4b, 0xfe, 0x9e, 0x7b, 0x3a, 0x99, 0x78, 0x1c, 0x9b, 0x90,
-	0x81, 0x61, 0x3d, 0x8b, 0x63, 0x72, 0x17, 0x3a, 0x5c, 0x2a, 0x71, 0xc9, 0x14, 0x1f, 0x8c, 0x99,
-	0x34, 0x11, 0x6c, 0x5b, 0xde, 0x19, 0x93, 0xf4, 0x04, 0xb6, 0x9e, 0x5c, 0x3d, 0xf1, 0x43, 0xef,
-	0xbb, 0xaf, 0xf1, 0x6e, 0xb9, 0xe2, 0x37, 0x57, 0xaf, 0x15, 0xae, 0xfe, 0x03, 0x20, 0x67, 0x5c,
-	0xfd, 0xf4, 0x2a, 0x60, 0x52, 0x5d, 0xe5, 0x2d, 0xbc, 0x14, 0x01, 0x8f, 0x53, 0xa8, 0x48, 0x28,
-	0xfa, 0xaf, 0x1a, 0x90, 0xd7, 0x31, 0x0b, 0x24, 0xf3, 0x34, 0xbe, 0x59, 0xe1, 0x04, 0x96, 0x2e,
-	0xe2, 0xf0, 0xd2, 0x5c, 0x07, 0xbf, 0x75, 0x56, 0xab, 0xd0, 0xdc, 0xa1, 0xae, 0x42, 0xed, 0xae,
-	0xb7, 0xcc, 0x9f, 0xda, 0x7a, 0x4e, 0x88, 0xcc, 0x89, 0x4b, 0x79, 0x27, 0xde, 0x86, 0xd6, 0x98,
-	0xc9, 0x41, 0x14, 0x0b, 0x8f, 0x63, 0x01, 0xb7, 0xdc, 0xe6, 0x98, 0xc9, 0x57, 0x9a, 0xb6, 0x8b,
-	0xbe, 0xb8, 0x14, 0xca, 0x24, 0xa3, 0x5e, 0x7c, 0xa1, 0x69, 0x72, 0xaa, 0x81, 0x23, 0x50, 0x31,
-	0xf3, 0x14, 0x66, 0x60, 0xfb, 0x74, 0xc7, 0x94, 0xe2, 0x53, 0xc3, 0x36, 0x36, 0xbb, 0xe9, 0x3e,
-	0x7d, 0xd9, 0xa1, 0x08, 0x58, 0x7c, 0x85, 0x25, 0xde, 0x71, 0x0d, 0x45, 0xaf, 0x61, 0xa3, 0x74,
-	0x48, 0x6f, 0x95, 0xe1, 0x34, 0x4e, 0x93, 0xc1, 0x50, 0x3a, 0x72, 0xc9, 0xd7, 0x00, 0x83, 0x6f,
-	0x22, 0x97, 0xb0, 0x5e, 0x5f, 0x45, 0x5c, 0x03, 0xda, 0xc5, 0x34, 0x40, 0xa7, 0x59, 0x40, 0xb3,
-	0xb4, 0xf6, 0x1e, 0x8b, 0xc7, 0x12, 0x5d, 0xd0, 0x72, 0xf1, 0x9b, 0xf6, 0x61, 0xef, 0x9c, 0x07,
-	0x23, 0x97, 0xbd, 0xab, 0x76, 0x37, 0xa2, 0x70, 0x0d, 0xcd, 0x4d, 0x50, 0xf8, 0xd7, 0xb0, 0xab,
-	0x0f, 0x14, 0x76, 0x67, 0xc1, 0x54, 0xef, 0x27, 0x4c, 0x4e, 0xac, 0xd1, 0x09, 0xa5, 0x8b, 0xdb,
-	0xfa, 0x60, 0x90, 0x01, 0x0e, 0x16, 0xb7, 0xe5, 0x3f, 0x36, 0xc0, 0x33, 0x80, 0xed, 0x33, 0xae,
-	0x30, 0xad, 0x9e, 0x5c, 0x7d, 0xcd, 0xe4, 0x24, 0x67, 0x4a, 0x4e, 0x32, 0x7e, 0x93, 0x53, 0xd8,
-	0xbe, 0x98, 0xfa, 0xfe, 0xe0, 0x42, 0xf8, 0xfe, 0x40, 0x65, 0x06, 0xa1, 0xf0, 0xa6, 0xbb, 0xa9,
-	0x17, 0xbf, 0x12, 0xbe, 0x9f, 0xb3, 0x95, 0x72, 0xac, 0x40, 0xab, 0xe0, 0xbf, 0xc9, 0xdc, 0xff,
-	0x49, 0xcd, 0x03, 0xb8, 0x7d, 0xc6, 0x55, 0x8e, 0xb3, 0xf0, 0x36, 0xf4, 0x1f, 0x0d, 0x58, 0x43,
-	0xbb, 0x52, 0x7f, 0x56, 0xdd, 0xf9, 0x0e, 0xb4, 0x23, 0x16, 0xf3, 0x40, 0x0d, 0x70, 0xc9, 0x24,
-	0x40, 0xc2, 0xd2, 0x1a, 0x72, 0xb7, 0x68, 0x14, 0x6e, 0x51, 0x5d, 0x00, 0xf9, 0xfe, 0xb7, 0x5c,
-	0xea, 0x7f, 0xfb, 0xd0, 0x52, 0xe2, 0x92, 0x4b, 0xc5, 0x2e, 0x23, 0xcc, 0xff, 0x86, 0x9b, 0x31,
-	0x0a, 0xad, 0x60, 0xb5, 0xd8, 0x0a, 0x0e, 0x00, 0x70, 0xb4, 0x18, 0xc4, 0x61, 0xa8, 0x0c, 0x00,
-	0xb7, 0x90, 0xe3, 0x86, 0xa1, 0xd2, 0x27, 0xd5, 0x7b, 0x99, 0x2c, 0xb6, 0x12, 0xa8, 0x53, 0xef,
-	0x25, 0x2e, 0x69, 0x60, 0x7a, 0xcb, 0x03, 0x65, 0x56, 0xc1, 0x00, 0x13, 0xb2, 0x70, 0xc3, 0x63,
-	0x58, 0x4f, 0x47, 0x98, 0x64, 0x4f, 0x1b, 0x8b, 0xaf, 0x77, 0x92, 0xb2, 0x93, 0x12, 0x4c, 0xbe,
-	0xf5, 0x19, 0x77, 0xcd, 0xcb, 0x93, 0xda, 0x11, 0x08, 0x32, 0x4e, 0x27, 0xc1, 0x07, 0x24, 0xb4,
-	0x66, 0x21, 0x07, 0x17, 0x22, 0x60, 0xbe, 0x50, 0x57, 0xce, 0x1a, 0x86, 0x16, 0x84, 0xfc, 0xca,
-	0x70, 0xc8, 0x4f, 0xa0, 0x93, 0x8b, 0xbd, 0x74, 0x46, 0xd8, 0x7f, 0x7b, 0xa6, 0xe8, 0x2b, 0xca,
-	0xc1, 0x2d, 0xec, 0xa7, 0xff, 0xae, 0xc3, 0x66, 0x55, 0xd1, 0x54, 0x05, 0xd9, 0x01, 0xeb, 0xcb,
-	0xf2, 0xbc, 0x62, 0x01, 0xb0, 0x31, 0x03, 0x80, 0x4b, 0xb3, 0x00, 0xb8, 0x5c, 0x09, 0x80, 0x2b,
-	0xf9, 0xf8, 0x17, 0x62, 0xbc, 0x5a, 0x8e, 0xb1, 0xed, 0x31, 0x4d, 0xd3, 0xd3, 0x35, 0xc0, 0x58,
-	0x4c, 0x68, 0x65, 0x98, 0x50, 0x84, 0x51, 0xb8, 0x09, 0x46, 0xdb, 0x25, 0x18, 0xad, 0x82, 0x86,
-	0x4e, 0x25, 0x34, 0x20, 0x24, 0x2a, 0xa6, 0xa6, 0x12, 0x83, 0xb3, 0xec, 0x1a, 0x4a, 0xa7, 0x93,
-	0x96, 0x3f, 0x95, 0x7c, 0xe4, 0xac, 0x27, 0xe9, 0x34, 0x66, 0xf2, 0x1b, 0xc9, 0x47, 0xf4, 0x0b,
-	0xb8, 0xf5, 0x92, 0xbf, 0x33, 0xed, 0xd6, 0xd6, 0xde, 0x21, 0x40, 0xc4, 0xa4, 0x8c, 0x26, 0xb1,
-	0x4e, 0xfa, 0x9a, 0x2d, 0x20, 0xcb, 0xa1, 0x27, 0x40, 0xf2, 0x87, 0xb2, 0xf6, 0x5c, 0xdd, 0xeb,
-	0xa9, 0x0f, 0x5b, 0xdf, 0x04, 0xba, 0x6e, 0x4b, 0x7a, 0xe6, 0x4f, 0x07, 0x45, 0x0b, 0xea, 0x65,
-	0x0b, 0x74, 0x51, 0x8e, 0xa6, 0x31, 0x4b, 0x31, 0x7c, 0xc9, 0x4d, 0x69, 0xda, 0x87, 0xed, 0x92,
-	0xb6, 0xca, 0x5e, 0xdf, 0xb4, 0xbd, 0x5e, 0x5f, 0xe7, 0xc5, 0x07, 0x18, 0x47, 0x3f, 0x83, 0xcd,
-	0x17, 0x1f, 0x20, 0xfe, 0xe7, 0xb0, 0x71, 0x2e, 0xc6, 0x41, 0x1e, 0xdc, 0xe6, 0x5f, 0xdc, 0xe6,
-	0x7a, 0x3d, 0xc9, 0x1d, 0xcc, 0xf5, 0x2e, 0x34, 0x98, 0x3f, 0x36, 0x63, 0x8c, 0xfe, 0xa4, 0x1f,
-	0x43, 0x37, 0x13, 0x99, 0x55, 0xc9, 0x4c, 0x27, 0xfa, 0x3d, 0x1c, 0xe9, 0x7d, 0xb9, 0xa2, 0x7a,
-	0x95, 0xfa, 0xd0, 0xda, 0xf2, 0x63, 0x68, 0xe7, 0x11, 0xbb, 0x86, 0x60, 0xb1, 0x57, 0x55, 0xb4,
-	0x49, 0xb3, 0xce, 0xef, 0x5e, 0x14, 0x27, 0xfa, 0x43, 0xb8, 0x7b, 0x83, 0x01, 0x0b, 0x2c, 0x2f,
-	0xf6, 0xd0, 0xff, 0xb3, 0xe5, 0x7d, 0xe8, 0x9e, 0x99, 0xfa, 0x4c, 0x0d, 0x2d, 0x14, 0x71, 0xad,
-	0x58, 0xc4, 0xf4, 0x2e, 0xb4, 0x17, 0xf5, 0xaf, 0x07, 0xd0, 0x3e, 0x63, 0xd9, 0x50, 0xdd, 0x85,
-	0x86, 0x9e, 0x1c, 0x93, 0x1d, 0xfa, 0x53, 0x73, 0xb2, 0x69, 0x53, 0x7f, 0xd2, 0x87, 0xb0, 0xfe,
-	0x2c, 0xc1, 0x76, 0x7b, 0xea, 0x23, 0x58, 0x49, 0xd0, 0x1e, 0xe7, 0xc1, 0xf6, 0x69, 0xc7, 0x5c,
-	0x18, 0xb7, 0xb9, 0x66, 0x8d, 0x3e, 0x80, 0x65, 0x64, 0x7c, 0xc0, 0xe3, 0xf1, 0x63, 0xe8, 0xbc,
-	0x8a, 0xe2, 0xf0, 0x22, 0xd7, 0xec, 0x7d, 0x21, 0x15, 0x0f, 0xec, 0xac, 0x92, 0x50, 0xf4, 0x13,
-	0x58, 0x33, 0xfb, 0x16, 0x24, 0xfe, 0x97, 0x70, 0xeb, 0x8c, 0xab, 0xa7, 0xf8, 0x16, 0x4e, 0x37,
-	0x1f, 0xc3, 0x4a, 0xf2, 0x3a, 0x36, 0xf1, 0xea, 0x9e, 0x24, 0xcf, 0xe6, 0xa4, 0x27, 0xe9, 0x9d,
-	0x66, 0xfd, 0xf4, 0xaf, 0x00, 0xf0, 0x38, 0x12, 0xe7, 0x3c, 0x7e, 0xab, 0x41, 0xf2, 0x0d, 0xb4,
-	0x73, 0xef, 0x2d, 0xb2, 0x6b, 0xae, 0x5d, 0x7e, 0xef, 0xf6, 0x6c, 0xbf, 0xa9, 0x78, 0x9c, 0xd1,
-	0xbd, 0xef, 0xff, 0xf6, 0xcf, 0x3f, 0xd6, 0x37, 0xc9, 0xad, 0xfe, 0xdb, 0x07, 0xfd, 0xa9, 0xe4,
-	0xb1, 0x7e, 0xb3, 0x63, 0xdb, 0x25, 0xbf, 0x81, 0xdd, 0x17, 0x4c, 0x71, 0xa9, 0x9e, 0xc7, 0x31,
-	0xc7, 0xa7, 0xd0, 0xd0, 0xe7, 0x38, 0x6c, 0xcc, 0x57, 0xb5, 0x65, 0x16, 0x0a, 0x33, 0x09, 0xdd,
-	0x42, 0x25, 0xeb, 0xa4, 0x93, 0x2a, 0xd1, 0xcf, 0xba, 0x18, 0x36, 0x4a, 0xef, 0x1a, 0x72, 0x90,
-	0x59, 0x5a, 0xf1, 0x76, 0xea, 0x1d, 0xce, 0x5b, 0x36, 0x7a, 0x8e, 0x50, 0x4f, 0x8f, 0x6e, 0xa7,
-	0x7a, 0x98, 0x79, 0xb6, 0xe9, 0x6d, 0x8f, 0x6a, 0xf7, 0xc9, 0x2b, 0x58, 0xd2, 0x8f, 0x1d, 0x32,
-	0xbf, 0x26, 0x7a, 0x9b, 0x76, 0x24, 0xcf, 0x3d, 0x8a, 0xa8, 0x83, 0x92, 0x09, 0x5d, 0x4b, 0x25,
-	0x7b, 0xcc, 0xf7, 0xb5, 0xc4, 0x6b, 0x20, 0xb3, 0xb3, 0x30, 0x39, 0x32, 0x42, 0xe6, 0x8e, 0xc9,
-	0xe9, 0x5d, 0xe6, 0xcc, 0xc5, 0x94, 0xa2, 0xc6, 0x7d, 0xba, 0x9b, 0x6a, 0x8c, 0xd9, 0xbb, 0x5c,
-	0xb9, 0x6a, 0xdd, 0x13, 0x58, 0x2f, 0x0e, 0xbe, 0x64, 0x3f, 0xf3, 0xd0, 0xec, 0x3c, 0x3c, 0x27,
-	0x3a, 0xb3, 0x9a, 0xc6, 0x85, 0xd3, 0x5a, 0x53, 0x00, 0xdd, 0xf2, 0x04, 0x4c, 0x0e, 0x67, 0x75,
-	0xe5, 0x47, 0xe3, 0x39, 0xda, 0x3e, 0x42, 0x6d, 0x87, 0x74, 0xaf, 0x4a, 0x1b, 0x9e, 0xd7, 0xfa,
-	0xbe, 0xaf, 0xe1, 0x4c, 0x5f, 0x70, 0x8c, 0xc7, 0x45, 0xa4, 0x08, 0xcd, 0xb4, 0xce, 0x9b, 0x94,
-	0x7b, 0x37, 0x0c, 0x58, 0xf4, 0x53, 0xd4, 0x7f, 0x8f, 0x1e, 0xe6, 0xf5, 0xcf, 0xea, 0xd1, 0x46,
-	0x0c, 0xa0, 0x95, 0xfe, 0xf4, 0x94, 0xa6, 0x7c, 0xf9, 0x87, 0xab, 0x9e, 0x33, 0xbb, 0x60, 0x54,
-	0x1d, 0xa0, 0xaa, 0x5d, 0x4a, 0x52, 0x55, 0xd2, 0xee, 0x79, 0x54, 0xbb, 0xff, 0x79, 0xcd, 0x14,
-	0xb0, 0x05, 0xd5, 0xf9, 0x55, 0x65, 0x17, 0xca, 0xf0, 0x4b, 0xf7, 0x51, 0xc3, 0x0e, 0xd9, 0xca,
-	0x5f, 0x26, 0x95, 0xf7, 0x06, 0xda, 0xcf, 0xb2, 0xc7, 0xf7, 0x4d, 0x39, 0x4f, 0x32, 0x05, 0xa9,
-	0xec, 0x3b, 0x28, 0x7b, 0x8f, 0x66, 0xb2, 0x73, 0x2f, 0x79, 0xed, 0x1e, 0x86, 0xf5, 0x9b, 0x60,
-	0xb1, 0x49, 0x3f, 0x2b, 0x27, 0x1f, 0x8c, 0xed, 0x3c, 0x1a, 0x67, 0xe2, 0xef, 0xa1, 0xf8, 0x03,
-	0xea, 0xe4, 0x4d, 0xcf, 0x0b, 0x4b, 0x54, 0x40, 0xf6, 0xfe, 0x27, 0xb7, 0x6d, 0x42, 0x55, 0xfc,
-	0x84, 0xd0, 0xdb, 0xcb, 0xf2, 0xa2, 0xf4, 0x7b, 0x01, 0xbd, 0x8d, 0xaa, 0xb6, 0x69, 0x37, 0x55,
-	0x35, 0x4a, 0x76, 0x3c, 0xaa, 0xdd, 0x3f, 0xfd, 0x4b, 0x0b, 0x3a, 0x8f, 0x47, 0x97, 0x22, 0xb0,
-	0xa8, 0xfa, 0x2d, 0x34, 0xed, 0x8f, 0x3d, 0x8b, 0x23, 0x52, 0xfe, 0x59, 0x88, 0xf6, 0x50, 0xd7,
-	0x16, 0xc1, 0x98, 0x33, 0x2d, 0x37, 0xc5, 0x20, 0xe2, 0x01, 0x64, 0x43, 0x22, 0xb1, 0x79, 0x33,
-	0x33, 0x6c, 0xa6, 0x57, 0x99, 0x9d, 0x28, 0x8b, 0x08, 0x57, 0x10, 0xdf, 0x0f, 0xf8, 0x3b, 0xed,
-	0xb2, 0x10, 0xd6, 0x0a, 0xb3, 0x5e, 0xea, 0xb5, 0xaa, 0x79, 0xb3, 0xb7, 0x5f, 0xbd, 0x58, 0x15,
-	0xa3, 0xa2, 0xb6, 0x29, 0x1e, 0xd0, 0x0a, 0xc7, 0xd0, 0xce, 0xcd, 0x7e, 0x69, 0x96, 0xcd, 0xce,
-	0x8f, 0x69, 0x59, 0x56, 0x8c, 0x8a, 0xf4, 0x2e, 0xaa, 0xba, 0x4d, 0x77, 0x66, 0x55, 0x59, 0x45,
-	0x01, 0x6c, 0x94, 0xc0, 0xf2, 0xa6, 0x94, 0x5e, 0x84, 0xaf, 0x15, 0x9e, 0x2c, 0xa1, 0xeb, 0xaf,
-	0xa0, 0x69, 0x47, 0x4a, 0x62, 0x7f, 0xa7, 0x29, 0x8d, 0xad, 0x69, 0x1e, 0x94, 0x67, 0x4f, 0x7a,
-	0x88, 0xe2, 0x1d, 0xba, 0x99, 0x89, 0x97, 0x62, 0x1c, 0xf4, 0x27, 0x26, 0xb3, 0xff, 0x50, 0x83,
-	0x83, 0xd2, 0x1c, 0xf8, 0x4b, 0xa1, 0x26, 0xd9, 0x48, 0x47, 0x3e, 0xc9, 0x89, 0xbe, 0x69, 0xe8,
-	0xeb, 0x1d, 0x2f, 0xde, 0x58, 0x6c, 0xf6, 0x74, 0xbd, 0x68, 0x94, 0xb6, 0xe7, 0x4f, 0xda, 0x9e,
-	0xa2, 0xab, 0xe6, 0xd9, 0xb3, 0x60, 0x08, 0x5d, 0xe8, 0xf9, 0x13, 0xb4, 0xe2, 0x98, 0xde, 0xab,
-	0xf4, 0x7c, 0x51, 0xab, 0x36, 0xed, 0x1c, 0xe0, 0x5c, 0xb1, 0x58, 0xe1, 0x88, 0x45, 0x6c, 0x7b,
-	0xce, 0x0f, 0x66, 0x69, 0xab, 0x29, 0x4c, 0x61, 0xb6, 0x16, 0xe9, 0x46, 0xa6, 0x28, 0xd2, 0x1b,
-	0x92, 0xe0, 0xb6, 0xd2, 0x49, 0x6c, 0x7e, 0x99, 0x3b, 0x19, 0xa8, 0x14, 0x87, 0x36, 0x8b, 0x29,
-	0x24, 0x17, 0xdf, 0x71, 0x2a, 0xef, 0x5b, 0x68, 0xda, 0xff, 0x17, 0x16, 0x43, 0x48, 0xf9, 0x9f,
-	0x88, 0x2a, 0x08, 0x09, 0xc2, 0x11, 0x17, 0xc1, 0x45, 0x38, 0x5c, 0xc1, 0x1f, 0xb6, 0xbf, 0xf8,
-	0x4f, 0x00, 0x00, 0x00, 0xff, 0xff, 0x97, 0x9c, 0x43, 0x6f, 0xe4, 0x19, 0x00, 0x00,
+	// 3228 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x1a, 0x5d, 0x6f, 0x24, 0x47,
+	0x51, 0xe3, 0xf5, 0xd7, 0xd6, 0x7a, 0x7d, 0xbe, 0xb6, 0xcf, 0x1e, 0x8f, 0x7d, 0x3e, 0x5f, 0x5f,
+	0x72, 0x71, 0x22, 0x62, 0x5f, 0x1c, 0x08, 0x28, 0x10, 0x24, 0xdf, 0xe5, 0xe2, 0x1c, 0x3a, 0xa2,
+	0x63, 0x7c, 0x77, 0x44, 0x22, 0x61, 0xd5, 0x3b, 0xd3, 0x5e, 0x0f, 0x37, 0x9e, 0xd9, 0xcc, 0xf4,
+	0x9e, 0x77, 0xef, 0x05, 0x94, 0x57, 0x04, 0x2f, 0xbc, 0x04, 0x09, 0x89, 0x9f, 0xc0, 0x0f, 0xe0,
+	0x57, 0x20, 0x84, 0x90, 0x10, 0x6f, 0xf0, 0x0a, 0xbf, 0x01, 0x55, 0x7f, 0xcc, 0xd7, 0xce, 0x7a,
+	0x73, 0x09, 0xca, 0x5b, 0x57, 0x75, 0x77, 0x55, 0x75, 0x75, 0x7d, 0x75, 0xcd, 0x40, 0x33, 0xe9,
+	0x7b, 0xfb, 0xfd, 0x24, 0x16, 0x31, 0x99, 0x4b, 0xfa, 0x5e, 0xbf, 0xeb, 0x6c, 0xf7, 0xe2, 0xb8,
+	0x17, 0xf2, 0x03, 0xd6, 0x0f, 0x0e, 0x58, 0x14, 0xc5, 0x82, 0x89, 0x20, 0x8e, 0x52, 0xb5, 0xc8,
+	0xf9, 0x5e, 0x2f, 0x10, 0x67, 0x83, 0xee, 0xbe, 0x17, 0x9f, 0x1f, 0x44, 0xbc, 0x3b, 0x08, 0x59,
+	0x1a, 0xc4, 0x07, 0xbd, 0xf8, 0x4d, 0x0d, 0x1c, 0x78, 0x71, 0x94, 0xf2, 0x28, 0x1d, 0xa4, 0x07,
+	0xfd, 0xee, 0x41, 0x2a, 0x98, 0xe0, 0x7a, 0xe7, 0x3b, 0xd3, 0x76, 0x46, 0xbc, 0x1b, 0x72, 0x81,
+	0xdb, 0xbc, 0x38, 0x3a, 0x0d, 0x7a, 0x6a, 0x1f, 0xfd, 0xb5, 0x05, 0x2b, 0x27, 0x83, 0x6e, 0xea,
+	0x25, 0x41, 0x97, 0xbb, 0xfc, 0xb3, 0x01, 0x4f, 0x05, 0x59, 0x87, 0x79, 0x11, 0xf7, 0x03, 0x2f,
+	0xb5, 0xad, 0xdd, 0xc6, 0x5e, 0xd3, 0xd5, 0x10, 0xb9, 0x09, 0x4b, 0x22, 0xee, 0x30, 0xdf, 0x4f,
+	0x78, 0x9a, 0xf2, 0xd4, 0x9e, 0x91, 0xb3, 0x2d, 0x11, 0x1f, 0x19, 0x14, 0xb9, 0x05, 0xed, 0x3e,
+	0x1b, 0x85, 0x31, 0xf3, 0x3b, 0x62, 0xd4, 0xe7, 0xa9, 0xdd, 0x90, 0x6b, 0x96, 0x34, 0xf2, 0x31,
+	0xe2, 0xc8, 0x06, 0x2c, 0x9c, 0x0e, 0xc2, 0xb0, 0x23, 0x86, 0xf6, 0xec, 0xae, 0xb5, 0xb7, 0xe8,
+	0xce, 0x23, 0xf8, 0x78, 0x48, 0xdf, 0x83, 0xab, 0x05, 0x61, 0xd2, 0x3e, 0x9e, 0x96, 0xac, 0xc1,
+	0x9c, 0xe4, 0x6f, 0x5b, 0xbb, 0xd6, 0x5e, 0xd3, 0x55, 0x00, 0x21, 0x30, 0xeb, 0x33, 0xc1, 0xec,
+	0x19, 0x89, 0x94, 0x63, 0x4a, 0x60, 0xe5, 0xa3, 0x38, 0x7a, 0xc4, 0x12, 0x76, 0x9e, 0xea, 0xb3,
+	0xd0, 0x3f, 0xcc, 0x20, 0xd2, 0xe7, 0x0f, 0xa2, 0xd3, 0x38, 0x23, 0xb9, 0x0c, 0x33, 0x81, 0xaf,
+	0xe9, 0xcd, 0x04, 0x3e, 0xd9, 0x84, 0x45, 0xef, 0x8c, 0x05, 0x51, 0x27, 0xf0, 0x25, 0xc1, 0xb6,
+	0xbb, 0x20, 0xe1, 0x07, 0x3e, 0x71, 0x60, 0xd1, 0x8b, 0x83, 0xa8, 0xcb, 0x52, 0x6e, 0x37, 0xe4,
+	0x86, 0x0c, 0x26, 0xd7, 0x01, 0xfa, 0x9c, 0x27, 0x1d, 0x2f, 0x1e, 0x44, 0x42, 0x1e, 0xa5, 0xed,
+	0x36, 0x11, 0x73, 0x0f, 0x11, 0x84, 0xc2, 0x52, 0x3a, 0x8a, 0xbc, 0xb3, 0x24, 0x8e, 0x82, 0x17,
+	0xdc, 0xb7, 0xe7, 0xe4, 0x59, 0x4b, 0x38, 0x72, 0x03, 0x5a, 0xdd, 0x81, 0xf7, 0x8c, 0x8b, 0x4e,
+	0x1a, 0xbc, 0xe0, 0xf6, 0xfc, 0xae, 0xb5, 0x37, 0xe7, 0x82, 0x42, 0x9d, 0x04, 0x2f, 0x38, 0x79,
+	0x1d, 0x56, 0xe4, 0x4d, 0x79, 0x71, 0xd8, 0x79, 0xce, 0x93, 0x34, 0x88, 0x23, 0x1b, 0xa4, 0x1c,
+	0x57, 0x0c, 0xfe, 0xa9, 0x42, 0x93, 0x43, 0x68, 0x25, 0xf1, 0x40, 0xf0, 0x8e, 0x60, 0xdd, 0x90,
+	0xdb, 0xad, 0xdd, 0xc6, 0x5e, 0xeb, 0xf0, 0xea, 0xbe, 0x34, 0xbc, 0x7d, 0x17, 0x67, 0x1e, 0xe3,
+	0x84, 0x0b, 0x49, 0x36, 0xa6, 0xef, 0x00, 0xe4, 0x33, 0x63, 0x7a, 0xb1, 0x61, 0x41, 0xdf, 0xb6,
+	0xbe, 0x6b, 0x03, 0xd2, 0xbf, 0x5b, 0xb0, 0x7a, 0xcc, 0xc5, 0x47, 0xbc, 0x7b, 0x82, 0x56, 0x98,
+	0x69, 0xb6, 0xa8, 0x49, 0xab, 0xac, 0x49, 0x02, 0xb3, 0x82, 0x05, 0xa1, 0xb9, 0x31, 0x1c, 0x93,
+	0x15, 0x68, 0x84, 0x41, 0x57, 0x2b, 0x16, 0x87, 0x68, 0x7b, 0x67, 0x3c, 0xe8, 0x9d, 0x29, 0x7d,
+	0xce, 0xba, 0x1a, 0xaa, 0xd5, 0xc3, 0x7c, 0xbd, 0x1e, 0xaa, 0x7a, 0x5f, 0xa8, 0xd1, 0xbb, 0x0d,
+	0x0b, 0x86, 0xca, 0xa2, 0xa4, 0x62, 0x40, 0x7a, 0x07, 0x56, 0x8e, 0x3c, 0x79, 0xa3, 0x69, 0x76,
+	0xaa, 0x6d, 0x68, 0xe6, 0x56, 0xaf, 0x7c, 0x22, 0x47, 0xd0, 0x1f, 0xc1, 0xfa, 0x31, 0x17, 0x7a,
+	0x93, 0x56, 0x87, 0x72, 0xa4, 0x82, 0xfe, 0x94, 0x52, 0x0d, 0x58, 0x38, 0xe6, 0x4c, 0xf1, 0x98,
+	0xf4, 0x53, 0xd8, 0x18, 0xa3, 0xa5, 0x85, 0xb0, 0x61, 0xa1, 0xcb, 0x42, 0x16, 0x79, 0xdc, 0x10,
+	0xd3, 0x20, 0x7a, 0x48, 0x14, 0x23, 0x5e, 0xd1, 0x52, 0x80, 0xd4, 0xf7, 0xa8, 0xaf, 0xac, 0xb6,
+	0xed, 0xca, 0x31, 0xfd, 0x05, 0x2c, 0xdd, 0x63, 0x61, 0x98, 0xd1, 0x5c, 0x87, 0xf9, 0x84, 0xa7,
+	0x83, 0x50, 0x68, 0x92, 0x1a, 0x42, 0xb3, 0xe4, 0x43, 0xee, 0xa1, 0x31, 0xf1, 0x24, 0xd1, 0x57,
+	0x06, 0x1a, 0x75, 0x3f, 0x49, 0x30, 0x14, 0xf0, 0x54, 0x04, 0xe7, 0x4c, 0xf0, 0x4e, 0x8f, 0xa5,
+	0xfa, 0x06, 0x5b, 0x06, 0x77, 0xcc, 0x52, 0xba, 0x0f, 0x6b, 0x77, 0x47, 0x77, 0xc3, 0xd8, 0x7b,
+	0xf6, 0xa1, 0x3c, 0x5b, 0x21, 0xba, 0xe8, 0xa3, 0x5b, 0xa5, 0xa3, 0x7f, 0x0b, 0xc8, 0x31, 0x17,
+	0xef, 0x8f, 0x22, 0x96, 0x8a, 0x51, 0x51, 0xc2, 0xf3, 0x20, 0xe2, 0x49, 0x16, 0x8b, 0x14, 0x44,
+	0xff, 0x38, 0x03, 0xe4, 0x71, 0xc2, 0xa2, 0x94, 0x79, 0x18, 0x41, 0x0d, 0x71, 0x02, 0xb3, 0xa7,
+	0x49, 0x7c, 0xae, 0x8f, 0x23, 0xc7, 0x68, 0xd5, 0x22, 0xd6, 0x67, 0x98, 0x11, 0x31, 0xaa, 0xeb,
+	0x39, 0x0b, 0x07, 0xc6, 0x9f, 0x15, 0x90, 0x2b, 0x71, 0xb6, 0xa8, 0xc4, 0x2d, 0x68, 0xf6, 0x58,
+	0xda, 0xe9, 0x27, 0x81, 0xc7, 0xa5, 0x03, 0x37, 0xdd, 0xc5, 0x1e, 0x4b, 0x1f, 0x25, 0x41, 0x3e,
+	0x19, 0x06, 0xe7, 0x81, 0xb0, 0xe7, 0xb3, 0xc9, 0x87, 0x08, 0x93, 0x43, 0x0c, 0x1c, 0x91, 0x48,
+	0x98, 0x27, 0xa4, 0x05, 0xb6, 0x0e, 0xd7, 0xb5, 0x2b, 0xde, 0xd3, 0x68, 0x2d, 0xb3, 0x9b, 0xad,
+	0xc3, 0xc3, 0x76, 0x83, 0x88, 0x25, 0x23, 0xe9, 0xe2, 0x4b, 0xae, 0x86, 0x30, 0xd0, 0xf0, 0x61,
+	0x3f, 0x48, 0xb8, 0xdf, 0x61, 0xc2, 0x6e, 0xed, 0x5a, 0x7b, 0x0d, 0xb7, 0xa9, 0x31, 0x47, 0x02,
+	0x45, 0xef, 0xb3, 0x11, 0x4f, 0xec, 0x25, 0x75, 0x20, 0x09, 0xd0, 0xdf, 0x5a, 0x70, 0xa5, 0xc2,
+	0x0a, 0x19, 0xa4, 0xf1, 0x20, 0xc9, 0x4c, 0x48, 0x43, 0x78, 0xdf, 0x6a, 0x24, 0xa3, 0xb6, 0xb9,
+	0x6f, 0x85, 0xc2, 0x98, 0x8d, 0x61, 0xf0, 0x74, 0x10, 0x49, 0x55, 0x9b, 0x30, 0x68, 0x60, 0xd4,
+	0x39, 0x4b, 0x7a, 0xa9, 0x54, 0x5c, 0xd3, 0x95, 0x63, 0xc4, 0xa5, 0x2c, 0x14, 0x5a, 0x65, 0x72,
+	0x4c, 0x0f, 0x60, 0xf3, 0x84, 0x47, 0xbe, 0xcb, 0x2e, 0xea, 0x2f, 0x4e, 0xc6, 0x73, 0x4b, 0x1e,
+	0x5c, 0x8e, 0xe9, 0x27, 0xb0, 0x81, 0x1b, 0x4a, 0xab, 0x73, 0xb3, 0x10, 0xc3, 0x33, 0x96, 0x9e,
+	0x99, 0x83, 0x28, 0x08, 0xc3, 0x84, 0xd1, 0x66, 0x27, 0x0f, 0x5d, 0x32, 0x4c, 0x18, 0xbc, 0x4e,
+	0x56, 0xb4, 0x03, 0xd7, 0x8e, 0xb9, 0x90, 0x06, 0x7a, 0x77, 0xf4, 0x21, 0x4b, 0xcf, 0x0a, 0xa2,
+	0x14, 0x28, 0xcb, 0x31, 0x39, 0x84, 0x6b, 0x32, 0x65, 0x9d, 0x06, 0x98, 0xb7, 0x72, 0x81, 0x24,
+	0xf1, 0x45, 0x77, 0x15, 0x27, 0x3f, 0x08, 0xc2, 0xb0, 0x20, 0x2b, 0xe5, 0xb0, 0x51, 0x60, 0xf0,
+	0x65, 0x7c, 0xe0, 0x2b, 0xb1, 0x79, 0x0b, 0xb6, 0x8e, 0xb9, 0x28, 0x60, 0xa6, 0x9e, 0x86, 0xfe,
+	0xb3, 0x01, 0x6d, 0x29, 0x57, 0xa6, 0xcf, 0xba, 0x33, 0xdf, 0x80, 0x56, 0x9f, 0x25, 0x3c, 0x12,
+	0x1d, 0x39, 0xa5, 0x8d, 0x42, 0xa1, 0x90, 0x43, 0xe1, 0x14, 0x8d, 0xd2, 0x29, 0xea, 0x5d, 0xa9,
+	0x98, 0x49, 0xe7, 0x2a, 0x99, 0x74, 0x1b, 0x9a, 0x22, 0x38, 0xe7, 0xa9, 0x60, 0xe7, 0x7d, 0xe9,
+	0x49, 0x0d, 0x37, 0x47, 0x94, 0x92, 0xca, 0x42, 0x39, 0xa9, 0x5c, 0x07, 0x90, 0x65, 0x50, 0x27,
+	0x89, 0x63, 0xa1, 0x43, 0x79, 0x53, 0x62, 0xdc, 0x38, 0x16, 0xb8, 0x53, 0x0c, 0x53, 0x35, 0xd9,
+	0x54, 0x41, 0x53, 0x0c, 0x53, 0x39, 0x85, 0x21, 0xee, 0x39, 0x8f, 0x84, 0x9e, 0x05, 0x1d, 0xe2,
+	0x24, 0x4a, 0x2e, 0x38, 0x82, 0xe5, 0xac, 0xdc, 0x52, 0x6b, 0x5a, 0xd2, 0x8d, 0x9d, 0xfd, 0x0c,
+	0xad, 0x9c, 0x59, 0x8d, 0x71, 0x8f, 0xdb, 0xf6, 0x8a, 0x20, 0x2a, 0x42, 0x86, 0x2b, 0xe3, 0x98,
+	0x12, 0x40, 0xce, 0x41, 0xda, 0x39, 0x0d, 0x22, 0x16, 0x06, 0x62, 0x64, 0xb7, 0xe5, 0xd5, 0x42,
+	0x90, 0x7e, 0xa0, 0x31, 0xe4, 0x87, 0xb0, 0x54, 0xb8, 0xfb, 0xd4, 0xf6, 0x65, 0x26, 0x77, 0x74,
+	0xf8, 0xa8, 0x71, 0x07, 0xb7, 0xb4, 0x9e, 0xfe, 0xb7, 0x01, 0xab, 0x75, 0x4e, 0x53, 0x77, 0xc9,
+	0x36, 0x18, 0x5d, 0x56, 0x2b, 0x1f, 0x13, 0x4a, 0x1b, 0x63, 0xa1, 0x74, 0x76, 0x3c, 0x94, 0xce,
+	0xd5, 0x86, 0xd2, 0xf9, 0xe2, 0xfd, 0x97, 0xee, 0x78, 0xa1, 0x7a, 0xc7, 0x26, 0x5b, 0xa9, 0x2b,
+	0x94, 0xe3, 0x2c, 0x26, 0x34, 0xf3, 0x98, 0x50, 0x0e, 0xc8, 0x70, 0x59, 0x40, 0x6e, 0x55, 0x02,
+	0x72, 0x5d, 0x68, 0x58, 0xaa, 0x0d, 0x0d, 0x32, 0x4c, 0x0a, 0x26, 0x06, 0xa9, 0xbc, 0x9c, 0x39,
+	0x57, 0x43, 0x68, 0x4e, 0x48, 0x7f, 0x90, 0x72, 0xdf, 0x5e, 0x56, 0xe6, 0xd4, 0x63, 0xe9, 0x93,
+	0x94, 0xfb, 0x98, 0x10, 0xbb, 0xe8, 0x51, 0x1d, 0xed, 0x11, 0x57, 0xe4, 0xd1, 0x5b, 0xdd, 0x3c,
+	0xff, 0x61, 0x6d, 0x5c, 0x48, 0xaa, 0x71, 0x62, 0xaf, 0x48, 0x12, 0x4b, 0x79, 0x5a, 0x8d, 0x93,
+	0x4a, 0xa8, 0xbf, 0x3a, 0x31, 0xd4, 0x93, 0x62, 0xa8, 0x7f, 0x1b, 0xae, 0x7e, 0xc4, 0x2f, 0x74,
+	0xd5, 0x60, 0x1c, 0x7f, 0x07, 0xa0, 0xcf, 0xd2, 0xb4, 0x7f, 0x96, 0xa0, 0xc7, 0x59, 0xc6, 0x7b,
+	0x0d, 0x86, 0xee, 0x03, 0x29, 0x6e, 0xca, 0xab, 0x8c, 0xfa, 0x92, 0x85, 0x86, 0xb0, 0xf6, 0x24,
+	0xc2, 0xe3, 0x54, 0xf8, 0x4c, 0xdc, 0x51, 0x91, 0x60, 0xa6, 0x2a, 0x01, 0x46, 0x04, 0x7f, 0x90,
+	0xb0, 0x2c, 0xa9, 0xcc, 0xba, 0x19, 0x4c, 0x0f, 0xe0, 0x5a, 0x85, 0x5b, 0x6d, 0xc9, 0xb2, 0x68,
+	0x4a, 0x16, 0x3c, 0xce, 0xc3, 0x97, 0x10, 0x8e, 0xbe, 0x09, 0xab, 0x0f, 0x5f, 0x82, 0xfc, 0x4f,
+	0xe0, 0xca, 0x49, 0xd0, 0x8b, 0x8a, 0x91, 0x75, 0xf2, 0xc1, 0x8d, 0xa3, 0xcd, 0x28, 0xc3, 0xc5,
+	0x31, 0x96, 0xba, 0x2c, 0xec, 0xe9, 0x6a, 0x0c, 0x87, 0xf4, 0x36, 0xac, 0xe4, 0x24, 0x73, 0x17,
+	0x1d, 0x4b, 0x83, 0xbf, 0x84, 0x5d, 0x5c, 0x57, 0xf0, 0xe8, 0x47, 0x99, 0x0e, 0x8d, 0x2c, 0xdf,
+	0x87, 0x56, 0x31, 0x5d, 0x58, 0x32, 0x52, 0x6d, 0xd6, 0x45, 0x0c, 0xb9, 0xde, 0x2d, 0xae, 0x9e,
+	0x76, 0x4f, 0xf4, 0xbb, 0x70, 0xf3, 0x12, 0x01, 0xa6, 0x48, 0x5e, 0x4e, 0xe0, 0xdf, 0xb0, 0xe4,
+	0x07, 0xb0, 0x72, 0xac, 0x83, 0x43, 0x26, 0x68, 0x29, 0x82, 0x58, 0xe5, 0x08, 0x42, 0x6f, 0x42,
+	0x6b, 0x5a, 0xf2, 0xfc, 0x93, 0x05, 0xad, 0x63, 0x96, 0x3f, 0x0e, 0x56, 0xa0, 0x81, 0x15, 0xb0,
+	0x5a, 0x82, 0x43, 0xc4, 0xe4, 0x55, 0x33, 0x0e, 0xcb, 0x81, 0xa9, 0x51, 0x09, 0x4c, 0x3a, 0xaa,
+	0xc8, 0xc4, 0x38, 0x9b, 0x45, 0x95, 0xbb, 0xe8, 0x21, 0x37, 0xa0, 0x25, 0x65, 0x55, 0xaf, 0x67,
+	0x1d, 0x65, 0x01, 0xa5, 0x55, 0x18, 0x8c, 0x29, 0xb8, 0x40, 0x85, 0x90, 0xfc, 0x4d, 0xb4, 0xd4,
+	0x63, 0xe9, 0x7d, 0x83, 0xa3, 0xef, 0xc0, 0xf2, 0x7d, 0x95, 0xd7, 0x8c, 0xcc, 0xaf, 0xc0, 0xbc,
+	0xca, 0x74, 0xb2, 0xaa, 0x6e, 0x1d, 0x2e, 0x69, 0x7d, 0xcb, 0x65, 0xae, 0x9e, 0xa3, 0x6f, 0xc1,
+	0x9c, 0x44, 0xbc, 0xc4, 0x13, 0xfc, 0x36, 0x2c, 0x3d, 0xea, 0x27, 0xf1, 0x69, 0xa1, 0xd0, 0x09,
+	0x83, 0x54, 0xf0, 0xc8, 0xd4, 0x69, 0x0a, 0xa2, 0xaf, 0x41, 0x5b, 0xaf, 0x9b, 0xe2, 0x77, 0xef,
+	0xc1, 0xd5, 0x63, 0x2e, 0xee, 0xc9, 0x9e, 0x45, 0xb6, 0x78, 0x0f, 0xe6, 0x55, 0x17, 0x43, 0x9b,
+	0xcb, 0xca, 0xbe, 0x6a, 0x6f, 0xa8, 0x7c, 0x8c, 0x2b, 0xf5, 0x3c, 0xfd, 0x8b, 0x05, 0x4e, 0xc5,
+	0x04, 0x4f, 0xd8, 0xe9, 0x37, 0x62, 0x7c, 0xe4, 0x55, 0x58, 0x66, 0x61, 0x18, 0x5f, 0x70, 0x5f,
+	0xc5, 0x7b, 0xd3, 0x0c, 0x69, 0x6b, 0xac, 0x0c, 0xf8, 0x3a, 0xd9, 0x24, 0x81, 0x27, 0x4c, 0x33,
+	0x44, 0x41, 0xd8, 0x25, 0x39, 0x67, 0xc3, 0xce, 0x29, 0x37, 0xd9, 0x75, 0xfe, 0x9c, 0x0d, 0x3f,
+	0xe0, 0x9c, 0xfe, 0x6d, 0x06, 0xb6, 0x6a, 0xcf, 0xf4, 0x7f, 0xab, 0x8d, 0x0b, 0xb7, 0xd1, 0xb8,
+	0xec, 0x5d, 0x38, 0x3b, 0xf6, 0x2e, 0x2c, 0x66, 0xc8, 0xb9, 0x72, 0x86, 0x2c, 0x9a, 0xf9, 0xfc,
+	0xa5, 0x66, 0xbe, 0x30, 0xdd, 0xcc, 0x17, 0xc7, 0xcd, 0xbc, 0xec, 0x64, 0xcd, 0x8a, 0x93, 0x15,
+	0x1f, 0xac, 0xa7, 0xdc, 0x94, 0x0e, 0xd9, 0x83, 0x15, 0xf5, 0x3a, 0x80, 0xeb, 0x4f, 0x79, 0x12,
+	0x9c, 0x8e, 0x1e, 0x44, 0x3e, 0x1f, 0x62, 0x61, 0x27, 0x6d, 0xd5, 0x1b, 0x19, 0x6b, 0xb9, 0x01,
+	0x2d, 0xac, 0x82, 0x3a, 0xa5, 0xd2, 0x1d, 0x10, 0xa5, 0x33, 0xfc, 0x16, 0x34, 0x45, 0xdc, 0x29,
+	0x3d, 0xec, 0x17, 0x45, 0xac, 0x27, 0xa5, 0x4e, 0xfb, 0x2c, 0x48, 0xec, 0x86, 0xb1, 0x70, 0x84,
+	0xe8, 0xbf, 0x2c, 0xd8, 0x99, 0xc4, 0x57, 0xdf, 0xe8, 0xd7, 0x66, 0x2c, 0xcb, 0x90, 0xd4, 0x94,
+	0xe9, 0x0a, 0xc2, 0x30, 0x25, 0x86, 0xa9, 0x2e, 0xd2, 0x71, 0x48, 0xde, 0x83, 0xb6, 0x1f, 0xa4,
+	0x1e, 0x0a, 0x16, 0x79, 0x01, 0x4f, 0xed, 0x39, 0x19, 0x1d, 0x36, 0xb4, 0x43, 0x48, 0xf9, 0xde,
+	0xcf, 0x16, 0x8c, 0xdc, 0xf2, 0x6a, 0xcc, 0xe7, 0xea, 0x4c, 0xdc, 0x97, 0x37, 0xdc, 0x76, 0x33,
+	0x98, 0x7e, 0x61, 0xc1, 0x4a, 0x75, 0x3f, 0x46, 0x90, 0x67, 0x41, 0x64, 0x3a, 0x4e, 0x72, 0x3c,
+	0xa9, 0x33, 0x82, 0x31, 0x48, 0xca, 0x6d, 0x5e, 0xed, 0x12, 0x90, 0x05, 0xe9, 0x30, 0x2b, 0x48,
+	0x87, 0xb8, 0xdb, 0xe7, 0xb2, 0xcd, 0xa4, 0x7d, 0x46, 0x41, 0x63, 0xa2, 0x2d, 0x16, 0x44, 0x3b,
+	0x81, 0xeb, 0x95, 0xf4, 0x76, 0x84, 0x86, 0xc7, 0x93, 0x4b, 0xde, 0xa6, 0x53, 0x33, 0xcf, 0x3e,
+	0x90, 0x47, 0x71, 0x1c, 0xe2, 0x03, 0x9c, 0x7f, 0x99, 0x72, 0x44, 0xc0, 0x6a, 0x69, 0xbd, 0xbe,
+	0xf9, 0xef, 0xc0, 0x22, 0xd3, 0xdd, 0x28, 0x1d, 0xaa, 0x4d, 0x74, 0xc2, 0xd5, 0xba, 0x78, 0x31,
+	0x9b, 0xb2, 0xa5, 0xe4, 0x36, 0xcc, 0xa5, 0x82, 0x09, 0xe5, 0xdf, 0x18, 0x1f, 0xf3, 0x3d, 0xd8,
+	0x54, 0x4a, 0x5d, 0x35, 0x8d, 0xb7, 0x42, 0xc6, 0x09, 0x5d, 0x52, 0xd9, 0x7c, 0x1b, 0x16, 0xfa,
+	0x3c, 0xf2, 0x83, 0xa8, 0x67, 0xcf, 0x4c, 0x7d, 0x95, 0x98, 0xa5, 0xe4, 0x10, 0xe6, 0x3f, 0x1b,
+	0xf0, 0x01, 0xf7, 0xed, 0xc6, 0xd4, 0x4d, 0x7a, 0x25, 0xfd, 0xb3, 0x05, 0xcd, 0x4c, 0x5e, 0x94,
+	0xc8, 0xf0, 0xd5, 0x6d, 0x45, 0x43, 0x7b, 0x3d, 0xa3, 0xad, 0xde, 0x2f, 0x1a, 0x92, 0x3b, 0x98,
+	0xf7, 0x0c, 0x77, 0x34, 0xf4, 0x0e, 0x05, 0xa2, 0x2d, 0x64, 0x3a, 0x55, 0x4d, 0xdb, 0x5c, 0x71,
+	0x14, 0xda, 0xe7, 0x41, 0xd4, 0xa9, 0xf6, 0x7c, 0x5a, 0xe7, 0x41, 0x64, 0x0a, 0x09, 0xb9, 0x86,
+	0x0d, 0x0b, 0x6b, 0xe6, 0xf5, 0x1a, 0x36, 0x34, 0x6b, 0xb0, 0x27, 0x68, 0x7a, 0x2f, 0x27, 0x22,
+	0x4e, 0x58, 0xef, 0x6b, 0xf4, 0x04, 0x8f, 0x60, 0x63, 0x8c, 0x56, 0x5e, 0x7b, 0x3c, 0xe3, 0x23,
+	0x6d, 0x98, 0x38, 0xcc, 0x5f, 0x64, 0xaa, 0xf6, 0x54, 0x00, 0x15, 0xe0, 0x54, 0x48, 0xbc, 0x1f,
+	0x9c, 0x9e, 0x4e, 0x17, 0xa9, 0x12, 0x78, 0x66, 0x2e, 0x0f, 0x3c, 0x8d, 0x72, 0xe0, 0xa1, 0x17,
+	0x70, 0xad, 0xc2, 0xf5, 0xde, 0x19, 0x8b, 0x7a, 0x79, 0x6b, 0xd2, 0x2a, 0x3c, 0xf6, 0xf4, 0x51,
+	0x66, 0xf2, 0xa3, 0x6c, 0x41, 0x33, 0x0e, 0xfd, 0x4e, 0xde, 0xab, 0x5b, 0x72, 0x17, 0xe3, 0xd0,
+	0x7f, 0x8a, 0x30, 0x4e, 0x46, 0xfc, 0x42, 0x4f, 0xce, 0xaa, 0xc9, 0x88, 0x5f, 0xc8, 0x49, 0xfa,
+	0x10, 0x6c, 0x55, 0x34, 0x28, 0xde, 0xb2, 0x8d, 0xf5, 0xd5, 0xf5, 0xff, 0x0f, 0x0b, 0x36, 0x6b,
+	0xc8, 0xe5, 0xd9, 0xf6, 0x2b, 0xb7, 0xd4, 0x7c, 0xde, 0x0f, 0x63, 0x7c, 0xcd, 0xe9, 0x92, 0xd0,
+	0xc0, 0x78, 0x3a, 0x35, 0xee, 0x64, 0xd1, 0x4d, 0x4f, 0x3e, 0x1e, 0xd6, 0xf5, 0xd6, 0x8a, 0xfd,
+	0x6c, 0xf5, 0xe8, 0x36, 0x20, 0x3e, 0x28, 0x07, 0xfd, 0x5e, 0xc2, 0x7c, 0x8e, 0xb4, 0x54, 0x6a,
+	0x6d, 0x6a, 0xcc, 0xe3, 0x21, 0xfd, 0x01, 0x90, 0x47, 0x9c, 0x27, 0x27, 0x5e, 0x9c, 0xf0, 0xbc,
+	0x3e, 0xbc, 0x0d, 0x73, 0x7d, 0x6e, 0x9a, 0xae, 0x85, 0xf8, 0x61, 0x56, 0xba, 0x6a, 0x9a, 0xfe,
+	0x0a, 0x9d, 0xd4, 0x20, 0xc7, 0x3e, 0x1f, 0xac, 0xc1, 0x5c, 0x8a, 0x13, 0xf2, 0xf0, 0x96, 0xab,
+	0x00, 0x14, 0xbf, 0xcb, 0xa2, 0xd4, 0xf4, 0xa5, 0x71, 0x2c, 0x5f, 0xcf, 0x2c, 0x8a, 0xb8, 0xdf,
+	0x19, 0x44, 0x22, 0x08, 0xe5, 0x91, 0x1b, 0x6e, 0x4b, 0xe1, 0x9e, 0x20, 0x4a, 0x66, 0x31, 0x09,
+	0xea, 0xef, 0x28, 0x1a, 0x3a, 0xfc, 0x4f, 0x0b, 0xe0, 0xa8, 0x1f, 0x9c, 0xf0, 0xe4, 0x39, 0x3a,
+	0xe7, 0xa7, 0xd0, 0x2a, 0x7c, 0x97, 0x20, 0x26, 0x75, 0x55, 0xbf, 0x0b, 0x39, 0x26, 0x04, 0xd5,
+	0x7c, 0xc4, 0xa0, 0x9b, 0x9f, 0xff, 0xf5, 0xdf, 0xbf, 0x9b, 0x59, 0x25, 0x57, 0x0f, 0x9e, 0xbf,
+	0x75, 0x30, 0x48, 0x79, 0x82, 0x5f, 0xcf, 0x64, 0x53, 0x89, 0xfc, 0x1c, 0x36, 0x1e, 0x32, 0xc1,
+	0x53, 0xf1, 0x20, 0x49, 0xb8, 0x54, 0x71, 0x37, 0xe4, 0xb2, 0x95, 0x36, 0x99, 0xd5, 0x9a, 0x9e,
+	0x28, 0x75, 0xdc, 0xe8, 0x9a, 0x64, 0xb2, 0x4c, 0x96, 0x32, 0x26, 0xf8, 0xf9, 0x23, 0x81, 0x2b,
+	0x95, 0xfe, 0x3f, 0xb9, 0x9e, 0x4b, 0x5a, 0xf3, 0x8d, 0xc1, 0xd9, 0x99, 0x34, 0xad, 0xf9, 0xec,
+	0x4a, 0x3e, 0x0e, 0xbd, 0x96, 0xf1, 0x31, 0xc1, 0x0e, 0x97, 0xbd, 0x6b, 0xbd, 0x41, 0x1e, 0xc1,
+	0x2c, 0x7e, 0x14, 0x20, 0x93, 0xeb, 0x5e, 0x67, 0x55, 0x4f, 0x15, 0x3f, 0x1e, 0x50, 0x5b, 0x52,
+	0x26, 0xb4, 0x9d, 0x51, 0xf6, 0x58, 0x18, 0x22, 0xc5, 0x17, 0x40, 0xc6, 0x3b, 0xbd, 0x64, 0x57,
+	0x13, 0x99, 0xd8, 0x04, 0x76, 0x76, 0x0a, 0x2b, 0x6a, 0x72, 0x03, 0xa5, 0x92, 0xe3, 0x36, 0xdd,
+	0xc8, 0x38, 0x26, 0xec, 0xa2, 0x50, 0x92, 0x23, 0xef, 0x33, 0x58, 0x2e, 0xb7, 0x75, 0xc9, 0x76,
+	0xae, 0xa1, 0xf1, 0x6e, 0xef, 0x84, 0xdb, 0x19, 0xe7, 0xd4, 0x2b, 0xed, 0x46, 0x4e, 0x11, 0xac,
+	0x54, 0xfb, 0xbb, 0x64, 0x67, 0x9c, 0x57, 0xb1, 0xf1, 0x3b, 0x81, 0xdb, 0x2b, 0x92, 0xdb, 0x0e,
+	0xdd, 0xac, 0xe3, 0x26, 0xf7, 0x23, 0xbf, 0xcf, 0x2d, 0xd9, 0xb1, 0x2e, 0x29, 0xc6, 0xe3, 0x41,
+	0x5f, 0x10, 0x9a, 0x73, 0x9d, 0xd4, 0x07, 0x76, 0x2e, 0xc9, 0xb9, 0xf4, 0x75, 0xc9, 0xff, 0x16,
+	0xdd, 0x29, 0xf2, 0x1f, 0xe7, 0x83, 0x42, 0x74, 0xa0, 0x99, 0x7d, 0xa2, 0xcd, 0x4c, 0xbe, 0xfa,
+	0x05, 0xd9, 0xb1, 0xc7, 0x27, 0x34, 0xab, 0xeb, 0x92, 0xd5, 0x06, 0x25, 0x19, 0xab, 0xd4, 0xac,
+	0x79, 0xd7, 0x7a, 0xe3, 0x8e, 0xa5, 0x1d, 0x38, 0x4b, 0xb6, 0x13, 0xbd, 0xca, 0x4c, 0x54, 0xdf,
+	0xf7, 0x74, 0x5b, 0x72, 0x58, 0x27, 0x6b, 0xc5, 0xc3, 0x64, 0xf4, 0x3e, 0x85, 0xd6, 0xfd, 0xfc,
+	0x23, 0xd5, 0x65, 0x36, 0x4f, 0x72, 0x06, 0x19, 0xed, 0x1b, 0x92, 0xf6, 0x26, 0xcd, 0x69, 0x17,
+	0xbe, 0x78, 0xa1, 0x7a, 0x98, 0xf4, 0x5f, 0xf5, 0xda, 0xd6, 0xe6, 0x67, 0xe8, 0x14, 0x2f, 0xe3,
+	0x5a, 0xf1, 0xbd, 0x9d, 0x93, 0xbf, 0x25, 0xc9, 0x5f, 0xa7, 0x76, 0x51, 0xf4, 0x22, 0x31, 0xc5,
+	0x02, 0xf2, 0xef, 0x64, 0x64, 0xcb, 0x18, 0x54, 0xcd, 0xa7, 0x36, 0x67, 0x33, 0xb7, 0x8b, 0xca,
+	0x77, 0x35, 0xba, 0x25, 0x59, 0x5d, 0xa3, 0x2b, 0x19, 0x2b, 0x5f, 0xad, 0x40, 0x16, 0x77, 0xa5,
+	0x0f, 0xfd, 0xb8, 0x50, 0xf3, 0xbc, 0xf4, 0x35, 0x90, 0xa7, 0x70, 0x75, 0x2c, 0x69, 0x92, 0x1b,
+	0xb9, 0x40, 0xb5, 0xd9, 0xd9, 0xd9, 0x9d, 0xbc, 0x40, 0xd1, 0x3d, 0xfc, 0xfd, 0x32, 0x2c, 0x1d,
+	0xf9, 0xe7, 0x41, 0x64, 0x22, 0xfe, 0xc7, 0xb0, 0x68, 0x3e, 0xd8, 0x4e, 0x17, 0xb3, 0xfa, 0x69,
+	0x97, 0x3a, 0x52, 0x0f, 0x6b, 0x44, 0xda, 0x23, 0x43, 0xba, 0x59, 0x7c, 0x24, 0x1e, 0x40, 0xde,
+	0x21, 0x25, 0xc6, 0xa6, 0xc7, 0x3a, 0xad, 0xce, 0x66, 0xcd, 0x4c, 0x5d, 0xf4, 0x2d, 0x91, 0x3f,
+	0x88, 0xf8, 0x05, 0xea, 0x3a, 0x86, 0x76, 0xa9, 0xd1, 0x99, 0xdd, 0x68, 0x5d, 0xb3, 0xd5, 0xd9,
+	0xae, 0x9f, 0xac, 0xb3, 0x9f, 0x32, 0xb7, 0x81, 0xdc, 0x80, 0x0c, 0x7b, 0xd0, 0x2a, 0x34, 0x3e,
+	0x33, 0x0f, 0x18, 0x6f, 0x9e, 0x3a, 0x4e, 0xdd, 0x94, 0x66, 0x75, 0x53, 0xb2, 0xda, 0xa2, 0xeb,
+	0xe3, 0xac, 0x0c, 0xa3, 0x08, 0xae, 0x54, 0x02, 0xf9, 0x65, 0xee, 0x36, 0x2d, 0xf6, 0xd7, 0x68,
+	0xb2, 0x12, 0xf9, 0x7f, 0x06, 0x8b, 0xa6, 0x9f, 0x4a, 0xcc, 0xb7, 0xd6, 0x4a, 0xcf, 0xd6, 0xd9,
+	0x18, 0xc3, 0x6b, 0xf2, 0x3b, 0x92, 0xbc, 0x4d, 0x57, 0x73, 0xf2, 0x69, 0xd0, 0x8b, 0x0e, 0xce,
+	0xb4, 0xd7, 0xfd, 0xc6, 0x1a, 0x7b, 0x25, 0xfe, 0x34, 0x10, 0x67, 0x79, 0x3f, 0x93, 0xbc, 0x56,
+	0x20, 0x7d, 0x59, 0xc7, 0xd3, 0xd9, 0x9b, 0xbe, 0xb0, 0x5c, 0x88, 0xd0, 0xe5, 0xb2, 0x50, 0x28,
+	0xcf, 0x17, 0x28, 0x4f, 0x59, 0x55, 0x93, 0xe4, 0x99, 0xd2, 0x81, 0x9d, 0xaa, 0xf9, 0x7d, 0x29,
+	0xc5, 0x1e, 0xbd, 0x55, 0xab, 0xf9, 0x32, 0x57, 0x14, 0xed, 0x04, 0xe0, 0x44, 0xb0, 0x44, 0xc8,
+	0x06, 0x1f, 0x31, 0xa5, 0x43, 0xb1, 0x2d, 0xe8, 0xac, 0x95, 0x91, 0x65, 0x5f, 0xa4, 0x57, 0x72,
+	0x46, 0x7d, 0x5c, 0xa0, 0x2e, 0xb7, 0x99, 0xf5, 0x01, 0x27, 0xbb, 0xb9, 0x5d, 0x0a, 0x1f, 0x85,
+	0x96, 0xa1, 0x89, 0x77, 0xa4, 0x70, 0xbf, 0xbd, 0x8c, 0xde, 0xc7, 0xb0, 0x68, 0xfe, 0x11, 0x9a,
+	0x1e, 0x42, 0xaa, 0x7f, 0x13, 0xd5, 0x85, 0x90, 0x28, 0xf6, 0x79, 0x80, 0xd4, 0x3e, 0x81, 0xd5,
+	0x9a, 0x56, 0x1d, 0xb9, 0x59, 0xaf, 0xf2, 0x42, 0x6b, 0xd2, 0xa1, 0x97, 0x2d, 0xd1, 0x31, 0x96,
+	0xc3, 0x7a, 0x7d, 0xe7, 0x88, 0xbc, 0xa2, 0x77, 0x5f, 0xda, 0xd0, 0x72, 0x5e, 0x9d, 0xb2, 0x4a,
+	0xb3, 0x39, 0x83, 0xf5, 0xfa, 0x06, 0x49, 0xc6, 0xe6, 0xd2, 0xfe, 0xc9, 0x97, 0x37, 0x78, 0x72,
+	0x2c, 0x13, 0x4f, 0xa1, 0x11, 0x42, 0x8a, 0xed, 0x8e, 0x72, 0x33, 0xc5, 0x71, 0xea, 0xa6, 0x34,
+	0xa1, 0x27, 0xf2, 0x67, 0x92, 0xca, 0xeb, 0x33, 0x2b, 0xa5, 0xeb, 0x9f, 0xe6, 0xce, 0xce, 0xa4,
+	0x69, 0x45, 0xf4, 0x8e, 0x45, 0x3e, 0x86, 0x55, 0x7c, 0x38, 0x57, 0xe9, 0xde, 0xac, 0xdf, 0x58,
+	0x78, 0x63, 0x3b, 0xdb, 0xf5, 0x4b, 0xd4, 0x83, 0xf8, 0x8e, 0x45, 0xee, 0x41, 0x1b, 0x4f, 0x9e,
+	0x3d, 0xc5, 0x26, 0xdb, 0xe1, 0x66, 0xf5, 0x31, 0x96, 0x25, 0xb3, 0xee, 0xbc, 0xfc, 0x15, 0xea,
+	0xed, 0xff, 0x0d, 0x00, 0xd2, 0x74, 0x6a, 0x36, 0x78, 0x28, 0x00, 0x00,
 }
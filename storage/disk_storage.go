@@ -26,6 +26,7 @@ import (
 	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/syndtr/goleveldb/leveldb/filter"
 	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
 )
 
 // DiskStorage the nodes in trie.
@@ -120,6 +121,23 @@ func (storage *DiskStorage) Del(key []byte) error {
 	return storage.db.Delete(key, nil)
 }
 
+// Iterate calls fn on the key-value entries in Storage from the start key in key order,
+// it stops at the first error of fn. The pending batch writes are not visited.
+func (storage *DiskStorage) Iterate(start []byte, fn func(key []byte, value []byte) error) error {
+	iter := storage.db.NewIterator(&util.Range{Start: start}, nil)
+	defer iter.Release()
+
+	for iter.Next() {
+		// the iterator reuses its buffers.
+		key := append([]byte{}, iter.Key()...)
+		value := append([]byte{}, iter.Value()...)
+		if err := fn(key, value); err != nil {
+			return err
+		}
+	}
+	return iter.Error()
+}
+
 // Close levelDB
 func (storage *DiskStorage) Close() error {
 	return storage.db.Close()
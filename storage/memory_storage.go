@@ -19,6 +19,7 @@
 package storage
 
 import (
+	"sort"
 	"sync"
 
 	"github.com/alexlisong/go-nebulas/util/byteutils"
@@ -65,6 +66,37 @@ func (db *MemoryStorage) Del(key []byte) error {
 	return nil
 }
 
+// Iterate calls fn on the key-value entries in Storage from the start key in key order,
+// it stops at the first error of fn.
+func (db *MemoryStorage) Iterate(start []byte, fn func(key []byte, value []byte) error) error {
+	// the hex keys are in the same order as the keys.
+	from := byteutils.Hex(start)
+	keys := []string{}
+	db.data.Range(func(k, v interface{}) bool {
+		if k.(string) >= from {
+			keys = append(keys, k.(string))
+		}
+		return true
+	})
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		v, ok := db.data.Load(k)
+		if !ok {
+			// deleted by fn.
+			continue
+		}
+		key, err := byteutils.FromHex(k)
+		if err != nil {
+			return err
+		}
+		if err := fn(key, v.([]byte)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // EnableBatch enable batch write.
 func (db *MemoryStorage) EnableBatch() {
 }
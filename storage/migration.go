@@ -0,0 +1,323 @@
+// Copyright (C) 2017 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+
+	"github.com/alexlisong/go-nebulas/util/byteutils"
+)
+
+// SchemaVersionKey is the storage key of the persisted schema version record.
+var SchemaVersionKey = []byte("storage_schema_version")
+
+// migrationProgressPrefix prefix of the per-step progress records.
+const migrationProgressPrefix = "storage_migration_progress_"
+
+// migration errors
+var (
+	ErrInvalidMigrationID    = errors.New("invalid migration id")
+	ErrDuplicatedMigrationID = errors.New("duplicated migration id")
+	ErrNilMigrationUp        = errors.New("migration has no up function")
+	ErrSchemaVersionTooNew   = errors.New("storage schema version is newer than the node supports")
+)
+
+// MigrationStep is a versioned storage migration. ID is the schema version
+// the storage reaches once the step is done, steps are applied in ID order.
+type MigrationStep struct {
+	ID   uint64
+	Name string
+
+	// Up rewrites the storage. It must be idempotent in respect to the last
+	// committed progress, a crashed step is restarted from its cursor.
+	// Up runs with the storage batch enabled, the writes are flushed together
+	// with the cursor by progress.Commit, or with the schema version once Up returns.
+	Up func(stor Storage, progress *MigrationProgress) error
+}
+
+// MigrationProgress is the persisted progress of a migration step.
+type MigrationProgress struct {
+	stor   Storage
+	key    []byte
+	cursor []byte
+}
+
+// Cursor return the last committed cursor, nil if the step has not committed yet.
+func (p *MigrationProgress) Cursor() []byte {
+	return p.cursor
+}
+
+// Commit records the cursor and flushes it together with the pending batch writes.
+func (p *MigrationProgress) Commit(cursor []byte) error {
+	if err := p.stor.Put(p.key, cursor); err != nil {
+		return err
+	}
+	if err := p.stor.Flush(); err != nil {
+		return err
+	}
+	p.cursor = cursor
+	return nil
+}
+
+// MigrationReport describes the changes a migration step made, or would make in dry-run mode.
+type MigrationReport struct {
+	ID   uint64
+	Name string
+	Puts int
+	Dels int
+}
+
+// Migrator applies the registered migration steps sequentially.
+type Migrator struct {
+	stor  Storage
+	steps []*MigrationStep
+}
+
+// NewMigrator create a new migrator on storage.
+func NewMigrator(stor Storage) *Migrator {
+	return &Migrator{stor: stor}
+}
+
+// Register register a migration step.
+func (m *Migrator) Register(step *MigrationStep) error {
+	if step == nil || step.ID == 0 {
+		return ErrInvalidMigrationID
+	}
+	if step.Up == nil {
+		return ErrNilMigrationUp
+	}
+	for _, s := range m.steps {
+		if s.ID == step.ID {
+			return ErrDuplicatedMigrationID
+		}
+	}
+	m.steps = append(m.steps, step)
+	sort.Slice(m.steps, func(i, j int) bool {
+		return m.steps[i].ID < m.steps[j].ID
+	})
+	return nil
+}
+
+// LatestVersion return the schema version after all registered steps are applied.
+func (m *Migrator) LatestVersion() uint64 {
+	if len(m.steps) == 0 {
+		return 0
+	}
+	return m.steps[len(m.steps)-1].ID
+}
+
+// SchemaVersion return the persisted schema version, 0 if never migrated.
+func (m *Migrator) SchemaVersion() (uint64, error) {
+	return schemaVersion(m.stor)
+}
+
+// Run applies the pending migration steps to the storage.
+func (m *Migrator) Run() ([]*MigrationReport, error) {
+	return m.run(m.stor)
+}
+
+// DryRun reports what the pending migration steps would change, the storage is left untouched.
+func (m *Migrator) DryRun() ([]*MigrationReport, error) {
+	return m.run(newOverlayStorage(m.stor))
+}
+
+func (m *Migrator) run(stor Storage) ([]*MigrationReport, error) {
+	version, err := schemaVersion(stor)
+	if err != nil {
+		return nil, err
+	}
+	if version > m.LatestVersion() {
+		return nil, ErrSchemaVersionTooNew
+	}
+
+	reports := []*MigrationReport{}
+	for _, step := range m.steps {
+		if step.ID <= version {
+			continue
+		}
+		report, err := runStep(stor, step)
+		if err != nil {
+			return reports, err
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+func runStep(stor Storage, step *MigrationStep) (*MigrationReport, error) {
+	progress := &MigrationProgress{
+		stor: stor,
+		key:  append([]byte(migrationProgressPrefix), byteutils.FromUint64(step.ID)...),
+	}
+	cursor, err := stor.Get(progress.key)
+	if err != nil && err != ErrKeyNotFound {
+		return nil, err
+	}
+	if err == nil {
+		progress.cursor = cursor
+	}
+
+	counter := &countingStorage{Storage: stor}
+	report := &MigrationReport{ID: step.ID, Name: step.Name}
+
+	// the writes of Up are pending until progress.Commit, the uncommitted ones are dropped on failure.
+	stor.EnableBatch()
+	defer stor.DisableBatch()
+
+	if err := step.Up(counter, progress); err != nil {
+		return nil, err
+	}
+
+	// finish the step atomically: bump the schema version and drop the progress record.
+	if err := stor.Put(SchemaVersionKey, byteutils.FromUint64(step.ID)); err != nil {
+		return nil, err
+	}
+	if err := stor.Del(progress.key); err != nil {
+		return nil, err
+	}
+	if err := stor.Flush(); err != nil {
+		return nil, err
+	}
+
+	report.Puts, report.Dels = counter.puts, counter.dels
+	return report, nil
+}
+
+func schemaVersion(stor Storage) (uint64, error) {
+	value, err := stor.Get(SchemaVersionKey)
+	if err == ErrKeyNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return byteutils.Uint64(value), nil
+}
+
+// countingStorage counts the writes a migration step makes.
+type countingStorage struct {
+	Storage
+	puts int
+	dels int
+}
+
+func (s *countingStorage) Put(key []byte, value []byte) error {
+	if err := s.Storage.Put(key, value); err != nil {
+		return err
+	}
+	s.puts++
+	return nil
+}
+
+func (s *countingStorage) Del(key []byte) error {
+	if err := s.Storage.Del(key); err != nil {
+		return err
+	}
+	s.dels++
+	return nil
+}
+
+func (s *countingStorage) Iterate(start []byte, fn func(key []byte, value []byte) error) error {
+	return Iterate(s.Storage, start, fn)
+}
+
+// overlayStorage keeps writes in memory on top of a read-only storage, used by dry-run.
+type overlayStorage struct {
+	base    Storage
+	entries map[string]*batchOpt
+}
+
+func newOverlayStorage(base Storage) *overlayStorage {
+	return &overlayStorage{
+		base:    base,
+		entries: make(map[string]*batchOpt),
+	}
+}
+
+func (s *overlayStorage) Get(key []byte) ([]byte, error) {
+	if entry, ok := s.entries[byteutils.Hex(key)]; ok {
+		if entry.deleted {
+			return nil, ErrKeyNotFound
+		}
+		return entry.value, nil
+	}
+	return s.base.Get(key)
+}
+
+func (s *overlayStorage) Put(key []byte, value []byte) error {
+	s.entries[byteutils.Hex(key)] = &batchOpt{key: key, value: value}
+	return nil
+}
+
+func (s *overlayStorage) Del(key []byte) error {
+	s.entries[byteutils.Hex(key)] = &batchOpt{key: key, deleted: true}
+	return nil
+}
+
+// Iterate merges the written entries into the walk of base storage in key order.
+func (s *overlayStorage) Iterate(start []byte, fn func(key []byte, value []byte) error) error {
+	entries := []*batchOpt{}
+	for _, entry := range s.entries {
+		if bytes.Compare(entry.key, start) >= 0 {
+			entries = append(entries, entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].key, entries[j].key) < 0
+	})
+
+	// next calls fn on the written entries up to key, all of them if key is nil.
+	next := func(key []byte) error {
+		for len(entries) > 0 && (key == nil || bytes.Compare(entries[0].key, key) <= 0) {
+			entry := entries[0]
+			entries = entries[1:]
+			if entry.deleted {
+				continue
+			}
+			if err := fn(entry.key, entry.value); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := Iterate(s.base, start, func(key []byte, value []byte) error {
+		if err := next(key); err != nil {
+			return err
+		}
+		if _, ok := s.entries[byteutils.Hex(key)]; ok {
+			// overwritten or deleted.
+			return nil
+		}
+		return fn(key, value)
+	}); err != nil {
+		return err
+	}
+	return next(nil)
+}
+
+func (s *overlayStorage) EnableBatch() {}
+
+func (s *overlayStorage) DisableBatch() {}
+
+func (s *overlayStorage) Flush() error {
+	return nil
+}
@@ -23,6 +23,7 @@ import "errors"
 // const
 var (
 	ErrKeyNotFound = errors.New("not found")
+	ErrNotIterable = errors.New("storage is not iterable")
 )
 
 // Storage interface of Storage.
@@ -45,3 +46,19 @@ type Storage interface {
 	// Flush write and flush pending batch write.
 	Flush() error
 }
+
+// Iterable is implemented by the storages whose entries can be walked.
+type Iterable interface {
+	// Iterate calls fn on the key-value entries in Storage from the start key in key order,
+	// it stops at the first error of fn. The entries written by fn may not be walked.
+	Iterate(start []byte, fn func(key []byte, value []byte) error) error
+}
+
+// Iterate walks the entries of stor from start, ErrNotIterable if stor is not Iterable.
+func Iterate(stor Storage, start []byte, fn func(key []byte, value []byte) error) error {
+	iterable, ok := stor.(Iterable)
+	if !ok {
+		return ErrNotIterable
+	}
+	return iterable.Iterate(start, fn)
+}
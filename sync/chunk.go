@@ -20,6 +20,7 @@ package sync
 
 import (
 	"bytes"
+	"context"
 
 	"github.com/alexlisong/go-nebulas/common/trie"
 	"github.com/alexlisong/go-nebulas/core"
@@ -80,8 +81,24 @@ func (c *Chunk) generateChunkHeaders(syncpointHash byteutils.Hash) (*syncpb.Chun
 
 	startChunk := (syncpoint.Height() - 1) / core.ChunkSize
 	endChunk := (tail.Height() - 1) / core.ChunkSize
-	curChunk := startChunk
-	for curChunk < endChunk && curChunk-startChunk < MaxChunkPerSyncRequest {
+	chunks := endChunk - startChunk
+	if chunks > MaxChunkPerSyncRequest {
+		chunks = MaxChunkPerSyncRequest
+	}
+
+	// stream the blocks of chunks following the last block of previous chunk.
+	base := c.blockChain.GetBlockOnCanonicalChainByHeight(startChunk*core.ChunkSize + 1)
+	if base == nil {
+		logging.VLog().WithFields(logrus.Fields{
+			"height": startChunk*core.ChunkSize + 1,
+		}).Debug("Failed to find the block on canonical chain.")
+		return nil, ErrCannotFindBlockByHeight
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	blockCh, errCh := c.blockChain.FetchDescendantInCanonicalChainCh(ctx, int(chunks*core.ChunkSize), base)
+
+	for curChunk := startChunk; curChunk < startChunk+chunks; curChunk++ {
 		headers := [][]byte{}
 		blocksTrie, err := trie.NewTrie(nil, stor, false)
 		if err != nil {
@@ -91,25 +108,20 @@ func (c *Chunk) generateChunkHeaders(syncpointHash byteutils.Hash) (*syncpb.Chun
 			return nil, err
 		}
 
-		startHeight := curChunk*core.ChunkSize + 2
-		endHeight := (curChunk+1)*core.ChunkSize + 2
-		curHeight := startHeight
-		for curHeight < endHeight {
-			block := c.blockChain.GetBlockOnCanonicalChainByHeight(curHeight)
-			if block == nil {
+		for len(headers) < core.ChunkSize {
+			block, ok := <-blockCh
+			if !ok {
 				logging.VLog().WithFields(logrus.Fields{
-					"height": curHeight + 1,
+					"height": curChunk*core.ChunkSize + uint64(len(headers)) + 2,
+					"err":    <-errCh,
 				}).Debug("Failed to find the block on canonical chain.")
 				return nil, ErrCannotFindBlockByHeight
 			}
 			headers = append(headers, block.Hash())
 			blocksTrie.Put(block.Hash(), block.Hash())
-			curHeight++
 		}
 		chunkHeaders = append(chunkHeaders, &syncpb.ChunkHeader{Headers: headers, Root: blocksTrie.RootHash()})
 		chunksTrie.Put(blocksTrie.RootHash(), blocksTrie.RootHash())
-
-		curChunk++
 	}
 
 	logging.VLog().WithFields(logrus.Fields{
@@ -293,7 +305,7 @@ func verifyChunkData(chunkHeader *syncpb.ChunkHeader, chunkData *syncpb.ChunkDat
 func (c *Chunk) processChunkData(chunk *syncpb.ChunkData) error {
 	for k, v := range chunk.Blocks {
 		block := new(core.Block)
-		if err := block.FromProto(v); err != nil {
+		if err := block.FromProtoWithChainID(v, c.blockChain.ChainID()); err != nil {
 			logging.VLog().WithFields(logrus.Fields{
 				"index": k,
 				"hash":  byteutils.Hex(v.Header.Hash),
@@ -241,6 +241,12 @@ func (n mockNetService) ClosePeer(peerID string, reason error) {}
 
 func (n mockNetService) BroadcastNetworkID([]byte) {}
 
+func (n mockNetService) PeerCapabilities(peerID string) (net.Capability, bool) {
+	return net.LocalCapabilities, true
+}
+
+func (n mockNetService) ReportPeer(peerID string, verdict net.Verdict) {}
+
 func TestChunk_generateChunkMeta(t *testing.T) {
 	neb := mockNeb(t)
 	chain := neb.chain
@@ -12,6 +12,10 @@ It has these top-level messages:
 	ChunkHeader
 	ChunkHeaders
 	ChunkData
+	SnapshotNodeHashes
+	SnapshotNodes
+	HeadersRequest
+	Headers
 */
 package syncpb
 
@@ -119,30 +123,112 @@ func (m *ChunkData) GetRoot() []byte {
 	return nil
 }
 
+type SnapshotNodeHashes struct {
+	Hashes [][]byte `protobuf:"bytes,1,rep,name=hashes" json:"hashes,omitempty"`
+}
+
+func (m *SnapshotNodeHashes) Reset()                    { *m = SnapshotNodeHashes{} }
+func (m *SnapshotNodeHashes) String() string            { return proto.CompactTextString(m) }
+func (*SnapshotNodeHashes) ProtoMessage()               {}
+func (*SnapshotNodeHashes) Descriptor() ([]byte, []int) { return fileDescriptorSync, []int{4} }
+
+func (m *SnapshotNodeHashes) GetHashes() [][]byte {
+	if m != nil {
+		return m.Hashes
+	}
+	return nil
+}
+
+type SnapshotNodes struct {
+	Nodes [][]byte `protobuf:"bytes,1,rep,name=nodes" json:"nodes,omitempty"`
+}
+
+func (m *SnapshotNodes) Reset()                    { *m = SnapshotNodes{} }
+func (m *SnapshotNodes) String() string            { return proto.CompactTextString(m) }
+func (*SnapshotNodes) ProtoMessage()               {}
+func (*SnapshotNodes) Descriptor() ([]byte, []int) { return fileDescriptorSync, []int{5} }
+
+func (m *SnapshotNodes) GetNodes() [][]byte {
+	if m != nil {
+		return m.Nodes
+	}
+	return nil
+}
+
+type HeadersRequest struct {
+	From  uint64 `protobuf:"varint,1,opt,name=from,proto3" json:"from,omitempty"`
+	Count uint64 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+}
+
+func (m *HeadersRequest) Reset()                    { *m = HeadersRequest{} }
+func (m *HeadersRequest) String() string            { return proto.CompactTextString(m) }
+func (*HeadersRequest) ProtoMessage()               {}
+func (*HeadersRequest) Descriptor() ([]byte, []int) { return fileDescriptorSync, []int{6} }
+
+func (m *HeadersRequest) GetFrom() uint64 {
+	if m != nil {
+		return m.From
+	}
+	return 0
+}
+
+func (m *HeadersRequest) GetCount() uint64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+type Headers struct {
+	Headers []*corepb.LightBlock `protobuf:"bytes,1,rep,name=headers" json:"headers,omitempty"`
+}
+
+func (m *Headers) Reset()                    { *m = Headers{} }
+func (m *Headers) String() string            { return proto.CompactTextString(m) }
+func (*Headers) ProtoMessage()               {}
+func (*Headers) Descriptor() ([]byte, []int) { return fileDescriptorSync, []int{7} }
+
+func (m *Headers) GetHeaders() []*corepb.LightBlock {
+	if m != nil {
+		return m.Headers
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Sync)(nil), "syncpb.Sync")
 	proto.RegisterType((*ChunkHeader)(nil), "syncpb.ChunkHeader")
 	proto.RegisterType((*ChunkHeaders)(nil), "syncpb.ChunkHeaders")
 	proto.RegisterType((*ChunkData)(nil), "syncpb.ChunkData")
+	proto.RegisterType((*SnapshotNodeHashes)(nil), "syncpb.SnapshotNodeHashes")
+	proto.RegisterType((*SnapshotNodes)(nil), "syncpb.SnapshotNodes")
+	proto.RegisterType((*HeadersRequest)(nil), "syncpb.HeadersRequest")
+	proto.RegisterType((*Headers)(nil), "syncpb.Headers")
 }
 
 func init() { proto.RegisterFile("sync.proto", fileDescriptorSync) }
 
 var fileDescriptorSync = []byte{
-	// 236 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x90, 0x41, 0x4b, 0x03, 0x31,
-	0x10, 0x85, 0x59, 0x2d, 0x2b, 0x4e, 0xb7, 0x08, 0xf1, 0xb2, 0x78, 0x2a, 0x0b, 0x4a, 0x2f, 0x26,
-	0x60, 0x0f, 0x1e, 0xbc, 0xa9, 0x48, 0xcf, 0xeb, 0xd1, 0x43, 0x49, 0x62, 0x68, 0x96, 0xae, 0x99,
-	0x90, 0x64, 0x0f, 0xfd, 0xf7, 0x92, 0x69, 0x0b, 0x5b, 0xd8, 0xdb, 0x7b, 0x33, 0xf3, 0x3e, 0x78,
-	0x03, 0x10, 0x0f, 0x4e, 0x73, 0x1f, 0x30, 0x21, 0x2b, 0xb3, 0xf6, 0xea, 0x61, 0xbd, 0xeb, 0x92,
-	0x1d, 0x14, 0xd7, 0xf8, 0x27, 0x9c, 0x51, 0x43, 0x2f, 0x63, 0x87, 0x62, 0x87, 0xcf, 0x27, 0x23,
-	0x34, 0x06, 0x23, 0xbc, 0x12, 0xaa, 0x47, 0xbd, 0x3f, 0x86, 0x1b, 0x0e, 0xb3, 0xef, 0x83, 0xd3,
-	0xec, 0x09, 0xee, 0x92, 0xec, 0xfa, 0x2d, 0xed, 0xb6, 0x56, 0x46, 0x5b, 0x17, 0xcb, 0x62, 0x55,
-	0xb5, 0x8b, 0x3c, 0x7e, 0xcf, 0xd3, 0x8d, 0x8c, 0xb6, 0x79, 0x83, 0xf9, 0x87, 0x1d, 0xdc, 0x7e,
-	0x63, 0xe4, 0xaf, 0x09, 0xac, 0x86, 0x1b, 0x4b, 0x2a, 0xd6, 0xc5, 0xf2, 0x7a, 0x55, 0xb5, 0x67,
-	0xcb, 0x18, 0xcc, 0x02, 0x62, 0xaa, 0xaf, 0x88, 0x42, 0xba, 0xf9, 0x81, 0x6a, 0x14, 0x8e, 0xec,
-	0x15, 0x2a, 0x3d, 0xf2, 0x84, 0x98, 0xbf, 0xdc, 0xf3, 0x63, 0x21, 0x3e, 0xba, 0x6d, 0x2f, 0x0e,
-	0x27, 0xe1, 0x5f, 0x70, 0x4b, 0x81, 0x4f, 0x99, 0x24, 0x7b, 0x84, 0x92, 0x9a, 0x9c, 0x99, 0x0b,
-	0x9e, 0xcb, 0x7b, 0xc5, 0xa9, 0x49, 0x7b, 0x5a, 0x4e, 0x71, 0x54, 0x49, 0x8f, 0x59, 0xff, 0x07,
-	0x00, 0x00, 0xff, 0xff, 0x73, 0x84, 0xc9, 0xac, 0x63, 0x01, 0x00, 0x00,
+	// 324 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x91, 0xc1, 0x4b, 0xf3, 0x30,
+	0x18, 0xc6, 0xd9, 0xf7, 0xd5, 0x0e, 0xdf, 0x75, 0x0a, 0x51, 0xa4, 0x78, 0x1a, 0x85, 0xc9, 0x0e,
+	0xb3, 0x85, 0xed, 0x30, 0xd0, 0x9b, 0x8a, 0xec, 0x20, 0x1e, 0xb2, 0xa3, 0x87, 0x91, 0x64, 0x71,
+	0x29, 0xdb, 0xf2, 0xd6, 0x26, 0x3d, 0xec, 0xbf, 0x97, 0xa4, 0xad, 0x74, 0xb0, 0xdb, 0xf3, 0x24,
+	0xcf, 0xfb, 0x34, 0xbf, 0xb7, 0x00, 0xe6, 0xa8, 0x45, 0x5a, 0x94, 0x68, 0x91, 0x84, 0x4e, 0x17,
+	0xfc, 0x7e, 0xbe, 0xcd, 0xad, 0xaa, 0x78, 0x2a, 0xf0, 0x90, 0x69, 0xc9, 0xab, 0x3d, 0x33, 0x39,
+	0x66, 0x5b, 0x7c, 0x6c, 0x4c, 0x26, 0xb0, 0x94, 0x59, 0xc1, 0x33, 0xbe, 0x47, 0xb1, 0xab, 0x87,
+	0x93, 0x14, 0x82, 0xd5, 0x51, 0x0b, 0xf2, 0x00, 0xd7, 0x96, 0xe5, 0xfb, 0xb5, 0xbf, 0x5b, 0x2b,
+	0x66, 0x54, 0xdc, 0x1b, 0xf5, 0x26, 0x11, 0x1d, 0xba, 0xe3, 0x17, 0x77, 0xba, 0x64, 0x46, 0x25,
+	0xcf, 0x30, 0x78, 0x55, 0x95, 0xde, 0x2d, 0x25, 0xdb, 0xc8, 0x92, 0xc4, 0xd0, 0x57, 0x5e, 0x99,
+	0xb8, 0x37, 0xfa, 0x3f, 0x89, 0x68, 0x6b, 0x09, 0x81, 0xa0, 0x44, 0xb4, 0xf1, 0x3f, 0xdf, 0xe2,
+	0x75, 0xf2, 0x05, 0x51, 0x67, 0xd8, 0x90, 0x05, 0x44, 0xa2, 0xe3, 0x7d, 0xc5, 0x60, 0x76, 0x93,
+	0xd6, 0x40, 0x69, 0x27, 0x4b, 0x4f, 0x82, 0x67, 0xcb, 0xdf, 0xe1, 0xd2, 0x0f, 0xbc, 0x31, 0xcb,
+	0xc8, 0x18, 0x42, 0x4f, 0xd2, 0x76, 0x0e, 0x53, 0x07, 0x5f, 0xf0, 0xd4, 0x93, 0xd0, 0xe6, 0xf2,
+	0x6c, 0xcf, 0x14, 0xc8, 0x4a, 0xb3, 0xc2, 0x28, 0xb4, 0x9f, 0xb8, 0x91, 0x8e, 0x5a, 0x1a, 0x72,
+	0x07, 0xa1, 0xf2, 0xaa, 0xe1, 0x6c, 0x5c, 0x32, 0x86, 0x61, 0x37, 0x6d, 0xc8, 0x2d, 0x5c, 0x68,
+	0xdc, 0xfc, 0xe5, 0x6a, 0x93, 0x3c, 0xc1, 0x55, 0xf3, 0x76, 0x2a, 0x7f, 0x2a, 0x69, 0xac, 0xfb,
+	0xf4, 0x77, 0x89, 0x07, 0xbf, 0xe5, 0x80, 0x7a, 0xed, 0x66, 0x05, 0x56, 0xba, 0x7e, 0x4f, 0x40,
+	0x6b, 0x93, 0x2c, 0xa0, 0xdf, 0x72, 0x4f, 0x4f, 0xd7, 0x3d, 0x98, 0x91, 0x96, 0xeb, 0x23, 0xdf,
+	0x2a, 0x5b, 0xc3, 0xb5, 0x11, 0x1e, 0xfa, 0x5f, 0x3c, 0xff, 0x1d, 0x00, 0xc0, 0x20, 0x91, 0xa6,
+	0x2d, 0x02, 0x00, 0x00,
 }
@@ -0,0 +1,520 @@
+// Copyright (C) 2018 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package sync
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/alexlisong/go-nebulas/common/trie"
+	"github.com/alexlisong/go-nebulas/core"
+	"github.com/alexlisong/go-nebulas/core/pb"
+	"github.com/alexlisong/go-nebulas/crypto/hash"
+	"github.com/alexlisong/go-nebulas/net"
+	"github.com/alexlisong/go-nebulas/sync/pb"
+	"github.com/alexlisong/go-nebulas/util/byteutils"
+	"github.com/alexlisong/go-nebulas/util/logging"
+	"github.com/gogo/protobuf/proto"
+	"github.com/sirupsen/logrus"
+)
+
+// Errors
+var (
+	ErrInvalidSnapshotMessageData      = errors.New("invalid Snapshot message data")
+	ErrInvalidSnapshotSourcePeer       = errors.New("invalid snapshot source peer")
+	ErrInvalidSnapshotNodesMessageData = errors.New("invalid SnapshotNodes message data")
+)
+
+// snapshotNode is a trie node to download, the leaves of account trie refer to the variables tries.
+type snapshotNode struct {
+	hash    byteutils.Hash
+	account bool
+}
+
+type snapshotNodesRequest struct {
+	nodes  map[byteutils.HexHash]*snapshotNode
+	sentAt int64
+}
+
+// snapshotTask downloads the states at the LIB agreed by most peers, node by node
+// from the roots in block header, and applies the LIB as local tail.
+type snapshotTask struct {
+	blockChain *core.BlockChain
+	netService net.Service
+	mu         sync.Mutex
+
+	snapshotPeers         []string
+	snapshotCounter       map[byteutils.HexHash]int
+	snapshotCounterPeers  map[byteutils.HexHash][]string
+	receivedSnapshotPeers map[string]bool
+	snapshot              *core.Block
+	snapshotDoneCh        chan bool
+
+	peers        []string
+	pending      []*snapshotNode
+	queued       map[byteutils.HexHash]bool
+	inflight     map[string]*snapshotNodesRequest
+	fetchedCount int
+	nodesDoneCh  chan bool
+	nodesFailCh  chan bool
+}
+
+func newSnapshotTask(blockChain *core.BlockChain, netService net.Service) *snapshotTask {
+	sn := &snapshotTask{
+		blockChain:     blockChain,
+		netService:     netService,
+		snapshotDoneCh: make(chan bool, 1),
+		nodesDoneCh:    make(chan bool, 1),
+		nodesFailCh:    make(chan bool, 1),
+	}
+	sn.reset()
+	return sn
+}
+
+func notify(ch chan bool) {
+	select {
+	case ch <- true:
+	default:
+	}
+}
+
+func (sn *snapshotTask) reset() {
+	sn.mu.Lock()
+	defer sn.mu.Unlock()
+
+	sn.snapshotPeers = nil
+	sn.snapshotCounter = make(map[byteutils.HexHash]int)
+	sn.snapshotCounterPeers = make(map[byteutils.HexHash][]string)
+	sn.receivedSnapshotPeers = make(map[string]bool)
+	sn.snapshot = nil
+	sn.peers = nil
+	sn.pending = nil
+	sn.queued = make(map[byteutils.HexHash]bool)
+	sn.inflight = make(map[string]*snapshotNodesRequest)
+	sn.fetchedCount = 0
+
+	for _, ch := range []chan bool{sn.snapshotDoneCh, sn.nodesDoneCh, sn.nodesFailCh} {
+		select {
+		case <-ch:
+		default:
+		}
+	}
+}
+
+// run downloads and applies the snapshot, return false if the task is stopped.
+func (sn *snapshotTask) run(quitCh chan bool) bool {
+	for {
+		sn.snapshotRequest()
+
+		snapshotTicker := time.NewTicker(10 * time.Second)
+
+	SNAPSHOT_STEP_1:
+		for {
+			select {
+			case <-quitCh:
+				snapshotTicker.Stop()
+				logging.VLog().Info("Stopped snapshot sync.")
+				return false
+			case <-snapshotTicker.C:
+				sn.reset()
+				sn.snapshotRequest()
+			case <-sn.snapshotDoneCh:
+				snapshotTicker.Stop()
+				break SNAPSHOT_STEP_1
+			}
+		}
+
+		tail := sn.blockChain.TailBlock()
+		if sn.snapshot.Height() <= tail.Height() {
+			logging.CLog().WithFields(logrus.Fields{
+				"snapshot": sn.snapshot,
+				"tail":     tail,
+			}).Info("Snapshot is not ahead of tail, sync blocks only.")
+			return true
+		}
+
+		logging.CLog().WithFields(logrus.Fields{
+			"snapshot": sn.snapshot,
+			"peers":    sn.peers,
+		}).Info("Starting to download states of snapshot.")
+
+		sn.startNodesRequest()
+
+		timeoutTicker := time.NewTicker(GetSnapshotNodesTimeout * time.Second)
+
+	SNAPSHOT_STEP_2:
+		for {
+			select {
+			case <-quitCh:
+				timeoutTicker.Stop()
+				logging.VLog().Info("Stopped snapshot sync.")
+				return false
+			case <-timeoutTicker.C:
+				sn.checkNodesTimeout()
+			case <-sn.nodesFailCh:
+				timeoutTicker.Stop()
+				logging.VLog().WithFields(logrus.Fields{
+					"snapshot": sn.snapshot,
+					"fetched":  sn.fetchedCount,
+				}).Info("No peer serves the snapshot, retry.")
+				break SNAPSHOT_STEP_2
+			case <-sn.nodesDoneCh:
+				timeoutTicker.Stop()
+				if err := sn.blockChain.ApplySnapshot(sn.snapshot); err != nil {
+					logging.VLog().WithFields(logrus.Fields{
+						"snapshot": sn.snapshot,
+						"err":      err,
+					}).Info("Failed to apply snapshot, retry.")
+					break SNAPSHOT_STEP_2
+				}
+				logging.CLog().WithFields(logrus.Fields{
+					"snapshot": sn.snapshot,
+					"fetched":  sn.fetchedCount,
+				}).Info("Snapshot Sync Finished. Move to ChainSync.")
+				return true
+			}
+		}
+		sn.reset()
+	}
+}
+
+func (sn *snapshotTask) snapshotRequest() {
+	chunkSync := &syncpb.Sync{
+		TailBlockHash: sn.blockChain.TailBlock().Hash(),
+	}
+	data, err := proto.Marshal(chunkSync)
+	if err != nil {
+		logging.VLog().WithFields(logrus.Fields{
+			"err": err,
+		}).Debug("Failed to serialize snapshot message")
+		return
+	}
+
+	// hold the lock to record the peers before their responses are processed.
+	sn.mu.Lock()
+	defer sn.mu.Unlock()
+
+	sn.snapshotPeers = sn.netService.SendMessageToPeers(net.SnapshotRequest, data,
+		net.MessagePriorityLow, new(net.ChainSyncPeersFilter))
+}
+
+func (sn *snapshotTask) processSnapshot(message net.Message) {
+	sn.mu.Lock()
+	defer sn.mu.Unlock()
+
+	if sn.snapshot != nil {
+		return
+	}
+
+	isValidSourcePeer := false
+	for _, pid := range sn.snapshotPeers {
+		if pid == message.MessageFrom() {
+			isValidSourcePeer = true
+			break
+		}
+	}
+	if !isValidSourcePeer {
+		logging.VLog().WithFields(logrus.Fields{
+			"err": ErrInvalidSnapshotSourcePeer,
+			"pid": message.MessageFrom(),
+		}).Debug("Invalid Snapshot message source peer.")
+		return
+	}
+	if sn.receivedSnapshotPeers[message.MessageFrom()] {
+		return
+	}
+
+	pbBlock := new(corepb.Block)
+	block := new(core.Block)
+	if err := proto.Unmarshal(message.Data(), pbBlock); err != nil {
+		logging.VLog().WithFields(logrus.Fields{
+			"err": err,
+			"pid": message.MessageFrom(),
+		}).Debug("Invalid Snapshot message data.")
+		sn.netService.ClosePeer(message.MessageFrom(), ErrInvalidSnapshotMessageData)
+		return
+	}
+	if err := block.FromProto(pbBlock); err != nil {
+		logging.VLog().WithFields(logrus.Fields{
+			"err": err,
+			"pid": message.MessageFrom(),
+		}).Debug("Invalid Snapshot message data.")
+		sn.netService.ClosePeer(message.MessageFrom(), ErrInvalidSnapshotMessageData)
+		return
+	}
+
+	// the snapshot is trusted when most peers agree on it.
+	key := block.Hash().Hex()
+	sn.receivedSnapshotPeers[message.MessageFrom()] = true
+	sn.snapshotCounter[key]++
+	sn.snapshotCounterPeers[key] = append(sn.snapshotCounterPeers[key], message.MessageFrom())
+
+	logging.VLog().WithFields(logrus.Fields{
+		"block": block,
+		"count": sn.snapshotCounter[key],
+		"pid":   message.MessageFrom(),
+	}).Debug("Processed Snapshot message data.")
+
+	if sn.snapshotCounter[key] >= len(sn.snapshotPeers)/2+1 {
+		sn.snapshot = block
+		sn.peers = sn.snapshotCounterPeers[key]
+		notify(sn.snapshotDoneCh)
+	}
+}
+
+func (sn *snapshotTask) startNodesRequest() {
+	sn.mu.Lock()
+	defer sn.mu.Unlock()
+
+	block := sn.snapshot
+	sn.enqueue(block.StateRoot(), true)
+	for _, root := range []byteutils.Hash{
+		block.TxsRoot(),
+		block.EventsRoot(),
+		block.ReceiptsRoot(),
+		block.DelegateRoot(),
+		block.ConsensusRoot().DynastyRoot,
+	} {
+		sn.enqueue(root, false)
+	}
+	sn.dispatch()
+}
+
+func (sn *snapshotTask) enqueue(h byteutils.Hash, account bool) {
+	if len(h) == 0 || sn.queued[h.Hex()] {
+		return
+	}
+	sn.queued[h.Hex()] = true
+	sn.pending = append(sn.pending, &snapshotNode{hash: h, account: account})
+}
+
+// expand enqueue the children of node, and the variables trie of account.
+func (sn *snapshotTask) expand(n *snapshotNode, bytes []byte) error {
+	children, val, err := trie.DecodeNode(bytes)
+	if err != nil {
+		return err
+	}
+	if n.account && val != nil {
+		pbAcc := new(corepb.Account)
+		if err := proto.Unmarshal(val, pbAcc); err != nil {
+			return err
+		}
+		sn.enqueue(pbAcc.VarsHash, false)
+	}
+	for _, child := range children {
+		sn.enqueue(child, n.account)
+	}
+	return nil
+}
+
+func (sn *snapshotTask) nextNodesRequest(limit int) *snapshotNodesRequest {
+	nodes := make(map[byteutils.HexHash]*snapshotNode)
+	for len(sn.pending) > 0 && len(nodes) < limit {
+		n := sn.pending[len(sn.pending)-1]
+		sn.pending = sn.pending[:len(sn.pending)-1]
+
+		// the nodes in storage are expanded locally, they may be left by an interrupted sync.
+		if bytes, err := sn.blockChain.Storage().Get(n.hash); err == nil {
+			if err := sn.expand(n, bytes); err == nil {
+				continue
+			}
+		}
+		nodes[n.hash.Hex()] = n
+	}
+	if len(nodes) == 0 {
+		return nil
+	}
+	return &snapshotNodesRequest{nodes: nodes}
+}
+
+func (sn *snapshotTask) dispatch() {
+	idle := []string{}
+	for _, pid := range sn.peers {
+		if _, ok := sn.inflight[pid]; !ok {
+			idle = append(idle, pid)
+		}
+	}
+
+	// spread the pending nodes over idle peers.
+	for i, pid := range idle {
+		limit := (len(sn.pending) + len(idle) - i - 1) / (len(idle) - i)
+		if limit > MaxSnapshotNodesPerRequest {
+			limit = MaxSnapshotNodesPerRequest
+		}
+		req := sn.nextNodesRequest(limit)
+		if req == nil {
+			continue
+		}
+		sn.nodesRequest(pid, req)
+	}
+
+	if len(sn.peers) == 0 {
+		notify(sn.nodesFailCh)
+		return
+	}
+	if len(sn.inflight) == 0 {
+		// the pending nodes may be new children of the nodes expanded locally.
+		if len(sn.pending) > 0 {
+			sn.dispatch()
+			return
+		}
+		notify(sn.nodesDoneCh)
+	}
+}
+
+func (sn *snapshotTask) nodesRequest(pid string, req *snapshotNodesRequest) {
+	hashes := &syncpb.SnapshotNodeHashes{}
+	for _, n := range req.nodes {
+		hashes.Hashes = append(hashes.Hashes, n.hash)
+	}
+	data, err := proto.Marshal(hashes)
+	if err != nil {
+		logging.VLog().WithFields(logrus.Fields{
+			"err": err,
+		}).Warn("Failed to marshal SnapshotNodeHashes.")
+		sn.requeue(req)
+		return
+	}
+
+	if err := sn.netService.SendMessageToPeer(net.SnapshotNodesRequest, data, net.MessagePriorityLow, pid); err != nil {
+		logging.VLog().WithFields(logrus.Fields{
+			"err": err,
+			"pid": pid,
+		}).Debug("Failed to send SnapshotNodeHashes, rotate to other peers.")
+		sn.requeue(req)
+		sn.dropPeer(pid)
+		return
+	}
+	req.sentAt = time.Now().Unix()
+	sn.inflight[pid] = req
+}
+
+func (sn *snapshotTask) requeue(req *snapshotNodesRequest) {
+	for _, n := range req.nodes {
+		sn.pending = append(sn.pending, n)
+	}
+}
+
+func (sn *snapshotTask) dropPeer(pid string) {
+	for i, v := range sn.peers {
+		if v == pid {
+			sn.peers = append(sn.peers[:i], sn.peers[i+1:]...)
+			return
+		}
+	}
+}
+
+func (sn *snapshotTask) rotatePeer(pid string) {
+	sn.dropPeer(pid)
+	sn.peers = append(sn.peers, pid)
+}
+
+func (sn *snapshotTask) checkNodesTimeout() {
+	sn.mu.Lock()
+	defer sn.mu.Unlock()
+
+	now := time.Now().Unix()
+	for pid, req := range sn.inflight {
+		if now-req.sentAt < GetSnapshotNodesTimeout {
+			continue
+		}
+		logging.VLog().WithFields(logrus.Fields{
+			"pid":   pid,
+			"nodes": len(req.nodes),
+		}).Debug("Get snapshot nodes timeout. Retry.")
+		delete(sn.inflight, pid)
+		sn.requeue(req)
+		sn.rotatePeer(pid)
+	}
+	sn.dispatch()
+}
+
+func (sn *snapshotTask) processNodes(message net.Message) {
+	sn.mu.Lock()
+	defer sn.mu.Unlock()
+
+	pid := message.MessageFrom()
+	req, ok := sn.inflight[pid]
+	if !ok {
+		logging.VLog().WithFields(logrus.Fields{
+			"pid": pid,
+		}).Debug("Unexpected SnapshotNodes message.")
+		return
+	}
+	delete(sn.inflight, pid)
+
+	nodes := new(syncpb.SnapshotNodes)
+	if err := proto.Unmarshal(message.Data(), nodes); err != nil {
+		logging.VLog().WithFields(logrus.Fields{
+			"err": err,
+			"pid": pid,
+		}).Debug("Invalid SnapshotNodes message data.")
+		sn.netService.ClosePeer(pid, ErrInvalidSnapshotNodesMessageData)
+		sn.requeue(req)
+		sn.dropPeer(pid)
+		sn.dispatch()
+		return
+	}
+
+	// verify each node against the hash referring to it, which is rooted at the block header.
+	wrong := false
+	for _, bytes := range nodes.Nodes {
+		h := byteutils.Hash(hash.Sha3256(bytes))
+		n, ok := req.nodes[h.Hex()]
+		if !ok {
+			wrong = true
+			continue
+		}
+		if err := sn.expand(n, bytes); err != nil {
+			wrong = true
+			continue
+		}
+		if err := sn.blockChain.Storage().Put(h, bytes); err != nil {
+			logging.VLog().WithFields(logrus.Fields{
+				"err":  err,
+				"hash": h,
+			}).Error("Failed to store snapshot node.")
+			continue
+		}
+		delete(req.nodes, h.Hex())
+		sn.fetchedCount++
+	}
+
+	// the missing nodes are requested again, rotated to other peers.
+	sn.requeue(req)
+	if wrong {
+		logging.VLog().WithFields(logrus.Fields{
+			"err": ErrWrongSnapshotNode,
+			"pid": pid,
+		}).Debug("Wrong SnapshotNodes message data, rotate to other peers.")
+		sn.netService.ClosePeer(pid, ErrWrongSnapshotNode)
+		sn.dropPeer(pid)
+	} else if len(req.nodes) > 0 {
+		sn.rotatePeer(pid)
+	}
+
+	logging.VLog().WithFields(logrus.Fields{
+		"pid":     pid,
+		"fetched": sn.fetchedCount,
+		"pending": len(sn.pending),
+	}).Debug("Processed SnapshotNodes message data.")
+
+	sn.dispatch()
+}
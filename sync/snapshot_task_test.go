@@ -0,0 +1,198 @@
+// Copyright (C) 2018 go-nebulas authors
+//
+// This file is part of the go-nebulas library.
+//
+// the go-nebulas library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// the go-nebulas library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the go-nebulas library.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package sync
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alexlisong/go-nebulas/consensus/dpos"
+	"github.com/alexlisong/go-nebulas/core"
+	"github.com/alexlisong/go-nebulas/net"
+	"github.com/alexlisong/go-nebulas/sync/pb"
+	"github.com/alexlisong/go-nebulas/util"
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+// linkedNetService delivers messages to the sync services of linked peers in memory.
+type linkedNetService struct {
+	mockNetService
+	mu      sync.Mutex
+	id      string
+	peers   map[string]*Service
+	closed  map[string]error
+	corrupt bool
+}
+
+func newLinkedNetService(id string) *linkedNetService {
+	return &linkedNetService{
+		id:     id,
+		peers:  make(map[string]*Service),
+		closed: make(map[string]error),
+	}
+}
+
+func (n *linkedNetService) link(id string, ss *Service) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.peers[id] = ss
+}
+
+func (n *linkedNetService) SendMessageToPeers(messageName string, data []byte, priority int, filter net.PeerFilterAlgorithm) []string {
+	n.mu.Lock()
+	ids := []string{}
+	for id := range n.peers {
+		ids = append(ids, id)
+	}
+	n.mu.Unlock()
+
+	peers := []string{}
+	for _, id := range ids {
+		if n.SendMessageToPeer(messageName, data, priority, id) == nil {
+			peers = append(peers, id)
+		}
+	}
+	return peers
+}
+
+func (n *linkedNetService) SendMessageToPeer(messageName string, data []byte, priority int, peerID string) error {
+	n.mu.Lock()
+	ss, ok := n.peers[peerID]
+	n.mu.Unlock()
+	if !ok {
+		return errors.New("peer is not linked")
+	}
+
+	// a corrupt peer flips a byte of every trie node it serves.
+	if n.corrupt && messageName == net.SnapshotNodesResponse {
+		nodes := new(syncpb.SnapshotNodes)
+		if err := proto.Unmarshal(data, nodes); err != nil {
+			return err
+		}
+		for _, node := range nodes.Nodes {
+			node[len(node)-1] ^= 0xff
+		}
+		data, _ = proto.Marshal(nodes)
+	}
+	// deliver with latency as real network, the sender records the request first.
+	message := net.NewBaseMessage(messageName, n.id, data)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		ss.messageCh <- message
+	}()
+	return nil
+}
+
+func (n *linkedNetService) ClosePeer(peerID string, reason error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.closed[peerID] = reason
+	delete(n.peers, peerID)
+}
+
+func TestService_SnapshotSync(t *testing.T) {
+	neb := mockNeb(t)
+	chain := neb.chain
+
+	from, _ := core.AddressParse("n1FF1nz6tarkDVwWQkMnnwFPuPKUaQTdptE")
+	to, _ := core.AddressParse("n1GmkKH6nBMw4rrjt16RrJ9WcgvKUtAZP1s")
+	assert.Nil(t, neb.am.Unlock(from, []byte("passphrase"), time.Second*60*60*24*365))
+
+	blocks := []*core.Block{}
+	for i := 0; i < 1000; i++ {
+		context, err := chain.TailBlock().WorldState().NextConsensusState(dpos.BlockIntervalInMs / dpos.SecondInMs)
+		assert.Nil(t, err)
+		coinbase, err := core.AddressParseFromBytes(context.Proposer())
+		assert.Nil(t, err)
+		assert.Nil(t, neb.am.Unlock(coinbase, []byte("passphrase"), time.Second*60*60*24*365))
+		block, err := chain.NewBlock(coinbase)
+		assert.Nil(t, err)
+		block.WorldState().SetConsensusState(context)
+		block.SetTimestamp(chain.TailBlock().Timestamp() + dpos.BlockIntervalInMs/dpos.SecondInMs)
+		if i%100 == 0 {
+			value, _ := util.NewUint128FromInt(1)
+			gasLimit, _ := util.NewUint128FromInt(200000)
+			tx, _ := core.NewTransaction(chain.ChainID(), from, to, value, uint64(i/100+1), core.TxPayloadBinaryType, nil, core.TransactionGasPrice, gasLimit)
+			assert.Nil(t, neb.am.SignTransaction(from, tx))
+			assert.Nil(t, chain.TransactionPool().Push(tx))
+			block.CollectTransactions(time.Now().Unix()*1000 + 1000)
+			assert.Equal(t, 1, len(block.Transactions()))
+		}
+		assert.Nil(t, block.Seal())
+		assert.Nil(t, neb.am.SignBlock(coinbase, block))
+		assert.Nil(t, chain.BlockPool().Push(block))
+		blocks = append(blocks, block)
+	}
+	tail := blocks[len(blocks)-1]
+	assert.Equal(t, tail.Hash(), chain.TailBlock().Hash())
+	lib := blocks[989]
+	assert.Nil(t, chain.SetLIB(lib))
+
+	// a honest peer and a corrupt one serve the same chain.
+	fresh := mockNeb(t)
+	freshNet := newLinkedNetService("fresh")
+	freshService := NewService(fresh.chain, freshNet)
+	assert.Equal(t, ErrInvalidSyncMode, freshService.SetSyncMode("fast"))
+	assert.Nil(t, freshService.SetSyncMode(SyncModeSnapshot))
+	fresh.chain.SetSyncService(freshService)
+	freshService.Start()
+	defer freshService.Stop()
+
+	for _, id := range []string{"honest", "corrupt"} {
+		peerNet := newLinkedNetService(id)
+		peerNet.corrupt = id == "corrupt"
+		peerNet.link("fresh", freshService)
+		peerService := NewService(chain, peerNet)
+		peerService.Start()
+		defer peerService.Stop()
+		freshNet.link(id, peerService)
+	}
+
+	assert.True(t, freshService.StartActiveSync())
+	freshService.WaitingForFinish()
+	assert.Equal(t, ErrWrongSnapshotNode, freshNet.closed["corrupt"])
+
+	// the states at LIB are downloaded without executing the blocks before it.
+	assert.Equal(t, lib.Hash(), fresh.chain.TailBlock().Hash())
+	assert.Equal(t, lib.Hash(), fresh.chain.LIB().Hash())
+	assert.Nil(t, fresh.chain.GetBlockOnCanonicalChainByHeight(500))
+	assert.Nil(t, fresh.chain.GetBlock(blocks[988].Hash()))
+	for _, addr := range []*core.Address{from, to} {
+		expect, err := lib.GetAccount(addr.Bytes())
+		assert.Nil(t, err)
+		actual, err := fresh.chain.TailBlock().GetAccount(addr.Bytes())
+		assert.Nil(t, err)
+		assert.Equal(t, 0, expect.Balance().Cmp(actual.Balance()))
+		assert.Equal(t, expect.Nonce(), actual.Nonce())
+	}
+
+	// then the blocks after LIB are synced block by block.
+	for _, block := range blocks[990:] {
+		pbBlock, err := block.ToProto()
+		assert.Nil(t, err)
+		copied := new(core.Block)
+		assert.Nil(t, copied.FromProto(pbBlock))
+		assert.Nil(t, fresh.chain.BlockPool().Push(copied))
+	}
+	assert.Equal(t, tail.Hash(), fresh.chain.TailBlock().Hash())
+	assert.Equal(t, tail.StateRoot(), fresh.chain.TailBlock().StateRoot())
+}
@@ -21,10 +21,13 @@ package sync
 import (
 	"errors"
 	"sync"
+
+	"github.com/alexlisong/go-nebulas/crypto/hash"
 	"github.com/alexlisong/go-nebulas/util/byteutils"
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/alexlisong/go-nebulas/core"
+	"github.com/alexlisong/go-nebulas/core/pb"
 	"github.com/alexlisong/go-nebulas/net"
 	"github.com/alexlisong/go-nebulas/sync/pb"
 	"github.com/alexlisong/go-nebulas/util/logging"
@@ -35,6 +38,8 @@ import (
 var (
 	ErrInvalidChainSyncMessageData     = errors.New("invalid ChainSync message data")
 	ErrInvalidChainGetChunkMessageData = errors.New("invalid ChainGetChunk message data")
+	ErrInvalidSnapshotNodesRequestData = errors.New("invalid SnapshotNodeHashes message data")
+	ErrInvalidHeadersRequestData       = errors.New("invalid HeadersRequest message data")
 )
 
 // Service manage sync tasks
@@ -44,6 +49,8 @@ type Service struct {
 	chunk      *Chunk
 	quitCh     chan bool
 	messageCh  chan net.Message
+	subscriber *net.Subscriber
+	syncMode   string
 
 	activeTask      *Task
 	activeTaskMutex sync.Mutex
@@ -51,14 +58,41 @@ type Service struct {
 
 // NewService return new Service.
 func NewService(blockChain *core.BlockChain, netService net.Service) *Service {
-	return &Service{
+	ss := &Service{
 		blockChain: blockChain,
 		netService: netService,
 		chunk:      NewChunk(blockChain),
 		quitCh:     make(chan bool, 1),
 		activeTask: nil,
 		messageCh:  make(chan net.Message, 128),
+		syncMode:   SyncModeFull,
 	}
+	ss.subscriber = net.NewSubscriber(ss, []string{
+		net.ChunkHeadersRequest, net.ChunkHeadersResponse, net.ChunkDataRequest, net.ChunkDataResponse,
+		net.SnapshotRequest, net.SnapshotResponse, net.SnapshotNodesRequest, net.SnapshotNodesResponse,
+		net.HeadersRequest,
+	},
+		net.WithMessageChan(ss.messageCh),
+		net.WithMessageWeight(net.ChunkHeadersResponse, net.MessageWeightChainChunks),
+		net.WithMessageWeight(net.ChunkDataResponse, net.MessageWeightChainChunkData),
+		net.WithMessageWeight(net.SnapshotResponse, net.MessageWeightChainChunks),
+		net.WithMessageWeight(net.SnapshotNodesResponse, net.MessageWeightChainChunkData),
+		net.WithName("SyncService"),
+	)
+	return ss
+}
+
+// SetSyncMode set the sync mode of a fresh node, empty for full.
+func (ss *Service) SetSyncMode(mode string) error {
+	switch mode {
+	case "":
+		mode = SyncModeFull
+	case SyncModeFull, SyncModeSnapshot:
+	default:
+		return ErrInvalidSyncMode
+	}
+	ss.syncMode = mode
+	return nil
 }
 
 // Start start sync service.
@@ -67,10 +101,7 @@ func (ss *Service) Start() {
 
 	// register the network handler.
 	netService := ss.netService
-	netService.Register(net.NewSubscriber(ss, ss.messageCh, false, net.ChunkHeadersRequest, net.MessageWeightZero))
-	netService.Register(net.NewSubscriber(ss, ss.messageCh, false, net.ChunkHeadersResponse, net.MessageWeightChainChunks))
-	netService.Register(net.NewSubscriber(ss, ss.messageCh, false, net.ChunkDataRequest, net.MessageWeightZero))
-	netService.Register(net.NewSubscriber(ss, ss.messageCh, false, net.ChunkDataResponse, net.MessageWeightChainChunkData))
+	netService.Register(ss.subscriber)
 
 	// start loop().
 	go ss.startLoop()
@@ -80,10 +111,7 @@ func (ss *Service) Start() {
 func (ss *Service) Stop() {
 	// deregister the network handler.
 	netService := ss.netService
-	netService.Deregister(net.NewSubscriber(ss, ss.messageCh, false, net.ChunkHeadersRequest, net.MessageWeightZero))
-	netService.Deregister(net.NewSubscriber(ss, ss.messageCh, false, net.ChunkHeadersResponse, net.MessageWeightChainChunks))
-	netService.Deregister(net.NewSubscriber(ss, ss.messageCh, false, net.ChunkDataRequest, net.MessageWeightZero))
-	netService.Deregister(net.NewSubscriber(ss, ss.messageCh, false, net.ChunkDataResponse, net.MessageWeightChainChunkData))
+	netService.Deregister(ss.subscriber)
 
 	ss.StopActiveSync()
 
@@ -101,6 +129,10 @@ func (ss *Service) StartActiveSync() bool {
 	}
 
 	ss.activeTask = NewTask(ss.blockChain, ss.netService, ss.chunk)
+	// a fresh node downloads the states at snapshot instead of replaying all blocks.
+	if ss.syncMode == SyncModeSnapshot && core.CheckGenesisBlock(ss.blockChain.TailBlock()) {
+		ss.activeTask.snapshot = newSnapshotTask(ss.blockChain, ss.netService)
+	}
 	ss.activeTask.Start()
 
 	logging.CLog().WithFields(logrus.Fields{
@@ -164,6 +196,16 @@ func (ss *Service) startLoop() {
 				ss.onChunkDataRequest(message)
 			case net.ChunkDataResponse:
 				ss.onChunkDataResponse(message)
+			case net.SnapshotRequest:
+				ss.onSnapshotRequest(message)
+			case net.SnapshotResponse:
+				ss.onSnapshotResponse(message)
+			case net.SnapshotNodesRequest:
+				ss.onSnapshotNodesRequest(message)
+			case net.SnapshotNodesResponse:
+				ss.onSnapshotNodesResponse(message)
+			case net.HeadersRequest:
+				ss.onHeadersRequest(message)
 			default:
 				logging.VLog().WithFields(logrus.Fields{
 					"messageName": message.MessageType(),
@@ -248,6 +290,131 @@ func (ss *Service) onChunkDataResponse(message net.Message) {
 	ss.activeTask.processChunkData(message)
 }
 
+func (ss *Service) onSnapshotRequest(message net.Message) {
+	if ss.IsActiveSyncing() {
+		return
+	}
+
+	chunkSync := new(syncpb.Sync)
+	if err := proto.Unmarshal(message.Data(), chunkSync); err != nil {
+		logging.VLog().WithFields(logrus.Fields{
+			"err": err,
+			"pid": message.MessageFrom(),
+		}).Debug("Invalid Snapshot request message data.")
+		ss.netService.ClosePeer(message.MessageFrom(), ErrInvalidChainSyncMessageData)
+		return
+	}
+
+	// the states of LIB are never pruned.
+	pbBlock, err := ss.blockChain.LIB().ToProto()
+	if err != nil {
+		logging.VLog().WithFields(logrus.Fields{
+			"err": err,
+		}).Debug("Failed to convert LIB to proto.")
+		return
+	}
+	data, err := proto.Marshal(pbBlock)
+	if err != nil {
+		logging.VLog().WithFields(logrus.Fields{
+			"err": err,
+		}).Debug("Failed to marshal LIB.")
+		return
+	}
+
+	ss.netService.SendMessageToPeer(net.SnapshotResponse, data, net.MessagePriorityLow, message.MessageFrom())
+}
+
+func (ss *Service) onSnapshotResponse(message net.Message) {
+	if ss.activeTask == nil || ss.activeTask.snapshot == nil {
+		return
+	}
+
+	ss.activeTask.snapshot.processSnapshot(message)
+}
+
+func (ss *Service) onSnapshotNodesRequest(message net.Message) {
+	if ss.IsActiveSyncing() {
+		return
+	}
+
+	hashes := new(syncpb.SnapshotNodeHashes)
+	if err := proto.Unmarshal(message.Data(), hashes); err != nil || len(hashes.Hashes) > MaxSnapshotNodesPerRequest {
+		logging.VLog().WithFields(logrus.Fields{
+			"err": err,
+			"pid": message.MessageFrom(),
+		}).Debug("Invalid SnapshotNodeHashes message data.")
+		ss.netService.ClosePeer(message.MessageFrom(), ErrInvalidSnapshotNodesRequestData)
+		return
+	}
+
+	// only the content addressed values are served, the missing ones are left out
+	// and the requester asks other peers for them.
+	nodes := &syncpb.SnapshotNodes{}
+	for _, h := range hashes.Hashes {
+		bytes, err := ss.blockChain.Storage().Get(h)
+		if err == nil && byteutils.Equal(hash.Sha3256(bytes), h) {
+			nodes.Nodes = append(nodes.Nodes, bytes)
+		}
+	}
+
+	data, err := proto.Marshal(nodes)
+	if err != nil {
+		logging.VLog().WithFields(logrus.Fields{
+			"err": err,
+		}).Debug("Failed to marshal syncpb.SnapshotNodes.")
+		return
+	}
+
+	ss.netService.SendMessageToPeer(net.SnapshotNodesResponse, data, net.MessagePriorityLow, message.MessageFrom())
+}
+
+func (ss *Service) onSnapshotNodesResponse(message net.Message) {
+	if ss.activeTask == nil || ss.activeTask.snapshot == nil {
+		return
+	}
+
+	ss.activeTask.snapshot.processNodes(message)
+}
+
+func (ss *Service) onHeadersRequest(message net.Message) {
+	request := new(syncpb.HeadersRequest)
+	if err := proto.Unmarshal(message.Data(), request); err != nil || request.Count > MaxHeadersPerRequest {
+		logging.VLog().WithFields(logrus.Fields{
+			"err": err,
+			"pid": message.MessageFrom(),
+		}).Debug("Invalid HeadersRequest message data.")
+		ss.netService.ClosePeer(message.MessageFrom(), ErrInvalidHeadersRequestData)
+		return
+	}
+
+	// the headers are served up to tail, the transactions are never decoded.
+	headers := &syncpb.Headers{}
+	for height := request.From; height < request.From+request.Count; height++ {
+		header, err := ss.blockChain.GetBlockHeaderOnCanonicalChainByHeight(height)
+		if err != nil {
+			break
+		}
+		pbHeader, err := header.ToProto()
+		if err != nil {
+			break
+		}
+		headers.Headers = append(headers.Headers, &corepb.LightBlock{
+			Header: pbHeader.(*corepb.BlockHeader),
+			Height: height,
+		})
+	}
+
+	data, err := proto.Marshal(headers)
+	if err != nil {
+		logging.VLog().WithFields(logrus.Fields{
+			"err": err,
+		}).Debug("Failed to marshal syncpb.Headers.")
+		return
+	}
+
+	ss.netService.SendMessageToPeer(net.HeadersResponse, data, net.MessagePriorityLow, message.MessageFrom())
+}
+
 func (ss *Service) chunkHeadersResponse(peerID string, chunks *syncpb.ChunkHeaders) {
 	data, err := proto.Marshal(chunks)
 	if err != nil {
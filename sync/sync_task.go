@@ -73,6 +73,9 @@ type Task struct {
 	chainChunkDataStatus          map[int]int64
 	chinGetChunkDataDoneCh        chan bool
 
+	// download the states at snapshot before syncing blocks, nil for full sync.
+	snapshot *snapshotTask
+
 	// debug fields.
 	chainSyncRetryCount int
 }
@@ -114,6 +117,13 @@ func (st *Task) Stop() {
 }
 
 func (st *Task) startSyncLoop() {
+	if st.snapshot != nil {
+		if !st.snapshot.run(st.quitCh) {
+			return
+		}
+		st.setSyncPointToNewTail()
+	}
+
 	for {
 		// start chain sync.
 		st.chunkHeadersRequest()
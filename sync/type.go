@@ -33,6 +33,8 @@ var (
 	ErrWrongChunkDataSize       = errors.New("wrong chunk data size")
 	ErrInvalidBlockHashInChunk  = errors.New("invalid block hash in chunk data")
 	ErrWrongBlockHashInChunk    = errors.New("wrong block hash in chunk data compared with chunk header")
+	ErrInvalidSyncMode          = errors.New("invalid sync mode")
+	ErrWrongSnapshotNode        = errors.New("wrong snapshot node compared with requested hashes")
 )
 
 // Contants
@@ -40,4 +42,15 @@ const (
 	MaxChunkPerSyncRequest       = 10
 	ConcurrentSyncChunkDataCount = 10
 	GetChunkDataTimeout          = 10 // 10s.
+
+	MaxSnapshotNodesPerRequest = 1024
+	GetSnapshotNodesTimeout    = 10 // 10s.
+
+	MaxHeadersPerRequest = 1024
+)
+
+// Sync modes
+const (
+	SyncModeFull     = "full"
+	SyncModeSnapshot = "snapshot"
 )